@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/exporter"
 	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
@@ -34,6 +35,29 @@ func main() {
 			}
 		}(utils.Config.Metrics.Address)
 	}
+	if utils.Config.BlobIndexer.SaveStats {
+		db.MustInitDB(&types.DatabaseConfig{
+			Username:     utils.Config.WriterDatabase.Username,
+			Password:     utils.Config.WriterDatabase.Password,
+			Name:         utils.Config.WriterDatabase.Name,
+			Host:         utils.Config.WriterDatabase.Host,
+			Port:         utils.Config.WriterDatabase.Port,
+			MaxOpenConns: utils.Config.WriterDatabase.MaxOpenConns,
+			MaxIdleConns: utils.Config.WriterDatabase.MaxIdleConns,
+			SSL:          utils.Config.WriterDatabase.SSL,
+		}, &types.DatabaseConfig{
+			Username:     utils.Config.ReaderDatabase.Username,
+			Password:     utils.Config.ReaderDatabase.Password,
+			Name:         utils.Config.ReaderDatabase.Name,
+			Host:         utils.Config.ReaderDatabase.Host,
+			Port:         utils.Config.ReaderDatabase.Port,
+			MaxOpenConns: utils.Config.ReaderDatabase.MaxOpenConns,
+			MaxIdleConns: utils.Config.ReaderDatabase.MaxIdleConns,
+			SSL:          utils.Config.ReaderDatabase.SSL,
+		}, "pgx", "postgres")
+		defer db.ReaderDb.Close()
+		defer db.WriterDb.Close()
+	}
 	blobIndexer, err := exporter.NewBlobIndexer()
 	if err != nil {
 		logrus.Fatal(err)
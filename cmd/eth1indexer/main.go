@@ -73,6 +73,9 @@ func main() {
 	enableEnsUpdater := flag.Bool("ens.enabled", false, "Enable ens update process")
 	ensBatchSize := flag.Int64("ens.batch", 200, "Batch size for ens updates")
 
+	enableProxyUpdater := flag.Bool("proxy.enabled", false, "Enable proxy implementation update process, resolving old blocks requires an archive node")
+	proxyBatchSize := flag.Int64("proxy.batch", 1000, "Batch size for proxy implementation updates")
+
 	flag.Parse()
 
 	if *versionFlag {
@@ -153,6 +156,9 @@ func main() {
 	if err != nil {
 		utils.LogFatal(err, "erigon client creation error", 0)
 	}
+	defer rpc.CloseRawStore()
+	// the contract transformer reads the storage slots of created proxies
+	rpc.CurrentErigonClient = client
 
 	chainId := strconv.FormatUint(utils.Config.Chain.ClConfig.DepositChainID, 10)
 
@@ -190,6 +196,10 @@ func main() {
 		go ImportEnsUpdatesLoop(bt, client, *ensBatchSize)
 	}
 
+	if *enableProxyUpdater {
+		go ImportProxyUpdatesLoop(bt, client, *proxyBatchSize)
+	}
+
 	if *enableFullBalanceUpdater {
 		ProcessMetadataUpdates(bt, client, balanceUpdaterPrefix, *balanceUpdaterBatchSize, -1)
 		return
@@ -201,8 +211,11 @@ func main() {
 		bt.TransformTx,
 		bt.TransformItx,
 		bt.TransformBlobTx,
+		bt.TransformSetCodeAuthorizations,
+		bt.TransformUserOperations,
 		bt.TransformERC20,
 		bt.TransformERC721,
+		bt.TransformApprovals,
 		bt.TransformERC1155,
 		bt.TransformUncle,
 		bt.TransformWithdrawals,
@@ -411,6 +424,18 @@ func ImportEnsUpdatesLoop(bt *db.Bigtable, client *rpc.ErigonClient, batchSize i
 	}
 }
 
+func ImportProxyUpdatesLoop(bt *db.Bigtable, client *rpc.ErigonClient, batchSize int64) {
+	for {
+		time.Sleep(time.Second * 5)
+		err := bt.ImportProxyUpdates(client, batchSize)
+		if err != nil {
+			logrus.WithError(err).Errorf("error importing proxy updates")
+		} else {
+			services.ReportStatus("proxyIndexer", "Running", nil)
+		}
+	}
+}
+
 func UpdateTokenPrices(bt *db.Bigtable, client *rpc.ErigonClient, tokenListPath string) error {
 
 	tokenListContent, err := os.ReadFile(tokenListPath)
@@ -222,6 +222,7 @@ func main() {
 	defer db.FrontendReaderDB.Close()
 	defer db.FrontendWriterDB.Close()
 	defer db.BigtableClient.Close()
+	defer rpc.CloseRawStore()
 
 	if utils.Config.Metrics.Enabled {
 		go metrics.MonitorDB(db.WriterDb)
@@ -247,16 +248,30 @@ func main() {
 	logrus.Infof("database connection established")
 
 	if utils.Config.Indexer.Enabled {
-		var rpcClient rpc.Client
-
 		chainID := new(big.Int).SetUint64(utils.Config.Chain.ClConfig.DepositChainID)
-		if utils.Config.Indexer.Node.Type == "lighthouse" {
-			rpcClient, err = rpc.NewLighthouseClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
+		rpcClient, err := rpc.NewClient(utils.Config.Indexer.Node.Type, "http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
+		if err != nil {
+			utils.LogFatal(err, "new explorer rpc client error", 0)
+		}
+
+		if len(utils.Config.Indexer.FailoverNodes) > 0 {
+			endpoints := []rpc.MultiClientEndpoint{{Name: cfg.Indexer.Node.Host + ":" + cfg.Indexer.Node.Port, Client: rpcClient}}
+			for _, node := range utils.Config.Indexer.FailoverNodes {
+				client, err := rpc.NewClient(node.Type, "http://"+node.Host+":"+node.Port, chainID)
+				if err != nil {
+					utils.LogFatal(err, "new explorer failover rpc client error", 0)
+				}
+				endpoints = append(endpoints, rpc.MultiClientEndpoint{Name: node.Host + ":" + node.Port, Client: client})
+			}
+
+			healthCheckInterval := utils.Config.Indexer.NodeHealthCheckInterval
+			if healthCheckInterval == 0 {
+				healthCheckInterval = time.Second * time.Duration(utils.Config.Chain.ClConfig.SecondsPerSlot)
+			}
+			rpcClient, err = rpc.NewMultiClient(endpoints, utils.Config.Indexer.NodeQuorum, healthCheckInterval)
 			if err != nil {
-				utils.LogFatal(err, "new explorer lighthouse client error", 0)
+				utils.LogFatal(err, "new explorer multi rpc client error", 0)
 			}
-		} else {
-			logrus.Fatalf("invalid note type %v specified. supported node types are prysm and lighthouse", utils.Config.Indexer.Node.Type)
 		}
 
 		go services.StartHistoricPriceService()
@@ -318,6 +333,9 @@ func main() {
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/consolidation_requests", handlers.ApiValidatorConsolidationRequests).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/switch_to_compounding_requests", handlers.ApiValidatorSwitchToCompoundingRequests).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/deposits", handlers.ApiValidatorDeposits).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/queue", handlers.ApiValidatorQueuePositions).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/slasher/attesterslashings", handlers.ApiSlasherAttesterSlashings).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/slasher/proposerslashings", handlers.ApiSlasherProposerSlashings).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationefficiency", handlers.ApiValidatorAttestationEfficiency).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationeffectiveness", handlers.ApiValidatorAttestationEffectiveness).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/stats/{index}", handlers.ApiValidatorDailyStats).Methods("GET", "OPTIONS")
@@ -331,6 +349,7 @@ func main() {
 		apiV1Router.HandleFunc("/dashboard/data/allbalances", handlers.DashboardDataBalanceCombined).Methods("GET", "OPTIONS") // consensus & execution
 		apiV1Router.HandleFunc("/dashboard/data/proposals", handlers.DashboardDataProposals).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/stripe/webhook", handlers.StripeWebhook).Methods("POST")
+		apiV1Router.HandleFunc("/telegram/updates", handlers.TelegramUpdates).Methods("POST")
 		apiV1Router.HandleFunc("/stats/{apiKey}/{machine}", handlers.ClientStatsPostOld).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/stats/{apiKey}", handlers.ClientStatsPostOld).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/client/metrics", handlers.ClientStatsPostNew).Methods("POST", "OPTIONS")
@@ -340,11 +359,16 @@ func main() {
 		apiV1Router.HandleFunc("/ethstore/{day}", handlers.ApiEthStoreDay).Methods("GET", "OPTIONS")
 
 		apiV1Router.HandleFunc("/execution/gasnow", handlers.ApiEth1GasNowData).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/blobs/submitters", handlers.ApiEth1BlobSubmitters).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/block/{blockNumber}", handlers.ApiETH1ExecBlocks).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/{addressIndexOrPubkey}/produced", handlers.ApiETH1AccountProducedBlocks).Methods("GET", "OPTIONS")
 
 		apiV1Router.HandleFunc("/execution/address/{address}", handlers.ApiEth1Address).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/address/{address}/erc20tokens", handlers.ApiEth1AddressERC20Tokens).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/authorizations", handlers.ApiEth1AddressSetCodeAuthorizations).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/userops", handlers.ApiEth1AddressUserOperations).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/approvals", handlers.ApiEth1AddressApprovals).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/proxy", handlers.ApiEth1AddressProxy).Methods("GET", "OPTIONS")
 
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/widget", handlers.GetMobileWidgetStatsGet).Methods("GET")
 		apiV1Router.HandleFunc("/dashboard/widget", handlers.GetMobileWidgetStatsPost).Methods("POST")
@@ -367,9 +391,18 @@ func main() {
 		apiV1AuthRouter.HandleFunc("/notifications/subscribe", handlers.UserNotificationsSubscribe).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications/unsubscribe", handlers.UserNotificationsUnsubscribe).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications", handlers.UserNotificationsSubscribed).Methods("POST", "GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/notifications/settings", handlers.ApiUserNotificationSettings).Methods("POST", "GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/notifications/digest", handlers.ApiUserNotificationsDigest).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/stats", handlers.ClientStats).Methods("GET", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/stats/{offset}/{limit}", handlers.ClientStats).Methods("GET", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/ethpool", handlers.RegisterEthpoolSubscription).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/webhooks/{webhookID}/deliveries", handlers.ApiUserWebhookDeliveries).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver", handlers.ApiUserWebhookRedeliver).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/webhooks/{webhookID}/secret", handlers.ApiUserWebhookRegenerateSecret).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/usage", handlers.ApiUserUsage).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/exports", handlers.ApiUserValidatorExportCreate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/exports", handlers.ApiUserValidatorExports).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/exports/{jobID}", handlers.ApiUserValidatorExport).Methods("GET", "OPTIONS")
 
 		apiV1AuthRouter.Use(utils.CORSMiddleware)
 		apiV1AuthRouter.Use(utils.AuthorizedAPIMiddleware)
@@ -436,6 +469,8 @@ func main() {
 			router.HandleFunc("/address/{address}/transactions", handlers.Eth1AddressTransactions).Methods("GET")
 			router.HandleFunc("/address/{address}/internalTxns", handlers.Eth1AddressInternalTransactions).Methods("GET")
 			router.HandleFunc("/address/{address}/blobTxns", handlers.Eth1AddressBlobTransactions).Methods("GET")
+			router.HandleFunc("/address/{address}/authorizations", handlers.Eth1AddressSetCodeAuthorizations).Methods("GET")
+			router.HandleFunc("/address/{address}/userops", handlers.Eth1AddressUserOperations).Methods("GET")
 			router.HandleFunc("/address/{address}/erc20", handlers.Eth1AddressErc20Transactions).Methods("GET")
 			router.HandleFunc("/address/{address}/erc721", handlers.Eth1AddressErc721Transactions).Methods("GET")
 			router.HandleFunc("/address/{address}/erc1155", handlers.Eth1AddressErc1155Transactions).Methods("GET")
@@ -601,6 +636,14 @@ func main() {
 			authRouter.HandleFunc("/webhooks/add", handlers.UsersAddWebhook).Methods("POST")
 			authRouter.HandleFunc("/webhooks/{webhookID}/update", handlers.UsersEditWebhook).Methods("POST")
 			authRouter.HandleFunc("/webhooks/{webhookID}/delete", handlers.UsersDeleteWebhook).Methods("POST")
+			authRouter.HandleFunc("/webhooks/{webhookID}/secret", handlers.UsersRegenerateWebhookSecret).Methods("POST")
+			authRouter.HandleFunc("/webhooks/{webhookID}/verify", handlers.UsersVerifyWebhook).Methods("POST")
+			authRouter.HandleFunc("/api-usage", handlers.UserApiUsage).Methods("GET")
+			authRouter.HandleFunc("/api-usage/export", handlers.UserApiUsageExport).Methods("GET")
+			authRouter.HandleFunc("/api-keys", handlers.UserApiKeys).Methods("GET")
+			authRouter.HandleFunc("/api-keys/create", handlers.UserApiKeyCreate).Methods("POST")
+			authRouter.HandleFunc("/api-keys/{keyID}/rotate", handlers.UserApiKeyRotate).Methods("POST")
+			authRouter.HandleFunc("/api-keys/{keyID}/revoke", handlers.UserApiKeyRevoke).Methods("POST")
 
 			err = initStripe(authRouter)
 			if err != nil {
@@ -642,6 +685,22 @@ func main() {
 
 		n.UseHandler(utils.SessionStore.SCS.LoadAndSave(router))
 
+		// the event stream is served without compression and sessions as their response writers do not support
+		// streaming without a write timeout
+		eventsRouter := mux.NewRouter()
+		eventsRouter.HandleFunc("/api/v1/events", handlers.ApiEvents).Methods("GET", "OPTIONS")
+		eventsRouter.Use(utils.CORSMiddleware)
+		if utils.Config.Metrics.Enabled {
+			eventsRouter.Use(metrics.HttpMiddleware)
+		}
+		eventsRouter.Use(ratelimit.HttpMiddleware)
+
+		rootHandler := http.NewServeMux()
+		rootHandler.HandleFunc("/api/v1/events", func(w http.ResponseWriter, r *http.Request) {
+			pa.ServeHTTP(w, r, eventsRouter.ServeHTTP)
+		})
+		rootHandler.Handle("/", n)
+
 		if utils.Config.Frontend.HttpWriteTimeout == 0 {
 			utils.Config.Frontend.HttpWriteTimeout = time.Second * 15
 		}
@@ -656,7 +715,7 @@ func main() {
 			WriteTimeout: utils.Config.Frontend.HttpWriteTimeout,
 			ReadTimeout:  utils.Config.Frontend.HttpReadTimeout,
 			IdleTimeout:  utils.Config.Frontend.HttpIdleTimeout,
-			Handler:      n,
+			Handler:      rootHandler,
 		}
 
 		logrus.Printf("http server listening on %v", frontendHttpServer.Addr)
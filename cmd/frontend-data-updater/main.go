@@ -126,9 +126,9 @@ func main() {
 	price.Init(utils.Config.Chain.ClConfig.DepositChainID, utils.Config.Eth1ErigonEndpoint, utils.Config.Frontend.ClCurrency, utils.Config.Frontend.ElCurrency)
 
 	chainID := new(big.Int).SetUint64(utils.Config.Chain.ClConfig.DepositChainID)
-	rpcClient, err := rpc.NewLighthouseClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
+	rpcClient, err := rpc.NewClient(utils.Config.Indexer.Node.Type, "http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
 	if err != nil {
-		utils.LogFatal(err, "new explorer lighthouse client error", 0)
+		utils.LogFatal(err, "new frontend-data-updater rpc client error", 0)
 	}
 	rpc.CurrentClient = rpcClient
 
@@ -95,9 +95,9 @@ func main() {
 
 	chainIDBig := new(big.Int).SetUint64(utils.Config.Chain.ClConfig.DepositChainID)
 
-	rpcClient, err := rpc.NewLighthouseClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainIDBig)
+	rpcClient, err := rpc.NewClient(utils.Config.Indexer.Node.Type, "http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainIDBig)
 	if err != nil {
-		utils.LogFatal(err, "new bigtable lighthouse client error", 0)
+		utils.LogFatal(err, "new bigtable rpc client error", 0)
 	}
 
 	gOuter := errgroup.Group{}
@@ -186,10 +186,9 @@ func monitor(configPath string) {
 
 	chainIDBig := new(big.Int).SetUint64(utils.Config.Chain.ClConfig.DepositChainID)
 
-	var rpcClient rpc.Client
-	rpcClient, err = rpc.NewLighthouseClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainIDBig)
+	rpcClient, err := rpc.NewClient(utils.Config.Indexer.Node.Type, "http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainIDBig)
 	if err != nil {
-		utils.LogFatal(err, "new bigtable lighthouse client in monitor error", 0)
+		utils.LogFatal(err, "new bigtable rpc client in monitor error", 0)
 	}
 	current := uint64(0)
 
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/exporter"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	backfillStorePostgres   = "postgres"
+	backfillStoreBigtable   = "bigtable"
+	backfillStoreClickhouse = "clickhouse"
+)
+
+// backfillMaxUnitEpochs is the maximum number of consecutive epochs that are exported by a single work unit
+const backfillMaxUnitEpochs = 10
+
+// backfill scans all stores for missing epochs in the range of start-epoch and end-epoch, plans work units for the
+// gaps and exports them from the beacon node. The progress is persisted so that an interrupted run of the same range
+// resumes with the epochs that have not been exported yet.
+func backfill() error {
+	if opts.EndEpoch < opts.StartEpoch {
+		return fmt.Errorf("end-epoch %v must not be smaller than start-epoch %v", opts.EndEpoch, opts.StartEpoch)
+	}
+	latestFinalizedEpoch, err := db.GetLatestFinalizedEpoch()
+	if err != nil {
+		return err
+	}
+	// only finalized epochs are backfilled, the slot exporter takes care of the epochs after them
+	if opts.EndEpoch > latestFinalizedEpoch {
+		return fmt.Errorf("end-epoch %v is not finalized yet, the latest finalized epoch is %v", opts.EndEpoch, latestFinalizedEpoch)
+	}
+
+	gapsBefore, err := scanBackfillGaps(opts.StartEpoch, opts.EndEpoch)
+	if err != nil {
+		return err
+	}
+
+	jobID, err := db.GetUnfinishedBackfillJob(opts.StartEpoch, opts.EndEpoch)
+	if err != nil {
+		return err
+	}
+	var units []*types.BackfillUnit
+	if jobID != 0 {
+		units, err = db.GetBackfillUnits(jobID)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("resuming backfill job %v of epochs %v - %v with %v open units", jobID, opts.StartEpoch, opts.EndEpoch, len(units))
+	} else {
+		units = planBackfillUnits(gapsBefore)
+		logrus.Infof("planned %v backfill units for epochs %v - %v", len(units), opts.StartEpoch, opts.EndEpoch)
+	}
+
+	if opts.DryRun {
+		for _, u := range units {
+			logrus.Infof("backfill unit: epochs %v - %v (next epoch %v), stores %v", u.StartEpoch, u.EndEpoch, u.NextEpoch, u.Stores)
+		}
+		logBackfillSummary(gapsBefore, nil)
+		logrus.Infof("dry run, not exporting any epochs, run with -dry-run=false to backfill")
+		return nil
+	}
+
+	if jobID == 0 {
+		if len(units) == 0 {
+			logBackfillSummary(gapsBefore, gapsBefore)
+			return nil
+		}
+		jobID, err = db.CreateBackfillJob(opts.StartEpoch, opts.EndEpoch, units)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("created backfill job %v", jobID)
+	}
+
+	start := time.Now()
+	failed := 0
+	failedMux := &sync.Mutex{}
+	g := new(errgroup.Group)
+	g.SetLimit(int(opts.DataConcurrency))
+	for _, u := range units {
+		u := u
+		g.Go(func() error {
+			err := runBackfillUnit(u)
+			if err != nil {
+				logrus.WithError(err).Errorf("error backfilling epochs %v - %v", u.StartEpoch, u.EndEpoch)
+				u.Status = "failed"
+				u.Error = sql.NullString{String: err.Error(), Valid: true}
+				failedMux.Lock()
+				failed++
+				failedMux.Unlock()
+			} else {
+				u.Status = "done"
+				u.Error = sql.NullString{}
+			}
+			// a unit whose status could not be saved is simply retried by the next run
+			return db.UpdateBackfillUnitStatus(u)
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		return err
+	}
+	logrus.Infof("backfilled %v units of job %v in %v, %v failed", len(units)-failed, jobID, time.Since(start), failed)
+
+	if failed == 0 {
+		err = db.FinishBackfillJob(jobID)
+		if err != nil {
+			return err
+		}
+	}
+
+	gapsAfter, err := scanBackfillGaps(opts.StartEpoch, opts.EndEpoch)
+	if err != nil {
+		return err
+	}
+	logBackfillSummary(gapsBefore, gapsAfter)
+
+	if failed > 0 {
+		return fmt.Errorf("%v backfill units of job %v failed, rerun the command to retry them", failed, jobID)
+	}
+	return nil
+}
+
+// scanBackfillGaps returns the missing epochs of the range per store
+func scanBackfillGaps(startEpoch, endEpoch uint64) (map[string][]uint64, error) {
+	start := time.Now()
+	gaps := make(map[string][]uint64)
+
+	missing, err := db.GetPostgresMissingEpochs(startEpoch, endEpoch)
+	if err != nil {
+		return nil, err
+	}
+	gaps[backfillStorePostgres] = missing
+
+	missing, err = getBigtableMissingEpochs(startEpoch, endEpoch)
+	if err != nil {
+		return nil, err
+	}
+	gaps[backfillStoreBigtable] = missing
+
+	if utils.Config.ClickHouseEnabled {
+		missing, err = db.GetClickhouseMissingEpochs(startEpoch, endEpoch)
+		if err != nil {
+			return nil, err
+		}
+		gaps[backfillStoreClickhouse] = missing
+	}
+
+	logrus.Infof("scanned epochs %v - %v for gaps in %v stores, took %v", startEpoch, endEpoch, len(gaps), time.Since(start))
+	return gaps, nil
+}
+
+// bigtableGapScanEpochs is the number of epochs whose validator history is read from bigtable with one range scan
+const bigtableGapScanEpochs = 10000
+
+// getBigtableMissingEpochs returns the epochs of the range without validator balance history in bigtable
+func getBigtableMissingEpochs(startEpoch, endEpoch uint64) ([]uint64, error) {
+	missing := []uint64{}
+	for batchStart := startEpoch; batchStart <= endEpoch; batchStart += bigtableGapScanEpochs {
+		batchEnd := min(batchStart+bigtableGapScanEpochs-1, endEpoch)
+		maxIndices, err := db.BigtableClient.GetMaxValidatorindexForEpochs(batchStart, batchEnd)
+		if err != nil {
+			return nil, fmt.Errorf("error getting max validator indices of epochs %v - %v from bigtable: %w", batchStart, batchEnd, err)
+		}
+		missing = append(missing, missingEpochs(batchStart, batchEnd, maxIndices)...)
+	}
+	return missing, nil
+}
+
+// missingEpochs returns the epochs of the range without a highest active validator index
+func missingEpochs(startEpoch, endEpoch uint64, maxIndices map[uint64]uint64) []uint64 {
+	missing := []uint64{}
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		if maxIndices[epoch] == 0 {
+			missing = append(missing, epoch)
+		}
+	}
+	return missing
+}
+
+// planBackfillUnits groups the epochs missing in postgres or bigtable into units of consecutive epochs, clickhouse
+// gaps are only reported as clickhouse is filled from the exported data by its own pipeline
+func planBackfillUnits(gaps map[string][]uint64) []*types.BackfillUnit {
+	storesByEpoch := make(map[uint64][]string)
+	epochs := []uint64{}
+	for _, store := range []string{backfillStorePostgres, backfillStoreBigtable} {
+		for _, epoch := range gaps[store] {
+			if storesByEpoch[epoch] == nil {
+				epochs = append(epochs, epoch)
+			}
+			storesByEpoch[epoch] = append(storesByEpoch[epoch], store)
+		}
+	}
+	slices.Sort(epochs)
+
+	units := []*types.BackfillUnit{}
+	var current *types.BackfillUnit
+	for _, epoch := range epochs {
+		if current == nil || epoch != current.EndEpoch+1 || epoch-current.StartEpoch >= backfillMaxUnitEpochs {
+			current = &types.BackfillUnit{StartEpoch: epoch, EndEpoch: epoch, NextEpoch: epoch, Status: "pending"}
+			units = append(units, current)
+		}
+		current.EndEpoch = epoch
+		for _, store := range storesByEpoch[epoch] {
+			if !slices.Contains(current.Stores, store) {
+				current.Stores = append(current.Stores, store)
+			}
+		}
+	}
+	return units
+}
+
+// runBackfillUnit exports the remaining epochs of the unit and persists the progress after every epoch
+func runBackfillUnit(unit *types.BackfillUnit) error {
+	for epoch := unit.NextEpoch; epoch <= unit.EndEpoch; epoch++ {
+		err := backfillEpoch(epoch)
+		if err != nil {
+			return err
+		}
+		unit.NextEpoch = epoch + 1
+		err = db.UpdateBackfillUnitProgress(unit)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("backfilled epoch %v", epoch)
+	}
+	return nil
+}
+
+func backfillEpoch(epoch uint64) error {
+	tx, err := db.WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for slot := epoch * utils.Config.Chain.ClConfig.SlotsPerEpoch; slot < (epoch+1)*utils.Config.Chain.ClConfig.SlotsPerEpoch; slot++ {
+		err = exporter.ExportSlot(rpcClient, slot, false, tx)
+		if err != nil {
+			return fmt.Errorf("error exporting slot %v: %w", slot, err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing tx of epoch %v: %w", epoch, err)
+	}
+	return nil
+}
+
+// logBackfillSummary logs the number of missing epochs per store before and, if available, after the backfill
+func logBackfillSummary(before, after map[string][]uint64) {
+	checked := opts.EndEpoch - opts.StartEpoch + 1
+	for _, store := range []string{backfillStorePostgres, backfillStoreBigtable, backfillStoreClickhouse} {
+		missingBefore, ok := before[store]
+		if !ok {
+			logrus.WithField("store", store).Infof("store not scanned")
+			continue
+		}
+		fields := logrus.Fields{
+			"store":       store,
+			"checked":     checked,
+			"gaps_before": len(missingBefore),
+		}
+		if after != nil {
+			missingAfter := after[store]
+			fields["gaps_after"] = len(missingAfter)
+			if len(missingAfter) > 0 {
+				fields["first_gap"] = missingAfter[0]
+			}
+			if len(missingAfter) == 0 {
+				logrus.WithFields(fields).Infof("store is consistent")
+			} else {
+				logrus.WithFields(fields).Warnf("store still has gaps")
+			}
+			continue
+		}
+		logrus.WithFields(fields).Infof("store scanned")
+	}
+}
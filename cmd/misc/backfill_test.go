@@ -0,0 +1,92 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+func TestPlanBackfillUnits(t *testing.T) {
+	tests := []struct {
+		name string
+		gaps map[string][]uint64
+		want []*types.BackfillUnit
+	}{
+		{
+			name: "no gaps",
+			gaps: map[string][]uint64{backfillStorePostgres: {}, backfillStoreBigtable: {}},
+			want: []*types.BackfillUnit{},
+		},
+		{
+			name: "consecutive epochs of both stores are merged",
+			gaps: map[string][]uint64{backfillStorePostgres: {5, 6}, backfillStoreBigtable: {7}},
+			want: []*types.BackfillUnit{
+				{StartEpoch: 5, EndEpoch: 7, NextEpoch: 5, Status: "pending", Stores: []string{backfillStorePostgres, backfillStoreBigtable}},
+			},
+		},
+		{
+			name: "gaps are split",
+			gaps: map[string][]uint64{backfillStorePostgres: {10, 3}, backfillStoreBigtable: {3}},
+			want: []*types.BackfillUnit{
+				{StartEpoch: 3, EndEpoch: 3, NextEpoch: 3, Status: "pending", Stores: []string{backfillStorePostgres, backfillStoreBigtable}},
+				{StartEpoch: 10, EndEpoch: 10, NextEpoch: 10, Status: "pending", Stores: []string{backfillStorePostgres}},
+			},
+		},
+		{
+			name: "units are limited to the max number of epochs",
+			gaps: map[string][]uint64{backfillStoreBigtable: {0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+			want: []*types.BackfillUnit{
+				{StartEpoch: 0, EndEpoch: 9, NextEpoch: 0, Status: "pending", Stores: []string{backfillStoreBigtable}},
+				{StartEpoch: 10, EndEpoch: 11, NextEpoch: 10, Status: "pending", Stores: []string{backfillStoreBigtable}},
+			},
+		},
+		{
+			name: "clickhouse gaps are only reported",
+			gaps: map[string][]uint64{backfillStoreClickhouse: {1, 2}, backfillStorePostgres: {2}},
+			want: []*types.BackfillUnit{
+				{StartEpoch: 2, EndEpoch: 2, NextEpoch: 2, Status: "pending", Stores: []string{backfillStorePostgres}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planBackfillUnits(tt.gaps)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got units %v, want %v", formatBackfillUnits(got), formatBackfillUnits(tt.want))
+			}
+		})
+	}
+}
+
+func formatBackfillUnits(units []*types.BackfillUnit) []types.BackfillUnit {
+	res := make([]types.BackfillUnit, 0, len(units))
+	for _, u := range units {
+		res = append(res, *u)
+	}
+	return res
+}
+
+func TestMissingEpochs(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end uint64
+		maxIndices map[uint64]uint64
+		want       []uint64
+	}{
+		{name: "complete", start: 4, end: 6, maxIndices: map[uint64]uint64{4: 10, 5: 10, 6: 11}, want: []uint64{}},
+		{name: "empty", start: 4, end: 6, maxIndices: map[uint64]uint64{}, want: []uint64{4, 5, 6}},
+		{name: "gaps and epochs outside of the range", start: 4, end: 7, maxIndices: map[uint64]uint64{3: 10, 5: 10, 6: 0, 8: 10}, want: []uint64{4, 6, 7}},
+		{name: "single epoch", start: 0, end: 0, maxIndices: map[uint64]uint64{0: 1}, want: []uint64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := missingEpochs(tt.start, tt.end, tt.maxIndices)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
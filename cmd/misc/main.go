@@ -68,14 +68,13 @@ var opts = struct {
 
 var bt *db.Bigtable
 var erigonClient *rpc.ErigonClient
-var lighthouseClient *rpc.LighthouseClient
-var rpcClient *rpc.LighthouseClient
+var rpcClient rpc.Client
 
 func main() {
 	statsPartitionCommand := commands.StatsMigratorCommand{}
 
 	configPath := flag.String("config", "config/default.config.yml", "Path to the config file")
-	flag.StringVar(&opts.Command, "command", "", "command to run, available: updateAPIKey, applyDbSchema, initBigtableSchema, epoch-export, debug-rewards, debug-blocks, clear-bigtable, index-old-eth1-blocks, update-aggregation-bits, historic-prices-export, index-missing-blocks, re-index-blocks, export-epoch-missed-slots, migrate-last-attestation-slot-bigtable, export-genesis-validators, update-block-finalization-sequentially, nameValidatorsByRanges, export-stats-totals, export-sync-committee-periods, export-sync-committee-validator-stats, partition-validator-stats, migrate-app-purchases, disable-user-per-email, validate-firebase-tokens")
+	flag.StringVar(&opts.Command, "command", "", "command to run, available: updateAPIKey, applyDbSchema, initBigtableSchema, epoch-export, debug-rewards, debug-blocks, clear-bigtable, index-old-eth1-blocks, update-aggregation-bits, historic-prices-export, index-missing-blocks, re-index-blocks, export-epoch-missed-slots, migrate-last-attestation-slot-bigtable, export-genesis-validators, update-block-finalization-sequentially, nameValidatorsByRanges, export-stats-totals, export-sync-committee-periods, export-sync-committee-validator-stats, partition-validator-stats, migrate-app-purchases, disable-user-per-email, validate-firebase-tokens, backfill")
 	flag.Uint64Var(&opts.StartEpoch, "start-epoch", 0, "start epoch")
 	flag.Uint64Var(&opts.EndEpoch, "end-epoch", 0, "end epoch")
 	flag.Uint64Var(&opts.User, "user", 0, "user id")
@@ -145,11 +144,10 @@ func main() {
 	go func() {
 		defer wg.Done()
 		var err error
-		rpcClient, err = rpc.NewLighthouseClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainIDBig)
+		rpcClient, err = rpc.NewClient(utils.Config.Indexer.Node.Type, "http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainIDBig)
 		if err != nil {
-			utils.LogFatal(err, "lighthouse client error", 0)
+			utils.LogFatal(err, "rpc client error", 0)
 		}
-		lighthouseClient = rpcClient
 	}()
 
 	go func() {
@@ -159,6 +157,7 @@ func main() {
 		if err != nil {
 			logrus.Fatalf("error initializing erigon client: %v", err)
 		}
+		rpc.CurrentErigonClient = erigonClient
 	}()
 
 	go func() {
@@ -209,11 +208,26 @@ func main() {
 
 	wg.Wait()
 
+	// only the backfill reads from clickhouse, it reports the gaps of clickhouse as well
+	if utils.Config.ClickHouseEnabled && opts.Command == "backfill" {
+		db.MustInitClickhouseDB(nil, &types.DatabaseConfig{
+			Username:     cfg.ClickHouse.ReaderDatabase.Username,
+			Password:     cfg.ClickHouse.ReaderDatabase.Password,
+			Name:         cfg.ClickHouse.ReaderDatabase.Name,
+			Host:         cfg.ClickHouse.ReaderDatabase.Host,
+			Port:         cfg.ClickHouse.ReaderDatabase.Port,
+			MaxOpenConns: cfg.ClickHouse.ReaderDatabase.MaxOpenConns,
+			MaxIdleConns: cfg.ClickHouse.ReaderDatabase.MaxIdleConns,
+			SSL:          cfg.ClickHouse.ReaderDatabase.SSL,
+		}, "clickhouse", "clickhouse")
+	}
+
 	defer db.ReaderDb.Close()
 	defer db.WriterDb.Close()
 
 	defer db.FrontendReaderDB.Close()
 	defer db.FrontendWriterDB.Close()
+	defer rpc.CloseRawStore()
 
 	switch opts.Command {
 	case "nameValidatorsByRanges":
@@ -448,6 +462,8 @@ func main() {
 		err = disableUserPerEmail()
 	case "fix-epochs":
 		err = fixEpochs()
+	case "backfill":
+		err = backfill()
 	case "fix-internal-txs-from-node":
 		fixInternalTxsFromNode(opts.StartBlock, opts.EndBlock, opts.BatchSize, opts.DataConcurrency, bt)
 	case "validate-firebase-tokens":
@@ -480,7 +496,7 @@ func fixEpoch(e uint64) error {
 		return fmt.Errorf("error starting tx: %w", err)
 	}
 	defer tx.Rollback()
-	s, err := lighthouseClient.GetValidatorParticipation(e)
+	s, err := rpcClient.GetValidatorParticipation(e)
 	if err != nil {
 		return err
 	}
@@ -1157,7 +1173,7 @@ func debugBlocks() error {
 		return err
 	}
 
-	clClient, err := rpc.NewLighthouseClient(fmt.Sprintf("http://%v:%v", utils.Config.Indexer.Node.Host, utils.Config.Indexer.Node.Port), new(big.Int).SetUint64(utils.Config.Chain.ClConfig.DepositChainID))
+	clClient, err := rpc.NewClient(utils.Config.Indexer.Node.Type, fmt.Sprintf("http://%v:%v", utils.Config.Indexer.Node.Host, utils.Config.Indexer.Node.Port), new(big.Int).SetUint64(utils.Config.Chain.ClConfig.DepositChainID))
 	if err != nil {
 		return err
 	}
@@ -1298,7 +1314,7 @@ func migrateLastAttestationSlotToBigtable() {
 	}
 }
 
-func updateAggreationBits(rpcClient *rpc.LighthouseClient, startEpoch uint64, endEpoch uint64, concurency uint64) {
+func updateAggreationBits(rpcClient rpc.Client, startEpoch uint64, endEpoch uint64, concurency uint64) {
 	logrus.Infof("update-aggregation-bits epochs %v - %v", startEpoch, endEpoch)
 	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
 		logrus.Infof("Getting data from the node for epoch %v", epoch)
@@ -1508,6 +1524,28 @@ func updateAPIKey(user uint64) error {
 		return fmt.Errorf("error too many rows affected expected 1 but got: %v", amount)
 	}
 
+	// the new key keeps the name, scopes and allowed ips of the old key, the old key stops working immediately
+	rows, err = tx.Exec(`
+		INSERT INTO api_keys (user_id, api_key, name, scopes, allowed_ips, valid_until, changed_at)
+		SELECT user_id, $1, name, scopes, allowed_ips, valid_until, NOW() FROM api_keys WHERE api_key = $2 AND user_id = $3`, apiKey, u.OldKey, user)
+	if err != nil {
+		return err
+	}
+	amount, err = rows.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if amount == 0 {
+		_, err = tx.Exec(`INSERT INTO api_keys (user_id, api_key, changed_at) VALUES ($1, $2, NOW())`, user, apiKey)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`UPDATE api_keys SET valid_until = NOW(), revoked_at = NOW(), changed_at = NOW() WHERE api_key = $1 AND valid_until > NOW()`, u.OldKey)
+	if err != nil {
+		return err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return err
@@ -1791,7 +1829,7 @@ func getTransformers(transformerFlag string, bt *db.Bigtable) ([]db.TransformFun
 	logrus.Infof("transformerFlag: %v", transformerFlag)
 	transformerList := strings.Split(transformerFlag, ",")
 	if transformerFlag == "all" {
-		transformerList = []string{"TransformBlock", "TransformTx", "TransformBlobTx", "TransformItx", "TransformERC20", "TransformERC721", "TransformERC1155", "TransformWithdrawals", "TransformUncle", "TransformEnsNameRegistered", "TransformContract", "TransformConsolidationRequests", "TransformWithdrawalRequests"}
+		transformerList = []string{"TransformBlock", "TransformTx", "TransformBlobTx", "TransformSetCodeAuthorizations", "TransformUserOperations", "TransformItx", "TransformERC20", "TransformERC721", "TransformApprovals", "TransformERC1155", "TransformWithdrawals", "TransformUncle", "TransformEnsNameRegistered", "TransformContract", "TransformConsolidationRequests", "TransformWithdrawalRequests"}
 	} else if len(transformerList) == 0 {
 		utils.LogError(nil, "no transformer functions provided", 0)
 		return nil, false, fmt.Errorf("no transformer functions provided")
@@ -1807,12 +1845,18 @@ func getTransformers(transformerFlag string, bt *db.Bigtable) ([]db.TransformFun
 			transforms = append(transforms, bt.TransformTx)
 		case "TransformBlobTx":
 			transforms = append(transforms, bt.TransformBlobTx)
+		case "TransformSetCodeAuthorizations":
+			transforms = append(transforms, bt.TransformSetCodeAuthorizations)
+		case "TransformUserOperations":
+			transforms = append(transforms, bt.TransformUserOperations)
 		case "TransformItx":
 			transforms = append(transforms, bt.TransformItx)
 		case "TransformERC20":
 			transforms = append(transforms, bt.TransformERC20)
 		case "TransformERC721":
 			transforms = append(transforms, bt.TransformERC721)
+		case "TransformApprovals":
+			transforms = append(transforms, bt.TransformApprovals)
 		case "TransformERC1155":
 			transforms = append(transforms, bt.TransformERC1155)
 		case "TransformWithdrawals":
@@ -141,16 +141,10 @@ func main() {
 		cache.MustInitTieredCache(utils.Config.RedisCacheEndpoint)
 	}
 
-	var rpcClient rpc.Client
-
 	chainID := new(big.Int).SetUint64(utils.Config.Chain.ClConfig.DepositChainID)
-	if utils.Config.Indexer.Node.Type == "lighthouse" {
-		rpcClient, err = rpc.NewLighthouseClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
-		if err != nil {
-			utils.LogFatal(err, "new explorer lighthouse client error", 0)
-		}
-	} else {
-		logrus.Fatalf("invalid note type %v specified. supported node types are prysm and lighthouse", utils.Config.Indexer.Node.Type)
+	rpcClient, err := rpc.NewClient(utils.Config.Indexer.Node.Type, "http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
+	if err != nil {
+		utils.LogFatal(err, "new statistics rpc client error", 0)
 	}
 
 	if opt.statisticsDaysToExport != "" {
@@ -15,6 +15,13 @@ import (
 
 func main() {
 	configPath := flag.String("config", "config/default.config.yml", "Path to the config file")
+	backend := flag.String("backend", "bigtable", "Storage backend of the raw store (bigtable or pebble)")
+	pebblePath := flag.String("pebble.path", "data/raw", "Directory of the pebble database if the pebble backend is used")
+	command := flag.String("command", "", "Command to run instead of serving the store, index-hashes writes the block and transaction hash rows of already stored blocks")
+	chainID := flag.Uint64("chain-id", 0, "Chain id of the blocks to index, defaults to the deposit chain id of the config")
+	start := flag.Int64("start", 0, "First block to index")
+	end := flag.Int64("end", 0, "Last block to index")
+	batchSize := flag.Int64("batch-size", 1000, "Number of blocks to index at once")
 	flag.Parse()
 
 	cfg := &types.Config{}
@@ -23,13 +30,42 @@ func main() {
 		panic(err)
 	}
 
-	bt, err := store.NewBigTable(cfg.RawBigtable.Bigtable.Project, cfg.RawBigtable.Bigtable.Instance, nil)
+	var db store.TableStore
+	switch *backend {
+	case "bigtable":
+		db, err = store.NewBigTable(cfg.RawBigtable.Bigtable.Project, cfg.RawBigtable.Bigtable.Instance, nil)
+	case "pebble":
+		db, err = store.NewPebble(*pebblePath)
+	default:
+		logrus.Fatalf("unknown storage backend %v", *backend)
+	}
 	if err != nil {
 		panic(err)
 	}
-	remote := store.NewRemoteStore(store.Wrap(bt, db2.BlocksRawTable, ""))
+	defer db.Close()
+
+	switch *command {
+	case "":
+	case "index-hashes":
+		if *chainID == 0 {
+			*chainID = cfg.Chain.ClConfig.DepositChainID
+		}
+		if *end < *start || *batchSize <= 0 {
+			logrus.Fatalf("invalid block range %v to %v or batch size %v", *start, *end, *batchSize)
+		}
+		raw := db2.NewRawStore(store.Wrap(db, db2.BlocksRawTable, ""))
+		if err := raw.IndexHashes(*chainID, *start, *end, *batchSize); err != nil {
+			logrus.Fatalf("error indexing hashes: %v", err)
+		}
+		logrus.Infof("indexed the hashes of blocks %v to %v", *start, *end)
+		return
+	default:
+		logrus.Fatalf("unknown command %v", *command)
+	}
+
+	remote := store.NewRemoteStore(store.Wrap(db, db2.BlocksRawTable, ""))
 	go func() {
-		logrus.Info("starting remote raw store on port 8087")
+		logrus.Infof("starting remote raw store on port 8087 using the %v backend", *backend)
 		if err := http.ListenAndServe("0.0.0.0:8087", remote.Routes()); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			panic(err)
 		}
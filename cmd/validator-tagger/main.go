@@ -87,6 +87,7 @@ func main() {
 	if err != nil {
 		logrus.Fatalf("error initializing erigon client: %v", err)
 	}
+	defer rpc.CloseRawStore()
 
 	if utils.Config.Metrics.Enabled {
 		go func(addr string) {
@@ -37,3 +37,6 @@ var PectraDevnet5ChainYml string
 
 //go:embed pectra-devnet-6.chain.yml
 var PectraDevnet6ChainYml string
+
+//go:embed rollups.json
+var RollupsJson string
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// ErrApiKeyNotFound is returned if the api key does not exist, belongs to another user or is not valid anymore
+var ErrApiKeyNotFound = errors.New("api key not found")
+
+// GetUserApiKeys returns the api keys of a user, keys that expired more than 30 days ago are omitted
+func GetUserApiKeys(userId uint64) ([]*types.ApiKey, error) {
+	keys := []*types.ApiKey{}
+	err := FrontendWriterDB.Select(&keys, `
+		SELECT id, api_key, name, scopes, allowed_ips, created_at, valid_until, last_used_at, revoked_at, rotated_to
+		FROM api_keys
+		WHERE user_id = $1 AND valid_until > NOW() - INTERVAL '30 days'
+		ORDER BY valid_until > NOW() DESC, created_at DESC`, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting api keys of user %v: %w", userId, err)
+	}
+	return keys, nil
+}
+
+// CountUserActiveApiKeys returns the number of api keys of a user that are not expired
+func CountUserActiveApiKeys(userId uint64) (int, error) {
+	var count int
+	err := FrontendWriterDB.Get(&count, `SELECT COUNT(*) FROM api_keys WHERE user_id = $1 AND valid_until > NOW()`, userId)
+	if err != nil {
+		return 0, fmt.Errorf("error counting api keys of user %v: %w", userId, err)
+	}
+	return count, nil
+}
+
+// CreateUserApiKey creates a new api key for a user, a zero validUntil creates a key that does not expire
+func CreateUserApiKey(userId uint64, name string, scopes, allowedIPs []string, validUntil time.Time) (*types.ApiKey, error) {
+	key, err := utils.GenerateRandomAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	if validUntil.IsZero() {
+		validUntil = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+	}
+
+	apiKey := &types.ApiKey{}
+	err = FrontendWriterDB.Get(apiKey, `
+		INSERT INTO api_keys (user_id, api_key, name, scopes, allowed_ips, valid_until, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id, api_key, name, scopes, allowed_ips, created_at, valid_until, last_used_at, revoked_at, rotated_to`,
+		userId, key, name, pq.StringArray(scopes), pq.StringArray(allowedIPs), validUntil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating api key for user %v: %w", userId, err)
+	}
+	return apiKey, nil
+}
+
+// RotateUserApiKey replaces an api key of a user with a new key with the same name, scopes, allowed ips and expiry. The
+// old key stays valid for the overlap so that clients can switch to the new key.
+func RotateUserApiKey(userId, keyId uint64, overlap time.Duration) (*types.ApiKey, error) {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	old := &types.ApiKey{}
+	err = tx.Get(old, `
+		SELECT id, api_key, name, scopes, allowed_ips, created_at, valid_until, last_used_at, revoked_at, rotated_to
+		FROM api_keys
+		WHERE id = $1 AND user_id = $2 AND valid_until > NOW() AND rotated_to IS NULL
+		FOR UPDATE`, keyId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrApiKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting api key %v of user %v: %w", keyId, userId, err)
+	}
+
+	key, err := utils.GenerateRandomAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	apiKey := &types.ApiKey{}
+	err = tx.Get(apiKey, `
+		INSERT INTO api_keys (user_id, api_key, name, scopes, allowed_ips, valid_until, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id, api_key, name, scopes, allowed_ips, created_at, valid_until, last_used_at, revoked_at, rotated_to`,
+		userId, key, old.Name, old.Scopes, old.AllowedIPs, old.ValidUntil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating rotated api key for user %v: %w", userId, err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE api_keys SET valid_until = LEAST(valid_until, NOW() + $1 * INTERVAL '1 second'), rotated_to = $2, changed_at = NOW()
+		WHERE id = $3`, int64(overlap.Seconds()), apiKey.Id, old.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error expiring rotated api key %v: %w", old.Id, err)
+	}
+
+	// the key of the users table is shown in the settings and used by the app
+	_, err = tx.Exec(`UPDATE users SET api_key = $1 WHERE id = $2 AND api_key = $3`, apiKey.ApiKey, userId, old.ApiKey)
+	if err != nil {
+		return nil, fmt.Errorf("error updating api key of user %v: %w", userId, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+// RevokeUserApiKey invalidates an api key of a user immediately
+func RevokeUserApiKey(userId, keyId uint64) error {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var key string
+	err = tx.Get(&key, `
+		UPDATE api_keys SET valid_until = NOW(), revoked_at = NOW(), changed_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND valid_until > NOW()
+		RETURNING api_key`, keyId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrApiKeyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error revoking api key %v of user %v: %w", keyId, userId, err)
+	}
+
+	_, err = tx.Exec(`UPDATE users SET api_key = NULL WHERE id = $1 AND api_key = $2`, userId, key)
+	if err != nil {
+		return fmt.Errorf("error removing revoked api key of user %v: %w", userId, err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// GetUserApiUsage returns the requests of the api keys of a user per route since the given time, truncated to the
+// given interval (hour or day) and ordered by time
+func GetUserApiUsage(userId uint64, since time.Time, interval string) ([]*types.ApiUsage, error) {
+	if interval != "hour" && interval != "day" {
+		return nil, fmt.Errorf("invalid interval %v", interval)
+	}
+
+	usage := []*types.ApiUsage{}
+	err := FrontendReaderDB.Select(&usage, `
+		SELECT DATE_TRUNC($3, s.ts) AS ts, s.apikey, s.endpoint, SUM(s.count) AS count, SUM(s.blocked) AS blocked
+		FROM api_statistics s
+		INNER JOIN api_keys k ON k.api_key = s.apikey
+		WHERE k.user_id = $1 AND s.ts >= $2
+		GROUP BY 1, 2, 3
+		ORDER BY 1, 2, 3`, userId, since, interval)
+	if err != nil {
+		return nil, fmt.Errorf("error getting api usage of user %v: %w", userId, err)
+	}
+	return usage, nil
+}
+
+// GetUserApiRequestsSince returns the number of requests of the api keys of a user in a bucket since the given time
+func GetUserApiRequestsSince(userId uint64, bucket string, since time.Time) (int64, error) {
+	var count int64
+	err := FrontendReaderDB.Get(&count, `
+		SELECT COALESCE(SUM(s.count), 0)
+		FROM api_statistics s
+		INNER JOIN api_keys k ON k.api_key = s.apikey
+		WHERE k.user_id = $1 AND s.bucket = $2 AND s.ts >= $3`, userId, bucket, since)
+	if err != nil {
+		return 0, fmt.Errorf("error getting api requests of user %v in bucket %v since %v: %w", userId, bucket, since, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// GetPostgresMissingEpochs returns the epochs of the range that have no row in the epochs table or less block rows
+// than slots per epoch
+func GetPostgresMissingEpochs(startEpoch, endEpoch uint64) ([]uint64, error) {
+	var epochs []uint64
+	err := ReaderDb.Select(&epochs, `
+		SELECT e.epoch
+		FROM generate_series($1::INT, $2::INT) AS e(epoch)
+		LEFT JOIN epochs ON epochs.epoch = e.epoch
+		LEFT JOIN (
+			SELECT epoch, COUNT(*) AS slots
+			FROM blocks
+			WHERE epoch BETWEEN $1 AND $2
+			GROUP BY epoch
+		) b ON b.epoch = e.epoch
+		WHERE epochs.epoch IS NULL OR COALESCE(b.slots, 0) < $3
+		ORDER BY e.epoch`, startEpoch, endEpoch, utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting missing epochs %v - %v from postgres: %w", startEpoch, endEpoch, err)
+	}
+	return epochs, nil
+}
+
+// GetClickhouseMissingEpochs returns the epochs of the range that have no validator data in clickhouse
+func GetClickhouseMissingEpochs(startEpoch, endEpoch uint64) ([]uint64, error) {
+	var present []uint64
+	err := ClickhouseReaderDb.Select(&present, `
+		SELECT DISTINCT epoch
+		FROM validator_dashboard_data_epoch
+		WHERE epoch_timestamp >= ? AND epoch_timestamp <= ?`, utils.EpochToTime(startEpoch), utils.EpochToTime(endEpoch))
+	if err != nil {
+		return nil, fmt.Errorf("error getting epochs %v - %v from clickhouse: %w", startEpoch, endEpoch, err)
+	}
+
+	presentMap := make(map[uint64]bool, len(present))
+	for _, epoch := range present {
+		presentMap[epoch] = true
+	}
+	missing := []uint64{}
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		if !presentMap[epoch] {
+			missing = append(missing, epoch)
+		}
+	}
+	return missing, nil
+}
+
+// GetUnfinishedBackfillJob returns the id of the latest unfinished backfill job of the range, it returns 0 if there is none
+func GetUnfinishedBackfillJob(startEpoch, endEpoch uint64) (uint64, error) {
+	var id uint64
+	err := WriterDb.Get(&id, `
+		SELECT id
+		FROM backfill_jobs
+		WHERE start_epoch = $1 AND end_epoch = $2 AND finished_ts IS NULL
+		ORDER BY id DESC
+		LIMIT 1`, startEpoch, endEpoch)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error getting unfinished backfill job of epochs %v - %v: %w", startEpoch, endEpoch, err)
+	}
+	return id, nil
+}
+
+// CreateBackfillJob saves a new backfill job of the range with its planned units and returns the id of the job
+func CreateBackfillJob(startEpoch, endEpoch uint64, units []*types.BackfillUnit) (uint64, error) {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id uint64
+	err = tx.Get(&id, `INSERT INTO backfill_jobs (start_epoch, end_epoch) VALUES ($1, $2) RETURNING id`, startEpoch, endEpoch)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting backfill job: %w", err)
+	}
+
+	batchSize := 1000
+	for b := 0; b < len(units); b += batchSize {
+		start := b
+		end := b + batchSize
+		if len(units) < end {
+			end = len(units)
+		}
+
+		valueStrings := make([]string, 0, end-start)
+		valueArgs := make([]interface{}, 0, (end-start)*5)
+		for i, u := range units[start:end] {
+			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", i*5+1, i*5+2, i*5+3, i*5+4, i*5+5))
+			valueArgs = append(valueArgs, id, u.StartEpoch, u.EndEpoch, u.StartEpoch, pq.Array(u.Stores))
+		}
+
+		_, err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO backfill_units (job_id, start_epoch, end_epoch, next_epoch, stores)
+			VALUES %s`, strings.Join(valueStrings, ",")), valueArgs...)
+		if err != nil {
+			return 0, fmt.Errorf("error inserting backfill units: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("error committing backfill job: %w", err)
+	}
+	for _, u := range units {
+		u.JobID = id
+		u.NextEpoch = u.StartEpoch
+		u.Status = "pending"
+	}
+	return id, nil
+}
+
+// GetBackfillUnits returns the units of the backfill job that are not done yet
+func GetBackfillUnits(jobID uint64) ([]*types.BackfillUnit, error) {
+	var units []*types.BackfillUnit
+	err := WriterDb.Select(&units, `
+		SELECT job_id, start_epoch, end_epoch, next_epoch, stores, status, attempts, error
+		FROM backfill_units
+		WHERE job_id = $1 AND status != 'done'
+		ORDER BY start_epoch`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting units of backfill job %v: %w", jobID, err)
+	}
+	return units, nil
+}
+
+// UpdateBackfillUnitProgress saves the next epoch of the unit that has to be exported
+func UpdateBackfillUnitProgress(unit *types.BackfillUnit) error {
+	_, err := WriterDb.Exec(`
+		UPDATE backfill_units SET next_epoch = $3, updated_ts = NOW()
+		WHERE job_id = $1 AND start_epoch = $2`, unit.JobID, unit.StartEpoch, unit.NextEpoch)
+	if err != nil {
+		return fmt.Errorf("error updating progress of backfill unit %v - %v: %w", unit.StartEpoch, unit.EndEpoch, err)
+	}
+	return nil
+}
+
+// UpdateBackfillUnitStatus saves the status of the unit after an attempt to export it
+func UpdateBackfillUnitStatus(unit *types.BackfillUnit) error {
+	_, err := WriterDb.Exec(`
+		UPDATE backfill_units SET status = $3, attempts = attempts + 1, error = $4, updated_ts = NOW()
+		WHERE job_id = $1 AND start_epoch = $2`, unit.JobID, unit.StartEpoch, unit.Status, unit.Error)
+	if err != nil {
+		return fmt.Errorf("error updating status of backfill unit %v - %v: %w", unit.StartEpoch, unit.EndEpoch, err)
+	}
+	return nil
+}
+
+// FinishBackfillJob marks the backfill job as finished so that it is not resumed again
+func FinishBackfillJob(jobID uint64) error {
+	_, err := WriterDb.Exec(`UPDATE backfill_jobs SET finished_ts = NOW() WHERE id = $1`, jobID)
+	if err != nil {
+		return fmt.Errorf("error finishing backfill job %v: %w", jobID, err)
+	}
+	return nil
+}
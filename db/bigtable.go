@@ -694,6 +694,90 @@ func (bigtable *Bigtable) SaveAttestationDuties(duties map[types.Slot]map[types.
 	return nil
 }
 
+// DeleteAttestationInclusions removes the inclusions of the given attestations in the block at the inclusion slot,
+// it is used to roll back the attestations of orphaned blocks. Attestations without any other inclusion fall back to
+// the missed attestation written when the duties of the epoch were exported.
+func (bigtable *Bigtable) DeleteAttestationInclusions(inclusionSlot uint64, attestations map[types.Slot][]types.ValidatorIndex) error {
+	start := time.Now()
+
+	inclusionTs := gcp_bigtable.Time(utils.SlotToTime(inclusionSlot))
+	muts := types.NewBulkMutations(MAX_BATCH_MUTATIONS)
+	for attestedSlot, validators := range attestations {
+		epoch := utils.EpochOfSlot(uint64(attestedSlot))
+		for _, validator := range validators {
+			key := fmt.Sprintf("%s:%s:%s:%s", bigtable.chainId, bigtable.validatorIndexToKey(uint64(validator)), ATTESTATIONS_FAMILY, bigtable.reversedPaddedEpoch(epoch))
+
+			mut := gcp_bigtable.NewMutation()
+			mut.DeleteTimestampRange(ATTESTATIONS_FAMILY, fmt.Sprintf("%d", attestedSlot), inclusionTs, inclusionTs+1000)
+			muts.Add(key, mut)
+		}
+	}
+
+	err := bigtable.WriteBulk(muts, bigtable.tableValidatorsHistory, MAX_BATCH_MUTATIONS)
+	if err != nil {
+		return fmt.Errorf("error deleting attestation inclusions of slot %v: %w", inclusionSlot, err)
+	}
+
+	logger.Infof("deleted %v attestation inclusions of slot %v from bigtable in %v", muts.Len(), inclusionSlot, time.Since(start))
+	return nil
+}
+
+// DeleteValidatorBalancesAndIncome removes the balances and income details of all validators for the given epochs
+// together with the highest active index and the income sum of the epochs, it is used to roll back epochs that
+// have been exported from orphaned blocks before they are exported again
+func (bigtable *Bigtable) DeleteValidatorBalancesAndIncome(epochs []uint64) error {
+	if len(epochs) == 0 {
+		return nil
+	}
+	start := time.Now()
+
+	startEpoch, endEpoch := epochs[0], epochs[0]
+	for _, epoch := range epochs {
+		startEpoch = min(startEpoch, epoch)
+		endEpoch = max(endEpoch, epoch)
+	}
+	highestActiveIndices, err := bigtable.GetMaxValidatorindexForEpochs(startEpoch, endEpoch)
+	if err != nil {
+		return fmt.Errorf("error retrieving highest active validator indices of epochs %v: %w", epochs, err)
+	}
+
+	muts := types.NewBulkMutations(MAX_BATCH_MUTATIONS)
+	for _, epoch := range epochs {
+		epochKey := bigtable.reversedPaddedEpoch(epoch)
+
+		highestActiveIndex, ok := highestActiveIndices[epoch]
+		if ok {
+			for i := uint64(0); i <= highestActiveIndex; i++ {
+				validatorKey := bigtable.validatorIndexToKey(i)
+
+				mut := gcp_bigtable.NewMutation()
+				mut.DeleteRow()
+				muts.Add(fmt.Sprintf("%s:%s:%s:%s", bigtable.chainId, validatorKey, VALIDATOR_BALANCES_FAMILY, epochKey), mut)
+
+				mut = gcp_bigtable.NewMutation()
+				mut.DeleteRow()
+				muts.Add(fmt.Sprintf("%s:%s:%s:%s", bigtable.chainId, validatorKey, INCOME_DETAILS_COLUMN_FAMILY, epochKey), mut)
+			}
+		}
+
+		mut := gcp_bigtable.NewMutation()
+		mut.DeleteRow()
+		muts.Add(fmt.Sprintf("%s:%s:%s", bigtable.chainId, VALIDATOR_HIGHEST_ACTIVE_INDEX_FAMILY, epochKey), mut)
+
+		mut = gcp_bigtable.NewMutation()
+		mut.DeleteRow()
+		muts.Add(fmt.Sprintf("%s:%s:%s", bigtable.chainId, SUM_COLUMN, epochKey), mut)
+	}
+
+	err = bigtable.WriteBulk(muts, bigtable.tableValidatorsHistory, MAX_BATCH_MUTATIONS)
+	if err != nil {
+		return fmt.Errorf("error deleting validator balances and income of epochs %v: %w", epochs, err)
+	}
+
+	logger.Infof("deleted validator balances and income of epochs %v from bigtable in %v", epochs, time.Since(start))
+	return nil
+}
+
 // This method is only to be used for migrating the last attestation slot to bigtable and should not be used for any other purpose
 func (bigtable *Bigtable) SetLastAttestationSlot(validator uint64, lastAttestationSlot uint64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
@@ -776,6 +860,48 @@ func (bigtable *Bigtable) getMaxValidatorindexForEpochV2(epoch uint64) (uint64,
 	return 0, nil
 }
 
+// GetMaxValidatorindexForEpochs returns the highest active validator index of every epoch of the range that has
+// validator history, the rows of all epochs are read with a single range scan
+func (bigtable *Bigtable) GetMaxValidatorindexForEpochs(startEpoch, endEpoch uint64) (map[uint64]uint64, error) {
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"startEpoch": startEpoch,
+			"endEpoch":   endEpoch,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Minute*5))
+	defer cancel()
+
+	// epochs are sorted descending, so start with the largest epoch and end with the smallest
+	// add \x00 to make the range inclusive
+	rangeStart := fmt.Sprintf("%s:%s:%s", bigtable.chainId, VALIDATOR_HIGHEST_ACTIVE_INDEX_FAMILY, bigtable.reversedPaddedEpoch(endEpoch))
+	rangeEnd := fmt.Sprintf("%s:%s:%s%s", bigtable.chainId, VALIDATOR_HIGHEST_ACTIVE_INDEX_FAMILY, bigtable.reversedPaddedEpoch(startEpoch), "\x00")
+
+	res := make(map[uint64]uint64, endEpoch-startEpoch+1)
+	var parseErr error
+	err := bigtable.tableValidatorsHistory.ReadRows(ctx, gcp_bigtable.NewRange(rangeStart, rangeEnd), func(r gcp_bigtable.Row) bool {
+		keySplit := strings.Split(r.Key(), ":")
+		epoch, err := strconv.ParseUint(keySplit[len(keySplit)-1], 10, 64)
+		if err != nil {
+			parseErr = fmt.Errorf("error parsing epoch from row key %v: %w", r.Key(), err)
+			return false
+		}
+		for _, ri := range r[VALIDATOR_HIGHEST_ACTIVE_INDEX_FAMILY] {
+			res[MAX_EPOCH-epoch] = binary.LittleEndian.Uint64(ri.Value)
+		}
+		return true
+	}, gcp_bigtable.RowFilter(gcp_bigtable.FamilyFilter(VALIDATOR_HIGHEST_ACTIVE_INDEX_FAMILY)))
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return res, nil
+}
+
 // Clickhouse port: Done
 func (bigtable *Bigtable) GetValidatorBalanceHistory(validators []uint64, startEpoch uint64, endEpoch uint64) (map[uint64][]*types.ValidatorBalance, error) {
 	// Disable balance fetching from clickhouse until we have a compatible data set available
@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html/template"
 	"log"
+	"math"
 	"math/big"
 	"sort"
 	"strconv"
@@ -19,6 +21,7 @@ import (
 	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
 	"github.com/gobitfly/eth2-beaconchain-explorer/erc1155"
 	"github.com/gobitfly/eth2-beaconchain-explorer/erc20"
+	"github.com/gobitfly/eth2-beaconchain-explorer/erc4337"
 	"github.com/gobitfly/eth2-beaconchain-explorer/erc721"
 	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
 	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
@@ -41,11 +44,13 @@ import (
 
 const (
 	ECR20TokensPerAddressLimit    = uint64(200) // when changing this, you will have to update the swagger docu for func ApiEth1Address too
+	ApprovalsPerAddressLimit      = 200         // when changing this, you will have to update the swagger docu for func ApiEth1AddressApprovals too
 	digitLimitInAddressPagesTable = 17
 	nameLimitInAddressPagesTable  = 0
 )
 
 var ErrBlockNotFound = errors.New("block not found")
+var ErrInvalidPageToken = errors.New("invalid page token")
 
 type IndexFilter string
 
@@ -58,6 +63,8 @@ const (
 	FILTER_METHOD         IndexFilter = "METHOD"
 	FILTER_CONTRACT       IndexFilter = "CONTRACT"
 	FILTER_ERROR          IndexFilter = "ERROR"
+	FILTER_AUTHORITY      IndexFilter = "AUTHORITY"
+	FILTER_PAYMASTER      IndexFilter = "PAYMASTER"
 )
 
 const (
@@ -79,6 +86,7 @@ const (
 const (
 	ACCOUNT_COLUMN_NAME = "NAME"
 	ACCOUNT_IS_CONTRACT = "ISCONTRACT"
+	ACCOUNT_PROXY       = "PROXY"
 
 	CONTRACT_NAME = "CONTRACTNAME"
 	CONTRACT_ABI  = "ABI"
@@ -1235,6 +1243,109 @@ func (bigtable *Bigtable) TransformBlobTx(blk *types.Eth1Block, cache *freecache
 	return bulkData, bulkMetadataUpdates, nil
 }
 
+// TransformSetCodeAuthorizations accepts an eth1 block and creates bigtable mutations for the authorizations of
+// EIP-7702 set-code transactions.
+// It writes the authorizations to the table data:
+// Row:    <chainID>:AUTH:<txHash>:<paddedAuthorizationIndex>
+// Family: f
+// Column: data
+// Cell:   Proto<Eth1SetCodeAuthorizationIndexed>
+//
+// It indexes the authorizations by:
+// Row:    <chainID>:I:AUTH:<AUTHORITY_ADDRESS>:TIME:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<paddedAuthorizationIndex>
+// Family: f
+// Column: <chainID>:AUTH:<txHash>:<paddedAuthorizationIndex>
+// Cell:   nil
+//
+// Row:    <chainID>:I:AUTH:<DELEGATE_ADDRESS>:TIME:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<paddedAuthorizationIndex>
+// Family: f
+// Column: <chainID>:AUTH:<txHash>:<paddedAuthorizationIndex>
+// Cell:   nil
+//
+// Row:    <chainID>:I:AUTH:<AUTHORITY_ADDRESS>:AUTHORITY:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<paddedAuthorizationIndex>
+// Family: f
+// Column: <chainID>:AUTH:<txHash>:<paddedAuthorizationIndex>
+// Cell:   nil
+func (bigtable *Bigtable) TransformSetCodeAuthorizations(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
+	startTime := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("bt_transform_set_code_authorizations").Observe(time.Since(startTime).Seconds())
+	}()
+
+	bulkData = &types.BulkMutations{}
+	bulkMetadataUpdates = &types.BulkMutations{}
+
+	chainId := new(big.Int).SetUint64(utils.Config.Chain.ClConfig.DepositChainID)
+	for i, tx := range blk.GetTransactions() {
+		if i >= TX_PER_BLOCK_LIMIT {
+			return nil, nil, fmt.Errorf("unexpected number of transactions in block expected at most %d but got: %v, tx: %x", TX_PER_BLOCK_LIMIT-1, i, tx.GetHash())
+		}
+		if len(tx.GetAuthorizationList()) == 0 {
+			continue
+		}
+		iReversed := reversePaddedIndex(i, TX_PER_BLOCK_LIMIT)
+
+		for j, auth := range tx.GetAuthorizationList() {
+			// authorizations share the index layout of internal transactions so that the paging of the address tables works for them as well
+			if j >= ITX_PER_TX_LIMIT {
+				return nil, nil, fmt.Errorf("unexpected number of authorizations in tx expected at most %d but got: %v, tx: %x", ITX_PER_TX_LIMIT-1, j, tx.GetHash())
+			}
+			jReversed := reversePaddedIndex(j, ITX_PER_TX_LIMIT)
+
+			indexedAuth := &types.Eth1SetCodeAuthorizationIndexed{
+				ParentHash:  tx.GetHash(),
+				BlockNumber: blk.GetNumber(),
+				Time:        blk.GetTime(),
+				Authority:   auth.GetAuthority(),
+				Address:     auth.GetAddress(),
+				ChainId:     auth.GetChainId(),
+				Nonce:       auth.GetNonce(),
+				Valid:       isValidSetCodeAuthorization(auth, chainId),
+			}
+
+			b, err := proto.Marshal(indexedAuth)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			key := fmt.Sprintf("%s:AUTH:%x:%s", bigtable.chainId, tx.GetHash(), jReversed)
+			mut := gcp_bigtable.NewMutation()
+			mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
+
+			bulkData.Keys = append(bulkData.Keys, key)
+			bulkData.Muts = append(bulkData.Muts, mut)
+
+			indexes := []string{
+				fmt.Sprintf("%s:I:AUTH:%x:TIME:%s:%s:%s", bigtable.chainId, auth.GetAddress(), reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+			}
+			// authorizations with an invalid signature have no authority
+			if len(auth.GetAuthority()) > 0 {
+				indexes = append(indexes,
+					fmt.Sprintf("%s:I:AUTH:%x:TIME:%s:%s:%s", bigtable.chainId, auth.GetAuthority(), reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:AUTH:%x:%s:%s:%s:%s", bigtable.chainId, auth.GetAuthority(), FILTER_AUTHORITY, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+				)
+			}
+
+			for _, idx := range indexes {
+				mut := gcp_bigtable.NewMutation()
+				mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+
+				bulkData.Keys = append(bulkData.Keys, idx)
+				bulkData.Muts = append(bulkData.Muts, mut)
+			}
+		}
+	}
+
+	return bulkData, bulkMetadataUpdates, nil
+}
+
+// isValidSetCodeAuthorization checks the signature, chain id and nonce bound of an authorization. Whether the nonce
+// matches the nonce of the authority is not checked, so a valid authorization was not necessarily applied.
+func isValidSetCodeAuthorization(auth *types.Eth1SetCodeAuthorization, chainId *big.Int) bool {
+	authChainId := new(big.Int).SetBytes(auth.GetChainId())
+	return len(auth.GetAuthority()) > 0 && (authChainId.Sign() == 0 || authChainId.Cmp(chainId) == 0) && auth.GetNonce() < math.MaxUint64
+}
+
 // custom timestamp
 func encodeIsContractUpdateTs(block_number, tx_idx, trace_idx uint64) (gcp_bigtable.Timestamp, error) {
 	var res uint64
@@ -1273,6 +1384,27 @@ func decodeIsContractUpdateTs(ts gcp_bigtable.Timestamp) (block_number, tx_idx,
 	return block_number, tx_idx, trace_idx
 }
 
+// TransformContract accepts an eth1 block and writes the contract state and the proxy implementation updates of the
+// created, destroyed and upgraded contracts to the metadata table. Both are versioned by the position of the update in
+// the chain (see encodeIsContractUpdateTs):
+// Row:    <chainID>:S:<ADDRESS>
+// Family: a
+// Column: ISCONTRACT
+// Cell:   Proto<IsContractUpdate>
+//
+// Row:    <chainID>:S:<ADDRESS>
+// Family: a
+// Column: PROXY
+// Cell:   Proto<Eth1ProxyImplementation>
+//
+// Reading the proxy implementation needs the state of the block, so created contracts and Upgraded and BeaconUpgraded
+// events are only queued for ImportProxyUpdates in the table data, the cell holds the implementation or beacon of the event:
+// Row:    <chainID>:PROXY:V:<paddedBlockNumber>:<paddedTxIndex>:<paddedIndex>:<ADDRESS>
+// Family: f
+// Column: data
+// Cell:   Proto<Eth1ProxyImplementation>
+// Candidates of blocks whose state could not be read are parked by ImportProxyUpdates in rows with the prefix
+// <chainID>:PROXY:P: and retried once the queue is empty.
 func (bigtable *Bigtable) TransformContract(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
 	startTime := time.Now()
 	defer func() {
@@ -1283,6 +1415,16 @@ func (bigtable *Bigtable) TransformContract(blk *types.Eth1Block, cache *freecac
 	bulkMetadataUpdates = &types.BulkMutations{}
 	contractUpdateWrites := &types.BulkMutations{}
 
+	type proxyUpdate struct {
+		address []byte
+		txIdx   int
+		idx     int
+		update  *types.Eth1ProxyImplementation
+	}
+	createdContracts := make(map[string]bool)
+	createdUpdates := make([]*proxyUpdate, 0)
+	eventUpdates := make([]*proxyUpdate, 0)
+
 	for i, tx := range blk.GetTransactions() {
 		if i >= TX_PER_BLOCK_LIMIT {
 			return nil, nil, fmt.Errorf("unexpected number of transactions in block expected at most %d but got: %v, tx: %x", TX_PER_BLOCK_LIMIT-1, i, tx.GetHash())
@@ -1317,8 +1459,67 @@ func (bigtable *Bigtable) TransformContract(blk *types.Eth1Block, cache *freecac
 					contractUpdateWrites.Keys = append(contractUpdateWrites.Keys, fmt.Sprintf("%s:S:%x", bigtable.chainId, address))
 					contractUpdateWrites.Muts = append(contractUpdateWrites.Muts, mutWrite)
 				}
+
+				if contractUpdate.IsContract && contractUpdate.Success && !createdContracts[string(address)] {
+					createdContracts[string(address)] = true
+					createdUpdates = append(createdUpdates, &proxyUpdate{address: address, txIdx: i, idx: j})
+				}
 			}
 		}
+
+		if tx.GetStatus() != 1 {
+			continue
+		}
+		for j, log := range tx.GetLogs() {
+			if j >= ITX_PER_TX_LIMIT {
+				return nil, nil, fmt.Errorf("unexpected number of logs in block expected at most %d but got: %v tx: %x", ITX_PER_TX_LIMIT-1, j, tx.GetHash())
+			}
+			if len(log.GetTopics()) < 2 || log.GetRemoved() {
+				continue
+			}
+			update := &proxyUpdate{address: log.GetAddress(), txIdx: i, idx: j}
+			if bytes.Equal(log.GetTopics()[0], utils.ProxyUpgradedTopic) {
+				update.update = &types.Eth1ProxyImplementation{
+					Type:           types.ProxyType_EIP1967_PROXY,
+					Implementation: common.BytesToAddress(log.GetTopics()[1]).Bytes(),
+				}
+			} else if bytes.Equal(log.GetTopics()[0], utils.ProxyBeaconUpgradedTopic) {
+				update.update = &types.Eth1ProxyImplementation{
+					Type:   types.ProxyType_BEACON_PROXY,
+					Beacon: common.BytesToAddress(log.GetTopics()[1]).Bytes(),
+				}
+			} else {
+				continue
+			}
+			update.update.ParentHash = tx.GetHash()
+			eventUpdates = append(eventUpdates, update)
+		}
+	}
+
+	// the storage slots of contracts created in this block already contain all upgrades of the block
+	proxyUpdates := createdUpdates
+	for _, update := range eventUpdates {
+		if !createdContracts[string(update.address)] {
+			proxyUpdates = append(proxyUpdates, update)
+		}
+	}
+
+	for _, update := range proxyUpdates {
+		candidate := update.update
+		if candidate == nil {
+			candidate = &types.Eth1ProxyImplementation{ParentHash: blk.GetTransactions()[update.txIdx].GetHash()}
+		}
+		candidate.BlockNumber = blk.GetNumber()
+		candidate.Time = blk.GetTime()
+		b, err := proto.Marshal(candidate)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		mut := gcp_bigtable.NewMutation()
+		mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
+		bulkData.Keys = append(bulkData.Keys, proxyCandidateKey(bigtable.chainId, blk.GetNumber(), update.txIdx, update.idx, update.address))
+		bulkData.Muts = append(bulkData.Muts, mut)
 	}
 
 	err = bigtable.WriteBulk(contractUpdateWrites, bigtable.tableMetadata, DEFAULT_BATCH_INSERTS)
@@ -1764,6 +1965,341 @@ func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache
 	return bulkData, bulkMetadataUpdates, nil
 }
 
+// TransformApprovals accepts an eth1 block and creates bigtable mutations for erc20 / erc721 Approval and ApprovalForAll events.
+// It transforms the logs contained within a block and writes the transformed logs to bigtable
+// It writes approval events to the table data:
+// Row:    <chainID>:APPROVAL:<txHash>:<paddedLogIndex>
+// Family: f
+// Column: data
+// Cell:   Proto<Eth1ApprovalIndexed>
+//
+// It indexes approval events by:
+// Row:    <chainID>:I:APPROVAL:<OWNER_ADDRESS>:TIME:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<PaddedLogIndex>
+// Family: f
+// Column: <chainID>:APPROVAL:<txHash>:<paddedLogIndex>
+// Cell:   nil
+//
+// Row:    <chainID>:I:APPROVAL:<SPENDER_ADDRESS>:TIME:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<PaddedLogIndex>
+// Family: f
+// Column: <chainID>:APPROVAL:<txHash>:<paddedLogIndex>
+// Cell:   nil
+//
+// Row:    <chainID>:I:APPROVAL:<TOKEN_ADDRESS>:ALL:TIME:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<PaddedLogIndex>
+// Family: f
+// Column: <chainID>:APPROVAL:<txHash>:<paddedLogIndex>
+// Cell:   nil
+//
+// It keeps the latest approval state of an owner in the table metadata, the cell timestamp encodes the position of the event in the chain:
+// Row:    <chainID>:APPROVAL:<OWNER_ADDRESS>
+// Family: a
+// Column: ERC20:<TOKEN_ADDRESS>:<SPENDER_ADDRESS> | ERC721:<TOKEN_ADDRESS>:<TOKEN_ID> | ALL:<TOKEN_ADDRESS>:<OPERATOR_ADDRESS>
+// Cell:   Proto<Eth1ApprovalIndexed>
+// The cells of revoked approvals and of approvals that are no longer active on chain are deleted by GetAddressActiveApprovals.
+func (bigtable *Bigtable) TransformApprovals(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
+	startTime := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("bt_transform_approvals").Observe(time.Since(startTime).Seconds())
+	}()
+
+	bulkData = &types.BulkMutations{}
+	bulkMetadataUpdates = &types.BulkMutations{}
+	approvalStateWrites := &types.BulkMutations{}
+
+	erc20Filterer, err := erc20.NewErc20Filterer(common.Address{}, nil)
+	if err != nil {
+		log.Printf("error creating filterer: %v", err)
+	}
+	erc721Filterer, err := erc721.NewErc721Filterer(common.Address{}, nil)
+	if err != nil {
+		log.Printf("error creating filterer: %v", err)
+	}
+
+	for i, tx := range blk.GetTransactions() {
+		if i >= TX_PER_BLOCK_LIMIT {
+			return nil, nil, fmt.Errorf("unexpected number of transactions in block expected at most %d but got: %v, tx: %x", TX_PER_BLOCK_LIMIT-1, i, tx.GetHash())
+		}
+		iReversed := reversePaddedIndex(i, TX_PER_BLOCK_LIMIT)
+		for j, log := range tx.GetLogs() {
+			if j >= ITX_PER_TX_LIMIT {
+				return nil, nil, fmt.Errorf("unexpected number of logs in block expected at most %d but got: %v tx: %x", ITX_PER_TX_LIMIT-1, j, tx.GetHash())
+			}
+			if len(log.GetTopics()) < 3 || (!bytes.Equal(log.GetTopics()[0], erc20.ApprovalTopic) && !bytes.Equal(log.GetTopics()[0], erc721.ApprovalForAllTopic)) {
+				continue
+			}
+			jReversed := reversePaddedIndex(j, ITX_PER_TX_LIMIT)
+
+			topics := make([]common.Hash, 0, len(log.GetTopics()))
+
+			for _, lTopic := range log.GetTopics() {
+				topics = append(topics, common.BytesToHash(lTopic))
+			}
+
+			ethLog := eth_types.Log{
+				Address:     common.BytesToAddress(log.GetAddress()),
+				Data:        log.Data,
+				Topics:      topics,
+				BlockNumber: blk.GetNumber(),
+				TxHash:      common.BytesToHash(tx.GetHash()),
+				TxIndex:     uint(i),
+				BlockHash:   common.BytesToHash(blk.GetHash()),
+				Index:       uint(j),
+				Removed:     log.GetRemoved(),
+			}
+
+			indexedLog := parseApprovalLog(erc20Filterer, erc721Filterer, ethLog)
+			if indexedLog == nil {
+				continue
+			}
+			indexedLog.ParentHash = tx.GetHash()
+			indexedLog.BlockNumber = blk.GetNumber()
+			indexedLog.Time = blk.GetTime()
+
+			key := fmt.Sprintf("%s:APPROVAL:%x:%s", bigtable.chainId, tx.GetHash(), jReversed)
+
+			b, err := proto.Marshal(indexedLog)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			mut := gcp_bigtable.NewMutation()
+			mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
+
+			bulkData.Keys = append(bulkData.Keys, key)
+			bulkData.Muts = append(bulkData.Muts, mut)
+
+			indexes := []string{
+				fmt.Sprintf("%s:I:APPROVAL:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.Owner, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+				fmt.Sprintf("%s:I:APPROVAL:%x:ALL:TIME:%s:%s:%s", bigtable.chainId, indexedLog.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+			}
+			if !bytes.Equal(indexedLog.Owner, indexedLog.Spender) {
+				indexes = append(indexes, fmt.Sprintf("%s:I:APPROVAL:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.Spender, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed))
+			}
+
+			for _, idx := range indexes {
+				mut := gcp_bigtable.NewMutation()
+				mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+
+				bulkData.Keys = append(bulkData.Keys, idx)
+				bulkData.Muts = append(bulkData.Muts, mut)
+			}
+
+			// the cell timestamp makes sure that the latest approval wins, independent of the order in which the blocks are indexed
+			ts, err := encodeIsContractUpdateTs(blk.GetNumber(), uint64(i), uint64(j))
+			if err != nil {
+				utils.LogError(err, "error generating bigtable approval timestamp", 0)
+				continue
+			}
+			// only the latest event of an approval is read, so older cells of the column are deleted
+			mutWrite := gcp_bigtable.NewMutation()
+			mutWrite.DeleteTimestampRange(ACCOUNT_METADATA_FAMILY, approvalStateColumn(indexedLog), 0, ts)
+			mutWrite.Set(ACCOUNT_METADATA_FAMILY, approvalStateColumn(indexedLog), ts, b)
+			approvalStateWrites.Keys = append(approvalStateWrites.Keys, fmt.Sprintf("%s:APPROVAL:%x", bigtable.chainId, indexedLog.Owner))
+			approvalStateWrites.Muts = append(approvalStateWrites.Muts, mutWrite)
+		}
+	}
+
+	err = bigtable.WriteBulk(approvalStateWrites, bigtable.tableMetadata, DEFAULT_BATCH_INSERTS)
+	return bulkData, bulkMetadataUpdates, err
+}
+
+// parseApprovalLog parses erc20 Approval, erc721 Approval and ApprovalForAll events, it returns nil if the log is none of them.
+// erc20 and erc721 Approval events share the same signature and are distinguished by the number of indexed topics.
+func parseApprovalLog(erc20Filterer *erc20.Erc20Filterer, erc721Filterer *erc721.Erc721Filterer, ethLog eth_types.Log) *types.Eth1ApprovalIndexed {
+	switch {
+	case len(ethLog.Topics) == 3 && bytes.Equal(ethLog.Topics[0].Bytes(), erc20.ApprovalTopic):
+		approval, _ := erc20Filterer.ParseApproval(ethLog)
+		if approval == nil {
+			return nil
+		}
+		value := []byte{}
+		if approval.Value != nil {
+			value = approval.Value.Bytes()
+		}
+		return &types.Eth1ApprovalIndexed{
+			TokenAddress: ethLog.Address.Bytes(),
+			Type:         types.ApprovalType_ERC20_APPROVAL,
+			Owner:        approval.Owner.Bytes(),
+			Spender:      approval.Spender.Bytes(),
+			Value:        value,
+		}
+	case len(ethLog.Topics) == 4 && bytes.Equal(ethLog.Topics[0].Bytes(), erc721.ApprovalTopic):
+		approval, _ := erc721Filterer.ParseApproval(ethLog)
+		if approval == nil {
+			return nil
+		}
+		tokenId := new(big.Int)
+		if approval.TokenId != nil {
+			tokenId = approval.TokenId
+		}
+		return &types.Eth1ApprovalIndexed{
+			TokenAddress: ethLog.Address.Bytes(),
+			Type:         types.ApprovalType_ERC721_APPROVAL,
+			Owner:        approval.Owner.Bytes(),
+			Spender:      approval.Approved.Bytes(),
+			TokenId:      tokenId.Bytes(),
+		}
+	case len(ethLog.Topics) == 3 && bytes.Equal(ethLog.Topics[0].Bytes(), erc721.ApprovalForAllTopic):
+		approval, _ := erc721Filterer.ParseApprovalForAll(ethLog)
+		if approval == nil {
+			return nil
+		}
+		return &types.Eth1ApprovalIndexed{
+			TokenAddress: ethLog.Address.Bytes(),
+			Type:         types.ApprovalType_APPROVAL_FOR_ALL,
+			Owner:        approval.Owner.Bytes(),
+			Spender:      approval.Operator.Bytes(),
+			Approved:     approval.Approved,
+		}
+	}
+	return nil
+}
+
+// approvalStateColumn returns the column of the latest state of the approval in the approval row of the owner
+func approvalStateColumn(approval *types.Eth1ApprovalIndexed) string {
+	switch approval.GetType() {
+	case types.ApprovalType_ERC721_APPROVAL:
+		return fmt.Sprintf("ERC721:%x:%x", approval.GetTokenAddress(), approval.GetTokenId())
+	case types.ApprovalType_APPROVAL_FOR_ALL:
+		return fmt.Sprintf("ALL:%x:%x", approval.GetTokenAddress(), approval.GetSpender())
+	default:
+		return fmt.Sprintf("ERC20:%x:%x", approval.GetTokenAddress(), approval.GetSpender())
+	}
+}
+
+// TransformUserOperations accepts an eth1 block and creates bigtable mutations for ERC-4337 user operations.
+// It decodes the UserOperationEvent logs of the EntryPoint contracts and, if the EntryPoint has been called directly, the handleOps calldata of the bundle
+// It writes user operations to the table data:
+// Row:    <chainID>:UOP:<txHash>:<paddedLogIndex>
+// Family: f
+// Column: data
+// Cell:   Proto<Eth1UserOperationIndexed>
+//
+// It indexes user operations by:
+// Row:    <chainID>:I:UOP:<SENDER_ADDRESS>:TIME:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<PaddedLogIndex>
+// Family: f
+// Column: <chainID>:UOP:<txHash>:<paddedLogIndex>
+// Cell:   nil
+//
+// Row:    <chainID>:I:UOP:<PAYMASTER_ADDRESS>:PAYMASTER:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<PaddedLogIndex>
+// Family: f
+// Column: <chainID>:UOP:<txHash>:<paddedLogIndex>
+// Cell:   nil
+func (bigtable *Bigtable) TransformUserOperations(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
+	startTime := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("bt_transform_user_operations").Observe(time.Since(startTime).Seconds())
+	}()
+
+	bulkData = &types.BulkMutations{}
+	bulkMetadataUpdates = &types.BulkMutations{}
+
+	for i, tx := range blk.GetTransactions() {
+		if i >= TX_PER_BLOCK_LIMIT {
+			return nil, nil, fmt.Errorf("unexpected number of transactions in block expected at most %d but got: %v, tx: %x", TX_PER_BLOCK_LIMIT-1, i, tx.GetHash())
+		}
+		iReversed := reversePaddedIndex(i, TX_PER_BLOCK_LIMIT)
+
+		userOps := userOperationsFromTx(tx)
+		for j := range tx.GetLogs() {
+			if j >= ITX_PER_TX_LIMIT {
+				return nil, nil, fmt.Errorf("unexpected number of logs in block expected at most %d but got: %v tx: %x", ITX_PER_TX_LIMIT-1, j, tx.GetHash())
+			}
+			indexedOp := userOps[j]
+			if indexedOp == nil {
+				continue
+			}
+			jReversed := reversePaddedIndex(j, ITX_PER_TX_LIMIT)
+
+			indexedOp.ParentHash = tx.GetHash()
+			indexedOp.BlockNumber = blk.GetNumber()
+			indexedOp.Time = blk.GetTime()
+
+			key := fmt.Sprintf("%s:UOP:%x:%s", bigtable.chainId, tx.GetHash(), jReversed)
+
+			b, err := proto.Marshal(indexedOp)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			mut := gcp_bigtable.NewMutation()
+			mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
+
+			bulkData.Keys = append(bulkData.Keys, key)
+			bulkData.Muts = append(bulkData.Muts, mut)
+
+			indexes := []string{
+				fmt.Sprintf("%s:I:UOP:%x:%s:%s:%s:%s", bigtable.chainId, indexedOp.GetSender(), FILTER_TIME, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+			}
+			if !bytes.Equal(indexedOp.GetPaymaster(), ZERO_ADDRESS) {
+				indexes = append(indexes, fmt.Sprintf("%s:I:UOP:%x:%s:%s:%s:%s", bigtable.chainId, indexedOp.GetPaymaster(), FILTER_PAYMASTER, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed))
+			}
+
+			for _, idx := range indexes {
+				mut := gcp_bigtable.NewMutation()
+				mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+
+				bulkData.Keys = append(bulkData.Keys, idx)
+				bulkData.Muts = append(bulkData.Muts, mut)
+			}
+		}
+	}
+
+	return bulkData, bulkMetadataUpdates, nil
+}
+
+// userOperationsFromTx returns the user operations of the UserOperationEvent logs of the EntryPoint contracts by log index.
+// The factory and the method id are taken from the matching operation of the handleOps calldata if the transaction calls the EntryPoint directly.
+func userOperationsFromTx(tx *types.Eth1Transaction) map[int]*types.Eth1UserOperationIndexed {
+	userOps := make(map[int]*types.Eth1UserOperationIndexed)
+	for j, log := range tx.GetLogs() {
+		if !erc4337.IsEntryPoint(log.GetAddress()) {
+			continue
+		}
+		event := erc4337.ParseUserOperationEvent(log.GetTopics(), log.GetData())
+		if event == nil {
+			continue
+		}
+		userOps[j] = &types.Eth1UserOperationIndexed{
+			UserOpHash:    event.UserOpHash.Bytes(),
+			EntryPoint:    log.GetAddress(),
+			Sender:        event.Sender.Bytes(),
+			Paymaster:     event.Paymaster.Bytes(),
+			Nonce:         event.Nonce.Bytes(),
+			Success:       event.Success,
+			ActualGasCost: event.ActualGasCost.Bytes(),
+			ActualGasUsed: event.ActualGasUsed.Bytes(),
+			Bundler:       tx.GetFrom(),
+		}
+	}
+	if len(userOps) == 0 || !erc4337.IsEntryPoint(tx.GetTo()) {
+		return userOps
+	}
+
+	ops, err := erc4337.ParseHandleOps(tx.GetData())
+	if err != nil {
+		// handleAggregatedOps and calls of other EntryPoint methods are only indexed from their events
+		return userOps
+	}
+	// the sender and the nonce identify the operation of an event in the calldata
+	opsBySenderNonce := make(map[string]erc4337.UserOperation, len(ops))
+	for _, op := range ops {
+		opsBySenderNonce[fmt.Sprintf("%x:%x", op.Sender.Bytes(), op.Nonce.Bytes())] = op
+	}
+	for _, userOp := range userOps {
+		op, ok := opsBySenderNonce[fmt.Sprintf("%x:%x", userOp.GetSender(), userOp.GetNonce())]
+		if !ok {
+			continue
+		}
+		if len(op.InitCode) >= 20 {
+			userOp.Factory = op.InitCode[:20]
+		}
+		if len(op.CallData) >= 4 {
+			userOp.MethodId = op.CallData[:4]
+		}
+	}
+	return userOps
+}
+
 // TransformERC1155 accepts an eth1 block and creates bigtable mutations for erc1155 transfer events.
 // Example: https://etherscan.io/tx/0xcffdd4b44ba9361a769a559c360293333d09efffeab79c36125bb4b20bd04270#eventlog
 // It transforms the logs contained within a block and writes the transformed logs to bigtable
@@ -2768,7 +3304,222 @@ func (bigtable *Bigtable) GetAddressBlocksMinedTableData(address string, pageTok
 	return data, nil
 }
 
-func (bigtable *Bigtable) GetEth1UnclesForAddress(prefix string, limit int64) ([]*types.Eth1UncleIndexed, string, error) {
+func (bigtable *Bigtable) GetEth1UnclesForAddress(prefix string, limit int64) ([]*types.Eth1UncleIndexed, string, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"prefix": prefix,
+			"limit":  limit,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	// add \x00 to the row range such that we skip the previous value
+	rowRange := gcp_bigtable.NewRange(prefix+"\x00", prefixSuccessor(prefix, 4))
+	data := make([]*types.Eth1UncleIndexed, 0, limit)
+	keys := make([]string, 0, limit)
+	indexes := make([]string, 0, limit)
+	keysMap := make(map[string]*types.Eth1UncleIndexed, limit)
+
+	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
+		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
+		indexes = append(indexes, row.Key())
+		return true
+	}, gcp_bigtable.LimitRows(limit))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(keys) == 0 {
+		return data, "", nil
+	}
+
+	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
+		b := &types.Eth1UncleIndexed{}
+		err := proto.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
+
+		if err != nil {
+			logrus.Fatalf("error parsing Eth1UncleIndexed data: %v", err)
+		}
+		keysMap[row.Key()] = b
+
+		return true
+	})
+	if err != nil {
+		logger.WithError(err).WithField("prefix", prefix).WithField("limit", limit).Errorf("error reading rows in bigtable_eth1 / GetEth1UnclesForAddress")
+		return nil, "", err
+	}
+
+	for _, key := range keys {
+		if d := keysMap[key]; d != nil {
+			data = append(data, d)
+		}
+	}
+
+	return data, indexes[len(indexes)-1], nil
+}
+
+func (bigtable *Bigtable) GetAddressUnclesMinedTableData(address string, pageToken string) (*types.DataTableResponse, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"address":   address,
+			"pageToken": pageToken,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	defaultPageToken := fmt.Sprintf("%s:I:U:%s:", bigtable.chainId, address)
+	if pageToken == "" {
+		pageToken = defaultPageToken
+	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
+		return nil, fmt.Errorf("invalid pageToken for function GetAddressUnclesMinedTableData: %s", pageToken)
+	}
+
+	uncles, lastKey, err := BigtableClient.GetEth1UnclesForAddress(pageToken, DefaultInfScrollRows)
+	if err != nil {
+		return nil, err
+	}
+
+	tableData := make([][]interface{}, len(uncles))
+	for i, u := range uncles {
+		tableData[i] = []interface{}{
+			utils.FormatBlockNumber(u.Number),
+			utils.FormatTimestamp(u.Time.AsTime().Unix()),
+			utils.FormatDifficulty(new(big.Int).SetBytes(u.Difficulty)),
+			utils.FormatAmount(new(big.Int).SetBytes(u.Reward), utils.Config.Frontend.ElCurrency, 6),
+		}
+	}
+
+	data := &types.DataTableResponse{
+		Data:        tableData,
+		PagingToken: lastKey,
+	}
+
+	return data, nil
+}
+
+func (bigtable *Bigtable) GetEth1BtxForAddress(prefix string, limit int64) ([]*types.Eth1BlobTransactionIndexed, string, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"prefix": prefix,
+			"limit":  limit,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	// add \x00 to the row range such that we skip the previous value
+	rowRange := gcp_bigtable.NewRange(prefix+"\x00", prefixSuccessor(prefix, 5))
+	data := make([]*types.Eth1BlobTransactionIndexed, 0, limit)
+	keys := make([]string, 0, limit)
+	indexes := make([]string, 0, limit)
+	keysMap := make(map[string]*types.Eth1BlobTransactionIndexed, limit)
+
+	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
+		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
+		indexes = append(indexes, row.Key())
+		return true
+	}, gcp_bigtable.LimitRows(limit))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(keys) == 0 {
+		return data, "", nil
+	}
+
+	indexes, keys = bigtable.rearrangeReversePaddedIndexZero(ctx, indexes, keys)
+
+	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
+		b := &types.Eth1BlobTransactionIndexed{}
+		err := proto.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
+		if err != nil {
+			logrus.Fatalf("error parsing Eth1BlobTransactionIndexed data: %v", err)
+		}
+		keysMap[row.Key()] = b
+		return true
+	})
+	if err != nil {
+		logger.WithError(err).WithField("prefix", prefix).WithField("limit", limit).Errorf("error reading rows in bigtable_eth1 / GetEth1BtxForAddress")
+		return nil, "", err
+	}
+
+	for _, key := range keys {
+		if d := keysMap[key]; d != nil {
+			data = append(data, d)
+		}
+	}
+
+	return data, skipBlockIfLastTxIndex(indexes[len(indexes)-1]), nil
+}
+
+func (bigtable *Bigtable) GetAddressBlobTableData(address []byte, pageToken string) (*types.DataTableResponse, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"address":   address,
+			"pageToken": pageToken,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	// defaults to most recent
+	defaultPageToken := fmt.Sprintf("%s:I:BTX:%x:%s:", bigtable.chainId, address, FILTER_TIME)
+	if pageToken == "" {
+		pageToken = defaultPageToken
+	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
+		return nil, fmt.Errorf("invalid pageToken for function GetAddressBlobTableData: %s", pageToken)
+	}
+
+	transactions, lastKey, err := bigtable.GetEth1BtxForAddress(pageToken, DefaultInfScrollRows)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	for _, t := range transactions {
+		names[string(t.From)] = ""
+		names[string(t.To)] = ""
+	}
+	names, _, err = BigtableClient.GetAddressesNamesArMetadata(&names, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tableData := make([][]interface{}, len(transactions))
+	for i, t := range transactions {
+
+		fromName := names[string(t.From)]
+		toName := names[string(t.To)]
+
+		tableData[i] = []interface{}{
+			utils.FormatTransactionHash(t.Hash, t.ErrorMsg == ""),
+			utils.FormatBlockNumber(t.BlockNumber),
+			utils.FormatTimestamp(t.Time.AsTime().Unix()),
+			utils.FormatAddressWithLimitsInAddressPageTable(address, t.From, fromName, false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
+			utils.FormatInOutSelf(address, t.From, t.To),
+			utils.FormatAddressWithLimitsInAddressPageTable(address, t.To, toName, false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
+			utils.FormatBytesAmount(t.BlobGasPrice, "GWei", 6),
+			utils.FormatBytesAmount(t.BlobTxFee, "ETH", 6),
+			len(t.BlobVersionedHashes),
+		}
+	}
+
+	data := &types.DataTableResponse{
+		Data:        tableData,
+		PagingToken: lastKey,
+	}
+
+	return data, nil
+}
+
+func (bigtable *Bigtable) GetEth1SetCodeAuthorizationsForAddress(prefix string, limit int64) ([]*types.Eth1SetCodeAuthorizationIndexed, string, error) {
 
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
 		logger.WithFields(logrus.Fields{
@@ -2782,11 +3533,11 @@ func (bigtable *Bigtable) GetEth1UnclesForAddress(prefix string, limit int64) ([
 	defer cancel()
 
 	// add \x00 to the row range such that we skip the previous value
-	rowRange := gcp_bigtable.NewRange(prefix+"\x00", prefixSuccessor(prefix, 4))
-	data := make([]*types.Eth1UncleIndexed, 0, limit)
+	rowRange := gcp_bigtable.NewRange(prefix+"\x00", prefixSuccessor(prefix, 5))
+	data := make([]*types.Eth1SetCodeAuthorizationIndexed, 0, limit)
 	keys := make([]string, 0, limit)
 	indexes := make([]string, 0, limit)
-	keysMap := make(map[string]*types.Eth1UncleIndexed, limit)
+	keysMap := make(map[string]*types.Eth1SetCodeAuthorizationIndexed, limit)
 
 	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
 		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
@@ -2796,24 +3547,23 @@ func (bigtable *Bigtable) GetEth1UnclesForAddress(prefix string, limit int64) ([
 	if err != nil {
 		return nil, "", err
 	}
-
 	if len(keys) == 0 {
 		return data, "", nil
 	}
 
+	indexes, keys = bigtable.rearrangeReversePaddedIndexZero(ctx, indexes, keys)
+
 	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
-		b := &types.Eth1UncleIndexed{}
+		b := &types.Eth1SetCodeAuthorizationIndexed{}
 		err := proto.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
-
 		if err != nil {
-			logrus.Fatalf("error parsing Eth1UncleIndexed data: %v", err)
+			logrus.Fatalf("error parsing Eth1SetCodeAuthorizationIndexed data: %v", err)
 		}
 		keysMap[row.Key()] = b
-
 		return true
 	})
 	if err != nil {
-		logger.WithError(err).WithField("prefix", prefix).WithField("limit", limit).Errorf("error reading rows in bigtable_eth1 / GetEth1UnclesForAddress")
+		logger.WithError(err).WithField("prefix", prefix).WithField("limit", limit).Errorf("error reading rows in bigtable_eth1 / GetEth1SetCodeAuthorizationsForAddress")
 		return nil, "", err
 	}
 
@@ -2823,10 +3573,23 @@ func (bigtable *Bigtable) GetEth1UnclesForAddress(prefix string, limit int64) ([
 		}
 	}
 
-	return data, indexes[len(indexes)-1], nil
+	return data, skipBlockIfLastTxIndex(indexes[len(indexes)-1]), nil
 }
 
-func (bigtable *Bigtable) GetAddressUnclesMinedTableData(address string, pageToken string) (*types.DataTableResponse, error) {
+// GetAddressSetCodeAuthorizations returns a page of the authorizations that were signed by the address or that delegate
+// to it, newest first, and the page token of the next page
+func (bigtable *Bigtable) GetAddressSetCodeAuthorizations(address []byte, pageToken string) ([]*types.Eth1SetCodeAuthorizationIndexed, string, error) {
+	// defaults to most recent
+	defaultPageToken := fmt.Sprintf("%s:I:AUTH:%x:%s:", bigtable.chainId, address, FILTER_TIME)
+	if pageToken == "" {
+		pageToken = defaultPageToken
+	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
+		return nil, "", fmt.Errorf("%w for function GetAddressSetCodeAuthorizations: %s", ErrInvalidPageToken, pageToken)
+	}
+	return bigtable.GetEth1SetCodeAuthorizationsForAddress(pageToken, DefaultInfScrollRows)
+}
+
+func (bigtable *Bigtable) GetAddressSetCodeAuthorizationsTableData(address []byte, pageToken string) (*types.DataTableResponse, error) {
 
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
 		logger.WithFields(logrus.Fields{
@@ -2836,25 +3599,37 @@ func (bigtable *Bigtable) GetAddressUnclesMinedTableData(address string, pageTok
 	})
 	defer tmr.Stop()
 
-	defaultPageToken := fmt.Sprintf("%s:I:U:%s:", bigtable.chainId, address)
-	if pageToken == "" {
-		pageToken = defaultPageToken
-	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
-		return nil, fmt.Errorf("invalid pageToken for function GetAddressUnclesMinedTableData: %s", pageToken)
+	authorizations, lastKey, err := bigtable.GetAddressSetCodeAuthorizations(address, pageToken)
+	if err != nil {
+		return nil, err
 	}
 
-	uncles, lastKey, err := BigtableClient.GetEth1UnclesForAddress(pageToken, DefaultInfScrollRows)
+	names := make(map[string]string)
+	for _, a := range authorizations {
+		names[string(a.Authority)] = ""
+		names[string(a.Address)] = ""
+	}
+	names, _, err = BigtableClient.GetAddressesNamesArMetadata(&names, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	tableData := make([][]interface{}, len(uncles))
-	for i, u := range uncles {
+	tableData := make([][]interface{}, len(authorizations))
+	for i, a := range authorizations {
+		authority := template.HTML("N/A")
+		if len(a.Authority) > 0 {
+			authority = utils.FormatAddressWithLimitsInAddressPageTable(address, a.Authority, names[string(a.Authority)], false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true)
+		}
 		tableData[i] = []interface{}{
-			utils.FormatBlockNumber(u.Number),
-			utils.FormatTimestamp(u.Time.AsTime().Unix()),
-			utils.FormatDifficulty(new(big.Int).SetBytes(u.Difficulty)),
-			utils.FormatAmount(new(big.Int).SetBytes(u.Reward), utils.Config.Frontend.ElCurrency, 6),
+			utils.FormatTransactionHash(a.ParentHash, true),
+			utils.FormatBlockNumber(a.BlockNumber),
+			utils.FormatTimestamp(a.Time.AsTime().Unix()),
+			authority,
+			utils.FormatInOutSelf(address, a.Authority, a.Address),
+			utils.FormatAddressWithLimitsInAddressPageTable(address, a.Address, names[string(a.Address)], false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
+			new(big.Int).SetBytes(a.ChainId),
+			a.Nonce,
+			utils.FormatSetCodeAuthorizationValidity(a.Valid),
 		}
 	}
 
@@ -2866,7 +3641,7 @@ func (bigtable *Bigtable) GetAddressUnclesMinedTableData(address string, pageTok
 	return data, nil
 }
 
-func (bigtable *Bigtable) GetEth1BtxForAddress(prefix string, limit int64) ([]*types.Eth1BlobTransactionIndexed, string, error) {
+func (bigtable *Bigtable) GetEth1UserOperationsForAddress(prefix string, limit int64) ([]*types.Eth1UserOperationIndexed, string, error) {
 
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
 		logger.WithFields(logrus.Fields{
@@ -2881,10 +3656,10 @@ func (bigtable *Bigtable) GetEth1BtxForAddress(prefix string, limit int64) ([]*t
 
 	// add \x00 to the row range such that we skip the previous value
 	rowRange := gcp_bigtable.NewRange(prefix+"\x00", prefixSuccessor(prefix, 5))
-	data := make([]*types.Eth1BlobTransactionIndexed, 0, limit)
+	data := make([]*types.Eth1UserOperationIndexed, 0, limit)
 	keys := make([]string, 0, limit)
 	indexes := make([]string, 0, limit)
-	keysMap := make(map[string]*types.Eth1BlobTransactionIndexed, limit)
+	keysMap := make(map[string]*types.Eth1UserOperationIndexed, limit)
 
 	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
 		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
@@ -2901,16 +3676,16 @@ func (bigtable *Bigtable) GetEth1BtxForAddress(prefix string, limit int64) ([]*t
 	indexes, keys = bigtable.rearrangeReversePaddedIndexZero(ctx, indexes, keys)
 
 	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
-		b := &types.Eth1BlobTransactionIndexed{}
+		b := &types.Eth1UserOperationIndexed{}
 		err := proto.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
 		if err != nil {
-			logrus.Fatalf("error parsing Eth1BlobTransactionIndexed data: %v", err)
+			logrus.Fatalf("error parsing Eth1UserOperationIndexed data: %v", err)
 		}
 		keysMap[row.Key()] = b
 		return true
 	})
 	if err != nil {
-		logger.WithError(err).WithField("prefix", prefix).WithField("limit", limit).Errorf("error reading rows in bigtable_eth1 / GetEth1BtxForAddress")
+		logger.WithError(err).WithField("prefix", prefix).WithField("limit", limit).Errorf("error reading rows in bigtable_eth1 / GetEth1UserOperationsForAddress")
 		return nil, "", err
 	}
 
@@ -2923,7 +3698,20 @@ func (bigtable *Bigtable) GetEth1BtxForAddress(prefix string, limit int64) ([]*t
 	return data, skipBlockIfLastTxIndex(indexes[len(indexes)-1]), nil
 }
 
-func (bigtable *Bigtable) GetAddressBlobTableData(address []byte, pageToken string) (*types.DataTableResponse, error) {
+// GetAddressUserOperations returns a page of the user operations of the smart account (FILTER_TIME) or of the paymaster
+// (FILTER_PAYMASTER), newest first, and the page token of the next page
+func (bigtable *Bigtable) GetAddressUserOperations(address []byte, filter IndexFilter, pageToken string) ([]*types.Eth1UserOperationIndexed, string, error) {
+	// defaults to most recent
+	defaultPageToken := fmt.Sprintf("%s:I:UOP:%x:%s:", bigtable.chainId, address, filter)
+	if pageToken == "" {
+		pageToken = defaultPageToken
+	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
+		return nil, "", fmt.Errorf("%w for function GetAddressUserOperations: %s", ErrInvalidPageToken, pageToken)
+	}
+	return bigtable.GetEth1UserOperationsForAddress(pageToken, DefaultInfScrollRows)
+}
+
+func (bigtable *Bigtable) GetAddressUserOperationsTableData(address []byte, pageToken string) (*types.DataTableResponse, error) {
 
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
 		logger.WithFields(logrus.Fields{
@@ -2933,45 +3721,40 @@ func (bigtable *Bigtable) GetAddressBlobTableData(address []byte, pageToken stri
 	})
 	defer tmr.Stop()
 
-	// defaults to most recent
-	defaultPageToken := fmt.Sprintf("%s:I:BTX:%x:%s:", bigtable.chainId, address, FILTER_TIME)
-	if pageToken == "" {
-		pageToken = defaultPageToken
-	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
-		return nil, fmt.Errorf("invalid pageToken for function GetAddressBlobTableData: %s", pageToken)
-	}
-
-	transactions, lastKey, err := bigtable.GetEth1BtxForAddress(pageToken, DefaultInfScrollRows)
+	userOps, lastKey, err := bigtable.GetAddressUserOperations(address, FILTER_TIME, pageToken)
 	if err != nil {
 		return nil, err
 	}
 
 	names := make(map[string]string)
-	for _, t := range transactions {
-		names[string(t.From)] = ""
-		names[string(t.To)] = ""
+	for _, op := range userOps {
+		names[string(op.Paymaster)] = ""
+		names[string(op.Bundler)] = ""
 	}
 	names, _, err = BigtableClient.GetAddressesNamesArMetadata(&names, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	tableData := make([][]interface{}, len(transactions))
-	for i, t := range transactions {
-
-		fromName := names[string(t.From)]
-		toName := names[string(t.To)]
-
+	tableData := make([][]interface{}, len(userOps))
+	for i, op := range userOps {
+		method := "N/A"
+		if len(op.MethodId) == 4 {
+			method = bigtable.GetMethodLabel(op.MethodId, types.CONTRACT_PRESENT)
+		}
+		paymaster := template.HTML("Self")
+		if !bytes.Equal(op.Paymaster, ZERO_ADDRESS) {
+			paymaster = utils.FormatAddressWithLimitsInAddressPageTable(address, op.Paymaster, names[string(op.Paymaster)], false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true)
+		}
 		tableData[i] = []interface{}{
-			utils.FormatTransactionHash(t.Hash, t.ErrorMsg == ""),
-			utils.FormatBlockNumber(t.BlockNumber),
-			utils.FormatTimestamp(t.Time.AsTime().Unix()),
-			utils.FormatAddressWithLimitsInAddressPageTable(address, t.From, fromName, false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
-			utils.FormatInOutSelf(address, t.From, t.To),
-			utils.FormatAddressWithLimitsInAddressPageTable(address, t.To, toName, false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
-			utils.FormatBytesAmount(t.BlobGasPrice, "GWei", 6),
-			utils.FormatBytesAmount(t.BlobTxFee, "ETH", 6),
-			len(t.BlobVersionedHashes),
+			utils.FormatTransactionHash(op.ParentHash, op.Success),
+			utils.FormatMethod(method),
+			utils.FormatBlockNumber(op.BlockNumber),
+			utils.FormatTimestamp(op.Time.AsTime().Unix()),
+			new(big.Int).SetBytes(op.Nonce),
+			utils.FormatAddressWithLimitsInAddressPageTable(address, op.Bundler, names[string(op.Bundler)], false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
+			paymaster,
+			utils.FormatAmount(new(big.Int).SetBytes(op.ActualGasCost), utils.Config.Frontend.ElCurrency, 6),
 		}
 	}
 
@@ -3476,130 +4259,410 @@ func (bigtable *Bigtable) GetAddressErc20TableData(address []byte, pageToken str
 			utils.FormatTokenValue(tb, true),
 			utils.FormatTokenName(tb),
 		}
-
+
+	}
+
+	data := &types.DataTableResponse{
+		Data:        tableData,
+		PagingToken: lastKey,
+	}
+
+	return data, nil
+}
+
+func (bigtable *Bigtable) GetEth1ERC721ForAddress(prefix string, limit int64) ([]*types.Eth1ERC721Indexed, string, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"prefix": prefix,
+			"limit":  limit,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	// add \x00 to the row range such that we don't include the prefix itself in the response. Converts range to open interval (start, end).
+	// "1:I:ERC721:81d98c8fda0410ee3e9d7586cb949cd19fa4cf38:TIME;"
+	rowRange := gcp_bigtable.NewRange(prefix+"\x00", prefixSuccessor(prefix, 5))
+
+	data := make([]*types.Eth1ERC721Indexed, 0, limit)
+
+	keys := make([]string, 0, limit)
+	keysMap := make(map[string]*types.Eth1ERC721Indexed, limit)
+	indexes := make([]string, 0, limit)
+
+	//  1:I:ERC721:81d98c8fda0410ee3e9d7586cb949cd19fa4cf38:TIME:9223372035220135322:0052:00000
+	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
+		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
+		indexes = append(indexes, row.Key())
+		return true
+	}, gcp_bigtable.LimitRows(limit))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(keys) == 0 {
+		return data, "", nil
+	}
+
+	indexes, keys = bigtable.rearrangeReversePaddedIndexZero(ctx, indexes, keys)
+
+	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
+		b := &types.Eth1ERC721Indexed{}
+		err := proto.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
+
+		if err != nil {
+			logrus.Fatalf("error parsing Eth1ERC721Indexed data: %v", err)
+		}
+		keysMap[row.Key()] = b
+		return true
+	})
+	if err != nil {
+		logger.WithError(err).WithField("prefix", prefix).WithField("limit", limit).Errorf("error reading rows in bigtable_eth1 / GetEth1ERC721ForAddress")
+		return nil, "", err
+	}
+
+	for _, key := range keys {
+		if d := keysMap[key]; d != nil {
+			data = append(data, d)
+		}
+	}
+	return data, skipBlockIfLastTxIndex(indexes[len(indexes)-1]), nil
+}
+
+func (bigtable *Bigtable) GetAddressErc721TableData(address []byte, pageToken string) (*types.DataTableResponse, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"address":   address,
+			"pageToken": pageToken,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	defaultPageToken := fmt.Sprintf("%s:I:ERC721:%x:%s:", bigtable.chainId, address, FILTER_TIME)
+	if pageToken == "" {
+		pageToken = defaultPageToken
+	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
+		return nil, fmt.Errorf("invalid pageToken for function GetAddressErc721TableData: %s", pageToken)
+	}
+
+	transactions, lastKey, err := bigtable.GetEth1ERC721ForAddress(pageToken, DefaultInfScrollRows)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	for _, t := range transactions {
+		names[string(t.From)] = ""
+		names[string(t.To)] = ""
+	}
+	names, _, err = BigtableClient.GetAddressesNamesArMetadata(&names, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tableData := make([][]interface{}, len(transactions))
+	for i, t := range transactions {
+		fromName := names[string(t.From)]
+		toName := names[string(t.To)]
+
+		tableData[i] = []interface{}{
+			utils.FormatTransactionHash(t.ParentHash, true),
+			utils.FormatBlockNumber(t.BlockNumber),
+			utils.FormatTimestamp(t.Time.AsTime().Unix()),
+			utils.FormatAddressWithLimitsInAddressPageTable(address, t.From, fromName, false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
+			utils.FormatAddressWithLimitsInAddressPageTable(address, t.To, toName, false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
+			utils.FormatAddressAsLink(t.TokenAddress, "", true),
+			new(big.Int).SetBytes(t.TokenId).String(),
+		}
+	}
+
+	data := &types.DataTableResponse{
+		Data:        tableData,
+		PagingToken: lastKey,
+	}
+
+	return data, nil
+}
+
+// approvalVerificationTTL is how long the result of checking an approval against the token contract is cached
+const approvalVerificationTTL = time.Hour
+
+// approvalVerificationsRunning holds the owners whose approvals are being verified in the background
+var approvalVerificationsRunning sync.Map
+
+// approvalState is the latest approval event of an approval state column, ts is the timestamp of the cell
+type approvalState struct {
+	approval *types.Eth1ApprovalIndexed
+	column   string
+	ts       gcp_bigtable.Timestamp
+}
+
+// GetAddressActiveApprovals returns the approvals of the owner that are active according to the latest approval events, ordered by
+// recency. The allowance may have been spent or the approved token transferred without an approval event, so the approvals are
+// checked against the token contract. The checks run in the background and their results are cached, approvals that have not
+// been checked yet are returned unverified and approvals that are no longer active on chain are left out.
+func (bigtable *Bigtable) GetAddressActiveApprovals(address []byte) ([]*types.Eth1AddressApproval, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"address": address,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	filter := gcp_bigtable.ChainFilters(gcp_bigtable.FamilyFilter(ACCOUNT_METADATA_FAMILY), gcp_bigtable.LatestNFilter(1))
+	row, err := bigtable.tableMetadata.ReadRow(ctx, fmt.Sprintf("%s:APPROVAL:%x", bigtable.chainId, address), gcp_bigtable.RowFilter(filter))
+	if err != nil {
+		return nil, fmt.Errorf("error reading approvals of address %x from bigtable: %w", address, err)
+	}
+
+	approvals := make([]*approvalState, 0, len(row[ACCOUNT_METADATA_FAMILY]))
+	stale := make([]*approvalState, 0)
+	for _, item := range row[ACCOUNT_METADATA_FAMILY] {
+		a := &types.Eth1ApprovalIndexed{}
+		err := proto.Unmarshal(item.Value, a)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing Eth1ApprovalIndexed data: %w", err)
+		}
+		state := &approvalState{approval: a, column: strings.TrimPrefix(item.Column, ACCOUNT_METADATA_FAMILY+":"), ts: item.Timestamp}
+		if isRevokedApproval(a) {
+			stale = append(stale, state)
+			continue
+		}
+		approvals = append(approvals, state)
+	}
+
+	sort.SliceStable(approvals, func(i, j int) bool {
+		return approvals[i].approval.GetBlockNumber() > approvals[j].approval.GetBlockNumber()
+	})
+	if len(approvals) > ApprovalsPerAddressLimit {
+		approvals = approvals[:ApprovalsPerAddressLimit]
+	}
+
+	pipe := bigtable.redisCache.Pipeline()
+	cmds := make([]*redis.StringCmd, len(approvals))
+	for i, a := range approvals {
+		cmds[i] = pipe.Get(ctx, bigtable.approvalVerificationKey(address, a))
+	}
+	if len(approvals) > 0 {
+		_, err = pipe.Exec(ctx)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("error reading approval verifications of address %x from redis: %w", address, err)
+		}
+	}
+
+	ret := make([]*types.Eth1AddressApproval, 0, len(approvals))
+	unverified := make([]*approvalState, 0)
+	for i, a := range approvals {
+		res, err := cmds[i].Result()
+		if errors.Is(err, redis.Nil) {
+			unverified = append(unverified, a)
+			ret = append(ret, &types.Eth1AddressApproval{Approval: a.approval})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading approval verification of address %x from redis: %w", address, err)
+		}
+		active, allowance, err := decodeApprovalVerification(res)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			continue
+		}
+		if a.approval.GetType() == types.ApprovalType_ERC20_APPROVAL {
+			a.approval.Value = allowance
+		}
+		ret = append(ret, &types.Eth1AddressApproval{Approval: a.approval, Verified: true})
 	}
 
-	data := &types.DataTableResponse{
-		Data:        tableData,
-		PagingToken: lastKey,
+	if len(unverified) > 0 || len(stale) > 0 {
+		bigtable.verifyApprovalsAsync(address, unverified, stale)
 	}
-
-	return data, nil
+	return ret, nil
 }
 
-func (bigtable *Bigtable) GetEth1ERC721ForAddress(prefix string, limit int64) ([]*types.Eth1ERC721Indexed, string, error) {
+// verifyApprovalsAsync checks the approvals of the owner against the token contracts in the background and caches the results.
+// The state cells of revoked approvals and of approvals that are no longer active on chain are deleted, newer cells of the same
+// column are kept.
+func (bigtable *Bigtable) verifyApprovalsAsync(address []byte, approvals, stale []*approvalState) {
+	if _, running := approvalVerificationsRunning.LoadOrStore(string(address), true); running {
+		return
+	}
 
-	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
-		logger.WithFields(logrus.Fields{
-			"prefix": prefix,
-			"limit":  limit,
-		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
-	})
-	defer tmr.Stop()
+	go func() {
+		defer approvalVerificationsRunning.Delete(string(address))
 
-	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
-	defer cancel()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
 
-	// add \x00 to the row range such that we don't include the prefix itself in the response. Converts range to open interval (start, end).
-	// "1:I:ERC721:81d98c8fda0410ee3e9d7586cb949cd19fa4cf38:TIME;"
-	rowRange := gcp_bigtable.NewRange(prefix+"\x00", prefixSuccessor(prefix, 5))
+		var mu sync.Mutex
+		g := new(errgroup.Group)
+		g.SetLimit(10)
+		for _, a := range approvals {
+			a := a
+			g.Go(func() error {
+				// the approvals are returned to the caller, so the allowance is updated on a copy
+				approval := proto.Clone(a.approval).(*types.Eth1ApprovalIndexed)
+				isActive, err := verifyApproval(approval)
+				if err != nil {
+					// the approval stays unverified and is checked again on the next request
+					logger.WithError(err).Warnf("error verifying approval of token %x for owner %x", a.approval.GetTokenAddress(), a.approval.GetOwner())
+					return nil
+				}
+				err = bigtable.redisCache.Set(ctx, bigtable.approvalVerificationKey(address, a), encodeApprovalVerification(isActive, approval.GetValue()), approvalVerificationTTL).Err()
+				if err != nil {
+					logger.WithError(err).Warnf("error caching approval verification of token %x for owner %x", a.approval.GetTokenAddress(), a.approval.GetOwner())
+				}
+				if !isActive {
+					mu.Lock()
+					stale = append(stale, a)
+					mu.Unlock()
+				}
+				return nil
+			})
+		}
+		_ = g.Wait()
 
-	data := make([]*types.Eth1ERC721Indexed, 0, limit)
+		if len(stale) == 0 {
+			return
+		}
+		muts := &types.BulkMutations{}
+		for _, a := range stale {
+			mut := gcp_bigtable.NewMutation()
+			mut.DeleteTimestampRange(ACCOUNT_METADATA_FAMILY, a.column, 0, a.ts+TIMESTAMP_GBT_SCALE)
+			muts.Keys = append(muts.Keys, fmt.Sprintf("%s:APPROVAL:%x", bigtable.chainId, address))
+			muts.Muts = append(muts.Muts, mut)
+		}
+		err := bigtable.WriteBulk(muts, bigtable.tableMetadata, DEFAULT_BATCH_INSERTS)
+		if err != nil {
+			logger.WithError(err).Errorf("error pruning stale approvals of owner %x", address)
+		}
+	}()
+}
 
-	keys := make([]string, 0, limit)
-	keysMap := make(map[string]*types.Eth1ERC721Indexed, limit)
-	indexes := make([]string, 0, limit)
+// approvalVerificationKey returns the redis key of the verification of an approval, it contains the cell timestamp so that a new
+// approval event of the same token and spender is verified again
+func (bigtable *Bigtable) approvalVerificationKey(address []byte, a *approvalState) string {
+	return fmt.Sprintf("%s:APPROVAL_VERIFICATION:%x:%s:%d", bigtable.chainId, address, a.column, a.ts)
+}
 
-	//  1:I:ERC721:81d98c8fda0410ee3e9d7586cb949cd19fa4cf38:TIME:9223372035220135322:0052:00000
-	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
-		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
-		indexes = append(indexes, row.Key())
-		return true
-	}, gcp_bigtable.LimitRows(limit))
-	if err != nil {
-		return nil, "", err
+// encodeApprovalVerification encodes the result of verifying an approval, the allowance is only set for erc20 approvals
+func encodeApprovalVerification(active bool, allowance []byte) string {
+	if !active {
+		return "inactive"
 	}
+	return fmt.Sprintf("active:%x", allowance)
+}
 
-	if len(keys) == 0 {
-		return data, "", nil
+func decodeApprovalVerification(value string) (active bool, allowance []byte, err error) {
+	if value == "inactive" {
+		return false, nil, nil
 	}
+	allowanceHex, ok := strings.CutPrefix(value, "active:")
+	if !ok {
+		return false, nil, fmt.Errorf("invalid approval verification %q", value)
+	}
+	allowance, err = hex.DecodeString(allowanceHex)
+	if err != nil {
+		return false, nil, fmt.Errorf("invalid approval verification %q: %w", value, err)
+	}
+	return true, allowance, nil
+}
 
-	indexes, keys = bigtable.rearrangeReversePaddedIndexZero(ctx, indexes, keys)
-
-	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
-		b := &types.Eth1ERC721Indexed{}
-		err := proto.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
+// isRevokedApproval returns whether the approval event revokes the approval of its state column
+func isRevokedApproval(approval *types.Eth1ApprovalIndexed) bool {
+	switch approval.GetType() {
+	case types.ApprovalType_ERC20_APPROVAL:
+		return new(big.Int).SetBytes(approval.GetValue()).Sign() == 0
+	case types.ApprovalType_ERC721_APPROVAL:
+		return bytes.Equal(approval.GetSpender(), ZERO_ADDRESS)
+	case types.ApprovalType_APPROVAL_FOR_ALL:
+		return !approval.GetApproved()
+	}
+	return false
+}
 
+// verifyApproval checks the approval against the current state of the token contract, the value of an erc20 approval is
+// updated to the current allowance
+func verifyApproval(approval *types.Eth1ApprovalIndexed) (bool, error) {
+	switch approval.GetType() {
+	case types.ApprovalType_ERC20_APPROVAL:
+		allowance, err := rpc.CurrentGethClient.GetERC20Allowance(approval.GetTokenAddress(), approval.GetOwner(), approval.GetSpender())
 		if err != nil {
-			logrus.Fatalf("error parsing Eth1ERC721Indexed data: %v", err)
+			return false, err
 		}
-		keysMap[row.Key()] = b
-		return true
-	})
-	if err != nil {
-		logger.WithError(err).WithField("prefix", prefix).WithField("limit", limit).Errorf("error reading rows in bigtable_eth1 / GetEth1ERC721ForAddress")
-		return nil, "", err
-	}
-
-	for _, key := range keys {
-		if d := keysMap[key]; d != nil {
-			data = append(data, d)
+		approval.Value = allowance
+		return new(big.Int).SetBytes(allowance).Sign() > 0, nil
+	case types.ApprovalType_ERC721_APPROVAL:
+		approved, err := rpc.CurrentGethClient.GetERC721Approved(approval.GetTokenAddress(), approval.GetTokenId())
+		if err != nil {
+			return false, err
 		}
+		return bytes.Equal(approved, approval.GetSpender()), nil
+	case types.ApprovalType_APPROVAL_FOR_ALL:
+		return rpc.CurrentGethClient.GetIsApprovedForAll(approval.GetTokenAddress(), approval.GetOwner(), approval.GetSpender())
 	}
-	return data, skipBlockIfLastTxIndex(indexes[len(indexes)-1]), nil
+	return false, fmt.Errorf("unknown approval type %v", approval.GetType())
 }
 
-func (bigtable *Bigtable) GetAddressErc721TableData(address []byte, pageToken string) (*types.DataTableResponse, error) {
+func (bigtable *Bigtable) GetAddressApprovalsTableData(address []byte) (*types.DataTableResponse, error) {
 
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
 		logger.WithFields(logrus.Fields{
-			"address":   address,
-			"pageToken": pageToken,
+			"address": address,
 		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
 	})
 	defer tmr.Stop()
 
-	defaultPageToken := fmt.Sprintf("%s:I:ERC721:%x:%s:", bigtable.chainId, address, FILTER_TIME)
-	if pageToken == "" {
-		pageToken = defaultPageToken
-	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
-		return nil, fmt.Errorf("invalid pageToken for function GetAddressErc721TableData: %s", pageToken)
-	}
-
-	transactions, lastKey, err := bigtable.GetEth1ERC721ForAddress(pageToken, DefaultInfScrollRows)
+	approvals, err := bigtable.GetAddressActiveApprovals(address)
 	if err != nil {
 		return nil, err
 	}
 
 	names := make(map[string]string)
-	for _, t := range transactions {
-		names[string(t.From)] = ""
-		names[string(t.To)] = ""
+	tokens := make(map[string]*types.ERC20Metadata)
+	for _, a := range approvals {
+		names[string(a.Approval.GetSpender())] = ""
+		if a.Approval.GetType() == types.ApprovalType_ERC20_APPROVAL {
+			tokens[string(a.Approval.GetTokenAddress())] = nil
+		}
 	}
-	names, _, err = BigtableClient.GetAddressesNamesArMetadata(&names, nil)
+	names, tokens, err = BigtableClient.GetAddressesNamesArMetadata(&names, &tokens)
 	if err != nil {
 		return nil, err
 	}
 
-	tableData := make([][]interface{}, len(transactions))
-	for i, t := range transactions {
-		fromName := names[string(t.From)]
-		toName := names[string(t.To)]
+	tableData := make([][]interface{}, len(approvals))
+	for i, a := range approvals {
+		token := utils.FormatAddressAsLink(a.Approval.GetTokenAddress(), "", true)
+		if metadata := tokens[string(a.Approval.GetTokenAddress())]; metadata != nil {
+			token = utils.FormatTokenName(&types.Eth1AddressBalance{
+				Address:  address,
+				Token:    a.Approval.GetTokenAddress(),
+				Metadata: metadata,
+			})
+		}
 
 		tableData[i] = []interface{}{
-			utils.FormatTransactionHash(t.ParentHash, true),
-			utils.FormatBlockNumber(t.BlockNumber),
-			utils.FormatTimestamp(t.Time.AsTime().Unix()),
-			utils.FormatAddressWithLimitsInAddressPageTable(address, t.From, fromName, false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
-			utils.FormatAddressWithLimitsInAddressPageTable(address, t.To, toName, false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
-			utils.FormatAddressAsLink(t.TokenAddress, "", true),
-			new(big.Int).SetBytes(t.TokenId).String(),
+			token,
+			utils.FormatAddressWithLimitsInAddressPageTable(address, a.Approval.GetSpender(), names[string(a.Approval.GetSpender())], false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
+			utils.FormatApprovalAmount(a.Approval, tokens[string(a.Approval.GetTokenAddress())]),
+			utils.FormatTransactionHash(a.Approval.GetParentHash(), true),
+			utils.FormatTimestamp(a.Approval.GetTime().AsTime().Unix()),
+			utils.FormatApprovalVerification(a.Verified),
 		}
 	}
 
 	data := &types.DataTableResponse{
-		Data:        tableData,
-		PagingToken: lastKey,
+		Data: tableData,
 	}
 
 	return data, nil
@@ -4414,6 +5477,292 @@ func (bigtable *Bigtable) SaveAddressName(address []byte, name string) error {
 	return bigtable.tableMetadata.Apply(ctx, fmt.Sprintf("%s:%x", bigtable.chainId, address), mut)
 }
 
+// GetProxyImplementationHistory returns the implementation updates of a proxy, newest first. It returns an empty slice
+// if the address is not a known proxy.
+func (bigtable *Bigtable) GetProxyImplementationHistory(address []byte) ([]*types.Eth1ProxyImplementation, error) {
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"address": address,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	filter := gcp_bigtable.ChainFilters(gcp_bigtable.FamilyFilter(ACCOUNT_METADATA_FAMILY), gcp_bigtable.ColumnFilter(ACCOUNT_PROXY))
+	row, err := bigtable.tableMetadata.ReadRow(ctx, fmt.Sprintf("%s:S:%x", bigtable.chainId, address), gcp_bigtable.RowFilter(filter))
+	if err != nil {
+		return nil, fmt.Errorf("error reading proxy history of address 0x%x: %w", address, err)
+	}
+
+	// cells are returned in reverse order, so the latest update is first
+	history := make([]*types.Eth1ProxyImplementation, 0, len(row[ACCOUNT_METADATA_FAMILY]))
+	for _, item := range row[ACCOUNT_METADATA_FAMILY] {
+		update := &types.Eth1ProxyImplementation{}
+		err := proto.Unmarshal(item.Value, update)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding proxy update of address 0x%x: %w", address, err)
+		}
+		history = append(history, update)
+	}
+	return history, nil
+}
+
+// GetProxyImplementationAt returns the implementation of a proxy at the end of the given block, -1 returns the current
+// implementation. Upgrades of the beacon of beacon proxies are taken into account. It returns nil if the address was
+// not a proxy at that block, beacons are not proxies as they do not delegate calls to their implementation.
+func (bigtable *Bigtable) GetProxyImplementationAt(address []byte, blockNumber int64) (*types.Eth1ProxyImplementation, error) {
+	history, err := bigtable.GetProxyImplementationHistory(address)
+	if err != nil {
+		return nil, err
+	}
+	proxy := proxyImplementationAt(history, blockNumber)
+	if proxy != nil && proxy.Type == types.ProxyType_UPGRADEABLE_BEACON {
+		return nil, nil
+	}
+	if proxy == nil || proxy.Type != types.ProxyType_BEACON_PROXY {
+		return proxy, nil
+	}
+
+	beaconHistory, err := bigtable.GetProxyImplementationHistory(proxy.Beacon)
+	if err != nil {
+		return nil, err
+	}
+	beacon := proxyImplementationAt(beaconHistory, blockNumber)
+	if beacon != nil && beacon.BlockNumber > proxy.BlockNumber {
+		proxy = proto.Clone(proxy).(*types.Eth1ProxyImplementation)
+		proxy.Implementation = beacon.Implementation
+	}
+	return proxy, nil
+}
+
+// proxyImplementationAt returns the latest update of a proxy history, newest first, up to the given block
+func proxyImplementationAt(history []*types.Eth1ProxyImplementation, blockNumber int64) *types.Eth1ProxyImplementation {
+	for _, update := range history {
+		if blockNumber == -1 || update.BlockNumber <= uint64(blockNumber) {
+			return update
+		}
+	}
+	return nil
+}
+
+// proxyCandidateKey returns the row of a created contract or a proxy event that has to be checked by ImportProxyUpdates,
+// the rows are ordered by their position in the chain
+func proxyCandidateKey(chainId string, blockNumber uint64, txIdx, idx int, address []byte) string {
+	return fmt.Sprintf("%s:PROXY:V:%012d:%05d:%06d:%x", chainId, blockNumber, txIdx, idx, address)
+}
+
+// parkedProxyCandidateKey returns the row a queued candidate is moved to if the state of its block can not be read
+func parkedProxyCandidateKey(key string) string {
+	return strings.Replace(key, ":PROXY:V:", ":PROXY:P:", 1)
+}
+
+// proxyCandidate is a queued created contract or proxy event
+type proxyCandidate struct {
+	key     string
+	address []byte
+	ts      gcp_bigtable.Timestamp
+	event   *types.Eth1ProxyImplementation
+	value   []byte
+}
+
+// ImportProxyUpdates checks the queued created contracts and proxy events against the state at the end of their block and
+// writes the proxy implementations to the metadata table. Upgraded and BeaconUpgraded events can be emitted by any contract,
+// so they are only accepted if the implementation or beacon slot of the emitter holds the address of the event. Upgraded
+// events of beacons are accepted if the implementation of the beacon is the one of the event. If the node returns an
+// error for a block, e.g. because reading the state of old blocks requires an archive node, the candidates of the block
+// are parked and the remaining blocks are imported. Parked candidates are retried once the queue is empty.
+func (bigtable *Bigtable) ImportProxyUpdates(client *rpc.ErigonClient, batchSize int64) error {
+	startTime := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("bt_import_proxy_updates").Observe(time.Since(startTime).Seconds())
+	}()
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	parked := false
+	candidatesByBlock, blocks, err := bigtable.readProxyCandidates(ctx, fmt.Sprintf("%s:PROXY:V:", bigtable.chainId), batchSize)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		parked = true
+		candidatesByBlock, blocks, err = bigtable.readProxyCandidates(ctx, fmt.Sprintf("%s:PROXY:P:", bigtable.chainId), batchSize)
+		if err != nil {
+			return err
+		}
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	writes := &types.BulkMutations{}
+	deletes := &types.BulkMutations{}
+	parkWrites := &types.BulkMutations{}
+	for _, blockNumber := range blocks {
+		candidates := candidatesByBlock[blockNumber]
+		addresses := make([][]byte, 0, len(candidates))
+		for _, c := range candidates {
+			addresses = append(addresses, c.address)
+		}
+		implementations, err := client.GetProxyImplementations(addresses, blockNumber)
+		if err == nil {
+			err = bigtable.acceptProxyCandidates(client, blockNumber, candidates, implementations, writes)
+		}
+		if err != nil {
+			logger.WithError(err).Warnf("error checking the proxy candidates of block %v, parking them", blockNumber)
+			if parked {
+				continue
+			}
+			for _, c := range candidates {
+				mut := gcp_bigtable.NewMutation()
+				mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), c.value)
+				parkWrites.Keys = append(parkWrites.Keys, parkedProxyCandidateKey(c.key))
+				parkWrites.Muts = append(parkWrites.Muts, mut)
+			}
+		}
+
+		for _, c := range candidates {
+			mutDelete := gcp_bigtable.NewMutation()
+			mutDelete.DeleteRow()
+			deletes.Keys = append(deletes.Keys, c.key)
+			deletes.Muts = append(deletes.Muts, mutDelete)
+		}
+	}
+
+	err = bigtable.WriteBulk(writes, bigtable.tableMetadata, DEFAULT_BATCH_INSERTS)
+	if err != nil {
+		return err
+	}
+	err = bigtable.WriteBulk(parkWrites, bigtable.tableData, DEFAULT_BATCH_INSERTS)
+	if err != nil {
+		return err
+	}
+	err = bigtable.WriteBulk(deletes, bigtable.tableData, DEFAULT_BATCH_INSERTS)
+	if err != nil {
+		return err
+	}
+	logger.WithFields(logrus.Fields{"updates": len(deletes.Keys) - len(parkWrites.Keys), "parked": len(parkWrites.Keys)}).Info("Import of proxy updates completed")
+	return nil
+}
+
+// acceptProxyCandidates adds the proxy implementations of the accepted candidates of a block to writes, implementations
+// is the proxy state of the candidates at the end of the block
+func (bigtable *Bigtable) acceptProxyCandidates(client *rpc.ErigonClient, blockNumber uint64, candidates []*proxyCandidate, implementations []*types.Eth1ProxyImplementation, writes *types.BulkMutations) error {
+	// Upgraded events of contracts without an implementation slot may be emitted by beacons
+	beacons := make([][]byte, 0)
+	for i, c := range candidates {
+		if implementations[i] == nil && c.event.Type == types.ProxyType_EIP1967_PROXY && len(c.event.Implementation) > 0 {
+			beacons = append(beacons, c.address)
+		}
+	}
+	beaconImplementations, err := client.GetBeaconImplementations(beacons, blockNumber)
+	if err != nil {
+		return fmt.Errorf("error getting beacon implementations at block %v: %w", blockNumber, err)
+	}
+
+	blockWrites := &types.BulkMutations{}
+	for i, c := range candidates {
+		var beaconImplementation []byte
+		if implementations[i] == nil && c.event.Type == types.ProxyType_EIP1967_PROXY && len(c.event.Implementation) > 0 {
+			beaconImplementation = beaconImplementations[0]
+			beaconImplementations = beaconImplementations[1:]
+		}
+
+		update := acceptProxyUpdate(c.event, implementations[i], beaconImplementation)
+		if update != nil {
+			update.ParentHash = c.event.ParentHash
+			update.BlockNumber = c.event.BlockNumber
+			update.Time = c.event.Time
+			b, err := proto.Marshal(update)
+			if err != nil {
+				return err
+			}
+			mut := gcp_bigtable.NewMutation()
+			mut.Set(ACCOUNT_METADATA_FAMILY, ACCOUNT_PROXY, c.ts, b)
+			blockWrites.Keys = append(blockWrites.Keys, fmt.Sprintf("%s:S:%x", bigtable.chainId, c.address))
+			blockWrites.Muts = append(blockWrites.Muts, mut)
+		} else if len(c.event.Implementation) > 0 || len(c.event.Beacon) > 0 {
+			logger.Warnf("ignoring proxy event of contract %x at block %v that does not match its state", c.address, blockNumber)
+		}
+	}
+
+	writes.Keys = append(writes.Keys, blockWrites.Keys...)
+	writes.Muts = append(writes.Muts, blockWrites.Muts...)
+	return nil
+}
+
+// readProxyCandidates reads up to limit queued or parked proxy candidates with the given row prefix, grouped by block
+func (bigtable *Bigtable) readProxyCandidates(ctx context.Context, prefix string, limit int64) (map[uint64][]*proxyCandidate, []uint64, error) {
+	candidatesByBlock := make(map[uint64][]*proxyCandidate)
+	blocks := make([]uint64, 0)
+	var parseErr error
+	err := bigtable.tableData.ReadRows(ctx, gcp_bigtable.PrefixRange(prefix), func(row gcp_bigtable.Row) bool {
+		split := strings.Split(row.Key(), ":")
+		if len(split) != 7 {
+			parseErr = fmt.Errorf("invalid proxy candidate key %v", row.Key())
+			return false
+		}
+		blockNumber, err1 := strconv.ParseUint(split[3], 10, 64)
+		txIdx, err2 := strconv.ParseUint(split[4], 10, 64)
+		idx, err3 := strconv.ParseUint(split[5], 10, 64)
+		address, err4 := hex.DecodeString(split[6])
+		if err := errors.Join(err1, err2, err3, err4); err != nil {
+			parseErr = fmt.Errorf("invalid proxy candidate key %v: %w", row.Key(), err)
+			return false
+		}
+		ts, err := encodeIsContractUpdateTs(blockNumber, txIdx, idx)
+		if err != nil {
+			parseErr = err
+			return false
+		}
+		event := &types.Eth1ProxyImplementation{}
+		if err := proto.Unmarshal(row[DEFAULT_FAMILY][0].Value, event); err != nil {
+			parseErr = fmt.Errorf("error decoding proxy candidate %v: %w", row.Key(), err)
+			return false
+		}
+		if _, ok := candidatesByBlock[blockNumber]; !ok {
+			blocks = append(blocks, blockNumber)
+		}
+		candidatesByBlock[blockNumber] = append(candidatesByBlock[blockNumber], &proxyCandidate{key: row.Key(), address: address, ts: ts, event: event, value: row[DEFAULT_FAMILY][0].Value})
+		return true
+	}, gcp_bigtable.LimitRows(limit))
+	if err != nil {
+		return nil, nil, err
+	}
+	if parseErr != nil {
+		return nil, nil, parseErr
+	}
+	return candidatesByBlock, blocks, nil
+}
+
+// acceptProxyUpdate returns the update to store for a queued created contract or proxy event, given the proxy state of
+// the contract at the end of the block. For an Upgraded event of a contract that is not a proxy, beaconImplementation is
+// the result of implementation() of the contract. It returns nil if the contract is not a proxy or the event does not
+// match the state.
+func acceptProxyUpdate(event, state *types.Eth1ProxyImplementation, beaconImplementation []byte) *types.Eth1ProxyImplementation {
+	switch {
+	case len(event.Beacon) > 0:
+		if state != nil && state.Type == types.ProxyType_BEACON_PROXY && bytes.Equal(state.Beacon, event.Beacon) {
+			return state
+		}
+	case len(event.Implementation) > 0:
+		if state != nil && (state.Type == types.ProxyType_EIP1967_PROXY || state.Type == types.ProxyType_TRANSPARENT_PROXY) && bytes.Equal(state.Implementation, event.Implementation) {
+			return state
+		}
+		if state == nil && len(beaconImplementation) > 0 && bytes.Equal(beaconImplementation, event.Implementation) {
+			// the beacon does not delegate calls itself, its history is only used for the proxies pointing to it
+			return &types.Eth1ProxyImplementation{Type: types.ProxyType_UPGRADEABLE_BEACON, Implementation: event.Implementation}
+		}
+	default:
+		// a created contract
+		return state
+	}
+	return nil
+}
+
 func (bigtable *Bigtable) GetContractMetadata(address []byte) (*types.ContractMetadata, error) {
 
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
@@ -4649,7 +5998,7 @@ func (bigtable *Bigtable) DeleteBlock(blockNumber uint64, blockHash []byte) erro
 
 	filter := gcp_bigtable.ChainFilters(
 		gcp_bigtable.FamilyFilter(ACCOUNT_METADATA_FAMILY),
-		gcp_bigtable.ColumnFilter(ACCOUNT_IS_CONTRACT),
+		gcp_bigtable.ColumnFilter(fmt.Sprintf("^(%s|%s)$", ACCOUNT_IS_CONTRACT, ACCOUNT_PROXY)),
 		gcp_bigtable.TimestampRangeFilterMicros(starttime, endtime-1),
 	)
 
@@ -4671,6 +6020,7 @@ func (bigtable *Bigtable) DeleteBlock(blockNumber uint64, blockHash []byte) erro
 	err = bigtable.tableMetadata.ReadRows(ctx, gcp_bigtable.PrefixRange(fmt.Sprintf("%s:S:", bigtable.chainId)), func(row gcp_bigtable.Row) bool {
 		mutDelete := gcp_bigtable.NewMutation()
 		mutDelete.DeleteTimestampRange(ACCOUNT_METADATA_FAMILY, ACCOUNT_IS_CONTRACT, starttime, endtime)
+		mutDelete.DeleteTimestampRange(ACCOUNT_METADATA_FAMILY, ACCOUNT_PROXY, starttime, endtime)
 
 		mutsDelete.Keys = append(mutsDelete.Keys, row.Key())
 		mutsDelete.Muts = append(mutsDelete.Muts, mutDelete)
@@ -5123,22 +6473,31 @@ var (
 	GASNOW_FAST_COLUMN     = "FAST"
 	GASNOW_STANDARD_COLUMN = "STAN"
 	GASNOW_SLOW_COLUMN     = "SLOW"
+
+	GASNOW_BLOB_BASE_FEE_COLUMN   = "BBFE"
+	GASNOW_BLOB_GAS_USED_COLUMN   = "BGUS"
+	GASNOW_EXCESS_BLOB_GAS_COLUMN = "XBGS"
 )
 
-func (bigtable *Bigtable) SaveGasNowHistory(slow, standard, rapid, fast *big.Int) error {
+func (bigtable *Bigtable) SaveGasNowHistory(history *types.GasNowHistory) error {
 	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
 	defer done()
 
-	ts := time.Now().Truncate(time.Minute)
+	ts := history.Ts.Truncate(time.Minute)
 	row := fmt.Sprintf("%s:GASNOW:%s", bigtable.chainId, reversePaddedBigtableTimestamp(timestamppb.New(ts)))
 
 	gcpTs := gcp_bigtable.Time(ts)
 
 	mut := gcp_bigtable.NewMutation()
-	mut.Set(SERIES_FAMILY, GASNOW_SLOW_COLUMN, gcpTs, slow.Bytes())
-	mut.Set(SERIES_FAMILY, GASNOW_STANDARD_COLUMN, gcpTs, standard.Bytes())
-	mut.Set(SERIES_FAMILY, GASNOW_FAST_COLUMN, gcpTs, fast.Bytes())
-	mut.Set(SERIES_FAMILY, GASNOW_RAPID_COLUMN, gcpTs, rapid.Bytes())
+	mut.Set(SERIES_FAMILY, GASNOW_SLOW_COLUMN, gcpTs, history.Slow.Bytes())
+	mut.Set(SERIES_FAMILY, GASNOW_STANDARD_COLUMN, gcpTs, history.Standard.Bytes())
+	mut.Set(SERIES_FAMILY, GASNOW_FAST_COLUMN, gcpTs, history.Fast.Bytes())
+	mut.Set(SERIES_FAMILY, GASNOW_RAPID_COLUMN, gcpTs, history.Rapid.Bytes())
+	if history.BlobBaseFee != nil {
+		mut.Set(SERIES_FAMILY, GASNOW_BLOB_BASE_FEE_COLUMN, gcpTs, history.BlobBaseFee.Bytes())
+		mut.Set(SERIES_FAMILY, GASNOW_BLOB_GAS_USED_COLUMN, gcpTs, new(big.Int).SetUint64(history.BlobGasUsed).Bytes())
+		mut.Set(SERIES_FAMILY, GASNOW_EXCESS_BLOB_GAS_COLUMN, gcpTs, new(big.Int).SetUint64(history.ExcessBlobGas).Bytes())
+	}
 
 	err := bigtable.tableMetadata.Apply(ctx, row, mut)
 	if err != nil {
@@ -5174,14 +6533,29 @@ func (bigtable *Bigtable) GetGasNowHistory(ts, pastTs time.Time) ([]types.GasNow
 			logrus.Errorf("error reading row: %+v", row)
 			return false
 		}
-		// Columns are returned alphabetically so fast, rapid, slow, standard should be the order
-		history = append(history, types.GasNowHistory{
-			Ts:       row[SERIES_FAMILY][0].Timestamp.Time(),
-			Fast:     new(big.Int).SetBytes(row[SERIES_FAMILY][0].Value),
-			Rapid:    new(big.Int).SetBytes(row[SERIES_FAMILY][1].Value),
-			Slow:     new(big.Int).SetBytes(row[SERIES_FAMILY][2].Value),
-			Standard: new(big.Int).SetBytes(row[SERIES_FAMILY][3].Value),
-		})
+		entry := types.GasNowHistory{
+			Ts: row[SERIES_FAMILY][0].Timestamp.Time(),
+		}
+		for _, item := range row[SERIES_FAMILY] {
+			value := new(big.Int).SetBytes(item.Value)
+			switch strings.TrimPrefix(item.Column, SERIES_FAMILY+":") {
+			case GASNOW_SLOW_COLUMN:
+				entry.Slow = value
+			case GASNOW_STANDARD_COLUMN:
+				entry.Standard = value
+			case GASNOW_FAST_COLUMN:
+				entry.Fast = value
+			case GASNOW_RAPID_COLUMN:
+				entry.Rapid = value
+			case GASNOW_BLOB_BASE_FEE_COLUMN:
+				entry.BlobBaseFee = value
+			case GASNOW_BLOB_GAS_USED_COLUMN:
+				entry.BlobGasUsed = value.Uint64()
+			case GASNOW_EXCESS_BLOB_GAS_COLUMN:
+				entry.ExcessBlobGas = value.Uint64()
+			}
+		}
+		history = append(history, entry)
 		return true
 	}
 
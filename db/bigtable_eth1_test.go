@@ -1,9 +1,25 @@
 package db
 
 import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
 	"testing"
+	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/erc20"
+	"github.com/gobitfly/eth2-beaconchain-explorer/erc4337"
+	"github.com/gobitfly/eth2-beaconchain-explorer/erc721"
 	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth_types "github.com/ethereum/go-ethereum/core/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestName(t *testing.T) {
@@ -56,3 +72,386 @@ func TestName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseApprovalLog(t *testing.T) {
+	erc20Filterer, err := erc20.NewErc20Filterer(common.Address{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	erc721Filterer, err := erc721.NewErc721Filterer(common.Address{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := common.HexToAddress("0x1f9840a85d5af5bf1d1762f925bdaddc4201f984")
+	owner := common.HexToAddress("0x96abc34501e9fc274f6d4e39cbb4004c0f6e519f")
+	spender := common.HexToAddress("0x000000000022d473030f116ddee9f6b43ac78ba3")
+
+	tests := []struct {
+		name     string
+		topics   []common.Hash
+		data     []byte
+		expected *types.Eth1ApprovalIndexed
+	}{
+		{
+			name:   "erc20 approval",
+			topics: []common.Hash{common.BytesToHash(erc20.ApprovalTopic), common.BytesToHash(owner.Bytes()), common.BytesToHash(spender.Bytes())},
+			data:   common.LeftPadBytes(big.NewInt(1000).Bytes(), 32),
+			expected: &types.Eth1ApprovalIndexed{
+				TokenAddress: token.Bytes(),
+				Type:         types.ApprovalType_ERC20_APPROVAL,
+				Owner:        owner.Bytes(),
+				Spender:      spender.Bytes(),
+				Value:        big.NewInt(1000).Bytes(),
+			},
+		},
+		{
+			name:   "erc721 approval",
+			topics: []common.Hash{common.BytesToHash(erc721.ApprovalTopic), common.BytesToHash(owner.Bytes()), common.BytesToHash(spender.Bytes()), common.BigToHash(big.NewInt(42))},
+			expected: &types.Eth1ApprovalIndexed{
+				TokenAddress: token.Bytes(),
+				Type:         types.ApprovalType_ERC721_APPROVAL,
+				Owner:        owner.Bytes(),
+				Spender:      spender.Bytes(),
+				TokenId:      big.NewInt(42).Bytes(),
+			},
+		},
+		{
+			name:   "approval for all",
+			topics: []common.Hash{common.BytesToHash(erc721.ApprovalForAllTopic), common.BytesToHash(owner.Bytes()), common.BytesToHash(spender.Bytes())},
+			data:   common.LeftPadBytes([]byte{1}, 32),
+			expected: &types.Eth1ApprovalIndexed{
+				TokenAddress: token.Bytes(),
+				Type:         types.ApprovalType_APPROVAL_FOR_ALL,
+				Owner:        owner.Bytes(),
+				Spender:      spender.Bytes(),
+				Approved:     true,
+			},
+		},
+		{
+			name:     "transfer",
+			topics:   []common.Hash{common.BytesToHash(erc20.TransferTopic), common.BytesToHash(owner.Bytes()), common.BytesToHash(spender.Bytes())},
+			data:     common.LeftPadBytes(big.NewInt(1000).Bytes(), 32),
+			expected: nil,
+		},
+		{
+			name:     "approval without indexed spender",
+			topics:   []common.Hash{common.BytesToHash(erc20.ApprovalTopic), common.BytesToHash(owner.Bytes())},
+			data:     common.LeftPadBytes(big.NewInt(1000).Bytes(), 32),
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseApprovalLog(erc20Filterer, erc721Filterer, eth_types.Log{Address: token, Topics: tt.topics, Data: tt.data})
+			if !proto.Equal(got, tt.expected) {
+				t.Errorf("got %v want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUserOperationsFromTx(t *testing.T) {
+	type packedUserOperation struct {
+		Sender             common.Address
+		Nonce              *big.Int
+		InitCode           []byte
+		CallData           []byte
+		AccountGasLimits   [32]byte
+		PreVerificationGas *big.Int
+		GasFees            [32]byte
+		PaymasterAndData   []byte
+		Signature          []byte
+	}
+
+	entryPoint := erc4337.EntryPoints[1]
+	bundler := common.HexToAddress("0x4337001fff419768e088ce247456c1b892888084")
+	sender := common.HexToAddress("0x96abc34501e9fc274f6d4e39cbb4004c0f6e519f")
+	paymaster := common.HexToAddress("0x0000000000000039cd5e8ae05257ce51c473ddd1")
+	factory := common.HexToAddress("0x9406cc6185a346906296840746125a0e44976454")
+
+	calldata, err := erc4337.EntryPointV07Abi.Pack("handleOps", []packedUserOperation{
+		{
+			Sender:             sender,
+			Nonce:              big.NewInt(0),
+			InitCode:           append(factory.Bytes(), 0x5f, 0xbf, 0xb9, 0xcf),
+			CallData:           []byte{0xb6, 0x1d, 0x27, 0xf6, 0x00},
+			PreVerificationGas: big.NewInt(50000),
+		},
+		{
+			Sender:             sender,
+			Nonce:              big.NewInt(1),
+			CallData:           []byte{0x47, 0xe1, 0xda, 0x2a},
+			PreVerificationGas: big.NewInt(50000),
+		},
+	}, bundler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userOpEvent := func(address common.Address, nonce int64, paymaster common.Address, success bool) *types.Eth1Log {
+		successWord := common.Hash{}
+		if success {
+			successWord = common.BigToHash(big.NewInt(1))
+		}
+		data := append(common.BigToHash(big.NewInt(nonce)).Bytes(), successWord.Bytes()...)
+		data = append(data, common.BigToHash(big.NewInt(21000)).Bytes()...)
+		data = append(data, common.BigToHash(big.NewInt(100000)).Bytes()...)
+		return &types.Eth1Log{
+			Address: address.Bytes(),
+			Topics:  [][]byte{erc4337.UserOperationEventTopic, common.BigToHash(big.NewInt(nonce + 1)).Bytes(), common.BytesToHash(sender.Bytes()).Bytes(), common.BytesToHash(paymaster.Bytes()).Bytes()},
+			Data:    data,
+		}
+	}
+
+	tx := &types.Eth1Transaction{
+		From: bundler.Bytes(),
+		To:   entryPoint.Bytes(),
+		Data: calldata,
+		Logs: []*types.Eth1Log{
+			userOpEvent(entryPoint, 0, common.Address{}, true),
+			// events of other contracts must not be indexed
+			userOpEvent(common.HexToAddress("0x1f9840a85d5af5bf1d1762f925bdaddc4201f984"), 1, paymaster, true),
+			userOpEvent(entryPoint, 1, paymaster, false),
+		},
+	}
+
+	userOps := userOperationsFromTx(tx)
+	if got, want := len(userOps), 2; got != want {
+		t.Fatalf("got %v user operations want %v", got, want)
+	}
+	if userOps[1] != nil {
+		t.Errorf("got user operation of log 1 of a contract that is not an entry point")
+	}
+
+	tests := []struct {
+		name     string
+		logIndex int
+		expected *types.Eth1UserOperationIndexed
+	}{
+		{
+			name:     "account deployment without paymaster",
+			logIndex: 0,
+			expected: &types.Eth1UserOperationIndexed{
+				UserOpHash:    common.BigToHash(big.NewInt(1)).Bytes(),
+				EntryPoint:    entryPoint.Bytes(),
+				Sender:        sender.Bytes(),
+				Paymaster:     common.Address{}.Bytes(),
+				Nonce:         big.NewInt(0).Bytes(),
+				Success:       true,
+				ActualGasCost: big.NewInt(21000).Bytes(),
+				ActualGasUsed: big.NewInt(100000).Bytes(),
+				Bundler:       bundler.Bytes(),
+				Factory:       factory.Bytes(),
+				MethodId:      []byte{0xb6, 0x1d, 0x27, 0xf6},
+			},
+		},
+		{
+			name:     "failed operation with paymaster",
+			logIndex: 2,
+			expected: &types.Eth1UserOperationIndexed{
+				UserOpHash:    common.BigToHash(big.NewInt(2)).Bytes(),
+				EntryPoint:    entryPoint.Bytes(),
+				Sender:        sender.Bytes(),
+				Paymaster:     paymaster.Bytes(),
+				Nonce:         big.NewInt(1).Bytes(),
+				Success:       false,
+				ActualGasCost: big.NewInt(21000).Bytes(),
+				ActualGasUsed: big.NewInt(100000).Bytes(),
+				Bundler:       bundler.Bytes(),
+				MethodId:      []byte{0x47, 0xe1, 0xda, 0x2a},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userOps[tt.logIndex]; !proto.Equal(got, tt.expected) {
+				t.Errorf("got %v want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProxyImplementationAt(t *testing.T) {
+	history := []*types.Eth1ProxyImplementation{
+		{BlockNumber: 300, Implementation: []byte{0x03}},
+		{BlockNumber: 200, Implementation: []byte{0x02}},
+		{BlockNumber: 100, Implementation: []byte{0x01}},
+	}
+	tests := []struct {
+		blockNumber int64
+		want        []byte
+	}{
+		{-1, []byte{0x03}},
+		{99, nil},
+		{100, []byte{0x01}},
+		{250, []byte{0x02}},
+		{300, []byte{0x03}},
+	}
+	for _, tt := range tests {
+		got := proxyImplementationAt(history, tt.blockNumber)
+		if (got == nil) != (tt.want == nil) || (got != nil && !bytes.Equal(got.Implementation, tt.want)) {
+			t.Errorf("block %v: got %v, want implementation %x", tt.blockNumber, got, tt.want)
+		}
+	}
+}
+
+func TestTransformSetCodeAuthorizations(t *testing.T) {
+	config := utils.Config
+	t.Cleanup(func() { utils.Config = config })
+	utils.Config = &types.Config{}
+	utils.Config.Chain.ClConfig.DepositChainID = 1
+
+	authority := common.HexToAddress("0x96abc34501e9fc274f6d4e39cbb4004c0f6e519f").Bytes()
+	delegate := common.HexToAddress("0x63c0c19a282a1b52b07dd5a65b58948a07dae32b").Bytes()
+	txHash := common.HexToHash("0x01").Bytes()
+	blockTime := timestamppb.New(time.Unix(1700000000, 0))
+
+	blk := &types.Eth1Block{
+		Number: 100,
+		Time:   blockTime,
+		Transactions: []*types.Eth1Transaction{
+			{Hash: common.HexToHash("0x02").Bytes()},
+			{
+				Hash: txHash,
+				AuthorizationList: []*types.Eth1SetCodeAuthorization{
+					{ChainId: []byte{1}, Address: delegate, Nonce: 3, Authority: authority},
+					// authorizations for any chain are valid as well
+					{Address: delegate, Nonce: 4, Authority: authority},
+					{ChainId: []byte{5}, Address: delegate, Nonce: 5, Authority: authority},
+					// the signature could not be recovered
+					{ChainId: []byte{1}, Address: delegate, Nonce: 6},
+					// the nonce can not be incremented anymore
+					{ChainId: []byte{1}, Address: delegate, Nonce: math.MaxUint64, Authority: authority},
+				},
+			},
+		},
+	}
+
+	bigtable := &Bigtable{chainId: "1"}
+	bulkData, _, err := bigtable.TransformSetCodeAuthorizations(blk, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := reversePaddedBigtableTimestamp(blockTime)
+	txIndex := reversePaddedIndex(1, TX_PER_BLOCK_LIMIT)
+	wantValid := []bool{true, true, false, false, false}
+	var wantKeys []string
+	for j := range blk.Transactions[1].AuthorizationList {
+		authIndex := reversePaddedIndex(j, ITX_PER_TX_LIMIT)
+		wantKeys = append(wantKeys,
+			fmt.Sprintf("1:AUTH:%x:%s", txHash, authIndex),
+			fmt.Sprintf("1:I:AUTH:%x:TIME:%s:%s:%s", delegate, ts, txIndex, authIndex),
+		)
+		if j != 3 {
+			wantKeys = append(wantKeys,
+				fmt.Sprintf("1:I:AUTH:%x:TIME:%s:%s:%s", authority, ts, txIndex, authIndex),
+				fmt.Sprintf("1:I:AUTH:%x:AUTHORITY:%s:%s:%s", authority, ts, txIndex, authIndex),
+			)
+		}
+	}
+	if !slices.Equal(bulkData.Keys, wantKeys) {
+		t.Fatalf("got keys %v, want %v", bulkData.Keys, wantKeys)
+	}
+
+	for j, auth := range blk.Transactions[1].AuthorizationList {
+		if got := isValidSetCodeAuthorization(auth, big.NewInt(1)); got != wantValid[j] {
+			t.Errorf("authorization %v: got valid %v, want %v", j, got, wantValid[j])
+		}
+	}
+}
+
+func TestApprovalVerification(t *testing.T) {
+	tests := []struct {
+		name      string
+		active    bool
+		allowance []byte
+	}{
+		{name: "inactive", active: false},
+		{name: "active erc20 approval", active: true, allowance: big.NewInt(1000).Bytes()},
+		{name: "active approval for all", active: true, allowance: []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, allowance, err := decodeApprovalVerification(encodeApprovalVerification(tt.active, tt.allowance))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if active != tt.active || !bytes.Equal(allowance, tt.allowance) {
+				t.Errorf("got %v %x, want %v %x", active, allowance, tt.active, tt.allowance)
+			}
+		})
+	}
+
+	if _, _, err := decodeApprovalVerification("active:xyz"); err == nil {
+		t.Errorf("got no error for an invalid allowance")
+	}
+}
+
+func TestIsRevokedApproval(t *testing.T) {
+	spender := common.HexToAddress("0x63c0c19a282a1b52b07dd5a65b58948a07dae32b").Bytes()
+	tests := []struct {
+		name     string
+		approval *types.Eth1ApprovalIndexed
+		want     bool
+	}{
+		{name: "erc20 allowance", approval: &types.Eth1ApprovalIndexed{Type: types.ApprovalType_ERC20_APPROVAL, Spender: spender, Value: []byte{1}}, want: false},
+		{name: "erc20 zero allowance", approval: &types.Eth1ApprovalIndexed{Type: types.ApprovalType_ERC20_APPROVAL, Spender: spender}, want: true},
+		{name: "erc721 approval", approval: &types.Eth1ApprovalIndexed{Type: types.ApprovalType_ERC721_APPROVAL, Spender: spender}, want: false},
+		{name: "erc721 approval of the zero address", approval: &types.Eth1ApprovalIndexed{Type: types.ApprovalType_ERC721_APPROVAL, Spender: ZERO_ADDRESS}, want: true},
+		{name: "approval for all", approval: &types.Eth1ApprovalIndexed{Type: types.ApprovalType_APPROVAL_FOR_ALL, Spender: spender, Approved: true}, want: false},
+		{name: "revoked approval for all", approval: &types.Eth1ApprovalIndexed{Type: types.ApprovalType_APPROVAL_FOR_ALL, Spender: spender}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRevokedApproval(tt.approval); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcceptProxyUpdate(t *testing.T) {
+	implementation := common.HexToAddress("0x01").Bytes()
+	other := common.HexToAddress("0x02").Bytes()
+	beacon := common.HexToAddress("0x03").Bytes()
+
+	eip1967 := &types.Eth1ProxyImplementation{Type: types.ProxyType_EIP1967_PROXY, Implementation: implementation}
+	transparent := &types.Eth1ProxyImplementation{Type: types.ProxyType_TRANSPARENT_PROXY, Implementation: implementation, Admin: other}
+	beaconProxy := &types.Eth1ProxyImplementation{Type: types.ProxyType_BEACON_PROXY, Beacon: beacon, Implementation: implementation}
+	minimal := &types.Eth1ProxyImplementation{Type: types.ProxyType_MINIMAL_PROXY, Implementation: implementation}
+
+	upgraded := &types.Eth1ProxyImplementation{Type: types.ProxyType_EIP1967_PROXY, Implementation: implementation}
+	beaconUpgraded := &types.Eth1ProxyImplementation{Type: types.ProxyType_BEACON_PROXY, Beacon: beacon}
+	created := &types.Eth1ProxyImplementation{}
+
+	tests := []struct {
+		name                 string
+		event                *types.Eth1ProxyImplementation
+		state                *types.Eth1ProxyImplementation
+		beaconImplementation []byte
+		want                 *types.Eth1ProxyImplementation
+	}{
+		{name: "created proxy", event: created, state: minimal, want: minimal},
+		{name: "created contract that is not a proxy", event: created, state: nil, want: nil},
+		{name: "upgrade of an eip-1967 proxy", event: upgraded, state: eip1967, want: eip1967},
+		{name: "upgrade of a transparent proxy", event: upgraded, state: transparent, want: transparent},
+		{name: "upgrade to another implementation than the slot", event: upgraded, state: &types.Eth1ProxyImplementation{Type: types.ProxyType_EIP1967_PROXY, Implementation: other}, want: nil},
+		{name: "upgrade event of a contract that is not a proxy", event: upgraded, state: nil, want: nil},
+		{name: "upgrade event of a minimal proxy", event: upgraded, state: minimal, want: nil},
+		{name: "upgrade of a beacon", event: upgraded, state: nil, beaconImplementation: implementation, want: &types.Eth1ProxyImplementation{Type: types.ProxyType_UPGRADEABLE_BEACON, Implementation: implementation}},
+		{name: "upgrade event of a beacon with another implementation", event: upgraded, state: nil, beaconImplementation: other, want: nil},
+		{name: "beacon upgrade", event: beaconUpgraded, state: beaconProxy, want: beaconProxy},
+		{name: "beacon upgrade to another beacon than the slot", event: beaconUpgraded, state: &types.Eth1ProxyImplementation{Type: types.ProxyType_BEACON_PROXY, Beacon: other}, want: nil},
+		{name: "beacon upgrade event of an eip-1967 proxy", event: beaconUpgraded, state: eip1967, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := acceptProxyUpdate(tt.event, tt.state, tt.beaconImplementation)
+			if (got == nil) != (tt.want == nil) || (got != nil && !proto.Equal(got, tt.want)) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package db
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// blobSubmitters aggregates the blob transactions of a day per sender and to-address
+type blobSubmitters struct {
+	day    time.Time
+	stats  map[string]*types.BlobSubmitterStats
+	hashes map[string][][]byte
+}
+
+func newBlobSubmitters(day time.Time) *blobSubmitters {
+	return &blobSubmitters{
+		day:    day,
+		stats:  make(map[string]*types.BlobSubmitterStats),
+		hashes: make(map[string][][]byte),
+	}
+}
+
+// add adds a blob transaction to the stats of its sender and to-address
+func (b *blobSubmitters) add(tx *types.Eth1Transaction) {
+	key := fmt.Sprintf("%x:%x", tx.GetFrom(), tx.GetTo())
+	stats, ok := b.stats[key]
+	if !ok {
+		stats = &types.BlobSubmitterStats{
+			Day:       b.day,
+			Sender:    tx.GetFrom(),
+			ToAddress: tx.GetTo(),
+		}
+		b.stats[key] = stats
+	}
+
+	blobGasUsed := new(big.Int).SetUint64(tx.GetBlobGasUsed())
+	blobFee := new(big.Int).Mul(blobGasUsed, new(big.Int).SetBytes(tx.GetBlobGasPrice()))
+
+	stats.TxCount++
+	stats.BlobCount += uint64(len(tx.GetBlobVersionedHashes()))
+	stats.BlobGasUsed = stats.BlobGasUsed.Add(decimal.NewFromBigInt(blobGasUsed, 0))
+	stats.BlobFee = stats.BlobFee.Add(decimal.NewFromBigInt(blobFee, 0))
+	b.hashes[key] = append(b.hashes[key], tx.GetBlobVersionedHashes()...)
+}
+
+// measure adds the non-zero bytes of the blobs that are known to the stats
+func (b *blobSubmitters) measure(nonZeroBytes map[string]uint64) {
+	for key, stats := range b.stats {
+		for _, hash := range b.hashes[key] {
+			if n, ok := nonZeroBytes[string(hash)]; ok {
+				stats.MeasuredBlobCount++
+				stats.NonZeroBytes += n
+			}
+		}
+	}
+}
+
+// save looks up the non-zero bytes of the blobs and saves the stats of the day
+func (b *blobSubmitters) save() error {
+	hashes := make([][]byte, 0)
+	for _, h := range b.hashes {
+		hashes = append(hashes, h...)
+	}
+	nonZeroBytes, err := GetBlobNonZeroBytes(hashes)
+	if err != nil {
+		return err
+	}
+	b.measure(nonZeroBytes)
+
+	stats := make([]*types.BlobSubmitterStats, 0, len(b.stats))
+	for _, s := range b.stats {
+		stats = append(stats, s)
+	}
+	return SaveBlobSubmitterStats(b.day, stats)
+}
+
+// SaveBlobSidecarStats saves the number of non-zero bytes of blobs
+func SaveBlobSidecarStats(stats []*types.BlobSidecarStats) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	numArgs := 3
+	valueStrings := make([]string, 0, len(stats))
+	valueArgs := make([]interface{}, 0, len(stats)*numArgs)
+	for i, s := range stats {
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d)", i*numArgs+1, i*numArgs+2, i*numArgs+3))
+		valueArgs = append(valueArgs, s.BlobVersionedHash, s.Slot, s.NonZeroBytes)
+	}
+	_, err := WriterDb.Exec(fmt.Sprintf(`
+		INSERT INTO blob_sidecar_stats (blob_versioned_hash, block_slot, non_zero_bytes)
+		VALUES %s
+		ON CONFLICT (blob_versioned_hash) DO NOTHING`, strings.Join(valueStrings, ",")), valueArgs...)
+	if err != nil {
+		return fmt.Errorf("error saving stats of %v blob sidecars: %w", len(stats), err)
+	}
+	return nil
+}
+
+// GetBlobNonZeroBytes returns the number of non-zero bytes of the given blobs by versioned hash, blobs that have not
+// been measured are missing from the result
+func GetBlobNonZeroBytes(hashes [][]byte) (map[string]uint64, error) {
+	nonZeroBytes := make(map[string]uint64, len(hashes))
+
+	batchSize := 10000
+	for b := 0; b < len(hashes); b += batchSize {
+		end := b + batchSize
+		if len(hashes) < end {
+			end = len(hashes)
+		}
+
+		var stats []*types.BlobSidecarStats
+		err := WriterDb.Select(&stats, `
+			SELECT blob_versioned_hash, block_slot, non_zero_bytes
+			FROM blob_sidecar_stats
+			WHERE blob_versioned_hash = ANY($1)`, pq.ByteaArray(hashes[b:end]))
+		if err != nil {
+			return nil, fmt.Errorf("error getting non-zero bytes of %v blobs: %w", end-b, err)
+		}
+		for _, s := range stats {
+			nonZeroBytes[string(s.BlobVersionedHash)] = s.NonZeroBytes
+		}
+	}
+	return nonZeroBytes, nil
+}
+
+// SaveBlobSubmitterStats replaces the blob submitter stats of the given day
+func SaveBlobSubmitterStats(day time.Time, stats []*types.BlobSubmitterStats) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM blob_submitter_stats WHERE day = $1`, day)
+	if err != nil {
+		return fmt.Errorf("error deleting blob submitter stats of day %v: %w", day, err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO blob_submitter_stats (day, sender, to_address, tx_count, blob_count, blob_gas_used, blob_fee, measured_blob_count, non_zero_bytes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`)
+	if err != nil {
+		return fmt.Errorf("error preparing blob submitter stats insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, s := range stats {
+		_, err = stmt.Exec(day, s.Sender, s.ToAddress, s.TxCount, s.BlobCount, s.BlobGasUsed, s.BlobFee, s.MeasuredBlobCount, s.NonZeroBytes)
+		if err != nil {
+			return fmt.Errorf("error saving blob submitter stats of 0x%x to 0x%x: %w", s.Sender, s.ToAddress, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing blob submitter stats of day %v: %w", day, err)
+	}
+	return nil
+}
+
+// GetBlobSubmitterStats returns the daily blob submitter stats since the given day, ordered by day and blob fee, if an
+// address is given only the stats where it is the sender or the to-address are returned
+func GetBlobSubmitterStats(since time.Time, address []byte) ([]*types.BlobSubmitterStats, error) {
+	var stats []*types.BlobSubmitterStats
+	err := ReaderDb.Select(&stats, `
+		SELECT day, sender, to_address, tx_count, blob_count, blob_gas_used, blob_fee, measured_blob_count, non_zero_bytes
+		FROM blob_submitter_stats
+		WHERE day >= $1 AND ($2::BYTEA IS NULL OR sender = $2 OR to_address = $2)
+		ORDER BY day, blob_fee DESC`, since, address)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blob submitter stats since %v: %w", since, err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+func TestBlobSubmitters(t *testing.T) {
+	sender := []byte{0x01}
+	inbox := []byte{0x02}
+	blobGasPrice := big.NewInt(3).Bytes()
+
+	b := newBlobSubmitters(time.Date(2025, 10, 28, 0, 0, 0, 0, time.UTC))
+	b.add(&types.Eth1Transaction{From: sender, To: inbox, BlobGasUsed: 2 << 17, BlobGasPrice: blobGasPrice, BlobVersionedHashes: [][]byte{{0xa1}, {0xa2}}})
+	b.add(&types.Eth1Transaction{From: sender, To: inbox, BlobGasUsed: 1 << 17, BlobGasPrice: blobGasPrice, BlobVersionedHashes: [][]byte{{0xa3}}})
+	b.add(&types.Eth1Transaction{From: inbox, To: sender, BlobGasUsed: 1 << 17, BlobGasPrice: blobGasPrice, BlobVersionedHashes: [][]byte{{0xb1}}})
+	b.measure(map[string]uint64{
+		string([]byte{0xa1}): 100,
+		string([]byte{0xa3}): 50,
+	})
+
+	if len(b.stats) != 2 {
+		t.Fatalf("got stats of %v submitters, want 2", len(b.stats))
+	}
+
+	stats := b.stats["01:02"]
+	if stats == nil {
+		t.Fatalf("missing stats of sender 0x01 to 0x02")
+	}
+	if stats.TxCount != 2 || stats.BlobCount != 3 {
+		t.Errorf("got %v txs and %v blobs, want 2 txs and 3 blobs", stats.TxCount, stats.BlobCount)
+	}
+	if stats.BlobGasUsed.IntPart() != 3<<17 || stats.BlobFee.IntPart() != 3*3<<17 {
+		t.Errorf("got blob gas used %v and blob fee %v, want %v and %v", stats.BlobGasUsed, stats.BlobFee, 3<<17, 3*3<<17)
+	}
+	if stats.MeasuredBlobCount != 2 || stats.NonZeroBytes != 150 {
+		t.Errorf("got %v measured blobs with %v non-zero bytes, want 2 blobs with 150 bytes", stats.MeasuredBlobCount, stats.NonZeroBytes)
+	}
+
+	stats = b.stats["02:01"]
+	if stats == nil || stats.MeasuredBlobCount != 0 || stats.NonZeroBytes != 0 {
+		t.Errorf("got %+v, want unmeasured stats of sender 0x02 to 0x01", stats)
+	}
+}
@@ -537,8 +537,8 @@ func GetAllSlots(tx *sqlx.Tx) ([]uint64, error) {
 	return slots, nil
 }
 
-func SetSlotFinalizationAndStatus(slot uint64, finalized bool, status string, tx *sqlx.Tx) error {
-	_, err := tx.Exec("UPDATE blocks SET finalized = $1, status = $2 WHERE slot = $3", finalized, status, slot)
+func SetSlotFinalizationAndStatus(slot uint64, blockRoot []byte, finalized bool, status string, tx *sqlx.Tx) error {
+	_, err := tx.Exec("UPDATE blocks SET finalized = $1, status = $2 WHERE slot = $3 AND blockroot = $4", finalized, status, slot, blockRoot)
 
 	if err != nil {
 		return fmt.Errorf("error setting slot finalization and status: %w", err)
@@ -548,15 +548,16 @@ func SetSlotFinalizationAndStatus(slot uint64, finalized bool, status string, tx
 }
 
 type GetAllNonFinalizedSlotsRow struct {
-	Slot      uint64 `db:"slot"`
-	BlockRoot []byte `db:"blockroot"`
-	Finalized bool   `db:"finalized"`
-	Status    string `db:"status"`
+	Slot       uint64 `db:"slot"`
+	BlockRoot  []byte `db:"blockroot"`
+	ParentRoot []byte `db:"parentroot"`
+	Finalized  bool   `db:"finalized"`
+	Status     string `db:"status"`
 }
 
-func GetAllNonFinalizedSlots() ([]*GetAllNonFinalizedSlotsRow, error) {
+func GetAllNonFinalizedSlots(tx *sqlx.Tx) ([]*GetAllNonFinalizedSlotsRow, error) {
 	var slots []*GetAllNonFinalizedSlotsRow
-	err := WriterDb.Select(&slots, "SELECT slot, blockroot, finalized, status FROM blocks WHERE NOT finalized ORDER BY slot")
+	err := tx.Select(&slots, "SELECT slot, blockroot, parentroot, finalized, status FROM blocks WHERE NOT finalized ORDER BY slot")
 
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving all non finalized slots from the DB: %w", err)
@@ -565,6 +566,96 @@ func GetAllNonFinalizedSlots() ([]*GetAllNonFinalizedSlotsRow, error) {
 	return slots, nil
 }
 
+// GetBlockAttestationInclusions returns the validators of all attestations included in the block with the given root
+// by the slot they attested to
+func GetBlockAttestationInclusions(blockRoot []byte, tx *sqlx.Tx) (map[types.Slot][]types.ValidatorIndex, error) {
+	var attestations []struct {
+		Slot       uint64        `db:"slot"`
+		Validators pq.Int64Array `db:"validators"`
+	}
+	err := tx.Select(&attestations, "SELECT slot, validators FROM blocks_attestations WHERE block_root = $1", blockRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attestations of block %#x: %w", blockRoot, err)
+	}
+
+	inclusions := make(map[types.Slot][]types.ValidatorIndex)
+	for _, a := range attestations {
+		for _, v := range a.Validators {
+			inclusions[types.Slot(a.Slot)] = append(inclusions[types.Slot(a.Slot)], types.ValidatorIndex(v))
+		}
+	}
+	return inclusions, nil
+}
+
+// InvalidateEpochs recalculates the block statistics of the given epochs from their canonical blocks and resets
+// their participation, finalization and exported rewards, which are updated again once the epochs finalize
+func InvalidateEpochs(epochs []uint64, tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		UPDATE epochs SET
+			eligibleether = 0,
+			globalparticipationrate = 0,
+			votedether = 0,
+			finalized = false,
+			rewards_exported = false,
+			blockscount = (SELECT COUNT(*) FROM blocks WHERE blocks.epoch = epochs.epoch AND status = '1'),
+			proposerslashingscount = (SELECT COALESCE(SUM(proposerslashingscount),0) FROM blocks WHERE blocks.epoch = epochs.epoch AND status = '1'),
+			attesterslashingscount = (SELECT COALESCE(SUM(attesterslashingscount),0) FROM blocks WHERE blocks.epoch = epochs.epoch AND status = '1'),
+			attestationscount = (SELECT COALESCE(SUM(attestationscount),0) FROM blocks WHERE blocks.epoch = epochs.epoch AND status = '1'),
+			depositscount = (SELECT COALESCE(SUM(depositscount),0) FROM blocks WHERE blocks.epoch = epochs.epoch AND status = '1'),
+			withdrawalcount = (SELECT COALESCE(SUM(withdrawalcount),0) FROM blocks WHERE blocks.epoch = epochs.epoch AND status = '1'),
+			voluntaryexitscount = (SELECT COALESCE(SUM(voluntaryexitscount),0) FROM blocks WHERE blocks.epoch = epochs.epoch AND status = '1')
+		WHERE epoch = ANY($1)`, pq.Array(epochs))
+	if err != nil {
+		return fmt.Errorf("error invalidating epochs %v: %w", epochs, err)
+	}
+	return nil
+}
+
+// SaveChainReorg stores a chain reorg detected by the slot exporter, it is pending until it has been rolled back
+func SaveChainReorg(reorg *types.ChainReorg, tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		INSERT INTO chain_reorgs (ts, slot, epoch, depth, old_blockroot, new_blockroot, last_slot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		reorg.Ts, reorg.Slot, reorg.Epoch, reorg.Depth, reorg.OldBlockRoot, reorg.NewBlockRoot, reorg.LastSlot)
+	if err != nil {
+		return fmt.Errorf("error saving chain reorg at slot %v: %w", reorg.Slot, err)
+	}
+	return nil
+}
+
+// GetPendingChainReorgs returns the chain reorgs whose exported data has not been rolled back yet, oldest first
+func GetPendingChainReorgs(tx *sqlx.Tx) ([]*types.ChainReorg, error) {
+	var reorgs []*types.ChainReorg
+	err := tx.Select(&reorgs, `
+		SELECT ts, slot, epoch, depth, old_blockroot, new_blockroot, last_slot, rolled_back
+		FROM chain_reorgs
+		WHERE NOT rolled_back
+		ORDER BY ts, slot`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pending chain reorgs: %w", err)
+	}
+	return reorgs, nil
+}
+
+// SetChainReorgRolledBack marks the exported data of a chain reorg as rolled back
+func SetChainReorgRolledBack(reorg *types.ChainReorg, tx *sqlx.Tx) error {
+	_, err := tx.Exec("UPDATE chain_reorgs SET rolled_back = true WHERE ts = $1 AND slot = $2", reorg.Ts, reorg.Slot)
+	if err != nil {
+		return fmt.Errorf("error marking chain reorg at slot %v as rolled back: %w", reorg.Slot, err)
+	}
+	return nil
+}
+
+// GetOrphanedBlocks returns the orphaned blocks between the given slots (inclusive)
+func GetOrphanedBlocks(fromSlot, toSlot uint64, tx *sqlx.Tx) ([]*types.CanonBlock, error) {
+	var blocks []*types.CanonBlock
+	err := tx.Select(&blocks, "SELECT epoch, slot, blockroot, parentroot FROM blocks WHERE slot >= $1 AND slot <= $2 AND status = '3' ORDER BY slot", fromSlot, toSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving orphaned blocks between slot %v and %v: %w", fromSlot, toSlot, err)
+	}
+	return blocks, nil
+}
+
 // Get latest finalized epoch
 func GetLatestFinalizedEpoch() (uint64, error) {
 	var latestFinalized uint64
@@ -613,6 +704,143 @@ func GetPendingDeposits(pubkey []byte, limit int) ([]types.PendingDeposit, error
 	return pendingDeposit, err
 }
 
+// SaveValidatorQueuePositions replaces the current queue positions of all validators. A history entry is added for
+// validators that entered a queue or whose estimated epoch changed since the last update. The positions and the
+// history are written in one transaction, so the history always matches the positions it was derived from.
+func SaveValidatorQueuePositions(positions []*types.ValidatorQueuePosition) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous []*types.ValidatorQueuePosition
+	err = tx.Select(&previous, `SELECT pubkey, queue, est_epoch FROM validator_queue_positions`)
+	if err != nil {
+		return fmt.Errorf("error getting previous validator queue positions: %w", err)
+	}
+	previousEstimates := make(map[string]uint64, len(previous))
+	for _, p := range previous {
+		previousEstimates[string(p.Queue)+string(p.Pubkey)] = p.EstEpoch
+	}
+
+	history := make([]*types.ValidatorQueuePosition, 0)
+	for _, p := range positions {
+		if estEpoch, exists := previousEstimates[string(p.Queue)+string(p.Pubkey)]; !exists || estEpoch != p.EstEpoch {
+			history = append(history, p)
+		}
+	}
+
+	_, err = tx.Exec(`TRUNCATE TABLE validator_queue_positions`)
+	if err != nil {
+		return fmt.Errorf("error clearing validator queue positions: %w", err)
+	}
+
+	batchSize := 5000
+	for b := 0; b < len(positions); b += batchSize {
+		start := b
+		end := b + batchSize
+		if len(positions) < end {
+			end = len(positions)
+		}
+
+		valueStrings := make([]string, 0, end-start)
+		valueArgs := make([]interface{}, 0, (end-start)*6)
+		for i, p := range positions[start:end] {
+			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", i*6+1, i*6+2, i*6+3, i*6+4, i*6+5, i*6+6))
+			valueArgs = append(valueArgs, []byte(p.Pubkey), string(p.Queue), p.ValidatorIndex, p.Position, p.BalanceAhead, p.EstEpoch)
+		}
+
+		_, err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO validator_queue_positions (pubkey, queue, validator_index, position, balance_ahead, est_epoch) 
+			VALUES %s`, strings.Join(valueStrings, ",")), valueArgs...)
+		if err != nil {
+			return fmt.Errorf("error inserting validator queue positions: %w", err)
+		}
+	}
+
+	for b := 0; b < len(history); b += batchSize {
+		start := b
+		end := b + batchSize
+		if len(history) < end {
+			end = len(history)
+		}
+
+		valueStrings := make([]string, 0, end-start)
+		valueArgs := make([]interface{}, 0, (end-start)*7)
+		for i, p := range history[start:end] {
+			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)", i*7+1, i*7+2, i*7+3, i*7+4, i*7+5, i*7+6, i*7+7))
+			valueArgs = append(valueArgs, p.Ts.UTC(), []byte(p.Pubkey), string(p.Queue), p.ValidatorIndex, p.Position, p.BalanceAhead, p.EstEpoch)
+		}
+
+		_, err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO validator_queue_positions_history (ts, pubkey, queue, validator_index, position, balance_ahead, est_epoch) 
+			VALUES %s 
+			ON CONFLICT (pubkey, queue, ts) DO NOTHING`, strings.Join(valueStrings, ",")), valueArgs...)
+		if err != nil {
+			return fmt.Errorf("error inserting validator queue position history: %w", err)
+		}
+	}
+
+	// validators do not stay in a queue for months, older entries are of no use anymore
+	_, err = tx.Exec(`DELETE FROM validator_queue_positions_history WHERE ts < now() - interval '90 days'`)
+	if err != nil {
+		return fmt.Errorf("error deleting old validator queue position history: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetValidatorQueuePositions returns the current queue positions of the validators
+func GetValidatorQueuePositions(pubkeys [][]byte) ([]*types.ValidatorQueuePosition, error) {
+	var positions []*types.ValidatorQueuePosition
+	err := ReaderDb.Select(&positions, `
+		SELECT pubkey, queue, validator_index, position, balance_ahead, est_epoch 
+		FROM validator_queue_positions 
+		WHERE pubkey = ANY($1) 
+		ORDER BY queue, position`, pq.ByteaArray(pubkeys))
+	if err != nil {
+		return nil, fmt.Errorf("error getting validator queue positions: %w", err)
+	}
+	return positions, nil
+}
+
+// GetValidatorQueuePositionHistory returns the changes of the estimated epoch of the validators, newest first
+func GetValidatorQueuePositionHistory(pubkeys [][]byte, limit uint64) ([]*types.ValidatorQueuePosition, error) {
+	var history []*types.ValidatorQueuePosition
+	err := ReaderDb.Select(&history, `
+		SELECT ts, pubkey, queue, validator_index, position, balance_ahead, est_epoch 
+		FROM validator_queue_positions_history 
+		WHERE pubkey = ANY($1) 
+		ORDER BY ts DESC 
+		LIMIT $2`, pq.ByteaArray(pubkeys), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting validator queue position history: %w", err)
+	}
+	return history, nil
+}
+
+// GetInitialValidatorQueueEstimates returns the estimated epoch of the validators when they entered their current
+// queue, the key of the map is the queue name followed by the public key
+func GetInitialValidatorQueueEstimates(pubkeys [][]byte) (map[string]uint64, error) {
+	var initial []*types.ValidatorQueuePosition
+	err := ReaderDb.Select(&initial, `
+		SELECT DISTINCT ON (h.pubkey, h.queue) h.pubkey, h.queue, h.est_epoch 
+		FROM validator_queue_positions_history h 
+		INNER JOIN validator_queue_positions p ON p.pubkey = h.pubkey AND p.queue = h.queue 
+		WHERE h.pubkey = ANY($1) 
+		ORDER BY h.pubkey, h.queue, h.ts`, pq.ByteaArray(pubkeys))
+	if err != nil {
+		return nil, fmt.Errorf("error getting initial validator queue estimates: %w", err)
+	}
+
+	estimates := make(map[string]uint64, len(initial))
+	for _, p := range initial {
+		estimates[string(p.Queue)+string(p.Pubkey)] = p.EstEpoch
+	}
+	return estimates, nil
+}
+
 // GetValidatorDeposits will return eth1- and eth2-deposits for a public key from the database
 func GetValidatorDeposits(publicKey []byte) (*types.ValidatorDeposits, error) {
 	deposits := &types.ValidatorDeposits{}
@@ -2460,6 +2688,109 @@ func GetEpochWithdrawals(epoch uint64) ([]*types.WithdrawalsNotification, error)
 	return withdrawals, nil
 }
 
+// GetEpochConsolidationRequests returns the consolidation requests that were included in execution blocks of the epoch.
+// Requests whose source and target are the same validator are requests to switch to compounding credentials and
+// are skipped.
+func GetEpochConsolidationRequests(epoch uint64) ([]*types.ConsolidationRequestNotification, error) {
+	var requests []*types.ConsolidationRequestNotification
+
+	err := ReaderDb.Select(&requests, `
+	SELECT 
+		r.block_number, 
+		r.tx_hash, 
+		r.source_address, 
+		r.source_pubkey, 
+		r.target_pubkey, 
+		COALESCE(sv.validatorindex, -1) AS source_index, 
+		COALESCE(tv.validatorindex, -1) AS target_index
+	FROM eth1_consolidation_requests r
+	LEFT JOIN validators sv ON sv.pubkey = r.source_pubkey
+	LEFT JOIN validators tv ON tv.pubkey = r.target_pubkey
+	WHERE r.block_ts >= $1 AND r.block_ts < $2 AND r.source_pubkey != r.target_pubkey
+	ORDER BY r.block_number, r.tx_index, r.itx_index`, utils.EpochToTime(epoch).UTC(), utils.EpochToTime(epoch+1).UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error getting eth1_consolidation_requests for epoch: %d: %w", epoch, err)
+	}
+
+	return requests, nil
+}
+
+// GetEpochProcessedConsolidationRequests returns the consolidation requests that were processed by the consensus layer
+// in the epoch, rejected requests have a status other than completed
+func GetEpochProcessedConsolidationRequests(epoch uint64) ([]*types.ConsolidationRequestNotification, error) {
+	var requests []*types.ConsolidationRequestNotification
+
+	err := ReaderDb.Select(&requests, `
+	SELECT 
+		c.slot_processed AS slot, 
+		c.source_pubkey, 
+		c.target_pubkey, 
+		COALESCE(sv.validatorindex, -1) AS source_index, 
+		COALESCE(tv.validatorindex, -1) AS target_index, 
+		COALESCE(c.amount_consolidated, 0) AS amount, 
+		c.status
+	FROM blocks_consolidation_requests_v2 c
+	INNER JOIN blocks b ON b.blockroot = c.block_processed_root AND b.status = '1'
+	LEFT JOIN validators sv ON sv.pubkey = c.source_pubkey
+	LEFT JOIN validators tv ON tv.pubkey = c.target_pubkey
+	WHERE c.slot_processed >= $1 AND c.slot_processed < $2 
+	ORDER BY c.slot_processed, c.index_processed`, epoch*utils.Config.Chain.ClConfig.SlotsPerEpoch, (epoch+1)*utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blocks_consolidation_requests_v2 for epoch: %d: %w", epoch, err)
+	}
+
+	return requests, nil
+}
+
+// GetEpochSwitchToCompoundingRequests returns the validators that successfully switched to 0x02 credentials in the epoch
+func GetEpochSwitchToCompoundingRequests(epoch uint64) ([]*types.SwitchToCompoundingNotification, error) {
+	var requests []*types.SwitchToCompoundingNotification
+
+	// TODO: remove v1 table dependency once eth1id resolving is available
+	err := ReaderDb.Select(&requests, `
+	SELECT 
+		s.slot_processed AS slot, 
+		COALESCE(v1.address, decode('0000000000000000000000000000000000000000', 'hex')) AS address, 
+		s.validator_pubkey AS pubkey, 
+		COALESCE(v.validatorindex, -1) AS validator_index
+	FROM blocks_switch_to_compounding_requests_v2 s
+	INNER JOIN blocks b ON b.blockroot = s.block_processed_root AND b.status = '1'
+	LEFT JOIN validators v ON v.pubkey = s.validator_pubkey
+	LEFT JOIN blocks_switch_to_compounding_requests v1 ON (s.slot_processed = v1.block_slot AND s.block_processed_root = v1.block_root AND s.index_processed = v1.request_index)
+	WHERE s.slot_processed >= $1 AND s.slot_processed < $2 AND s.status = 'completed'
+	ORDER BY s.slot_processed, s.index_processed`, epoch*utils.Config.Chain.ClConfig.SlotsPerEpoch, (epoch+1)*utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blocks_switch_to_compounding_requests_v2 for epoch: %d: %w", epoch, err)
+	}
+
+	return requests, nil
+}
+
+// GetEpochWithdrawalRequests returns the execution layer triggered withdrawal requests (EIP-7002) processed in the epoch
+func GetEpochWithdrawalRequests(epoch uint64) ([]*types.WithdrawalRequestNotification, error) {
+	var requests []*types.WithdrawalRequestNotification
+
+	// TODO: remove v1 table dependency once eth1id resolving is available
+	err := ReaderDb.Select(&requests, `
+	SELECT 
+		w.slot_processed AS slot, 
+		COALESCE(v1.source_address, decode('0000000000000000000000000000000000000000', 'hex')) AS source_address, 
+		w.validator_pubkey AS pubkey, 
+		COALESCE(v.validatorindex, -1) AS validator_index, 
+		w.amount
+	FROM blocks_withdrawal_requests_v2 w
+	INNER JOIN blocks b ON b.blockroot = w.block_processed_root AND b.status = '1'
+	LEFT JOIN validators v ON v.pubkey = w.validator_pubkey
+	LEFT JOIN blocks_withdrawal_requests v1 ON (w.slot_processed = v1.block_slot AND w.block_processed_root = v1.block_root AND w.index_processed = v1.request_index)
+	WHERE w.slot_processed >= $1 AND w.slot_processed < $2 AND w.status = 'completed'
+	ORDER BY w.slot_processed, w.index_processed`, epoch*utils.Config.Chain.ClConfig.SlotsPerEpoch, (epoch+1)*utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blocks_withdrawal_requests_v2 for epoch: %d: %w", epoch, err)
+	}
+
+	return requests, nil
+}
+
 func GetValidatorWithdrawals(validator uint64, limit uint64, offset uint64, orderBy string, orderDir string) ([]*types.Withdrawals, error) {
 	var withdrawals []*types.Withdrawals
 	if limit == 0 {
@@ -90,8 +90,9 @@ func GetUserIdByApiKey(apiKey string) (*types.UserWithPremium, error) {
 				WHEN 'plankton'       THEN  9
 				ELSE                       10  -- For any other product_id values
 			END, id desc limit 1
-		) FROM users 
-		WHERE api_key = $1`, apiKey)
+		) FROM api_keys k
+		INNER JOIN users ON users.id = k.user_id
+		WHERE k.api_key = $1 AND k.valid_until > NOW()`, apiKey)
 	err := row.Scan(&data.ID, &data.Product)
 	if err != nil {
 		return nil, err
@@ -178,6 +179,10 @@ func CreateAPIKey(userID uint64) error {
 	if err != nil {
 		return err
 	}
+	_, err = tx.Exec("INSERT INTO api_keys (user_id, api_key, changed_at) VALUES ($1, $2, NOW())", userID, key)
+	if err != nil {
+		return err
+	}
 
 	err = tx.Commit()
 	if err != nil {
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// SaveMempoolTransactions saves the changes of a mempool snapshot in a single db transaction: the transactions seen in
+// the mempool and the transactions they replaced. A transaction that is already known is set back to pending as it has
+// reappeared in the mempool.
+func SaveMempoolTransactions(txs, replaced []*types.MempoolHistoryTransaction) error {
+	if len(txs) == 0 && len(replaced) == 0 {
+		return nil
+	}
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	batchSize := 1000
+	for b := 0; b < len(txs); b += batchSize {
+		start := b
+		end := b + batchSize
+		if len(txs) < end {
+			end = len(txs)
+		}
+
+		numArgs := 10
+		valueStrings := make([]string, 0, end-start)
+		valueArgs := make([]interface{}, 0, (end-start)*numArgs)
+		for i, t := range txs[start:end] {
+			placeholders := make([]string, 0, numArgs)
+			for j := 1; j <= numArgs; j++ {
+				placeholders = append(placeholders, fmt.Sprintf("$%d", i*numArgs+j))
+			}
+			valueStrings = append(valueStrings, fmt.Sprintf("(%s)", strings.Join(placeholders, ", ")))
+			valueArgs = append(valueArgs, t.Hash, t.Sender, t.Nonce, t.To, t.Value, t.Gas, t.GasPrice, t.GasTipCap, t.FirstSeen, t.LastSeen)
+		}
+
+		_, err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO mempool_transactions (hash, sender, nonce, to_address, value, gas, gas_price, gas_tip_cap, first_seen, last_seen)
+			VALUES %s
+			ON CONFLICT (hash) DO UPDATE SET
+				last_seen = GREATEST(mempool_transactions.last_seen, excluded.last_seen),
+				status = 'pending',
+				replaced_by = NULL,
+				block_number = NULL,
+				block_ts = NULL`, strings.Join(valueStrings, ",")), valueArgs...)
+		if err != nil {
+			return fmt.Errorf("error inserting mempool transactions: %w", err)
+		}
+	}
+
+	err = updateMempoolTransactionsStatus(tx, replaced)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing mempool transactions: %w", err)
+	}
+	return nil
+}
+
+// UpdateMempoolTransactionsLastSeen sets the time the transactions have last been seen in the mempool
+func UpdateMempoolTransactionsLastSeen(hashes [][]byte, ts time.Time) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	_, err := WriterDb.Exec(`
+		UPDATE mempool_transactions SET last_seen = $2
+		WHERE hash = ANY($1) AND status = 'pending'`, pq.ByteaArray(hashes), ts)
+	if err != nil {
+		return fmt.Errorf("error updating last seen of %v mempool transactions: %w", len(hashes), err)
+	}
+	return nil
+}
+
+// UpdateMempoolTransactionsStatus saves the status of transactions that have left the mempool
+func UpdateMempoolTransactionsStatus(txs []*types.MempoolHistoryTransaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = updateMempoolTransactionsStatus(tx, txs)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing mempool transaction status updates: %w", err)
+	}
+	return nil
+}
+
+func updateMempoolTransactionsStatus(tx *sqlx.Tx, txs []*types.MempoolHistoryTransaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	stmt, err := tx.Prepare(`
+		UPDATE mempool_transactions SET status = $2, replaced_by = $3, block_number = $4, block_ts = $5, last_seen = GREATEST(last_seen, $6)
+		WHERE hash = $1`)
+	if err != nil {
+		return fmt.Errorf("error preparing mempool transaction status update: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, t := range txs {
+		_, err = stmt.Exec(t.Hash, t.Status, t.ReplacedBy, t.BlockNumber, t.BlockTs, t.LastSeen)
+		if err != nil {
+			return fmt.Errorf("error updating status of mempool transaction 0x%x: %w", t.Hash, err)
+		}
+	}
+	return nil
+}
+
+// GetPendingMempoolTransactions returns the transactions that have not left the mempool when they were last seen
+func GetPendingMempoolTransactions() ([]*types.MempoolHistoryTransaction, error) {
+	var txs []*types.MempoolHistoryTransaction
+	err := WriterDb.Select(&txs, `
+		SELECT hash, sender, nonce, to_address, value, gas, gas_price, gas_tip_cap, first_seen, last_seen, status, replaced_by, block_number, block_ts
+		FROM mempool_transactions
+		WHERE status = 'pending'`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting pending mempool transactions: %w", err)
+	}
+	return txs, nil
+}
+
+// GetMempoolTransactionHistory returns the mempool history of the transaction together with all transactions of its
+// sender using the same nonce, it returns nil if the transaction has not been recorded
+func GetMempoolTransactionHistory(hash []byte) (*types.MempoolTxHistory, error) {
+	history := &types.MempoolTxHistory{
+		Transaction: &types.MempoolHistoryTransaction{},
+	}
+	err := ReaderDb.Get(history.Transaction, `
+		SELECT hash, sender, nonce, to_address, value, gas, gas_price, gas_tip_cap, first_seen, last_seen, status, replaced_by, block_number, block_ts
+		FROM mempool_transactions
+		WHERE hash = $1`, hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting mempool history of tx 0x%x: %w", hash, err)
+	}
+
+	err = ReaderDb.Select(&history.Replacements, `
+		SELECT hash, sender, nonce, to_address, value, gas, gas_price, gas_tip_cap, first_seen, last_seen, status, replaced_by, block_number, block_ts
+		FROM mempool_transactions
+		WHERE sender = $1 AND nonce = $2
+		ORDER BY first_seen, hash`, history.Transaction.Sender, history.Transaction.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("error getting mempool replacements of tx 0x%x: %w", hash, err)
+	}
+	return history, nil
+}
+
+// GetMempoolWaitTimes returns the median and 90th percentile of the time mined transactions with a max priority fee
+// waited in the mempool since the given time, grouped by their max priority fee rounded to 0.1 Gwei
+func GetMempoolWaitTimes(since time.Time) ([]*types.MempoolWaitTime, error) {
+	var waitTimes []*types.MempoolWaitTime
+	err := ReaderDb.Select(&waitTimes, `
+		SELECT
+			LEAST(ROUND(gas_tip_cap / 1e9, 1), 10) AS tip_gwei,
+			COUNT(*) AS count,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM block_ts - first_seen)) AS median_wait,
+			PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM block_ts - first_seen)) AS p90_wait
+		FROM mempool_transactions
+		WHERE status = 'mined' AND block_ts >= $1 AND block_ts >= first_seen AND gas_tip_cap IS NOT NULL
+		GROUP BY 1
+		HAVING COUNT(*) >= 10
+		ORDER BY 1`, since)
+	if err != nil {
+		return nil, fmt.Errorf("error getting mempool wait times since %v: %w", since, err)
+	}
+	return waitTimes, nil
+}
+
+// DeleteMempoolTransactionsBefore deletes the transactions that have last been seen before the given time
+func DeleteMempoolTransactionsBefore(ts time.Time) (int64, error) {
+	res, err := WriterDb.Exec(`DELETE FROM mempool_transactions WHERE last_seen < $1`, ts)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting mempool transactions last seen before %v: %w", ts, err)
+	}
+	return res.RowsAffected()
+}
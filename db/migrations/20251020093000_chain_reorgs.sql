@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS chain_reorgs (
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    slot INT NOT NULL,
+    epoch INT NOT NULL,
+    depth INT NOT NULL, -- number of orphaned blocks
+    old_blockroot BYTEA NOT NULL,
+    new_blockroot BYTEA, -- null if the slot is missed on the canonical chain
+    last_slot INT NOT NULL, -- latest exported slot when the reorg was detected
+    rolled_back BOOLEAN NOT NULL DEFAULT false, -- whether the exported data up to last_slot has been rolled back
+    PRIMARY KEY (ts, slot)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE chain_reorgs;
+
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE users_webhooks ADD COLUMN IF NOT EXISTS secret TEXT;
+
+ALTER TABLE notification_queue ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE notification_queue ADD COLUMN IF NOT EXISTS next_attempt TIMESTAMP WITHOUT TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS users_webhooks_deliveries (
+    id BIGSERIAL NOT NULL,
+    webhook_id INT NOT NULL,
+    user_id INT NOT NULL,
+    notification_id INT NOT NULL, -- id of the notification_queue entry
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    attempt INT NOT NULL,
+    event_name TEXT NOT NULL,
+    request JSONB NOT NULL,
+    status_code INT, -- null if no response was received
+    latency_ms INT NOT NULL,
+    response_snippet TEXT,
+    error TEXT,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_webhooks_deliveries_webhook_ts ON users_webhooks_deliveries (webhook_id, ts DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS users_webhooks_deliveries;
+
+ALTER TABLE notification_queue DROP COLUMN IF EXISTS next_attempt;
+ALTER TABLE notification_queue DROP COLUMN IF EXISTS attempts;
+
+ALTER TABLE users_webhooks DROP COLUMN IF EXISTS secret;
+
+-- +goose StatementEnd
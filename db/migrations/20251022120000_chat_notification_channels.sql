@@ -0,0 +1,16 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE notification_channels ADD VALUE IF NOT EXISTS 'webhook_slack';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TYPE notification_channels ADD VALUE IF NOT EXISTS 'telegram';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TYPE notification_channels ADD VALUE IF NOT EXISTS 'matrix';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'values of the notification_channels enum can not be dropped, the slack, telegram and matrix channels are kept';
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE users_subscriptions ADD COLUMN IF NOT EXISTS digest_mode TEXT NOT NULL DEFAULT 'immediate';
+
+CREATE TABLE IF NOT EXISTS users_notification_settings (
+    user_id INT NOT NULL,
+    time_zone TEXT NOT NULL DEFAULT 'UTC',
+    quiet_hours_start SMALLINT, -- minutes after midnight in the time zone of the user, null if quiet hours are disabled
+    quiet_hours_end SMALLINT,
+    PRIMARY KEY (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS notification_digest_entries (
+    id BIGSERIAL NOT NULL,
+    user_id INT NOT NULL,
+    subscription_id INT NOT NULL,
+    event_name TEXT NOT NULL,
+    epoch INT NOT NULL,
+    title TEXT NOT NULL,
+    info TEXT NOT NULL,
+    created TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    flush_after TIMESTAMP WITHOUT TIME ZONE NOT NULL, -- the entry is sent with the next digest of the user after this time
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_digest_entries_user_flush ON notification_digest_entries (user_id, flush_after);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_digest_entries;
+DROP TABLE IF EXISTS users_notification_settings;
+ALTER TABLE users_subscriptions DROP COLUMN IF EXISTS digest_mode;
+-- +goose StatementEnd
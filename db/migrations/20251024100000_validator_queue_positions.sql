@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS validator_queue_positions (
+    pubkey BYTEA NOT NULL,
+    queue TEXT NOT NULL, -- activation or exit
+    validator_index INT,
+    position INT NOT NULL,
+    balance_ahead BIGINT NOT NULL,
+    est_epoch INT NOT NULL,
+    PRIMARY KEY (pubkey, queue)
+);
+
+CREATE TABLE IF NOT EXISTS validator_queue_positions_history (
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    pubkey BYTEA NOT NULL,
+    queue TEXT NOT NULL,
+    validator_index INT,
+    position INT NOT NULL,
+    balance_ahead BIGINT NOT NULL,
+    est_epoch INT NOT NULL,
+    PRIMARY KEY (pubkey, queue, ts)
+);
+
+CREATE INDEX IF NOT EXISTS idx_validator_queue_positions_history_ts ON validator_queue_positions_history (ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS validator_queue_positions_history;
+DROP TABLE IF EXISTS validator_queue_positions;
+-- +goose StatementEnd
@@ -0,0 +1,88 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS slasher_attestations (
+    data_root BYTEA NOT NULL,
+    signature BYTEA NOT NULL,
+    attesting_indices INTEGER[] NOT NULL,
+    slot INT NOT NULL,
+    committeeindex INT NOT NULL,
+    beaconblockroot BYTEA NOT NULL,
+    source_epoch INT NOT NULL,
+    source_root BYTEA NOT NULL,
+    target_epoch INT NOT NULL,
+    target_root BYTEA NOT NULL,
+    PRIMARY KEY (data_root, signature)
+);
+
+CREATE INDEX IF NOT EXISTS idx_slasher_attestations_target_epoch ON slasher_attestations (target_epoch);
+CREATE INDEX IF NOT EXISTS idx_slasher_attestations_attesting_indices ON slasher_attestations USING GIN (attesting_indices);
+
+CREATE TABLE IF NOT EXISTS slasher_proposals (
+    slot INT NOT NULL,
+    proposerindex INT NOT NULL,
+    block_root BYTEA NOT NULL,
+    parentroot BYTEA NOT NULL,
+    stateroot BYTEA NOT NULL,
+    bodyroot BYTEA NOT NULL,
+    signature BYTEA NOT NULL,
+    PRIMARY KEY (slot, proposerindex, block_root)
+);
+
+CREATE TABLE IF NOT EXISTS slasher_attester_slashings (
+    detected_slot INT NOT NULL,
+    detected_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    type TEXT NOT NULL, -- double_vote or surround_vote
+    validators INTEGER[] NOT NULL, -- the validators that are slashable by the attestations
+    attestation1_data_root BYTEA NOT NULL,
+    attestation1_indices INTEGER[] NOT NULL,
+    attestation1_signature BYTEA NOT NULL,
+    attestation1_slot INT NOT NULL,
+    attestation1_index INT NOT NULL,
+    attestation1_beaconblockroot BYTEA NOT NULL,
+    attestation1_source_epoch INT NOT NULL,
+    attestation1_source_root BYTEA NOT NULL,
+    attestation1_target_epoch INT NOT NULL,
+    attestation1_target_root BYTEA NOT NULL,
+    attestation2_data_root BYTEA NOT NULL,
+    attestation2_indices INTEGER[] NOT NULL,
+    attestation2_signature BYTEA NOT NULL,
+    attestation2_slot INT NOT NULL,
+    attestation2_index INT NOT NULL,
+    attestation2_beaconblockroot BYTEA NOT NULL,
+    attestation2_source_epoch INT NOT NULL,
+    attestation2_source_root BYTEA NOT NULL,
+    attestation2_target_epoch INT NOT NULL,
+    attestation2_target_root BYTEA NOT NULL,
+    PRIMARY KEY (attestation1_signature, attestation2_signature)
+);
+
+CREATE INDEX IF NOT EXISTS idx_slasher_attester_slashings_detected_slot ON slasher_attester_slashings (detected_slot);
+
+CREATE TABLE IF NOT EXISTS slasher_proposer_slashings (
+    detected_slot INT NOT NULL,
+    detected_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    proposerindex INT NOT NULL,
+    header1_slot INT NOT NULL,
+    header1_parentroot BYTEA NOT NULL,
+    header1_stateroot BYTEA NOT NULL,
+    header1_bodyroot BYTEA NOT NULL,
+    header1_signature BYTEA NOT NULL,
+    header2_slot INT NOT NULL,
+    header2_parentroot BYTEA NOT NULL,
+    header2_stateroot BYTEA NOT NULL,
+    header2_bodyroot BYTEA NOT NULL,
+    header2_signature BYTEA NOT NULL,
+    PRIMARY KEY (header1_signature, header2_signature)
+);
+
+CREATE INDEX IF NOT EXISTS idx_slasher_proposer_slashings_detected_slot ON slasher_proposer_slashings (detected_slot);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS slasher_proposer_slashings;
+DROP TABLE IF EXISTS slasher_attester_slashings;
+DROP TABLE IF EXISTS slasher_proposals;
+DROP TABLE IF EXISTS slasher_attestations;
+-- +goose StatementEnd
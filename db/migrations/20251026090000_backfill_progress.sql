@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS backfill_jobs (
+    id SERIAL PRIMARY KEY,
+    start_epoch INT NOT NULL,
+    end_epoch INT NOT NULL,
+    created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_ts TIMESTAMP WITHOUT TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS backfill_units (
+    job_id INT NOT NULL REFERENCES backfill_jobs (id) ON DELETE CASCADE,
+    start_epoch INT NOT NULL,
+    end_epoch INT NOT NULL,
+    next_epoch INT NOT NULL, -- first epoch of the unit that has not been exported yet
+    stores TEXT[] NOT NULL, -- stores that had gaps in the unit when it was planned
+    status TEXT NOT NULL DEFAULT 'pending', -- pending, done or failed
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT,
+    updated_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (job_id, start_epoch)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS backfill_units;
+DROP TABLE IF EXISTS backfill_jobs;
+-- +goose StatementEnd
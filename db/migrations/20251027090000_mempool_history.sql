@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS mempool_transactions (
+    hash BYTEA NOT NULL PRIMARY KEY,
+    sender BYTEA NOT NULL,
+    nonce BIGINT NOT NULL,
+    to_address BYTEA,
+    value NUMERIC NOT NULL,
+    gas BIGINT NOT NULL,
+    gas_price NUMERIC NOT NULL, -- gas price of legacy transactions or max fee per gas
+    gas_tip_cap NUMERIC, -- max priority fee per gas, null for legacy transactions
+    first_seen TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    last_seen TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending', -- pending, mined, replaced, cancelled or dropped
+    replaced_by BYTEA, -- hash of the transaction with the same sender and nonce that replaced this one, if it was seen
+    block_number BIGINT,
+    block_ts TIMESTAMP WITHOUT TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_mempool_transactions_sender_nonce ON mempool_transactions (sender, nonce);
+CREATE INDEX IF NOT EXISTS idx_mempool_transactions_pending ON mempool_transactions (last_seen) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_mempool_transactions_mined ON mempool_transactions (block_ts) WHERE status = 'mined';
+CREATE INDEX IF NOT EXISTS idx_mempool_transactions_last_seen ON mempool_transactions (last_seen);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mempool_transactions;
+-- +goose StatementEnd
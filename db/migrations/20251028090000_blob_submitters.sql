@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS blob_sidecar_stats (
+    blob_versioned_hash BYTEA NOT NULL PRIMARY KEY,
+    block_slot INT NOT NULL,
+    non_zero_bytes INT NOT NULL -- number of non-zero bytes of the blob, used to estimate how well it is filled
+);
+
+CREATE TABLE IF NOT EXISTS blob_submitter_stats (
+    day DATE NOT NULL,
+    sender BYTEA NOT NULL,
+    to_address BYTEA NOT NULL,
+    tx_count INT NOT NULL,
+    blob_count INT NOT NULL,
+    blob_gas_used NUMERIC NOT NULL,
+    blob_fee NUMERIC NOT NULL, -- blob fees paid in wei
+    measured_blob_count INT NOT NULL, -- number of blobs with known non-zero bytes
+    non_zero_bytes BIGINT NOT NULL, -- sum of the non-zero bytes of the measured blobs
+    PRIMARY KEY (day, sender, to_address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blob_submitter_stats_sender ON blob_submitter_stats (sender, day);
+CREATE INDEX IF NOT EXISTS idx_blob_submitter_stats_to_address ON blob_submitter_stats (to_address, day);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS blob_submitter_stats;
+DROP TABLE IF EXISTS blob_sidecar_stats;
+-- +goose StatementEnd
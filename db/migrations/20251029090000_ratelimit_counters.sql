@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS ratelimit_counters (
+    key TEXT NOT NULL PRIMARY KEY, -- same keys as in redis: rl:c:s:*, rl:c:h:*, rl:c:m:* and rl:s:*
+    value BIGINT NOT NULL,
+    expire_at TIMESTAMP WITHOUT TIME ZONE -- NULL if the counter does not expire
+);
+
+CREATE INDEX IF NOT EXISTS idx_ratelimit_counters_expire_at ON ratelimit_counters (expire_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ratelimit_counters;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE api_statistics ADD COLUMN IF NOT EXISTS blocked INT NOT NULL DEFAULT 0; -- requests that were answered with 429
+
+CREATE TABLE IF NOT EXISTS api_quota_alerts (
+    user_id INT NOT NULL,
+    api_key VARCHAR(256) NOT NULL,
+    bucket VARCHAR(20) NOT NULL,
+    month DATE NOT NULL,
+    used BIGINT NOT NULL, -- requests of the key in the month when the alert was sent
+    quota BIGINT NOT NULL,
+    sent_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, api_key, bucket, month)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_quota_alerts;
+ALTER TABLE api_statistics DROP COLUMN IF EXISTS blocked;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS validator_export_jobs (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    validators INT[] NOT NULL,
+    source TEXT NOT NULL, -- balance or income
+    fields TEXT[] NOT NULL,
+    format TEXT NOT NULL, -- csv or parquet
+    start_epoch INT NOT NULL,
+    end_epoch INT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending', -- pending, running, finished or failed
+    error TEXT,
+    object_key TEXT, -- key of the finished file in the object storage
+    row_count BIGINT NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITHOUT TIME ZONE,
+    heartbeat_at TIMESTAMP WITHOUT TIME ZONE, -- updated by the worker while the job is running
+    finished_at TIMESTAMP WITHOUT TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_validator_export_jobs_user_id ON validator_export_jobs (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_validator_export_jobs_status ON validator_export_jobs (created_at) WHERE status IN ('pending', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS validator_export_jobs;
+-- +goose StatementEnd
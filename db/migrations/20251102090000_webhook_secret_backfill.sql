@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- webhooks created before payloads were signed get a random hex secret of the same length as new webhooks
+UPDATE users_webhooks SET secret = replace(gen_random_uuid()::text || gen_random_uuid()::text, '-', '') WHERE secret IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+SELECT 'secrets are kept on down migration';
+
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- telegram chats and matrix rooms only receive notifications once they have been verified, the verification token
+-- is sent to the bot from the chat (telegram) or posted in the room (matrix)
+ALTER TABLE users_webhooks ADD COLUMN IF NOT EXISTS verification_token TEXT;
+ALTER TABLE users_webhooks ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP WITHOUT TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_webhooks_verification_token ON users_webhooks (verification_token);
+
+-- existing chats and rooms were never verified and have to be linked again
+UPDATE users_webhooks SET verification_token = replace(gen_random_uuid()::text, '-', '') WHERE destination IN ('telegram', 'matrix');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_users_webhooks_verification_token;
+
+ALTER TABLE users_webhooks DROP COLUMN IF EXISTS verified_at;
+ALTER TABLE users_webhooks DROP COLUMN IF EXISTS verification_token;
+
+-- +goose StatementEnd
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// SaveSlasherAttestations adds the attestations to the history of the slasher and returns the ones that were not part
+// of it yet
+func SaveSlasherAttestations(attestations []*types.SlasherAttestation, tx *sqlx.Tx) ([]*types.SlasherAttestation, error) {
+	type attestationKey struct {
+		DataRoot  []byte `db:"data_root"`
+		Signature []byte `db:"signature"`
+	}
+	inserted := make(map[string]bool, len(attestations))
+
+	batchSize := 1000
+	for b := 0; b < len(attestations); b += batchSize {
+		start := b
+		end := b + batchSize
+		if len(attestations) < end {
+			end = len(attestations)
+		}
+
+		valueStrings := make([]string, 0, end-start)
+		valueArgs := make([]interface{}, 0, (end-start)*10)
+		for i, a := range attestations[start:end] {
+			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", i*10+1, i*10+2, i*10+3, i*10+4, i*10+5, i*10+6, i*10+7, i*10+8, i*10+9, i*10+10))
+			valueArgs = append(valueArgs, a.DataRoot, a.Signature, pq.Array(a.AttestingIndices), a.Data.Slot, a.Data.CommitteeIndex, a.Data.BeaconBlockRoot, a.Data.Source.Epoch, a.Data.Source.Root, a.Data.Target.Epoch, a.Data.Target.Root)
+		}
+
+		var keys []attestationKey
+		err := tx.Select(&keys, fmt.Sprintf(`
+			INSERT INTO slasher_attestations (data_root, signature, attesting_indices, slot, committeeindex, beaconblockroot, source_epoch, source_root, target_epoch, target_root)
+			VALUES %s
+			ON CONFLICT (data_root, signature) DO NOTHING
+			RETURNING data_root, signature`, strings.Join(valueStrings, ",")), valueArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("error inserting slasher attestations: %w", err)
+		}
+		for _, key := range keys {
+			inserted[string(key.DataRoot)+string(key.Signature)] = true
+		}
+	}
+
+	added := make([]*types.SlasherAttestation, 0, len(inserted))
+	for _, a := range attestations {
+		key := string(a.DataRoot) + string(a.Signature)
+		if inserted[key] {
+			added = append(added, a)
+			// a block can include the same aggregate twice
+			delete(inserted, key)
+		}
+	}
+	return added, nil
+}
+
+func scanSlasherAttestations(rows *sql.Rows) ([]*types.SlasherAttestation, error) {
+	defer rows.Close()
+
+	var attestations []*types.SlasherAttestation
+	for rows.Next() {
+		a := &types.SlasherAttestation{
+			IndexedAttestation: &types.IndexedAttestation{
+				Data: &types.AttestationData{
+					Source: &types.Checkpoint{},
+					Target: &types.Checkpoint{},
+				},
+			},
+		}
+		var indices pq.Int64Array
+		err := rows.Scan(&a.DataRoot, &a.Signature, &indices, &a.Data.Slot, &a.Data.CommitteeIndex, &a.Data.BeaconBlockRoot, &a.Data.Source.Epoch, &a.Data.Source.Root, &a.Data.Target.Epoch, &a.Data.Target.Root)
+		if err != nil {
+			return nil, err
+		}
+		a.AttestingIndices = make([]uint64, len(indices))
+		for i, index := range indices {
+			a.AttestingIndices[i] = uint64(index)
+		}
+		attestations = append(attestations, a)
+	}
+	return attestations, rows.Err()
+}
+
+// GetSlasherAttestations returns the attestations of the slasher history with a target epoch starting at the epoch
+func GetSlasherAttestations(epoch uint64) ([]*types.SlasherAttestation, error) {
+	rows, err := WriterDb.Query(`
+		SELECT data_root, signature, attesting_indices, slot, committeeindex, beaconblockroot, source_epoch, source_root, target_epoch, target_root
+		FROM slasher_attestations
+		WHERE target_epoch >= $1
+		ORDER BY target_epoch, slot`, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting slasher attestations: %w", err)
+	}
+	attestations, err := scanSlasherAttestations(rows)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning slasher attestations: %w", err)
+	}
+	return attestations, nil
+}
+
+// GetSlasherValidatorAttestation returns an attestation of the validator with the target epoch whose data differs
+// from the given data root, it returns nil if there is none
+func GetSlasherValidatorAttestation(validatorIndex, targetEpoch uint64, dataRoot []byte, tx *sqlx.Tx) (*types.SlasherAttestation, error) {
+	rows, err := tx.Query(`
+		SELECT data_root, signature, attesting_indices, slot, committeeindex, beaconblockroot, source_epoch, source_root, target_epoch, target_root
+		FROM slasher_attestations
+		WHERE target_epoch = $1 AND attesting_indices @> ARRAY[$2]::INTEGER[] AND data_root != $3
+		LIMIT 1`, targetEpoch, validatorIndex, dataRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error getting slasher attestation of validator %v: %w", validatorIndex, err)
+	}
+	attestations, err := scanSlasherAttestations(rows)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning slasher attestation of validator %v: %w", validatorIndex, err)
+	}
+	if len(attestations) == 0 {
+		return nil, nil
+	}
+	return attestations[0], nil
+}
+
+// SaveSlasherProposal adds the header of the block to the history of the slasher and returns the headers of all other
+// blocks of the same proposer for the slot, nothing is returned if the block is already part of the history
+func SaveSlasherProposal(block *types.Block, tx *sqlx.Tx) ([]*types.Block, error) {
+	res, err := tx.Exec(`
+		INSERT INTO slasher_proposals (slot, proposerindex, block_root, parentroot, stateroot, bodyroot, signature)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (slot, proposerindex, block_root) DO NOTHING`,
+		block.Slot, block.Proposer, block.BlockRoot, block.ParentRoot, block.StateRoot, block.BodyRoot, block.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting slasher proposal of slot %v: %w", block.Slot, err)
+	}
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+		return nil, err
+	}
+
+	var proposals []*types.Block
+	err = tx.Select(&proposals, `
+		SELECT slot, proposerindex AS proposer, block_root AS blockroot, parentroot, stateroot, bodyroot, signature
+		FROM slasher_proposals
+		WHERE slot = $1 AND proposerindex = $2 AND block_root != $3`, block.Slot, block.Proposer, block.BlockRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error getting slasher proposals of slot %v: %w", block.Slot, err)
+	}
+	return proposals, nil
+}
+
+// SaveSlasherAttesterSlashing saves a candidate attester slashing for the validators, the validators are merged into
+// an already detected slashing of the same attestations
+func SaveSlasherAttesterSlashing(detectedSlot uint64, slashingType string, validators []uint64, slashing *types.AttesterSlashing, dataRoot1, dataRoot2 []byte, tx *sqlx.Tx) error {
+	a1, a2 := slashing.Attestation1, slashing.Attestation2
+	_, err := tx.Exec(`
+		INSERT INTO slasher_attester_slashings (detected_slot, type, validators, attestation1_data_root, attestation1_indices, attestation1_signature, attestation1_slot, attestation1_index, attestation1_beaconblockroot, attestation1_source_epoch, attestation1_source_root, attestation1_target_epoch, attestation1_target_root, attestation2_data_root, attestation2_indices, attestation2_signature, attestation2_slot, attestation2_index, attestation2_beaconblockroot, attestation2_source_epoch, attestation2_source_root, attestation2_target_epoch, attestation2_target_root)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
+		ON CONFLICT (attestation1_signature, attestation2_signature) DO UPDATE SET
+			validators = ARRAY(SELECT DISTINCT UNNEST(slasher_attester_slashings.validators || excluded.validators) ORDER BY 1)`,
+		detectedSlot, slashingType, pq.Array(validators),
+		dataRoot1, pq.Array(a1.AttestingIndices), a1.Signature, a1.Data.Slot, a1.Data.CommitteeIndex, a1.Data.BeaconBlockRoot, a1.Data.Source.Epoch, a1.Data.Source.Root, a1.Data.Target.Epoch, a1.Data.Target.Root,
+		dataRoot2, pq.Array(a2.AttestingIndices), a2.Signature, a2.Data.Slot, a2.Data.CommitteeIndex, a2.Data.BeaconBlockRoot, a2.Data.Source.Epoch, a2.Data.Source.Root, a2.Data.Target.Epoch, a2.Data.Target.Root)
+	if err != nil {
+		return fmt.Errorf("error saving slasher attester slashing: %w", err)
+	}
+	return nil
+}
+
+// SaveSlasherProposerSlashing saves a candidate proposer slashing
+func SaveSlasherProposerSlashing(detectedSlot uint64, slashing *types.ProposerSlashing, tx *sqlx.Tx) error {
+	h1, h2 := slashing.Header1, slashing.Header2
+	_, err := tx.Exec(`
+		INSERT INTO slasher_proposer_slashings (detected_slot, proposerindex, header1_slot, header1_parentroot, header1_stateroot, header1_bodyroot, header1_signature, header2_slot, header2_parentroot, header2_stateroot, header2_bodyroot, header2_signature)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (header1_signature, header2_signature) DO NOTHING`,
+		detectedSlot, slashing.ProposerIndex,
+		h1.Slot, h1.ParentRoot, h1.StateRoot, h1.BodyRoot, h1.Signature,
+		h2.Slot, h2.ParentRoot, h2.StateRoot, h2.BodyRoot, h2.Signature)
+	if err != nil {
+		return fmt.Errorf("error saving slasher proposer slashing: %w", err)
+	}
+	return nil
+}
+
+// DeleteSlasherHistory removes the attestations and proposals before the epoch from the history of the slasher
+func DeleteSlasherHistory(epoch uint64, tx *sqlx.Tx) error {
+	_, err := tx.Exec(`DELETE FROM slasher_attestations WHERE target_epoch < $1`, epoch)
+	if err != nil {
+		return fmt.Errorf("error deleting slasher attestations: %w", err)
+	}
+	_, err = tx.Exec(`DELETE FROM slasher_proposals WHERE slot < $1`, epoch*utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return fmt.Errorf("error deleting slasher proposals: %w", err)
+	}
+	return nil
+}
+
+// GetSlasherSlashingsForNotifications returns the not yet slashed validators of all slashings the slasher detected in the epoch
+func GetSlasherSlashingsForNotifications(epoch uint64) ([]*types.SlasherSlashingNotification, error) {
+	firstSlot := epoch * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	lastSlot := firstSlot + utils.Config.Chain.ClConfig.SlotsPerEpoch - 1
+
+	var slashings []*types.SlasherSlashingNotification
+	err := ReaderDb.Select(&slashings, `
+		SELECT v.validatorindex, v.pubkey, s.type, s.detected_slot
+		FROM slasher_attester_slashings s
+		CROSS JOIN LATERAL UNNEST(s.validators) AS slashable(validatorindex)
+		INNER JOIN validators v ON v.validatorindex = slashable.validatorindex
+		WHERE s.detected_slot BETWEEN $1 AND $2 AND NOT v.slashed
+		UNION ALL
+		SELECT v.validatorindex, v.pubkey, 'double_proposal' AS type, s.detected_slot
+		FROM slasher_proposer_slashings s
+		INNER JOIN validators v ON v.validatorindex = s.proposerindex
+		WHERE s.detected_slot BETWEEN $1 AND $2 AND NOT v.slashed`, firstSlot, lastSlot)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("error getting slasher slashings of epoch %v: %w", epoch, err)
+	}
+	return slashings, nil
+}
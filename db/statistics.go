@@ -1559,6 +1559,8 @@ func WriteExecutionChartSeriesForDay(day int64) error {
 
 	accumulatedBlockTime := decimal.NewFromInt(0)
 
+	blobSubmitters := newBlobSubmitters(dateTrunc)
+
 	for blk := range blocksChan {
 		// logger.Infof("analyzing block: %v with: %v transactions", blk.Number, len(blk.Transactions))
 		blockCount += 1
@@ -1617,6 +1619,7 @@ func WriteExecutionChartSeriesForDay(day int64) error {
 				totalBlobGasUsed = totalBlobGasUsed.Add(blobGasUsed)
 				totalBurnedBlob = blobGasUsed.Mul(decimal.NewFromBigInt(new(big.Int).SetBytes(tx.BlobGasPrice), 0))
 				totalBlobCount = totalBlobCount.Add(decimal.NewFromInt(int64(len(tx.BlobVersionedHashes))))
+				blobSubmitters.add(tx)
 
 			default:
 				logger.Fatalf("error unknown tx type %v hash: %x", tx.Type, tx.Hash)
@@ -1769,6 +1772,12 @@ func WriteExecutionChartSeriesForDay(day int64) error {
 		return fmt.Errorf("error calculating TX_COUNT chart_series: %w", err)
 	}
 
+	logger.Infof("Exporting blob submitter stats of %v submitters", len(blobSubmitters.stats))
+	err = blobSubmitters.save()
+	if err != nil {
+		return fmt.Errorf("error saving blob submitter stats: %w", err)
+	}
+
 	// Not sure how this is currently possible (where do we store the size, i think this is missing)
 	// logger.Infof("Exporting AVG_SIZE %v", totalSize.div)
 	// err = SaveChartSeriesPoint(dateTrunc, "AVG_SIZE", totalSize.div)
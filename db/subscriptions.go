@@ -200,7 +200,7 @@ type GetSubscriptionsFilter struct {
 // GetSubscriptions returns the subscriptions filtered by the provided filter.
 func GetSubscriptions(filter GetSubscriptionsFilter) ([]*types.Subscription, error) {
 	subs := []*types.Subscription{}
-	qry := "SELECT event_name, event_filter, last_sent_ts, last_sent_epoch, created_ts, created_epoch, event_threshold, ENCODE(unsubscribe_hash, 'hex') as unsubscribe_hash FROM users_subscriptions"
+	qry := "SELECT event_name, event_filter, last_sent_ts, last_sent_epoch, created_ts, created_epoch, event_threshold, ENCODE(unsubscribe_hash, 'hex') as unsubscribe_hash, digest_mode FROM users_subscriptions"
 
 	if filter.JoinValidator {
 		qry = "SELECT id, user_id, event_name, event_filter, last_sent_ts, created_ts, ENCODE(unsubscribe_hash, 'hex') as unsubscribe_hash FROM users_subscriptions INNER JOIN validators ON users_subscriptions.event_filter = ENCODE(validators.pubkey::bytea, 'hex')"
@@ -269,6 +269,80 @@ func UpdateSubscriptionLastSent(tx *sqlx.Tx, ts uint64, epoch uint64, subID uint
 	return err
 }
 
+// GetSubscriptionDigestModes returns the digest mode of each of the given subscriptions
+func GetSubscriptionDigestModes(subscriptionIDs []uint64) (map[uint64]types.NotificationDigestMode, error) {
+	rows := []struct {
+		ID         uint64                       `db:"id"`
+		DigestMode types.NotificationDigestMode `db:"digest_mode"`
+	}{}
+	err := FrontendWriterDB.Select(&rows, `SELECT id, digest_mode FROM users_subscriptions WHERE id = ANY($1)`, pq.Array(subscriptionIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error getting digest modes of subscriptions: %w", err)
+	}
+
+	modes := make(map[uint64]types.NotificationDigestMode, len(rows))
+	for _, row := range rows {
+		modes[row.ID] = row.DigestMode
+	}
+	return modes, nil
+}
+
+// UpdateSubscriptionsDigestMode sets the digest mode of the subscriptions of a user for an event, if no event filter
+// is given all subscriptions of the event are updated. The number of updated subscriptions is returned.
+func UpdateSubscriptionsDigestMode(userID uint64, eventName string, eventFilter string, mode types.NotificationDigestMode) (int64, error) {
+	var res sql.Result
+	var err error
+	if eventFilter == "" {
+		res, err = FrontendWriterDB.Exec(`UPDATE users_subscriptions SET digest_mode = $3 WHERE user_id = $1 AND event_name = $2`, userID, eventName, mode)
+	} else {
+		res, err = FrontendWriterDB.Exec(`UPDATE users_subscriptions SET digest_mode = $4 WHERE user_id = $1 AND event_name = $2 AND event_filter = $3`, userID, eventName, eventFilter, mode)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error updating digest mode of subscriptions: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// UpdateAllSubscriptionsDigestMode sets the digest mode of all subscriptions of a user
+func UpdateAllSubscriptionsDigestMode(userID uint64, mode types.NotificationDigestMode) error {
+	_, err := FrontendWriterDB.Exec(`UPDATE users_subscriptions SET digest_mode = $2 WHERE user_id = $1`, userID, mode)
+	if err != nil {
+		return fmt.Errorf("error updating digest mode of subscriptions: %w", err)
+	}
+	return nil
+}
+
+// GetUserNotificationSettings returns the notification settings of the given users, users without settings get the
+// default settings (UTC, no quiet hours)
+func GetUserNotificationSettings(userIDs []uint64) (map[uint64]*types.UserNotificationSettings, error) {
+	rows := []*types.UserNotificationSettings{}
+	err := FrontendWriterDB.Select(&rows, `SELECT user_id, time_zone, quiet_hours_start, quiet_hours_end FROM users_notification_settings WHERE user_id = ANY($1)`, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error getting notification settings of users: %w", err)
+	}
+
+	settings := make(map[uint64]*types.UserNotificationSettings, len(userIDs))
+	for _, userID := range userIDs {
+		settings[userID] = &types.UserNotificationSettings{UserID: userID, TimeZone: "UTC"}
+	}
+	for _, row := range rows {
+		settings[row.UserID] = row
+	}
+	return settings, nil
+}
+
+// SaveUserNotificationSettings stores the notification settings of a user
+func SaveUserNotificationSettings(settings *types.UserNotificationSettings) error {
+	_, err := FrontendWriterDB.Exec(`
+		INSERT INTO users_notification_settings (user_id, time_zone, quiet_hours_start, quiet_hours_end) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET time_zone = excluded.time_zone, quiet_hours_start = excluded.quiet_hours_start, quiet_hours_end = excluded.quiet_hours_end`,
+		settings.UserID, settings.TimeZone, settings.QuietHoursStart, settings.QuietHoursEnd)
+	if err != nil {
+		return fmt.Errorf("error saving notification settings of user %v: %w", settings.UserID, err)
+	}
+	return nil
+}
+
 // CountSentMail increases the count of sent mails in the table `mails_sent` for this day.
 func CountSentMail(email string) error {
 	day := time.Now().Truncate(utils.Day).Unix()
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// ErrValidatorExportJobNotFound is returned if the export job does not exist or belongs to another user
+var ErrValidatorExportJobNotFound = errors.New("validator export job not found")
+
+const validatorExportJobColumns = `id, user_id, validators, source, fields, format, start_epoch, end_epoch, status, error, object_key, row_count, size_bytes, created_at, started_at, finished_at`
+
+// CreateValidatorExportJob queues a new export job
+func CreateValidatorExportJob(job *types.ValidatorExportJob) (*types.ValidatorExportJob, error) {
+	created := &types.ValidatorExportJob{}
+	err := FrontendWriterDB.Get(created, `
+		INSERT INTO validator_export_jobs (user_id, validators, source, fields, format, start_epoch, end_epoch, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+validatorExportJobColumns,
+		job.UserID, job.Validators, job.Source, job.Fields, job.Format, job.StartEpoch, job.EndEpoch, types.ValidatorExportPending)
+	if err != nil {
+		return nil, fmt.Errorf("error creating validator export job for user %v: %w", job.UserID, err)
+	}
+	return created, nil
+}
+
+// CountUserActiveValidatorExportJobs returns the number of pending or running export jobs of a user
+func CountUserActiveValidatorExportJobs(userId uint64) (int, error) {
+	var count int
+	err := FrontendWriterDB.Get(&count, `
+		SELECT COUNT(*) FROM validator_export_jobs WHERE user_id = $1 AND status IN ($2, $3)`,
+		userId, types.ValidatorExportPending, types.ValidatorExportRunning)
+	if err != nil {
+		return 0, fmt.Errorf("error counting validator export jobs of user %v: %w", userId, err)
+	}
+	return count, nil
+}
+
+// GetUserValidatorExportJobs returns the most recent export jobs of a user
+func GetUserValidatorExportJobs(userId uint64, limit uint64) ([]*types.ValidatorExportJob, error) {
+	jobs := []*types.ValidatorExportJob{}
+	err := FrontendReaderDB.Select(&jobs, `
+		SELECT `+validatorExportJobColumns+`
+		FROM validator_export_jobs
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`, userId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting validator export jobs of user %v: %w", userId, err)
+	}
+	return jobs, nil
+}
+
+// GetUserValidatorExportJob returns an export job of a user
+func GetUserValidatorExportJob(userId, jobId uint64) (*types.ValidatorExportJob, error) {
+	job := &types.ValidatorExportJob{}
+	err := FrontendReaderDB.Get(job, `
+		SELECT `+validatorExportJobColumns+`
+		FROM validator_export_jobs
+		WHERE id = $1 AND user_id = $2`, jobId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrValidatorExportJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting validator export job %v of user %v: %w", jobId, userId, err)
+	}
+	return job, nil
+}
+
+// ClaimValidatorExportJob marks the oldest pending export job as running and returns it, jobs whose worker did not send
+// a heartbeat for staleAfter are claimed again. Returns nil if there is no job to run.
+func ClaimValidatorExportJob(staleAfter time.Duration) (*types.ValidatorExportJob, error) {
+	job := &types.ValidatorExportJob{}
+	err := FrontendWriterDB.Get(job, `
+		UPDATE validator_export_jobs SET status = $1, started_at = NOW(), heartbeat_at = NOW()
+		WHERE id = (
+			SELECT id FROM validator_export_jobs
+			WHERE status = $2 OR (status = $1 AND heartbeat_at < NOW() - $3 * INTERVAL '1 second')
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+validatorExportJobColumns,
+		types.ValidatorExportRunning, types.ValidatorExportPending, int64(staleAfter.Seconds()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error claiming validator export job: %w", err)
+	}
+	return job, nil
+}
+
+// UpdateValidatorExportJobHeartbeat tells other workers that the export job is still running
+func UpdateValidatorExportJobHeartbeat(jobId uint64) error {
+	_, err := FrontendWriterDB.Exec(`UPDATE validator_export_jobs SET heartbeat_at = NOW() WHERE id = $1 AND status = $2`, jobId, types.ValidatorExportRunning)
+	if err != nil {
+		return fmt.Errorf("error updating heartbeat of validator export job %v: %w", jobId, err)
+	}
+	return nil
+}
+
+// FinishValidatorExportJob marks an export job as finished after its file was uploaded to the object storage
+func FinishValidatorExportJob(jobId uint64, objectKey string, rowCount, sizeBytes uint64) error {
+	_, err := FrontendWriterDB.Exec(`
+		UPDATE validator_export_jobs SET status = $1, object_key = $2, row_count = $3, size_bytes = $4, finished_at = NOW(), error = NULL
+		WHERE id = $5`, types.ValidatorExportFinished, objectKey, rowCount, sizeBytes, jobId)
+	if err != nil {
+		return fmt.Errorf("error finishing validator export job %v: %w", jobId, err)
+	}
+	return nil
+}
+
+// FailValidatorExportJob marks an export job as failed
+func FailValidatorExportJob(jobId uint64, reason string) error {
+	_, err := FrontendWriterDB.Exec(`
+		UPDATE validator_export_jobs SET status = $1, error = $2, finished_at = NOW()
+		WHERE id = $3`, types.ValidatorExportFailed, reason, jobId)
+	if err != nil {
+		return fmt.Errorf("error failing validator export job %v: %w", jobId, err)
+	}
+	return nil
+}
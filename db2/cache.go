@@ -107,6 +107,14 @@ func (c *CachedRawStore) ReadBlockByHash(chainID uint64, hash string) (*FullBloc
 	return v.(*FullBlockRawData), nil
 }
 
+func (c *CachedRawStore) ReadBlockByTxHash(chainID uint64, hash string) (*FullBlockRawData, error) {
+	block, err := c.db.ReadBlockByTxHash(chainID, hash)
+	if block != nil {
+		c.cacheBlock(block, oneBlockTTL)
+	}
+	return block, err
+}
+
 func (c *CachedRawStore) ReadBlocksByNumber(chainID uint64, start, end int64) ([]*FullBlockRawData, error) {
 	blocks, err := c.db.ReadBlocksByNumber(chainID, start, end)
 	if err != nil {
@@ -9,8 +9,12 @@ import (
 	"io"
 	"math/big"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
 	"syscall"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db2/store"
@@ -22,6 +26,7 @@ var ErrMethodNotSupported = fmt.Errorf("method not supported")
 type RawStoreReader interface {
 	ReadBlockByNumber(chainID uint64, number int64) (*FullBlockRawData, error)
 	ReadBlockByHash(chainID uint64, hash string) (*FullBlockRawData, error)
+	ReadBlockByTxHash(chainID uint64, hash string) (*FullBlockRawData, error)
 	ReadBlocksByNumber(chainID uint64, start, end int64) ([]*FullBlockRawData, error)
 }
 
@@ -122,6 +127,65 @@ func (r *BigTableEthRaw) handle(ctx context.Context, message *jsonrpcMessage) (*
 		if err != nil {
 			return nil, err
 		}
+		if !fullTransactionsArg(args) {
+			respBody, err = withTransactionHashes(respBody)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case "eth_getBlockByHash":
+		hash, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		respBody, err = r.BlockByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if !fullTransactionsArg(args) {
+			respBody, err = withTransactionHashes(respBody)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case "eth_getTransactionByHash":
+		hash, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		respBody, err = r.TransactionByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+
+	case "eth_getTransactionReceipt":
+		hash, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		respBody, err = r.TransactionReceipt(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+
+	case "eth_getLogs":
+		var filters []logFilter
+		if err := json.Unmarshal(message.Params, &filters); err != nil {
+			return nil, err
+		}
+		if len(filters) == 0 {
+			return nil, fmt.Errorf("missing filter argument")
+		}
+
+		respBody, err = r.Logs(ctx, filters[0])
+		if err != nil {
+			return nil, err
+		}
 
 	case "debug_traceBlockByNumber":
 		block, err := hexutil.DecodeBig(args[0].(string))
@@ -155,6 +219,7 @@ func (r *BigTableEthRaw) handle(ctx context.Context, message *jsonrpcMessage) (*
 			return nil, err
 		}
 	default:
+		// state methods like eth_getBalance or eth_call cannot be served from the raw blocks and are left to the fallback
 		return nil, ErrMethodNotSupported
 	}
 	var resp jsonrpcMessage
@@ -189,6 +254,137 @@ func (r *BigTableEthRaw) BlockByNumber(ctx context.Context, number *big.Int) ([]
 	return block.Block, nil
 }
 
+func (r *BigTableEthRaw) BlockByHash(ctx context.Context, hash string) ([]byte, error) {
+	block, err := r.db.ReadBlockByHash(r.chainID, hash)
+	if err != nil {
+		return nil, err
+	}
+	return block.Block, nil
+}
+
+func (r *BigTableEthRaw) TransactionByHash(ctx context.Context, hash string) ([]byte, error) {
+	block, err := r.db.ReadBlockByTxHash(r.chainID, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+	msg, err := unmarshalResult(block.Block, &result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal block %d: %w", block.BlockNumber, err)
+	}
+	for _, tx := range result.Transactions {
+		if strings.EqualFold(transactionHash(tx), hash) {
+			msg.Result = tx
+			return json.Marshal(msg)
+		}
+	}
+	return nil, fmt.Errorf("transaction %s not found in block %d: %w", hash, block.BlockNumber, store.ErrNotFound)
+}
+
+func (r *BigTableEthRaw) TransactionReceipt(ctx context.Context, hash string) ([]byte, error) {
+	block, err := r.db.ReadBlockByTxHash(r.chainID, hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(block.Receipts) == 0 {
+		return nil, fmt.Errorf("receipts of block %d: %w", block.BlockNumber, store.ErrNotFound)
+	}
+
+	var receipts []json.RawMessage
+	msg, err := unmarshalResult(block.Receipts, &receipts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal receipts of block %d: %w", block.BlockNumber, err)
+	}
+	for _, receipt := range receipts {
+		var tx struct {
+			TransactionHash string `json:"transactionHash"`
+		}
+		if err := json.Unmarshal(receipt, &tx); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal receipt of block %d: %w", block.BlockNumber, err)
+		}
+		if strings.EqualFold(tx.TransactionHash, hash) {
+			msg.Result = receipt
+			return json.Marshal(msg)
+		}
+	}
+	return nil, fmt.Errorf("receipt %s not found in block %d: %w", hash, block.BlockNumber, store.ErrNotFound)
+}
+
+// Logs returns the logs of the blocks matching the filter, ranges using block tags like "latest" are left to the fallback
+// as the raw store does not know the head of the chain, as are blocks whose receipts were not stored
+func (r *BigTableEthRaw) Logs(ctx context.Context, filter logFilter) ([]byte, error) {
+	var blocks []*FullBlockRawData
+	if filter.BlockHash != "" {
+		block, err := r.db.ReadBlockByHash(r.chainID, filter.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	} else {
+		from, err := decodeBlockNumber(filter.FromBlock)
+		if err != nil {
+			return nil, err
+		}
+		to, err := decodeBlockNumber(filter.ToBlock)
+		if err != nil {
+			return nil, err
+		}
+		if to < from {
+			return nil, fmt.Errorf("invalid block range %d - %d", from, to)
+		}
+		if to-from >= maxLogsBlockRange {
+			return nil, fmt.Errorf("block range %d - %d exceeds %d blocks: %w", from, to, maxLogsBlockRange, ErrMethodNotSupported)
+		}
+		blocks, err = r.db.ReadBlocksByNumber(r.chainID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(blocks)) != to-from+1 {
+			return nil, fmt.Errorf("blocks %d - %d are incomplete: %w", from, to, store.ErrNotFound)
+		}
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].BlockNumber < blocks[j].BlockNumber
+		})
+	}
+
+	logs := []json.RawMessage{}
+	for _, block := range blocks {
+		if len(block.Receipts) == 0 {
+			// the receipts were not stored, the fallback node has to answer
+			return nil, fmt.Errorf("receipts of block %d: %w", block.BlockNumber, store.ErrNotFound)
+		}
+		var receipts []struct {
+			Logs []json.RawMessage `json:"logs"`
+		}
+		if _, err := unmarshalResult(block.Receipts, &receipts); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal receipts of block %d: %w", block.BlockNumber, err)
+		}
+		for _, receipt := range receipts {
+			for _, log := range receipt.Logs {
+				var l struct {
+					Address common.Address `json:"address"`
+					Topics  []common.Hash  `json:"topics"`
+				}
+				if err := json.Unmarshal(log, &l); err != nil {
+					return nil, fmt.Errorf("cannot unmarshal log of block %d: %w", block.BlockNumber, err)
+				}
+				if filter.matches(l.Address, l.Topics) {
+					logs = append(logs, log)
+				}
+			}
+		}
+	}
+
+	result, err := json.Marshal(logs)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonrpcMessage{Version: "2.0", Result: result})
+}
+
 func (r *BigTableEthRaw) BlockReceipts(ctx context.Context, number *big.Int) ([]byte, error) {
 	block, err := r.db.ReadBlockByNumber(r.chainID, number.Int64())
 	if err != nil {
@@ -239,6 +435,145 @@ func (r *BigTableEthRaw) UncleByBlockHashAndIndex(ctx context.Context, hash stri
 	return json.Marshal(uncles[index])
 }
 
+// maxLogsBlockRange is the maximum number of blocks read for a single eth_getLogs request
+const maxLogsBlockRange = 1000
+
+// logFilter holds the filter argument of eth_getLogs, a single address or topic is decoded as a list of one
+type logFilter struct {
+	BlockHash string
+	FromBlock string
+	ToBlock   string
+	Addresses []common.Address
+	Topics    [][]common.Hash
+}
+
+func (f *logFilter) UnmarshalJSON(input []byte) error {
+	var raw struct {
+		BlockHash string            `json:"blockHash"`
+		FromBlock string            `json:"fromBlock"`
+		ToBlock   string            `json:"toBlock"`
+		Address   json.RawMessage   `json:"address"`
+		Topics    []json.RawMessage `json:"topics"`
+	}
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+	f.BlockHash, f.FromBlock, f.ToBlock = raw.BlockHash, raw.FromBlock, raw.ToBlock
+
+	if len(raw.Address) != 0 && string(raw.Address) != "null" {
+		var address common.Address
+		if err := json.Unmarshal(raw.Address, &address); err != nil {
+			if err := json.Unmarshal(raw.Address, &f.Addresses); err != nil {
+				return fmt.Errorf("invalid address filter: %w", err)
+			}
+		} else {
+			f.Addresses = []common.Address{address}
+		}
+	}
+
+	f.Topics = make([][]common.Hash, len(raw.Topics))
+	for i, topic := range raw.Topics {
+		if len(topic) == 0 || string(topic) == "null" {
+			// a nil position matches any topic
+			continue
+		}
+		var hash common.Hash
+		if err := json.Unmarshal(topic, &hash); err != nil {
+			if err := json.Unmarshal(topic, &f.Topics[i]); err != nil {
+				return fmt.Errorf("invalid topic filter at position %d: %w", i, err)
+			}
+		} else {
+			f.Topics[i] = []common.Hash{hash}
+		}
+	}
+	return nil
+}
+
+// matches reports whether a log with the address and topics is selected by the filter
+func (f logFilter) matches(address common.Address, topics []common.Hash) bool {
+	if len(f.Addresses) != 0 && !slices.Contains(f.Addresses, address) {
+		return false
+	}
+	if len(f.Topics) > len(topics) {
+		return false
+	}
+	for i, sub := range f.Topics {
+		if len(sub) != 0 && !slices.Contains(sub, topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeBlockNumber decodes a hex block number, block tags are not supported as the raw store does not know the head of the chain
+func decodeBlockNumber(number string) (int64, error) {
+	if !strings.HasPrefix(number, "0x") {
+		return 0, fmt.Errorf("block %q: %w", number, ErrMethodNotSupported)
+	}
+	n, err := hexutil.DecodeUint64(number)
+	if err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
+
+func stringArg(args []interface{}, i int) (string, error) {
+	if len(args) <= i {
+		return "", fmt.Errorf("missing argument %d", i)
+	}
+	s, ok := args[i].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid argument %d: %v", i, args[i])
+	}
+	return s, nil
+}
+
+// fullTransactionsArg returns the second argument of eth_getBlockByNumber and eth_getBlockByHash, the stored blocks
+// always include the full transactions so they are returned if the argument is missing
+func fullTransactionsArg(args []interface{}) bool {
+	if len(args) < 2 {
+		return true
+	}
+	full, ok := args[1].(bool)
+	return !ok || full
+}
+
+// unmarshalResult unmarshals the result of a stored JSON-RPC response into v and returns the response
+func unmarshalResult(data []byte, v interface{}) (*jsonrpcMessage, error) {
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(msg.Result, v); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// withTransactionHashes replaces the full transactions of a stored block response by their hashes
+func withTransactionHashes(data []byte) ([]byte, error) {
+	var block map[string]json.RawMessage
+	msg, err := unmarshalResult(data, &block)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal block: %w", err)
+	}
+	var transactions []json.RawMessage
+	if err := json.Unmarshal(block["transactions"], &transactions); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal transactions: %w", err)
+	}
+	hashes := make([]string, 0, len(transactions))
+	for _, tx := range transactions {
+		hashes = append(hashes, transactionHash(tx))
+	}
+	if block["transactions"], err = json.Marshal(hashes); err != nil {
+		return nil, err
+	}
+	if msg.Result, err = json.Marshal(block); err != nil {
+		return nil, err
+	}
+	return json.Marshal(msg)
+}
+
 // A value of this type can a JSON-RPC request, notification, successful response or
 // error response. Which one it is depends on the fields.
 type jsonrpcMessage struct {
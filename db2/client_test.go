@@ -4,9 +4,12 @@ import (
 	"context"
 	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"slices"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -293,6 +296,289 @@ func TestBigTableClientWithFallback(t *testing.T) {
 	}
 }
 
+func TestBigTableEthRawByHash(t *testing.T) {
+	client, admin := storetest.NewBigTable(t)
+	bg, err := store.NewBigTableWithClient(context.Background(), client, admin, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawStore := NewRawStore(store.Wrap(bg, BlocksRawTable, ""))
+	block := testFullBlock
+	block.Receipts = []byte(testBlockReceipts)
+	if err := rawStore.AddBlocks([]FullBlockRawData{block, testTwoUnclesFullBlock}); err != nil {
+		t.Fatal(err)
+	}
+
+	rpcClient, err := rpc.DialOptions(context.Background(), "http://foo.bar", rpc.WithHTTPClient(&http.Client{
+		Transport: NewBigTableEthRaw(WithCache(rawStore), block.ChainID),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const blockHash = "0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35"
+
+	t.Run("block by hash", func(t *testing.T) {
+		var full struct {
+			Number       string                   `json:"number"`
+			Transactions []map[string]interface{} `json:"transactions"`
+		}
+		if err := rpcClient.Call(&full, "eth_getBlockByHash", blockHash, true); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := full.Number, hexutil.EncodeUint64(testBlockNumber); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if got, want := len(full.Transactions), 7; got != want {
+			t.Errorf("got %v transactions, want %v", got, want)
+		}
+
+		var hashes struct {
+			Transactions []string `json:"transactions"`
+		}
+		if err := rpcClient.Call(&hashes, "eth_getBlockByHash", blockHash, false); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := hashes.Transactions[2], "0xe42b0256058b7cad8a14b136a0364acda0b4c36f5b02dea7e69bfd82cef252a2"; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		method    string
+		hash      string
+		wantField string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "transaction",
+			method:    "eth_getTransactionByHash",
+			hash:      "0x311be6a9b58748717ac0f70eb801d29973661aaf1365960d159e4ec4f4aa2d7f",
+			wantField: "transactionIndex",
+			want:      "0x1",
+		},
+		{
+			name:      "receipt",
+			method:    "eth_getTransactionReceipt",
+			hash:      "0xe42b0256058b7cad8a14b136a0364acda0b4c36f5b02dea7e69bfd82cef252a2",
+			wantField: "gasUsed",
+			want:      "0x8f2d",
+		},
+		{
+			name:    "unknown transaction",
+			method:  "eth_getTransactionByHash",
+			hash:    "0x0000000000000000000000000000000000000000000000000000000000000001",
+			wantErr: true,
+		},
+		{
+			name:    "receipt not stored",
+			method:  "eth_getTransactionReceipt",
+			hash:    "0x7e537d687a5525259480440c6ea2e1a8469cd98906eaff8597f3d2a44422ff97",
+			wantErr: true,
+		},
+		{
+			name:    "incomplete hash",
+			method:  "eth_getTransactionByHash",
+			hash:    "0x31",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res map[string]interface{}
+			err := rpcClient.Call(&res, tt.method, tt.hash)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", res)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res[tt.wantField]; got != tt.want {
+				t.Errorf("got %v %v, want %v", tt.wantField, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBigTableEthRawLogs(t *testing.T) {
+	client, admin := storetest.NewBigTable(t)
+	bg, err := store.NewBigTableWithClient(context.Background(), client, admin, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawStore := NewRawStore(store.Wrap(bg, BlocksRawTable, ""))
+	block := testFullBlock
+	block.Receipts = []byte(testBlockReceipts)
+	if err := rawStore.AddBlocks([]FullBlockRawData{block, testTwoUnclesFullBlock}); err != nil {
+		t.Fatal(err)
+	}
+
+	rpcClient, err := rpc.DialOptions(context.Background(), "http://foo.bar", rpc.WithHTTPClient(&http.Client{
+		Transport: NewBigTableEthRaw(rawStore, block.ChainID),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethClient := ethclient.NewClient(rpcClient)
+
+	token := common.HexToAddress("0x818fc6c2ec5986bc6e2cbf00939d90556ab12ce5")
+	transfer := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	recipient := common.HexToHash("0x000000000000000000000000398a58b2e3790431fdac1ea56017e65401fa9988")
+	number := big.NewInt(testBlockNumber)
+	blockHash := common.HexToHash("0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35")
+
+	tests := []struct {
+		name    string
+		query   ethereum.FilterQuery
+		want    []uint
+		wantErr bool
+	}{
+		{
+			name:  "all logs of the block",
+			query: ethereum.FilterQuery{FromBlock: number, ToBlock: number},
+			want:  []uint{0, 1, 2},
+		},
+		{
+			name:  "by address",
+			query: ethereum.FilterQuery{FromBlock: number, ToBlock: number, Addresses: []common.Address{token}},
+			want:  []uint{1, 2},
+		},
+		{
+			name:  "by first topic",
+			query: ethereum.FilterQuery{FromBlock: number, ToBlock: number, Topics: [][]common.Hash{{transfer}}},
+			want:  []uint{0, 1},
+		},
+		{
+			name:  "by third topic with wildcard",
+			query: ethereum.FilterQuery{FromBlock: number, ToBlock: number, Topics: [][]common.Hash{nil, nil, {recipient}}},
+			want:  []uint{1},
+		},
+		{
+			name:  "by address and topic",
+			query: ethereum.FilterQuery{FromBlock: number, ToBlock: number, Addresses: []common.Address{token}, Topics: [][]common.Hash{{transfer}}},
+			want:  []uint{1},
+		},
+		{
+			name:  "by block hash",
+			query: ethereum.FilterQuery{BlockHash: &blockHash, Addresses: []common.Address{token}},
+			want:  []uint{1, 2},
+		},
+		{
+			name:    "unknown block hash",
+			query:   ethereum.FilterQuery{BlockHash: &common.Hash{0x01}},
+			wantErr: true,
+		},
+		{
+			name:  "no match",
+			query: ethereum.FilterQuery{FromBlock: number, ToBlock: number, Topics: [][]common.Hash{{common.Hash{}}}},
+			want:  []uint{},
+		},
+		{
+			name:    "missing block in range",
+			query:   ethereum.FilterQuery{FromBlock: number, ToBlock: big.NewInt(testBlockNumber + 1)},
+			wantErr: true,
+		},
+		{
+			name:    "block tag",
+			query:   ethereum.FilterQuery{FromBlock: number},
+			wantErr: true,
+		},
+		{
+			name:    "block without receipts",
+			query:   ethereum.FilterQuery{FromBlock: big.NewInt(testTwoUnclesBlockNumber), ToBlock: big.NewInt(testTwoUnclesBlockNumber)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs, err := ethClient.FilterLogs(context.Background(), tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", logs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []uint{}
+			for _, log := range logs {
+				got = append(got, log.Index)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got logs %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBigTableEthRawFallback(t *testing.T) {
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x2a"}`))
+	}))
+	defer fallback.Close()
+
+	client, admin := storetest.NewBigTable(t)
+	bg, err := store.NewBigTableWithClient(context.Background(), client, admin, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawStore := NewRawStore(store.Wrap(bg, BlocksRawTable, ""))
+	if err := rawStore.AddBlocks([]FullBlockRawData{testTwoUnclesFullBlock}); err != nil {
+		t.Fatal(err)
+	}
+
+	rpcClient, err := rpc.DialOptions(context.Background(), fallback.URL, rpc.WithHTTPClient(&http.Client{
+		Transport: NewWithFallback(NewBigTableEthRaw(rawStore, chainID), http.DefaultTransport),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		args   []interface{}
+	}{
+		{
+			name:   "state method",
+			method: "eth_getBalance",
+			args:   []interface{}{common.Address{}, "latest"},
+		},
+		{
+			name:   "unknown transaction",
+			method: "eth_getTransactionByHash",
+			args:   []interface{}{common.Hash{0x01}},
+		},
+		{
+			name:   "logs with block tag",
+			method: "eth_getLogs",
+			args:   []interface{}{map[string]interface{}{"fromBlock": "latest"}},
+		},
+		{
+			name:   "logs of a block without receipts",
+			method: "eth_getLogs",
+			args:   []interface{}{map[string]interface{}{"fromBlock": hexutil.EncodeUint64(testTwoUnclesBlockNumber), "toBlock": hexutil.EncodeUint64(testTwoUnclesBlockNumber)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res string
+			if err := rpcClient.Call(&res, tt.method, tt.args...); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := res, "0x2a"; got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
 // TODO import those 3 from somewhere
 var gethTracerArg = map[string]string{
 	"tracer": "callTracer",
@@ -316,3 +602,100 @@ type GethTraceCallResult struct {
 	Type                string                 `json:"type,omitempty"`
 	Calls               []*GethTraceCallResult `json:"calls,omitempty"`
 }
+
+// testBlockReceipts holds the receipts of the first three transactions of testBlock
+const testBlockReceipts = `{
+  "jsonrpc": "2.0",
+  "id": 1,
+  "result": [
+    {
+      "blockHash": "0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35",
+      "blockNumber": "0x5bad55",
+      "contractAddress": null,
+      "cumulativeGasUsed": "0x1a4f3",
+      "from": "0xfbb1b73c4f0bda4f67dca266ce6ef42f520fbb98",
+      "gasUsed": "0x1a4f3",
+      "logs": [
+        {
+          "address": "0x4b9c25ca0224aef6a7522cabdbc3b2e125b7ca50",
+          "topics": [
+            "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+            "0x000000000000000000000000fbb1b73c4f0bda4f67dca266ce6ef42f520fbb98"
+          ],
+          "data": "0x",
+          "blockNumber": "0x5bad55",
+          "transactionHash": "0x8784d99762bccd03b2086eabccee0d77f14d05463281e121a62abfebcf0d2d5f",
+          "transactionIndex": "0x0",
+          "blockHash": "0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35",
+          "logIndex": "0x0",
+          "removed": false
+        }
+      ],
+      "logsBloom": "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+      "status": "0x1",
+      "to": "0x4b9c25ca0224aef6a7522cabdbc3b2e125b7ca50",
+      "transactionHash": "0x8784d99762bccd03b2086eabccee0d77f14d05463281e121a62abfebcf0d2d5f",
+      "transactionIndex": "0x0",
+      "type": "0x0"
+    },
+    {
+      "blockHash": "0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35",
+      "blockNumber": "0x5bad55",
+      "contractAddress": null,
+      "cumulativeGasUsed": "0x1f6fb",
+      "from": "0xc837f51a0efa33f8eca03570e3d01a4b2cf97ffd",
+      "gasUsed": "0x5208",
+      "logs": [],
+      "logsBloom": "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+      "status": "0x1",
+      "to": "0xf49bd0367d830850456d2259da366a054038dc46",
+      "transactionHash": "0x311be6a9b58748717ac0f70eb801d29973661aaf1365960d159e4ec4f4aa2d7f",
+      "transactionIndex": "0x1",
+      "type": "0x0"
+    },
+    {
+      "blockHash": "0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35",
+      "blockNumber": "0x5bad55",
+      "contractAddress": null,
+      "cumulativeGasUsed": "0x28628",
+      "from": "0x532a2bae845abe7e5115808b832d34f9c3d41eed",
+      "gasUsed": "0x8f2d",
+      "logs": [
+        {
+          "address": "0x818fc6c2ec5986bc6e2cbf00939d90556ab12ce5",
+          "topics": [
+            "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+            "0x000000000000000000000000532a2bae845abe7e5115808b832d34f9c3d41eed",
+            "0x000000000000000000000000398a58b2e3790431fdac1ea56017e65401fa9988"
+          ],
+          "data": "0x00000000000000000000000000000000000000000007bcadb57b861109080000",
+          "blockNumber": "0x5bad55",
+          "transactionHash": "0xe42b0256058b7cad8a14b136a0364acda0b4c36f5b02dea7e69bfd82cef252a2",
+          "transactionIndex": "0x2",
+          "blockHash": "0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35",
+          "logIndex": "0x1",
+          "removed": false
+        },
+        {
+          "address": "0x818fc6c2ec5986bc6e2cbf00939d90556ab12ce5",
+          "topics": [
+            "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+          ],
+          "data": "0x",
+          "blockNumber": "0x5bad55",
+          "transactionHash": "0xe42b0256058b7cad8a14b136a0364acda0b4c36f5b02dea7e69bfd82cef252a2",
+          "transactionIndex": "0x2",
+          "blockHash": "0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35",
+          "logIndex": "0x2",
+          "removed": false
+        }
+      ],
+      "logsBloom": "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+      "status": "0x1",
+      "to": "0x818fc6c2ec5986bc6e2cbf00939d90556ab12ce5",
+      "transactionHash": "0xe42b0256058b7cad8a14b136a0364acda0b4c36f5b02dea7e69bfd82cef252a2",
+      "transactionIndex": "0x2",
+      "type": "0x0"
+    }
+  ]
+}`
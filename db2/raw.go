@@ -1,9 +1,13 @@
 package db2
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db2/store"
@@ -78,17 +82,81 @@ func (db RawStore) AddBlocks(blocks []FullBlockRawData) error {
 				Data:   uncles,
 			})
 		}
+
+		// index the block and transaction hashes to be able to serve the requests by hash
+		if err := fullBlock.addHashItems(itemsByKey); err != nil {
+			return err
+		}
 	}
 	return db.store.BulkAdd(itemsByKey)
 }
 
+// IndexHashes writes the block and transaction hash rows of the blocks from start to end that are already stored. AddBlocks
+// writes them for new blocks, this backfills them for blocks that were added before the hashes were indexed.
+func (db RawStore) IndexHashes(chainID uint64, start, end int64, batchSize int64) error {
+	for from := start; from <= end; from += batchSize {
+		to := min(from+batchSize-1, end)
+		blocks, err := db.ReadBlocksByNumber(chainID, from, to)
+		if errors.Is(err, store.ErrNotFound) {
+			slog.Warn(fmt.Sprintf("no blocks stored from %d to %d", from, to))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read blocks %d to %d: %w", from, to, err)
+		}
+		itemsByKey := make(map[string][]store.Item)
+		for _, block := range blocks {
+			if err := block.addHashItems(itemsByKey); err != nil {
+				return err
+			}
+		}
+		if err := db.store.BulkAdd(itemsByKey); err != nil {
+			return fmt.Errorf("cannot write hashes of blocks %d to %d: %w", from, to, err)
+		}
+		slog.Info(fmt.Sprintf("indexed the hashes of %d blocks from %d to %d", len(blocks), from, to))
+	}
+	return nil
+}
+
 func (db RawStore) ReadBlockByNumber(chainID uint64, number int64) (*FullBlockRawData, error) {
 	return db.readBlock(chainID, number)
 }
 
 func (db RawStore) ReadBlockByHash(chainID uint64, hash string) (*FullBlockRawData, error) {
-	// todo use sql db to retrieve hash
-	return nil, fmt.Errorf("ReadBlockByHash not implemented")
+	// the store reads rows by prefix, an incomplete hash could match the row of another hash
+	if !isHash(hash) {
+		return nil, fmt.Errorf("invalid hash %s: %w", hash, store.ErrNotFound)
+	}
+	number, err := db.readNumber(blockHashKey(chainID, hash))
+	if err != nil {
+		return nil, err
+	}
+	return db.readBlock(chainID, number)
+}
+
+// ReadBlockByTxHash returns the block including the transaction
+func (db RawStore) ReadBlockByTxHash(chainID uint64, hash string) (*FullBlockRawData, error) {
+	// the store reads rows by prefix, an incomplete hash could match the row of another hash
+	if !isHash(hash) {
+		return nil, fmt.Errorf("invalid hash %s: %w", hash, store.ErrNotFound)
+	}
+	number, err := db.readNumber(txHashKey(chainID, hash))
+	if err != nil {
+		return nil, err
+	}
+	return db.readBlock(chainID, number)
+}
+
+func (db RawStore) readNumber(key string) (int64, error) {
+	data, err := db.store.GetRow(key)
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.ParseInt(string(data[fmt.Sprintf("%s:%s", BT_COLUMNFAMILY_BLOCK, BT_COLUMN_NUMBER)]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse block number of %s: %w", key, err)
+	}
+	return number, nil
 }
 
 func (db RawStore) readBlock(chainID uint64, number int64) (*FullBlockRawData, error) {
@@ -151,6 +219,23 @@ func blockKey(chainID uint64, number int64) string {
 	return fmt.Sprintf("%d:%12d", chainID, MAX_EL_BLOCK_NUMBER-number)
 }
 
+func blockHashKey(chainID uint64, hash string) string {
+	return fmt.Sprintf("%d:H:%s", chainID, strings.TrimPrefix(strings.ToLower(hash), "0x"))
+}
+
+func txHashKey(chainID uint64, hash string) string {
+	return fmt.Sprintf("%d:T:%s", chainID, strings.TrimPrefix(strings.ToLower(hash), "0x"))
+}
+
+func isHash(hash string) bool {
+	hash = strings.TrimPrefix(hash, "0x")
+	if len(hash) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
 func blockKeyToNumber(chainID uint64, key string) int64 {
 	key = strings.TrimPrefix(key, fmt.Sprintf("%d:", chainID))
 	reversed, _ := new(big.Int).SetString(key, 10)
@@ -171,3 +256,69 @@ type FullBlockRawData struct {
 	Traces   Bytes
 	Uncles   Bytes
 }
+
+// addHashItems adds the rows that map the block hash and the transaction hashes to the number of the block
+func (b FullBlockRawData) addHashItems(itemsByKey map[string][]store.Item) error {
+	blockHash, txHashes, err := b.hashes()
+	if err != nil {
+		return fmt.Errorf("cannot read hashes of block %d: %w", b.BlockNumber, err)
+	}
+	number := []byte(strconv.FormatInt(b.BlockNumber, 10))
+	if isHash(blockHash) {
+		itemsByKey[blockHashKey(b.ChainID, blockHash)] = []store.Item{
+			{
+				Family: BT_COLUMNFAMILY_BLOCK,
+				Column: BT_COLUMN_NUMBER,
+				Data:   number,
+			},
+		}
+	}
+	for _, txHash := range txHashes {
+		if !isHash(txHash) {
+			continue
+		}
+		itemsByKey[txHashKey(b.ChainID, txHash)] = []store.Item{
+			{
+				Family: BT_COLUMNFAMILY_BLOCK,
+				Column: BT_COLUMN_NUMBER,
+				Data:   number,
+			},
+		}
+	}
+	return nil
+}
+
+// hashes returns the hash of the block and the hashes of its transactions
+func (b FullBlockRawData) hashes() (string, []string, error) {
+	var block struct {
+		Result struct {
+			Hash         string            `json:"hash"`
+			Transactions []json.RawMessage `json:"transactions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(b.Block, &block); err != nil {
+		return "", nil, err
+	}
+	blockHash := block.Result.Hash
+	if len(b.BlockHash) != 0 {
+		blockHash = b.BlockHash.String()
+	}
+	txHashes := make([]string, 0, len(block.Result.Transactions))
+	for _, tx := range block.Result.Transactions {
+		txHashes = append(txHashes, transactionHash(tx))
+	}
+	return blockHash, txHashes, nil
+}
+
+// transactionHash returns the hash of a transaction that is either a full transaction object or only its hash
+func transactionHash(tx json.RawMessage) string {
+	var hash string
+	if err := json.Unmarshal(tx, &hash); err == nil {
+		return hash
+	}
+	var obj struct {
+		Hash string `json:"hash"`
+	}
+	_ = json.Unmarshal(tx, &obj)
+	return obj.Hash
+}
@@ -2,6 +2,7 @@ package db2
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db2/store"
@@ -56,6 +57,58 @@ func TestRaw(t *testing.T) {
 	}
 }
 
+func TestRawIndexHashes(t *testing.T) {
+	client, admin := storetest.NewBigTable(t)
+
+	s, err := store.NewBigTableWithClient(context.Background(), client, admin, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := RawStore{
+		store:      store.Wrap(s, BlocksRawTable, ""),
+		compressor: noOpCompressor{},
+	}
+
+	// a block that was stored before the hashes were indexed
+	err = db.store.BulkAdd(map[string][]store.Item{
+		blockKey(1, testBlockNumber): {
+			{Family: BT_COLUMNFAMILY_BLOCK, Column: BT_COLUMN_BLOCK, Data: []byte(testBlock)},
+			{Family: BT_COLUMNFAMILY_RECEIPTS, Column: BT_COLUMN_RECEIPTS, Data: []byte(testReceipts)},
+			{Family: BT_COLUMNFAMILY_TRACES, Column: BT_COLUMN_TRACES, Data: []byte(testTraces)},
+			{Family: BT_COLUMNFAMILY_UNCLES, Column: BT_COLUMN_UNCLES, Data: []byte(testUncles)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blockHash := "0xb3b20624f8f0f86eb50dd04688409e5cea4bd02d700bf6e79e9384d47d6a5a35"
+	txHash := "0x311be6a9b58748717ac0f70eb801d29973661aaf1365960d159e4ec4f4aa2d7f"
+	if _, err := db.ReadBlockByHash(1, blockHash); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("got error %v before indexing, want %v", err, store.ErrNotFound)
+	}
+
+	if err := db.IndexHashes(1, testBlockNumber-10, testBlockNumber+10, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := db.ReadBlockByHash(1, blockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := res.BlockNumber, int64(testBlockNumber); got != want {
+		t.Errorf("got block %v by hash, want %v", got, want)
+	}
+	res, err = db.ReadBlockByTxHash(1, txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := res.BlockNumber, int64(testBlockNumber); got != want {
+		t.Errorf("got block %v by tx hash, want %v", got, want)
+	}
+}
+
 var testFullBlock = FullBlockRawData{
 	ChainID:          1,
 	BlockNumber:      testBlockNumber,
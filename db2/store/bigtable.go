@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"cloud.google.com/go/bigtable"
-	"golang.org/x/exp/maps"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -20,48 +19,6 @@ const (
 	maxRetries = 5
 )
 
-type TableWrapper struct {
-	*BigTableStore
-	table  string
-	family string
-}
-
-func Wrap(db *BigTableStore, table string, family string) TableWrapper {
-	return TableWrapper{
-		BigTableStore: db,
-		table:         table,
-		family:        family,
-	}
-}
-
-func (w TableWrapper) Add(key, column string, data []byte, allowDuplicate bool) error {
-	return w.BigTableStore.Add(w.table, w.family, key, column, data, allowDuplicate)
-}
-
-func (w TableWrapper) Read(prefix string) ([][]byte, error) {
-	return w.BigTableStore.Read(w.table, w.family, prefix)
-}
-
-func (w TableWrapper) GetLatestValue(key string) ([]byte, error) {
-	return w.BigTableStore.GetLatestValue(w.table, w.family, key)
-}
-
-func (w TableWrapper) GetRow(key string) (map[string][]byte, error) {
-	return w.BigTableStore.GetRow(w.table, key)
-}
-
-func (w TableWrapper) GetRowKeys(prefix string) ([]string, error) {
-	return w.BigTableStore.GetRowKeys(w.table, prefix)
-}
-
-func (w TableWrapper) BulkAdd(itemsByKey map[string][]Item) error {
-	return w.BigTableStore.BulkAdd(w.table, itemsByKey)
-}
-
-func (w TableWrapper) GetRowsRange(high, low string) (map[string]map[string][]byte, error) {
-	return w.BigTableStore.GetRowsRange(w.table, high, low)
-}
-
 // BigTableStore is a wrapper around Google Cloud Bigtable for storing and retrieving data
 type BigTableStore struct {
 	client *bigtable.Client
@@ -152,15 +109,17 @@ func (b BigTableStore) BulkAdd(table string, itemsByKey map[string][]Item) error
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	var muts []*bigtable.Mutation
-	for _, items := range itemsByKey {
+	keys := make([]string, 0, len(itemsByKey))
+	muts := make([]*bigtable.Mutation, 0, len(itemsByKey))
+	for key, items := range itemsByKey {
 		mut := bigtable.NewMutation()
 		for _, item := range items {
 			mut.Set(item.Family, item.Column, bigtable.Timestamp(0), item.Data)
 		}
+		keys = append(keys, key)
 		muts = append(muts, mut)
 	}
-	errs, err := tbl.ApplyBulk(ctx, maps.Keys(itemsByKey), muts)
+	errs, err := tbl.ApplyBulk(ctx, keys, muts)
 	if err != nil {
 		return fmt.Errorf("cannot ApplyBulk err: %w", err)
 	}
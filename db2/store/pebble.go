@@ -0,0 +1,270 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// keySeparator separates the table, row key, family and column of a cell in the pebble key space
+// Row keys must not contain it, as rows are ordered by the encoded key
+const keySeparator = 0x00
+
+// PebbleStore is an embedded on-disk store using Pebble with the same semantics as the BigTableStore
+// Every cell is stored under the key table|row|family|column so that rows are ordered like in Bigtable,
+// only the latest version of a cell is kept
+type PebbleStore struct {
+	db *pebble.DB
+
+	// mu guards the check for existing rows of conditional adds
+	mu sync.Mutex
+}
+
+// NewPebble opens or creates the pebble database in the given directory
+func NewPebble(path string) (*PebbleStore, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("could not open pebble db at %s: %v", path, err)
+	}
+	return &PebbleStore{db: db}, nil
+}
+
+func (p *PebbleStore) BulkAdd(table string, itemsByKey map[string][]Item) error {
+	batch := p.db.NewBatch()
+	defer batch.Close()
+
+	for key, items := range itemsByKey {
+		for _, item := range items {
+			if err := batch.Set(cellKey(table, key, item.Family, item.Column), item.Data, nil); err != nil {
+				return fmt.Errorf("cannot add %s to batch: %w", key, err)
+			}
+		}
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("cannot commit batch: %w", err)
+	}
+	return nil
+}
+
+// Add inserts the data into the given column of the row
+// If duplicates are not allowed, nothing is written when the row already exists
+func (p *PebbleStore) Add(table, family string, key string, column string, data []byte, allowDuplicate bool) error {
+	if !allowDuplicate {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		exists := false
+		err := p.iterate(rowKey(table, key), func(row, family, column string, value []byte) bool {
+			exists = true
+			return false
+		})
+		if err != nil {
+			return fmt.Errorf("could not read row: %v", err)
+		}
+		if exists {
+			return nil
+		}
+	}
+	if err := p.db.Set(cellKey(table, key, family, column), data, pebble.Sync); err != nil {
+		return fmt.Errorf("could not set cell: %v", err)
+	}
+	return nil
+}
+
+// Read retrieves the values of the family of all rows matching the prefix
+func (p *PebbleStore) Read(table, family, prefix string) ([][]byte, error) {
+	var data [][]byte
+	err := p.iterate(tablePrefix(table, prefix), func(_, f, _ string, value []byte) bool {
+		if f == family {
+			data = append(data, value)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read rows: %v", err)
+	}
+	return data, nil
+}
+
+// GetLatestValue returns the first value of the family of the last row matching the key
+func (p *PebbleStore) GetLatestValue(table, family, key string) ([]byte, error) {
+	var data []byte
+	lastRow := ""
+	found := false
+	err := p.iterate(tablePrefix(table, key), func(row, f, _ string, value []byte) bool {
+		if f != family || (found && row == lastRow) {
+			return true
+		}
+		data, lastRow, found = value, row, true
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read rows: %v", err)
+	}
+	return data, nil
+}
+
+// GetRow returns the cells of all rows matching the key indexed by family:column
+func (p *PebbleStore) GetRow(table, key string) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	err := p.iterate(tablePrefix(table, key), func(_, family, column string, value []byte) bool {
+		data[fmt.Sprintf("%s:%s", family, column)] = value
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read rows: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+// GetRowsRange returns the rows of the closed range [low, high] with their cells indexed by family:column
+func (p *PebbleStore) GetRowsRange(table, high, low string) (map[string]map[string][]byte, error) {
+	if low > high {
+		return nil, ErrNotFound
+	}
+	iter, err := p.db.NewIter(&pebble.IterOptions{
+		LowerBound: rowKey(table, low),
+		// every cell of the high row is encoded as high|0x00|..., so it is included by the next separator
+		UpperBound: append(tablePrefix(table, high), keySeparator+1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create iterator: %v", err)
+	}
+	defer iter.Close()
+
+	data := make(map[string]map[string][]byte)
+	for iter.First(); iter.Valid(); iter.Next() {
+		row, family, column, err := parseCellKey(table, iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		if data[row] == nil {
+			data[row] = make(map[string][]byte)
+		}
+		data[row][fmt.Sprintf("%s:%s", family, column)] = bytes.Clone(iter.Value())
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("could not read rows: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+// GetRowKeys returns the ordered keys of all rows matching the prefix
+func (p *PebbleStore) GetRowKeys(table, prefix string) ([]string, error) {
+	var data []string
+	err := p.iterate(tablePrefix(table, prefix), func(row, _, _ string, _ []byte) bool {
+		if len(data) == 0 || data[len(data)-1] != row {
+			data = append(data, row)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read rows: %v", err)
+	}
+	return data, nil
+}
+
+// Clear deletes the rows of all tables
+func (p *PebbleStore) Clear() error {
+	iter, err := p.db.NewIter(nil)
+	if err != nil {
+		return fmt.Errorf("could not create iterator: %v", err)
+	}
+	var first, last []byte
+	if iter.First() {
+		first = bytes.Clone(iter.Key())
+	}
+	if iter.Last() {
+		last = append(bytes.Clone(iter.Key()), keySeparator)
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("could not close iterator: %v", err)
+	}
+	if first == nil {
+		return nil
+	}
+	if err := p.db.DeleteRange(first, last, pebble.Sync); err != nil {
+		return fmt.Errorf("could not drop all rows: %v", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the pebble database
+func (p *PebbleStore) Close() error {
+	if err := p.db.Close(); err != nil {
+		return fmt.Errorf("could not close pebble db: %v", err)
+	}
+	return nil
+}
+
+// iterate calls fn for every cell whose key starts with the prefix until fn returns false
+func (p *PebbleStore) iterate(prefix []byte, fn func(row, family, column string, value []byte) bool) error {
+	iter, err := p.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	table, _, _ := bytes.Cut(prefix, []byte{keySeparator})
+	for iter.First(); iter.Valid(); iter.Next() {
+		row, family, column, err := parseCellKey(string(table), iter.Key())
+		if err != nil {
+			return err
+		}
+		if !fn(row, family, column, bytes.Clone(iter.Value())) {
+			break
+		}
+	}
+	return iter.Error()
+}
+
+// tablePrefix returns the prefix of all cells of the rows of the table starting with the prefix
+func tablePrefix(table, prefix string) []byte {
+	key := make([]byte, 0, len(table)+len(prefix)+1)
+	key = append(key, table...)
+	key = append(key, keySeparator)
+	return append(key, prefix...)
+}
+
+// rowKey returns the prefix of all cells of exactly the given row
+func rowKey(table, row string) []byte {
+	return append(tablePrefix(table, row), keySeparator)
+}
+
+func cellKey(table, row, family, column string) []byte {
+	key := rowKey(table, row)
+	key = append(key, family...)
+	key = append(key, keySeparator)
+	return append(key, column...)
+}
+
+func parseCellKey(table string, key []byte) (row, family, column string, err error) {
+	parts := bytes.SplitN(key[len(table)+1:], []byte{keySeparator}, 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("invalid cell key %x", key)
+	}
+	return string(parts[0]), string(parts[1]), string(parts[2]), nil
+}
+
+// prefixUpperBound returns the smallest key that is larger than all keys starting with the prefix
+func prefixUpperBound(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
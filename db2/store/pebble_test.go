@@ -0,0 +1,16 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db2/store"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db2/storetest"
+)
+
+func TestPebbleStore(t *testing.T) {
+	db, err := store.NewPebble(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	storetest.RunStoreTests(t, store.Wrap(db, storetest.Table, storetest.Family))
+}
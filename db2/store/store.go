@@ -12,7 +12,66 @@ type Store interface {
 	Clear() error
 }
 
+// TableStore is a backend holding multiple tables of rows with column families
+// It is turned into a Store of a single table and family with Wrap
+type TableStore interface {
+	Add(table, family, key, column string, data []byte, allowDuplicate bool) error
+	BulkAdd(table string, itemsByKey map[string][]Item) error
+	Read(table, family, prefix string) ([][]byte, error)
+	GetRow(table, key string) (map[string][]byte, error)
+	GetRowKeys(table, prefix string) ([]string, error)
+	GetLatestValue(table, family, key string) ([]byte, error)
+	GetRowsRange(table, high, low string) (map[string]map[string][]byte, error)
+	Close() error
+	Clear() error
+}
+
 var (
 	_ Store = (*TableWrapper)(nil)
 	_ Store = (*RemoteClient)(nil)
+
+	_ TableStore = (*BigTableStore)(nil)
+	_ TableStore = (*PebbleStore)(nil)
 )
+
+type TableWrapper struct {
+	TableStore
+	table  string
+	family string
+}
+
+func Wrap(db TableStore, table string, family string) TableWrapper {
+	return TableWrapper{
+		TableStore: db,
+		table:      table,
+		family:     family,
+	}
+}
+
+func (w TableWrapper) Add(key, column string, data []byte, allowDuplicate bool) error {
+	return w.TableStore.Add(w.table, w.family, key, column, data, allowDuplicate)
+}
+
+func (w TableWrapper) Read(prefix string) ([][]byte, error) {
+	return w.TableStore.Read(w.table, w.family, prefix)
+}
+
+func (w TableWrapper) GetLatestValue(key string) ([]byte, error) {
+	return w.TableStore.GetLatestValue(w.table, w.family, key)
+}
+
+func (w TableWrapper) GetRow(key string) (map[string][]byte, error) {
+	return w.TableStore.GetRow(w.table, key)
+}
+
+func (w TableWrapper) GetRowKeys(prefix string) ([]string, error) {
+	return w.TableStore.GetRowKeys(w.table, prefix)
+}
+
+func (w TableWrapper) BulkAdd(itemsByKey map[string][]Item) error {
+	return w.TableStore.BulkAdd(w.table, itemsByKey)
+}
+
+func (w TableWrapper) GetRowsRange(high, low string) (map[string]map[string][]byte, error) {
+	return w.TableStore.GetRowsRange(w.table, high, low)
+}
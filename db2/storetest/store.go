@@ -0,0 +1,305 @@
+package storetest
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db2/store"
+)
+
+// Table and Family are the table and column family the store under test has to be wrapped with
+const (
+	Table  = "testTable"
+	Family = "testFamily"
+)
+
+// RunStoreTests runs the conformance tests every store.Store backend has to pass against db,
+// the store is cleared between the tests and closed at the end
+func RunStoreTests(t *testing.T, db store.Store) {
+	t.Run("AddAndRead", func(t *testing.T) {
+		testAddAndRead(t, db)
+	})
+	t.Run("GetRow", func(t *testing.T) {
+		testGetRow(t, db)
+	})
+	t.Run("GetRowsRange", func(t *testing.T) {
+		testGetRowsRange(t, db)
+	})
+
+	if err := db.Close(); err != nil {
+		t.Errorf("cannot close db: %v", err)
+	}
+}
+
+func testAddAndRead(t *testing.T, db store.Store) {
+	type item struct {
+		key    string
+		column string
+		data   string
+	}
+	tests := []struct {
+		name     string
+		bulk     bool
+		items    []item
+		expected []string
+	}{
+		{
+			name: "simple add",
+			items: []item{{
+				key:    "foo",
+				column: "bar",
+				data:   "foobar",
+			}},
+			expected: []string{"foobar"},
+		},
+		{
+			name: "bulk add",
+			bulk: true,
+			items: []item{{
+				key:    "key1",
+				column: "col1",
+				data:   "foobar",
+			}, {
+				key:    "key2",
+				column: "col2",
+				data:   "foobar",
+			}, {
+				key:    "key3",
+				column: "col3",
+				data:   "foobar",
+			}},
+			expected: []string{"foobar", "foobar", "foobar"},
+		},
+		{
+			name: "dont duplicate",
+			items: []item{{
+				key:    "foo",
+				column: "bar",
+				data:   "foobar",
+			}, {
+				key:    "foo",
+				column: "bar",
+				data:   "foobar",
+			}},
+			expected: []string{"foobar"},
+		},
+		{
+			name: "with a prefix",
+			items: []item{{
+				key: "foo",
+			}, {
+				key: "foofoo",
+			}, {
+				key: "foofoofoo",
+			}, {
+				key: "bar",
+			}},
+			expected: []string{"", "", "", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				_ = db.Clear()
+			}()
+
+			if tt.bulk {
+				itemsByKey := make(map[string][]store.Item)
+				for _, item := range tt.items {
+					itemsByKey[item.key] = append(itemsByKey[item.key], store.Item{
+						Family: Family,
+						Column: item.column,
+						Data:   []byte(item.data),
+					})
+				}
+				if err := db.BulkAdd(itemsByKey); err != nil {
+					t.Error(err)
+				}
+			} else {
+				for _, it := range tt.items {
+					if err := db.Add(it.key, it.column, []byte(it.data), false); err != nil {
+						t.Error(err)
+					}
+				}
+			}
+
+			t.Run("Read", func(t *testing.T) {
+				res, err := db.Read("")
+				if err != nil {
+					t.Error(err)
+				}
+				if got, want := len(res), len(tt.expected); got != want {
+					t.Errorf("got %v want %v", got, want)
+				}
+				for _, data := range res {
+					if !slices.Contains(tt.expected, string(data)) {
+						t.Errorf("wrong data %s", data)
+					}
+				}
+			})
+
+			t.Run("GetLatestValue", func(t *testing.T) {
+				for _, it := range tt.items {
+					v, err := db.GetLatestValue(it.key)
+					if err != nil {
+						t.Error(err)
+					}
+					if got, want := string(v), it.data; got != want {
+						t.Errorf("got %v want %v", got, want)
+					}
+				}
+			})
+
+			t.Run("GetRowKeys", func(t *testing.T) {
+				for _, it := range tt.items {
+					keys, err := db.GetRowKeys(it.key)
+					if err != nil {
+						t.Error(err)
+					}
+					count, found := 0, false
+					for _, expected := range tt.items {
+						if !strings.HasPrefix(expected.key, it.key) {
+							continue
+						}
+						// don't count duplicate inputs since the add prevent duplicate keys
+						if expected.key == it.key && found {
+							continue
+						}
+						found = expected.key == it.key
+						count++
+						if !slices.Contains(keys, expected.key) {
+							t.Errorf("missing %v in %v", expected.key, keys)
+						}
+					}
+					if got, want := len(keys), count; got != want {
+						t.Errorf("got %v want %v", got, want)
+					}
+				}
+			})
+		})
+	}
+
+}
+
+func testGetRow(t *testing.T, db store.Store) {
+	defer func() {
+		_ = db.Clear()
+	}()
+
+	if _, err := db.GetRow("foo"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("got %v want %v", err, store.ErrNotFound)
+	}
+
+	err := db.BulkAdd(map[string][]store.Item{
+		"foo": {
+			{Family: Family, Column: "col1", Data: []byte("1")},
+			{Family: Family, Column: "col2", Data: []byte("2")},
+		},
+		"bar": {
+			{Family: Family, Column: "col1", Data: []byte("3")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row, err := db.GetRow("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(row), 2; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	for column, want := range map[string]string{Family + ":col1": "1", Family + ":col2": "2"} {
+		if got := string(row[column]); got != want {
+			t.Errorf("column %v: got %v want %v", column, got, want)
+		}
+	}
+}
+
+func testGetRowsRange(t *testing.T, db store.Store) {
+	defer func() {
+		_ = db.Clear()
+	}()
+
+	keys := []string{"10:000000000000", "1:999999999997", "1:999999999998", "1:999999999999", "2:000000000000"}
+	itemsByKey := make(map[string][]store.Item)
+	for _, key := range keys {
+		itemsByKey[key] = []store.Item{{Family: Family, Column: "col", Data: []byte(key)}}
+	}
+	if err := db.BulkAdd(itemsByKey); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		high     string
+		low      string
+		expected []string
+	}{
+		{
+			name:     "include limits",
+			high:     "1:999999999999",
+			low:      "1:999999999998",
+			expected: []string{"1:999999999998", "1:999999999999"},
+		},
+		{
+			name:     "single row",
+			high:     "1:999999999997",
+			low:      "1:999999999997",
+			expected: []string{"1:999999999997"},
+		},
+		{
+			name:     "lexicographic order",
+			high:     "2:000000000000",
+			low:      "1:999999999999",
+			expected: []string{"1:999999999999", "2:000000000000"},
+		},
+		{
+			name:     "limits without rows",
+			high:     "1:999999999999z",
+			low:      "1:9",
+			expected: []string{"1:999999999997", "1:999999999998", "1:999999999999"},
+		},
+		{
+			name: "empty range",
+			high: "3",
+			low:  "2:1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := db.GetRowsRange(tt.high, tt.low)
+			if len(tt.expected) == 0 {
+				if !errors.Is(err, store.ErrNotFound) {
+					t.Errorf("got %v want %v", err, store.ErrNotFound)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(rows), len(tt.expected); got != want {
+				t.Errorf("got %v want %v", got, want)
+			}
+			for _, key := range tt.expected {
+				if got, want := string(rows[key][Family+":col"]), key; got != want {
+					t.Errorf("got %v want %v", got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("GetRowKeys ordered", func(t *testing.T) {
+		res, err := db.GetRowKeys("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(res, keys) {
+			t.Errorf("got %v want %v", res, keys)
+		}
+	})
+}
@@ -11,6 +11,9 @@ const BT_COLUMN_TRACES = "t"
 const BT_COLUMNFAMILY_UNCLES = "u"
 const BT_COLUMN_UNCLES = "u"
 
+// BT_COLUMN_NUMBER holds the block number in the rows indexing block and transaction hashes
+const BT_COLUMN_NUMBER = "n"
+
 const MAX_EL_BLOCK_NUMBER = int64(1_000_000_000_000 - 1)
 
 var raw = map[string][]string{
@@ -0,0 +1,108 @@
+package erc4337
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 49628fd1471006c1482da88028e9ce4dbb080b815c9b0344d39e5a8e6ec1419f
+var UserOperationEventTopic []byte = []byte{0x49, 0x62, 0x8f, 0xd1, 0x47, 0x10, 0x06, 0xc1, 0x48, 0x2d, 0xa8, 0x80, 0x28, 0xe9, 0xce, 0x4d, 0xbb, 0x08, 0x0b, 0x81, 0x5c, 0x9b, 0x03, 0x44, 0xd3, 0x9e, 0x5a, 0x8e, 0x6e, 0xc1, 0x41, 0x9f}
+
+// EntryPoints are the canonical EntryPoint deployments of v0.6, v0.7 and v0.8, only events of these contracts are indexed
+var EntryPoints = []common.Address{
+	common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+	common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032"),
+	common.HexToAddress("0x4337084D9E255Ff0702461CF8895CE9E3b5Ff108"),
+}
+
+const entryPointV06ABI = `[{"inputs":[{"components":[{"name":"sender","type":"address"},{"name":"nonce","type":"uint256"},{"name":"initCode","type":"bytes"},{"name":"callData","type":"bytes"},{"name":"callGasLimit","type":"uint256"},{"name":"verificationGasLimit","type":"uint256"},{"name":"preVerificationGas","type":"uint256"},{"name":"maxFeePerGas","type":"uint256"},{"name":"maxPriorityFeePerGas","type":"uint256"},{"name":"paymasterAndData","type":"bytes"},{"name":"signature","type":"bytes"}],"name":"ops","type":"tuple[]"},{"name":"beneficiary","type":"address"}],"name":"handleOps","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+// the packed user operation of v0.7 is also used by v0.8
+const entryPointV07ABI = `[{"inputs":[{"components":[{"name":"sender","type":"address"},{"name":"nonce","type":"uint256"},{"name":"initCode","type":"bytes"},{"name":"callData","type":"bytes"},{"name":"accountGasLimits","type":"bytes32"},{"name":"preVerificationGas","type":"uint256"},{"name":"gasFees","type":"bytes32"},{"name":"paymasterAndData","type":"bytes"},{"name":"signature","type":"bytes"}],"name":"ops","type":"tuple[]"},{"name":"beneficiary","type":"address"}],"name":"handleOps","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+var EntryPointV06Abi, _ = abi.JSON(strings.NewReader(entryPointV06ABI))
+var EntryPointV07Abi, _ = abi.JSON(strings.NewReader(entryPointV07ABI))
+
+// UserOperation holds the fields of a user operation of a handleOps call that are common to all EntryPoint versions
+type UserOperation struct {
+	Sender   common.Address
+	Nonce    *big.Int
+	InitCode []byte
+	CallData []byte
+}
+
+// UserOperationEvent is emitted by the EntryPoint after the execution of each user operation
+type UserOperationEvent struct {
+	UserOpHash    common.Hash
+	Sender        common.Address
+	Paymaster     common.Address
+	Nonce         *big.Int
+	Success       bool
+	ActualGasCost *big.Int
+	ActualGasUsed *big.Int
+}
+
+func IsEntryPoint(address []byte) bool {
+	for _, entryPoint := range EntryPoints {
+		if bytes.Equal(entryPoint.Bytes(), address) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseHandleOps decodes the user operations of the calldata of a handleOps call of any EntryPoint version
+func ParseHandleOps(input []byte) ([]UserOperation, error) {
+	if len(input) < 4 {
+		return nil, fmt.Errorf("calldata is too short")
+	}
+	for _, entryPointAbi := range []abi.ABI{EntryPointV06Abi, EntryPointV07Abi} {
+		method := entryPointAbi.Methods["handleOps"]
+		if !bytes.Equal(input[:4], method.ID) {
+			continue
+		}
+		args, err := method.Inputs.Unpack(input[4:])
+		if err != nil {
+			return nil, fmt.Errorf("error unpacking handleOps calldata: %w", err)
+		}
+		// the unpacked tuples are anonymous structs that differ between the versions, read the common fields by name
+		ops := reflect.ValueOf(args[0])
+		if ops.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("unexpected type %v of unpacked user operations", ops.Type())
+		}
+		ret := make([]UserOperation, 0, ops.Len())
+		for i := 0; i < ops.Len(); i++ {
+			op := ops.Index(i)
+			ret = append(ret, UserOperation{
+				Sender:   op.FieldByName("Sender").Interface().(common.Address),
+				Nonce:    op.FieldByName("Nonce").Interface().(*big.Int),
+				InitCode: op.FieldByName("InitCode").Interface().([]byte),
+				CallData: op.FieldByName("CallData").Interface().([]byte),
+			})
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("calldata is not a handleOps call")
+}
+
+// ParseUserOperationEvent decodes a UserOperationEvent log, it returns nil if the log is not a UserOperationEvent
+func ParseUserOperationEvent(topics [][]byte, data []byte) *UserOperationEvent {
+	if len(topics) != 4 || !bytes.Equal(topics[0], UserOperationEventTopic) || len(data) != 4*32 {
+		return nil
+	}
+	return &UserOperationEvent{
+		UserOpHash:    common.BytesToHash(topics[1]),
+		Sender:        common.BytesToAddress(topics[2]),
+		Paymaster:     common.BytesToAddress(topics[3]),
+		Nonce:         new(big.Int).SetBytes(data[:32]),
+		Success:       new(big.Int).SetBytes(data[32:64]).Sign() != 0,
+		ActualGasCost: new(big.Int).SetBytes(data[64:96]),
+		ActualGasUsed: new(big.Int).SetBytes(data[96:128]),
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"strings"
 	"time"
@@ -91,6 +92,22 @@ func GetEth1Transaction(hash common.Hash, currency string) (*types.Eth1TxData, e
 	txPageData.BlockNumber = header.Number.Int64()
 	txPageData.Timestamp = time.Unix(int64(header.Time), 0)
 
+	if txPageData.TargetIsContract && !txPageData.IsContractCreation {
+		// calls to a proxy are decoded with the abi of its implementation at the block of the tx
+		abiAddress := *txPageData.To
+		proxy, err := db.BigtableClient.GetProxyImplementationAt(txPageData.To.Bytes(), txPageData.BlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving proxy implementation of tx recipient %v: %w", txPageData.To, err)
+		}
+		if proxy != nil && len(proxy.Implementation) > 0 {
+			implementation := common.BytesToAddress(proxy.Implementation)
+			txPageData.ProxyImplementation = &implementation
+			txPageData.ProxyType = utils.FormatProxyType(proxy.Type)
+			abiAddress = implementation
+		}
+		txPageData.Method, txPageData.DecodedCallData = decodeCallData(tx.Data(), abiAddress)
+	}
+
 	msg, err := core.TransactionToMessage(tx, geth_types.NewPragueSigner(tx.ChainId()), header.BaseFee)
 	if err != nil {
 		return nil, fmt.Errorf("error getting sender of tx: %w", err)
@@ -135,6 +152,24 @@ func GetEth1Transaction(hash common.Hash, currency string) (*types.Eth1TxData, e
 		}
 	}
 
+	if receipt.Type == geth_types.SetCodeTxType {
+		chainId := new(big.Int).SetUint64(utils.Config.Chain.ClConfig.DepositChainID)
+		txPageData.SetCodeAuthorizations = make([]*types.Eth1TxSetCodeAuthorization, 0, len(tx.SetCodeAuthorizations()))
+		for _, auth := range tx.SetCodeAuthorizations() {
+			a := &types.Eth1TxSetCodeAuthorization{
+				Address: auth.Address,
+				ChainId: auth.ChainID.ToBig(),
+				Nonce:   auth.Nonce,
+			}
+			authority, err := auth.Authority()
+			if err == nil {
+				a.Authority = authority.Bytes()
+				a.Valid = (a.ChainId.Sign() == 0 || a.ChainId.Cmp(chainId) == 0) && auth.Nonce < math.MaxUint64
+			}
+			txPageData.SetCodeAuthorizations = append(txPageData.SetCodeAuthorizations, a)
+		}
+	}
+
 	data, err := rpc.CurrentErigonClient.TraceGethTx(tx.Hash().Hex())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get parity trace for revert reason: %w", err)
@@ -176,7 +211,12 @@ func GetEth1Transaction(hash common.Hash, currency string) (*types.Eth1TxData, e
 
 		for _, log := range receipt.Logs {
 			if cmEntry, wasContractMetadataCached = contractMetadataCache[log.Address]; !wasContractMetadataCached {
-				cmEntry.meta, cmEntry.err = db.BigtableClient.GetContractMetadata(log.Address.Bytes())
+				metadataAddress := log.Address
+				// events of the implementation are emitted by the proxy
+				if txPageData.ProxyImplementation != nil && log.Address == *txPageData.To {
+					metadataAddress = *txPageData.ProxyImplementation
+				}
+				cmEntry.meta, cmEntry.err = db.BigtableClient.GetContractMetadata(metadataAddress.Bytes())
 				contractMetadataCache[log.Address] = cmEntry
 			}
 			if cmEntry.err != nil || cmEntry.meta == nil || cmEntry.meta.ABI == nil {
@@ -337,6 +377,45 @@ func GetEth1Transaction(hash common.Hash, currency string) (*types.Eth1TxData, e
 	return txPageData, nil
 }
 
+// decodeCallData returns the method and the decoded inputs of a contract call, it uses the verified abi of the given
+// contract and falls back to the label of the method signature if the abi is not known
+func decodeCallData(data []byte, contract common.Address) (string, []*types.Eth1DecodedCallInput) {
+	if len(data) < 4 {
+		return "", nil
+	}
+
+	meta, err := db.BigtableClient.GetContractMetadata(contract.Bytes())
+	if err != nil || meta == nil || meta.ABI == nil {
+		return db.BigtableClient.GetMethodLabel(data, types.CONTRACT_PRESENT), nil
+	}
+	method, err := meta.ABI.MethodById(data[:4])
+	if err != nil {
+		return db.BigtableClient.GetMethodLabel(data, types.CONTRACT_PRESENT), nil
+	}
+
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		logger.Warnf("error decoding call data of method [%v] of contract [%v]: %v", method.Sig, contract, err)
+		return method.Sig, nil
+	}
+	inputs := make([]*types.Eth1DecodedCallInput, 0, len(values))
+	for i, input := range method.Inputs {
+		decoded := &types.Eth1DecodedCallInput{
+			Name:  input.Name,
+			Type:  input.Type.String(),
+			Value: fmt.Sprintf("%v", values[i]),
+		}
+		switch input.Type.T {
+		case abi.AddressTy:
+			decoded.Address = values[i].(common.Address)
+		case abi.BytesTy, abi.FixedBytesTy:
+			decoded.Value = fmt.Sprintf("0x%x", values[i])
+		}
+		inputs = append(inputs, decoded)
+	}
+	return method.Sig, inputs
+}
+
 func IsContract(ctx context.Context, address common.Address) (bool, error) {
 	cacheKey := fmt.Sprintf("%d:isContract:%s", utils.Config.Chain.ClConfig.DepositChainID, address.String())
 	if wanted, err := cache.TieredCache.GetBoolWithLocalTimeout(cacheKey, time.Hour); err == nil {
@@ -357,6 +436,26 @@ func IsContract(ctx context.Context, address common.Address) (bool, error) {
 	return isContract, nil
 }
 
+// GetSetCodeDelegation returns the address an EOA delegates its code to with EIP-7702 or nil if it has no delegation.
+// The delegation is read from the current code of the address, authorizations that were signed but not applied (for
+// example because of a stale nonce) therefore do not show up.
+func GetSetCodeDelegation(ctx context.Context, address common.Address) (*common.Address, error) {
+	code, err := rpc.CurrentErigonClient.GetNativeClient().CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving code data for address %v: %w", address, err)
+	}
+	return setCodeDelegation(code), nil
+}
+
+// setCodeDelegation parses the 0xef0100 delegation designator of the code of an EOA
+func setCodeDelegation(code []byte) *common.Address {
+	delegate, ok := geth_types.ParseDelegation(code)
+	if !ok {
+		return nil
+	}
+	return &delegate
+}
+
 func getBlockHeaderByHash(ctx context.Context, hash common.Hash) (*geth_types.Header, error) {
 	header, err := rpc.CurrentErigonClient.GetNativeClient().HeaderByHash(ctx, hash)
 	if err != nil {
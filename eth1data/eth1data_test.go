@@ -116,6 +116,28 @@ func TestGetEth1Transaction(t *testing.T) {
 	}
 }
 
+func TestSetCodeDelegation(t *testing.T) {
+	delegate := common.HexToAddress("0x63c0c19a282a1b52b07dd5a65b58948a07dae32b")
+	tests := []struct {
+		name string
+		code []byte
+		want *common.Address
+	}{
+		{name: "eoa", code: nil, want: nil},
+		{name: "delegated eoa", code: append([]byte{0xef, 0x01, 0x00}, delegate.Bytes()...), want: &delegate},
+		{name: "contract", code: common.FromHex("0x6080604052348015600f57600080fd5b50"), want: nil},
+		{name: "truncated designator", code: append([]byte{0xef, 0x01, 0x00}, delegate.Bytes()[:19]...), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := setCodeDelegation(tt.code)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 type noRedis struct {
 }
 
@@ -12,7 +12,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 	"github.com/gobitfly/eth2-beaconchain-explorer/version"
 
@@ -211,6 +213,7 @@ func (bi *BlobIndexer) IndexBlobsAtSlot(slot uint64) error {
 		return nil
 	}
 
+	stats := make([]*types.BlobSidecarStats, len(blobSidecar.Data))
 	g, gCtx := errgroup.WithContext(ctx)
 	g.SetLimit(4)
 	for i, d := range blobSidecar.Data {
@@ -233,9 +236,17 @@ func (bi *BlobIndexer) IndexBlobsAtSlot(slot uint64) error {
 				return fmt.Errorf("error decoding blob at index %v: %w", i, err)
 			}
 
-			versionedBlobHash := fmt.Sprintf("%#x", utils.VersionedBlobHash(kzgCommitment).Bytes())
+			versionedBlobHashBytes := utils.VersionedBlobHash(kzgCommitment).Bytes()
+			versionedBlobHash := fmt.Sprintf("%#x", versionedBlobHashBytes)
 			key := fmt.Sprintf("blobs/%s", versionedBlobHash)
 
+			nonZeroBytes := utils.BlobNonZeroBytes(blob)
+			stats[i] = &types.BlobSidecarStats{
+				BlobVersionedHash: versionedBlobHashBytes,
+				Slot:              d.Slot,
+				NonZeroBytes:      nonZeroBytes,
+			}
+
 			tS3HeadObj := time.Now()
 			_, err = bi.S3Client.HeadObject(gCtx, &s3.HeadObjectInput{
 				Bucket: &utils.Config.BlobIndexer.S3.Bucket,
@@ -260,6 +271,7 @@ func (bi *BlobIndexer) IndexBlobsAtSlot(slot uint64) error {
 							"proposer_index":    fmt.Sprintf("%d", d.ProposerIndex),
 							"kzg_commitment":    d.KzgCommitment,
 							"kzg_proof":         d.KzgProof,
+							"non_zero_bytes":    fmt.Sprintf("%d", nonZeroBytes),
 						},
 					})
 					metrics.TaskDuration.WithLabelValues("blobindexer_put_blob").Observe(time.Since(tS3PutObj).Seconds())
@@ -278,6 +290,13 @@ func (bi *BlobIndexer) IndexBlobsAtSlot(slot uint64) error {
 		return fmt.Errorf("error indexing blobs at slot %v: %w", slot, err)
 	}
 
+	if utils.Config.BlobIndexer.SaveStats {
+		err = db.SaveBlobSidecarStats(stats)
+		if err != nil {
+			return fmt.Errorf("error saving blob stats at slot %v: %w", slot, err)
+		}
+	}
+
 	return nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
 	"github.com/sirupsen/logrus"
@@ -24,6 +25,7 @@ func Start(client rpc.Client) {
 	go syncCommitteesExporter(client)
 	go syncCommitteesCountExporter()
 	go NewPendingQueueIndexer(client).Start()
+	go headEventPublisher(client)
 	if utils.Config.SSVExporter.Enabled {
 		go ssvExporter()
 	}
@@ -38,6 +40,14 @@ func Start(client rpc.Client) {
 	if utils.Config.MevBoostRelayExporter.Enabled {
 		go mevBoostRelaysExporter()
 	}
+
+	if utils.Config.Slasher.Enabled {
+		err := initSlasher()
+		if err != nil {
+			utils.LogFatal(err, "error initializing slasher", 0)
+		}
+	}
+
 	// wait until the beacon-node is available
 	for {
 		head, err := client.GetChainHead()
@@ -71,6 +81,60 @@ func Start(client rpc.Client) {
 	}
 }
 
+// headEventPublisher publishes a head event to the event stream for every head event of the beacon node. The proposer
+// of the head is looked up in the proposer assignments of its epoch so that the blocks do not have to be retrieved,
+// the subscription is retried with backoff while the beacon node is unavailable.
+func headEventPublisher(client rpc.Client) {
+	if !services.EventsEnabled() {
+		logger.Infof("event stream is disabled, not publishing head events")
+		return
+	}
+
+	var proposers map[uint64]uint64
+	proposersEpoch := uint64(0)
+	backoff := time.Second
+	for {
+		events, err := client.GetHeadEventChan()
+		if err != nil {
+			logger.Errorf("error subscribing to head events, retrying in %v: %v", backoff, err)
+			time.Sleep(backoff)
+			backoff = min(backoff*2, time.Minute)
+			continue
+		}
+		backoff = time.Second
+
+		for e := range events {
+			slot := uint64(e.Slot)
+			epoch := utils.EpochOfSlot(slot)
+			event := &types.StreamEvent{
+				Type:      types.StreamEventHead,
+				Slot:      slot,
+				Epoch:     epoch,
+				BlockRoot: e.Block,
+			}
+
+			if proposers == nil || proposersEpoch != epoch {
+				// the assignments are cached by the client as they are also needed by the slot exporter
+				assignments, err := client.GetEpochAssignments(epoch)
+				if err != nil {
+					logger.Warnf("error getting proposer assignments of epoch %v for head event: %v", epoch, err)
+				} else {
+					proposers = assignments.ProposerAssignments
+					proposersEpoch = epoch
+				}
+			}
+			if proposer, ok := proposers[slot]; ok && proposersEpoch == epoch {
+				event.Proposer = &proposer
+			}
+
+			err = services.PublishEvent(event)
+			if err != nil {
+				logger.Errorf("error publishing head event for slot %v: %v", slot, err)
+			}
+		}
+	}
+}
+
 func networkLivenessUpdater(client rpc.Client) {
 	var prevHeadEpoch uint64
 	err := db.WriterDb.Get(&prevHeadEpoch, "SELECT COALESCE(MAX(headepoch), 0) FROM network_liveness")
@@ -0,0 +1,192 @@
+package exporter
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/slasher"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultSlasherHistoryLength is the number of epochs the slasher keeps if no history length is configured
+const defaultSlasherHistoryLength = 256
+
+var (
+	blockSlasher *slasher.Slasher
+	// blockSlasherStale is set if the slasher has processed blocks whose export was rolled back, it is rebuilt from the
+	// committed history before the next block
+	blockSlasherStale bool
+)
+
+// initSlasher creates the slasher and replays the attestations of its history that were exported before a restart
+func initSlasher() error {
+	historyLength := utils.Config.Slasher.HistoryLength
+	if historyLength == 0 {
+		historyLength = defaultSlasherHistoryLength
+	}
+	if utils.Config.Slasher.Path != "" {
+		s, err := slasher.Open(historyLength, utils.Config.Slasher.Path)
+		if err != nil {
+			return err
+		}
+		blockSlasher = s
+	} else {
+		blockSlasher = slasher.New(historyLength)
+	}
+	return replaySlasher()
+}
+
+// replaySlasher rebuilds the slasher from the attestations of its history in the database
+func replaySlasher() error {
+	start := time.Now()
+	err := blockSlasher.Reset()
+	if err != nil {
+		return err
+	}
+
+	var headEpoch uint64
+	err = db.WriterDb.Get(&headEpoch, "SELECT COALESCE(MAX(target_epoch), 0) FROM slasher_attestations")
+	if err != nil {
+		return fmt.Errorf("error getting head epoch of the slasher history: %w", err)
+	}
+	err = blockSlasher.Advance(headEpoch)
+	if err != nil {
+		return err
+	}
+
+	attestations, err := db.GetSlasherAttestations(blockSlasher.HistoryStart())
+	if err != nil {
+		return err
+	}
+	for _, a := range attestations {
+		if _, err := blockSlasher.ProcessAttestation(a.IndexedAttestation); err != nil {
+			return fmt.Errorf("error replaying slasher attestation of slot %v: %w", a.Data.Slot, err)
+		}
+	}
+	err = blockSlasher.Flush()
+	if err != nil {
+		return err
+	}
+	logger.Infof("slasher replayed %v attestations of epochs %v to %v, took %v", len(attestations), blockSlasher.HistoryStart(), headEpoch, time.Since(start))
+	return nil
+}
+
+// invalidateSlasher marks the slasher as stale after the export tx has been rolled back
+func invalidateSlasher() {
+	if blockSlasher != nil {
+		blockSlasherStale = true
+	}
+}
+
+// runSlasher checks the proposal and the attestations of the block for double proposals, double votes and surround
+// votes and saves candidate slashings for review before the offences are included on chain. Everything is saved with
+// the tx of the export, blocks and attestations that are already part of the history are not checked again, so slots
+// that are exported again after a reorg do not report their offences twice.
+func runSlasher(block *types.Block, tx *sqlx.Tx) error {
+	if block.Status != 1 || len(block.BlockRoot) == 0 {
+		return nil
+	}
+
+	if blockSlasherStale {
+		// the slasher history of the rolled back tx has not been written, so the committed history is complete
+		err := replaySlasher()
+		if err != nil {
+			return fmt.Errorf("error rebuilding slasher: %w", err)
+		}
+		blockSlasherStale = false
+	}
+
+	previousStart := blockSlasher.HistoryStart()
+	err := blockSlasher.Advance(utils.EpochOfSlot(block.Slot))
+	if err != nil {
+		return err
+	}
+	if start := blockSlasher.HistoryStart(); start > previousStart {
+		err := db.DeleteSlasherHistory(start, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	proposals, err := db.SaveSlasherProposal(block, tx)
+	if err != nil {
+		return err
+	}
+	for _, proposal := range proposals {
+		logger.Warnf("slasher detected a double proposal of validator %v at slot %v", block.Proposer, block.Slot)
+		err := db.SaveSlasherProposerSlashing(block.Slot, &types.ProposerSlashing{
+			ProposerIndex: block.Proposer,
+			Header1:       proposal,
+			Header2:       block,
+		}, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	attestations := make([]*types.SlasherAttestation, 0, len(block.Attestations))
+	for _, a := range block.Attestations {
+		dataRoot, err := slasher.DataRoot(a.Data)
+		if err != nil {
+			return fmt.Errorf("error computing data root of attestation of slot %v: %w", a.Data.Slot, err)
+		}
+		indices := slices.Clone(a.Attesters)
+		slices.Sort(indices)
+		attestations = append(attestations, &types.SlasherAttestation{
+			DataRoot: dataRoot[:],
+			IndexedAttestation: &types.IndexedAttestation{
+				Data:             a.Data,
+				AttestingIndices: slices.Compact(indices),
+				Signature:        a.Signature,
+			},
+		})
+	}
+	// the attestations are saved first so that offences within the block find their conflicting attestation
+	added, err := db.SaveSlasherAttestations(attestations, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range added {
+		offences, err := blockSlasher.ProcessAttestation(a.IndexedAttestation)
+		if err != nil {
+			return fmt.Errorf("error processing slasher attestation of slot %v: %w", a.Data.Slot, err)
+		}
+		for _, offence := range offences {
+			err := saveSlasherOffence(block.Slot, a, offence, tx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return blockSlasher.Flush()
+}
+
+// saveSlasherOffence builds the attester slashing of the offence from the conflicting attestation in the history
+func saveSlasherOffence(slot uint64, attestation *types.SlasherAttestation, offence *slasher.Offence, tx *sqlx.Tx) error {
+	conflicting, err := db.GetSlasherValidatorAttestation(offence.ValidatorIndex, offence.ConflictingTargetEpoch, attestation.DataRoot, tx)
+	if err != nil {
+		return err
+	}
+	if conflicting == nil {
+		logger.Warnf("slasher detected a %v of validator %v at slot %v but the conflicting attestation is not in the history", offence.Type, offence.ValidatorIndex, slot)
+		return nil
+	}
+	logger.Warnf("slasher detected a %v of validator %v at slot %v", offence.Type, offence.ValidatorIndex, slot)
+
+	// the first attestation of a surround vote slashing has to be the surrounding one
+	first, second := conflicting, attestation
+	if offence.Surrounding {
+		first, second = attestation, conflicting
+	}
+	slashing := &types.AttesterSlashing{
+		Attestation1: first.IndexedAttestation,
+		Attestation2: second.IndexedAttestation,
+	}
+	return db.SaveSlasherAttesterSlashing(slot, string(offence.Type), []uint64{offence.ValidatorIndex}, slashing, first.DataRoot, second.DataRoot, tx)
+}
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
 	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
@@ -19,6 +21,15 @@ import (
 )
 
 func RunSlotExporter(client rpc.Client, firstRun bool) error {
+	err := runSlotExporter(client, firstRun)
+	if err != nil {
+		// the tx has been rolled back, the slasher has to forget the blocks it processed for it
+		invalidateSlasher()
+	}
+	return err
+}
+
+func runSlotExporter(client rpc.Client, firstRun bool) error {
 	// get the current chain head
 	head, err := client.GetChainHead()
 
@@ -115,8 +126,15 @@ func RunSlotExporter(client rpc.Client, firstRun bool) error {
 
 	// at this point we have all all data up to the current chain head in the database
 
+	// mark the blocks that have been orphaned since they were exported
+	// reorg events are published and the exported data is rolled back once the changes have been committed
+	reorgEvents, err := handleChainReorgs(client, head, &dbChainReorgStore{tx: tx})
+	if err != nil {
+		return fmt.Errorf("error handling chain reorgs: %w", err)
+	}
+
 	// check if any non-finalized slot has changed by comparing it with the node
-	dbNonFinalSlots, err := db.GetAllNonFinalizedSlots()
+	dbNonFinalSlots, err := db.GetAllNonFinalizedSlots(tx)
 	if err != nil {
 		return fmt.Errorf("error retrieving all non finalized slots from the db: %w", err)
 	}
@@ -131,34 +149,43 @@ func RunSlotExporter(client rpc.Client, firstRun bool) error {
 
 		if nodeSlotFinalized != dbSlot.Finalized {
 			// slot has finalized, mark it in the db
-			if header != nil && bytes.Equal(dbSlot.BlockRoot, utils.MustParseHex(header.Data.Root)) {
+			if dbSlot.Status == "3" {
+				// block has already been orphaned, simply mark it as final
+				logger.Infof("setting orphaned block at slot %v as finalized", dbSlot.Slot)
+				err := db.SetSlotFinalizationAndStatus(dbSlot.Slot, dbSlot.BlockRoot, nodeSlotFinalized, dbSlot.Status, tx)
+				if err != nil {
+					return fmt.Errorf("error setting block %v as finalized (orphaned): %w", dbSlot.Slot, err)
+				}
+			} else if header != nil && bytes.Equal(dbSlot.BlockRoot, utils.MustParseHex(header.Data.Root)) {
 				// no reorg happened, simply mark the slot as final
 				logger.Infof("setting slot %v as finalized (proposed)", dbSlot.Slot)
-				err := db.SetSlotFinalizationAndStatus(dbSlot.Slot, nodeSlotFinalized, dbSlot.Status, tx)
+				err := db.SetSlotFinalizationAndStatus(dbSlot.Slot, dbSlot.BlockRoot, nodeSlotFinalized, dbSlot.Status, tx)
 				if err != nil {
 					return fmt.Errorf("error setting slot %v as finalized (proposed): %w", dbSlot.Slot, err)
 				}
 			} else if header == nil && len(dbSlot.BlockRoot) < 32 {
 				// no reorg happened, mark the slot as missed
 				logger.Infof("setting slot %v as finalized (missed)", dbSlot.Slot)
-				err := db.SetSlotFinalizationAndStatus(dbSlot.Slot, nodeSlotFinalized, "2", tx)
+				err := db.SetSlotFinalizationAndStatus(dbSlot.Slot, dbSlot.BlockRoot, nodeSlotFinalized, "2", tx)
 				if err != nil {
 					return fmt.Errorf("error setting slot %v as finalized (missed): %w", dbSlot.Slot, err)
 				}
 			} else if header == nil && len(dbSlot.BlockRoot) == 32 {
 				// slot has been orphaned, mark the slot as orphaned
 				logger.Infof("setting slot %v as finalized (orphaned)", dbSlot.Slot)
-				err := db.SetSlotFinalizationAndStatus(dbSlot.Slot, nodeSlotFinalized, "3", tx)
+				err := db.SetSlotFinalizationAndStatus(dbSlot.Slot, dbSlot.BlockRoot, nodeSlotFinalized, "3", tx)
 				if err != nil {
 					return fmt.Errorf("error setting block %v as finalized (orphaned): %w", dbSlot.Slot, err)
 				}
+				reorgEvents = append(reorgEvents, newReorgEvent(dbSlot.Slot, dbSlot.BlockRoot, nil))
 			} else if header != nil && !bytes.Equal(utils.MustParseHex(header.Data.Root), dbSlot.BlockRoot) {
 				// we have a different block root for the slot in the db, mark the currently present one as orphaned and write the new one
 				logger.Infof("setting slot %v as orphaned and exporting new slot", dbSlot.Slot)
-				err := db.SetSlotFinalizationAndStatus(dbSlot.Slot, nodeSlotFinalized, "3", tx)
+				err := db.SetSlotFinalizationAndStatus(dbSlot.Slot, dbSlot.BlockRoot, nodeSlotFinalized, "3", tx)
 				if err != nil {
 					return fmt.Errorf("error setting block %v as finalized (orphaned): %w", dbSlot.Slot, err)
 				}
+				reorgEvents = append(reorgEvents, newReorgEvent(dbSlot.Slot, dbSlot.BlockRoot, header))
 				err = ExportSlot(client, dbSlot.Slot, utils.EpochOfSlot(dbSlot.Slot) == head.HeadEpoch, tx)
 				if err != nil {
 					return fmt.Errorf("error exporting slot %v: %w", dbSlot.Slot, err)
@@ -208,10 +235,270 @@ func RunSlotExporter(client rpc.Client, firstRun bool) error {
 		return fmt.Errorf("error committing tx: %w", err)
 	}
 
+	for _, event := range reorgEvents {
+		err := services.PublishEvent(event)
+		if err != nil {
+			logger.Errorf("error publishing chain reorg event for slot %v: %v", event.Slot, err)
+		}
+	}
+
+	// the bigtable data of orphaned blocks is only rolled back once they have been committed as orphaned
+	err = rollBackPendingChainReorgs(client, head)
+	if err != nil {
+		return fmt.Errorf("error rolling back chain reorgs: %w", err)
+	}
+
 	return nil
 
 }
 
+// chainReorgStore holds the reads and writes handleChainReorgs performs on the stored chain
+type chainReorgStore interface {
+	GetAllNonFinalizedSlots() ([]*db.GetAllNonFinalizedSlotsRow, error)
+	SetSlotOrphaned(slot uint64, blockRoot []byte) error
+	SaveChainReorg(reorg *types.ChainReorg) error
+}
+
+// chainReorgRollbackStore holds the reads and writes rollBackChainReorgs performs on the stored chain and bigtable
+type chainReorgRollbackStore interface {
+	GetPendingChainReorgs() ([]*types.ChainReorg, error)
+	GetOrphanedBlocks(fromSlot, toSlot uint64) ([]*types.CanonBlock, error)
+	DeleteAttestationInclusions(slot uint64, blockRoot []byte) error
+	DeleteValidatorBalancesAndIncome(epochs []uint64) error
+	ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool) error
+	InvalidateEpochs(epochs []uint64) error
+	SetChainReorgRolledBack(reorg *types.ChainReorg) error
+}
+
+// dbChainReorgStore is the chainReorgStore and chainReorgRollbackStore writing to the db within the given tx and to bigtable
+type dbChainReorgStore struct {
+	tx *sqlx.Tx
+}
+
+func (s *dbChainReorgStore) GetAllNonFinalizedSlots() ([]*db.GetAllNonFinalizedSlotsRow, error) {
+	return db.GetAllNonFinalizedSlots(s.tx)
+}
+
+func (s *dbChainReorgStore) SetSlotOrphaned(slot uint64, blockRoot []byte) error {
+	return db.SetSlotFinalizationAndStatus(slot, blockRoot, false, "3", s.tx)
+}
+
+func (s *dbChainReorgStore) SaveChainReorg(reorg *types.ChainReorg) error {
+	return db.SaveChainReorg(reorg, s.tx)
+}
+
+func (s *dbChainReorgStore) GetPendingChainReorgs() ([]*types.ChainReorg, error) {
+	return db.GetPendingChainReorgs(s.tx)
+}
+
+func (s *dbChainReorgStore) GetOrphanedBlocks(fromSlot, toSlot uint64) ([]*types.CanonBlock, error) {
+	return db.GetOrphanedBlocks(fromSlot, toSlot, s.tx)
+}
+
+func (s *dbChainReorgStore) DeleteAttestationInclusions(slot uint64, blockRoot []byte) error {
+	inclusions, err := db.GetBlockAttestationInclusions(blockRoot, s.tx)
+	if err != nil {
+		return err
+	}
+	return db.BigtableClient.DeleteAttestationInclusions(slot, inclusions)
+}
+
+func (s *dbChainReorgStore) DeleteValidatorBalancesAndIncome(epochs []uint64) error {
+	return db.BigtableClient.DeleteValidatorBalancesAndIncome(epochs)
+}
+
+func (s *dbChainReorgStore) ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool) error {
+	return ExportSlot(client, slot, isHeadEpoch, s.tx)
+}
+
+func (s *dbChainReorgStore) InvalidateEpochs(epochs []uint64) error {
+	return db.InvalidateEpochs(epochs, s.tx)
+}
+
+func (s *dbChainReorgStore) SetChainReorgRolledBack(reorg *types.ChainReorg) error {
+	return db.SetChainReorgRolledBack(reorg, s.tx)
+}
+
+// handleChainReorgs detects blocks that have been orphaned after they were exported. Starting at the latest exported
+// block the stored parent roots are followed back through all non finalized slots, which is as far as the rollback
+// looks back, every block that is not part of this chain and does not match the block of the node at its slot is
+// orphaned. The reorg is saved as pending, its exported data is rolled back by rollBackChainReorgs once the orphaned
+// blocks have been committed.
+func handleChainReorgs(client rpc.Client, head *types.ChainHead, store chainReorgStore) ([]*types.StreamEvent, error) {
+	dbSlots, err := store.GetAllNonFinalizedSlots()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving all non finalized slots from the db: %w", err)
+	}
+
+	orphaned := []*db.GetAllNonFinalizedSlotsRow{}
+	var expectedRoot []byte
+	for i := len(dbSlots) - 1; i >= 0; i-- {
+		dbSlot := dbSlots[i]
+		if dbSlot.Status != "1" || len(dbSlot.BlockRoot) != 32 {
+			continue
+		}
+		if expectedRoot != nil && bytes.Equal(dbSlot.BlockRoot, expectedRoot) {
+			expectedRoot = dbSlot.ParentRoot
+			continue
+		}
+
+		// the block is not the parent of the previous canonical block, this can either be a reorg or missed slots
+		// between the two blocks that have been proposed late, the node decides which one it is
+		header, err := client.GetBlockHeader(dbSlot.Slot)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving block root for slot %v: %w", dbSlot.Slot, err)
+		}
+		if header != nil && bytes.Equal(dbSlot.BlockRoot, utils.MustParseHex(header.Data.Root)) {
+			expectedRoot = dbSlot.ParentRoot
+			continue
+		}
+		orphaned = append(orphaned, dbSlot)
+	}
+
+	if len(orphaned) == 0 {
+		return nil, nil
+	}
+
+	// orphaned blocks have been collected from the newest to the oldest one
+	firstOrphaned := orphaned[len(orphaned)-1]
+	logger.Warnf("detected chain reorg of %v blocks starting at slot %v", len(orphaned), firstOrphaned.Slot)
+	if expectedRoot == nil {
+		logger.Errorf("no common ancestor of the chain reorg at slot %v found in the non finalized slots, only the blocks since slot %v are rolled back", firstOrphaned.Slot, dbSlots[0].Slot)
+	}
+
+	reorgEvents := make([]*types.StreamEvent, 0, len(orphaned))
+	var firstHeader *rpc.StandardBeaconHeaderResponse
+	for i := len(orphaned) - 1; i >= 0; i-- {
+		dbSlot := orphaned[i]
+
+		logger.Infof("setting block %#x at slot %v as orphaned", dbSlot.BlockRoot, dbSlot.Slot)
+		err := store.SetSlotOrphaned(dbSlot.Slot, dbSlot.BlockRoot)
+		if err != nil {
+			return nil, fmt.Errorf("error setting block %v as orphaned: %w", dbSlot.Slot, err)
+		}
+
+		header, err := client.GetBlockHeader(dbSlot.Slot)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving block root for slot %v: %w", dbSlot.Slot, err)
+		}
+		if dbSlot == firstOrphaned {
+			firstHeader = header
+		}
+		reorgEvents = append(reorgEvents, newReorgEvent(dbSlot.Slot, dbSlot.BlockRoot, header))
+	}
+
+	reorg := &types.ChainReorg{
+		Ts:           time.Now(),
+		Slot:         firstOrphaned.Slot,
+		Epoch:        utils.EpochOfSlot(firstOrphaned.Slot),
+		Depth:        uint64(len(orphaned)),
+		OldBlockRoot: firstOrphaned.BlockRoot,
+		LastSlot:     dbSlots[len(dbSlots)-1].Slot,
+	}
+	if firstHeader != nil {
+		reorg.NewBlockRoot = utils.MustParseHex(firstHeader.Data.Root)
+	}
+	err = store.SaveChainReorg(reorg)
+	if err != nil {
+		return nil, err
+	}
+	metrics.ChainReorgDepth.Observe(float64(len(orphaned)))
+
+	return reorgEvents, nil
+}
+
+// rollBackPendingChainReorgs rolls back the chain reorgs that have been committed by the slot exporter, see
+// rollBackChainReorgs. It runs in its own tx which clears the pending state of the reorgs.
+func rollBackPendingChainReorgs(client rpc.Client, head *types.ChainHead) error {
+	tx, err := db.WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = rollBackChainReorgs(client, head, &dbChainReorgStore{tx: tx})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing tx: %w", err)
+	}
+	return nil
+}
+
+// rollBackChainReorgs rolls back the exported data of the pending chain reorgs. The attestations of the orphaned
+// blocks are removed from bigtable, the balances and income of all epochs from the one of the first orphaned block up
+// to the latest block exported when the reorg was detected are deleted and all slots of these epochs are exported
+// again, which replaces the orphaned blocks by the canonical blocks (or missed slots) of the node. A reorg stays pending
+// until all of this has succeeded, so a failed rollback is repeated by the next run of the slot exporter.
+func rollBackChainReorgs(client rpc.Client, head *types.ChainHead, store chainReorgRollbackStore) error {
+	reorgs, err := store.GetPendingChainReorgs()
+	if err != nil {
+		return fmt.Errorf("error retrieving pending chain reorgs: %w", err)
+	}
+
+	for _, reorg := range reorgs {
+		logger.Infof("rolling back chain reorg at slot %v up to slot %v", reorg.Slot, reorg.LastSlot)
+
+		orphaned, err := store.GetOrphanedBlocks(reorg.Slot, reorg.LastSlot)
+		if err != nil {
+			return fmt.Errorf("error retrieving orphaned blocks of the chain reorg at slot %v: %w", reorg.Slot, err)
+		}
+		for _, block := range orphaned {
+			err = store.DeleteAttestationInclusions(block.Slot, block.BlockRoot)
+			if err != nil {
+				return fmt.Errorf("error deleting attestation inclusions of block %#x at slot %v: %w", block.BlockRoot, block.Slot, err)
+			}
+		}
+
+		// the duties and balances of an epoch are exported from the state of its first slot, which depends on all
+		// blocks of the previous epoch, so every epoch from the one of the first orphaned block on is exported again
+		affectedEpochs := []uint64{}
+		for epoch := utils.EpochOfSlot(reorg.Slot); epoch <= utils.EpochOfSlot(reorg.LastSlot); epoch++ {
+			affectedEpochs = append(affectedEpochs, epoch)
+		}
+
+		err = store.DeleteValidatorBalancesAndIncome(affectedEpochs)
+		if err != nil {
+			return fmt.Errorf("error deleting validator balances and income of epochs %v: %w", affectedEpochs, err)
+		}
+
+		for slot := affectedEpochs[0] * utils.Config.Chain.ClConfig.SlotsPerEpoch; slot <= reorg.LastSlot; slot++ {
+			err := store.ExportSlot(client, slot, utils.EpochOfSlot(slot) == head.HeadEpoch)
+			if err != nil {
+				return fmt.Errorf("error exporting slot %v: %w", slot, err)
+			}
+		}
+
+		err = store.InvalidateEpochs(affectedEpochs)
+		if err != nil {
+			return err
+		}
+
+		err = store.SetChainReorgRolledBack(reorg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newReorgEvent creates the event for a stored block that has been orphaned, the header is the new canonical block of the slot if there is one
+func newReorgEvent(slot uint64, oldBlockRoot []byte, header *rpc.StandardBeaconHeaderResponse) *types.StreamEvent {
+	event := &types.StreamEvent{
+		Type:         types.StreamEventChainReorg,
+		Slot:         slot,
+		Epoch:        utils.EpochOfSlot(slot),
+		OldBlockRoot: fmt.Sprintf("%#x", oldBlockRoot),
+	}
+	if header != nil {
+		event.BlockRoot = header.Data.Root
+	}
+	return event
+}
+
 func ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool, tx *sqlx.Tx) error {
 
 	isFirstSlotOfEpoch := slot%utils.Config.Chain.ClConfig.SlotsPerEpoch == 0
@@ -376,6 +663,13 @@ func ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool, tx *sqlx.Tx) e
 	if err != nil {
 		return fmt.Errorf("error saving slot to the db: %w", err)
 	}
+
+	if blockSlasher != nil {
+		err = runSlasher(block, tx)
+		if err != nil {
+			return fmt.Errorf("error running slasher for slot %v: %w", block.Slot, err)
+		}
+	}
 	// time.Sleep(time.Second)
 
 	logger.WithFields(
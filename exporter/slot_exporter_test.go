@@ -0,0 +1,350 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// reorgTestBlock is a block of a test chain, a block without fork is a missed slot
+type reorgTestBlock struct {
+	slot uint64
+	fork byte
+}
+
+func reorgTestRoot(fork byte, slot uint64) []byte {
+	root := make([]byte, 32)
+	root[0] = fork
+	root[31] = byte(slot)
+	return root
+}
+
+// reorgTestChain links the proposed blocks of the chain to their predecessor, the first block is linked to parent
+func reorgTestChain(parent []byte, blocks ...reorgTestBlock) map[uint64]*types.Block {
+	chain := make(map[uint64]*types.Block)
+	for _, b := range blocks {
+		if b.fork == 0 {
+			chain[b.slot] = &types.Block{Slot: b.slot, Status: 0, BlockRoot: []byte{0x0}}
+			continue
+		}
+		root := reorgTestRoot(b.fork, b.slot)
+		chain[b.slot] = &types.Block{Slot: b.slot, Status: 1, BlockRoot: root, ParentRoot: parent}
+		parent = root
+	}
+	return chain
+}
+
+func proposed(fork byte, from, to uint64) []reorgTestBlock {
+	blocks := []reorgTestBlock{}
+	for slot := from; slot <= to; slot++ {
+		blocks = append(blocks, reorgTestBlock{slot: slot, fork: fork})
+	}
+	return blocks
+}
+
+// fakeReorgNode is a beacon node serving the blocks of its canonical chain
+type fakeReorgNode struct {
+	rpc.Client
+	chain map[uint64]*types.Block
+}
+
+func (n *fakeReorgNode) GetBlockHeader(slot uint64) (*rpc.StandardBeaconHeaderResponse, error) {
+	block, ok := n.chain[slot]
+	if !ok || block.Status != 1 {
+		return nil, nil
+	}
+	header := &rpc.StandardBeaconHeaderResponse{}
+	header.Data.Root = fmt.Sprintf("%#x", block.BlockRoot)
+	header.Data.Header.Message.ParentRoot = fmt.Sprintf("%#x", block.ParentRoot)
+	return header, nil
+}
+
+func (n *fakeReorgNode) GetBlockBySlot(slot uint64) (*types.Block, error) {
+	block, ok := n.chain[slot]
+	if !ok {
+		return nil, fmt.Errorf("slot %v not found", slot)
+	}
+	return block, nil
+}
+
+// fakeReorgStore holds the non finalized slots of the stored chain and records every write of the rollback
+type fakeReorgStore struct {
+	slots []*db.GetAllNonFinalizedSlotsRow
+	// failDelete makes the deletion of balances and income fail
+	failDelete bool
+
+	orphaned          []uint64
+	deletedInclusions []uint64
+	deletedEpochs     []uint64
+	exported          []uint64
+	invalidatedEpochs []uint64
+	reorg             *types.ChainReorg
+}
+
+func newFakeReorgStore(chain map[uint64]*types.Block, from uint64) *fakeReorgStore {
+	store := &fakeReorgStore{}
+	for slot := from; ; slot++ {
+		block, ok := chain[slot]
+		if !ok {
+			break
+		}
+		row := &db.GetAllNonFinalizedSlotsRow{Slot: slot, BlockRoot: block.BlockRoot, ParentRoot: block.ParentRoot, Status: "1"}
+		if block.Status != 1 {
+			row.BlockRoot = []byte{0x1}
+			row.Status = "2"
+		}
+		store.slots = append(store.slots, row)
+	}
+	return store
+}
+
+func (s *fakeReorgStore) GetAllNonFinalizedSlots() ([]*db.GetAllNonFinalizedSlotsRow, error) {
+	return s.slots, nil
+}
+
+func (s *fakeReorgStore) SetSlotOrphaned(slot uint64, blockRoot []byte) error {
+	for _, row := range s.slots {
+		if row.Slot == slot && bytes.Equal(row.BlockRoot, blockRoot) {
+			row.Status = "3"
+			s.orphaned = append(s.orphaned, slot)
+			return nil
+		}
+	}
+	return fmt.Errorf("block %#x at slot %v not found", blockRoot, slot)
+}
+
+func (s *fakeReorgStore) SaveChainReorg(reorg *types.ChainReorg) error {
+	s.reorg = reorg
+	return nil
+}
+
+func (s *fakeReorgStore) GetPendingChainReorgs() ([]*types.ChainReorg, error) {
+	if s.reorg == nil || s.reorg.RolledBack {
+		return nil, nil
+	}
+	return []*types.ChainReorg{s.reorg}, nil
+}
+
+func (s *fakeReorgStore) GetOrphanedBlocks(fromSlot, toSlot uint64) ([]*types.CanonBlock, error) {
+	blocks := []*types.CanonBlock{}
+	for _, row := range s.slots {
+		if row.Slot >= fromSlot && row.Slot <= toSlot && row.Status == "3" {
+			blocks = append(blocks, &types.CanonBlock{Slot: row.Slot, BlockRoot: row.BlockRoot})
+		}
+	}
+	return blocks, nil
+}
+
+func (s *fakeReorgStore) DeleteAttestationInclusions(slot uint64, blockRoot []byte) error {
+	s.deletedInclusions = append(s.deletedInclusions, slot)
+	return nil
+}
+
+func (s *fakeReorgStore) DeleteValidatorBalancesAndIncome(epochs []uint64) error {
+	if s.failDelete {
+		return fmt.Errorf("bigtable is not available")
+	}
+	if len(s.exported) > 0 {
+		return fmt.Errorf("balances deleted after slots have been exported again")
+	}
+	s.deletedEpochs = append(s.deletedEpochs, epochs...)
+	return nil
+}
+
+func (s *fakeReorgStore) ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool) error {
+	if _, err := client.GetBlockBySlot(slot); err != nil {
+		return err
+	}
+	s.exported = append(s.exported, slot)
+	return nil
+}
+
+func (s *fakeReorgStore) InvalidateEpochs(epochs []uint64) error {
+	s.invalidatedEpochs = append(s.invalidatedEpochs, epochs...)
+	return nil
+}
+
+func (s *fakeReorgStore) SetChainReorgRolledBack(reorg *types.ChainReorg) error {
+	reorg.RolledBack = true
+	return nil
+}
+
+func TestHandleChainReorgs(t *testing.T) {
+	previous := utils.Config
+	t.Cleanup(func() { utils.Config = previous })
+	utils.Config = &types.Config{}
+	utils.Config.Chain.ClConfig.SlotsPerEpoch = 4
+
+	genesis := reorgTestRoot(0xff, 0)
+
+	tests := []struct {
+		name string
+		// stored is the chain in the db, node the canonical chain of the node
+		stored, node map[uint64]*types.Block
+		// lookbackFrom is the first non finalized slot of the stored chain
+		lookbackFrom uint64
+
+		wantOrphaned []uint64
+		wantEpochs   []uint64
+		wantExported []uint64
+		wantNewRoot  []byte
+	}{
+		{
+			name:         "one block",
+			stored:       reorgTestChain(genesis, proposed(1, 0, 9)...),
+			node:         reorgTestChain(genesis, append(proposed(1, 0, 8), reorgTestBlock{slot: 9, fork: 2})...),
+			wantOrphaned: []uint64{9},
+			wantEpochs:   []uint64{2},
+			wantExported: []uint64{8, 9},
+			wantNewRoot:  reorgTestRoot(2, 9),
+		},
+		{
+			name:         "multiple blocks across an epoch boundary",
+			stored:       reorgTestChain(genesis, proposed(1, 0, 10)...),
+			node:         reorgTestChain(genesis, append(proposed(1, 0, 6), proposed(2, 7, 10)...)...),
+			wantOrphaned: []uint64{7, 8, 9, 10},
+			wantEpochs:   []uint64{1, 2},
+			wantExported: []uint64{4, 5, 6, 7, 8, 9, 10},
+			wantNewRoot:  reorgTestRoot(2, 7),
+		},
+		{
+			name:         "after missed slots",
+			stored:       reorgTestChain(genesis, append(proposed(1, 0, 5), reorgTestBlock{slot: 6}, reorgTestBlock{slot: 7}, reorgTestBlock{slot: 8, fork: 1}, reorgTestBlock{slot: 9, fork: 1})...),
+			node:         reorgTestChain(genesis, append(proposed(1, 0, 5), reorgTestBlock{slot: 6}, reorgTestBlock{slot: 7}, reorgTestBlock{slot: 8}, reorgTestBlock{slot: 9, fork: 2})...),
+			wantOrphaned: []uint64{8, 9},
+			wantEpochs:   []uint64{2},
+			wantExported: []uint64{8, 9},
+			wantNewRoot:  nil,
+		},
+		{
+			name:         "deeper than the lookback",
+			stored:       reorgTestChain(genesis, proposed(1, 0, 11)...),
+			node:         reorgTestChain(genesis, append(proposed(1, 0, 5), proposed(2, 6, 11)...)...),
+			lookbackFrom: 8,
+			wantOrphaned: []uint64{8, 9, 10, 11},
+			wantEpochs:   []uint64{2},
+			wantExported: []uint64{8, 9, 10, 11},
+			wantNewRoot:  reorgTestRoot(2, 8),
+		},
+		{
+			name:   "no reorg",
+			stored: reorgTestChain(genesis, append(proposed(1, 0, 5), reorgTestBlock{slot: 6}, reorgTestBlock{slot: 7, fork: 1})...),
+			node:   reorgTestChain(genesis, append(proposed(1, 0, 5), reorgTestBlock{slot: 6}, reorgTestBlock{slot: 7, fork: 1})...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeReorgStore(tt.stored, tt.lookbackFrom)
+			node := &fakeReorgNode{chain: tt.node}
+			head := &types.ChainHead{HeadSlot: store.slots[len(store.slots)-1].Slot, HeadEpoch: utils.EpochOfSlot(store.slots[len(store.slots)-1].Slot)}
+
+			events, err := handleChainReorgs(node, head, store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// bigtable is only changed once the orphaned blocks have been committed
+			if len(store.deletedInclusions) > 0 || len(store.deletedEpochs) > 0 || len(store.exported) > 0 {
+				t.Fatalf("got exported data rolled back before the reorg has been saved")
+			}
+			if store.reorg != nil && store.reorg.RolledBack {
+				t.Fatalf("got reorg saved as rolled back")
+			}
+
+			err = rollBackChainReorgs(node, head, store)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(store.orphaned, tt.wantOrphaned) {
+				t.Errorf("got orphaned slots %v, want %v", store.orphaned, tt.wantOrphaned)
+			}
+			if !slices.Equal(store.deletedInclusions, tt.wantOrphaned) {
+				t.Errorf("got attestation inclusions deleted for slots %v, want %v", store.deletedInclusions, tt.wantOrphaned)
+			}
+			if !slices.Equal(store.deletedEpochs, tt.wantEpochs) {
+				t.Errorf("got balances and income deleted for epochs %v, want %v", store.deletedEpochs, tt.wantEpochs)
+			}
+			if !slices.Equal(store.invalidatedEpochs, tt.wantEpochs) {
+				t.Errorf("got invalidated epochs %v, want %v", store.invalidatedEpochs, tt.wantEpochs)
+			}
+			if !slices.Equal(store.exported, tt.wantExported) {
+				t.Errorf("got exported slots %v, want %v", store.exported, tt.wantExported)
+			}
+			if len(events) != len(tt.wantOrphaned) {
+				t.Errorf("got %v reorg events, want %v", len(events), len(tt.wantOrphaned))
+			}
+
+			if len(tt.wantOrphaned) == 0 {
+				if store.reorg != nil {
+					t.Errorf("got chain reorg %+v, want none", store.reorg)
+				}
+				return
+			}
+			if store.reorg == nil {
+				t.Fatal("got no chain reorg saved")
+			}
+			if !store.reorg.RolledBack {
+				t.Errorf("got chain reorg still pending")
+			}
+			if store.reorg.Slot != tt.wantOrphaned[0] || store.reorg.Depth != uint64(len(tt.wantOrphaned)) {
+				t.Errorf("got chain reorg at slot %v with depth %v, want slot %v with depth %v", store.reorg.Slot, store.reorg.Depth, tt.wantOrphaned[0], len(tt.wantOrphaned))
+			}
+			if !bytes.Equal(store.reorg.OldBlockRoot, tt.stored[tt.wantOrphaned[0]].BlockRoot) || !bytes.Equal(store.reorg.NewBlockRoot, tt.wantNewRoot) {
+				t.Errorf("got chain reorg from %#x to %#x, want from %#x to %#x", store.reorg.OldBlockRoot, store.reorg.NewBlockRoot, tt.stored[tt.wantOrphaned[0]].BlockRoot, tt.wantNewRoot)
+			}
+		})
+	}
+}
+
+func TestRollBackChainReorgsRetry(t *testing.T) {
+	previous := utils.Config
+	t.Cleanup(func() { utils.Config = previous })
+	utils.Config = &types.Config{}
+	utils.Config.Chain.ClConfig.SlotsPerEpoch = 4
+
+	genesis := reorgTestRoot(0xff, 0)
+	stored := reorgTestChain(genesis, proposed(1, 0, 9)...)
+	node := &fakeReorgNode{chain: reorgTestChain(genesis, append(proposed(1, 0, 8), reorgTestBlock{slot: 9, fork: 2})...)}
+	store := newFakeReorgStore(stored, 0)
+	head := &types.ChainHead{HeadSlot: 9, HeadEpoch: 2}
+
+	_, err := handleChainReorgs(node, head, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.failDelete = true
+	err = rollBackChainReorgs(node, head, store)
+	if err == nil {
+		t.Fatal("got no error while bigtable is not available")
+	}
+	if len(store.exported) > 0 || store.reorg.RolledBack {
+		t.Fatalf("got slots %v exported and rolled back %v after the deletion failed, want none and false", store.exported, store.reorg.RolledBack)
+	}
+
+	// the next run of the slot exporter repeats the rollback
+	store.failDelete = false
+	err = rollBackChainReorgs(node, head, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(store.exported, []uint64{8, 9}) || !store.reorg.RolledBack {
+		t.Errorf("got slots %v exported and rolled back %v, want [8 9] and true", store.exported, store.reorg.RolledBack)
+	}
+
+	// a rolled back reorg is not rolled back again
+	err = rollBackChainReorgs(node, head, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.exported) != 2 {
+		t.Errorf("got slots %v exported, want the rolled back reorg to be skipped", store.exported)
+	}
+}
@@ -0,0 +1,521 @@
+package exporter
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/mail"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	itypes "github.com/gobitfly/eth-rewards/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ValidatorExportSourceBalance = "balance"
+	ValidatorExportSourceIncome  = "income"
+
+	ValidatorExportFormatCsv     = "csv"
+	ValidatorExportFormatParquet = "parquet"
+
+	// validatorExportValidatorBatch and validatorExportMaxEpochBatch are the maximum number of validators and epochs
+	// that are read from bigtable at once
+	validatorExportValidatorBatch = 100
+	validatorExportMaxEpochBatch  = 225
+	// validatorExportMaxBufferedRows is the maximum number of rows that are kept in memory to be sorted before they are
+	// written to the file
+	validatorExportMaxBufferedRows = 100000
+	// validatorExportStaleAfter is the time after which a running job without heartbeat is picked up by another worker
+	validatorExportStaleAfter = time.Minute * 10
+)
+
+// ValidatorExportColumns are the columns that can be exported per source, every export starts with the validator index
+// and the epoch. Amounts are in gwei, except for the tx fee reward which is in wei and written as decimal string.
+var ValidatorExportColumns = map[string][]utils.ParquetColumn{
+	ValidatorExportSourceBalance: {
+		{Name: "validator_index"},
+		{Name: "epoch"},
+		{Name: "ts"},
+		{Name: "balance"},
+		{Name: "effective_balance"},
+	},
+	ValidatorExportSourceIncome: {
+		{Name: "validator_index"},
+		{Name: "epoch"},
+		{Name: "ts"},
+		{Name: "attestation_source_reward"},
+		{Name: "attestation_source_penalty"},
+		{Name: "attestation_target_reward"},
+		{Name: "attestation_target_penalty"},
+		{Name: "attestation_head_reward"},
+		{Name: "finality_delay_penalty"},
+		{Name: "proposer_slashing_inclusion_reward"},
+		{Name: "proposer_attestation_inclusion_reward"},
+		{Name: "proposer_sync_inclusion_reward"},
+		{Name: "sync_committee_reward"},
+		{Name: "sync_committee_penalty"},
+		{Name: "slashing_reward"},
+		{Name: "slashing_penalty"},
+		{Name: "tx_fee_reward_wei", String: true},
+		{Name: "proposals_missed"},
+	},
+}
+
+// ValidatorExportFields returns the fields of an export of the source in the order of the columns of the source. The
+// validator index and epoch are always exported, all columns are exported if no fields are given.
+func ValidatorExportFields(source string, fields []string) ([]string, error) {
+	available, ok := ValidatorExportColumns[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown source %v", source)
+	}
+	selected := map[string]bool{"validator_index": true, "epoch": true}
+	for _, f := range fields {
+		found := false
+		for _, c := range available {
+			if c.Name == f {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %v of source %v", f, source)
+		}
+		selected[f] = true
+	}
+
+	res := make([]string, 0, len(available))
+	for _, c := range available {
+		if len(fields) == 0 || selected[c.Name] {
+			res = append(res, c.Name)
+		}
+	}
+	return res, nil
+}
+
+// ValidatorExportFileName returns the name of the file of a finished export job
+func ValidatorExportFileName(job *types.ValidatorExportJob) string {
+	return fmt.Sprintf("validator-%v-export-%v.%v", job.Source, job.ID, job.Format)
+}
+
+var validatorExportS3Client *s3.Client
+var validatorExportS3ClientOnce = &sync.Once{}
+
+func getValidatorExportS3Client() *s3.Client {
+	validatorExportS3ClientOnce.Do(func() {
+		s3Resolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+			return aws.Endpoint{
+				PartitionID:       "aws",
+				URL:               utils.Config.ValidatorExport.S3.Endpoint,
+				SigningRegion:     "us-east-2",
+				HostnameImmutable: true,
+			}, nil
+		})
+		validatorExportS3Client = s3.NewFromConfig(aws.Config{
+			Region: "us-east-2",
+			Credentials: credentials.NewStaticCredentialsProvider(
+				utils.Config.ValidatorExport.S3.AccessKeyId,
+				utils.Config.ValidatorExport.S3.AccessKeySecret,
+				"",
+			),
+			EndpointResolverWithOptions: s3Resolver,
+		}, func(o *s3.Options) {
+			o.UsePathStyle = true
+		})
+	})
+	return validatorExportS3Client
+}
+
+// validatorExportLinkValidity returns the duration the signed download links are valid for
+func validatorExportLinkValidity() time.Duration {
+	validity := utils.Config.ValidatorExport.LinkValidity
+	if validity <= 0 || validity > time.Hour*24*7 {
+		// signed links can not be valid for more than 7 days
+		validity = time.Hour * 24 * 7
+	}
+	return validity
+}
+
+// ValidatorExportDownloadUrl returns a signed link to the file of a finished export job
+func ValidatorExportDownloadUrl(ctx context.Context, job *types.ValidatorExportJob) (string, error) {
+	if job.ObjectKey == nil {
+		return "", fmt.Errorf("validator export job %v has no file", job.ID)
+	}
+	disposition := fmt.Sprintf(`attachment; filename="%v"`, ValidatorExportFileName(job))
+	req, err := s3.NewPresignClient(getValidatorExportS3Client()).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     &utils.Config.ValidatorExport.S3.Bucket,
+		Key:                        job.ObjectKey,
+		ResponseContentDisposition: &disposition,
+	}, s3.WithPresignExpires(validatorExportLinkValidity()))
+	if err != nil {
+		return "", fmt.Errorf("error signing download link of validator export job %v: %w", job.ID, err)
+	}
+	return req.URL, nil
+}
+
+// validatorExportWriter writes the rows of an export to a file
+type validatorExportWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+type csvValidatorExportWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCsvValidatorExportWriter(w io.Writer, columns []utils.ParquetColumn) (*csvValidatorExportWriter, error) {
+	cw := &csvValidatorExportWriter{w: csv.NewWriter(w), record: make([]string, len(columns))}
+	for i, c := range columns {
+		cw.record[i] = c.Name
+	}
+	err := cw.w.Write(cw.record)
+	if err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvValidatorExportWriter) WriteRow(values []interface{}) error {
+	for i, v := range values {
+		switch v := v.(type) {
+		case uint64:
+			cw.record[i] = strconv.FormatUint(v, 10)
+		case string:
+			cw.record[i] = v
+		default:
+			return fmt.Errorf("unsupported csv value %T", v)
+		}
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvValidatorExportWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// ValidatorExporter runs the queued validator export jobs. Multiple exporters can run at the same time, every job is
+// only claimed by one of them.
+type ValidatorExporter struct{}
+
+func NewValidatorExporter() *ValidatorExporter {
+	return &ValidatorExporter{}
+}
+
+func (ve *ValidatorExporter) Start() {
+	logrus.WithFields(logrus.Fields{"s3Endpoint": utils.Config.ValidatorExport.S3.Endpoint}).Infof("starting validator exporter")
+	for {
+		job, err := db.ClaimValidatorExportJob(validatorExportStaleAfter)
+		if err != nil {
+			logrus.WithError(err).Errorf("error claiming validator export job")
+			time.Sleep(time.Second * 10)
+			continue
+		}
+		if job == nil {
+			time.Sleep(time.Second * 10)
+			continue
+		}
+
+		start := time.Now()
+		err = ve.runJob(job)
+		metrics.TaskDuration.WithLabelValues("validator_export_job").Observe(time.Since(start).Seconds())
+		if err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{"job": job.ID, "userId": job.UserID}).Errorf("error running validator export job")
+			err = db.FailValidatorExportJob(job.ID, "the export could not be created, please try again later")
+			if err != nil {
+				logrus.WithError(err).Errorf("error marking validator export job %v as failed", job.ID)
+			}
+			ve.notify(job, "Your validator export failed", fmt.Sprintf("Your export #%v of the %v history of %v validators could not be created. Please try again later.", job.ID, job.Source, len(job.Validators)))
+			continue
+		}
+		logrus.WithFields(logrus.Fields{"job": job.ID, "duration": time.Since(start)}).Infof("finished validator export job")
+	}
+}
+
+// runJob writes the export of a job to a temporary file and uploads it to the object storage
+func (ve *ValidatorExporter) runJob(job *types.ValidatorExportJob) error {
+	columns, err := validatorExportJobColumns(job)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp("", fmt.Sprintf("validator-export-%v-*", job.ID))
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	var w validatorExportWriter
+	switch job.Format {
+	case ValidatorExportFormatCsv:
+		w, err = newCsvValidatorExportWriter(f, columns)
+	case ValidatorExportFormatParquet:
+		w, err = utils.NewParquetRowWriter(f, "validator_"+job.Source, columns)
+	default:
+		err = fmt.Errorf("unknown validator export format %v", job.Format)
+	}
+	if err != nil {
+		return err
+	}
+
+	// tell the other workers that the job is still running
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(validatorExportStaleAfter / 5)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := db.UpdateValidatorExportJobHeartbeat(job.ID); err != nil {
+					logrus.WithError(err).Warnf("error updating heartbeat of validator export job %v", job.ID)
+				}
+			}
+		}
+	}()
+
+	rowCount, err := writeValidatorExport(job, columns, w)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("error closing export file: %w", err)
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("error getting size of export file: %w", err)
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("error rewinding export file: %w", err)
+	}
+
+	contentType := "text/csv"
+	if job.Format == ValidatorExportFormatParquet {
+		contentType = "application/vnd.apache.parquet"
+	}
+	key := fmt.Sprintf("validator-exports/%v/%v/%v", utils.Config.Chain.ClConfig.DepositChainID, job.UserID, ValidatorExportFileName(job))
+	_, err = getValidatorExportS3Client().PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        &utils.Config.ValidatorExport.S3.Bucket,
+		Key:           &key,
+		Body:          f,
+		ContentLength: &size,
+		ContentType:   &contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("error uploading export file: %w", err)
+	}
+
+	err = db.FinishValidatorExportJob(job.ID, key, rowCount, uint64(size))
+	if err != nil {
+		return err
+	}
+	job.ObjectKey = &key
+
+	link, err := ValidatorExportDownloadUrl(ctx, job)
+	if err != nil {
+		// the user can still get a link from the api
+		logrus.WithError(err).Errorf("error signing download link of validator export job %v", job.ID)
+		link = fmt.Sprintf("https://%v/api/v1/user/exports/%v", utils.Config.Frontend.SiteDomain, job.ID)
+	}
+	ve.notify(job, "Your validator export is ready", fmt.Sprintf("Your export #%v of the %v history of %v validators from epoch %v to %v (%v rows) is ready.\n\nDownload: %v\n\nThe link is valid for %v hours, afterwards you can get a new link at https://%v/api/v1/user/exports/%v",
+		job.ID, job.Source, len(job.Validators), job.StartEpoch, job.EndEpoch, rowCount, link, validatorExportLinkValidity().Hours(), utils.Config.Frontend.SiteDomain, job.ID))
+	return nil
+}
+
+// notify emails the owner of an export job
+func (ve *ValidatorExporter) notify(job *types.ValidatorExportJob, subject, msg string) {
+	email, err := db.GetUserEmailById(job.UserID)
+	if err != nil {
+		logrus.WithError(err).Errorf("error getting email of user %v for validator export job %v", job.UserID, job.ID)
+		return
+	}
+	err = mail.SendTextMail(email, subject, msg, []types.EmailAttachment{})
+	if err != nil {
+		logrus.WithError(err).Errorf("error sending email for validator export job %v", job.ID)
+	}
+}
+
+// validatorExportJobColumns returns the columns of the fields of a job
+func validatorExportJobColumns(job *types.ValidatorExportJob) ([]utils.ParquetColumn, error) {
+	available, ok := ValidatorExportColumns[job.Source]
+	if !ok {
+		return nil, fmt.Errorf("unknown validator export source %v", job.Source)
+	}
+	columns := make([]utils.ParquetColumn, 0, len(job.Fields))
+	for _, field := range job.Fields {
+		found := false
+		for _, c := range available {
+			if c.Name == field {
+				columns = append(columns, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %v of validator export source %v", field, job.Source)
+		}
+	}
+	return columns, nil
+}
+
+// validatorExportRow is a row of an export before it is converted to the columns of the export
+type validatorExportRow struct {
+	validator uint64
+	epoch     uint64
+	balance   *types.ValidatorBalance
+	income    *itypes.ValidatorEpochIncome
+}
+
+// validatorExportEpochBatchSize returns the number of epochs that are read at once for the validators, so that at most
+// validatorExportMaxBufferedRows rows are kept in memory
+func validatorExportEpochBatchSize(validators int) uint64 {
+	if validators <= 0 {
+		return validatorExportMaxEpochBatch
+	}
+	return max(1, min(validatorExportMaxEpochBatch, uint64(validatorExportMaxBufferedRows/validators)))
+}
+
+// writeValidatorExport streams the history of the validators of a job from bigtable to the writer, ordered by epoch and
+// validator index, and returns the number of written rows
+func writeValidatorExport(job *types.ValidatorExportJob, columns []utils.ParquetColumn, w validatorExportWriter) (uint64, error) {
+	validators := make([]uint64, 0, len(job.Validators))
+	for _, v := range job.Validators {
+		validators = append(validators, uint64(v))
+	}
+
+	epochBatch := validatorExportEpochBatchSize(len(validators))
+	values := make([]interface{}, len(columns))
+	rowCount := uint64(0)
+	for startEpoch := job.StartEpoch; startEpoch <= job.EndEpoch; startEpoch += epochBatch {
+		endEpoch := min(startEpoch+epochBatch-1, job.EndEpoch)
+
+		rows := []validatorExportRow{}
+		for i := 0; i < len(validators); i += validatorExportValidatorBatch {
+			batch := validators[i:min(i+validatorExportValidatorBatch, len(validators))]
+			switch job.Source {
+			case ValidatorExportSourceBalance:
+				history, err := db.BigtableClient.GetValidatorBalanceHistory(batch, startEpoch, endEpoch)
+				if err != nil {
+					return 0, fmt.Errorf("error getting balance history from epoch %v to %v: %w", startEpoch, endEpoch, err)
+				}
+				for validator, balances := range history {
+					for _, b := range balances {
+						rows = append(rows, validatorExportRow{validator: validator, epoch: b.Epoch, balance: b})
+					}
+				}
+			case ValidatorExportSourceIncome:
+				history, err := db.BigtableClient.GetValidatorIncomeDetailsHistory(batch, startEpoch, endEpoch)
+				if err != nil {
+					return 0, fmt.Errorf("error getting income history from epoch %v to %v: %w", startEpoch, endEpoch, err)
+				}
+				for validator, incomes := range history {
+					for epoch, income := range incomes {
+						rows = append(rows, validatorExportRow{validator: validator, epoch: epoch, income: income})
+					}
+				}
+			default:
+				return 0, fmt.Errorf("unknown validator export source %v", job.Source)
+			}
+		}
+
+		sort.Slice(rows, func(i, j int) bool {
+			if rows[i].epoch != rows[j].epoch {
+				return rows[i].epoch < rows[j].epoch
+			}
+			return rows[i].validator < rows[j].validator
+		})
+		for _, row := range rows {
+			for i, c := range columns {
+				if row.balance != nil {
+					values[i] = validatorBalanceExportValue(row.validator, row.balance, c.Name)
+				} else {
+					values[i] = validatorIncomeExportValue(row.validator, row.epoch, row.income, c.Name)
+				}
+			}
+			err := w.WriteRow(values)
+			if err != nil {
+				return 0, fmt.Errorf("error writing export row: %w", err)
+			}
+		}
+		rowCount += uint64(len(rows))
+	}
+	return rowCount, nil
+}
+
+func validatorBalanceExportValue(validator uint64, b *types.ValidatorBalance, column string) interface{} {
+	switch column {
+	case "validator_index":
+		return validator
+	case "epoch":
+		return b.Epoch
+	case "ts":
+		return uint64(utils.EpochToTime(b.Epoch).Unix())
+	case "balance":
+		return b.Balance
+	case "effective_balance":
+		return b.EffectiveBalance
+	}
+	return nil
+}
+
+func validatorIncomeExportValue(validator, epoch uint64, income *itypes.ValidatorEpochIncome, column string) interface{} {
+	switch column {
+	case "validator_index":
+		return validator
+	case "epoch":
+		return epoch
+	case "ts":
+		return uint64(utils.EpochToTime(epoch).Unix())
+	case "attestation_source_reward":
+		return income.AttestationSourceReward
+	case "attestation_source_penalty":
+		return income.AttestationSourcePenalty
+	case "attestation_target_reward":
+		return income.AttestationTargetReward
+	case "attestation_target_penalty":
+		return income.AttestationTargetPenalty
+	case "attestation_head_reward":
+		return income.AttestationHeadReward
+	case "finality_delay_penalty":
+		return income.FinalityDelayPenalty
+	case "proposer_slashing_inclusion_reward":
+		return income.ProposerSlashingInclusionReward
+	case "proposer_attestation_inclusion_reward":
+		return income.ProposerAttestationInclusionReward
+	case "proposer_sync_inclusion_reward":
+		return income.ProposerSyncInclusionReward
+	case "sync_committee_reward":
+		return income.SyncCommitteeReward
+	case "sync_committee_penalty":
+		return income.SyncCommitteePenalty
+	case "slashing_reward":
+		return income.SlashingReward
+	case "slashing_penalty":
+		return income.SlashingPenalty
+	case "tx_fee_reward_wei":
+		return new(big.Int).SetBytes(income.TxFeeRewardWei).String()
+	case "proposals_missed":
+		return income.ProposalsMissed
+	}
+	return nil
+}
@@ -2005,24 +2005,18 @@ func ApiValidatorByEth1Address(w http.ResponseWriter, r *http.Request) {
 // @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
 // @Param  latest_epoch query int false "The latest epoch to consider in the query"
 // @Param  offset query int false "Number of items to skip"
-// @Param  limit query int false "Maximum number of items to return, up to 100"
-// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorIncomeHistoryResponse}
+// @Param  limit query int false "Maximum number of epochs to return, up to 100"
+// @Param  cursor query string false "next_cursor of the previous page, can not be combined with latest_epoch and offset"
+// @Success 200 {object} types.ApiListResponse{data=[]types.ApiValidatorIncomeHistoryResponse}
 // @Failure 400 {object} types.ApiResponse
 // @Router /api/v1/validator/{indexOrPubkey}/incomedetailhistory [get]
 func ApiValidatorIncomeDetailsHistory(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	j := json.NewEncoder(w)
 	vars := mux.Vars(r)
 	maxValidators := getUserPremium(r).MaxValidators
 
-	latestEpoch, limit, err := getIncomeDetailsHistoryQueryParameters(r.URL.Query())
-	if err != nil {
-		SendBadRequestResponse(w, r.URL.String(), err.Error())
-		return
-	}
-
 	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), err.Error())
@@ -2034,7 +2028,15 @@ func ApiValidatorIncomeDetailsHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	history, err := db.BigtableClient.GetValidatorIncomeDetailsHistory(queryIndices, latestEpoch-(limit-1), latestEpoch)
+	filter := apiCursorFilter(queryIndices)
+	latestEpoch, limit, err := getValidatorHistoryQueryParameters(r.URL.Query(), "incomedetailhistory", filter, services.LatestFinalizedEpoch())
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	firstEpoch, next := apiEpochWindow("incomedetailhistory", filter, latestEpoch, limit)
+
+	history, err := db.BigtableClient.GetValidatorIncomeDetailsHistory(queryIndices, firstEpoch, latestEpoch)
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
@@ -2087,26 +2089,26 @@ func ApiValidatorIncomeDetailsHistory(w http.ResponseWriter, r *http.Request) {
 		return responseData[i].ValidatorIndex < responseData[j].ValidatorIndex
 	})
 
-	response := &types.ApiResponse{}
-	response.Status = "OK"
-
-	response.Data = responseData
+	sendApiListResponse(w, r, responseData, next)
+}
 
-	err = j.Encode(response)
+// getValidatorHistoryQueryParameters returns the last epoch and the number of epochs of the requested page of the
+// validator history lists, the last epoch is taken from the cursor if it is set
+func getValidatorHistoryQueryParameters(q url.Values, list string, filter, onChainLatestEpoch uint64) (uint64, uint64, error) {
+	defaultLimit := uint64(apiListEpochWindow)
 
+	cursor, err := parseApiCursor(q, list, filter)
 	if err != nil {
-		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
-		return
+		return 0, 0, err
+	}
+	if cursor != nil && (q.Has("latest_epoch") || q.Has("offset")) {
+		return 0, 0, fmt.Errorf("the cursor parameter can not be combined with the latest_epoch and offset parameters")
 	}
-}
-
-func getIncomeDetailsHistoryQueryParameters(q url.Values) (uint64, uint64, error) {
-	onChainLatestEpoch := services.LatestFinalizedEpoch()
-	defaultLimit := uint64(100)
 
 	latestEpoch := onChainLatestEpoch
-	if q.Has("latest_epoch") {
-		var err error
+	if cursor != nil {
+		latestEpoch = cursor.Epoch
+	} else if q.Has("latest_epoch") {
 		latestEpoch, err = strconv.ParseUint(q.Get("latest_epoch"), 10, 64)
 		if err != nil || latestEpoch > onChainLatestEpoch {
 			return 0, 0, fmt.Errorf("invalid latest epoch parameter")
@@ -2123,7 +2125,6 @@ func getIncomeDetailsHistoryQueryParameters(q url.Values) (uint64, uint64, error
 
 	limit := defaultLimit
 	if q.Has("limit") {
-		var err error
 		limit, err = strconv.ParseUint(q.Get("limit"), 10, 64)
 		if err != nil || limit > defaultLimit || limit < 1 {
 			return 0, 0, fmt.Errorf("invalid limit parameter")
@@ -2140,7 +2141,8 @@ func getIncomeDetailsHistoryQueryParameters(q url.Values) (uint64, uint64, error
 // @Produce  json
 // @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
 // @Param  epoch query int false "the start epoch for the withdrawal history (default: latest epoch)"
-// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorWithdrawalResponse}
+// @Param  cursor query string false "next_cursor of the previous page, returns the withdrawals of the 100 epochs before the previous page"
+// @Success 200 {object} types.ApiListResponse{data=[]types.ApiValidatorWithdrawalResponse}
 // @Failure 400 {object} types.ApiResponse
 // @Router /api/v1/validator/{indexOrPubkey}/withdrawals [get]
 func ApiValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
@@ -2157,21 +2159,35 @@ func ApiValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
 
 	if len(queryIndices) == 0 {
 		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
 	}
 
 	q := r.URL.Query()
 
-	epoch, err := strconv.ParseUint(q.Get("epoch"), 10, 64)
+	filter := apiCursorFilter(queryIndices)
+	cursor, err := parseApiCursor(q, "withdrawals", filter)
 	if err != nil {
-		epoch = services.LatestEpoch()
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
 	}
 
-	// startEpoch and endEpoch are both inclusive, so substracting 99 here will result in a limit of 100 epochs
-	endEpoch := epoch - 99
-	if epoch < 99 {
-		endEpoch = 0
+	var epoch uint64
+	if cursor != nil {
+		if q.Has("epoch") {
+			SendBadRequestResponse(w, r.URL.String(), "the cursor parameter can not be combined with the epoch parameter")
+			return
+		}
+		epoch = cursor.Epoch
+	} else {
+		epoch, err = strconv.ParseUint(q.Get("epoch"), 10, 64)
+		if err != nil {
+			epoch = services.LatestEpoch()
+		}
 	}
 
+	// startEpoch and endEpoch are both inclusive, the window results in a limit of 100 epochs
+	endEpoch, next := apiEpochWindow("withdrawals", filter, epoch, apiListEpochWindow)
+
 	data, err := db.GetValidatorsWithdrawals(queryIndices, endEpoch, epoch)
 	if err != nil {
 		logger.Errorf("error retrieving withdrawals for %v route: %v", r.URL.String(), err)
@@ -2193,16 +2209,7 @@ func ApiValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	response := &types.ApiResponse{}
-	response.Status = "OK"
-
-	response.Data = dataFormatted
-
-	err = json.NewEncoder(w).Encode(response)
-	if err != nil {
-		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
-		return
-	}
+	sendApiListResponse(w, r, dataFormatted, next)
 }
 
 // ApiValidatorBlsChange godoc
@@ -2274,24 +2281,18 @@ func ApiValidatorBlsChange(w http.ResponseWriter, r *http.Request) {
 // @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
 // @Param  latest_epoch query int false "The latest epoch to consider in the query"
 // @Param  offset query int false "Number of items to skip"
-// @Param  limit query int false "Maximum number of items to return, up to 100"
-// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorBalanceHistoryResponse}
+// @Param  limit query int false "Maximum number of epochs to return, up to 100"
+// @Param  cursor query string false "next_cursor of the previous page, can not be combined with latest_epoch and offset"
+// @Success 200 {object} types.ApiListResponse{data=[]types.ApiValidatorBalanceHistoryResponse}
 // @Failure 400 {object} types.ApiResponse
 // @Router /api/v1/validator/{indexOrPubkey}/balancehistory [get]
 func ApiValidatorBalanceHistory(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	j := json.NewEncoder(w)
 	vars := mux.Vars(r)
 	maxValidators := getUserPremium(r).MaxValidators
 
-	latestEpoch, limit, err := getBalanceHistoryQueryParameters(r.URL.Query())
-	if err != nil {
-		SendBadRequestResponse(w, r.URL.String(), err.Error())
-		return
-	}
-
 	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), err.Error())
@@ -2300,9 +2301,18 @@ func ApiValidatorBalanceHistory(w http.ResponseWriter, r *http.Request) {
 
 	if len(queryIndices) == 0 {
 		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	filter := apiCursorFilter(queryIndices)
+	latestEpoch, limit, err := getValidatorHistoryQueryParameters(r.URL.Query(), "balancehistory", filter, services.LatestEpoch())
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
 	}
+	firstEpoch, next := apiEpochWindow("balancehistory", filter, latestEpoch, limit)
 
-	history, err := db.BigtableClient.GetValidatorBalanceHistory(queryIndices, latestEpoch-(limit-1), latestEpoch)
+	history, err := db.BigtableClient.GetValidatorBalanceHistory(queryIndices, firstEpoch, latestEpoch)
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
@@ -2333,50 +2343,7 @@ func ApiValidatorBalanceHistory(w http.ResponseWriter, r *http.Request) {
 		return responseData[i].Validatorindex < responseData[j].Validatorindex
 	})
 
-	response := &types.ApiResponse{}
-	response.Status = "OK"
-
-	response.Data = responseData
-
-	err = j.Encode(response)
-
-	if err != nil {
-		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
-		return
-	}
-}
-
-func getBalanceHistoryQueryParameters(q url.Values) (uint64, uint64, error) {
-	onChainLatestEpoch := services.LatestEpoch()
-	defaultLimit := uint64(100)
-
-	latestEpoch := onChainLatestEpoch
-	if q.Has("latest_epoch") {
-		var err error
-		latestEpoch, err = strconv.ParseUint(q.Get("latest_epoch"), 10, 64)
-		if err != nil || latestEpoch > onChainLatestEpoch {
-			return 0, 0, fmt.Errorf("invalid latest epoch parameter")
-		}
-	}
-
-	if q.Has("offset") {
-		offset, err := strconv.ParseUint(q.Get("offset"), 10, 64)
-		if err != nil || offset > latestEpoch {
-			return 0, 0, fmt.Errorf("invalid offset parameter")
-		}
-		latestEpoch -= offset
-	}
-
-	limit := defaultLimit
-	if q.Has("limit") {
-		var err error
-		limit, err = strconv.ParseUint(q.Get("limit"), 10, 64)
-		if err != nil || limit > defaultLimit || limit < 1 {
-			return 0, 0, fmt.Errorf("invalid limit parameter")
-		}
-	}
-
-	return latestEpoch, limit, nil
+	sendApiListResponse(w, r, responseData, next)
 }
 
 // ApiValidatorPerformance godoc
@@ -2636,11 +2603,13 @@ func ApiValidatorLeaderboard(w http.ResponseWriter, r *http.Request) {
 
 // ApiValidatorDeposits godoc
 // @Summary Get validator execution layer deposits
-// @Description Get all eth1 deposits for up to 100 validators
+// @Description Get all eth1 deposits for up to 100 validators, newest first
 // @Tags Validators
 // @Produce  json
 // @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
-// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorDepositsResponse}
+// @Param  limit query int false "Maximum number of deposits per page (default and maximum: 100)"
+// @Param  cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} types.ApiListResponse{data=[]types.ApiValidatorDepositsResponse}
 // @Failure 400 {object} types.ApiResponse
 // @Router /api/v1/validator/{indexOrPubkey}/deposits [get]
 func ApiValidatorDeposits(w http.ResponseWriter, r *http.Request) {
@@ -2655,9 +2624,28 @@ func ApiValidatorDeposits(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	q := r.URL.Query()
+	limit, err := parseApiListLimit(q)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	filter := apiPubkeysCursorFilter(pubkeys)
+	cursor, err := parseApiCursor(q, "deposits", filter)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	if cursor == nil {
+		cursor = &apiCursor{}
+	}
+
+	// the merkletree index is unique, the cursor continues behind the last returned deposit
 	rows, err := db.ReaderDb.Query(
 		`SELECT amount, block_number, block_ts, from_address, merkletree_index, publickey, removed, signature, tx_hash, tx_index, tx_input, valid_signature, withdrawal_credentials FROM eth1_deposits 
-		WHERE publickey = ANY($1)`, pubkeys,
+		WHERE publickey = ANY($1) AND ($2::BYTEA IS NULL OR (block_number, merkletree_index) < ($3, $2))
+		ORDER BY block_number DESC, merkletree_index DESC
+		LIMIT $4`, pubkeys, cursor.Key, cursor.Block, limit,
 	)
 	if err != nil {
 		logger.WithError(err).Error("could not retrieve db results")
@@ -2666,7 +2654,99 @@ func ApiValidatorDeposits(w http.ResponseWriter, r *http.Request) {
 	}
 	defer rows.Close()
 
-	returnQueryResultsAsArray(rows, w, r)
+	data, err := utils.SqlRowsToJSON(rows)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse db results")
+		return
+	}
+	next, err := apiKeysetCursor("deposits", filter, data, limit, apiBlockNumberColumn, "merkletree_index")
+	if err != nil {
+		logger.WithError(err).Errorf("error creating cursor for API %v route", r.URL.String())
+		sendServerErrorResponse(w, r.URL.String(), "could not create cursor")
+		return
+	}
+
+	sendApiListResponse(w, r, data, next)
+}
+
+// ApiValidatorQueuePositions godoc
+// @Summary Get the queue position of validators
+// @Description Returns the position of up to 100 validators in the activation or exit queue, the estimated activation or exit epoch and the history of the estimate. Validators that are not queued are omitted.
+// @Tags Validators
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Param  limit query int false "Maximum number of history entries per validator (default and maximum: 100)"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorQueuePositionResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/queue [get]
+func ApiValidatorQueuePositions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	limit, err := strconv.ParseUint(r.URL.Query().Get("limit"), 10, 64)
+	if err != nil || limit == 0 || limit > 100 {
+		limit = 100
+	}
+
+	pubkeys, err := parseApiValidatorParamToPubkeys(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	positions, err := db.GetValidatorQueuePositions(pubkeys)
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve db results")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	history, err := db.GetValidatorQueuePositionHistory(pubkeys, limit*uint64(len(pubkeys)))
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve db results")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	historyByQueue := make(map[string][]types.ApiValidatorQueuePositionHistoryResponse)
+	for _, h := range history {
+		key := string(h.Queue) + string(h.Pubkey)
+		if uint64(len(historyByQueue[key])) >= limit {
+			continue
+		}
+		historyByQueue[key] = append(historyByQueue[key], types.ApiValidatorQueuePositionHistoryResponse{
+			Ts:             h.Ts.Unix(),
+			Position:       h.Position,
+			BalanceAhead:   h.BalanceAhead,
+			EstimatedEpoch: h.EstEpoch,
+		})
+	}
+
+	data := make([]interface{}, 0, len(positions))
+	for _, p := range positions {
+		res := types.ApiValidatorQueuePositionResponse{
+			Pubkey:         fmt.Sprintf("%#x", []byte(p.Pubkey)),
+			Queue:          string(p.Queue),
+			Position:       p.Position,
+			BalanceAhead:   p.BalanceAhead,
+			EstimatedEpoch: p.EstEpoch,
+			EstimatedTs:    utils.EpochToTime(p.EstEpoch).Unix(),
+			History:        historyByQueue[string(p.Queue)+string(p.Pubkey)],
+		}
+		if p.ValidatorIndex.Valid {
+			index := uint64(p.ValidatorIndex.Int64)
+			res.ValidatorIndex = &index
+		}
+		if res.History == nil {
+			res.History = []types.ApiValidatorQueuePositionHistoryResponse{}
+		}
+		data = append(data, res)
+	}
+
+	SendOKResponse(j, r.URL.String(), data)
 }
 
 // ApiValidatorAttestations godoc
@@ -2678,14 +2758,14 @@ func ApiValidatorDeposits(w http.ResponseWriter, r *http.Request) {
 // @Param  startEpoch query int false "Start epoch for the query (default: latest epoch - 99)"
 // @Param  endEpoch query int false "End epoch for the query (default: latest epoch)"
 // @Param  slim query bool false "If true, drops rarely used week and committee index fields from the response"
-// @Success 200 {object} types.ApiResponse{[]types.ApiValidatorAttestationsResponse}
+// @Param  cursor query string false "next_cursor of the previous page, returns the attestations of the 100 epochs before the previous page"
+// @Success 200 {object} types.ApiListResponse{data=[]types.ApiValidatorAttestationsResponse}
 // @Failure 400 {object} types.ApiResponse
 // @Router /api/v1/validator/{indexOrPubkey}/attestations [get]
 func ApiValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	j := json.NewEncoder(w)
 	q := r.URL.Query()
 	vars := mux.Vars(r)
 	maxValidators := getUserPremium(r).MaxValidators
@@ -2697,8 +2777,26 @@ func ApiValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	startEpoch := max(latestEpoch-99, 0)
+	filter := apiCursorFilter(queryIndices)
+	cursor, err := parseApiCursor(q, "attestations", filter)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	if cursor != nil && (q.Has("startEpoch") || q.Has("endEpoch")) {
+		SendBadRequestResponse(w, r.URL.String(), "the cursor parameter can not be combined with the startEpoch or endEpoch parameter")
+		return
+	}
+
 	endEpoch := latestEpoch
+	if cursor != nil {
+		if cursor.Epoch > latestEpoch {
+			SendBadRequestResponse(w, r.URL.String(), "invalid cursor parameter")
+			return
+		}
+		endEpoch = cursor.Epoch
+	}
+	startEpoch, _ := apiEpochWindow("attestations", filter, endEpoch, apiListEpochWindow)
 
 	if q.Has("startEpoch") {
 		userStartEpoch, err := strconv.ParseUint(q.Get("startEpoch"), 10, 64)
@@ -2755,8 +2853,10 @@ func ApiValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 		return responseData[i].ValidatorIndex < responseData[j].ValidatorIndex
 	})
 
-	response := &types.ApiResponse{}
-	response.Status = "OK"
+	var next *apiCursor
+	if startEpoch > 0 {
+		next = &apiCursor{List: "attestations", Filter: filter, Epoch: startEpoch - 1}
+	}
 
 	if q.Has("slim") && q.Get("slim") == "true" {
 		// if slim is true, drop the week and committee index fields
@@ -2770,18 +2870,12 @@ func ApiValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 				ValidatorIndex: attestation.ValidatorIndex,
 			})
 		}
-		response.Data = slimmedResponseData
-	} else {
-		// otherwise, keep the full response data
-		response.Data = responseData
-	}
-
-	err = j.Encode(response)
-
-	if err != nil {
-		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
+		sendApiListResponse(w, r, slimmedResponseData, next)
 		return
 	}
+
+	// otherwise, keep the full response data
+	sendApiListResponse(w, r, responseData, next)
 }
 
 // ApiValidatorProposals godoc
@@ -2791,7 +2885,8 @@ func ApiValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 // @Produce  json
 // @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
 // @Param  epoch query string false "Page the result by epoch"
-// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorProposalsResponse}
+// @Param  cursor query string false "next_cursor of the previous page, returns the proposals of the epochs before the previous page"
+// @Success 200 {object} types.ApiListResponse{data=[]types.ApiValidatorProposalsResponse}
 // @Failure 400 {object} types.ApiResponse
 // @Router /api/v1/validator/{indexOrPubkey}/proposals [get]
 func ApiValidatorProposals(w http.ResponseWriter, r *http.Request) {
@@ -2802,27 +2897,42 @@ func ApiValidatorProposals(w http.ResponseWriter, r *http.Request) {
 	maxValidators := getUserPremium(r).MaxValidators
 	q := r.URL.Query()
 
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	filter := apiCursorFilter(queryIndices)
+	cursor, err := parseApiCursor(q, "proposals", filter)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
 	epochQuery := uint64(0)
-	if q.Get("epoch") == "" {
+	if cursor != nil {
+		if q.Get("epoch") != "" {
+			SendBadRequestResponse(w, r.URL.String(), "the cursor parameter can not be combined with the epoch parameter")
+			return
+		}
+		epochQuery = cursor.Epoch
+	} else if q.Get("epoch") == "" {
 		epochQuery = services.LatestEpoch()
 	} else {
-		var err error
 		epochQuery, err = strconv.ParseUint(q.Get("epoch"), 10, 64)
 		if err != nil {
 			SendBadRequestResponse(w, r.URL.String(), err.Error())
 			return
 		}
 	}
-
-	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
-	if err != nil {
-		SendBadRequestResponse(w, r.URL.String(), err.Error())
-		return
-	}
-	if epochQuery < 100 {
+	if cursor == nil && epochQuery < 100 {
 		epochQuery = 100
 	}
 
+	// the page contains the epochs epochQuery-100 up to epochQuery
+	firstEpoch, next := apiEpochWindow("proposals", filter, epochQuery, apiListEpochWindow+1)
+
 	rows, err := db.ReaderDb.Query(`
 	SELECT 
 		b.epoch,
@@ -2864,14 +2974,21 @@ func ApiValidatorProposals(w http.ResponseWriter, r *http.Request) {
 	FROM blocks as b 
 	LEFT JOIN validators ON validators.validatorindex = b.proposer 
 	WHERE (proposer = ANY($1)) and epoch <= $2 AND epoch >= $3 
-	ORDER BY proposer, epoch desc, slot desc`, pq.Array(queryIndices), epochQuery, epochQuery-100)
+	ORDER BY proposer, epoch desc, slot desc`, pq.Array(queryIndices), epochQuery, firstEpoch)
 	if err != nil {
 		logger.Errorf("could not retrieve db results: %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
+	defer rows.Close()
 
-	returnQueryResultsAsArray(rows, w, r)
+	data, err := utils.SqlRowsToJSON(rows)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse db results")
+		return
+	}
+
+	sendApiListResponse(w, r, data, next)
 }
 
 func ApiValidatorConsolidationRequests(w http.ResponseWriter, r *http.Request) {
@@ -2908,6 +3025,21 @@ func ApiValidatorConsolidationRequests(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter := apiCursorFilter(queryIndices)
+	cursor, err := parseApiCursor(q, "consolidations", filter)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	if cursor != nil && offset > 0 {
+		SendBadRequestResponse(w, r.URL.String(), "the cursor parameter can not be combined with the offset parameter")
+		return
+	}
+	afterSlot, afterIndex := uint64(0), uint64(0)
+	if cursor != nil {
+		afterSlot, afterIndex = cursor.Slot, cursor.Index
+	}
+
 	rows, err := db.ReaderDb.Query(`
 	SELECT 
 		slot_processed as block_slot, 
@@ -2919,11 +3051,12 @@ func ApiValidatorConsolidationRequests(w http.ResponseWriter, r *http.Request) {
 	FROM blocks_consolidation_requests_v2 
 	INNER JOIN validators sv ON (sv.pubkey = source_pubkey)
 	INNER JOIN validators tv ON (tv.pubkey = target_pubkey)
-	WHERE sv.validatorindex = ANY($1) OR tv.validatorindex = ANY($1) 
+	WHERE (sv.validatorindex = ANY($1) OR tv.validatorindex = ANY($1))
 	AND blocks_consolidation_requests_v2.status = 'completed'
+	AND ($4::BOOL OR (slot_processed, index_processed) < ($5, $6))
 	ORDER BY slot_processed DESC, index_processed DESC 
 	limit $2 offset $3
-	`, pq.Array(queryIndices), limit, offset)
+	`, pq.Array(queryIndices), limit, offset, cursor == nil, afterSlot, afterIndex)
 	if err != nil {
 		logger.WithError(err).Error("could not retrieve db results")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
@@ -2931,7 +3064,19 @@ func ApiValidatorConsolidationRequests(w http.ResponseWriter, r *http.Request) {
 	}
 	defer rows.Close()
 
-	returnQueryResultsAsArray(rows, w, r)
+	data, err := utils.SqlRowsToJSON(rows)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse db results")
+		return
+	}
+	next, err := apiKeysetCursor("consolidations", filter, data, uint64(limit), "block_slot", "request_index")
+	if err != nil {
+		logger.WithError(err).Errorf("error creating cursor for API %v route", r.URL.String())
+		sendServerErrorResponse(w, r.URL.String(), "could not create cursor")
+		return
+	}
+
+	sendApiListResponse(w, r, data, next)
 }
 
 func ApiValidatorSwitchToCompoundingRequests(w http.ResponseWriter, r *http.Request) {
@@ -2968,6 +3113,21 @@ func ApiValidatorSwitchToCompoundingRequests(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	filter := apiCursorFilter(queryIndices)
+	cursor, err := parseApiCursor(q, "switch_to_compounding", filter)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	if cursor != nil && offset > 0 {
+		SendBadRequestResponse(w, r.URL.String(), "the cursor parameter can not be combined with the offset parameter")
+		return
+	}
+	afterSlot, afterIndex := uint64(0), uint64(0)
+	if cursor != nil {
+		afterSlot, afterIndex = cursor.Slot, cursor.Index
+	}
+
 	// TODO: remove v1 table dependency once eth1id resolving is available
 	// See https://bitfly1.atlassian.net/browse/BEDS-1522
 	rows, err := db.ReaderDb.Query(`
@@ -2982,8 +3142,9 @@ func ApiValidatorSwitchToCompoundingRequests(w http.ResponseWriter, r *http.Requ
 		LEFT JOIN blocks_switch_to_compounding_requests v1 ON (blocks_switch_to_compounding_requests_v2.slot_processed = v1.block_slot AND blocks_switch_to_compounding_requests_v2.block_processed_root = v1.block_root AND blocks_switch_to_compounding_requests_v2.index_processed = v1.request_index)
 		WHERE v.validatorindex = ANY($1) 
 		AND blocks_switch_to_compounding_requests_v2.status = 'completed'
+		AND ($4::BOOL OR (slot_processed, index_processed) < ($5, $6))
 		ORDER BY slot_processed DESC, index_processed DESC 
-		limit $2 offset $3`, pq.Array(queryIndices), limit, offset)
+		limit $2 offset $3`, pq.Array(queryIndices), limit, offset, cursor == nil, afterSlot, afterIndex)
 	if err != nil {
 		logger.WithError(err).Error("could not retrieve db results")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
@@ -2991,7 +3152,19 @@ func ApiValidatorSwitchToCompoundingRequests(w http.ResponseWriter, r *http.Requ
 	}
 	defer rows.Close()
 
-	returnQueryResultsAsArray(rows, w, r)
+	data, err := utils.SqlRowsToJSON(rows)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse db results")
+		return
+	}
+	next, err := apiKeysetCursor("switch_to_compounding", filter, data, uint64(limit), "block_slot", "request_index")
+	if err != nil {
+		logger.WithError(err).Errorf("error creating cursor for API %v route", r.URL.String())
+		sendServerErrorResponse(w, r.URL.String(), "could not create cursor")
+		return
+	}
+
+	sendApiListResponse(w, r, data, next)
 }
 
 // ApiGraffitiwall godoc
@@ -4090,7 +4263,8 @@ func insertStats(userData *types.UserWithPremium, machine string, body *map[stri
 // @Param withdrawalCredentialsOrEth1address path string true "Provide a withdrawal credential or an eth1 address with an optional 0x prefix". It can also be a valid ENS name.
 // @Param  limit query int false "Limit the number of results, maximum: 200" default(10)
 // @Param offset query int false "Offset the number of results" default(0)
-// @Success 200 {object} types.ApiResponse{data=[]types.ApiWithdrawalCredentialsResponse}
+// @Param cursor query string false "next_cursor of the previous page, can not be combined with offset"
+// @Success 200 {object} types.ApiListResponse{data=[]types.ApiWithdrawalCredentialsResponse}
 // @Failure 400 {object} types.ApiResponse
 // @Router /api/v1/validator/withdrawalCredentials/{withdrawalCredentialsOrEth1address} [get]
 func ApiWithdrawalCredentialsValidators(w http.ResponseWriter, r *http.Request) {
@@ -4116,6 +4290,17 @@ func ApiWithdrawalCredentialsValidators(w http.ResponseWriter, r *http.Request)
 		credentials = [][]byte{credentialsOrAddress}
 	}
 
+	filter := apiPubkeysCursorFilter(credentials)
+	cursor, err := parseApiCursor(q, "withdrawal_credentials", filter)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	if cursor != nil && q.Has("offset") {
+		SendBadRequestResponse(w, r.URL.String(), "the cursor parameter can not be combined with the offset parameter")
+		return
+	}
+
 	limitQuery := q.Get("limit")
 	offsetQuery := q.Get("offset")
 
@@ -4127,6 +4312,12 @@ func ApiWithdrawalCredentialsValidators(w http.ResponseWriter, r *http.Request)
 
 	limit = utilMath.MinU64(limit, maxLimit)
 
+	// the validator index is unique, the cursor continues behind the last returned validator
+	afterIndex := uint64(0)
+	if cursor != nil {
+		afterIndex = cursor.Index
+	}
+
 	result := []struct {
 		Index  uint64 `db:"validatorindex"`
 		Pubkey []byte `db:"pubkey"`
@@ -4137,11 +4328,11 @@ func ApiWithdrawalCredentialsValidators(w http.ResponseWriter, r *http.Request)
 		validatorindex,
 		pubkey
 	FROM validators
-	WHERE withdrawalcredentials = ANY($1)
+	WHERE withdrawalcredentials = ANY($1) AND ($4::BOOL OR validatorindex > $5)
 	ORDER BY validatorindex ASC
 	LIMIT $2
 	OFFSET $3
-	`, credentials, limit, offset)
+	`, credentials, limit, offset, cursor == nil, afterIndex)
 
 	if err != nil {
 		logger.Warnf("error retrieving validator data from db: %v", err)
@@ -4157,7 +4348,12 @@ func ApiWithdrawalCredentialsValidators(w http.ResponseWriter, r *http.Request)
 		})
 	}
 
-	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+	var next *apiCursor
+	if limit > 0 && uint64(len(result)) == limit {
+		next = &apiCursor{List: "withdrawal_credentials", Filter: filter, Index: result[len(result)-1].Index}
+	}
+
+	sendApiListResponse(w, r, response, next)
 }
 
 // ApiProposalLuck godoc
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+const (
+	// maxApiListLimit is the maximum number of entries the keyset paginated list endpoints return per page
+	maxApiListLimit = 100
+	// apiListEpochWindow is the number of epochs the epoch paginated list endpoints return per page
+	apiListEpochWindow = 100
+	// apiBlockNumberColumn is the column of the keyset paginated lists that are ordered by execution block
+	apiBlockNumberColumn = "block_number"
+)
+
+// The list endpoints of the api that are not paginated with a cursor return all of their entries in a single
+// response or keep their own parameters:
+//   - the block level lists (/slot/{slot}/..., /epoch/{epoch}/slots) and the sync committee of a period are bounded
+//     by the block, epoch or period they are requested for
+//   - /validator/{indexOrPubkey}/queue returns the queue history of each validator limited by limit, it is not paged
+//   - /validator/leaderboard is a fixed list of the top validators
+//   - /graffitiwall returns the pixels painted within a slot range
+//   - /user/stats/{offset}/{limit} takes offset and limit as path parameters the mobile app relies on
+//   - /validator/eth1/{eth1address} includes deposits of validators that have no index yet, so there is no unique
+//     position to continue behind; it keeps limit and offset with a maximum of 2000 entries per page
+
+// apiCursor is the position of a paginated list request. It is handed to the client as an opaque token, the client
+// passes it back with the cursor query parameter to get the next page.
+type apiCursor struct {
+	List   string `json:"l"`           // the list endpoint the cursor was created for
+	Filter uint64 `json:"f"`           // hash of the validators the list was requested for
+	Epoch  uint64 `json:"e,omitempty"` // epoch paginated lists: the last epoch of the next page
+	Slot   uint64 `json:"s,omitempty"` // keyset paginated lists: the slot of the last returned entry
+	Block  uint64 `json:"b,omitempty"` // keyset paginated lists: the execution block number of the last returned entry
+	Index  uint64 `json:"i,omitempty"` // keyset paginated lists: the index of the last returned entry within the slot
+	Key    []byte `json:"k,omitempty"` // keyset paginated lists: the key of the last returned entry within the slot or block
+}
+
+// encodeApiCursor returns the opaque token of a cursor
+func encodeApiCursor(c *apiCursor) string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		// the cursor only consists of plain fields, marshalling can not fail
+		logger.WithError(err).Errorf("error encoding api cursor")
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// parseApiCursor returns the cursor of the cursor query parameter or nil if the parameter is not set. The cursor must
+// have been created for the same list and validators.
+func parseApiCursor(q url.Values, list string, filter uint64) (*apiCursor, error) {
+	token := q.Get("cursor")
+	if token == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor parameter")
+	}
+	c := &apiCursor{}
+	err = json.Unmarshal(b, c)
+	if err != nil || c.List != list {
+		return nil, fmt.Errorf("invalid cursor parameter")
+	}
+	if c.Filter != filter {
+		return nil, fmt.Errorf("cursor parameter does not belong to the requested validators")
+	}
+	return c, nil
+}
+
+// apiCursorFilter hashes the requested validators so that a cursor can not be used for a different set of validators.
+// The order of the validators does not matter.
+func apiCursorFilter[T uint64 | string](values []T) uint64 {
+	keys := make([]string, 0, len(values))
+	for _, v := range values {
+		keys = append(keys, fmt.Sprint(v))
+	}
+	sort.Strings(keys)
+	h := fnv.New64a()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// apiPubkeysCursorFilter hashes the requested validator pubkeys, see apiCursorFilter
+func apiPubkeysCursorFilter(pubkeys [][]byte) uint64 {
+	keys := make([]string, 0, len(pubkeys))
+	for _, p := range pubkeys {
+		keys = append(keys, hex.EncodeToString(p))
+	}
+	return apiCursorFilter(keys)
+}
+
+// parseApiListLimit returns the limit query parameter of keyset paginated list endpoints (default and maximum: 100)
+func parseApiListLimit(q url.Values) (uint64, error) {
+	if !q.Has("limit") {
+		return maxApiListLimit, nil
+	}
+	limit, err := strconv.ParseUint(q.Get("limit"), 10, 64)
+	if err != nil || limit < 1 || limit > maxApiListLimit {
+		return 0, fmt.Errorf("invalid limit parameter")
+	}
+	return limit, nil
+}
+
+// apiEpochWindow returns the first epoch of the page of size epochs that ends with lastEpoch and the cursor of the
+// following page, the cursor is nil if the page reaches the genesis epoch
+func apiEpochWindow(list string, filter, lastEpoch, size uint64) (uint64, *apiCursor) {
+	if lastEpoch < size {
+		return 0, nil
+	}
+	firstEpoch := lastEpoch - size + 1
+	if firstEpoch == 0 {
+		return 0, nil
+	}
+	return firstEpoch, &apiCursor{List: list, Filter: filter, Epoch: firstEpoch - 1}
+}
+
+// apiKeysetCursor returns the cursor of the page following the query results of a keyset paginated list. The cursor
+// points behind the last entry and is nil if the page was not full. Lists ordered by execution block pass
+// apiBlockNumberColumn as slotColumn, its value is stored in the Block field of the cursor.
+func apiKeysetCursor(list string, filter uint64, data []interface{}, limit uint64, slotColumn, indexColumn string) (*apiCursor, error) {
+	if uint64(len(data)) < limit || len(data) == 0 {
+		return nil, nil
+	}
+	last, ok := data[len(data)-1].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("error type asserting query results as a map")
+	}
+
+	c := &apiCursor{List: list, Filter: filter}
+	slot, ok := last[slotColumn].(int64)
+	if !ok || slot < 0 {
+		return nil, fmt.Errorf("error getting %v of the last entry", slotColumn)
+	}
+	if slotColumn == apiBlockNumberColumn {
+		c.Block = uint64(slot)
+	} else {
+		c.Slot = uint64(slot)
+	}
+
+	switch index := last[indexColumn].(type) {
+	case int64:
+		if index < 0 {
+			return nil, fmt.Errorf("error getting %v of the last entry", indexColumn)
+		}
+		c.Index = uint64(index)
+	case string:
+		// bytea columns are returned as 0x prefixed hex strings
+		key, err := hex.DecodeString(strings.TrimPrefix(index, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %v of the last entry: %w", indexColumn, err)
+		}
+		c.Key = key
+	default:
+		return nil, fmt.Errorf("error getting %v of the last entry", indexColumn)
+	}
+	return c, nil
+}
+
+// sendApiListResponse sends a page of a paginated list, next_cursor is null on the last page
+func sendApiListResponse(w http.ResponseWriter, r *http.Request, data interface{}, next *apiCursor) {
+	response := &types.ApiListResponse{
+		Status: "OK",
+		Data:   data,
+	}
+	if next != nil {
+		token := encodeApiCursor(next)
+		response.NextCursor = &token
+	}
+
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
+		logger.Errorf("error serializing json data for API %v route: %v", r.URL.String(), err)
+	}
+}
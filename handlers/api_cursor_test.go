@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseApiCursor(t *testing.T) {
+	filter := apiCursorFilter([]uint64{1, 2, 3})
+	valid := encodeApiCursor(&apiCursor{List: "withdrawals", Filter: filter, Epoch: 1234})
+	keyset := encodeApiCursor(&apiCursor{List: "deposits", Filter: filter, Block: 21000000, Key: []byte{0x0a, 0xff}})
+
+	tests := []struct {
+		name    string
+		token   string
+		list    string
+		filter  uint64
+		want    *apiCursor
+		wantErr bool
+	}{
+		{name: "no cursor", token: "", list: "withdrawals", filter: filter, want: nil},
+		{name: "valid", token: valid, list: "withdrawals", filter: filter, want: &apiCursor{List: "withdrawals", Filter: filter, Epoch: 1234}},
+		{name: "keyset", token: keyset, list: "deposits", filter: filter, want: &apiCursor{List: "deposits", Filter: filter, Block: 21000000, Key: []byte{0x0a, 0xff}}},
+		{name: "keyset of another list", token: keyset, list: "consolidations", filter: filter, wantErr: true},
+		{name: "other list", token: valid, list: "proposals", filter: filter, wantErr: true},
+		{name: "other validators", token: valid, list: "withdrawals", filter: apiCursorFilter([]uint64{1, 2}), wantErr: true},
+		{name: "not base64", token: "not a cursor!", list: "withdrawals", filter: filter, wantErr: true},
+		{name: "not json", token: "bm90IGpzb24", list: "withdrawals", filter: filter, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseApiCursor(url.Values{"cursor": []string{tt.token}}, tt.list, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got cursor %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApiCursorFilter(t *testing.T) {
+	if apiCursorFilter([]uint64{3, 1, 2}) != apiCursorFilter([]uint64{1, 2, 3}) {
+		t.Errorf("the filter depends on the order of the validators")
+	}
+	if apiCursorFilter([]uint64{1, 23}) == apiCursorFilter([]uint64{12, 3}) {
+		t.Errorf("different validators result in the same filter")
+	}
+	if apiPubkeysCursorFilter([][]byte{{0x01}, {0x02}}) != apiPubkeysCursorFilter([][]byte{{0x02}, {0x01}}) {
+		t.Errorf("the pubkey filter depends on the order of the validators")
+	}
+}
+
+func TestParseApiListLimit(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    uint64
+		wantErr bool
+	}{
+		{query: "", want: 100},
+		{query: "limit=1", want: 1},
+		{query: "limit=100", want: 100},
+		{query: "limit=0", wantErr: true},
+		{query: "limit=101", wantErr: true},
+		{query: "limit=-1", wantErr: true},
+		{query: "limit=abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			got, err := parseApiListLimit(q)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got limit %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApiEpochWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		lastEpoch  uint64
+		size       uint64
+		wantFirst  uint64
+		wantCursor *uint64
+	}{
+		{name: "genesis", lastEpoch: 0, size: 100, wantFirst: 0},
+		{name: "before first full window", lastEpoch: 98, size: 100, wantFirst: 0},
+		{name: "first full window", lastEpoch: 99, size: 100, wantFirst: 0},
+		{name: "second window", lastEpoch: 100, size: 100, wantFirst: 1, wantCursor: ptr(uint64(0))},
+		{name: "later window", lastEpoch: 250000, size: 100, wantFirst: 249901, wantCursor: ptr(uint64(249900))},
+		{name: "inclusive window of proposals", lastEpoch: 250000, size: 101, wantFirst: 249900, wantCursor: ptr(uint64(249899))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, next := apiEpochWindow("withdrawals", 1, tt.lastEpoch, tt.size)
+			if first != tt.wantFirst {
+				t.Errorf("got first epoch %v, want %v", first, tt.wantFirst)
+			}
+			if (next == nil) != (tt.wantCursor == nil) || (next != nil && next.Epoch != *tt.wantCursor) {
+				t.Errorf("got cursor %+v, want epoch %v", next, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestApiKeysetCursor(t *testing.T) {
+	rows := func(entries ...map[string]interface{}) []interface{} {
+		data := []interface{}{}
+		for _, row := range entries {
+			data = append(data, row)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name        string
+		data        []interface{}
+		limit       uint64
+		slotColumn  string
+		indexColumn string
+		want        *apiCursor
+		wantErr     bool
+	}{
+		{
+			name:        "full page",
+			data:        rows(map[string]interface{}{"block_slot": int64(12), "request_index": int64(3)}, map[string]interface{}{"block_slot": int64(10), "request_index": int64(1)}),
+			limit:       2,
+			slotColumn:  "block_slot",
+			indexColumn: "request_index",
+			want:        &apiCursor{List: "deposits", Filter: 1, Slot: 10, Index: 1},
+		},
+		{
+			name:        "bytea key",
+			data:        rows(map[string]interface{}{"slot": int64(7), "key": "0x00ff"}),
+			limit:       1,
+			slotColumn:  "slot",
+			indexColumn: "key",
+			want:        &apiCursor{List: "deposits", Filter: 1, Slot: 7, Key: []byte{0x00, 0xff}},
+		},
+		{
+			name:        "block number",
+			data:        rows(map[string]interface{}{"block_number": int64(21000000), "merkletree_index": "0x0a00000000000000"}),
+			limit:       1,
+			slotColumn:  apiBlockNumberColumn,
+			indexColumn: "merkletree_index",
+			want:        &apiCursor{List: "deposits", Filter: 1, Block: 21000000, Key: []byte{0x0a, 0, 0, 0, 0, 0, 0, 0}},
+		},
+		{
+			name:        "short page",
+			data:        rows(map[string]interface{}{"slot": int64(7), "index": int64(1)}),
+			limit:       2,
+			slotColumn:  "slot",
+			indexColumn: "index",
+		},
+		{name: "no entries", data: []interface{}{}, limit: 2, slotColumn: "slot", indexColumn: "index"},
+		{name: "not a map", data: []interface{}{"row"}, limit: 1, slotColumn: "slot", indexColumn: "index", wantErr: true},
+		{name: "missing slot", data: rows(map[string]interface{}{"index": int64(1)}), limit: 1, slotColumn: "slot", indexColumn: "index", wantErr: true},
+		{name: "negative slot", data: rows(map[string]interface{}{"slot": int64(-1), "index": int64(1)}), limit: 1, slotColumn: "slot", indexColumn: "index", wantErr: true},
+		{name: "missing index", data: rows(map[string]interface{}{"slot": int64(1)}), limit: 1, slotColumn: "slot", indexColumn: "index", wantErr: true},
+		{name: "invalid key", data: rows(map[string]interface{}{"slot": int64(1), "index": "0xzz"}), limit: 1, slotColumn: "slot", indexColumn: "index", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := apiKeysetCursor("deposits", 1, tt.data, tt.limit, tt.slotColumn, tt.indexColumn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got cursor %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendApiListResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		next     *apiCursor
+		wantNext bool
+	}{
+		{name: "last page", next: nil, wantNext: false},
+		{name: "more pages", next: &apiCursor{List: "proposals", Epoch: 1}, wantNext: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			sendApiListResponse(rec, httptest.NewRequest("GET", "/api/v1/validator/1/proposals", nil), []int{1, 2}, tt.next)
+
+			res := map[string]json.RawMessage{}
+			err := json.Unmarshal(rec.Body.Bytes(), &res)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(res["status"]) != `"OK"` || string(res["data"]) != "[1,2]" {
+				t.Errorf("got response %v", rec.Body.String())
+			}
+			next, ok := res["next_cursor"]
+			if !ok {
+				t.Fatalf("next_cursor is missing in %v", rec.Body.String())
+			}
+			if (string(next) != "null") != tt.wantNext {
+				t.Errorf("got next_cursor %v, want a cursor %v", string(next), tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestGetValidatorHistoryQueryParameters(t *testing.T) {
+	filter := apiCursorFilter([]uint64{1, 2})
+	cursor := encodeApiCursor(&apiCursor{List: "balancehistory", Filter: filter, Epoch: 899})
+
+	tests := []struct {
+		name       string
+		query      url.Values
+		wantLatest uint64
+		wantLimit  uint64
+		wantErr    bool
+	}{
+		{name: "defaults", query: url.Values{}, wantLatest: 1000, wantLimit: 100},
+		{name: "latest epoch and offset", query: url.Values{"latest_epoch": {"500"}, "offset": {"10"}, "limit": {"5"}}, wantLatest: 490, wantLimit: 5},
+		{name: "cursor", query: url.Values{"cursor": {cursor}, "limit": {"5"}}, wantLatest: 899, wantLimit: 5},
+		{name: "cursor with latest epoch", query: url.Values{"cursor": {cursor}, "latest_epoch": {"500"}}, wantErr: true},
+		{name: "cursor with offset", query: url.Values{"cursor": {cursor}, "offset": {"10"}}, wantErr: true},
+		{name: "cursor of another list", query: url.Values{"cursor": {encodeApiCursor(&apiCursor{List: "incomedetailhistory", Filter: filter, Epoch: 899})}}, wantErr: true},
+		{name: "latest epoch in the future", query: url.Values{"latest_epoch": {"1001"}}, wantErr: true},
+		{name: "limit too high", query: url.Values{"limit": {"101"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest, limit, err := getValidatorHistoryQueryParameters(tt.query, "balancehistory", filter, 1000)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if latest != tt.wantLatest || limit != tt.wantLimit {
+				t.Errorf("got latest epoch %v and limit %v, want %v and %v", latest, limit, tt.wantLatest, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
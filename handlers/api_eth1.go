@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/eth1data"
 	"github.com/gobitfly/eth2-beaconchain-explorer/price"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
@@ -253,7 +256,7 @@ func ApiETH1AccountProducedBlocks(w http.ResponseWriter, r *http.Request) {
 // ApiETH1GasNowData godoc
 // @Tags Gas
 // @Summary Get current gas prices
-// @Description Gets the current estimation for gas prices in GWei.. The response is split into four estimated inclusion speeds rapid (15 seconds), fast (1 minute), standard (3 minutes) and slow (> 10 minutes).
+// @Description Gets the current estimation for gas prices in GWei.. The response is split into four estimated inclusion speeds rapid (15 seconds), fast (1 minute), standard (3 minutes) and slow (> 10 minutes). On chains with blobs the response also contains the blob gas values of the pending block and a forecast of the blob base fee of the following blocks if they contain no blobs, the target or the max number of blobs.
 // @Produce json
 // @Success 200 {object} types.GasNowPageData
 // @Failure 400 {object} types.ApiResponse
@@ -282,6 +285,76 @@ func ApiEth1GasNowData(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ApiEth1BlobSubmitters godoc
+// @Tags Network
+// @Summary Get daily blob submitter stats
+// @Description Returns the daily number of blob transactions, blobs, blob gas used and blob fees paid in wei per sender and to-address, newest day last. Known rollups are labeled by name. The fill efficiency is the share of non-zero bytes of the blobs and is null if the blobs have not been measured.
+// @Produce json
+// @Param days query int false "number of days, at most 365" default(30)
+// @Param address query string false "only return the stats where the address is the sender or the to-address"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiBlobSubmitterStatsResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/blobs/submitters [get]
+func ApiEth1BlobSubmitters(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	days := uint64(30)
+	if q.Get("days") != "" {
+		var err error
+		days, err = strconv.ParseUint(q.Get("days"), 10, 64)
+		if err != nil || days == 0 || days > 365 {
+			SendBadRequestResponse(w, r.URL.String(), "error invalid days, must be between 1 and 365")
+			return
+		}
+	}
+
+	var address []byte
+	if q.Get("address") != "" {
+		addr := ReplaceEnsNameWithAddress(q.Get("address"))
+		addr = strings.ToLower(strings.Replace(addr, "0x", "", -1))
+		if !utils.IsEth1Address(addr) {
+			SendBadRequestResponse(w, r.URL.String(), "error invalid address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+			return
+		}
+		address = common.FromHex(addr)
+	}
+
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -int(days))
+	stats, err := db.GetBlobSubmitterStats(since, address)
+	if err != nil {
+		utils.LogError(err, "error could not get blob submitter stats", 0, map[string]interface{}{"route": r.URL.String()})
+		sendServerErrorResponse(w, r.URL.String(), "error could not get blob submitter stats")
+		return
+	}
+
+	response := make([]types.ApiBlobSubmitterStatsResponse, 0, len(stats))
+	for _, s := range stats {
+		rollup := utils.GetRollupName(s.ToAddress)
+		if rollup == "" {
+			rollup = utils.GetRollupName(s.Sender)
+		}
+		submitter := types.ApiBlobSubmitterStatsResponse{
+			Day:         s.Day.Format("2006-01-02"),
+			Sender:      fmt.Sprintf("0x%x", s.Sender),
+			To:          fmt.Sprintf("0x%x", s.ToAddress),
+			Rollup:      rollup,
+			TxCount:     s.TxCount,
+			BlobCount:   s.BlobCount,
+			BlobGasUsed: s.BlobGasUsed.String(),
+			BlobFee:     s.BlobFee.String(),
+		}
+		if s.MeasuredBlobCount > 0 {
+			fillEfficiency := utils.BlobFillEfficiency(s.NonZeroBytes, s.MeasuredBlobCount)
+			submitter.FillEfficiency = &fillEfficiency
+		}
+		response = append(response, submitter)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
 // ApiEth1Address godoc
 // @Tags Addresses
 // @Summary Get address balances
@@ -342,6 +415,260 @@ func ApiEth1Address(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	delegation, err := eth1data.GetSetCodeDelegation(r.Context(), common.HexToAddress(address))
+	if err != nil {
+		logger.Errorf("error retrieving delegation for address: %v route: %v err: %v", address, r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get delegation for address")
+		return
+	}
+	if delegation != nil {
+		response.DelegatesTo = fmt.Sprintf("0x%x", delegation.Bytes())
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1AddressSetCodeAuthorizations godoc
+// @Tags Addresses
+// @Summary Get EIP-7702 authorizations of an address
+// @Description Returns the EIP-7702 set-code authorizations that were signed by the address or that delegate to it, newest first. Pass the returned page token to get the next page.
+// @Produce json
+// @Param address path string true "provide an Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Param pageToken query string false "page token of the previous response"
+// @Success 200 {object} types.ApiResponse{data=types.ApiEth1AddressSetCodeAuthorizationsResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/authorizations [get]
+func ApiEth1AddressSetCodeAuthorizations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	authorizations, lastKey, err := db.BigtableClient.GetAddressSetCodeAuthorizations(common.FromHex(address), r.URL.Query().Get("pageToken"))
+	if errors.Is(err, db.ErrInvalidPageToken) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid page token")
+		return
+	} else if err != nil {
+		utils.LogError(err, "error could not get set code authorizations for address", 0, map[string]interface{}{"route": r.URL.String()})
+		sendServerErrorResponse(w, r.URL.String(), "error could not get authorizations for address")
+		return
+	}
+
+	response := types.ApiEth1AddressSetCodeAuthorizationsResponse{
+		Authorizations: make([]types.ApiEth1SetCodeAuthorizationResponse, 0, len(authorizations)),
+		PageToken:      lastKey,
+	}
+	for _, a := range authorizations {
+		authority := ""
+		if len(a.Authority) > 0 {
+			authority = fmt.Sprintf("0x%x", a.Authority)
+		}
+		response.Authorizations = append(response.Authorizations, types.ApiEth1SetCodeAuthorizationResponse{
+			TxHash:      fmt.Sprintf("0x%x", a.ParentHash),
+			BlockNumber: a.BlockNumber,
+			Time:        a.Time.AsTime(),
+			Authority:   authority,
+			Delegate:    fmt.Sprintf("0x%x", a.Address),
+			ChainId:     new(big.Int).SetBytes(a.ChainId).String(),
+			Nonce:       a.Nonce,
+			Valid:       a.Valid,
+		})
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1AddressUserOperations godoc
+// @Tags Addresses
+// @Summary Get ERC-4337 user operations of an address
+// @Description Returns the user operations of a smart account or, with role paymaster, the user operations that were sponsored by the address, newest first. Pass the returned page token to get the next page.
+// @Produce json
+// @Param address path string true "provide an Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Param role query string false "sender or paymaster" Enums(sender, paymaster) default(sender)
+// @Param pageToken query string false "page token of the previous response"
+// @Success 200 {object} types.ApiResponse{data=types.ApiEth1AddressUserOperationsResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/userops [get]
+func ApiEth1AddressUserOperations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	var filter db.IndexFilter
+	switch r.URL.Query().Get("role") {
+	case "", "sender":
+		filter = db.FILTER_TIME
+	case "paymaster":
+		filter = db.FILTER_PAYMASTER
+	default:
+		SendBadRequestResponse(w, r.URL.String(), "error invalid role, must be sender or paymaster")
+		return
+	}
+
+	userOps, lastKey, err := db.BigtableClient.GetAddressUserOperations(common.FromHex(address), filter, r.URL.Query().Get("pageToken"))
+	if errors.Is(err, db.ErrInvalidPageToken) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid page token")
+		return
+	} else if err != nil {
+		utils.LogError(err, "error could not get user operations for address", 0, map[string]interface{}{"route": r.URL.String()})
+		sendServerErrorResponse(w, r.URL.String(), "error could not get user operations for address")
+		return
+	}
+
+	response := types.ApiEth1AddressUserOperationsResponse{
+		UserOperations: make([]types.ApiEth1UserOperationResponse, 0, len(userOps)),
+		PageToken:      lastKey,
+	}
+	for _, op := range userOps {
+		userOp := types.ApiEth1UserOperationResponse{
+			UserOpHash:    fmt.Sprintf("0x%x", op.UserOpHash),
+			TxHash:        fmt.Sprintf("0x%x", op.ParentHash),
+			BlockNumber:   op.BlockNumber,
+			Time:          op.Time.AsTime(),
+			EntryPoint:    fmt.Sprintf("0x%x", op.EntryPoint),
+			Sender:        fmt.Sprintf("0x%x", op.Sender),
+			Bundler:       fmt.Sprintf("0x%x", op.Bundler),
+			Nonce:         new(big.Int).SetBytes(op.Nonce).String(),
+			Success:       op.Success,
+			ActualGasCost: new(big.Int).SetBytes(op.ActualGasCost).String(),
+			ActualGasUsed: new(big.Int).SetBytes(op.ActualGasUsed).String(),
+		}
+		// the zero address is emitted if the sender paid for the operation itself
+		if !bytes.Equal(op.Paymaster, common.Address{}.Bytes()) {
+			userOp.Paymaster = fmt.Sprintf("0x%x", op.Paymaster)
+		}
+		if len(op.Factory) > 0 {
+			userOp.Factory = fmt.Sprintf("0x%x", op.Factory)
+		}
+		if len(op.MethodId) > 0 {
+			userOp.MethodId = fmt.Sprintf("0x%x", op.MethodId)
+		}
+		response.UserOperations = append(response.UserOperations, userOp)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1AddressProxy godoc
+// @Tags Addresses
+// @Summary Get the implementation history of a proxy
+// @Description Returns the implementations of an EIP-1967, EIP-1822, transparent, beacon or EIP-1167 minimal proxy, newest first. The implementation of a beacon proxy is the one of its beacon at the time of the update. For the beacon of beacon proxies the implementations it pointed to are returned with the type Upgradeable Beacon. The list is empty if the address is not a known proxy or beacon.
+// @Produce json
+// @Param address path string true "provide an Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiEth1ProxyImplementationResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/proxy [get]
+func ApiEth1AddressProxy(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	history, err := db.BigtableClient.GetProxyImplementationHistory(common.FromHex(address))
+	if err != nil {
+		utils.LogError(err, "error could not get proxy history for address", 0, map[string]interface{}{"route": r.URL.String()})
+		sendServerErrorResponse(w, r.URL.String(), "error could not get proxy history for address")
+		return
+	}
+
+	response := make([]types.ApiEth1ProxyImplementationResponse, 0, len(history))
+	for _, update := range history {
+		implementation := types.ApiEth1ProxyImplementationResponse{
+			TxHash:      fmt.Sprintf("0x%x", update.ParentHash),
+			BlockNumber: update.BlockNumber,
+			Time:        update.Time.AsTime(),
+			Type:        utils.FormatProxyType(update.Type),
+		}
+		if len(update.Implementation) > 0 {
+			implementation.Implementation = fmt.Sprintf("0x%x", update.Implementation)
+		}
+		if len(update.Beacon) > 0 {
+			implementation.Beacon = fmt.Sprintf("0x%x", update.Beacon)
+		}
+		if len(update.Admin) > 0 {
+			implementation.Admin = fmt.Sprintf("0x%x", update.Admin)
+		}
+		response = append(response, implementation)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1AddressApprovals godoc
+// @Tags Addresses
+// @Summary Get active token approvals of an address
+// @Description Returns the erc20 allowances, erc721 token approvals and approvals for all that the address has granted and that are still active, newest first. At most 200 approvals are returned.
+// @Description Approvals are checked against the token contract in the background and the results are cached for an hour, the allowance of verified erc20 approvals is the current on chain allowance. Approvals that have not been checked yet are returned as unverified.
+// @Produce json
+// @Param address path string true "provide an Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiEth1AddressApprovalResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/approvals [get]
+func ApiEth1AddressApprovals(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	approvals, err := db.BigtableClient.GetAddressActiveApprovals(common.FromHex(address))
+	if err != nil {
+		utils.LogError(err, "error could not get approvals for address", 0, map[string]interface{}{"route": r.URL.String()})
+		sendServerErrorResponse(w, r.URL.String(), "error could not get approvals for address")
+		return
+	}
+
+	response := make([]types.ApiEth1AddressApprovalResponse, 0, len(approvals))
+	for _, a := range approvals {
+		approval := types.ApiEth1AddressApprovalResponse{
+			Token:       fmt.Sprintf("0x%x", a.Approval.TokenAddress),
+			Spender:     fmt.Sprintf("0x%x", a.Approval.Spender),
+			TxHash:      fmt.Sprintf("0x%x", a.Approval.ParentHash),
+			BlockNumber: a.Approval.BlockNumber,
+			Time:        a.Approval.Time.AsTime(),
+			Verified:    a.Verified,
+		}
+		switch a.Approval.Type {
+		case types.ApprovalType_ERC20_APPROVAL:
+			approval.Type = "erc20"
+			approval.Allowance = new(big.Int).SetBytes(a.Approval.Value).String()
+		case types.ApprovalType_ERC721_APPROVAL:
+			approval.Type = "erc721"
+			approval.TokenId = new(big.Int).SetBytes(a.Approval.TokenId).String()
+		case types.ApprovalType_APPROVAL_FOR_ALL:
+			approval.Type = "approval_for_all"
+		}
+		response = append(response, approval)
+	}
+
 	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
 }
 
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// eventStreamKeepAliveInterval is the interval in which a comment is sent to idle event stream clients
+// to keep proxies and load balancers from closing the connection
+const eventStreamKeepAliveInterval = time.Second * 15
+
+// ApiEvents godoc
+// @Summary Subscribe to the chain event stream
+// @Description Opens a server-sent events stream pushing head, slot, epoch, finalized_checkpoint and chain_reorg events as they are exported. Each event is sent with its type as the event name and the json encoded event as data. If validators are given, only head and slot events of blocks proposed by one of them are sent, events without a proposer (epoch, finalized_checkpoint and chain_reorg) are not sent.
+// @Tags Events
+// @Produce text/event-stream
+// @Param  topics query string false "Comma separated list of event types to subscribe to, defaults to all event types"
+// @Param  validators query string false "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.StreamEvent
+// @Failure 400 {object} types.ApiResponse
+// @Failure 503 {object} types.ApiResponse
+// @Router /api/v1/events [get]
+func ApiEvents(w http.ResponseWriter, r *http.Request) {
+	if !services.EventsEnabled() {
+		w.Header().Set("Content-Type", "application/json")
+		sendErrorWithCodeResponse(w, r.URL.String(), "the event stream is not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	topics, err := parseStreamEventTopics(r.URL.Query().Get("topics"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	var validators []uint64
+	if param := r.URL.Query().Get("validators"); param != "" {
+		validators, err = parseApiValidatorParamToIndices(param, 100)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			SendBadRequestResponse(w, r.URL.String(), "invalid validators parameter: "+err.Error())
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	// the stream stays open until the client disconnects, so the write timeout of the server must not apply
+	err = rc.SetWriteDeadline(time.Time{})
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logger.Errorf("error disabling write deadline for event stream: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	err = rc.Flush()
+	if err != nil {
+		logger.Errorf("error flushing event stream, streaming is not supported by the response writer: %v", err)
+		return
+	}
+
+	sub := services.SubscribeEvents(topics, validators)
+	defer sub.Close()
+
+	keepAlive := time.NewTicker(eventStreamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keepalive\n\n")
+		case event := <-sub.C:
+			var data []byte
+			data, err = json.Marshal(event)
+			if err != nil {
+				logger.Errorf("error marshalling %v event for event stream: %v", event.Type, err)
+				continue
+			}
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		if err != nil {
+			return
+		}
+		err = rc.Flush()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseStreamEventTopics parses the comma separated topics parameter of the event stream, all event types are
+// returned if no topic is given
+func parseStreamEventTopics(param string) ([]types.StreamEventType, error) {
+	if param == "" {
+		return types.StreamEventTypes, nil
+	}
+
+	topics := []types.StreamEventType{}
+	for _, t := range strings.Split(param, ",") {
+		topic := types.StreamEventType(strings.TrimSpace(t))
+		valid := false
+		for _, eventType := range types.StreamEventTypes {
+			if topic == eventType {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid topic %v, supported topics are %v", topic, types.StreamEventTypes)
+		}
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/ratelimit"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// maxUserApiKeys is the maximum number of api keys a user can have at the same time
+	maxUserApiKeys = 10
+	// maxApiKeyRotationOverlap is the maximum time a rotated api key stays valid
+	maxApiKeyRotationOverlap = time.Hour * 24 * 7
+)
+
+// parseApiKeyForm returns the name, scopes, allowed ips and expiry of an api key submitted by the create form
+func parseApiKeyForm(r *http.Request) (name string, scopes, allowedIPs []string, validUntil time.Time, err error) {
+	name = strings.TrimSpace(r.FormValue("name"))
+	if name == "" || len(name) > 50 {
+		return "", nil, nil, time.Time{}, fmt.Errorf("the name of the key has to be between 1 and 50 characters")
+	}
+
+	for _, scope := range ratelimit.ApiKeyScopes {
+		if r.FormValue(scope) == "on" {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return "", nil, nil, time.Time{}, fmt.Errorf("select at least one scope for the key")
+	}
+
+	for _, ip := range strings.FieldsFunc(r.FormValue("allowed_ips"), func(c rune) bool { return c == ',' || c == '\n' || c == '\r' || c == ' ' }) {
+		allowedIPs = append(allowedIPs, strings.TrimSpace(ip))
+	}
+	if _, err := ratelimit.ParseAllowedIPs(allowedIPs); err != nil {
+		return "", nil, nil, time.Time{}, err
+	}
+
+	if expires := r.FormValue("expires"); expires != "" {
+		day, err := time.Parse("2006-01-02", expires)
+		if err != nil {
+			return "", nil, nil, time.Time{}, fmt.Errorf("invalid expiry date %v", expires)
+		}
+		// the key is valid until the end of the selected day
+		validUntil = day.AddDate(0, 0, 1)
+		if !validUntil.After(time.Now()) {
+			return "", nil, nil, time.Time{}, fmt.Errorf("the expiry date has to be in the future")
+		}
+	}
+	return name, scopes, allowedIPs, validUntil, nil
+}
+
+// publishApiKeysChanged makes all explorer instances reload the api keys
+func publishApiKeysChanged() {
+	err := ratelimit.PublishApiKeysChanged()
+	if err != nil {
+		logger.WithError(err).Errorf("error publishing api key change, the change will be picked up with the next ratelimit update")
+	}
+}
+
+// UserApiKeys renders the page to manage the api keys of the user
+func UserApiKeys(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "user/api_keys.html")
+	var apiKeysTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+	user := getUser(r)
+
+	keys, err := db.GetUserApiKeys(user.UserID)
+	if err != nil {
+		logger.WithError(err).Errorf("error getting api keys of user %v", user.UserID)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := InitPageData(w, r, "user", "/user/api-keys", "API Keys", templateFiles)
+	data.Data = &types.UserApiKeysPageData{
+		Keys:      keys,
+		Scopes:    ratelimit.ApiKeyScopes,
+		MaxKeys:   maxUserApiKeys,
+		CsrfField: csrf.TemplateField(r),
+		Flashes:   utils.GetFlashes(w, r, authSessionName),
+	}
+
+	if handleTemplateError(w, r, "api_keys.go", "UserApiKeys", "", apiKeysTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// UserApiKeyCreate creates a new api key for the user
+func UserApiKeyCreate(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	err := r.ParseForm()
+	if err != nil {
+		utils.LogError(err, "error parsing form", 0)
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong creating your API key, please try again in a bit.")
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+
+	name, scopes, allowedIPs, validUntil, err := parseApiKeyForm(r)
+	if err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: "+html.EscapeString(err.Error()))
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+
+	count, err := db.CountUserActiveApiKeys(user.UserID)
+	if err != nil {
+		logger.WithError(err).Errorf("error counting api keys of user %v", user.UserID)
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong creating your API key, please try again in a bit.")
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+	if count >= maxUserApiKeys {
+		utils.SetFlash(w, r, authSessionName, fmt.Sprintf("Error: You can not have more than %v API keys, please revoke a key first.", maxUserApiKeys))
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+
+	key, err := db.CreateUserApiKey(user.UserID, name, scopes, allowedIPs, validUntil)
+	if err != nil {
+		logger.WithError(err).Errorf("error creating api key for user %v", user.UserID)
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong creating your API key, please try again in a bit.")
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+	publishApiKeysChanged()
+
+	utils.SetFlash(w, r, authSessionName, fmt.Sprintf("The API key %v was created.", html.EscapeString(key.Name)))
+	http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+}
+
+// UserApiKeyRotate replaces an api key of the user with a new key, the old key stays valid for the selected overlap
+func UserApiKeyRotate(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	keyId, err := strconv.ParseUint(mux.Vars(r)["keyID"], 10, 64)
+	if err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: Invalid API key.")
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+
+	overlapHours, err := strconv.ParseInt(r.FormValue("overlap"), 10, 64)
+	overlap := time.Duration(overlapHours) * time.Hour
+	if err != nil || overlap < 0 || overlap > maxApiKeyRotationOverlap {
+		utils.SetFlash(w, r, authSessionName, fmt.Sprintf("Error: The overlap has to be between 0 and %v hours.", int64(maxApiKeyRotationOverlap.Hours())))
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+
+	key, err := db.RotateUserApiKey(user.UserID, keyId, overlap)
+	if errors.Is(err, db.ErrApiKeyNotFound) {
+		utils.SetFlash(w, r, authSessionName, "Error: The API key does not exist or was already rotated or revoked.")
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Errorf("error rotating api key %v of user %v", keyId, user.UserID)
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong rotating your API key, please try again in a bit.")
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+	publishApiKeysChanged()
+
+	msg := fmt.Sprintf("The API key %v was rotated, the old key stopped working.", html.EscapeString(key.Name))
+	if overlap > 0 {
+		msg = fmt.Sprintf("The API key %v was rotated, the old key keeps working for %v hours.", html.EscapeString(key.Name), overlapHours)
+	}
+	utils.SetFlash(w, r, authSessionName, msg)
+	http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+}
+
+// UserApiKeyRevoke invalidates an api key of the user on all explorer instances
+func UserApiKeyRevoke(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	keyId, err := strconv.ParseUint(mux.Vars(r)["keyID"], 10, 64)
+	if err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: Invalid API key.")
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+
+	err = db.RevokeUserApiKey(user.UserID, keyId)
+	if errors.Is(err, db.ErrApiKeyNotFound) {
+		utils.SetFlash(w, r, authSessionName, "Error: The API key does not exist or was already revoked.")
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Errorf("error revoking api key %v of user %v", keyId, user.UserID)
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong revoking your API key, please try again in a bit.")
+		http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+		return
+	}
+	publishApiKeysChanged()
+
+	utils.SetFlash(w, r, authSessionName, "The API key was revoked.")
+	http.Redirect(w, r, "/user/api-keys", http.StatusSeeOther)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// ApiUserNotificationSettings godoc
+// @Summary Get or update your notification delivery settings
+// @Description Returns your time zone and quiet hours. Post the settings to update them, the quiet hours are given as minutes after midnight in your time zone and are disabled if omitted. Email notifications created during the quiet hours are sent as a digest once the quiet hours are over.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param settings body types.UserNotificationSettings false "The new settings, only for POST requests"
+// @Success 200 {object} types.ApiResponse{data=types.UserNotificationSettings}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/notifications/settings [get]
+// @Router /api/v1/user/notifications/settings [post]
+func ApiUserNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	if r.Method == http.MethodPost {
+		settings := &types.UserNotificationSettings{}
+		err := json.NewDecoder(r.Body).Decode(settings)
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), "could not parse body")
+			return
+		}
+		settings.UserID = user.UserID
+
+		err = validateUserNotificationSettings(settings)
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), err.Error())
+			return
+		}
+
+		err = db.SaveUserNotificationSettings(settings)
+		if err != nil {
+			logger.WithError(err).Errorf("error saving notification settings for user %v", user.UserID)
+			sendServerErrorResponse(w, r.URL.String(), "could not save settings")
+			return
+		}
+	}
+
+	settings, err := db.GetUserNotificationSettings([]uint64{user.UserID})
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving notification settings for user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{settings[user.UserID]})
+}
+
+// ApiUserNotificationsDigest godoc
+// @Summary Set the digest mode of your subscriptions
+// @Description Sets whether the email notifications of your subscriptions of an event are sent immediately or summarized in an hourly or daily digest. If no event filter is given, all subscriptions of the event are updated.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param digest body object{event_name=string,event_filter=string,digest_mode=string} true "The event and the digest mode (immediate, hourly or daily)"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/notifications/digest [post]
+func ApiUserNotificationsDigest(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	req := struct {
+		EventName   string `json:"event_name"`
+		EventFilter string `json:"event_filter"`
+		DigestMode  string `json:"digest_mode"`
+	}{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse body")
+		return
+	}
+
+	mode, err := types.NotificationDigestModeFromString(req.DigestMode)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid digest mode, it has to be one of immediate, hourly or daily")
+		return
+	}
+
+	eventName, err := types.EventNameFromString(strings.TrimPrefix(req.EventName, utils.GetNetwork()+":"))
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid event name provided")
+		return
+	}
+
+	updated, err := db.UpdateSubscriptionsDigestMode(user.UserID, utils.GetNetwork()+":"+string(eventName), strings.Replace(req.EventFilter, "0x", "", -1), mode)
+	if err != nil {
+		logger.WithError(err).Errorf("error updating digest mode for user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not update subscriptions")
+		return
+	}
+	if updated == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no matching subscription found")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
+
+// validateUserNotificationSettings checks the time zone and quiet hours of the settings
+func validateUserNotificationSettings(settings *types.UserNotificationSettings) error {
+	if settings.TimeZone == "" {
+		settings.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(settings.TimeZone); err != nil {
+		return fmt.Errorf("unknown time zone %v", settings.TimeZone)
+	}
+	if (settings.QuietHoursStart == nil) != (settings.QuietHoursEnd == nil) {
+		return errors.New("both the start and the end of the quiet hours have to be provided")
+	}
+	for _, m := range []*int64{settings.QuietHoursStart, settings.QuietHoursEnd} {
+		if m != nil && (*m < 0 || *m >= 24*60) {
+			return errors.New("the quiet hours have to be given in minutes after midnight between 0 and 1439")
+		}
+	}
+	return nil
+}
+
+// parseQuietHoursTime parses a time of the form 15:04 of the notification settings form into minutes after midnight
+func parseQuietHoursTime(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return nil, err
+	}
+	minutes := int64(t.Hour()*60 + t.Minute())
+	return &minutes, nil
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+)
+
+// parseSlasherQuery returns the limit, offset and the optional validator filter of a slasher api request
+func parseSlasherQuery(r *http.Request) (limit, offset uint64, validator sql.NullInt64, err error) {
+	q := r.URL.Query()
+	limit = 100
+	if q.Get("limit") != "" {
+		limit, err = strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil || limit == 0 || limit > 100 {
+			return 0, 0, validator, errors.New("invalid limit provided, it has to be between 1 and 100")
+		}
+	}
+	if q.Get("offset") != "" {
+		offset, err = strconv.ParseUint(q.Get("offset"), 10, 64)
+		if err != nil {
+			return 0, 0, validator, errors.New("invalid offset provided")
+		}
+	}
+	if q.Get("validator") != "" {
+		index, err := strconv.ParseInt(q.Get("validator"), 10, 32)
+		if err != nil || index < 0 {
+			return 0, 0, validator, errors.New("invalid validator index provided")
+		}
+		validator = sql.NullInt64{Int64: index, Valid: true}
+	}
+	return limit, offset, validator, nil
+}
+
+// ApiSlasherAttesterSlashings godoc
+// @Tags Slasher
+// @Summary Get the attester slashings detected by the slasher
+// @Description Returns the double votes and surround votes the slasher of the explorer detected in the exported attestations, newest first. The slashings are candidates for review and are not necessarily included on chain yet.
+// @Produce  json
+// @Param  validator query int false "Only return slashings of this validator index"
+// @Param  limit query int false "Limit the number of results (default and maximum 100)"
+// @Param  offset query int false "Offset of the results"
+// @Success 200 {object} types.ApiResponse{data=[]types.APISlasherAttesterSlashingResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/slasher/attesterslashings [get]
+func ApiSlasherAttesterSlashings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit, offset, validator, err := parseSlasherQuery(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	rows, err := db.ReaderDb.Query(`
+		SELECT detected_slot, detected_ts, type, validators, attestation1_beaconblockroot, attestation1_index, attestation1_indices, attestation1_signature, attestation1_slot, attestation1_source_epoch, attestation1_source_root, attestation1_target_epoch, attestation1_target_root, attestation2_beaconblockroot, attestation2_index, attestation2_indices, attestation2_signature, attestation2_slot, attestation2_source_epoch, attestation2_source_root, attestation2_target_epoch, attestation2_target_root
+		FROM slasher_attester_slashings
+		WHERE $1::INTEGER IS NULL OR validators @> ARRAY[$1::INTEGER]
+		ORDER BY detected_slot DESC
+		LIMIT $2 OFFSET $3`, validator, limit, offset)
+	if err != nil {
+		logger.WithError(err).Error("error retrieving slasher attester slashings")
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	defer rows.Close()
+
+	returnQueryResultsAsArray(rows, w, r)
+}
+
+// ApiSlasherProposerSlashings godoc
+// @Tags Slasher
+// @Summary Get the proposer slashings detected by the slasher
+// @Description Returns the double proposals the slasher of the explorer detected in the exported blocks, newest first. The slashings are candidates for review and are not necessarily included on chain yet.
+// @Produce  json
+// @Param  validator query int false "Only return slashings of this validator index"
+// @Param  limit query int false "Limit the number of results (default and maximum 100)"
+// @Param  offset query int false "Offset of the results"
+// @Success 200 {object} types.ApiResponse{data=[]types.APISlasherProposerSlashingResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/slasher/proposerslashings [get]
+func ApiSlasherProposerSlashings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit, offset, validator, err := parseSlasherQuery(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	rows, err := db.ReaderDb.Query(`
+		SELECT detected_slot, detected_ts, proposerindex, header1_slot, header1_parentroot, header1_stateroot, header1_bodyroot, header1_signature, header2_slot, header2_parentroot, header2_stateroot, header2_bodyroot, header2_signature
+		FROM slasher_proposer_slashings
+		WHERE $1::INTEGER IS NULL OR proposerindex = $1
+		ORDER BY detected_slot DESC
+		LIMIT $2 OFFSET $3`, validator, limit, offset)
+	if err != nil {
+		logger.WithError(err).Error("error retrieving slasher proposer slashings")
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	defer rows.Close()
+
+	returnQueryResultsAsArray(rows, w, r)
+}
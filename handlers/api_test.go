@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gorilla/mux"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestApiValidatorConsolidationRequests(t *testing.T) {
+	dsn, exists := os.LookupEnv("HANDLERS_TEST_POSTGRES")
+	if !exists {
+		t.Skip()
+	}
+	testDb, err := sqlx.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDb.Close()
+	// the temporary tables only exist on the connection that created them
+	testDb.SetMaxOpenConns(1)
+
+	previous := db.ReaderDb
+	t.Cleanup(func() { db.ReaderDb = previous })
+	db.ReaderDb = testDb
+
+	_, err = testDb.Exec(`
+		CREATE TEMPORARY TABLE validators (validatorindex INT NOT NULL, pubkey BYTEA NOT NULL);
+		CREATE TEMPORARY TABLE blocks_consolidation_requests_v2 (
+			slot_processed INT NOT NULL,
+			block_processed_root BYTEA NOT NULL,
+			index_processed INT NOT NULL,
+			amount_consolidated BIGINT NOT NULL,
+			source_pubkey BYTEA NOT NULL,
+			target_pubkey BYTEA NOT NULL,
+			status TEXT NOT NULL
+		);
+		INSERT INTO validators VALUES (1, '\x01'), (2, '\x02'), (3, '\x03');
+		INSERT INTO blocks_consolidation_requests_v2 VALUES
+			(10, '\xaa', 0, 32, '\x01', '\x03', 'completed'),
+			(11, '\xbb', 1, 32, '\x01', '\x03', 'failed'),
+			(12, '\xcc', 2, 32, '\x03', '\x01', 'completed'),
+			(13, '\xdd', 3, 32, '\x03', '\x01', 'failed'),
+			(14, '\xee', 4, 32, '\x02', '\x03', 'completed');`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		validators string
+		want       []int64
+	}{
+		{name: "source and target", validators: "1", want: []int64{2, 0}},
+		{name: "no requests", validators: "4", want: []int64{}},
+		{name: "multiple validators", validators: "1,2", want: []int64{4, 2, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/v1/validator/"+tt.validators+"/consolidation_requests", nil)
+			r = mux.SetURLVars(r, map[string]string{"indexOrPubkey": tt.validators})
+			w := httptest.NewRecorder()
+			ApiValidatorConsolidationRequests(w, r)
+
+			res := struct {
+				Status string `json:"status"`
+				Data   []struct {
+					RequestIndex int64 `json:"request_index"`
+				} `json:"data"`
+			}{}
+			err := json.Unmarshal(w.Body.Bytes(), &res)
+			if err != nil || res.Status != "OK" {
+				t.Fatalf("got response %v", w.Body.String())
+			}
+			got := []int64{}
+			for _, d := range res.Data {
+				got = append(got, d.RequestIndex)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got requests %v, want the completed requests %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/ratelimit"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+const (
+	// maxApiUsageDays is the maximum number of days of api usage that can be requested
+	maxApiUsageDays = 90
+	// apiUsageTopBlockedRoutes is the number of routes returned in the top 429 routes
+	apiUsageTopBlockedRoutes = 5
+)
+
+// parseApiUsageParams returns the number of days and the interval of the api usage requested by the query params
+func parseApiUsageParams(r *http.Request) (days int, interval string, err error) {
+	q := r.URL.Query()
+	days = 30
+	if d := q.Get("days"); d != "" {
+		days, err = strconv.Atoi(d)
+		if err != nil || days < 1 || days > maxApiUsageDays {
+			return 0, "", fmt.Errorf("invalid days provided, it has to be between 1 and %v", maxApiUsageDays)
+		}
+	}
+	interval = q.Get("interval")
+	if interval == "" {
+		interval = "day"
+	}
+	if interval != "hour" && interval != "day" {
+		return 0, "", fmt.Errorf("invalid interval provided, it has to be hour or day")
+	}
+	return days, interval, nil
+}
+
+// getApiUsage returns the usage of the api keys of a user in the last days aggregated per route and per key and the
+// requests of every key and route per interval
+func getApiUsage(userId uint64, days int, interval string) (*types.ApiUserUsageResponse, []*types.ApiUsage, error) {
+	step := time.Hour * 24
+	if interval == "hour" {
+		step = time.Hour
+	}
+	now := time.Now().UTC()
+	from := now.Truncate(step).Add(step - time.Hour*24*time.Duration(days))
+
+	usage, err := db.GetUserApiUsage(userId, from, interval)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := &types.ApiUserUsageResponse{
+		From:             from,
+		To:               now,
+		Interval:         interval,
+		Routes:           []*types.ApiUsageSeries{},
+		Keys:             []*types.ApiUsageSeries{},
+		TopBlockedRoutes: []*types.ApiUsageBlocked{},
+	}
+
+	routes := map[string]*types.ApiUsageSeries{}
+	keys := map[string]*types.ApiUsageSeries{}
+	add := func(series map[string]*types.ApiUsageSeries, name string, u *types.ApiUsage) {
+		s, ok := series[name]
+		if !ok {
+			s = &types.ApiUsageSeries{Name: name, Data: []*types.ApiUsagePoint{}}
+			series[name] = s
+		}
+		s.Total += u.Count
+		s.Blocked += u.Blocked
+		// the usage is ordered by time, so only the last point can be of the same time
+		ts := u.Ts.Unix()
+		if len(s.Data) > 0 && s.Data[len(s.Data)-1].Ts == ts {
+			s.Data[len(s.Data)-1].Count += u.Count
+			s.Data[len(s.Data)-1].Blocked += u.Blocked
+			return
+		}
+		s.Data = append(s.Data, &types.ApiUsagePoint{Ts: ts, Count: u.Count, Blocked: u.Blocked})
+	}
+	for _, u := range usage {
+		res.Total += u.Count
+		res.Blocked += u.Blocked
+		add(routes, u.Endpoint, u)
+		add(keys, ratelimit.MaskApiKey(u.ApiKey), u)
+	}
+
+	for _, s := range routes {
+		res.Routes = append(res.Routes, s)
+		if s.Blocked > 0 {
+			res.TopBlockedRoutes = append(res.TopBlockedRoutes, &types.ApiUsageBlocked{Route: s.Name, Blocked: s.Blocked})
+		}
+	}
+	for _, s := range keys {
+		res.Keys = append(res.Keys, s)
+	}
+	for _, series := range [][]*types.ApiUsageSeries{res.Routes, res.Keys} {
+		sort.Slice(series, func(i, j int) bool {
+			if series[i].Total != series[j].Total {
+				return series[i].Total > series[j].Total
+			}
+			return series[i].Name < series[j].Name
+		})
+	}
+	sort.Slice(res.TopBlockedRoutes, func(i, j int) bool {
+		if res.TopBlockedRoutes[i].Blocked != res.TopBlockedRoutes[j].Blocked {
+			return res.TopBlockedRoutes[i].Blocked > res.TopBlockedRoutes[j].Blocked
+		}
+		return res.TopBlockedRoutes[i].Route < res.TopBlockedRoutes[j].Route
+	})
+	if len(res.TopBlockedRoutes) > apiUsageTopBlockedRoutes {
+		res.TopBlockedRoutes = res.TopBlockedRoutes[:apiUsageTopBlockedRoutes]
+	}
+
+	res.MonthlyQuotas, err = getApiMonthlyQuotas(userId, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, usage, nil
+}
+
+// getApiMonthlyQuotas returns the monthly limits of a user and the requests of the current month per bucket, buckets
+// without limit and requests are left out except for the default bucket
+func getApiMonthlyQuotas(userId uint64, now time.Time) ([]types.ApiUsageQuota, error) {
+	buckets, err := ratelimit.DBGetUserApiBuckets(int64(userId))
+	if err != nil {
+		return nil, fmt.Errorf("error getting api buckets of user %v: %w", userId, err)
+	}
+	if !utils.SliceContains(buckets, "default") {
+		buckets = append([]string{"default"}, buckets...)
+	}
+
+	quotas := make([]types.ApiUsageQuota, 0, len(buckets))
+	for _, bucket := range buckets {
+		quota, err := getApiMonthlyQuota(userId, bucket, now)
+		if err != nil {
+			return nil, err
+		}
+		if bucket != "default" && quota.Limit == 0 && quota.Used == 0 {
+			continue
+		}
+		quotas = append(quotas, quota)
+	}
+	return quotas, nil
+}
+
+// getApiMonthlyQuota returns the monthly limit of a user in a bucket and the requests of the current month
+func getApiMonthlyQuota(userId uint64, bucket string, now time.Time) (types.ApiUsageQuota, error) {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	quota := types.ApiUsageQuota{
+		Bucket:  bucket,
+		ResetTs: monthStart.AddDate(0, 1, 0).Unix(),
+	}
+
+	rl, err := ratelimit.DBGetUserApiRateLimitForBucket(int64(userId), bucket)
+	if err != nil {
+		return quota, fmt.Errorf("error getting api ratelimit of user %v in bucket %v: %w", userId, bucket, err)
+	}
+	quota.Limit = rl.Month
+
+	counted := false
+	if rl.Month > 0 {
+		// the counter of the rate limiter includes the weights of the routes
+		quota.Used, err = ratelimit.GetMonthlyUsage(int64(userId), bucket)
+		if err != nil {
+			logger.WithError(err).Warnf("error getting monthly usage of user %v in bucket %v from the rate limiter, using the api statistics", userId, bucket)
+		} else {
+			counted = true
+		}
+	}
+	if !counted {
+		quota.Used, err = db.GetUserApiRequestsSince(userId, bucket, monthStart)
+		if err != nil {
+			return quota, err
+		}
+	}
+
+	if rl.Month > 0 {
+		remaining := rl.Month - quota.Used
+		if remaining < 0 {
+			remaining = 0
+		}
+		quota.Remaining = &remaining
+	}
+	return quota, nil
+}
+
+// writeApiUsageCsv writes the requests of every key and route per interval as csv
+func writeApiUsageCsv(w http.ResponseWriter, usage []*types.ApiUsage) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=api-usage-%s.csv", time.Now().UTC().Format("2006-01-02")))
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"time", "api_key", "route", "requests", "blocked"})
+	for _, u := range usage {
+		_ = cw.Write([]string{u.Ts.Format(time.RFC3339), ratelimit.MaskApiKey(u.ApiKey), u.Endpoint, strconv.FormatInt(u.Count, 10), strconv.FormatInt(u.Blocked, 10)})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		logger.WithError(err).Errorf("error writing api usage csv")
+	}
+}
+
+// ApiUserUsage godoc
+// @Summary Get the usage of your API keys
+// @Description Returns the requests of your API keys per route and per key in hourly or daily buckets, the remaining monthly quota and the routes with the most requests that were answered with 429. With format=csv the requests of every key and route are returned as CSV file.
+// @Tags User
+// @Produce json
+// @Param days query int false "Number of days to return, between 1 and 90, defaults to 30"
+// @Param interval query string false "Length of the time buckets, hour or day, defaults to day"
+// @Param format query string false "json or csv, defaults to json"
+// @Success 200 {object} types.ApiResponse{data=types.ApiUserUsageResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/usage [get]
+func ApiUserUsage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	days, interval, err := parseApiUsageParams(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		SendBadRequestResponse(w, r.URL.String(), "invalid format provided, it has to be json or csv")
+		return
+	}
+
+	res, usage, err := getApiUsage(user.UserID, days, interval)
+	if err != nil {
+		logger.WithError(err).Errorf("error getting api usage of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	if format == "csv" {
+		writeApiUsageCsv(w, usage)
+		return
+	}
+	SendOKResponse(j, r.URL.String(), []interface{}{res})
+}
+
+// UserApiUsage renders the api usage page of the user settings
+func UserApiUsage(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "user/api_usage.html")
+	var usageTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+	user := getUser(r)
+
+	days, interval, err := parseApiUsageParams(r)
+	if err != nil {
+		days, interval = 30, "day"
+	}
+
+	usage, _, err := getApiUsage(user.UserID, days, interval)
+	if err != nil {
+		logger.WithError(err).Errorf("error getting api usage of user %v", user.UserID)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := InitPageData(w, r, "user", "/user/api-usage", "API Usage", templateFiles)
+	pageData := &types.UserApiUsagePageData{
+		Usage:    usage,
+		Days:     days,
+		Interval: interval,
+	}
+	for _, quota := range usage.MonthlyQuotas {
+		q := types.UserApiUsageQuota{ApiUsageQuota: quota}
+		if quota.Remaining != nil {
+			q.RemainingCount = *quota.Remaining
+		}
+		if quota.Limit > 0 {
+			q.UsedShare = float64(quota.Used) / float64(quota.Limit)
+		}
+		pageData.Quotas = append(pageData.Quotas, q)
+	}
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "api_usage.go", "UserApiUsage", "", usageTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// UserApiUsageExport sends the api usage of the user as csv file
+func UserApiUsageExport(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	days, interval, err := parseApiUsageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, usage, err := getApiUsage(user.UserID, days, interval)
+	if err != nil {
+		logger.WithError(err).Errorf("error getting api usage of user %v", user.UserID)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writeApiUsageCsv(w, usage)
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/exporter"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+const (
+	// maxValidatorExportValidators is the maximum number of validators of a single export
+	maxValidatorExportValidators = 10000
+	// maxValidatorExportDays is the maximum number of days of a single export
+	maxValidatorExportDays = 366
+	// maxActiveValidatorExports is the number of exports a user can have pending or running at the same time
+	maxActiveValidatorExports = 3
+	// defaultValidatorExportMaxRows is the maximum number of rows of an export if none is configured
+	defaultValidatorExportMaxRows = 50_000_000
+	// validatorExportJobsLimit is the number of exports returned by the export list api
+	validatorExportJobsLimit = 100
+)
+
+// parseValidatorExportRequest validates an export request and returns the job to queue for it, the export ends at
+// lastEpoch at the latest
+func parseValidatorExportRequest(req *types.ApiValidatorExportRequest, lastEpoch uint64) (*types.ValidatorExportJob, error) {
+	fields, err := exporter.ValidatorExportFields(req.Source, req.Fields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source or fields provided: %w", err)
+	}
+	if req.Format != exporter.ValidatorExportFormatCsv && req.Format != exporter.ValidatorExportFormatParquet {
+		return nil, fmt.Errorf("invalid format provided, it has to be csv or parquet")
+	}
+
+	indices, err := parseApiValidatorParamToIndices(strings.ReplaceAll(req.Validators, " ", ""), maxValidatorExportValidators)
+	if err != nil {
+		return nil, err
+	}
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("no or invalid validator indices provided")
+	}
+	validators := make(pq.Int64Array, 0, len(indices))
+	seen := make(map[uint64]bool, len(indices))
+	for _, index := range indices {
+		if !seen[index] {
+			seen[index] = true
+			validators = append(validators, int64(index))
+		}
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start_date provided, it has to be YYYY-MM-DD")
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end_date provided, it has to be YYYY-MM-DD")
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("the end_date has to be after the start_date")
+	}
+	if endDate.Sub(startDate) >= time.Hour*24*maxValidatorExportDays {
+		return nil, fmt.Errorf("an export can cover at most %v days", maxValidatorExportDays)
+	}
+
+	// the end date is inclusive, the export ends with the epoch of the last second of the day
+	startEpoch := uint64(utils.TimeToEpoch(startDate))
+	endEpoch := min(uint64(utils.TimeToEpoch(endDate.AddDate(0, 0, 1).Add(-time.Second))), lastEpoch)
+	if startEpoch > endEpoch {
+		return nil, fmt.Errorf("there is no finalized data for the requested dates yet")
+	}
+
+	maxRows := utils.Config.ValidatorExport.MaxRows
+	if maxRows == 0 {
+		maxRows = defaultValidatorExportMaxRows
+	}
+	if rows := uint64(len(validators)) * (endEpoch - startEpoch + 1); rows > maxRows {
+		return nil, fmt.Errorf("the export would contain %v rows but at most %v rows are allowed, please request fewer validators or days", rows, maxRows)
+	}
+
+	return &types.ValidatorExportJob{
+		Validators: validators,
+		Source:     req.Source,
+		Fields:     fields,
+		Format:     req.Format,
+		StartEpoch: startEpoch,
+		EndEpoch:   endEpoch,
+	}, nil
+}
+
+// setValidatorExportDownloadUrl signs the download link of a finished export
+func setValidatorExportDownloadUrl(ctx context.Context, job *types.ValidatorExportJob) error {
+	if job.Status != types.ValidatorExportFinished {
+		return nil
+	}
+	link, err := exporter.ValidatorExportDownloadUrl(ctx, job)
+	if err != nil {
+		return err
+	}
+	job.DownloadUrl = &link
+	return nil
+}
+
+// ApiUserValidatorExportCreate godoc
+// @Summary Export the balance or income history of validators
+// @Description Queues an export of the balance or income history of up to 10000 validators for up to 366 days to a csv or parquet file. The export runs in the background, you are notified by email when it is finished. Poll /api/v1/user/exports/{jobID} to get the signed download link.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param request body types.ApiValidatorExportRequest true "Validators, source (balance or income), fields, format (csv or parquet) and date range of the export"
+// @Success 200 {object} types.ApiResponse{data=types.ValidatorExportJob}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/exports [post]
+func ApiUserValidatorExportCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	if utils.Config.ValidatorExport.S3.Bucket == "" {
+		sendErrorWithCodeResponse(w, r.URL.String(), "exports are not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	req := &types.ApiValidatorExportRequest{}
+	err := json.NewDecoder(io.LimitReader(r.Body, 1024*1024)).Decode(req)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid request body")
+		return
+	}
+
+	job, err := parseValidatorExportRequest(req, services.LatestFinalizedEpoch())
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	job.UserID = user.UserID
+
+	count, err := db.CountUserActiveValidatorExportJobs(user.UserID)
+	if err != nil {
+		logger.WithError(err).Errorf("error counting validator export jobs of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if count >= maxActiveValidatorExports {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("you can not have more than %v exports running at the same time", maxActiveValidatorExports))
+		return
+	}
+
+	job, err = db.CreateValidatorExportJob(job)
+	if err != nil {
+		logger.WithError(err).Errorf("error creating validator export job for user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not create export")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{job})
+}
+
+// ApiUserValidatorExports godoc
+// @Summary Get your validator exports
+// @Description Returns your 100 most recent validator exports, finished exports contain a signed download link.
+// @Tags User
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]types.ValidatorExportJob}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/exports [get]
+func ApiUserValidatorExports(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	jobs, err := db.GetUserValidatorExportJobs(user.UserID, validatorExportJobsLimit)
+	if err != nil {
+		logger.WithError(err).Errorf("error getting validator export jobs of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	for _, job := range jobs {
+		err = setValidatorExportDownloadUrl(r.Context(), job)
+		if err != nil {
+			logger.WithError(err).Errorf("error signing download link of validator export job %v", job.ID)
+			sendServerErrorResponse(w, r.URL.String(), "could not sign download link")
+			return
+		}
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{jobs})
+}
+
+// ApiUserValidatorExport godoc
+// @Summary Get a validator export
+// @Description Returns the status of a validator export, finished exports contain a signed download link.
+// @Tags User
+// @Produce json
+// @Param jobID path string true "Id of the export"
+// @Success 200 {object} types.ApiResponse{data=types.ValidatorExportJob}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/exports/{jobID} [get]
+func ApiUserValidatorExport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	jobID, err := strconv.ParseUint(mux.Vars(r)["jobID"], 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid export id provided")
+		return
+	}
+
+	job, err := db.GetUserValidatorExportJob(user.UserID, jobID)
+	if errors.Is(err, db.ErrValidatorExportJobNotFound) {
+		sendErrorWithCodeResponse(w, r.URL.String(), "export not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Errorf("error getting validator export job %v of user %v", jobID, user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	err = setValidatorExportDownloadUrl(r.Context(), job)
+	if err != nil {
+		logger.WithError(err).Errorf("error signing download link of validator export job %v", job.ID)
+		sendServerErrorResponse(w, r.URL.String(), "could not sign download link")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{job})
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+func TestParseValidatorExportRequest(t *testing.T) {
+	config := utils.Config
+	t.Cleanup(func() { utils.Config = config })
+	utils.Config = &types.Config{}
+	// genesis at 2024-01-01 with 32 slots of 12 seconds, so 225 epochs per day
+	utils.Config.Chain.GenesisTimestamp = uint64(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	utils.Config.Chain.ClConfig.SecondsPerSlot = 12
+	utils.Config.Chain.ClConfig.SlotsPerEpoch = 32
+	utils.Config.ValidatorExport.MaxRows = 1_000_000
+
+	lastEpoch := uint64(225 * 100)
+	valid := types.ApiValidatorExportRequest{
+		Validators: "1,2,3",
+		Source:     "balance",
+		Format:     "csv",
+		StartDate:  "2024-01-02",
+		EndDate:    "2024-01-03",
+	}
+
+	tests := []struct {
+		name           string
+		modify         func(r *types.ApiValidatorExportRequest)
+		wantErr        string
+		wantValidators []int64
+		wantFields     []string
+		wantStart      uint64
+		wantEnd        uint64
+	}{
+		{
+			name:           "all fields",
+			modify:         func(r *types.ApiValidatorExportRequest) {},
+			wantValidators: []int64{1, 2, 3},
+			wantFields:     []string{"validator_index", "epoch", "ts", "balance", "effective_balance"},
+			wantStart:      225,
+			wantEnd:        674,
+		},
+		{
+			name: "selected fields and duplicate validators",
+			modify: func(r *types.ApiValidatorExportRequest) {
+				r.Validators = "3, 1,3"
+				r.Source = "income"
+				r.Format = "parquet"
+				r.Fields = []string{"tx_fee_reward_wei", "attestation_head_reward"}
+			},
+			wantValidators: []int64{3, 1},
+			wantFields:     []string{"validator_index", "epoch", "attestation_head_reward", "tx_fee_reward_wei"},
+			wantStart:      225,
+			wantEnd:        674,
+		},
+		{
+			name: "end is capped at the last finalized epoch",
+			modify: func(r *types.ApiValidatorExportRequest) {
+				r.StartDate = "2024-04-09"
+				r.EndDate = "2024-05-01"
+			},
+			wantValidators: []int64{1, 2, 3},
+			wantFields:     []string{"validator_index", "epoch", "ts", "balance", "effective_balance"},
+			wantStart:      225 * 99,
+			wantEnd:        225 * 100,
+		},
+		{name: "unknown source", modify: func(r *types.ApiValidatorExportRequest) { r.Source = "rewards" }, wantErr: "invalid source"},
+		{name: "unknown field", modify: func(r *types.ApiValidatorExportRequest) { r.Fields = []string{"income"} }, wantErr: "invalid source or fields"},
+		{name: "unknown format", modify: func(r *types.ApiValidatorExportRequest) { r.Format = "xlsx" }, wantErr: "invalid format"},
+		{name: "invalid validators", modify: func(r *types.ApiValidatorExportRequest) { r.Validators = "1,abc" }, wantErr: "invalid"},
+		{name: "invalid start date", modify: func(r *types.ApiValidatorExportRequest) { r.StartDate = "02.01.2024" }, wantErr: "invalid start_date"},
+		{name: "end before start", modify: func(r *types.ApiValidatorExportRequest) { r.EndDate = "2024-01-01" }, wantErr: "has to be after"},
+		{name: "more than a year", modify: func(r *types.ApiValidatorExportRequest) { r.EndDate = "2025-01-02" }, wantErr: "at most 366 days"},
+		{
+			name: "no finalized data",
+			modify: func(r *types.ApiValidatorExportRequest) {
+				r.StartDate = "2024-05-01"
+				r.EndDate = "2024-05-02"
+			},
+			wantErr: "no finalized data",
+		},
+		{
+			name: "too many rows",
+			modify: func(r *types.ApiValidatorExportRequest) {
+				r.Validators = "1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32,33,34,35,36,37,38,39,40,41,42,43,44,45"
+				r.StartDate = "2024-01-01"
+				r.EndDate = "2024-04-09"
+			},
+			wantErr: "at most 1000000 rows",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			job, err := parseValidatorExportRequest(&req, lastEpoch)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(job.Fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("got fields %v, want %v", job.Fields, tt.wantFields)
+			}
+			if len(job.Validators) != len(tt.wantValidators) {
+				t.Fatalf("got validators %v, want %v", job.Validators, tt.wantValidators)
+			}
+			for i := range job.Validators {
+				if job.Validators[i] != tt.wantValidators[i] {
+					t.Errorf("got validators %v, want %v", job.Validators, tt.wantValidators)
+				}
+			}
+			if job.StartEpoch != tt.wantStart || job.EndEpoch != tt.wantEnd {
+				t.Errorf("got epochs %v-%v, want %v-%v", job.StartEpoch, job.EndEpoch, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// maxWebhookDeliveriesLimit is the maximum number of deliveries returned by the webhook delivery log api
+const maxWebhookDeliveriesLimit = 100
+
+// generateWebhookSecret creates the secret used to sign the payloads sent to a webhook
+func generateWebhookSecret() (string, error) {
+	b, err := utils.GenerateRandomBytesSecure(32)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// regenerateWebhookSecret replaces the signing secret of a webhook of the user and returns the new secret,
+// sql.ErrNoRows is returned if the user has no such webhook
+func regenerateWebhookSecret(userID, webhookID uint64) (string, error) {
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return "", fmt.Errorf("error generating webhook secret: %w", err)
+	}
+	res, err := db.FrontendWriterDB.Exec(`UPDATE users_webhooks SET secret = $1 WHERE id = $2 AND user_id = $3`, secret, webhookID, userID)
+	if err != nil {
+		return "", fmt.Errorf("error updating webhook secret: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return "", fmt.Errorf("error updating webhook secret: %w", err)
+	}
+	if rows == 0 {
+		return "", sql.ErrNoRows
+	}
+	return secret, nil
+}
+
+// matrixRoomIDRegex matches room ids like !abcdef:matrix.org, aliases are not supported as they can change
+var matrixRoomIDRegex = regexp.MustCompile(`^![^:\s]+:[^\s]+$`)
+
+// generateWebhookVerificationToken creates the token used to link a telegram chat or verify a matrix room, telegram
+// allows at most 64 characters in deep links
+func generateWebhookVerificationToken() (string, error) {
+	b, err := utils.GenerateRandomBytesSecure(16)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// webhookVerification returns the verification state of a webhook whose destination or target changed from the
+// current one. Telegram chats can not be entered by the user, they are linked by sending the token to the bot.
+// Matrix rooms have to be verified again if the room changed. Other destinations do not need to be verified.
+func webhookVerification(current *types.UserWebhook, destination, target string) (url string, token sql.NullString, verifiedAt sql.NullTime, err error) {
+	switch destination {
+	case string(types.TelegramNotificationChannel):
+		if current != nil && current.Destination.String == destination {
+			return current.Url, current.VerificationToken, current.VerifiedAt, nil
+		}
+		target = ""
+	case string(types.MatrixNotificationChannel):
+		if current != nil && current.Destination.String == destination && current.Url == target {
+			return current.Url, current.VerificationToken, current.VerifiedAt, nil
+		}
+	default:
+		return target, sql.NullString{}, sql.NullTime{}, nil
+	}
+
+	t, err := generateWebhookVerificationToken()
+	if err != nil {
+		return "", sql.NullString{}, sql.NullTime{}, err
+	}
+	return target, sql.NullString{String: t, Valid: true}, sql.NullTime{}, nil
+}
+
+// webhookVerificationHTML renders how an unverified telegram chat or matrix room is verified, telegram chats are linked
+// by starting the bot with the token, matrix rooms by posting the code and letting the bot check it
+func webhookVerificationHTML(wh *types.UserWebhook, hostname string, csrfField template.HTML) template.HTML {
+	token := wh.VerificationToken.String
+	if wh.Destination.String == string(types.TelegramNotificationChannel) {
+		return template.HTML(fmt.Sprintf(`<span class="text-warning mr-2">Not linked</span><a href="%v" target="_blank" rel="noopener noreferrer">Private chat</a> · <a href="%v" target="_blank" rel="noopener noreferrer">Group</a>`,
+			template.HTMLEscapeString(services.TelegramLinkUrl(token, false)), template.HTMLEscapeString(services.TelegramLinkUrl(token, true))))
+	}
+	return template.HTML(fmt.Sprintf(`<span>%v</span><div><span class="text-warning mr-2">Not verified</span>Post <kbd>%v</kbd> in the room, then <form class="d-inline" action="/user/webhooks/%v/verify" method="post">%v<button type="submit" class="btn btn-link p-0 align-baseline">verify</button></form></div>`,
+		hostname, token, wh.ID, csrfField))
+}
+
+// webhookDestinations returns the platforms a webhook can be configured for, telegram and matrix are only offered if
+// the bot account is configured
+func webhookDestinations() []types.WebhookDestination {
+	destinations := []types.WebhookDestination{
+		{Value: "webhook", Label: "Webhook", Placeholder: "https://..."},
+		{Value: string(types.WebhookDiscordNotificationChannel), Label: "Discord", Placeholder: "https://discord.com/api/webhooks/..."},
+		{Value: string(types.WebhookSlackNotificationChannel), Label: "Slack", Placeholder: "https://hooks.slack.com/services/..."},
+	}
+	if utils.Config.Notifications.Telegram.BotToken != "" && utils.Config.Notifications.Telegram.BotUsername != "" {
+		destinations = append(destinations, types.WebhookDestination{Value: string(types.TelegramNotificationChannel), Label: "Telegram", Placeholder: "The chat is linked via Telegram after adding the webhook"})
+	}
+	if utils.Config.Notifications.Matrix.HomeserverUrl != "" && utils.Config.Notifications.Matrix.AccessToken != "" {
+		destinations = append(destinations, types.WebhookDestination{Value: string(types.MatrixNotificationChannel), Label: "Matrix", Placeholder: "Room id, e.g. !abcdef:matrix.org"})
+	}
+	return destinations
+}
+
+// webhookDestinationLabel returns the display name of a webhook destination
+func webhookDestinationLabel(destination string) string {
+	for _, d := range webhookDestinations() {
+		if d.Value == destination {
+			return d.Label
+		}
+	}
+	return destination
+}
+
+// webhookDestinationFromForm returns the destination selected in the webhook form, the discord checkbox of older
+// versions of the form is still supported
+func webhookDestinationFromForm(r *http.Request) string {
+	destination := r.FormValue("destination")
+	if destination == "" && r.FormValue("discord") == "on" {
+		return string(types.WebhookDiscordNotificationChannel)
+	}
+	for _, d := range webhookDestinations() {
+		if d.Value == destination {
+			return destination
+		}
+	}
+	return "webhook"
+}
+
+// validateWebhookTarget checks the url of a webhook or the room id of matrix destinations, telegram chats are linked
+// via the bot instead
+func validateWebhookTarget(destination, target string) error {
+	switch destination {
+	case string(types.TelegramNotificationChannel):
+		return nil
+	case string(types.MatrixNotificationChannel):
+		if !matrixRoomIDRegex.MatchString(target) {
+			return errors.New("The Matrix room id provided is invalid.")
+		}
+	case string(types.WebhookSlackNotificationChannel):
+		u, err := url.Parse(target)
+		if err != nil || !utils.IsValidUrl(target) || u.Scheme != "https" || u.Hostname() != "hooks.slack.com" {
+			return errors.New("The Slack webhook URL provided is invalid.")
+		}
+	default:
+		if !utils.IsValidUrl(target) {
+			return errors.New("The URL provided is invalid.")
+		}
+	}
+	return nil
+}
+
+// ApiUserWebhookDeliveries godoc
+// @Summary Get the delivery log of a webhook
+// @Description Returns the most recent delivery attempts of one of your webhooks including the status code, latency and a snippet of the response of the receiver.
+// @Tags User
+// @Produce json
+// @Param webhookID path string true "Id of the webhook"
+// @Param limit query int false "Number of deliveries to return, defaults to and may not exceed 100"
+// @Success 200 {object} types.ApiResponse{data=[]types.UserWebhookDelivery}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/webhooks/{webhookID}/deliveries [get]
+func ApiUserWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	webhookID, err := strconv.ParseUint(mux.Vars(r)["webhookID"], 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid webhook id provided")
+		return
+	}
+
+	limit := uint64(maxWebhookDeliveriesLimit)
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.ParseUint(l, 10, 64)
+		if err != nil || limit == 0 || limit > maxWebhookDeliveriesLimit {
+			SendBadRequestResponse(w, r.URL.String(), "invalid limit provided, it has to be between 1 and 100")
+			return
+		}
+	}
+
+	deliveries := []*types.UserWebhookDelivery{}
+	err = db.FrontendReaderDB.Select(&deliveries, `
+		SELECT id, webhook_id, notification_id, ts, attempt, event_name, request, status_code, latency_ms, response_snippet, error
+		FROM users_webhooks_deliveries
+		WHERE webhook_id = $1 AND user_id = $2
+		ORDER BY ts DESC
+		LIMIT $3`, webhookID, user.UserID, limit)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving deliveries of webhook %v for user %v", webhookID, user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{deliveries})
+}
+
+// ApiUserWebhookRedeliver godoc
+// @Summary Redeliver a webhook notification
+// @Description Queues the notification of a previous delivery to be sent again to the current url and destination of the webhook, e.g. as Discord message if the webhook has been switched to Discord.
+// @Tags User
+// @Produce json
+// @Param webhookID path string true "Id of the webhook"
+// @Param deliveryID path string true "Id of the delivery to send again"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver [post]
+func ApiUserWebhookRedeliver(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	vars := mux.Vars(r)
+	webhookID, err := strconv.ParseUint(vars["webhookID"], 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid webhook id provided")
+		return
+	}
+	deliveryID, err := strconv.ParseUint(vars["deliveryID"], 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid delivery id provided")
+		return
+	}
+
+	var request []byte
+	err = db.FrontendReaderDB.Get(&request, `SELECT request FROM users_webhooks_deliveries WHERE id = $1 AND webhook_id = $2 AND user_id = $3`, deliveryID, webhookID, user.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		SendBadRequestResponse(w, r.URL.String(), "delivery not found")
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving delivery %v of webhook %v for user %v", deliveryID, webhookID, user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	var content types.TransitWebhookContent
+	err = json.Unmarshal(request, &content)
+	if err != nil {
+		logger.WithError(err).Errorf("error parsing request of delivery %v", deliveryID)
+		sendServerErrorResponse(w, r.URL.String(), "could not parse the delivered notification")
+		return
+	}
+
+	// the notification is sent with the current configuration of the webhook
+	err = db.FrontendReaderDB.Get(&content.Webhook, `SELECT id, user_id, url, retries, last_sent, event_names, destination, verification_token, verified_at FROM users_webhooks WHERE id = $1 AND user_id = $2`, webhookID, user.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		SendBadRequestResponse(w, r.URL.String(), "webhook not found")
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving webhook %v for user %v", webhookID, user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	// the event is queued on the channel of the current destination, e.g. as discord embed
+	err = services.QueueWebhookRedelivery(db.FrontendWriterDB, content.Webhook, content.Event)
+	if err != nil {
+		logger.WithError(err).Errorf("error queuing redelivery of delivery %v", deliveryID)
+		sendServerErrorResponse(w, r.URL.String(), "could not queue the redelivery")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
+
+// ApiUserWebhookRegenerateSecret godoc
+// @Summary Regenerate the signing secret of a webhook
+// @Description Replaces the signing secret of one of your webhooks and returns the new secret. Requests are signed with the new secret from now on, including retries of earlier notifications.
+// @Tags User
+// @Produce json
+// @Param webhookID path string true "Id of the webhook"
+// @Success 200 {object} types.ApiResponse{data=types.ApiWebhookSecretResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/webhooks/{webhookID}/secret [post]
+func ApiUserWebhookRegenerateSecret(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	webhookID, err := strconv.ParseUint(mux.Vars(r)["webhookID"], 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid webhook id provided")
+		return
+	}
+
+	secret, err := regenerateWebhookSecret(user.UserID, webhookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		SendBadRequestResponse(w, r.URL.String(), "webhook not found")
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Errorf("error regenerating secret of webhook %v for user %v", webhookID, user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not regenerate the secret")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{types.ApiWebhookSecretResponse{Secret: secret}})
+}
+
+// telegramUpdate is an update of the telegram bot, only the messages that can contain a /start command are used
+type telegramUpdate struct {
+	Message     *telegramMessage `json:"message"`
+	ChannelPost *telegramMessage `json:"channel_post"`
+}
+
+type telegramMessage struct {
+	Text string `json:"text"`
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+}
+
+// TelegramUpdates receives the updates of the telegram bot via its webhook and links the chat of a /start command to
+// the telegram webhook with the token of the command. The token is only known to the user who added the webhook and
+// is passed to the chat by the deep link, so only chats the user can message the bot from can be linked.
+func TelegramUpdates(w http.ResponseWriter, r *http.Request) {
+	secret := utils.Config.Notifications.Telegram.WebhookSecret
+	if secret == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Telegram-Bot-Api-Secret-Token")), []byte(secret)) != 1 {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	// telegram retries updates that are not answered with status 200, so invalid updates are acknowledged
+	update := &telegramUpdate{}
+	err := json.NewDecoder(io.LimitReader(r.Body, 1024*1024)).Decode(update)
+	if err != nil {
+		logger.WithError(err).Warnf("error decoding telegram update")
+		return
+	}
+	msg := update.Message
+	if msg == nil {
+		msg = update.ChannelPost
+	}
+	if msg == nil {
+		return
+	}
+	token, ok := services.ParseTelegramStartToken(msg.Text)
+	if !ok {
+		return
+	}
+
+	chatID := strconv.FormatInt(msg.Chat.ID, 10)
+	res, err := db.FrontendWriterDB.Exec(`UPDATE users_webhooks SET url = $1, verified_at = now(), verification_token = NULL WHERE verification_token = $2 AND destination = $3`, chatID, token, types.TelegramNotificationChannel)
+	if err != nil {
+		logger.WithError(err).Errorf("error linking telegram chat %v", chatID)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	rows, err := res.RowsAffected()
+	if err != nil || rows == 0 {
+		return
+	}
+
+	err = services.SendTelegramText(r.Context(), chatID, fmt.Sprintf("This chat is now linked to your %v account and will receive your notifications.", utils.Config.Frontend.SiteName))
+	if err != nil {
+		logger.WithError(err).Warnf("error confirming link of telegram chat %v", chatID)
+	}
+}
@@ -57,15 +57,19 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	g := new(errgroup.Group)
-	g.SetLimit(11)
+	g.SetLimit(15)
 
 	isContract := false
 	txns := &types.DataTableResponse{}
 	blobs := &types.DataTableResponse{}
+	authorizations := &types.DataTableResponse{}
+	var delegation *common.Address
+	userOps := &types.DataTableResponse{}
 	internal := &types.DataTableResponse{}
 	erc20 := &types.DataTableResponse{}
 	erc721 := &types.DataTableResponse{}
 	erc1155 := &types.DataTableResponse{}
+	approvals := &types.DataTableResponse{}
 	blocksMined := &types.DataTableResponse{}
 	unclesMined := &types.DataTableResponse{}
 	withdrawals := &types.DataTableResponse{}
@@ -98,6 +102,33 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		var err error
+		authorizations, err = db.BigtableClient.GetAddressSetCodeAuthorizationsTableData(addressBytes, "")
+		if err != nil {
+			return fmt.Errorf("GetAddressSetCodeAuthorizationsTableData: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		var err error
+		delegation, err = eth1data.GetSetCodeDelegation(ctx, common.BytesToAddress(addressBytes))
+		if err != nil {
+			return fmt.Errorf("GetSetCodeDelegation: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		userOps, err = db.BigtableClient.GetAddressUserOperationsTableData(addressBytes, "")
+		if err != nil {
+			return fmt.Errorf("GetAddressUserOperationsTableData: %w", err)
+		}
+		return nil
+	})
 	g.Go(func() error {
 		var err error
 		internal, err = db.BigtableClient.GetAddressInternalTableData(addressBytes, "")
@@ -130,6 +161,14 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		var err error
+		approvals, err = db.BigtableClient.GetAddressApprovalsTableData(addressBytes)
+		if err != nil {
+			return fmt.Errorf("GetAddressApprovalsTableData: %w", err)
+		}
+		return nil
+	})
 	g.Go(func() error {
 		var err error
 		blocksMined, err = db.BigtableClient.GetAddressBlocksMinedTableData(address, "")
@@ -185,6 +224,22 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 			Data: blobs,
 		})
 	}
+	if authorizations != nil && len(authorizations.Data) != 0 {
+		tabs = append(tabs, types.Eth1AddressPageTabs{
+			Id:   "authorizations",
+			Href: "#authorizations",
+			Text: "Authorizations",
+			Data: authorizations,
+		})
+	}
+	if userOps != nil && len(userOps.Data) != 0 {
+		tabs = append(tabs, types.Eth1AddressPageTabs{
+			Id:   "userOps",
+			Href: "#userOps",
+			Text: "User Operations",
+			Data: userOps,
+		})
+	}
 	if internal != nil && len(internal.Data) != 0 {
 		tabs = append(tabs, types.Eth1AddressPageTabs{
 			Id:   "internalTxns",
@@ -233,6 +288,14 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 			Data: erc1155,
 		})
 	}
+	if approvals != nil && len(approvals.Data) != 0 {
+		tabs = append(tabs, types.Eth1AddressPageTabs{
+			Id:   "approvals",
+			Href: "#approvals",
+			Text: "Token Approvals",
+			Data: approvals,
+		})
+	}
 	if withdrawals != nil && len(withdrawals.Data) != 0 {
 		tabs = append(tabs, types.Eth1AddressPageTabs{
 			Id:   "withdrawals",
@@ -242,25 +305,34 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	delegatesTo := template.HTML("")
+	if delegation != nil {
+		delegatesTo = utils.FormatAddress(delegation.Bytes(), nil, "", false, true, true)
+	}
+
 	data.Data = types.Eth1AddressPageData{
-		Address:            address,
-		EnsName:            ensData.Domain,
-		IsContract:         isContract,
-		QRCode:             pngStr,
-		QRCodeInverse:      pngStrInverse,
-		Metadata:           metadata,
-		WithdrawalsSummary: withdrawalSummary,
-		TransactionsTable:  txns,
-		BlobTxnsTable:      blobs,
-		InternalTxnsTable:  internal,
-		Erc20Table:         erc20,
-		Erc721Table:        erc721,
-		Erc1155Table:       erc1155,
-		WithdrawalsTable:   withdrawals,
-		BlocksMinedTable:   blocksMined,
-		UnclesMinedTable:   unclesMined,
-		EtherValue:         utils.FormatPricedValue(utils.WeiBytesToEther(metadata.EthBalance.Balance), utils.Config.Frontend.ElCurrency, currency),
-		Tabs:               tabs,
+		Address:             address,
+		EnsName:             ensData.Domain,
+		IsContract:          isContract,
+		QRCode:              pngStr,
+		QRCodeInverse:       pngStrInverse,
+		Metadata:            metadata,
+		WithdrawalsSummary:  withdrawalSummary,
+		TransactionsTable:   txns,
+		BlobTxnsTable:       blobs,
+		AuthorizationsTable: authorizations,
+		DelegatesTo:         delegatesTo,
+		UserOpsTable:        userOps,
+		InternalTxnsTable:   internal,
+		Erc20Table:          erc20,
+		Erc721Table:         erc721,
+		Erc1155Table:        erc1155,
+		ApprovalsTable:      approvals,
+		WithdrawalsTable:    withdrawals,
+		BlocksMinedTable:    blocksMined,
+		UnclesMinedTable:    unclesMined,
+		EtherValue:          utils.FormatPricedValue(utils.WeiBytesToEther(metadata.EthBalance.Balance), utils.Config.Frontend.ElCurrency, currency),
+		Tabs:                tabs,
 	}
 
 	if handleTemplateError(w, r, "eth1Account.go", "Eth1Address", "Done", eth1AddressTemplate.ExecuteTemplate(w, "layout", data)) != nil {
@@ -406,6 +478,60 @@ func Eth1AddressBlobTransactions(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func Eth1AddressSetCodeAuthorizations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	address, err := lowerAddressFromRequest(w, r)
+	if err != nil {
+		return
+	}
+	addressBytes := common.FromHex(address)
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	pageToken := q.Get("pageToken")
+	data, err := db.BigtableClient.GetAddressSetCodeAuthorizationsTableData(addressBytes, pageToken)
+	if err != nil {
+		utils.LogError(err, "error getting eth1 set code authorizations table data", 0, errFields)
+	}
+
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		utils.LogError(err, "error enconding json response", 0, errFields)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+func Eth1AddressUserOperations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	address, err := lowerAddressFromRequest(w, r)
+	if err != nil {
+		return
+	}
+	addressBytes := common.FromHex(address)
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	pageToken := q.Get("pageToken")
+	data, err := db.BigtableClient.GetAddressUserOperationsTableData(addressBytes, pageToken)
+	if err != nil {
+		utils.LogError(err, "error getting eth1 user operations table data", 0, errFields)
+	}
+
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		utils.LogError(err, "error enconding json response", 0, errFields)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
 func Eth1AddressInternalTransactions(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
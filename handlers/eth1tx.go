@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"math/big"
 	"net/http"
 	"strings"
 
@@ -18,6 +19,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/gorilla/mux"
 	"github.com/shopspring/decimal"
 	"golang.org/x/text/language"
@@ -27,8 +29,8 @@ import (
 // Tx will show the tx using a go template
 func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 	txNotFoundTemplateFiles := append(layoutTemplateFiles, "eth1txnotfound.html")
-	txTemplateFiles := append(layoutTemplateFiles, "eth1tx.html")
-	mempoolTxTemplateFiles := append(layoutTemplateFiles, "mempoolTx.html")
+	txTemplateFiles := append(layoutTemplateFiles, "eth1tx.html", "components/mempoolHistory.html")
+	mempoolTxTemplateFiles := append(layoutTemplateFiles, "mempoolTx.html", "components/mempoolHistory.html")
 	var txNotFoundTemplate = templates.GetTemplate(txNotFoundTemplateFiles...)
 	var txTemplate = templates.GetTemplate(txTemplateFiles...)
 	var mempoolTxTemplate = templates.GetTemplate(mempoolTxTemplateFiles...)
@@ -51,13 +53,21 @@ func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 		data = InitPageData(w, r, "blockchain", path, title, txNotFoundTemplateFiles)
 		txTemplate = txNotFoundTemplate
 	} else {
+		mempoolHistory, historyErr := db.GetMempoolTransactionHistory(txHash)
+		if historyErr != nil {
+			utils.LogError(historyErr, "error getting mempool history of transaction", 0, errFields)
+		}
 		txData, err := eth1data.GetEth1Transaction(common.BytesToHash(txHash), "ETH")
 		if err != nil {
 			mempool := services.LatestMempoolTransactions()
 			mempoolTx := mempool.FindTxByHash(txHashString)
+			if mempoolTx == nil && mempoolHistory != nil && mempoolHistory.Transaction.Status != types.MempoolTxMined {
+				// the transaction has left the mempool without being mined, show it as it was last seen
+				mempoolTx = mempoolHistoryToRawTransaction(mempoolHistory.Transaction)
+			}
 			if mempoolTx != nil {
 				data = InitPageData(w, r, "blockchain", path, title, mempoolTxTemplateFiles)
-				mempoolPageData := &types.MempoolTxPageData{RawMempoolTransaction: *mempoolTx}
+				mempoolPageData := &types.MempoolTxPageData{RawMempoolTransaction: *mempoolTx, History: mempoolHistory}
 				txTemplate = mempoolTxTemplate
 				if mempoolTx.To == nil {
 					mempoolPageData.IsContractCreation = true
@@ -109,6 +119,8 @@ func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 
+			txData.MempoolHistory = mempoolHistory
+
 			data = InitPageData(w, r, "blockchain", path, title, txTemplateFiles)
 			data.Data = txData
 		}
@@ -126,6 +138,28 @@ func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// mempoolHistoryToRawTransaction returns a recorded mempool transaction in the format of the mempool updater
+func mempoolHistoryToRawTransaction(tx *types.MempoolHistoryTransaction) *types.RawMempoolTransaction {
+	from := common.BytesToAddress(tx.Sender)
+	raw := &types.RawMempoolTransaction{
+		Hash:     common.BytesToHash(tx.Hash),
+		From:     &from,
+		Value:    (*hexutil.Big)(tx.Value.BigInt()),
+		Gas:      (*hexutil.Big)(new(big.Int).SetUint64(tx.Gas)),
+		GasPrice: (*hexutil.Big)(tx.GasPrice.BigInt()),
+		Nonce:    (*hexutil.Big)(new(big.Int).SetUint64(tx.Nonce)),
+	}
+	if len(tx.To) > 0 {
+		to := common.BytesToAddress(tx.To)
+		raw.To = &to
+	}
+	if tx.GasTipCap.Valid {
+		raw.GasFeeCap = raw.GasPrice
+		raw.GasTipCap = (*hexutil.Big)(tx.GasTipCap.Decimal.BigInt())
+	}
+	return raw
+}
+
 func Eth1TransactionTxData(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -11,6 +11,7 @@ import (
 	"github.com/gobitfly/eth2-beaconchain-explorer/price"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 )
 
@@ -42,16 +43,10 @@ func GasNow(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	resRet := []*struct {
-		Ts      int64   `json:"ts"`
-		AvgFast float64 `json:"fast"`
-	}{}
+	resRet := []*types.GasNowHistoryAverage{}
 
 	for ts, fast := range group {
-		resRet = append(resRet, &struct {
-			Ts      int64   `json:"ts"`
-			AvgFast float64 `json:"fast"`
-		}{
+		resRet = append(resRet, &types.GasNowHistoryAverage{
 			Ts:      ts,
 			AvgFast: fast,
 		})
@@ -61,7 +56,10 @@ func GasNow(w http.ResponseWriter, r *http.Request) {
 		return resRet[i].Ts > resRet[j].Ts
 	})
 
-	data.Data = resRet
+	data.Data = &types.GasNowChartsData{
+		History:   resRet,
+		WaitTimes: services.LatestMempoolWaitTimes(),
+	}
 
 	if handleTemplateError(w, r, "gasnow.go", "GasNow", "", gasNowTemplate.ExecuteTemplate(w, "layout", data)) != nil {
 		return // an error has occurred and was processed
@@ -8,7 +8,6 @@ import (
 	"html/template"
 	"io"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -521,7 +520,8 @@ func UserNotificationsCenter(w http.ResponseWriter, r *http.Request) {
 			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkValidatorActivationQueueNotFullEventName) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkValidatorExitQueueFullEventName) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkValidatorExitQueueNotFullEventName) ||
-			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkLivenessIncreasedEventName) {
+			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkLivenessIncreasedEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkChainReorgEventName) {
 			typeCount.Network++
 		} else if sub.EventName == utils.GetNetwork()+":"+string(types.TaxReportEventName) {
 			typeCount.Income++
@@ -717,9 +717,26 @@ func UserNotificationsCenter(w http.ResponseWriter, r *http.Request) {
 		Events:    events,
 	}
 
+	notificationSettings, err := db.GetUserNotificationSettings([]uint64{user.UserID})
+	if err != nil {
+		logger.Errorf("error retrieving notification settings for user %v: %v ", user.UserID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	formatQuietHours := func(minutes *int64) string {
+		if minutes == nil {
+			return ""
+		}
+		return fmt.Sprintf("%02d:%02d", *minutes/60, *minutes%60)
+	}
+
 	userNotificationsCenterData.NotificationChannelsModal = types.NotificationChannelsModal{
 		CsrfField:            csrf.TemplateField(r),
 		NotificationChannels: notificationChannels,
+		TimeZone:             notificationSettings[user.UserID].TimeZone,
+		QuietHoursStart:      formatQuietHours(notificationSettings[user.UserID].QuietHoursStart),
+		QuietHoursEnd:        formatQuietHours(notificationSettings[user.UserID].QuietHoursEnd),
+		DigestModes:          types.NotificationDigestModes,
 	}
 	userNotificationsCenterData.NetworkEventModal = types.NetworkEventModal{
 		CsrfField: csrf.TemplateField(r),
@@ -2558,7 +2575,10 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 			event_names,
 			destination,
 			request,
-			response
+			response,
+			secret,
+			verification_token,
+			verified_at
 		FROM users_webhooks
 		WHERE user_id = $1;
 	`, user.UserID)
@@ -2641,6 +2661,13 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 			isDiscord = true
 		}
 
+		destination := "webhook"
+		if wh.Destination.Valid && wh.Destination.String != "" {
+			destination = wh.Destination.String
+		}
+		// telegram and matrix webhooks store a chat or room id instead of an url
+		isChat := destination == string(types.TelegramNotificationChannel) || destination == string(types.MatrixNotificationChannel)
+
 		ls := template.HTML(`N/A`)
 
 		if wh.LastSent.Valid {
@@ -2661,22 +2688,39 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 		}
 
 		hostname := ""
-		if url != nil {
+		if isChat {
+			hostname = template.HTMLEscapeString(wh.Url)
+		} else if url != nil {
 			hostname = url.Hostname()
 		} else {
 			hostname = wh.Url
 		}
 
+		// the signing secret is only used for the generic webhooks, the chat platforms do not verify signatures
+		secret := template.HTML(`N/A`)
+		if wh.Secret.Valid && len(wh.Secret.String) >= 8 && destination == "webhook" {
+			secret = template.HTML(fmt.Sprintf(`<span>%v…</span>%v`, wh.Secret.String[:8], utils.CopyButtonText(wh.Secret.String)))
+		}
+
+		urlCell := template.HTML(fmt.Sprintf(`<span>%v</span><span style="margin-left: .5rem;">%v</span>`, hostname, utils.CopyButtonText(wh.Url)))
+		if !services.IsChatTargetVerified(wh) && wh.VerificationToken.Valid {
+			urlCell = webhookVerificationHTML(&wh, hostname, csrf.TemplateField(r))
+		}
+
 		webhookRows = append(webhookRows, types.UserWebhookRow{
-			ID:           wh.ID,
-			Retries:      template.HTML(fmt.Sprintf("%d", wh.Retries)),
-			UrlFull:      wh.Url,
-			Url:          template.HTML(fmt.Sprintf(`<span>%v</span><span style="margin-left: .5rem;">%v</span>`, hostname, utils.CopyButtonText(wh.Url))),
-			LastSent:     ls,
-			Events:       events,
-			Discord:      isDiscord,
-			CsrfField:    csrf.TemplateField(r),
-			WebhookError: whErr,
+			ID:               wh.ID,
+			Secret:           secret,
+			Retries:          template.HTML(fmt.Sprintf("%d", wh.Retries)),
+			UrlFull:          wh.Url,
+			Url:              urlCell,
+			LastSent:         ls,
+			Events:           events,
+			Discord:          isDiscord,
+			Destination:      template.HTML(destination),
+			DestinationLabel: webhookDestinationLabel(destination),
+			Destinations:     webhookDestinations(),
+			CsrfField:        csrf.TemplateField(r),
+			WebhookError:     whErr,
 		})
 
 	}
@@ -2730,6 +2774,7 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 	})
 
 	pageData.Events = events
+	pageData.Destinations = webhookDestinations()
 
 	pageData.Flashes = utils.GetFlashes(w, r, authSessionName)
 
@@ -2755,16 +2800,15 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 	// const VALIDATOR_EVENTS = ['validator_attestation_missed', 'validator_proposal_missed', 'validator_proposal_submitted', 'validator_got_slashed', 'validator_synccommittee_soon']
 	// const MONITORING_EVENTS = ['monitoring_machine_offline', 'monitoring_hdd_almostfull', 'monitoring_cpu_load']
 
-	urlForm := r.FormValue("url")
+	urlForm := strings.TrimSpace(r.FormValue("url"))
+	destination := webhookDestinationFromForm(r)
 
-	if !utils.IsValidUrl(urlForm) {
-		utils.SetFlash(w, r, authSessionName, "Error: The URL provided is invalid.")
+	if err := validateWebhookTarget(destination, urlForm); err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: "+err.Error())
 		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
 		return
 	}
 
-	destination := "webhook"
-
 	validatorIsOffline := r.FormValue(string(types.ValidatorIsOfflineEventName)) == "on"
 	validatorProposalMissed := r.FormValue(string(types.ValidatorMissedProposalEventName)) == "on"
 	validatorProposalSubmitted := r.FormValue(string(types.ValidatorExecutedProposalEventName)) == "on"
@@ -2775,11 +2819,6 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 	monitoringMachineOffline := r.FormValue(string(types.MonitoringMachineOfflineEventName)) == "on"
 	monitoringHddAlmostfull := r.FormValue(string(types.MonitoringMachineDiskAlmostFullEventName)) == "on"
 	monitoringCpuLoad := r.FormValue(string(types.MonitoringMachineCpuLoadEventName)) == "on"
-	discord := r.FormValue("discord") == "on"
-
-	if discord {
-		destination = "webhook_discord"
-	}
 
 	all := r.FormValue("all") == "on"
 
@@ -2860,7 +2899,23 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = tx.Exec(`INSERT INTO users_webhooks (user_id, url, event_names, destination) VALUES ($1, $2, $3, $4)`, user.UserID, urlForm, pq.StringArray(eventNames), destination)
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		logger.WithError(err).Errorf("error generating webhook secret")
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong adding your webhook, please try again in a bit.")
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	target, verificationToken, _, err := webhookVerification(nil, destination, urlForm)
+	if err != nil {
+		logger.WithError(err).Errorf("error generating webhook verification token")
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong adding your webhook, please try again in a bit.")
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	_, err = tx.Exec(`INSERT INTO users_webhooks (user_id, url, event_names, destination, secret, verification_token) VALUES ($1, $2, $3, $4, $5, $6)`, user.UserID, target, pq.StringArray(eventNames), destination, secret, verificationToken)
 	if err != nil {
 		logger.WithError(err).Errorf("error inserting a new webhook for user")
 		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong adding your webhook, please try again in a bit.")
@@ -2896,9 +2951,14 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 	// const VALIDATOR_EVENTS = ['validator_attestation_missed', 'validator_proposal_missed', 'validator_proposal_submitted', 'validator_got_slashed', 'validator_synccommittee_soon']
 	// const MONITORING_EVENTS = ['monitoring_machine_offline', 'monitoring_hdd_almostfull', 'monitoring_cpu_load']
 
-	urlForm := r.FormValue("url")
+	urlForm := strings.TrimSpace(r.FormValue("url"))
+	destination := webhookDestinationFromForm(r)
 
-	destination := "webhook"
+	if err := validateWebhookTarget(destination, urlForm); err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: "+err.Error())
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
 
 	validatorIsOffline := r.FormValue(string(types.ValidatorIsOfflineEventName)) == "on"
 	validatorProposalMissed := r.FormValue(string(types.ValidatorMissedProposalEventName)) == "on"
@@ -2910,11 +2970,6 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 	monitoringMachineOffline := r.FormValue(string(types.MonitoringMachineOfflineEventName)) == "on"
 	monitoringHddAlmostfull := r.FormValue(string(types.MonitoringMachineDiskAlmostFullEventName)) == "on"
 	monitoringCpuLoad := r.FormValue(string(types.MonitoringMachineCpuLoadEventName)) == "on"
-	discord := r.FormValue("discord") == "on"
-
-	if discord {
-		destination = "webhook_discord"
-	}
 
 	all := r.FormValue("all") == "on"
 
@@ -2951,21 +3006,26 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 	defer tx.Rollback()
 
-	urlValid := ""
-
-	urlParsed, err := url.Parse(urlForm)
+	current := &types.UserWebhook{}
+	err = tx.Get(current, `SELECT id, url, destination, verification_token, verified_at FROM users_webhooks WHERE user_id = $1 AND id = $2 FOR UPDATE`, user.UserID, webhookID)
 	if err != nil {
-		logger.WithError(err).Errorf("could not parse url: %v", urlForm)
-		utils.SetFlash(w, r, authSessionName, "Error: the URL you have provided is invalid.")
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.WithError(err).Errorf("error getting webhook %v for user", webhookID)
+			utils.SetFlash(w, r, authSessionName, "Error: Something went wrong editing your webhook, please try again in a bit.")
+		}
 		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
 		return
 	}
 
-	if urlParsed != nil {
-		urlValid = urlForm
+	target, verificationToken, verifiedAt, err := webhookVerification(current, destination, urlForm)
+	if err != nil {
+		logger.WithError(err).Errorf("error generating webhook verification token")
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong editing your webhook, please try again in a bit.")
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
 	}
 
-	_, err = tx.Exec(`UPDATE users_webhooks set url = $1, event_names = $2, destination = $3 where user_id = $4 and id = $5`, urlValid, pq.StringArray(eventNames), destination, user.UserID, webhookID)
+	_, err = tx.Exec(`UPDATE users_webhooks set url = $1, event_names = $2, destination = $3, verification_token = $4, verified_at = $5 where user_id = $6 and id = $7`, target, pq.StringArray(eventNames), destination, verificationToken, verifiedAt, user.UserID, webhookID)
 	if err != nil {
 		logger.WithError(err).Errorf("error update webhook for user")
 		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong editing your webhook, please try again in a bit.")
@@ -3017,6 +3077,74 @@ func UsersDeleteWebhook(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
 }
 
+// UsersRegenerateWebhookSecret replaces the signing secret of a webhook, all following requests are signed with the
+// new secret
+func UsersRegenerateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	webhookID, err := strconv.ParseUint(mux.Vars(r)["webhookID"], 10, 64)
+	if err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: Invalid webhook id.")
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	_, err = regenerateWebhookSecret(user.UserID, webhookID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.WithError(err).Errorf("error regenerating secret of webhook %v for user %v", webhookID, user.UserID)
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong regenerating the secret of your webhook, please try again in a bit.")
+	}
+	http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+}
+
+// UsersVerifyWebhook verifies the room of a matrix webhook, the bot joins the room and looks for the verification code
+// in its recent messages. The user has to invite the bot and post the code before.
+func UsersVerifyWebhook(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	webhookID, err := strconv.ParseUint(mux.Vars(r)["webhookID"], 10, 64)
+	if err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: Invalid webhook id.")
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	wh := &types.UserWebhook{}
+	err = db.FrontendReaderDB.Get(wh, `SELECT id, url, destination, verification_token, verified_at FROM users_webhooks WHERE user_id = $1 AND id = $2 AND destination = $3`, user.UserID, webhookID, types.MatrixNotificationChannel)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.WithError(err).Errorf("error getting webhook %v for user %v", webhookID, user.UserID)
+			utils.SetFlash(w, r, authSessionName, "Error: Something went wrong verifying your webhook, please try again in a bit.")
+		}
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+	if wh.VerifiedAt.Valid || !wh.VerificationToken.Valid {
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	err = services.VerifyMatrixRoom(r.Context(), wh.Url, wh.VerificationToken.String)
+	if err != nil {
+		if errors.Is(err, services.ErrMatrixRoomNotVerified) {
+			utils.SetFlash(w, r, authSessionName, "Error: The verification code was not found in the room, please invite our bot and post the code in the room.")
+		} else {
+			logger.WithError(err).Warnf("error verifying matrix room of webhook %v", webhookID)
+			utils.SetFlash(w, r, authSessionName, "Error: Our bot could not join the room, please invite it and try again.")
+		}
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	// the token is compared to make sure the room was not changed while it was verified
+	_, err = db.FrontendWriterDB.Exec(`UPDATE users_webhooks SET verified_at = now(), verification_token = NULL WHERE user_id = $1 AND id = $2 AND url = $3 AND verification_token = $4`, user.UserID, webhookID, wh.Url, wh.VerificationToken.String)
+	if err != nil {
+		logger.WithError(err).Errorf("error verifying webhook %v for user %v", webhookID, user.UserID)
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong verifying your webhook, please try again in a bit.")
+	}
+	http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+}
+
 // UsersNotificationChannel
 // Accepts form encoded values channel and active to set the global notification settings for a user
 func UsersNotificationChannels(w http.ResponseWriter, r *http.Request) {
@@ -3067,6 +3195,42 @@ func UsersNotificationChannels(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the delivery settings are part of the same form
+	if r.Form.Has("time_zone") {
+		settings := &types.UserNotificationSettings{UserID: user.UserID, TimeZone: r.FormValue("time_zone")}
+		settings.QuietHoursStart, err = parseQuietHoursTime(r.FormValue("quiet_hours_start"))
+		if err == nil {
+			settings.QuietHoursEnd, err = parseQuietHoursTime(r.FormValue("quiet_hours_end"))
+		}
+		if err == nil {
+			err = validateUserNotificationSettings(settings)
+		}
+		if err != nil {
+			http.Error(w, "The quiet hours or time zone provided are invalid.", http.StatusBadRequest)
+			return
+		}
+		err = db.SaveUserNotificationSettings(settings)
+		if err != nil {
+			logger.WithError(err).Error("error saving notification settings")
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if mode := r.FormValue("digest_mode"); mode != "" {
+		digestMode, err := types.NotificationDigestModeFromString(mode)
+		if err != nil {
+			http.Error(w, "The email delivery mode provided is invalid.", http.StatusBadRequest)
+			return
+		}
+		err = db.UpdateAllSubscriptionsDigestMode(user.UserID, digestMode)
+		if err != nil {
+			logger.WithError(err).Error("error updating digest mode of subscriptions")
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	http.Redirect(w, r, "/user/notifications", http.StatusSeeOther)
 }
 
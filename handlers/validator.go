@@ -39,6 +39,9 @@ import (
 
 var validatorEditFlash = "edit_validator_flash"
 
+// validatorQueueHistoryLimit is the number of changes of the estimated activation or exit epoch shown on the validator page
+const validatorQueueHistoryLimit = 10
+
 // Validator returns validator data using a go template
 func Validator(w http.ResponseWriter, r *http.Request) {
 	validatorTemplateFiles := append(layoutTemplateFiles,
@@ -274,6 +277,15 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 			}
 
 			g := errgroup.Group{}
+			g.Go(func() error {
+				history, err := db.GetValidatorQueuePositionHistory([][]byte{validatorPageData.PublicKey}, validatorQueueHistoryLimit)
+				if err != nil {
+					logrus.Warnf("error getting queue position history for validator %v: %v", validatorPageData.PublicKey, err)
+					return nil
+				}
+				validatorPageData.QueuePositionHistory = history
+				return nil
+			})
 			g.Go(func() error {
 				if utils.ElectraHasHappened(validatorPageData.Epoch) {
 					// deposit processing queue
@@ -450,6 +462,18 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	defer cancel() // ensure resources cleaned up in case g.Wait is skipped
 	g, ctx := errgroup.WithContext(gCtx)
 
+	g.Go(func() error {
+		if validatorPageData.ActivationEpoch <= validatorPageData.Epoch && validatorPageData.ExitEpoch == 9223372036854775807 {
+			return nil
+		}
+		history, err := db.GetValidatorQueuePositionHistory([][]byte{validatorPageData.PublicKey}, validatorQueueHistoryLimit)
+		if err != nil {
+			return fmt.Errorf("error calling db.GetValidatorQueuePositionHistory: %w", err)
+		}
+		validatorPageData.QueuePositionHistory = history
+		return nil
+	})
+
 	g.Go(func() error {
 		start := time.Now()
 		defer func() {
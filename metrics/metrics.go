@@ -70,6 +70,32 @@ var (
 		Name: "counter",
 		Help: "Counter of events with name in labels",
 	}, []string{"name"})
+	BeaconNodeRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "beacon_node_request_duration",
+		Help:    "Duration of beacon node requests in seconds by endpoint and method",
+		Buckets: []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 120},
+	}, []string{"endpoint", "method"})
+	BeaconNodeErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "beacon_node_errors",
+		Help: "Counter of failed beacon node requests by endpoint and method",
+	}, []string{"endpoint", "method"})
+	BeaconNodeFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "beacon_node_failovers",
+		Help: "Counter of requests that were retried on another beacon node by the endpoint that failed",
+	}, []string{"endpoint"})
+	BeaconNodeDisagreements = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "beacon_node_disagreements",
+		Help: "Counter of block roots on which a beacon node disagreed with the primary node",
+	}, []string{"endpoint"})
+	BeaconNodeHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "beacon_node_healthy",
+		Help: "Gauge with the health state (1 healthy, 0 unhealthy) of a beacon node",
+	}, []string{"endpoint"})
+	ChainReorgDepth = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "chain_reorg_depth",
+		Help:    "Histogram of the number of blocks orphaned by the chain reorgs detected by the slot exporter",
+		Buckets: []float64{1, 2, 3, 4, 8, 16, 32, 64},
+	})
 )
 
 var logger = logrus.New().WithField("module", "metrics")
@@ -144,6 +170,11 @@ func (r *responseWriterDelegator) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap returns the underlying ResponseWriter, allowing http.ResponseController to reach it
+func (r *responseWriterDelegator) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // Serve serves prometheus metrics on the given address under /metrics
 func Serve(addr string) error {
 	router := http.NewServeMux()
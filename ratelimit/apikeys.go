@@ -0,0 +1,261 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/go-redis/redis/v8"
+	gorillacontext "github.com/gorilla/context"
+	"github.com/lib/pq"
+)
+
+const (
+	ScopePublicRead    = "public:read"          // read-only access to the public data of the api
+	ScopeMetricsWrite  = "metrics:write"        // ingestion of machine metrics
+	ScopeNotifications = "notifications:manage" // managing the notification subscriptions of the user
+)
+
+// ApiKeyScopes are the scopes that can be granted to an api key
+var ApiKeyScopes = []string{ScopePublicRead, ScopeMetricsWrite, ScopeNotifications}
+
+var (
+	ErrApiKeyExpired      = errors.New("api key is expired or revoked")
+	ErrApiKeyIPNotAllowed = errors.New("api key is not allowed for this ip")
+	ErrApiKeyScope        = errors.New("api key is missing the scope for this route")
+)
+
+// ApiKey is an api key as it is known to the rate limiter
+type ApiKey struct {
+	UserId     int64
+	Scopes     []string
+	AllowedIPs []*net.IPNet // empty allows every ip
+	ValidUntil time.Time
+
+	invalidAllowedIPs bool // the allowed ips could not be parsed, no ip is allowed
+}
+
+// HasScope returns true if the scope was granted to the key
+func (k *ApiKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsIP returns true if requests of the ip may use the key
+func (k *ApiKey) AllowsIP(ip string) bool {
+	if k.invalidAllowedIPs {
+		return false
+	}
+	if len(k.AllowedIPs) == 0 {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range k.AllowedIPs {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+var apiKeysLastUsed = map[string]time.Time{} // guarded by apiKeysLastUsedMu
+var apiKeysLastUsedMu = &sync.Mutex{}
+
+var apiKeysRedisClient *redis.Client
+var apiKeysRedisClientOnce = &sync.Once{}
+
+// ParseAllowedIPs parses ips and cidrs, single ips are converted to networks that only contain the ip
+func ParseAllowedIPs(ips []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(ips))
+	for _, ip := range ips {
+		ip = strings.TrimSpace(ip)
+		if ip == "" {
+			continue
+		}
+		if strings.Contains(ip, "/") {
+			_, n, err := net.ParseCIDR(ip)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cidr %v", ip)
+			}
+			nets = append(nets, n)
+			continue
+		}
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return nil, fmt.Errorf("invalid ip %v", ip)
+		}
+		bits := 128
+		if parsed.To4() != nil {
+			parsed = parsed.To4()
+			bits = 32
+		}
+		nets = append(nets, &net.IPNet{IP: parsed, Mask: net.CIDRMask(bits, bits)})
+	}
+	return nets, nil
+}
+
+// newApiKey converts the columns of api_keys to an ApiKey
+func newApiKey(userId int64, scopes, allowedIPs pq.StringArray, validUntil time.Time) *ApiKey {
+	k := &ApiKey{
+		UserId:     userId,
+		Scopes:     scopes,
+		ValidUntil: validUntil,
+	}
+	nets, err := ParseAllowedIPs(allowedIPs)
+	if err != nil {
+		// a key with invalid ips must not become usable from everywhere
+		logger.WithError(err).Warnf("error parsing allowed ips of api key of user %v", userId)
+		k.invalidAllowedIPs = true
+		return k
+	}
+	k.AllowedIPs = nets
+	return k
+}
+
+// routeScope returns the scope an api key needs to be used for the route
+func routeScope(route string) string {
+	switch {
+	case route == "/api/v1/client/metrics" || strings.HasPrefix(route, "/api/v1/stats/"):
+		return ScopeMetricsWrite
+	case strings.HasPrefix(route, "/api/v1/user/notifications"):
+		return ScopeNotifications
+	default:
+		return ScopePublicRead
+	}
+}
+
+// authorizeApiKey checks that the api key of the request is valid, allowed for the ip and has the scope of the route.
+// Requests without key or with an unknown key are not checked, they are rate limited like requests without key.
+// If the key may be used to manage notifications, the user of the key is attached to the request.
+func authorizeApiKey(r *http.Request, key, ip string) error {
+	if key == "nokey" {
+		return nil
+	}
+
+	rateLimitsMu.RLock()
+	apiKey, ok := apiKeys[key]
+	rateLimitsMu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	if !apiKey.ValidUntil.After(now) {
+		return ErrApiKeyExpired
+	}
+	if !apiKey.AllowsIP(ip) {
+		return ErrApiKeyIPNotAllowed
+	}
+	scope := routeScope(getRoute(r))
+	if !apiKey.HasScope(scope) {
+		return ErrApiKeyScope
+	}
+	if scope == ScopeNotifications {
+		gorillacontext.Set(r, utils.ApiKeyUserIdKey, uint64(apiKey.UserId))
+	}
+
+	apiKeysLastUsedMu.Lock()
+	apiKeysLastUsed[key] = now
+	apiKeysLastUsedMu.Unlock()
+	return nil
+}
+
+// updateApiKeysLastUsed writes the time the api keys were last used by this process to postgres
+func updateApiKeysLastUsed() error {
+	apiKeysLastUsedMu.Lock()
+	lastUsed := apiKeysLastUsed
+	apiKeysLastUsed = make(map[string]time.Time, len(lastUsed))
+	apiKeysLastUsedMu.Unlock()
+
+	if len(lastUsed) == 0 {
+		return nil
+	}
+
+	keys := make(pq.StringArray, 0, len(lastUsed))
+	times := make(pq.StringArray, 0, len(lastUsed))
+	for k, t := range lastUsed {
+		keys = append(keys, k)
+		times = append(times, t.UTC().Format(time.RFC3339Nano))
+	}
+	// changed_at is not touched, otherwise every replica would reload the keys
+	_, err := db.FrontendWriterDB.Exec(`
+		UPDATE api_keys SET last_used_at = u.last_used_at
+		FROM (SELECT UNNEST($1::TEXT[]) AS api_key, UNNEST($2::TIMESTAMP[]) AS last_used_at) u
+		WHERE api_keys.api_key = u.api_key AND (api_keys.last_used_at IS NULL OR api_keys.last_used_at < u.last_used_at)`, keys, times)
+	if err != nil {
+		return fmt.Errorf("error updating last used time of api keys: %w", err)
+	}
+	return nil
+}
+
+func getApiKeysRedisClient() *redis.Client {
+	apiKeysRedisClientOnce.Do(func() {
+		if utils.Config.RedisCacheEndpoint == "" {
+			return
+		}
+		apiKeysRedisClient = redis.NewClient(&redis.Options{
+			Addr:        utils.Config.RedisCacheEndpoint,
+			ReadTimeout: time.Second * 20,
+		})
+	})
+	return apiKeysRedisClient
+}
+
+func apiKeysChannel() string {
+	return fmt.Sprintf("%d:ratelimit:apikeys", utils.Config.Chain.ClConfig.DepositChainID)
+}
+
+// PublishApiKeysChanged tells the rate limiters of all explorer instances to reload the api keys instead of waiting for
+// the next update, so that revoked keys stop working immediately
+func PublishApiKeysChanged() error {
+	client := getApiKeysRedisClient()
+	if client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	err := client.Publish(ctx, apiKeysChannel(), "changed").Err()
+	if err != nil {
+		return fmt.Errorf("error publishing api key change: %w", err)
+	}
+	return nil
+}
+
+// receiveApiKeyChanges reloads the api keys whenever another instance published a change
+func receiveApiKeyChanges() {
+	client := getApiKeysRedisClient()
+	if client == nil {
+		return
+	}
+	for {
+		pubsub := client.Subscribe(context.Background(), apiKeysChannel())
+		for {
+			_, err := pubsub.ReceiveMessage(context.Background())
+			if err != nil {
+				logger.WithError(err).Errorf("error receiving api key changes from redis, resubscribing")
+				break
+			}
+			err = updateRateLimits()
+			if err != nil {
+				logger.WithError(err).Errorf("error updating ratelimits after api key change")
+			}
+		}
+		pubsub.Close()
+		time.Sleep(time.Second)
+	}
+}
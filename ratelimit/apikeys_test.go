@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestApiKeyAllowsIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowedIPs []string
+		ip         string
+		want       bool
+	}{
+		{name: "no allowlist", allowedIPs: nil, ip: "198.51.100.1", want: true},
+		{name: "single ip", allowedIPs: []string{"198.51.100.1"}, ip: "198.51.100.1", want: true},
+		{name: "other ip", allowedIPs: []string{"198.51.100.1"}, ip: "198.51.100.2", want: false},
+		{name: "cidr", allowedIPs: []string{"198.51.100.0/24"}, ip: "198.51.100.77", want: true},
+		{name: "ipv6 cidr", allowedIPs: []string{"198.51.100.1", "2001:db8::/32"}, ip: "2001:db8::1", want: true},
+		{name: "invalid request ip", allowedIPs: []string{"198.51.100.0/24"}, ip: "INVALID", want: false},
+		{name: "invalid allowlist", allowedIPs: []string{"198.51.100.1", "not-an-ip"}, ip: "198.51.100.1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newApiKey(1, nil, pq.StringArray(tt.allowedIPs), time.Now().Add(time.Hour))
+			if got := k.AllowsIP(tt.ip); got != tt.want {
+				t.Errorf("got %v for %v with allowlist %v, want %v", got, tt.ip, tt.allowedIPs, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAllowedIPs(t *testing.T) {
+	nets, err := ParseAllowedIPs([]string{" 198.51.100.1 ", "", "2001:db8::/32"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nets) != 2 || nets[0].String() != "198.51.100.1/32" || nets[1].String() != "2001:db8::/32" {
+		t.Errorf("got %v, want [198.51.100.1/32 2001:db8::/32]", nets)
+	}
+
+	for _, invalid := range []string{"198.51.100.300", "198.51.100.0/33", "example.com"} {
+		if _, err := ParseAllowedIPs([]string{invalid}); err == nil {
+			t.Errorf("got no error for %v", invalid)
+		}
+	}
+}
+
+func TestRouteScope(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/validator/{indexOrPubkey}":     ScopePublicRead,
+		"/api/v1/client/metrics":                ScopeMetricsWrite,
+		"/api/v1/stats/{apiKey}/{machine}":      ScopeMetricsWrite,
+		"/api/v1/user/notifications/subscribe":  ScopeNotifications,
+		"/api/v1/user/notifications":            ScopeNotifications,
+		"/api/v1/user/dashboard/save":           ScopePublicRead,
+		"/api/v1/execution/{addressIndexOrPub}": ScopePublicRead,
+	}
+	for route, want := range tests {
+		if got := routeScope(route); got != want {
+			t.Errorf("got scope %v for %v, want %v", got, route, want)
+		}
+	}
+}
+
+func TestHttpMiddlewareApiKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      *ApiKey
+		wantCode int
+	}{
+		{
+			name:     "valid key",
+			key:      newApiKey(7, pq.StringArray{ScopePublicRead}, nil, time.Now().Add(time.Hour)),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "revoked key",
+			key:      newApiKey(7, pq.StringArray{ScopePublicRead}, nil, time.Now().Add(-time.Second)),
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "missing scope",
+			key:      newApiKey(7, pq.StringArray{ScopeMetricsWrite}, nil, time.Now().Add(time.Hour)),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "ip not allowed",
+			key:      newApiKey(7, pq.StringArray{ScopePublicRead}, pq.StringArray{"198.51.100.0/24"}, time.Now().Add(time.Hour)),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "ip allowed",
+			key:      newApiKey(7, pq.StringArray{ScopePublicRead}, pq.StringArray{"192.0.2.0/24"}, time.Now().Add(time.Hour)),
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(t, &ApiProduct{Second: 100, Hour: 100})
+			rateLimitsMu.Lock()
+			apiKeys = map[string]*ApiKey{"testkey": tt.key}
+			rateLimitsMu.Unlock()
+			t.Cleanup(func() {
+				rateLimitsMu.Lock()
+				apiKeys = map[string]*ApiKey{}
+				rateLimitsMu.Unlock()
+			})
+
+			// the remote address of httptest requests is 192.0.2.1
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/test?apikey=testkey", nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("got status %v, want %v", rec.Code, tt.wantCode)
+			}
+		})
+	}
+
+	t.Run("unknown keys are rate limited like requests without key", func(t *testing.T) {
+		router := newTestRouter(t, &ApiProduct{Second: 100, Hour: 100})
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/test?apikey=unknown", nil))
+		if rec.Code != http.StatusOK || rec.Header().Get(HeaderRateLimitValidApiKey) != "false" {
+			t.Errorf("got status %v and valid key header %v, want 200 and false", rec.Code, rec.Header().Get(HeaderRateLimitValidApiKey))
+		}
+	})
+}
@@ -3,6 +3,7 @@ package ratelimit
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/go-redis/redis/v8"
 	"github.com/gorilla/mux"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/time/rate"
 )
@@ -59,6 +61,9 @@ const (
 	defaultBucket = "default" // if no bucket is set for a route, use this one
 
 	statsTruncateDuration = time.Hour * 1 // ratelimit-stats are truncated to this duration
+
+	statsKeyPrefix        = "rl:s:" // counts the requests per hour, user, apikey, route and bucket
+	blockedStatsKeyPrefix = "rl:b:" // counts the requests answered with 429 with the same key as the stats
 )
 
 var updateInterval = time.Second * 60 // how often to update ratelimits, weights and stats
@@ -66,21 +71,21 @@ var updateInterval = time.Second * 60 // how often to update ratelimits, weights
 var apiProducts = map[string]*ApiProduct{} // key: <bucket>:<product_name>
 var apiProductsMu = &sync.RWMutex{}
 
-var redisClient *redis.Client
-var redisIsHealthy atomic.Bool
+var store CounterStore // where the ratelimit and stats counters are kept
+var storeIsHealthy atomic.Bool
 
 var lastRateLimitUpdateKeys = time.Unix(0, 0)       // guarded by lastRateLimitUpdateMu
 var lastRateLimitUpdateRateLimits = time.Unix(0, 0) // guarded by lastRateLimitUpdateMu
 var lastRateLimitUpdateMu = &sync.Mutex{}
 
-var fallbackRateLimiter = NewFallbackRateLimiter() // if redis is offline, use this rate limiter
+var fallbackRateLimiter = NewFallbackRateLimiter() // if the store is offline, use this rate limiter
 
 var initializedWg = &sync.WaitGroup{} // wait for everything to be initialized before serving requests
 
 var rateLimitsMu = &sync.RWMutex{}
 var rateLimits = map[string]*RateLimit{}         // guarded by rateLimitsMu
 var rateLimitsByUserId = map[string]*RateLimit{} // guarded by rateLimitsMu, key: <bucket>:<userId>
-var userIdByApiKey = map[string]int64{}          // guarded by rateLimitsMu
+var apiKeys = map[string]*ApiKey{}               // guarded by rateLimitsMu, expired keys are kept to reject them
 
 var weightsMu = &sync.RWMutex{}
 var weights = map[string]int64{}  // guarded by weightsMu
@@ -94,6 +99,7 @@ type DbEntry struct {
 	ApiKey   string
 	Endpoint string
 	Count    int64
+	Blocked  int64 // number of requests that were answered with 429
 	Bucket   string
 }
 
@@ -166,6 +172,11 @@ func (r *responseWriterDelegator) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap returns the underlying ResponseWriter, allowing http.ResponseController to reach it
+func (r *responseWriterDelegator) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 func (r *responseWriterDelegator) WriteHeader(code int) {
 	r.status = code
 	r.ResponseWriter.WriteHeader(code)
@@ -213,10 +224,11 @@ func GetMaxBadRquestWeight() int64 {
 
 // Init initializes the RateLimiting middleware, the rateLimiting middleware will not work without calling Init first. The second parameter is a function the will get called on every request, it will only apply ratelimiting to requests when this func returns true.
 func Init() {
-	redisClient = redis.NewClient(&redis.Options{
-		Addr:        utils.Config.RedisSessionStoreEndpoint,
-		ReadTimeout: time.Second * 3,
-	})
+	var err error
+	store, err = newStoreFromConfig()
+	if err != nil {
+		logger.Fatal(err)
+	}
 
 	updateInterval = utils.Config.Frontend.RatelimitUpdateInterval
 	if updateInterval < time.Second {
@@ -256,14 +268,19 @@ func Init() {
 				firstRun = false
 			}
 			time.Sleep(updateInterval)
+			err = updateApiKeysLastUsed()
+			if err != nil {
+				logger.WithError(err).Errorf("error updating last used time of api keys")
+			}
 		}
 	}()
+	go receiveApiKeyChanges()
 	go func() {
 		firstRun := true
 		for {
-			err := updateRedisStatus()
+			err := updateStoreStatus()
 			if err != nil {
-				logger.WithError(err).Errorf("error checking redis")
+				logger.WithError(err).Errorf("error checking ratelimit store")
 				time.Sleep(time.Second * 1)
 				continue
 			}
@@ -275,10 +292,39 @@ func Init() {
 		}
 	}()
 
+	if _, ok := store.(*MemoryCounterStore); ok {
+		// the counters of the in-process store are not visible to the DBUpdater, so the stats are written by this process
+		go func() {
+			for {
+				time.Sleep(updateInterval)
+				err := updateStats(store)
+				if err != nil {
+					logger.WithError(err).Errorf("error updating stats")
+				}
+				err = checkQuotaAlerts(store)
+				if err != nil {
+					logger.WithError(err).Errorf("error checking api quota alerts")
+				}
+			}
+		}()
+	}
+
 	initializedWg.Wait()
 }
 
-// HttpMiddleware returns an http.Handler that can be used as middleware to RateLimit requests. If redis is offline, it will use a fallback rate limiter.
+// newStoreFromConfig returns the counter store that is configured in utils.Config.Frontend.RatelimitStore
+func newStoreFromConfig() (CounterStore, error) {
+	var redisClient *redis.Client
+	if utils.Config.Frontend.RatelimitStore == "" || utils.Config.Frontend.RatelimitStore == RedisStore {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:        utils.Config.RedisSessionStoreEndpoint,
+			ReadTimeout: time.Second * 3,
+		})
+	}
+	return NewCounterStore(utils.Config.Frontend.RatelimitStore, redisClient, db.FrontendWriterDB)
+}
+
+// HttpMiddleware returns an http.Handler that can be used as middleware to RateLimit requests. If the store is offline, it will use a fallback rate limiter.
 func HttpMiddleware(next http.Handler) http.Handler {
 	initializedWg.Wait()
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -288,7 +334,19 @@ func HttpMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		if !redisIsHealthy.Load() {
+		key, ip := getKey(r)
+		err := authorizeApiKey(r, key, ip)
+		if err != nil {
+			metrics.Counter.WithLabelValues("ratelimit_apikey_rejected").Inc()
+			status := http.StatusForbidden
+			if errors.Is(err, ErrApiKeyExpired) {
+				status = http.StatusUnauthorized
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		if !storeIsHealthy.Load() {
 			metrics.Counter.WithLabelValues("ratelimit_fallback").Inc()
 			fallbackRateLimiter.Handle(w, r, next.ServeHTTP)
 			return
@@ -385,26 +443,26 @@ func updateWeights(firstRun bool) error {
 	return nil
 }
 
-// updateRedisStatus checks if redis is healthy and updates redisIsHealthy accordingly.
-func updateRedisStatus() error {
-	oldStatus := redisIsHealthy.Load()
+// updateStoreStatus checks if the store is healthy and updates storeIsHealthy accordingly.
+func updateStoreStatus() error {
+	oldStatus := storeIsHealthy.Load()
 	newStatus := true
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*1))
 	defer cancel()
-	err := redisClient.Ping(ctx).Err()
+	err := store.Ping(ctx)
 	if err != nil {
-		logger.WithError(err).Errorf("error pinging redis")
+		logger.WithError(err).Errorf("error pinging ratelimit store")
 		newStatus = false
 	}
 	if oldStatus != newStatus {
-		logger.WithFields(logrus.Fields{"oldStatus": oldStatus, "newStatus": newStatus}).Infof("redis status changed")
+		logger.WithFields(logrus.Fields{"oldStatus": oldStatus, "newStatus": newStatus}).Infof("ratelimit store status changed")
 	}
-	redisIsHealthy.Store(newStatus)
+	storeIsHealthy.Store(newStatus)
 	return nil
 }
 
-// updateStats scans redis for ratelimit:stats:* keys and inserts them into postgres, if the key's truncated date is older than specified stats-truncation it will also delete the key in redis.
-func updateStats(redisClient *redis.Client) error {
+// updateStats reads the rl:s:* counters from the store and inserts them into postgres, if the counter's truncated date is older than specified stats-truncation it will also delete the counter in the store.
+func updateStats(store CounterStore) error {
 	start := time.Now()
 	defer func() {
 		metrics.TaskDuration.WithLabelValues("ratelimit_updateStats").Observe(time.Since(start).Seconds())
@@ -413,48 +471,52 @@ func updateStats(redisClient *redis.Client) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*300)
 	defer cancel()
 
-	var err error
-	startTruncated := start.Truncate(statsTruncateDuration)
-
-	allKeys := []string{}
-	cursor := uint64(0)
+	entries, keysToDelete, err := getStatsEntries(ctx, store, start)
+	if err != nil {
+		return err
+	}
 
-	for {
-		// rl:s:<year>-<month>-<day>-<hour>:<userId>:<apikey>:<route>
-		cmd := redisClient.Scan(ctx, cursor, "rl:s:*:*:*:*", 1000)
-		if cmd.Err() != nil {
-			return cmd.Err()
-		}
-		keys, nextCursor, err := cmd.Result()
+	if len(entries) > 0 {
+		err = updateStatsEntries(entries)
 		if err != nil {
-			return err
-		}
-		cursor = nextCursor
-		allKeys = append(allKeys, keys...)
-		if cursor == 0 {
-			break
+			return fmt.Errorf("error updating stats entries: %w", err)
 		}
 	}
 
-	batchSize := 10000
-	for i := 0; i <= len(allKeys); i += batchSize {
-		start := i
-		end := i + batchSize
-		if end > len(allKeys) {
-			end = len(allKeys)
-		}
+	err = store.Del(ctx, keysToDelete)
+	if err != nil {
+		logger.Errorf("error deleting stats-keys from ratelimit store: %v", err)
+	}
+	err = store.DeleteExpired(ctx)
+	if err != nil {
+		logger.Errorf("error deleting expired counters from ratelimit store: %v", err)
+	}
 
-		if start == end {
-			break
-		}
+	return nil
+}
+
+// getStatsEntries returns the stats and blocked-stats counters of the store as db entries and the keys of the counters
+// whose hour has passed at the given time
+func getStatsEntries(ctx context.Context, store CounterStore, now time.Time) (entries []DbEntry, keysToDelete []string, err error) {
+	// rl:s:<year>-<month>-<day>-<hour>:<userId>:<apikey>:<route>:<bucket>
+	counters, err := store.Scan(ctx, statsKeyPrefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting stats counters: %w", err)
+	}
+	// rl:b:<year>-<month>-<day>-<hour>:<userId>:<apikey>:<route>:<bucket>
+	blockedCounters, err := store.Scan(ctx, blockedStatsKeyPrefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting blocked stats counters: %w", err)
+	}
 
-		keysToDelete := []string{}
-		keys := allKeys[start:end]
-		entries := make([]DbEntry, len(keys))
-		for i, k := range keys {
+	nowTruncated := now.Truncate(statsTruncateDuration)
+	entriesByKey := make(map[string]*DbEntry, len(counters))
+	keys := make([]string, 0, len(counters)) // keeps the order of the entries stable
+	for _, c := range []map[string]int64{counters, blockedCounters} {
+		for k, count := range c {
 			ks := strings.Split(k, ":")
 			if len(ks) < 6 {
-				return fmt.Errorf("error parsing key %s: split-len < 6", k)
+				return nil, nil, fmt.Errorf("error parsing key %s: split-len < 6", k)
 			}
 			bucket := "x" // older implementation did not have bucket in the key
 			if len(ks) == 7 {
@@ -463,71 +525,44 @@ func updateStats(redisClient *redis.Client) error {
 			dateString := ks[2]
 			date, err := time.Parse("2006-01-02-15", dateString)
 			if err != nil {
-				return fmt.Errorf("error parsing date in key %s: %v", k, err)
+				return nil, nil, fmt.Errorf("error parsing date in key %s: %v", k, err)
 			}
 			dateTruncated := date.Truncate(statsTruncateDuration)
-			if dateTruncated.Before(startTruncated) {
+			if dateTruncated.Before(nowTruncated) {
 				keysToDelete = append(keysToDelete, k)
 			}
 			userIdStr := ks[3]
 			userId, err := strconv.ParseInt(userIdStr, 10, 64)
 			if err != nil {
-				return fmt.Errorf("error parsing userId in key %s: %v", k, err)
-			}
-			entries[i] = DbEntry{
-				Date:     dateTruncated,
-				UserId:   userId,
-				ApiKey:   ks[4],
-				Endpoint: ks[5],
-				Bucket:   bucket,
+				return nil, nil, fmt.Errorf("error parsing userId in key %s: %v", k, err)
 			}
-		}
 
-		mgetSize := 500
-		for j := 0; j < len(keys); j += mgetSize {
-			mgetStart := j
-			mgetEnd := j + mgetSize
-			if mgetEnd > len(keys) {
-				mgetEnd = len(keys)
-			}
-			mgetRes, err := redisClient.MGet(ctx, keys[mgetStart:mgetEnd]...).Result()
-			if err != nil {
-				return fmt.Errorf("error getting stats-count from redis (%v-%v/%v): %w", mgetStart, mgetEnd, len(keys), err)
-			}
-			for k, v := range mgetRes {
-				vStr, ok := v.(string)
-				if !ok {
-					return fmt.Errorf("error parsing stats-count from redis: value is not string: %v: %v: %w", k, v, err)
-				}
-				entries[mgetStart+k].Count, err = strconv.ParseInt(vStr, 10, 64)
-				if err != nil {
-					return fmt.Errorf("error parsing stats-count from redis: value is not int64: %v: %v: %w", k, v, err)
+			entryKey := strings.Join(ks[2:], ":")
+			entry, ok := entriesByKey[entryKey]
+			if !ok {
+				entry = &DbEntry{
+					Date:     dateTruncated,
+					UserId:   userId,
+					ApiKey:   ks[4],
+					Endpoint: ks[5],
+					Bucket:   bucket,
 				}
+				entriesByKey[entryKey] = entry
+				keys = append(keys, entryKey)
 			}
-		}
-
-		err = updateStatsEntries(entries)
-		if err != nil {
-			return fmt.Errorf("error updating stats entries: %w", err)
-		}
-
-		if len(keysToDelete) > 0 {
-			delSize := 500
-			for j := 0; j < len(keysToDelete); j += delSize {
-				delStart := j
-				delEnd := j + delSize
-				if delEnd > len(keysToDelete) {
-					delEnd = len(keysToDelete)
-				}
-				_, err = redisClient.Del(ctx, keysToDelete[delStart:delEnd]...).Result()
-				if err != nil {
-					logger.Errorf("error deleting stats-keys from redis: %v", err)
-				}
+			if strings.HasPrefix(k, blockedStatsKeyPrefix) {
+				entry.Blocked = count
+			} else {
+				entry.Count = count
 			}
 		}
 	}
 
-	return nil
+	entries = make([]DbEntry, 0, len(keys))
+	for _, k := range keys {
+		entries = append(entries, *entriesByKey[k])
+	}
+	return entries, keysToDelete, nil
 }
 
 func updateStatsEntries(entries []DbEntry) error {
@@ -537,7 +572,7 @@ func updateStatsEntries(entries []DbEntry) error {
 	}
 	defer tx.Rollback()
 
-	numArgs := 5
+	numArgs := 6
 	batchSize := 65535 / numArgs // max 65535 params per batch, since postgres uses int16 for binding input params
 	valueArgs := make([]interface{}, 0, batchSize*numArgs)
 	valueStrings := make([]string, 0, batchSize)
@@ -554,6 +589,7 @@ func updateStatsEntries(entries []DbEntry) error {
 		valueArgs = append(valueArgs, entry.Endpoint)
 		valueArgs = append(valueArgs, entry.Count)
 		valueArgs = append(valueArgs, entry.Bucket)
+		valueArgs = append(valueArgs, entry.Blocked)
 
 		// logger.WithFields(logger.Fields{"count": entry.Count, "apikey": entry.ApiKey, "path": entry.Path, "date": entry.Date}).Infof("inserting stats entry %v/%v", allIdx+1, len(entries))
 
@@ -561,7 +597,7 @@ func updateStatsEntries(entries []DbEntry) error {
 		allIdx++
 
 		if batchIdx >= batchSize || allIdx >= len(entries) {
-			stmt := fmt.Sprintf(`INSERT INTO api_statistics (ts, apikey, endpoint, count, bucket) VALUES %s ON CONFLICT (ts, apikey, endpoint, bucket) DO UPDATE SET count = EXCLUDED.count`, strings.Join(valueStrings, ","))
+			stmt := fmt.Sprintf(`INSERT INTO api_statistics (ts, apikey, endpoint, count, bucket, blocked) VALUES %s ON CONFLICT (ts, apikey, endpoint, bucket) DO UPDATE SET count = EXCLUDED.count, blocked = EXCLUDED.blocked`, strings.Join(valueStrings, ","))
 			_, err := tx.Exec(stmt, valueArgs...)
 			if err != nil {
 				return err
@@ -580,7 +616,7 @@ func updateStatsEntries(entries []DbEntry) error {
 	return nil
 }
 
-// updateRateLimits updates the maps rateLimits, rateLimitsByUserId and userIdByApiKey with data from postgres-tables api_keys and api_ratelimits.
+// updateRateLimits updates the maps rateLimits, rateLimitsByUserId and apiKeys with data from postgres-tables api_keys and api_ratelimits.
 func updateRateLimits() error {
 	start := time.Now()
 	defer func() {
@@ -600,13 +636,15 @@ func updateRateLimits() error {
 	defer tx.Rollback()
 
 	dbApiKeys := []struct {
-		UserID     int64     `db:"user_id"`
-		ApiKey     string    `db:"api_key"`
-		ValidUntil time.Time `db:"valid_until"`
-		ChangedAt  time.Time `db:"changed_at"`
+		UserID     int64          `db:"user_id"`
+		ApiKey     string         `db:"api_key"`
+		Scopes     pq.StringArray `db:"scopes"`
+		AllowedIPs pq.StringArray `db:"allowed_ips"`
+		ValidUntil time.Time      `db:"valid_until"`
+		ChangedAt  time.Time      `db:"changed_at"`
 	}{}
 
-	err = tx.Select(&dbApiKeys, `SELECT user_id, api_key, valid_until, changed_at FROM api_keys WHERE changed_at > $1 OR valid_until < NOW()`, lastTKeys)
+	err = tx.Select(&dbApiKeys, `SELECT user_id, api_key, scopes, allowed_ips, valid_until, changed_at FROM api_keys WHERE changed_at > $1 OR valid_until < NOW()`, lastTKeys)
 	if err != nil {
 		return fmt.Errorf("error getting api_keys: %w", err)
 	}
@@ -647,11 +685,7 @@ func updateRateLimits() error {
 		if dbKey.ChangedAt.After(lastTKeys) {
 			lastTKeys = dbKey.ChangedAt
 		}
-		if dbKey.ValidUntil.Before(now) {
-			delete(userIdByApiKey, dbKey.ApiKey)
-			continue
-		}
-		userIdByApiKey[dbKey.ApiKey] = dbKey.UserID
+		apiKeys[dbKey.ApiKey] = newApiKey(dbKey.UserID, dbKey.Scopes, dbKey.AllowedIPs, dbKey.ValidUntil)
 	}
 
 	for _, dbRl := range dbRateLimits {
@@ -686,7 +720,7 @@ func updateRateLimits() error {
 	return nil
 }
 
-// postRateLimit decrements the rate limit keys in redis if the status is not 200.
+// postRateLimit decrements the rate limit counters in the store if the status is not 200.
 func postRateLimit(rl *RateLimitResult, status int) error {
 	if !(status >= 500 && status <= 599) && status != 429 {
 		// any statuscode but 5xx or 429 will count towards the ratelimit
@@ -694,7 +728,6 @@ func postRateLimit(rl *RateLimitResult, status int) error {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	pipe := redisClient.Pipeline()
 
 	decrByWeight := rl.Weight
 	mbrw := GetMaxBadRquestWeight()
@@ -702,19 +735,22 @@ func postRateLimit(rl *RateLimitResult, status int) error {
 		decrByWeight = mbrw
 	}
 
+	counters := make([]Counter, 0, len(rl.RedisKeys)+1)
 	for _, k := range rl.RedisKeys {
-		pipe.DecrBy(ctx, k.Key, decrByWeight)
-		pipe.ExpireAt(ctx, k.Key, k.ExpireAt) // make sure all keys have a TTL
+		counters = append(counters, Counter{Key: k.Key, Delta: -decrByWeight, ExpireAt: k.ExpireAt}) // make sure all keys have a TTL
 	}
-	pipe.DecrBy(ctx, rl.RedisStatsKey, 1)
-	_, err := pipe.Exec(ctx)
+	counters = append(counters, Counter{Key: rl.RedisStatsKey, Delta: -1})
+	if status == http.StatusTooManyRequests {
+		counters = append(counters, Counter{Key: blockedStatsKeyPrefix + strings.TrimPrefix(rl.RedisStatsKey, statsKeyPrefix), Delta: 1})
+	}
+	_, err := store.Incr(ctx, counters)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// rateLimitRequest is the main function for rate limiting, it will check the rate limits for the request and update the rate limits in redis.
+// rateLimitRequest is the main function for rate limiting, it will check the rate limits for the request and update the rate limits in the store.
 func rateLimitRequest(r *http.Request) (*RateLimitResult, error) {
 	start := time.Now()
 	defer func() {
@@ -739,15 +775,15 @@ func rateLimitRequest(r *http.Request) (*RateLimitResult, error) {
 	nokeyRatelimit, freeRatelimit := getDefaultRatelimit(bucket)
 
 	rateLimitsMu.RLock()
-	userId, ok := userIdByApiKey[key]
-	if !ok {
+	apiKey, ok := apiKeys[key]
+	if !ok || !apiKey.ValidUntil.After(start) {
 		res.UserId = -1
 		res.IsValidKey = false
 		res.RateLimit = nokeyRatelimit
 	} else {
-		res.UserId = userId
+		res.UserId = apiKey.UserId
 		res.IsValidKey = true
-		limit, ok := rateLimitsByUserId[fmt.Sprintf("%s/%d", bucket, userId)]
+		limit, ok := rateLimitsByUserId[fmt.Sprintf("%s/%d", bucket, apiKey.UserId)]
 		if ok {
 			res.RateLimit = limit
 		} else {
@@ -767,57 +803,71 @@ func rateLimitRequest(r *http.Request) (*RateLimitResult, error) {
 
 	rateLimitSecondKey := fmt.Sprintf("rl:c:s:%s:%d", res.Bucket, res.UserId)
 	rateLimitHourKey := fmt.Sprintf("rl:c:h:%04d-%02d-%02d-%02d:%s:%d", startUtc.Year(), startUtc.Month(), startUtc.Day(), startUtc.Hour(), res.Bucket, res.UserId)
-	rateLimitMonthKey := fmt.Sprintf("rl:c:m:%04d-%02d:%s:%d", startUtc.Year(), startUtc.Month(), res.Bucket, res.UserId)
+	rateLimitMonthKey := monthCounterKey(startUtc, res.Bucket, strconv.FormatInt(res.UserId, 10))
 	statsKey := fmt.Sprintf("rl:s:%04d-%02d-%02d-%02d:%d:%s:%s:%s", startUtc.Year(), startUtc.Month(), startUtc.Day(), startUtc.Hour(), res.UserId, res.Key, res.Route, res.Bucket)
 	if !res.IsValidKey {
 		normedIP := "ip_" + strings.ReplaceAll(ip, ":", "_")
 		rateLimitSecondKey = fmt.Sprintf("rl:c:s:%s:%s", res.Bucket, normedIP)
 		rateLimitHourKey = fmt.Sprintf("rl:c:h:%04d-%02d-%02d-%02d:%s:%s", startUtc.Year(), startUtc.Month(), startUtc.Day(), startUtc.Hour(), res.Bucket, normedIP)
-		rateLimitMonthKey = fmt.Sprintf("rl:c:m:%04d-%02d:%s:%s", startUtc.Year(), startUtc.Month(), res.Bucket, normedIP)
+		rateLimitMonthKey = monthCounterKey(startUtc, res.Bucket, normedIP)
 		statsKey = fmt.Sprintf("rl:s:%04d-%02d-%02d-%02d:%d:%s:%s:%s", startUtc.Year(), startUtc.Month(), startUtc.Day(), startUtc.Hour(), res.UserId, "nokey", res.Route, res.Bucket)
 	}
 	res.RedisStatsKey = statsKey
 
-	pipe := redisClient.Pipeline()
-
-	var rateLimitSecond, rateLimitHour, rateLimitMonth *redis.IntCmd
+	var rateLimitSecond, rateLimitHour, rateLimitMonth, statsCount int64
+	counters := make([]Counter, 0, 4)
+	counterValues := make([]*int64, 0, 4) // where the new values of the counters are stored
 
 	if res.RateLimit.Second > 0 {
-		rateLimitSecond = pipe.IncrBy(ctx, rateLimitSecondKey, weight)
-		pipe.ExpireNX(ctx, rateLimitSecondKey, time.Second)
+		counterValues = append(counterValues, &rateLimitSecond)
+		counters = append(counters, Counter{Key: rateLimitSecondKey, Delta: weight, TTL: time.Second})
 	}
 
 	if res.RateLimit.Hour > 0 {
-		rateLimitHour = pipe.IncrBy(ctx, rateLimitHourKey, weight)
-		pipe.ExpireAt(ctx, rateLimitHourKey, nextHourUtc.Add(time.Second*60)) // expire 1 minute after the window to make sure we do not miss any requests due to time-sync
+		counterValues = append(counterValues, &rateLimitHour)
+		counters = append(counters, Counter{Key: rateLimitHourKey, Delta: weight, ExpireAt: nextHourUtc.Add(time.Second * 60)}) // expire 1 minute after the window to make sure we do not miss any requests due to time-sync
 		res.RedisKeys = append(res.RedisKeys, RedisKey{rateLimitHourKey, nextHourUtc.Add(time.Second * 60)})
 	}
 
 	if res.RateLimit.Month > 0 {
-		rateLimitMonth = pipe.IncrBy(ctx, rateLimitMonthKey, weight)
-		pipe.ExpireAt(ctx, rateLimitMonthKey, nextMonthUtc.Add(time.Second*60)) // expire 1 minute after the window to make sure we do not miss any requests due to time-sync
+		counterValues = append(counterValues, &rateLimitMonth)
+		counters = append(counters, Counter{Key: rateLimitMonthKey, Delta: weight, ExpireAt: nextMonthUtc.Add(time.Second * 60)}) // expire 1 minute after the window to make sure we do not miss any requests due to time-sync
 		res.RedisKeys = append(res.RedisKeys, RedisKey{rateLimitMonthKey, nextMonthUtc.Add(time.Second * 60)})
+
+		if res.IsValidKey {
+			// the requests of the month per api key are only counted for the quota alerts of the keys, the limit applies to all keys of the user
+			var keyMonthCount int64
+			keyMonthKey := monthKeyCounterKey(startUtc, res.Bucket, res.Key)
+			counterValues = append(counterValues, &keyMonthCount)
+			counters = append(counters, Counter{Key: keyMonthKey, Delta: weight, ExpireAt: nextMonthUtc.Add(time.Second * 60)})
+			res.RedisKeys = append(res.RedisKeys, RedisKey{keyMonthKey, nextMonthUtc.Add(time.Second * 60)})
+		}
 	}
 
-	pipe.Incr(ctx, statsKey)
-	_, err := pipe.Exec(ctx)
+	counterValues = append(counterValues, &statsCount)
+	counters = append(counters, Counter{Key: statsKey, Delta: 1})
+
+	values, err := store.Incr(ctx, counters)
 	if err != nil {
 		return nil, err
 	}
+	for i, v := range values {
+		*counterValues[i] = v
+	}
 
-	if res.RateLimit.Month > 0 && rateLimitMonth.Val() > res.RateLimit.Month {
+	if res.RateLimit.Month > 0 && rateLimitMonth > res.RateLimit.Month {
 		res.Limit = res.RateLimit.Month
 		res.Remaining = 0
 		res.Reset = int64(timeUntilNextMonthUtc.Seconds())
 		res.Window = MonthTimeWindow
 		res.BlockRequest = true
-	} else if res.RateLimit.Hour > 0 && rateLimitHour.Val() > res.RateLimit.Hour {
+	} else if res.RateLimit.Hour > 0 && rateLimitHour > res.RateLimit.Hour {
 		res.Limit = res.RateLimit.Hour
 		res.Remaining = 0
 		res.Reset = int64(timeUntilNextHourUtc.Seconds())
 		res.Window = HourTimeWindow
 		res.BlockRequest = true
-	} else if res.RateLimit.Second > 0 && rateLimitSecond.Val() > res.RateLimit.Second {
+	} else if res.RateLimit.Second > 0 && rateLimitSecond > res.RateLimit.Second {
 		res.Limit = res.RateLimit.Second
 		res.Remaining = 0
 		res.Reset = int64(1)
@@ -825,25 +875,25 @@ func rateLimitRequest(r *http.Request) (*RateLimitResult, error) {
 		res.BlockRequest = true
 	} else {
 		res.Limit = res.RateLimit.Second
-		res.Remaining = res.RateLimit.Second - rateLimitSecond.Val()
+		res.Remaining = res.RateLimit.Second - rateLimitSecond
 		res.Reset = int64(1)
 		res.Window = SecondTimeWindow
 	}
 
 	if res.RateLimit.Second > 0 {
-		res.RemainingSecond = res.RateLimit.Second - rateLimitSecond.Val()
+		res.RemainingSecond = res.RateLimit.Second - rateLimitSecond
 		if res.RemainingSecond < 0 {
 			res.RemainingSecond = 0
 		}
 	}
 	if res.RateLimit.Hour > 0 {
-		res.RemainingHour = res.RateLimit.Hour - rateLimitHour.Val()
+		res.RemainingHour = res.RateLimit.Hour - rateLimitHour
 		if res.RemainingHour < 0 {
 			res.RemainingHour = 0
 		}
 	}
 	if res.RateLimit.Month > 0 {
-		res.RemainingMonth = res.RateLimit.Month - rateLimitMonth.Val()
+		res.RemainingMonth = res.RateLimit.Month - rateLimitMonth
 		if res.RemainingMonth < 0 {
 			res.RemainingMonth = 0
 		}
@@ -1042,18 +1092,36 @@ func (rl *FallbackRateLimiter) Handle(w http.ResponseWriter, r *http.Request, ne
 }
 
 func DBGetUserApiRateLimit(userId int64) (*RateLimit, error) {
+	return DBGetUserApiRateLimitForBucket(userId, defaultBucket)
+}
+
+// DBGetUserApiRateLimitForBucket returns the rate limit of a user in a bucket, users without rate limit in the bucket
+// have the limit of the free product
+func DBGetUserApiRateLimitForBucket(userId int64, bucket string) (*RateLimit, error) {
 	rl := &RateLimit{}
 	err := db.FrontendWriterDB.Get(rl, `
         select second, hour, month
         from api_ratelimits
-        where user_id = $1 and bucket = 'default'`, userId)
+        where user_id = $1 and bucket = $2`, userId, bucket)
 	if err != nil && err == sql.ErrNoRows {
-		_, freeRatelimit := getDefaultRatelimit("default")
+		_, freeRatelimit := getDefaultRatelimit(bucket)
 		return freeRatelimit, nil
 	}
 	return rl, err
 }
 
+// DBGetUserApiBuckets returns the buckets a user can send requests to, which are the buckets of the current api
+// products and of the rate limits of the user
+func DBGetUserApiBuckets(userId int64) ([]string, error) {
+	buckets := []string{}
+	err := db.FrontendWriterDB.Select(&buckets, `
+        select bucket from api_products where valid_from <= now()
+        union
+        select bucket from api_ratelimits where user_id = $1 and valid_until > now()
+        order by bucket`, userId)
+	return buckets, err
+}
+
 func DBGetCurrentApiProducts() ([]*ApiProduct, error) {
 	apiProducts := []*ApiProduct{}
 	err := db.FrontendWriterDB.Select(&apiProducts, `
@@ -1070,29 +1138,43 @@ func DBUpdater() {
 		logger.Warnf("updateInterval is below 1s, setting to 60s")
 		iv = time.Second * 60
 	}
-	logger.WithField("redis", utils.Config.RedisSessionStoreEndpoint).Infof("starting db updater")
-	redisClient = redis.NewClient(&redis.Options{
-		Addr:        utils.Config.RedisSessionStoreEndpoint,
-		ReadTimeout: time.Second * 3,
-	})
+	logger.WithField("store", utils.Config.Frontend.RatelimitStore).WithField("redis", utils.Config.RedisSessionStoreEndpoint).Infof("starting db updater")
+	store, err := newStoreFromConfig()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	if _, ok := store.(*MemoryCounterStore); ok {
+		// the in-process counters of the frontend are not visible here, the frontend writes the stats itself
+		logger.Warnf("ratelimit store is in-process, stats are updated by the frontend")
+		store = nil
+	}
 	for {
-		DBUpdate(redisClient)
+		DBUpdate(store)
 		time.Sleep(iv)
 	}
 }
 
-func DBUpdate(redisClient *redis.Client) {
+// DBUpdate writes the stats of the store to postgres and updates the api_keys and api_ratelimits, the stats are skipped if store is nil
+func DBUpdate(store CounterStore) {
 	wg := sync.WaitGroup{}
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
+		if store == nil {
+			return
+		}
 		start := time.Now()
-		err := updateStats(redisClient)
+		err := updateStats(store)
 		if err != nil {
 			logger.WithError(err).Errorf("error updating stats")
 			return
 		}
 		logger.WithField("duration", time.Since(start)).Infof("updated stats")
+
+		err = checkQuotaAlerts(store)
+		if err != nil {
+			logger.WithError(err).Errorf("error checking api quota alerts")
+		}
 	}()
 	go func() {
 		defer wg.Done()
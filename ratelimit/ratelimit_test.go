@@ -0,0 +1,231 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+// newTestRouter returns a router with the ratelimit middleware on top of an in-process store that limits requests
+// without api key by the given product, the handler responds with the status of the query param status
+func newTestRouter(t *testing.T, product *ApiProduct) *mux.Router {
+	store = NewMemoryCounterStore()
+	storeIsHealthy.Store(true)
+
+	apiProductsMu.Lock()
+	apiProducts = map[string]*ApiProduct{"default:nokey": product, "default:free": product}
+	apiProductsMu.Unlock()
+	t.Cleanup(func() {
+		apiProductsMu.Lock()
+		apiProducts = map[string]*ApiProduct{}
+		apiProductsMu.Unlock()
+	})
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/test", func(w http.ResponseWriter, r *http.Request) {
+		status, err := strconv.Atoi(r.URL.Query().Get("status"))
+		if err != nil {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+	})
+	router.Use(HttpMiddleware)
+	return router
+}
+
+func TestHttpMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		product    ApiProduct
+		statuses   []int // statuses returned by the handler
+		wantCodes  []int
+		wantWindow string
+	}{
+		{
+			name:       "second",
+			product:    ApiProduct{Second: 2, Hour: 100},
+			statuses:   []int{200, 200, 200},
+			wantCodes:  []int{200, 200, 429},
+			wantWindow: SecondTimeWindow,
+		},
+		{
+			name:       "hour",
+			product:    ApiProduct{Second: 100, Hour: 2},
+			statuses:   []int{200, 200, 200},
+			wantCodes:  []int{200, 200, 429},
+			wantWindow: HourTimeWindow,
+		},
+		{
+			name:       "month",
+			product:    ApiProduct{Second: 100, Hour: 100, Month: 2},
+			statuses:   []int{200, 200, 200},
+			wantCodes:  []int{200, 200, 429},
+			wantWindow: MonthTimeWindow,
+		},
+		{
+			name:       "server errors do not count",
+			product:    ApiProduct{Second: 100, Hour: 2},
+			statuses:   []int{500, 500, 200, 200, 200},
+			wantCodes:  []int{500, 500, 200, 200, 429},
+			wantWindow: HourTimeWindow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(t, &tt.product)
+
+			var rec *httptest.ResponseRecorder
+			for i, status := range tt.statuses {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/test?status="+strconv.Itoa(status), nil)
+				rec = httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				if rec.Code != tt.wantCodes[i] {
+					t.Fatalf("got status %v for request %v, want %v", rec.Code, i, tt.wantCodes[i])
+				}
+			}
+			if window := rec.Header().Get(HeaderRateLimitWindow); window != tt.wantWindow {
+				t.Errorf("got window %v, want %v", window, tt.wantWindow)
+			}
+			if remaining := rec.Header().Get(HeaderRateLimitRemaining); remaining != "0" {
+				t.Errorf("got %v remaining requests, want 0", remaining)
+			}
+		})
+	}
+}
+
+func TestHttpMiddlewareFallback(t *testing.T) {
+	router := newTestRouter(t, &ApiProduct{Second: 1, Hour: 1})
+	storeIsHealthy.Store(false)
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/test", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %v from the fallback rate limiter, want 200", rec.Code)
+		}
+	}
+}
+
+func TestGetStatsEntries(t *testing.T) {
+	router := newTestRouter(t, &ApiProduct{Second: 100, Hour: 3})
+	for _, status := range []int{200, 200, 500, 200, 200} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/test?status="+strconv.Itoa(status), nil))
+	}
+
+	ctx := context.Background()
+	lastHour := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+	_, err := store.Incr(ctx, []Counter{
+		{Key: "rl:s:" + lastHour.Format("2006-01-02-15") + ":7:somekey:/api/v1/test:default", Delta: 5},
+		{Key: "rl:s:" + lastHour.Format("2006-01-02-15") + ":7:somekey:/api/v1/old", Delta: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, keysToDelete, err := getStatsEntries(ctx, store, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %v entries, want 3", len(entries))
+	}
+	if len(keysToDelete) != 2 {
+		t.Errorf("got %v keys to delete, want the 2 keys of the last hour", len(keysToDelete))
+	}
+
+	got := map[string]DbEntry{}
+	for _, e := range entries {
+		got[e.ApiKey+e.Endpoint] = e
+	}
+	if e := got["nokey/api/v1/test"]; e.UserId != -1 || e.Count != 3 || e.Blocked != 1 || e.Bucket != defaultBucket || !e.Date.Equal(time.Now().UTC().Truncate(time.Hour)) {
+		t.Errorf("got %+v, want 3 requests and 1 blocked request of user -1 in the current hour", e)
+	}
+	if e := got["somekey/api/v1/test"]; e.UserId != 7 || e.Count != 5 || e.Bucket != defaultBucket || !e.Date.Equal(lastHour) {
+		t.Errorf("got %+v, want 5 requests of user 7 in the last hour", e)
+	}
+	if e := got["somekey/api/v1/old"]; e.Count != 3 || e.Bucket != "x" {
+		t.Errorf("got %+v, want 3 requests in bucket x", e)
+	}
+}
+
+func TestQuotaAlertDue(t *testing.T) {
+	tests := []struct {
+		used      int64
+		limit     int64
+		threshold float64
+		want      bool
+	}{
+		{used: 79, limit: 100, threshold: 0.8, want: false},
+		{used: 80, limit: 100, threshold: 0.8, want: true},
+		{used: 150, limit: 100, threshold: 1, want: true},
+		{used: 100, limit: 0, threshold: 0.8, want: false},
+		{used: 100, limit: 100, threshold: 0, want: false},
+	}
+	for _, tt := range tests {
+		if got := quotaAlertDue(tt.used, tt.limit, tt.threshold); got != tt.want {
+			t.Errorf("got %v for %v of %v requests with threshold %v, want %v", got, tt.used, tt.limit, tt.threshold, tt.want)
+		}
+	}
+}
+
+func TestQuotaAlertMessage(t *testing.T) {
+	previous := utils.Config
+	t.Cleanup(func() { utils.Config = previous })
+	utils.Config = &types.Config{}
+	utils.Config.Frontend.SiteDomain = "beaconcha.in"
+
+	msg := quotaAlertMessage(&quotaAlertKey{ApiKey: "eeee5555ffff6666", Name: "indexer", Bucket: "default", Limit: 100}, 90, 95)
+
+	for _, want := range []string{"API key indexer (eeee...6666) has used 90 of 100 requests (90%)", "in the default bucket", "together they have used 95 requests"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("got message %q, want it to contain %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, "eeee5555ffff6666") {
+		t.Errorf("got message %q, want the api key to be masked", msg)
+	}
+}
+
+func TestMonthlyKeyCounter(t *testing.T) {
+	router := newTestRouter(t, &ApiProduct{Second: 100, Hour: 100, Month: 100})
+	rateLimitsMu.Lock()
+	apiKeys = map[string]*ApiKey{
+		"key1": newApiKey(7, pq.StringArray{ScopePublicRead}, nil, time.Now().Add(time.Hour)),
+		"key2": newApiKey(7, pq.StringArray{ScopePublicRead}, nil, time.Now().Add(time.Hour)),
+	}
+	rateLimitsMu.Unlock()
+	t.Cleanup(func() {
+		rateLimitsMu.Lock()
+		apiKeys = map[string]*ApiKey{}
+		rateLimitsMu.Unlock()
+	})
+
+	// failed requests do not count towards the limit
+	for _, target := range []string{"/api/v1/test?apikey=key1", "/api/v1/test?apikey=key1", "/api/v1/test?apikey=key1&status=500", "/api/v1/test?apikey=key2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	now := time.Now().UTC()
+	values, err := store.Get(context.Background(), []string{
+		monthKeyCounterKey(now, defaultBucket, "key1"),
+		monthKeyCounterKey(now, defaultBucket, "key2"),
+		monthCounterKey(now, defaultBucket, "7"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0] != 2 || values[1] != 1 || values[2] != 3 {
+		t.Errorf("got monthly counters %v for key1, key2 and the user, want [2 1 3]", values)
+	}
+}
@@ -0,0 +1,387 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	RedisStore    = "redis"
+	PostgresStore = "postgres"
+	MemoryStore   = "memory"
+)
+
+// CounterStore stores the ratelimit and stats counters. All implementations have the same semantics as a redis
+// counter with INCRBY, EXPIREAT and EXPIRE NX: an expired counter starts again at zero.
+type CounterStore interface {
+	// Incr adds the deltas to the counters and returns the new values in the same order
+	Incr(ctx context.Context, counters []Counter) ([]int64, error)
+	// Get returns the values of the counters in the same order, missing or expired counters are zero
+	Get(ctx context.Context, keys []string) ([]int64, error)
+	// Scan returns the values of all counters with the given prefix that are not expired
+	Scan(ctx context.Context, prefix string) (map[string]int64, error)
+	// Del deletes the given counters
+	Del(ctx context.Context, keys []string) error
+	// DeleteExpired removes expired counters, stores that expire counters by themselves do nothing
+	DeleteExpired(ctx context.Context) error
+	Ping(ctx context.Context) error
+}
+
+type Counter struct {
+	Key   string
+	Delta int64
+	// ExpireAt sets the expiration of the counter, if zero the expiration is not changed
+	ExpireAt time.Time
+	// TTL sets the expiration of the counter only if it has none yet, like EXPIRE NX
+	TTL time.Duration
+}
+
+// NewCounterStore returns the store with the given name, an empty name returns the redis store
+func NewCounterStore(name string, redisClient *redis.Client, postgres *sqlx.DB) (CounterStore, error) {
+	switch name {
+	case RedisStore, "":
+		return NewRedisCounterStore(redisClient), nil
+	case PostgresStore:
+		return NewPostgresCounterStore(postgres), nil
+	case MemoryStore:
+		return NewMemoryCounterStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown ratelimit store: %v", name)
+	}
+}
+
+type RedisCounterStore struct {
+	client *redis.Client
+}
+
+func NewRedisCounterStore(client *redis.Client) *RedisCounterStore {
+	return &RedisCounterStore{client: client}
+}
+
+func (s *RedisCounterStore) Incr(ctx context.Context, counters []Counter) ([]int64, error) {
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(counters))
+	for i, c := range counters {
+		cmds[i] = pipe.IncrBy(ctx, c.Key, c.Delta)
+		if !c.ExpireAt.IsZero() {
+			pipe.ExpireAt(ctx, c.Key, c.ExpireAt)
+		} else if c.TTL > 0 {
+			pipe.ExpireNX(ctx, c.Key, c.TTL)
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]int64, len(cmds))
+	for i, cmd := range cmds {
+		values[i] = cmd.Val()
+	}
+	return values, nil
+}
+
+func (s *RedisCounterStore) Get(ctx context.Context, keys []string) ([]int64, error) {
+	values := make([]int64, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+	res, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range res {
+		vStr, ok := v.(string)
+		if !ok {
+			continue
+		}
+		values[i], err = strconv.ParseInt(vStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing counter %v from redis: value is not int64: %v: %w", keys[i], v, err)
+		}
+	}
+	return values, nil
+}
+
+func (s *RedisCounterStore) Scan(ctx context.Context, prefix string) (map[string]int64, error) {
+	allKeys := []string{}
+	cursor := uint64(0)
+	for {
+		keys, nextCursor, err := s.client.Scan(ctx, cursor, prefix+"*", 1000).Result()
+		if err != nil {
+			return nil, err
+		}
+		cursor = nextCursor
+		allKeys = append(allKeys, keys...)
+		if cursor == 0 {
+			break
+		}
+	}
+
+	values := make(map[string]int64, len(allKeys))
+	mgetSize := 500
+	for start := 0; start < len(allKeys); start += mgetSize {
+		end := start + mgetSize
+		if end > len(allKeys) {
+			end = len(allKeys)
+		}
+		res, err := s.client.MGet(ctx, allKeys[start:end]...).Result()
+		if err != nil {
+			return nil, fmt.Errorf("error getting counters from redis (%v-%v/%v): %w", start, end, len(allKeys), err)
+		}
+		for i, v := range res {
+			if v == nil {
+				// the key expired or was deleted since the scan
+				continue
+			}
+			vStr, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("error parsing counter %v from redis: value is not string: %v", allKeys[start+i], v)
+			}
+			value, err := strconv.ParseInt(vStr, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing counter %v from redis: value is not int64: %v: %w", allKeys[start+i], v, err)
+			}
+			values[allKeys[start+i]] = value
+		}
+	}
+	return values, nil
+}
+
+func (s *RedisCounterStore) Del(ctx context.Context, keys []string) error {
+	delSize := 500
+	for start := 0; start < len(keys); start += delSize {
+		end := start + delSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		err := s.client.Del(ctx, keys[start:end]...).Err()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RedisCounterStore) DeleteExpired(ctx context.Context) error {
+	return nil
+}
+
+func (s *RedisCounterStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// PostgresCounterStore stores the counters in the table ratelimit_counters, it can be shared by multiple replicas
+type PostgresCounterStore struct {
+	db  *sqlx.DB
+	now func() time.Time
+}
+
+func NewPostgresCounterStore(db *sqlx.DB) *PostgresCounterStore {
+	return &PostgresCounterStore{db: db, now: time.Now}
+}
+
+func (s *PostgresCounterStore) Incr(ctx context.Context, counters []Counter) ([]int64, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := s.now().UTC()
+	values := make([]int64, len(counters))
+	for i, c := range counters {
+		// an expired counter is reset, the expiration is overwritten if ExpireAt is set and only set if there is
+		// none yet otherwise
+		var expireAt *time.Time
+		overwrite := false
+		if !c.ExpireAt.IsZero() {
+			t := c.ExpireAt.UTC()
+			expireAt = &t
+			overwrite = true
+		} else if c.TTL > 0 {
+			t := now.Add(c.TTL)
+			expireAt = &t
+		}
+		err = tx.GetContext(ctx, &values[i], `
+			INSERT INTO ratelimit_counters (key, value, expire_at) VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO UPDATE SET
+				value = CASE WHEN ratelimit_counters.expire_at <= $4 THEN EXCLUDED.value ELSE ratelimit_counters.value + EXCLUDED.value END,
+				expire_at = CASE WHEN $5 OR ratelimit_counters.expire_at IS NULL OR ratelimit_counters.expire_at <= $4 THEN EXCLUDED.expire_at ELSE ratelimit_counters.expire_at END
+			RETURNING value`, c.Key, c.Delta, expireAt, now, overwrite)
+		if err != nil {
+			return nil, fmt.Errorf("error incrementing counter %v: %w", c.Key, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (s *PostgresCounterStore) Get(ctx context.Context, keys []string) ([]int64, error) {
+	values := make([]int64, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+	rows := []struct {
+		Key   string `db:"key"`
+		Value int64  `db:"value"`
+	}{}
+	err := s.db.SelectContext(ctx, &rows, `
+		SELECT key, value FROM ratelimit_counters
+		WHERE key = ANY($1) AND (expire_at IS NULL OR expire_at > $2)`, pq.StringArray(keys), s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		byKey[r.Key] = r.Value
+	}
+	for i, k := range keys {
+		values[i] = byKey[k]
+	}
+	return values, nil
+}
+
+func (s *PostgresCounterStore) Scan(ctx context.Context, prefix string) (map[string]int64, error) {
+	rows := []struct {
+		Key   string `db:"key"`
+		Value int64  `db:"value"`
+	}{}
+	err := s.db.SelectContext(ctx, &rows, `
+		SELECT key, value FROM ratelimit_counters
+		WHERE starts_with(key, $1) AND (expire_at IS NULL OR expire_at > $2)`, prefix, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		values[r.Key] = r.Value
+	}
+	return values, nil
+}
+
+func (s *PostgresCounterStore) Del(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := s.db.ExecContext(ctx, `DELETE FROM ratelimit_counters WHERE key = ANY($1)`, pq.StringArray(keys))
+	return err
+}
+
+func (s *PostgresCounterStore) DeleteExpired(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM ratelimit_counters WHERE expire_at <= $1`, s.now().UTC())
+	return err
+}
+
+func (s *PostgresCounterStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// MemoryCounterStore keeps the counters in the memory of the process, it must only be used with a single replica
+type MemoryCounterStore struct {
+	mu       sync.Mutex
+	counters map[string]*memoryCounter
+	now      func() time.Time
+}
+
+type memoryCounter struct {
+	value    int64
+	expireAt time.Time // zero if the counter does not expire
+}
+
+func NewMemoryCounterStore() *MemoryCounterStore {
+	return &MemoryCounterStore{counters: map[string]*memoryCounter{}, now: time.Now}
+}
+
+func (c *memoryCounter) expired(now time.Time) bool {
+	return !c.expireAt.IsZero() && !c.expireAt.After(now)
+}
+
+func (s *MemoryCounterStore) Incr(ctx context.Context, counters []Counter) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	values := make([]int64, len(counters))
+	for i, c := range counters {
+		counter, ok := s.counters[c.Key]
+		if !ok || counter.expired(now) {
+			counter = &memoryCounter{}
+			s.counters[c.Key] = counter
+		}
+		counter.value += c.Delta
+		if !c.ExpireAt.IsZero() {
+			counter.expireAt = c.ExpireAt
+		} else if c.TTL > 0 && counter.expireAt.IsZero() {
+			counter.expireAt = now.Add(c.TTL)
+		}
+		values[i] = counter.value
+	}
+	return values, nil
+}
+
+func (s *MemoryCounterStore) Get(ctx context.Context, keys []string) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	values := make([]int64, len(keys))
+	for i, k := range keys {
+		if c, ok := s.counters[k]; ok && !c.expired(now) {
+			values[i] = c.value
+		}
+	}
+	return values, nil
+}
+
+func (s *MemoryCounterStore) Scan(ctx context.Context, prefix string) (map[string]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	values := map[string]int64{}
+	for k, c := range s.counters {
+		if strings.HasPrefix(k, prefix) && !c.expired(now) {
+			values[k] = c.value
+		}
+	}
+	return values, nil
+}
+
+func (s *MemoryCounterStore) Del(ctx context.Context, keys []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, k := range keys {
+		delete(s.counters, k)
+	}
+	return nil
+}
+
+func (s *MemoryCounterStore) DeleteExpired(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, c := range s.counters {
+		if c.expired(now) {
+			delete(s.counters, k)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryCounterStore) Ping(ctx context.Context) error {
+	return nil
+}
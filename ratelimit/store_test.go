@@ -0,0 +1,208 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) now() time.Time {
+	return c.t
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func TestMemoryCounterStore(t *testing.T) {
+	clock := &testClock{t: time.Date(2025, 10, 29, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryCounterStore()
+	store.now = clock.now
+	testCounterStore(t, store, clock.now, clock.advance)
+}
+
+func TestPostgresCounterStore(t *testing.T) {
+	dsn, exists := os.LookupEnv("RATELIMIT_TEST_POSTGRES")
+	if !exists {
+		t.Skip()
+	}
+	db, err := sqlx.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	_, err = db.Exec(`TRUNCATE ratelimit_counters`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &testClock{t: time.Date(2025, 10, 29, 12, 0, 0, 0, time.UTC)}
+	store := NewPostgresCounterStore(db)
+	store.now = clock.now
+	testCounterStore(t, store, clock.now, clock.advance)
+}
+
+func TestRedisCounterStore(t *testing.T) {
+	addr, exists := os.LookupEnv("RATELIMIT_TEST_REDIS")
+	if !exists {
+		t.Skip()
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	err := client.FlushDB(context.Background()).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// redis expires keys by its own clock
+	testCounterStore(t, NewRedisCounterStore(client), time.Now, time.Sleep)
+}
+
+// testCounterStore checks the semantics that every CounterStore must provide
+func testCounterStore(t *testing.T, store CounterStore, now func() time.Time, advance func(time.Duration)) {
+	ctx := context.Background()
+
+	incr := func(c Counter) int64 {
+		t.Helper()
+		values, err := store.Incr(ctx, []Counter{c})
+		if err != nil {
+			t.Fatalf("error incrementing %v: %v", c.Key, err)
+		}
+		return values[0]
+	}
+	scan := func(prefix string) map[string]int64 {
+		t.Helper()
+		values, err := store.Scan(ctx, prefix)
+		if err != nil {
+			t.Fatalf("error scanning %v: %v", prefix, err)
+		}
+		return values
+	}
+
+	err := store.Ping(ctx)
+	if err != nil {
+		t.Fatalf("error pinging store: %v", err)
+	}
+
+	t.Run("multiple counters", func(t *testing.T) {
+		values, err := store.Incr(ctx, []Counter{{Key: "test:m:a", Delta: 2}, {Key: "test:m:b", Delta: 3}, {Key: "test:m:a", Delta: 4}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 3 || values[0] != 2 || values[1] != 3 || values[2] != 6 {
+			t.Errorf("got %v, want [2 3 6]", values)
+		}
+
+		values, err = store.Get(ctx, []string{"test:m:b", "test:m:missing", "test:m:a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 3 || values[0] != 3 || values[1] != 0 || values[2] != 6 {
+			t.Errorf("got %v, want [3 0 6]", values)
+		}
+	})
+
+	t.Run("ttl is only set once", func(t *testing.T) {
+		// like the per-second window: INCRBY and EXPIRE NX
+		if v := incr(Counter{Key: "test:s", Delta: 1, TTL: time.Second}); v != 1 {
+			t.Errorf("got %v, want 1", v)
+		}
+		advance(time.Millisecond * 600)
+		if v := incr(Counter{Key: "test:s", Delta: 2, TTL: time.Second}); v != 3 {
+			t.Errorf("got %v, want 3", v)
+		}
+		advance(time.Millisecond * 600)
+		if v := incr(Counter{Key: "test:s", Delta: 1, TTL: time.Second}); v != 1 {
+			t.Errorf("got %v after the ttl of the first increment, want 1", v)
+		}
+	})
+
+	t.Run("expire at is overwritten", func(t *testing.T) {
+		// like the hour and month windows: INCRBY and EXPIREAT
+		if v := incr(Counter{Key: "test:h", Delta: 5, ExpireAt: now().Add(time.Second)}); v != 5 {
+			t.Errorf("got %v, want 5", v)
+		}
+		if v := incr(Counter{Key: "test:h", Delta: -2, ExpireAt: now().Add(time.Second * 2)}); v != 3 {
+			t.Errorf("got %v, want 3", v)
+		}
+		advance(time.Millisecond * 1500)
+		if v := scan("test:h")["test:h"]; v != 3 {
+			t.Errorf("got %v before the overwritten expiration, want 3", v)
+		}
+		advance(time.Second)
+		if v, ok := scan("test:h")["test:h"]; ok {
+			t.Errorf("got %v after the expiration, want no counter", v)
+		}
+		if values, err := store.Get(ctx, []string{"test:h"}); err != nil || values[0] != 0 {
+			t.Errorf("got %v, %v after the expiration, want 0", values, err)
+		}
+		if v := incr(Counter{Key: "test:h", Delta: 1, ExpireAt: now().Add(time.Second)}); v != 1 {
+			t.Errorf("got %v after the expiration, want 1", v)
+		}
+	})
+
+	t.Run("counters without expiration", func(t *testing.T) {
+		// like the stats: INCR and DECRBY without expiration
+		incr(Counter{Key: "test:stats:a", Delta: 1})
+		incr(Counter{Key: "test:stats:a", Delta: 1})
+		incr(Counter{Key: "test:stats:a", Delta: -1})
+		incr(Counter{Key: "test:stats:b", Delta: 1})
+		incr(Counter{Key: "test:other", Delta: 1})
+
+		values := scan("test:stats:")
+		if len(values) != 2 || values["test:stats:a"] != 1 || values["test:stats:b"] != 1 {
+			t.Errorf("got %v, want test:stats:a=1 and test:stats:b=1", values)
+		}
+
+		err := store.Del(ctx, []string{"test:stats:a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = scan("test:stats:")
+		if len(values) != 1 || values["test:stats:b"] != 1 {
+			t.Errorf("got %v after deleting test:stats:a, want test:stats:b=1", values)
+		}
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		incr(Counter{Key: "test:e:expired", Delta: 1, TTL: time.Second})
+		incr(Counter{Key: "test:e:alive", Delta: 1})
+		advance(time.Millisecond * 1100)
+		err := store.DeleteExpired(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := scan("test:e:")
+		if len(values) != 1 || values["test:e:alive"] != 1 {
+			t.Errorf("got %v, want test:e:alive=1", values)
+		}
+	})
+}
+
+func TestMemoryCounterStoreDeleteExpired(t *testing.T) {
+	clock := &testClock{t: time.Date(2025, 10, 29, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryCounterStore()
+	store.now = clock.now
+
+	_, err := store.Incr(context.Background(), []Counter{{Key: "a", Delta: 1, TTL: time.Second}, {Key: "b", Delta: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.advance(time.Second)
+	err = store.DeleteExpired(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.counters) != 1 || store.counters["b"] == nil {
+		t.Errorf("got %v counters, want only b", len(store.counters))
+	}
+}
@@ -0,0 +1,157 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/mail"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// monthCounterKey returns the key of the counter of the monthly window of a user or ip
+func monthCounterKey(t time.Time, bucket, id string) string {
+	return fmt.Sprintf("rl:c:m:%04d-%02d:%s:%s", t.Year(), t.Month(), bucket, id)
+}
+
+// monthKeyCounterKey returns the key of the counter of the monthly window of an api key
+func monthKeyCounterKey(t time.Time, bucket, apiKey string) string {
+	return monthCounterKey(t, bucket, "key_"+apiKey)
+}
+
+// GetMonthlyUsage returns the weighted requests of a user in the current month as counted by the rate limiter. The
+// counter only exists if the user has a monthly limit in the bucket.
+func GetMonthlyUsage(userId int64, bucket string) (int64, error) {
+	if store == nil {
+		return 0, fmt.Errorf("ratelimit store is not initialized")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	values, err := store.Get(ctx, []string{monthCounterKey(time.Now().UTC(), bucket, strconv.FormatInt(userId, 10))})
+	if err != nil {
+		return 0, fmt.Errorf("error getting monthly usage of user %v: %w", userId, err)
+	}
+	return values[0], nil
+}
+
+// quotaAlertDue returns true if the used requests crossed the given share of the monthly limit
+func quotaAlertDue(used, limit int64, threshold float64) bool {
+	return limit > 0 && threshold > 0 && float64(used) >= float64(limit)*threshold
+}
+
+// quotaAlertKey is an api key whose requests of the month are checked against the monthly limit of its owner
+type quotaAlertKey struct {
+	UserId int64  `db:"user_id"`
+	Email  string `db:"email"`
+	ApiKey string `db:"api_key"`
+	Name   string `db:"name"`
+	Bucket string `db:"bucket"`
+	Limit  int64  `db:"month"`
+}
+
+// quotaAlertMessage returns the text of the quota alert of an api key in a bucket, the requests of all keys of the
+// user are included as the limit is shared by them
+func quotaAlertMessage(k *quotaAlertKey, keyUsed, userUsed int64) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Your API key %v (%v) has used %v of %v requests (%.0f%%) of the monthly limit of your account in the %v bucket.",
+		k.Name, MaskApiKey(k.ApiKey), keyUsed, k.Limit, float64(keyUsed)/float64(k.Limit)*100, k.Bucket)
+	fmt.Fprintf(&sb, " The limit is shared by all of your API keys, together they have used %v requests this month. Requests above the limit are answered with 429 until the start of next month.", userUsed)
+	fmt.Fprintf(&sb, "\n\nYou can see the usage per route and key at https://%v/user/api-usage and upgrade your plan at https://%v/pricing",
+		utils.Config.Frontend.SiteDomain, utils.Config.Frontend.SiteDomain)
+	return sb.String()
+}
+
+// checkQuotaAlerts emails the owners of the api keys whose requests of the current month crossed the configured share
+// of the monthly limit in a bucket. The limit is the limit of the user that is shared by all of their keys, the
+// requests of each key are counted by the rate limiter separately. Every key is alerted at most once per month and
+// bucket.
+func checkQuotaAlerts(store CounterStore) error {
+	threshold := utils.Config.RatelimitUpdater.QuotaAlertThreshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	keys := []*quotaAlertKey{}
+	// users without ratelimit have the limit of the free product of the bucket
+	err := db.FrontendWriterDB.Select(&keys, `
+		SELECT k.user_id, u.email, k.api_key, k.name, b.bucket, COALESCE(r.month, f.month, 0) AS month
+		FROM api_keys k
+		INNER JOIN users u ON u.id = k.user_id
+		CROSS JOIN (
+			SELECT bucket FROM api_products WHERE valid_from <= NOW()
+			UNION
+			SELECT bucket FROM api_ratelimits WHERE valid_until > NOW()
+		) b
+		LEFT JOIN api_ratelimits r ON r.user_id = k.user_id AND r.bucket = b.bucket AND r.valid_until > NOW()
+		LEFT JOIN LATERAL (
+			SELECT month FROM api_products
+			WHERE name = 'free' AND bucket = b.bucket AND valid_from <= NOW()
+			ORDER BY valid_from DESC LIMIT 1
+		) f ON TRUE
+		WHERE k.valid_until > NOW()
+			AND COALESCE(r.month, f.month, 0) > 0
+			AND NOT EXISTS (SELECT 1 FROM api_quota_alerts a WHERE a.user_id = k.user_id AND a.api_key = k.api_key AND a.bucket = b.bucket AND a.month = $1)`, month)
+	if err != nil {
+		return fmt.Errorf("error getting api keys with monthly limits: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	counterKeys := make([]string, len(keys))
+	for i, k := range keys {
+		counterKeys[i] = monthKeyCounterKey(now, k.Bucket, k.ApiKey)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	used, err := store.Get(ctx, counterKeys)
+	if err != nil {
+		return fmt.Errorf("error getting monthly counters of the api keys: %w", err)
+	}
+
+	for i, k := range keys {
+		if !quotaAlertDue(used[i], k.Limit, threshold) {
+			continue
+		}
+		res, err := db.FrontendWriterDB.Exec(`
+			INSERT INTO api_quota_alerts (user_id, api_key, bucket, month, used, quota) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id, api_key, bucket, month) DO NOTHING`, k.UserId, k.ApiKey, k.Bucket, month, used[i], k.Limit)
+		if err != nil {
+			return fmt.Errorf("error saving quota alert of user %v: %w", k.UserId, err)
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			// another updater sent the alert already
+			continue
+		}
+
+		userUsed, err := store.Get(ctx, []string{monthCounterKey(now, k.Bucket, strconv.FormatInt(k.UserId, 10))})
+		if err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{"userId": k.UserId}).Errorf("error getting the monthly counter of the user for the api quota alert")
+			userUsed = []int64{used[i]}
+		}
+
+		err = mail.SendTextMail(k.Email, "API usage alert", quotaAlertMessage(k, used[i], userUsed[0]), []types.EmailAttachment{})
+		if err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{"userId": k.UserId, "bucket": k.Bucket}).Errorf("error sending api quota alert")
+		}
+	}
+	return nil
+}
+
+// MaskApiKey shortens an api key so that it can be recognized by its owner without being usable
+func MaskApiKey(key string) string {
+	if len(key) <= 8 {
+		return key
+	}
+	return key[:4] + "..." + key[len(key)-4:]
+}
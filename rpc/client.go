@@ -1,3 +1,3 @@
 package rpc
 
-var CurrentClient *LighthouseClient
+var CurrentClient Client
@@ -47,6 +47,47 @@ type ErigonClient struct {
 
 var CurrentErigonClient *ErigonClient
 
+var (
+	// rawPebble is the pebble raw store shared by all erigon clients of the process, pebble locks its directory so it
+	// can only be opened once
+	rawPebble     *store.PebbleStore
+	rawPebblePath string
+	rawPebbleMu   sync.Mutex
+)
+
+// openRawPebble opens the pebble raw store at path or returns it if it is already open
+func openRawPebble(path string) (*store.PebbleStore, error) {
+	rawPebbleMu.Lock()
+	defer rawPebbleMu.Unlock()
+
+	if rawPebble != nil {
+		if rawPebblePath != path {
+			return nil, fmt.Errorf("pebble db %s is already open, can not open %s", rawPebblePath, path)
+		}
+		return rawPebble, nil
+	}
+	pebble, err := store.NewPebble(path)
+	if err != nil {
+		return nil, err
+	}
+	rawPebble, rawPebblePath = pebble, path
+	return rawPebble, nil
+}
+
+// CloseRawStore closes the pebble raw store of the erigon clients, it has to be called on shutdown after the clients
+// are no longer used
+func CloseRawStore() error {
+	rawPebbleMu.Lock()
+	defer rawPebbleMu.Unlock()
+
+	if rawPebble == nil {
+		return nil
+	}
+	err := rawPebble.Close()
+	rawPebble, rawPebblePath = nil, ""
+	return err
+}
+
 func NewErigonClient(endpoint string) (*ErigonClient, error) {
 	logger.Infof("initializing erigon client at %v", endpoint)
 	client := &ErigonClient{
@@ -55,7 +96,19 @@ func NewErigonClient(endpoint string) (*ErigonClient, error) {
 
 	var opts []geth_rpc.ClientOption
 	if utils.Config != nil {
-		if utils.Config.RawBigtable.Bigtable.Project != "" && utils.Config.RawBigtable.Bigtable.Instance != "" {
+		if utils.Config.RawBigtable.Pebble != "" {
+			pebble, err := openRawPebble(utils.Config.RawBigtable.Pebble)
+			if err != nil {
+				return nil, err
+			}
+			rawStore := db2.WithCache(db2.NewRawStore(store.Wrap(pebble, db2.BlocksRawTable, "")))
+			roundTripper := db2.NewBigTableEthRaw(rawStore, utils.Config.Chain.Id)
+			opts = append(opts, geth_rpc.WithHTTPClient(&http.Client{
+				Transport: db2.NewWithFallback(roundTripper, http.DefaultTransport),
+			}))
+			client.rawStore = rawStore
+			logger.Infof("using pebble db %s for erigon client", utils.Config.RawBigtable.Pebble)
+		} else if utils.Config.RawBigtable.Bigtable.Project != "" && utils.Config.RawBigtable.Bigtable.Instance != "" {
 			if utils.Config.RawBigtable.Bigtable.Emulator {
 				err := os.Setenv("BIGTABLE_EMULATOR_HOST", fmt.Sprintf("%s:%d", utils.Config.RawBigtable.Bigtable.EmulatorHost, utils.Config.RawBigtable.Bigtable.EmulatorPort))
 				if err != nil {
@@ -278,7 +331,8 @@ func (client *ErigonClient) GetBlock(number int64, traceMode string) (*types.Eth
 				}
 				return nil
 			}(),
-			BlobGasUsed: receipt.BlobGasUsed,
+			BlobGasUsed:       receipt.BlobGasUsed,
+			AuthorizationList: setCodeAuthorizations(tx),
 		}
 	}
 
@@ -746,6 +800,114 @@ func (client *ErigonClient) GetERC20TokenMetadata(token []byte) (*types.ERC20Met
 	return ret, err
 }
 
+// GetProxyImplementations detects whether the given contracts are proxies at the end of the given block and returns
+// their implementation, the result is nil for contracts that are not proxies
+func (client *ErigonClient) GetProxyImplementations(addresses [][]byte, blockNumber uint64) ([]*types.Eth1ProxyImplementation, error) {
+	startTime := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("rpc_el_get_proxy_implementations").Observe(time.Since(startTime).Seconds())
+	}()
+
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	block := hexutil.EncodeUint64(blockNumber)
+	slots := []common.Hash{utils.ProxyImplementationSlot, utils.ProxyBeaconSlot, utils.ProxyAdminSlot, utils.ProxiableSlot}
+	codes := make([]hexutil.Bytes, len(addresses))
+	values := make([][]common.Hash, len(addresses))
+	batchElements := make([]geth_rpc.BatchElem, 0, len(addresses)*(len(slots)+1))
+	for i, address := range addresses {
+		batchElements = append(batchElements, geth_rpc.BatchElem{
+			Method: "eth_getCode",
+			Args:   []interface{}{common.BytesToAddress(address), block},
+			Result: &codes[i],
+		})
+		values[i] = make([]common.Hash, len(slots))
+		for j, slot := range slots {
+			batchElements = append(batchElements, geth_rpc.BatchElem{
+				Method: "eth_getStorageAt",
+				Args:   []interface{}{common.BytesToAddress(address), slot, block},
+				Result: &values[i][j],
+			})
+		}
+	}
+
+	err := client.rpcClient.BatchCall(batchElements)
+	if err != nil {
+		return nil, fmt.Errorf("error during batch request: %w", err)
+	}
+	for _, el := range batchElements {
+		if el.Error != nil {
+			return nil, fmt.Errorf("error in batch call %v %v: %w", el.Method, el.Args, el.Error)
+		}
+	}
+
+	ret := make([]*types.Eth1ProxyImplementation, len(addresses))
+	beacons := make([][]byte, 0)
+	for i := range addresses {
+		ret[i] = utils.ClassifyProxy(codes[i], values[i][0], values[i][1], values[i][2], values[i][3])
+		if ret[i] != nil && ret[i].Type == types.ProxyType_BEACON_PROXY {
+			beacons = append(beacons, ret[i].Beacon)
+		}
+	}
+
+	implementations, err := client.GetBeaconImplementations(beacons, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, proxy := range ret {
+		if proxy != nil && proxy.Type == types.ProxyType_BEACON_PROXY {
+			proxy.Implementation = implementations[0]
+			implementations = implementations[1:]
+		}
+	}
+	return ret, nil
+}
+
+// GetBeaconImplementations returns the implementations of the given EIP-1967 beacons at the end of the given block, the
+// result is nil for beacons that do not return an implementation
+func (client *ErigonClient) GetBeaconImplementations(beacons [][]byte, blockNumber uint64) ([][]byte, error) {
+	if len(beacons) == 0 {
+		return nil, nil
+	}
+
+	block := hexutil.EncodeUint64(blockNumber)
+	results := make([]hexutil.Bytes, len(beacons))
+	batchElements := make([]geth_rpc.BatchElem, 0, len(beacons))
+	for i, beacon := range beacons {
+		to := common.BytesToAddress(beacon)
+		msg := ethereum.CallMsg{
+			To:   &to,
+			Gas:  1000000,
+			Data: utils.BeaconImplementationMethod,
+		}
+		batchElements = append(batchElements, geth_rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{toCallArg(msg), block},
+			Result: &results[i],
+		})
+	}
+
+	err := client.rpcClient.BatchCall(batchElements)
+	if err != nil {
+		return nil, fmt.Errorf("error during batch request: %w", err)
+	}
+
+	implementations := make([][]byte, len(beacons))
+	for i, el := range batchElements {
+		if el.Error != nil {
+			// the beacon reverted or is not a beacon at all
+			logrus.Warnf("error calling implementation() of beacon %x at block %v: %v", beacons[i], blockNumber, el.Error)
+			continue
+		}
+		if len(results[i]) == 32 && common.BytesToHash(results[i]) != (common.Hash{}) {
+			implementations[i] = common.BytesToAddress(results[i]).Bytes()
+		}
+	}
+	return implementations, nil
+}
+
 func toCallArg(msg ethereum.CallMsg) interface{} {
 	arg := map[string]interface{}{
 		"from": msg.From,
@@ -1117,6 +1279,7 @@ func (client *ErigonClient) processBlockResult(block BlockResponseWithUncles) *t
 		for _, h := range tx.BlobHashes() {
 			pbTx.BlobVersionedHashes = append(pbTx.BlobVersionedHashes, h.Bytes())
 		}
+		pbTx.AuthorizationList = setCodeAuthorizations(tx)
 
 		if tx.To() != nil {
 			pbTx.To = tx.To().Bytes()
@@ -1193,3 +1356,29 @@ func (client *ErigonClient) processReceiptsAndTraces(ethBlock *types.Eth1Block,
 	}
 
 }
+
+// setCodeAuthorizations converts the authorization list of an EIP-7702 transaction and recovers the authority of each
+// authorization, the authority is left empty if the signature of the authorization is invalid
+func setCodeAuthorizations(tx *geth_types.Transaction) []*types.Eth1SetCodeAuthorization {
+	authList := tx.SetCodeAuthorizations()
+	if len(authList) == 0 {
+		return nil
+	}
+	authorizations := make([]*types.Eth1SetCodeAuthorization, 0, len(authList))
+	for _, auth := range authList {
+		a := &types.Eth1SetCodeAuthorization{
+			ChainId: auth.ChainID.Bytes(),
+			Address: auth.Address.Bytes(),
+			Nonce:   auth.Nonce,
+			YParity: uint32(auth.V),
+			R:       auth.R.Bytes(),
+			S:       auth.S.Bytes(),
+		}
+		authority, err := auth.Authority()
+		if err == nil {
+			a.Authority = authority.Bytes()
+		}
+		authorizations = append(authorizations, a)
+	}
+	return authorizations
+}
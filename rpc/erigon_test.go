@@ -73,3 +73,35 @@ func TestGnosisBlockHash(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenRawPebble(t *testing.T) {
+	path := t.TempDir()
+	t.Cleanup(func() { _ = CloseRawStore() })
+
+	first, err := openRawPebble(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a second client of the process has to reuse the store, opening the directory again fails on its lock
+	second, err := openRawPebble(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("got a new store for the same path")
+	}
+	if _, err := openRawPebble(t.TempDir()); err == nil {
+		t.Errorf("got no error for a second path")
+	}
+
+	if err := CloseRawStore(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := openRawPebble(path)
+	if err != nil {
+		t.Fatalf("could not reopen the closed store: %v", err)
+	}
+	if reopened == first {
+		t.Errorf("got the closed store")
+	}
+}
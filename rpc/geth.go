@@ -10,6 +10,7 @@ import (
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/contracts/oneinchoracle"
 	"github.com/gobitfly/eth2-beaconchain-explorer/erc20"
+	"github.com/gobitfly/eth2-beaconchain-explorer/erc721"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 
 	"github.com/ethereum/go-ethereum"
@@ -177,6 +178,7 @@ func (client *GethClient) GetBlock(number int64) (*types.Eth1Block, *types.GetBl
 			AccessList:           []*types.AccessList{},
 			Hash:                 tx.Hash().Bytes(),
 			Itx:                  []*types.Eth1InternalTransaction{},
+			AuthorizationList:    setCodeAuthorizations(tx),
 		}
 
 		if tx.To() != nil {
@@ -378,6 +380,54 @@ func (client *GethClient) GetERC20TokenBalance(address string, token string) ([]
 	return balance, nil
 }
 
+// GetERC20Allowance returns the current allowance of the spender for the tokens of the owner
+func (client *GethClient) GetERC20Allowance(token, owner, spender []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	contract, err := erc20.NewErc20(common.BytesToAddress(token), client.ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("error getting token-contract: erc20.NewErc20: %w", err)
+	}
+	allowance, err := contract.Allowance(&bind.CallOpts{Context: ctx}, common.BytesToAddress(owner), common.BytesToAddress(spender))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving allowance of token %x: %w", token, err)
+	}
+	return allowance.Bytes(), nil
+}
+
+// GetERC721Approved returns the address that is currently approved to transfer the token, the zero address if there is none
+func (client *GethClient) GetERC721Approved(token, tokenId []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	contract, err := erc721.NewErc721(common.BytesToAddress(token), client.ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("error getting token-contract: erc721.NewErc721: %w", err)
+	}
+	approved, err := contract.GetApproved(&bind.CallOpts{Context: ctx}, new(big.Int).SetBytes(tokenId))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving approved address of token %x: %w", token, err)
+	}
+	return approved.Bytes(), nil
+}
+
+// GetIsApprovedForAll returns whether the operator is currently approved to transfer all tokens of the owner
+func (client *GethClient) GetIsApprovedForAll(token, owner, operator []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	contract, err := erc721.NewErc721(common.BytesToAddress(token), client.ethClient)
+	if err != nil {
+		return false, fmt.Errorf("error getting token-contract: erc721.NewErc721: %w", err)
+	}
+	approved, err := contract.IsApprovedForAll(&bind.CallOpts{Context: ctx}, common.BytesToAddress(owner), common.BytesToAddress(operator))
+	if err != nil {
+		return false, fmt.Errorf("error retrieving approval for all of token %x: %w", token, err)
+	}
+	return approved, nil
+}
+
 func (client *GethClient) GetERC20TokenMetadata(token []byte) (*types.ERC20Metadata, error) {
 	logger.Infof("retrieving metadata for token %x", token)
 
@@ -17,6 +17,7 @@ type Client interface {
 	GetBlockBySlot(slot uint64) (*types.Block, error)
 	GetValidatorParticipation(epoch uint64) (*types.ValidatorParticipation, error)
 	GetNewBlockChan() chan *types.Block
+	GetHeadEventChan() (chan *StreamedBlockEventData, error)
 	GetSyncCommittee(stateID string, epoch uint64) (*StandardSyncCommittee, error)
 	GetBalancesForEpoch(epoch int64) (map[uint64]uint64, error)
 	GetValidatorState(epoch uint64) (*StandardValidatorsResponse, error)
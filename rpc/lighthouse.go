@@ -57,16 +57,9 @@ func NewLighthouseClient(endpoint string, chainID *big.Int) (*LighthouseClient,
 
 func (lc *LighthouseClient) GetNewBlockChan() chan *types.Block {
 	blkCh := make(chan *types.Block, 10)
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/eth/v1/events?topics=head", lc.endpoint), nil)
-	if err != nil {
-		logger.Fatal(err, "error initializing event sse request", 0)
-	}
-	// disable gzip compression for sse
-	req.Header.Set("accept-encoding", "identity")
 
 	go func() {
-		stream, err := eventsource.SubscribeWithRequest("", req)
-
+		stream, err := lc.subscribeHeadEvents()
 		if err != nil {
 			utils.LogFatal(err, "getting eventsource stream error", 0)
 		}
@@ -101,6 +94,52 @@ func (lc *LighthouseClient) GetNewBlockChan() chan *types.Block {
 	return blkCh
 }
 
+// GetHeadEventChan subscribes to the head events of the beacon node. Unlike GetNewBlockChan the blocks of the events
+// are not retrieved. An error is returned if the event stream can not be established, once it is established the
+// stream reconnects automatically.
+func (lc *LighthouseClient) GetHeadEventChan() (chan *StreamedBlockEventData, error) {
+	stream, err := lc.subscribeHeadEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	eventCh := make(chan *StreamedBlockEventData, 10)
+	go func() {
+		defer stream.Close()
+
+		for {
+			select {
+			case err := <-stream.Errors:
+				utils.LogError(err, "Lighthouse connection error (will automatically retry to connect)", 0)
+			case e := <-stream.Events:
+				parsed := &StreamedBlockEventData{}
+				err := json.Unmarshal([]byte(e.Data()), parsed)
+				if err != nil {
+					logger.Warnf("failed to decode head event: %v", err)
+					continue
+				}
+				eventCh <- parsed
+			}
+		}
+	}()
+	return eventCh, nil
+}
+
+func (lc *LighthouseClient) subscribeHeadEvents() (*eventsource.Stream, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/eth/v1/events?topics=head", lc.endpoint), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing event sse request: %w", err)
+	}
+	// disable gzip compression for sse
+	req.Header.Set("accept-encoding", "identity")
+
+	stream, err := eventsource.SubscribeWithRequest("", req)
+	if err != nil {
+		return nil, fmt.Errorf("error subscribing to head events: %w", err)
+	}
+	return stream, nil
+}
+
 // /eth/v1/beacon/states/%v/pending_deposits
 func (lc *LighthouseClient) GetPendingDeposits() (*StandardBeaconPendingDepositsResponse, error) {
 	headResp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/states/head/pending_deposits", lc.endpoint))
@@ -173,7 +212,11 @@ func (lc *LighthouseClient) GetChainHead() (*types.ChainHead, error) {
 
 func (lc *LighthouseClient) GetValidatorQueue() (*types.ValidatorQueue, error) {
 	// pre-filter the status, to return much less validators, thus much faster!
-	validatorsResp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/states/head/validators?status=pending_queued,active_exiting,active_slashed", lc.endpoint))
+	return lc.getValidatorQueue(fmt.Sprintf("%s/eth/v1/beacon/states/head/validators?status=pending_queued,active_exiting,active_slashed", lc.endpoint))
+}
+
+func (lc *LighthouseClient) getValidatorQueue(url string) (*types.ValidatorQueue, error) {
+	validatorsResp, err := lc.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving validator for head valiqdator queue check: %w", err)
 	}
@@ -337,6 +380,12 @@ func (lc *LighthouseClient) GetValidatorState(epoch uint64) (*StandardValidators
 
 // GetEpochData will get the epoch data from Lighthouse RPC api
 func (lc *LighthouseClient) GetEpochData(epoch uint64, skipHistoricBalances bool) (*types.EpochData, error) {
+	return lc.getEpochData(epoch, lc.GetValidatorParticipation)
+}
+
+// getEpochData retrieves the epoch data using only standard beacon api endpoints,
+// the participation stats are retrieved via the passed node specific function
+func (lc *LighthouseClient) getEpochData(epoch uint64, getValidatorParticipation func(epoch uint64) (*types.ValidatorParticipation, error)) (*types.EpochData, error) {
 	wg := &errgroup.Group{}
 	mux := &sync.Mutex{}
 
@@ -429,10 +478,12 @@ func (lc *LighthouseClient) GetEpochData(epoch uint64, skipHistoricBalances bool
 	if epoch < head.HeadEpoch {
 		wg.Go(func() error {
 			var err error
-			data.EpochParticipationStats, err = lc.GetValidatorParticipation(epoch)
+			data.EpochParticipationStats, err = getValidatorParticipation(epoch)
 			if err != nil {
 				if strings.HasSuffix(err.Error(), "can't be retrieved as it hasn't finished yet") { // should no longer happen
 					logger.Warnf("error retrieving epoch participation statistics for epoch %v: %v", epoch, err)
+				} else if errors.Is(err, errParticipationNotAvailable) {
+					logger.Debugf("skipping epoch participation statistics for epoch %v: %v", epoch, err)
 				} else {
 					return fmt.Errorf("error retrieving epoch participation statistics for epoch %v: %w", epoch, err)
 				}
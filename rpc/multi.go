@@ -0,0 +1,412 @@
+package rpc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/sync/errgroup"
+)
+
+// MultiClientEndpoint is a named upstream beacon node of a MultiClient
+type MultiClientEndpoint struct {
+	Name   string
+	Client Client
+}
+
+type multiClientEndpoint struct {
+	MultiClientEndpoint
+	healthy  bool
+	headSlot uint64
+}
+
+// MultiClient is a Client that distributes requests over several beacon nodes. Requests are sent to the
+// healthiest node in the configured order and automatically retried on the other nodes if they fail.
+// If a quorum greater than one is configured, block roots returned by GetBlockBySlot, GetBlockHeader and
+// the blocks of GetEpochData have to be confirmed by at least quorum nodes before they are returned to the caller.
+type MultiClient struct {
+	endpoints []*multiClientEndpoint
+	quorum    int
+	mux       *sync.RWMutex
+}
+
+// NewMultiClient is used to create a new client over the given endpoints, the health of all endpoints is
+// checked in the given interval by comparing their chain heads
+func NewMultiClient(endpoints []MultiClientEndpoint, quorum int, healthCheckInterval time.Duration) (*MultiClient, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no endpoints provided")
+	}
+	if quorum > len(endpoints) {
+		return nil, fmt.Errorf("quorum %v is larger than the number of endpoints %v", quorum, len(endpoints))
+	}
+
+	mc := &MultiClient{
+		endpoints: make([]*multiClientEndpoint, 0, len(endpoints)),
+		quorum:    quorum,
+		mux:       &sync.RWMutex{},
+	}
+	for _, e := range endpoints {
+		mc.endpoints = append(mc.endpoints, &multiClientEndpoint{MultiClientEndpoint: e, healthy: true})
+	}
+
+	if healthCheckInterval > 0 {
+		go mc.healthCheckLoop(healthCheckInterval)
+	}
+
+	return mc, nil
+}
+
+func (mc *MultiClient) healthCheckLoop(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for ; true; <-t.C {
+		mc.checkHealth()
+	}
+}
+
+// checkHealth marks all endpoints as unhealthy that either fail to return their chain head or whose head
+// is more than one epoch behind the best head of all endpoints
+func (mc *MultiClient) checkHealth() {
+	heads := make([]*types.ChainHead, len(mc.endpoints))
+	wg := &sync.WaitGroup{}
+	for i, e := range mc.endpoints {
+		wg.Add(1)
+		go func(i int, e *multiClientEndpoint) {
+			defer wg.Done()
+			head, err := e.Client.GetChainHead()
+			if err != nil {
+				logger.Warnf("health check of beacon node %v failed: %v", e.Name, err)
+				return
+			}
+			heads[i] = head
+		}(i, e)
+	}
+	wg.Wait()
+
+	bestHeadSlot := uint64(0)
+	for _, head := range heads {
+		if head != nil && head.HeadSlot > bestHeadSlot {
+			bestHeadSlot = head.HeadSlot
+		}
+	}
+
+	mc.mux.Lock()
+	defer mc.mux.Unlock()
+	for i, e := range mc.endpoints {
+		healthy := heads[i] != nil && heads[i].HeadSlot+utils.Config.Chain.ClConfig.SlotsPerEpoch >= bestHeadSlot
+		if heads[i] != nil {
+			e.headSlot = heads[i].HeadSlot
+		}
+		if healthy != e.healthy {
+			logger.Infof("beacon node %v changed health state to healthy: %v (head slot: %v, best head slot: %v)", e.Name, healthy, e.headSlot, bestHeadSlot)
+		}
+		e.healthy = healthy
+		if healthy {
+			metrics.BeaconNodeHealthy.WithLabelValues(e.Name).Set(1)
+		} else {
+			metrics.BeaconNodeHealthy.WithLabelValues(e.Name).Set(0)
+		}
+	}
+}
+
+// orderedEndpoints returns all healthy endpoints in their configured order followed by the unhealthy ones
+func (mc *MultiClient) orderedEndpoints() []*multiClientEndpoint {
+	mc.mux.RLock()
+	defer mc.mux.RUnlock()
+
+	ordered := make([]*multiClientEndpoint, 0, len(mc.endpoints))
+	for _, e := range mc.endpoints {
+		if e.healthy {
+			ordered = append(ordered, e)
+		}
+	}
+	for _, e := range mc.endpoints {
+		if !e.healthy {
+			ordered = append(ordered, e)
+		}
+	}
+	return ordered
+}
+
+// multiCall calls fn on the endpoints in order until one of them succeeds and returns the result together with
+// the endpoint that returned it
+func multiCall[T any](mc *MultiClient, method string, fn func(c Client) (T, error)) (T, *multiClientEndpoint, error) {
+	var res T
+	var err error
+	endpoints := mc.orderedEndpoints()
+	for i, e := range endpoints {
+		start := time.Now()
+		res, err = fn(e.Client)
+		metrics.BeaconNodeRequestDuration.WithLabelValues(e.Name, method).Observe(time.Since(start).Seconds())
+		if err == nil {
+			return res, e, nil
+		}
+		metrics.BeaconNodeErrors.WithLabelValues(e.Name, method).Inc()
+		if i < len(endpoints)-1 {
+			metrics.BeaconNodeFailovers.WithLabelValues(e.Name).Inc()
+			logger.Warnf("error calling %v on beacon node %v, trying next node: %v", method, e.Name, err)
+		}
+	}
+	return res, nil, fmt.Errorf("error calling %v on all beacon nodes: %w", method, err)
+}
+
+// checkQuorum verifies that at least quorum nodes agree on the block root of the slot. A nil root means that
+// the source node has no block for the slot. Nodes that do not have a block of the slot yet abstain, only nodes with
+// a different root disagree. The check fails if not enough nodes agree, the caller retries it later.
+func (mc *MultiClient) checkQuorum(slot uint64, root []byte, source *multiClientEndpoint) error {
+	if mc.quorum <= 1 {
+		return nil
+	}
+
+	mux := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	agreements := 1
+	abstentions := 0
+	for _, e := range mc.orderedEndpoints() {
+		if e == source {
+			continue
+		}
+		wg.Add(1)
+		go func(e *multiClientEndpoint) {
+			defer wg.Done()
+			start := time.Now()
+			header, err := e.Client.GetBlockHeader(slot)
+			metrics.BeaconNodeRequestDuration.WithLabelValues(e.Name, "GetBlockHeader").Observe(time.Since(start).Seconds())
+			if err != nil {
+				metrics.BeaconNodeErrors.WithLabelValues(e.Name, "GetBlockHeader").Inc()
+				logger.Warnf("error retrieving block header of slot %v from beacon node %v for quorum check: %v", slot, e.Name, err)
+				return
+			}
+
+			var nodeRoot []byte
+			if header != nil {
+				nodeRoot = utils.MustParseHex(header.Data.Root)
+			} else if root != nil {
+				// the node might not have received the block yet, it neither confirms nor disputes the root
+				logger.Infof("beacon node %v has no block of slot %v yet, it abstains from the quorum check", e.Name, slot)
+				mux.Lock()
+				abstentions++
+				mux.Unlock()
+				return
+			}
+			if !bytes.Equal(nodeRoot, root) {
+				metrics.BeaconNodeDisagreements.WithLabelValues(e.Name).Inc()
+				logger.Warnf("beacon node %v disagrees with %v on block root of slot %v: %#x != %#x", e.Name, source.Name, slot, nodeRoot, root)
+				return
+			}
+
+			mux.Lock()
+			agreements++
+			mux.Unlock()
+		}(e)
+	}
+	wg.Wait()
+
+	if agreements < mc.quorum {
+		return fmt.Errorf("block root %#x of slot %v is only confirmed by %v of %v required beacon nodes, %v nodes have no block of the slot yet", root, slot, agreements, mc.quorum, abstentions)
+	}
+	return nil
+}
+
+// GetChainHead gets the chain head from the first responding beacon node
+func (mc *MultiClient) GetChainHead() (*types.ChainHead, error) {
+	res, _, err := multiCall(mc, "GetChainHead", func(c Client) (*types.ChainHead, error) {
+		return c.GetChainHead()
+	})
+	return res, err
+}
+
+// GetEpochData gets the epoch data from the first responding beacon node and verifies the roots of all blocks of
+// the epoch against the quorum
+func (mc *MultiClient) GetEpochData(epoch uint64, skipHistoricBalances bool) (*types.EpochData, error) {
+	data, source, err := multiCall(mc, "GetEpochData", func(c Client) (*types.EpochData, error) {
+		return c.GetEpochData(epoch, skipHistoricBalances)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if mc.quorum <= 1 {
+		return data, nil
+	}
+
+	g := &errgroup.Group{}
+	g.SetLimit(8)
+	for slot, blocks := range data.Blocks {
+		var root []byte
+		for _, block := range blocks {
+			if block.Status != 1 {
+				continue
+			}
+			if root != nil {
+				return nil, fmt.Errorf("beacon node %v returned more than one proposed block for slot %v", source.Name, slot)
+			}
+			root = block.BlockRoot
+		}
+		g.Go(func() error {
+			return mc.checkQuorum(slot, root, source)
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		return nil, fmt.Errorf("error verifying the blocks of epoch %v: %w", epoch, err)
+	}
+	return data, nil
+}
+
+// GetValidatorQueue gets the validator queue from the first responding beacon node
+func (mc *MultiClient) GetValidatorQueue() (*types.ValidatorQueue, error) {
+	res, _, err := multiCall(mc, "GetValidatorQueue", func(c Client) (*types.ValidatorQueue, error) {
+		return c.GetValidatorQueue()
+	})
+	return res, err
+}
+
+// GetEpochAssignments gets the epoch assignments from the first responding beacon node
+func (mc *MultiClient) GetEpochAssignments(epoch uint64) (*types.EpochAssignments, error) {
+	res, _, err := multiCall(mc, "GetEpochAssignments", func(c Client) (*types.EpochAssignments, error) {
+		return c.GetEpochAssignments(epoch)
+	})
+	return res, err
+}
+
+// GetBlockBySlot gets the block of the slot from the first responding beacon node and verifies its root against the quorum
+func (mc *MultiClient) GetBlockBySlot(slot uint64) (*types.Block, error) {
+	block, source, err := multiCall(mc, "GetBlockBySlot", func(c Client) (*types.Block, error) {
+		return c.GetBlockBySlot(slot)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var root []byte
+	if block.Status == 1 {
+		root = block.BlockRoot
+	}
+	err = mc.checkQuorum(slot, root, source)
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// GetValidatorParticipation gets the validator participation from the first responding beacon node
+func (mc *MultiClient) GetValidatorParticipation(epoch uint64) (*types.ValidatorParticipation, error) {
+	res, _, err := multiCall(mc, "GetValidatorParticipation", func(c Client) (*types.ValidatorParticipation, error) {
+		return c.GetValidatorParticipation(epoch)
+	})
+	return res, err
+}
+
+// GetNewBlockChan subscribes to the new blocks of all beacon nodes and forwards every block only once
+func (mc *MultiClient) GetNewBlockChan() chan *types.Block {
+	blkCh := make(chan *types.Block, 10)
+	seen, _ := lru.New(128)
+	seenMux := &sync.Mutex{}
+
+	for _, e := range mc.endpoints {
+		go func(e *multiClientEndpoint) {
+			for block := range e.Client.GetNewBlockChan() {
+				key := fmt.Sprintf("%x", block.BlockRoot)
+				seenMux.Lock()
+				ok, _ := seen.ContainsOrAdd(key, true)
+				seenMux.Unlock()
+				if ok {
+					continue
+				}
+				blkCh <- block
+			}
+		}(e)
+	}
+	return blkCh
+}
+
+// GetHeadEventChan subscribes to the head events of all beacon nodes and forwards every head only once, it fails if
+// none of the beacon nodes can be subscribed to
+func (mc *MultiClient) GetHeadEventChan() (chan *StreamedBlockEventData, error) {
+	eventCh := make(chan *StreamedBlockEventData, 10)
+	seen, _ := lru.New(128)
+	seenMux := &sync.Mutex{}
+
+	var errs []error
+	for _, e := range mc.endpoints {
+		events, err := e.Client.GetHeadEventChan()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", e.Name, err))
+			continue
+		}
+		go func() {
+			for event := range events {
+				seenMux.Lock()
+				ok, _ := seen.ContainsOrAdd(event.Block, true)
+				seenMux.Unlock()
+				if ok {
+					continue
+				}
+				eventCh <- event
+			}
+		}()
+	}
+	if len(errs) == len(mc.endpoints) {
+		return nil, fmt.Errorf("error subscribing to head events of all beacon nodes: %w", errors.Join(errs...))
+	}
+	return eventCh, nil
+}
+
+// GetSyncCommittee gets the sync committee from the first responding beacon node
+func (mc *MultiClient) GetSyncCommittee(stateID string, epoch uint64) (*StandardSyncCommittee, error) {
+	res, _, err := multiCall(mc, "GetSyncCommittee", func(c Client) (*StandardSyncCommittee, error) {
+		return c.GetSyncCommittee(stateID, epoch)
+	})
+	return res, err
+}
+
+// GetBalancesForEpoch gets the validator balances from the first responding beacon node
+func (mc *MultiClient) GetBalancesForEpoch(epoch int64) (map[uint64]uint64, error) {
+	res, _, err := multiCall(mc, "GetBalancesForEpoch", func(c Client) (map[uint64]uint64, error) {
+		return c.GetBalancesForEpoch(epoch)
+	})
+	return res, err
+}
+
+// GetValidatorState gets the validator state from the first responding beacon node
+func (mc *MultiClient) GetValidatorState(epoch uint64) (*StandardValidatorsResponse, error) {
+	res, _, err := multiCall(mc, "GetValidatorState", func(c Client) (*StandardValidatorsResponse, error) {
+		return c.GetValidatorState(epoch)
+	})
+	return res, err
+}
+
+// GetBlockHeader gets the block header of the slot from the first responding beacon node and verifies its root against the quorum
+func (mc *MultiClient) GetBlockHeader(slot uint64) (*StandardBeaconHeaderResponse, error) {
+	header, source, err := multiCall(mc, "GetBlockHeader", func(c Client) (*StandardBeaconHeaderResponse, error) {
+		return c.GetBlockHeader(slot)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var root []byte
+	if header != nil {
+		root = utils.MustParseHex(header.Data.Root)
+	}
+	err = mc.checkQuorum(slot, root, source)
+	if err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// GetPendingDeposits gets the pending deposits from the first responding beacon node
+func (mc *MultiClient) GetPendingDeposits() (*StandardBeaconPendingDepositsResponse, error) {
+	res, _, err := multiCall(mc, "GetPendingDeposits", func(c Client) (*StandardBeaconPendingDepositsResponse, error) {
+		return c.GetPendingDeposits()
+	})
+	return res, err
+}
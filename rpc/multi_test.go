@@ -0,0 +1,187 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+type fakeMultiClientNode struct {
+	Client
+	root    []byte
+	err     error
+	missing bool // the node has not received the block yet
+}
+
+func (f *fakeMultiClientNode) GetBlockBySlot(slot uint64) (*types.Block, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.Block{Slot: slot, Status: 1, BlockRoot: f.root}, nil
+}
+
+func (f *fakeMultiClientNode) GetEpochData(epoch uint64, skipHistoricBalances bool) (*types.EpochData, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.EpochData{
+		Epoch: epoch,
+		Blocks: map[uint64]map[string]*types.Block{
+			1: {fmt.Sprintf("%x", f.root): {Slot: 1, Status: 1, BlockRoot: f.root}},
+		},
+	}, nil
+}
+
+func (f *fakeMultiClientNode) GetBlockHeader(slot uint64) (*StandardBeaconHeaderResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.missing {
+		return nil, nil
+	}
+	header := &StandardBeaconHeaderResponse{}
+	header.Data.Root = fmt.Sprintf("%#x", f.root)
+	return header, nil
+}
+
+func TestMultiClientGetBlockBySlot(t *testing.T) {
+	rootA := []byte{0xaa}
+	rootB := []byte{0xbb}
+	errNode := errors.New("node unavailable")
+
+	tests := []struct {
+		name     string
+		nodes    []*fakeMultiClientNode
+		quorum   int
+		wantRoot []byte
+		wantErr  bool
+	}{
+		{
+			name:     "primary without quorum",
+			nodes:    []*fakeMultiClientNode{{root: rootA}, {root: rootB}},
+			quorum:   1,
+			wantRoot: rootA,
+		},
+		{
+			name:     "failover to second node",
+			nodes:    []*fakeMultiClientNode{{err: errNode}, {root: rootB}},
+			quorum:   1,
+			wantRoot: rootB,
+		},
+		{
+			name:     "quorum reached",
+			nodes:    []*fakeMultiClientNode{{root: rootA}, {root: rootB}, {root: rootA}},
+			quorum:   2,
+			wantRoot: rootA,
+		},
+		{
+			name:    "quorum not reached",
+			nodes:   []*fakeMultiClientNode{{root: rootA}, {root: rootB}, {err: errNode}},
+			quorum:  2,
+			wantErr: true,
+		},
+		{
+			name:     "node without the block abstains",
+			nodes:    []*fakeMultiClientNode{{root: rootA}, {missing: true}, {root: rootA}},
+			quorum:   2,
+			wantRoot: rootA,
+		},
+		{
+			name:    "quorum not reached with abstaining node",
+			nodes:   []*fakeMultiClientNode{{root: rootA}, {missing: true}, {root: rootB}},
+			quorum:  2,
+			wantErr: true,
+		},
+		{
+			name:    "all nodes failing",
+			nodes:   []*fakeMultiClientNode{{err: errNode}, {err: errNode}},
+			quorum:  1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := make([]MultiClientEndpoint, 0, len(tt.nodes))
+			for i, n := range tt.nodes {
+				endpoints = append(endpoints, MultiClientEndpoint{Name: fmt.Sprintf("node-%d", i), Client: n})
+			}
+			mc, err := NewMultiClient(endpoints, tt.quorum, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			block, err := mc.GetBlockBySlot(1)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got block with root %#x", block.BlockRoot)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprintf("%x", block.BlockRoot) != fmt.Sprintf("%x", tt.wantRoot) {
+				t.Errorf("got root %#x, want %#x", block.BlockRoot, tt.wantRoot)
+			}
+		})
+	}
+}
+
+func TestMultiClientGetEpochData(t *testing.T) {
+	rootA := []byte{0xaa}
+	rootB := []byte{0xbb}
+
+	tests := []struct {
+		name    string
+		nodes   []*fakeMultiClientNode
+		quorum  int
+		wantErr bool
+	}{
+		{
+			name:   "quorum reached",
+			nodes:  []*fakeMultiClientNode{{root: rootA}, {root: rootB}, {root: rootA}},
+			quorum: 2,
+		},
+		{
+			name:    "quorum not reached",
+			nodes:   []*fakeMultiClientNode{{root: rootA}, {root: rootB}, {root: rootB}},
+			quorum:  2,
+			wantErr: true,
+		},
+		{
+			name:   "no quorum configured",
+			nodes:  []*fakeMultiClientNode{{root: rootA}, {root: rootB}},
+			quorum: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := make([]MultiClientEndpoint, 0, len(tt.nodes))
+			for i, n := range tt.nodes {
+				endpoints = append(endpoints, MultiClientEndpoint{Name: fmt.Sprintf("node-%d", i), Client: n})
+			}
+			mc, err := NewMultiClient(endpoints, tt.quorum, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := mc.GetEpochData(0, false)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got epoch data with blocks %v", data.Blocks)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.Blocks[1][fmt.Sprintf("%x", rootA)] == nil {
+				t.Errorf("got blocks %v, want the block of the first node", data.Blocks)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package rpc
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+var (
+	recordURL      = flag.String("beacon.record", "", "url of the beacon node TestRecordBeaconFixtures records its responses from")
+	recordNodeType = flag.String("beacon.record.type", "", "node type of the beacon node the fixtures are recorded from")
+	recordNetwork  = flag.String("beacon.record.network", "", "name of the network the recorded beacon node is running on")
+	recordSlot     = flag.Uint64("beacon.record.slot", 0, "slot of the recorded block, defaults to a proposed slot two epochs behind the head")
+)
+
+// recordedFixtureSource is stored as SOURCE.json next to the fixtures recorded from a real beacon node and
+// describes where and at which slot they were recorded
+type recordedFixtureSource struct {
+	NodeType          string    `json:"node_type"`
+	NodeVersion       string    `json:"node_version"`
+	Network           string    `json:"network"`
+	RecordedAt        time.Time `json:"recorded_at"`
+	Slot              uint64    `json:"slot"`
+	SlotsPerEpoch     uint64    `json:"slots_per_epoch"`
+	SyncCommitteeSize uint64    `json:"sync_committee_size"`
+	AltairForkEpoch   uint64    `json:"altair_fork_epoch"`
+}
+
+func recordedFixtureDir(nodeType string) string {
+	return filepath.Join("testdata", "beacon", "recorded", nodeType)
+}
+
+func setRecordedFixtureConfig(t *testing.T, source *recordedFixtureSource) {
+	t.Helper()
+	previous := utils.Config
+	t.Cleanup(func() { utils.Config = previous })
+
+	utils.Config = &types.Config{}
+	utils.Config.Chain.ClConfig.SlotsPerEpoch = source.SlotsPerEpoch
+	utils.Config.Chain.ClConfig.SyncCommitteeSize = source.SyncCommitteeSize
+	utils.Config.Chain.ClConfig.AltairForkEpoch = source.AltairForkEpoch
+	utils.Config.Indexer.Node.StateParticipation = true
+}
+
+// TestRecordBeaconFixtures records the responses a real beacon node sends for the requests of the client into
+// testdata/beacon/recorded/<node type>. It only runs if a node is passed, e.g.
+//
+//	go test ./rpc -run TestRecordBeaconFixtures -beacon.record=http://localhost:5052 -beacon.record.type=teku -beacon.record.network=hoodi
+func TestRecordBeaconFixtures(t *testing.T) {
+	if *recordURL == "" {
+		t.Skip("no beacon node to record from, pass -beacon.record")
+	}
+	if *recordNodeType == "" || *recordNetwork == "" {
+		t.Fatal("-beacon.record.type and -beacon.record.network are required when recording")
+	}
+
+	var version struct {
+		Data struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+	if err := getRecordJSON(*recordURL+"/eth/v1/node/version", &version); err != nil {
+		t.Fatalf("error retrieving node version: %v", err)
+	}
+
+	var spec struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := getRecordJSON(*recordURL+"/eth/v1/config/spec", &spec); err != nil {
+		t.Fatalf("error retrieving chain spec: %v", err)
+	}
+	source := &recordedFixtureSource{
+		NodeType:    *recordNodeType,
+		NodeVersion: version.Data.Version,
+		Network:     *recordNetwork,
+		RecordedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+	for key, value := range map[string]*uint64{"SLOTS_PER_EPOCH": &source.SlotsPerEpoch, "SYNC_COMMITTEE_SIZE": &source.SyncCommitteeSize, "ALTAIR_FORK_EPOCH": &source.AltairForkEpoch} {
+		parsed, err := strconv.ParseUint(spec.Data[key], 10, 64)
+		if err != nil {
+			t.Fatalf("error parsing %v of the chain spec: %v", key, err)
+		}
+		*value = parsed
+	}
+	setRecordedFixtureConfig(t, source)
+
+	dir := recordedFixtureDir(source.NodeType)
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// every successful response passing the proxy is stored as fixture, errors are passed on without being stored
+	// so the replaying server answers them with a 404
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := http.Get(*recordURL + r.URL.RequestURI())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			name, err := fixtureName(dir, r.URL)
+			if err == nil {
+				err = os.MkdirAll(filepath.Dir(name), 0o755)
+			}
+			if err == nil {
+				err = os.WriteFile(name+".json", data, 0o644)
+			}
+			if err != nil {
+				t.Errorf("error storing fixture of %v: %v", r.URL, err)
+			}
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(data)
+	}))
+	defer proxy.Close()
+
+	client, err := NewClient(source.NodeType, proxy.URL, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := client.GetChainHead()
+	if err != nil {
+		t.Fatalf("error getting chain head: %v", err)
+	}
+
+	// the first slot of an epoch is avoided as it pulls the validators of the whole network
+	source.Slot = *recordSlot
+	if source.Slot == 0 {
+		source.Slot = head.HeadSlot - 2*source.SlotsPerEpoch
+		for {
+			if source.Slot%source.SlotsPerEpoch != 0 {
+				header, err := client.GetBlockHeader(source.Slot)
+				if err != nil {
+					t.Fatalf("error getting header at slot %v: %v", source.Slot, err)
+				}
+				if header != nil {
+					break
+				}
+			}
+			source.Slot--
+		}
+	}
+
+	if _, err := client.GetBlockHeader(source.Slot); err != nil {
+		t.Fatalf("error getting header at slot %v: %v", source.Slot, err)
+	}
+	if _, err := client.GetBlockBySlot(source.Slot); err != nil {
+		t.Fatalf("error getting block at slot %v: %v", source.Slot, err)
+	}
+
+	data, err := json.MarshalIndent(source, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SOURCE.json"), append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("recorded slot %v from %v into %v", source.Slot, source.NodeVersion, dir)
+}
+
+func getRecordJSON(url string, v interface{}) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// recordedBlock holds the parts of a block that have to be equal for every client recorded at the same slot
+type recordedBlock struct {
+	Root         string
+	ParentRoot   string
+	Proposer     uint64
+	Attesters    []string
+	Exits        int
+	SyncVotes    uint64
+	Transactions int
+}
+
+// TestClientRecordedFixtures replays the fixtures recorded from real nodes by TestRecordBeaconFixtures. Next to
+// checking every client on its own, the blocks of clients recorded at the same slot of the same network are
+// compared to each other, which is where differences in the json of the clients show up.
+func TestClientRecordedFixtures(t *testing.T) {
+	type recording struct {
+		source *recordedFixtureSource
+		block  *recordedBlock
+	}
+	recordings := []recording{}
+
+	for _, nodeType := range []string{NodeTypeLighthouse, NodeTypePrysm, NodeTypeTeku, NodeTypeNimbus, NodeTypeLodestar} {
+		t.Run(nodeType, func(t *testing.T) {
+			dir := recordedFixtureDir(nodeType)
+			data, err := os.ReadFile(filepath.Join(dir, "SOURCE.json"))
+			if os.IsNotExist(err) {
+				t.Skipf("no fixtures recorded from a %v node, see testdata/beacon/README.md", nodeType)
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			source := &recordedFixtureSource{}
+			if err := json.Unmarshal(data, source); err != nil {
+				t.Fatalf("error parsing SOURCE.json: %v", err)
+			}
+			setRecordedFixtureConfig(t, source)
+
+			server := newFixtureDirServer(t, dir)
+			client, err := NewClient(nodeType, server.URL, big.NewInt(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			head, err := client.GetChainHead()
+			if err != nil {
+				t.Fatalf("error getting chain head: %v", err)
+			}
+			if head.HeadSlot < source.Slot || head.HeadEpoch != head.HeadSlot/source.SlotsPerEpoch || head.FinalizedEpoch > head.HeadEpoch {
+				t.Errorf("got inconsistent head %+v for recorded slot %v", head, source.Slot)
+			}
+
+			header, err := client.GetBlockHeader(source.Slot)
+			if err != nil || header == nil {
+				t.Fatalf("error getting header at slot %v: %v", source.Slot, err)
+			}
+
+			block, err := client.GetBlockBySlot(source.Slot)
+			if err != nil {
+				t.Fatalf("error getting block at slot %v: %v", source.Slot, err)
+			}
+			if block.Slot != source.Slot || block.Status != 1 {
+				t.Errorf("got block slot %v status %v, want slot %v status 1", block.Slot, block.Status, source.Slot)
+			}
+			if got := fmt.Sprintf("%#x", block.BlockRoot); got != header.Data.Root {
+				t.Errorf("got block root %v, want header root %v", got, header.Data.Root)
+			}
+			if got := fmt.Sprintf("%#x", block.ParentRoot); got != header.Data.Header.Message.ParentRoot {
+				t.Errorf("got parent root %v, want header parent root %v", got, header.Data.Header.Message.ParentRoot)
+			}
+			if block.Proposer != uint64(header.Data.Header.Message.ProposerIndex) {
+				t.Errorf("got proposer %v, want header proposer %v", block.Proposer, header.Data.Header.Message.ProposerIndex)
+			}
+			if len(block.Attestations) == 0 {
+				t.Errorf("got no attestations in block at slot %v", source.Slot)
+			}
+
+			recorded := &recordedBlock{
+				Root:       header.Data.Root,
+				ParentRoot: header.Data.Header.Message.ParentRoot,
+				Proposer:   block.Proposer,
+				Exits:      len(block.VoluntaryExits),
+			}
+			for i, a := range block.Attestations {
+				if len(a.Attesters) == 0 {
+					t.Errorf("got no attesters for attestation %v", i)
+				}
+				if a.Data.Slot >= block.Slot {
+					t.Errorf("got attestation %v for slot %v in block at slot %v", i, a.Data.Slot, block.Slot)
+				}
+				recorded.Attesters = append(recorded.Attesters, fmt.Sprintf("%v/%v:%v", a.Data.Slot, a.Data.CommitteeIndex, a.Attesters))
+			}
+			sort.Strings(recorded.Attesters)
+			if block.SyncAggregate != nil {
+				if p := block.SyncAggregate.SyncAggregateParticipation; p < 0 || p > 1 {
+					t.Errorf("got sync aggregate participation %v", p)
+				}
+				recorded.SyncVotes = uint64(len(block.SyncAggregate.SyncCommitteeValidators))
+			}
+			if block.ExecutionPayload != nil {
+				recorded.Transactions = len(block.ExecutionPayload.Transactions)
+			}
+
+			recordings = append(recordings, recording{source: source, block: recorded})
+		})
+	}
+
+	for i := range recordings {
+		for j := i + 1; j < len(recordings); j++ {
+			a, b := recordings[i], recordings[j]
+			if a.source.Network != b.source.Network || a.source.Slot != b.source.Slot {
+				continue
+			}
+			if fmt.Sprintf("%+v", a.block) != fmt.Sprintf("%+v", b.block) {
+				t.Errorf("got different blocks at slot %v of %v from %v and %v:\n%+v\n%+v", a.source.Slot, a.source.Network, a.source.NodeVersion, b.source.NodeVersion, a.block, b.block)
+			}
+		}
+	}
+}
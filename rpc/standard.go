@@ -0,0 +1,179 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// Supported values for the indexer.node.type config option
+const (
+	NodeTypeLighthouse = "lighthouse"
+	NodeTypePrysm      = "prysm"
+	NodeTypeTeku       = "teku"
+	NodeTypeNimbus     = "nimbus"
+	NodeTypeLodestar   = "lodestar"
+)
+
+// timelyTargetFlagIndex is the index of the TIMELY_TARGET participation flag, see
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/beacon-chain.md#participation-flag-indices
+const timelyTargetFlagIndex = 1
+
+// NewClient creates the rpc client matching the given beacon node type
+func NewClient(nodeType string, endpoint string, chainID *big.Int) (Client, error) {
+	switch nodeType {
+	case NodeTypeLighthouse:
+		return NewLighthouseClient(endpoint, chainID)
+	case NodeTypePrysm, NodeTypeTeku, NodeTypeNimbus, NodeTypeLodestar:
+		return NewStandardClient(nodeType, endpoint, chainID)
+	default:
+		return nil, fmt.Errorf("invalid node type %v specified. supported node types are %v, %v, %v, %v and %v", nodeType, NodeTypeLighthouse, NodeTypePrysm, NodeTypeTeku, NodeTypeNimbus, NodeTypeLodestar)
+	}
+}
+
+// StandardClient holds the info for a beacon node that is only accessed via the standard beacon api.
+// It replaces the Lighthouse specific endpoints and query formats used by the LighthouseClient with their
+// standard equivalents, making it usable with Prysm, Teku, Nimbus and Lodestar nodes.
+type StandardClient struct {
+	*LighthouseClient
+	nodeType string
+}
+
+// NewStandardClient is used to create a new client for a beacon node implementing the standard beacon api
+func NewStandardClient(nodeType string, endpoint string, chainID *big.Int) (*StandardClient, error) {
+	lc, err := NewLighthouseClient(endpoint, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StandardClient{
+		LighthouseClient: lc,
+		nodeType:         nodeType,
+	}, nil
+}
+
+// NodeType returns the type of the beacon node the client is connected to
+func (sc *StandardClient) NodeType() string {
+	return sc.nodeType
+}
+
+// GetEpochData will get the epoch data from the standard beacon api
+func (sc *StandardClient) GetEpochData(epoch uint64, skipHistoricBalances bool) (*types.EpochData, error) {
+	return sc.getEpochData(epoch, sc.GetValidatorParticipation)
+}
+
+// GetValidatorQueue will get the validator queue from the standard beacon api. The status filter is passed
+// as a repeated query parameter as the comma separated form is not understood by every client.
+func (sc *StandardClient) GetValidatorQueue() (*types.ValidatorQueue, error) {
+	return sc.getValidatorQueue(fmt.Sprintf("%s/eth/v1/beacon/states/head/validators?status=pending_queued&status=active_exiting&status=active_slashed", sc.endpoint))
+}
+
+// errParticipationNotAvailable is returned by the standard client if the participation can not be calculated as
+// retrieving the beacon state is disabled
+var errParticipationNotAvailable = errors.New("participation is not available as retrieving the beacon state is disabled")
+
+// GetValidatorParticipation will get the validator participation from the participation flags of the beacon state.
+// The standard api has no participation endpoint and the per validator rewards and balances are about as large as the
+// state, so the full debug state of the following epoch is downloaded for every epoch. This is only done if it is
+// enabled with the stateParticipation option of the indexer node, otherwise errParticipationNotAvailable is returned.
+func (sc *StandardClient) GetValidatorParticipation(epoch uint64) (*types.ValidatorParticipation, error) {
+	if !utils.Config.Indexer.Node.StateParticipation {
+		return nil, errParticipationNotAvailable
+	}
+
+	head, err := sc.GetChainHead()
+	if err != nil {
+		return nil, err
+	}
+
+	if epoch > head.HeadEpoch {
+		return nil, fmt.Errorf("epoch %v is newer than the latest head %v", epoch, head.HeadEpoch)
+	}
+	if epoch == head.HeadEpoch {
+		// participation stats are calculated at the end of an epoch,
+		// making it impossible to retrieve stats of an currently ongoing epoch
+		return nil, fmt.Errorf("epoch %v can't be retrieved as it hasn't finished yet", epoch)
+	}
+	if epoch < utils.Config.Chain.ClConfig.AltairForkEpoch {
+		return nil, fmt.Errorf("participation of epoch %v can't be retrieved from the standard api as it is before the altair fork", epoch)
+	}
+
+	// the state at the last slot of the following epoch contains the participation flags of all attestations
+	// included for the requested epoch, if that state does not exist yet the head state is used
+	stateID := fmt.Sprintf("%d", (epoch+2)*utils.Config.Chain.ClConfig.SlotsPerEpoch-1)
+	if epoch+1 >= head.HeadEpoch {
+		stateID = "head"
+	}
+
+	logger.Infof("requesting participation flags for epoch %v from state %v", epoch, stateID)
+
+	resp, err := sc.get(fmt.Sprintf("%s/eth/v2/debug/beacon/states/%s", sc.endpoint, stateID))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving beacon state %v for participation of epoch %v: %w", stateID, epoch, err)
+	}
+
+	var parsedResponse StandardParticipationStateResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing beacon state %v for participation of epoch %v: %w", stateID, epoch, err)
+	}
+
+	votedGwei, eligibleGwei, err := participationFromState(&parsedResponse, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error calculating participation of epoch %v from state %v: %w", epoch, stateID, err)
+	}
+
+	res := &types.ValidatorParticipation{
+		Epoch:         epoch,
+		VotedEther:    votedGwei,
+		EligibleEther: eligibleGwei,
+		Finalized:     epoch <= head.FinalizedEpoch && head.JustifiedEpoch > 0,
+	}
+	if eligibleGwei > 0 {
+		res.GlobalParticipationRate = float32(votedGwei) / float32(eligibleGwei)
+	}
+	return res, nil
+}
+
+// participationFromState sums up the effective balance of all validators active in the given epoch and of the
+// unslashed ones among them that have the timely target flag set in the previous epoch participation of the state
+func participationFromState(state *StandardParticipationStateResponse, epoch uint64) (votedGwei uint64, eligibleGwei uint64, err error) {
+	validators := state.Data.Validators
+	participation := state.Data.PreviousEpochParticipation
+
+	if len(participation) != len(validators) {
+		return 0, 0, fmt.Errorf("len(previous_epoch_participation) != len(validators): %v != %v", len(participation), len(validators))
+	}
+
+	for i, v := range validators {
+		if uint64(v.ActivationEpoch) > epoch || epoch >= uint64(v.ExitEpoch) {
+			continue
+		}
+		eligibleGwei += uint64(v.EffectiveBalance)
+
+		if !v.Slashed && uint64(participation[i])&(1<<timelyTargetFlagIndex) != 0 {
+			votedGwei += uint64(v.EffectiveBalance)
+		}
+	}
+	return votedGwei, eligibleGwei, nil
+}
+
+// StandardParticipationStateResponse only contains the fields of a /eth/v2/debug/beacon/states response
+// that are required to calculate the participation of the previous epoch
+type StandardParticipationStateResponse struct {
+	Version string `json:"version"`
+	Data    struct {
+		Slot       uint64Str `json:"slot"`
+		Validators []struct {
+			EffectiveBalance uint64Str `json:"effective_balance"`
+			Slashed          bool      `json:"slashed"`
+			ActivationEpoch  uint64Str `json:"activation_epoch"`
+			ExitEpoch        uint64Str `json:"exit_epoch"`
+		} `json:"validators"`
+		PreviousEpochParticipation []uint64Str `json:"previous_epoch_participation"`
+	} `json:"data"`
+}
@@ -0,0 +1,298 @@
+package rpc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// newFixtureServer serves the hand-written beacon api fixtures stored in testdata/beacon/<nodeType>, see
+// testdata/beacon/README.md for how they relate to the fixtures recorded from real nodes.
+func newFixtureServer(t *testing.T, nodeType string) *httptest.Server {
+	t.Helper()
+	return newFixtureDirServer(t, filepath.Join("testdata", "beacon", nodeType))
+}
+
+// newFixtureDirServer serves the beacon api fixtures stored in dir. The file of a request is its path followed by
+// @<query> if the request has a query, so a request with unexpected query parameters is answered with a 404 like a
+// beacon node would do for unknown routes. Fixtures with the .sse extension are served as event stream that is kept
+// open until the test ends.
+func newFixtureDirServer(t *testing.T, dir string) *httptest.Server {
+	t.Helper()
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, err := fixtureName(dir, r.URL)
+		if err != nil {
+			http.Error(w, `{"code":400,"message":"BAD_REQUEST"}`, http.StatusBadRequest)
+			return
+		}
+
+		if data, err := os.ReadFile(name + ".sse"); err == nil {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write(data)
+			w.(http.Flusher).Flush()
+			select {
+			case <-done:
+			case <-r.Context().Done():
+			}
+			return
+		}
+
+		data, err := os.ReadFile(name + ".json")
+		if err != nil {
+			http.Error(w, `{"code":404,"message":"NOT_FOUND"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(func() {
+		close(done)
+		server.Close()
+	})
+	return server
+}
+
+// fixtureName returns the fixture file of a request without its extension
+func fixtureName(dir string, u *url.URL) (string, error) {
+	name := filepath.Join(dir, filepath.FromSlash(u.Path))
+	if u.RawQuery != "" {
+		query, err := url.QueryUnescape(u.RawQuery)
+		if err != nil {
+			return "", err
+		}
+		name += "@" + query
+	}
+	return name, nil
+}
+
+func setFixtureConfig(t *testing.T) {
+	t.Helper()
+	previous := utils.Config
+	t.Cleanup(func() { utils.Config = previous })
+
+	utils.Config = &types.Config{}
+	utils.Config.Chain.ClConfig.SlotsPerEpoch = 32
+	utils.Config.Chain.ClConfig.SyncCommitteeSize = 8
+	utils.Config.Chain.ClConfig.AltairForkEpoch = 0
+	utils.Config.Indexer.Node.StateParticipation = true
+}
+
+func TestClientFixtures(t *testing.T) {
+	setFixtureConfig(t)
+
+	for _, nodeType := range []string{NodeTypeLighthouse, NodeTypePrysm, NodeTypeTeku, NodeTypeNimbus, NodeTypeLodestar} {
+		t.Run(nodeType, func(t *testing.T) {
+			server := newFixtureServer(t, nodeType)
+
+			client, err := NewClient(nodeType, server.URL, big.NewInt(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			head, err := client.GetChainHead()
+			if err != nil {
+				t.Fatalf("error getting chain head: %v", err)
+			}
+			if head.HeadSlot != 101 || head.HeadEpoch != 3 {
+				t.Errorf("got head slot %v epoch %v, want slot 101 epoch 3", head.HeadSlot, head.HeadEpoch)
+			}
+			if head.FinalizedEpoch != 0 || head.JustifiedEpoch != 2 || head.PreviousJustifiedEpoch != 1 {
+				t.Errorf("got finalized %v justified %v previous justified %v, want 0, 2 and 1", head.FinalizedEpoch, head.JustifiedEpoch, head.PreviousJustifiedEpoch)
+			}
+
+			queue, err := client.GetValidatorQueue()
+			if err != nil {
+				t.Fatalf("error getting validator queue: %v", err)
+			}
+			if queue.Activating != 1 || queue.Exiting != 1 || queue.ExitingBalance != 32000000000 {
+				t.Errorf("got queue %+v, want 1 activating and 1 exiting with 32 ETH", queue)
+			}
+
+			participation, err := client.GetValidatorParticipation(1)
+			if err != nil {
+				t.Fatalf("error getting validator participation: %v", err)
+			}
+			if participation.VotedEther != 96000000000 || participation.EligibleEther != 160000000000 {
+				t.Errorf("got voted %v eligible %v, want 96000000000 and 160000000000", participation.VotedEther, participation.EligibleEther)
+			}
+			if participation.GlobalParticipationRate != 0.6 {
+				t.Errorf("got participation rate %v, want 0.6", participation.GlobalParticipationRate)
+			}
+
+			if _, err := client.GetValidatorParticipation(3); err == nil {
+				t.Errorf("expected error when retrieving the participation of the ongoing epoch")
+			}
+
+			block, err := client.GetBlockBySlot(70)
+			if err != nil {
+				t.Fatalf("error getting block: %v", err)
+			}
+			if block.Slot != 70 || block.Proposer != 2 || block.Status != 1 {
+				t.Errorf("got block slot %v proposer %v status %v, want slot 70 proposer 2 status 1", block.Slot, block.Proposer, block.Status)
+			}
+			if !bytes.Equal(block.BlockRoot, bytes.Repeat([]byte{0x70}, 32)) || !bytes.Equal(block.ParentRoot, bytes.Repeat([]byte{0x69}, 32)) {
+				t.Errorf("got block root %#x parent root %#x", block.BlockRoot, block.ParentRoot)
+			}
+			if len(block.Attestations) != 1 {
+				t.Fatalf("got %v attestations, want 1", len(block.Attestations))
+			}
+			if got := block.Attestations[0].Attesters; len(got) != 2 || got[0] != 0 || got[1] != 1 {
+				t.Errorf("got attesters %v, want [0 1]", got)
+			}
+			if got := block.AttestationDuties[1]; len(got) != 1 || got[0] != 69 {
+				t.Errorf("got attestation duties %v for validator 1, want [69]", got)
+			}
+			if block.SyncAggregate == nil || block.SyncAggregate.SyncAggregateParticipation != 0.875 {
+				t.Errorf("got sync aggregate %+v, want participation 0.875", block.SyncAggregate)
+			}
+			if len(block.VoluntaryExits) != 1 || block.VoluntaryExits[0].ValidatorIndex != 4 {
+				t.Errorf("got voluntary exits %+v, want exit of validator 4", block.VoluntaryExits)
+			}
+		})
+	}
+}
+
+func TestClientEpochDataFixtures(t *testing.T) {
+	setFixtureConfig(t)
+
+	for _, nodeType := range []string{NodeTypeLighthouse, NodeTypePrysm, NodeTypeTeku, NodeTypeNimbus, NodeTypeLodestar} {
+		t.Run(nodeType, func(t *testing.T) {
+			server := newFixtureServer(t, nodeType)
+
+			client, err := NewClient(nodeType, server.URL, big.NewInt(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := client.GetEpochData(2, false)
+			if err != nil {
+				t.Fatalf("error getting epoch data: %v", err)
+			}
+			if data.Epoch != 2 || data.Finalized {
+				t.Errorf("got epoch %v finalized %v, want epoch 2 not finalized", data.Epoch, data.Finalized)
+			}
+			if len(data.Validators) != 6 || !data.Validators[4].Slashed || data.Validators[5].Status != "pending_queued" {
+				t.Errorf("got validators %+v, want 6 with validator 4 slashed and 5 pending", data.Validators)
+			}
+			if data.EpochParticipationStats == nil || data.EpochParticipationStats.VotedEther != 128000000000 || data.EpochParticipationStats.EligibleEther != 160000000000 {
+				t.Errorf("got participation %+v, want 128000000000 of 160000000000 voted", data.EpochParticipationStats)
+			}
+
+			if len(data.Blocks) != 32 {
+				t.Fatalf("got %v slots, want 32", len(data.Blocks))
+			}
+			block := data.Blocks[70][fmt.Sprintf("%x", bytes.Repeat([]byte{0x70}, 32))]
+			if block == nil || block.Status != 1 {
+				t.Errorf("got block %+v for slot 70, want proposed block", block)
+			}
+			for slot := uint64(64); slot < 96; slot++ {
+				if slot != 70 && data.Blocks[slot]["00"] == nil {
+					t.Errorf("got %v for slot %v, want missed block", data.Blocks[slot], slot)
+				}
+			}
+			if missed := data.Blocks[64]["00"]; missed == nil || missed.EpochAssignments == nil || len(missed.Validators) != 6 {
+				t.Errorf("got first block %+v of the epoch, want it to carry the assignments and validators", missed)
+			}
+			if len(data.FutureBlocks) != 6 || data.FutureBlocks[101][fmt.Sprintf("%x", bytes.Repeat([]byte{0xaa}, 32))] == nil {
+				t.Errorf("got %v future slots, want slots 96 to 101 with the head block", len(data.FutureBlocks))
+			}
+
+			// the attestations for slot 69 and 95 are included in the blocks at slot 70 and 101
+			for _, duty := range []struct {
+				slot      types.Slot
+				validator types.ValidatorIndex
+				want      []types.Slot
+			}{
+				{slot: 69, validator: 0, want: []types.Slot{70}},
+				{slot: 69, validator: 1, want: []types.Slot{70}},
+				{slot: 69, validator: 2, want: []types.Slot{}},
+				{slot: 95, validator: 1, want: []types.Slot{101}},
+				{slot: 95, validator: 2, want: []types.Slot{}},
+			} {
+				got, ok := data.AttestationDuties[duty.slot][duty.validator]
+				if !ok || fmt.Sprint(got) != fmt.Sprint(duty.want) {
+					t.Errorf("got inclusions %v for validator %v at slot %v, want %v", got, duty.validator, duty.slot, duty.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStandardClientStateParticipationDisabled(t *testing.T) {
+	setFixtureConfig(t)
+	utils.Config.Indexer.Node.StateParticipation = false
+
+	server := newFixtureServer(t, NodeTypeTeku)
+	client, err := NewClient(NodeTypeTeku, server.URL, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetValidatorParticipation(1); !errors.Is(err, errParticipationNotAvailable) {
+		t.Errorf("got error %v, want %v", err, errParticipationNotAvailable)
+	}
+
+	// the epoch is exported without participation
+	data, err := client.GetEpochData(2, false)
+	if err != nil {
+		t.Fatalf("error getting epoch data: %v", err)
+	}
+	if data.EpochParticipationStats == nil || data.EpochParticipationStats.EligibleEther != 0 {
+		t.Errorf("got participation %+v, want empty participation", data.EpochParticipationStats)
+	}
+}
+
+func TestClientNewBlockChanFixtures(t *testing.T) {
+	setFixtureConfig(t)
+
+	for _, nodeType := range []string{NodeTypeLighthouse, NodeTypePrysm, NodeTypeTeku, NodeTypeNimbus, NodeTypeLodestar} {
+		t.Run(nodeType, func(t *testing.T) {
+			server := newFixtureServer(t, nodeType)
+
+			client, err := NewClient(nodeType, server.URL, big.NewInt(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case block := <-client.GetNewBlockChan():
+				if block.Slot != 70 || !bytes.Equal(block.BlockRoot, bytes.Repeat([]byte{0x70}, 32)) {
+					t.Errorf("got block slot %v root %#x, want the block of the head event at slot 70", block.Slot, block.BlockRoot)
+				}
+			case <-time.After(time.Second * 10):
+				t.Fatal("got no block for the head event")
+			}
+
+			events, err := client.GetHeadEventChan()
+			if err != nil {
+				t.Fatalf("error subscribing to head events: %v", err)
+			}
+			select {
+			case event := <-events:
+				if event.Slot != 70 || event.Block != fmt.Sprintf("%#x", bytes.Repeat([]byte{0x70}, 32)) {
+					t.Errorf("got head event slot %v block %v, want slot 70", event.Slot, event.Block)
+				}
+			case <-time.After(time.Second * 10):
+				t.Fatal("got no head event")
+			}
+		})
+	}
+}
+
+func TestNewClientInvalidNodeType(t *testing.T) {
+	if _, err := NewClient("unknown", "http://localhost:4000", big.NewInt(1)); err == nil {
+		t.Errorf("expected error for unknown node type")
+	}
+}
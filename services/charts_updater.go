@@ -17,6 +17,7 @@ import (
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
 	"github.com/aybabtme/uniplot/histogram"
+	"github.com/shopspring/decimal"
 )
 
 type chartHandler struct {
@@ -60,6 +61,10 @@ var ChartHandlers = map[string]chartHandler{
 	"avg_block_util_chart_data": {29, AvgBlockUtilChartData},
 	"tx_count_chart_data":       {31, TxCountChartData},
 	// "avg_block_size_chart_data":          {32, AvgBlockSizeChartData},
+
+	"blob_submitters_blob_count":      {33, BlobSubmittersBlobCountChartData},
+	"blob_submitters_blob_fees":       {34, BlobSubmittersBlobFeesChartData},
+	"blob_submitters_fill_efficiency": {35, BlobSubmittersFillEfficiencyChartData},
 }
 
 // LatestChartsPageData returns the latest chart page data
@@ -1588,6 +1593,143 @@ func TxCountChartData() (*types.GenericChartData, error) {
 	return chartData, nil
 }
 
+// blobSubmitterChartSeries returns one daily series per rollup, value returns the value of a day and whether it exists
+func blobSubmitterChartSeries(value func(stats *types.BlobSubmitterStats) (float64, bool)) ([]*types.GenericChartDataSeries, error) {
+	stats, err := db.GetBlobSubmitterStats(time.Time{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// sum up the stats of all submitters of a rollup per day
+	type rollupDay struct {
+		rollup string
+		day    time.Time
+	}
+	days := []rollupDay{}
+	rollupStats := map[rollupDay]*types.BlobSubmitterStats{}
+	for _, s := range stats {
+		key := rollupDay{rollup: utils.BlobSubmitterRollupName(s.Sender, s.ToAddress), day: s.Day}
+		sum, ok := rollupStats[key]
+		if !ok {
+			sum = &types.BlobSubmitterStats{Day: s.Day}
+			rollupStats[key] = sum
+			days = append(days, key)
+		}
+		sum.TxCount += s.TxCount
+		sum.BlobCount += s.BlobCount
+		sum.BlobGasUsed = sum.BlobGasUsed.Add(s.BlobGasUsed)
+		sum.BlobFee = sum.BlobFee.Add(s.BlobFee)
+		sum.MeasuredBlobCount += s.MeasuredBlobCount
+		sum.NonZeroBytes += s.NonZeroBytes
+	}
+
+	rollups := []string{}
+	seriesData := map[string][][]float64{}
+	for _, key := range days {
+		v, ok := value(rollupStats[key])
+		if !ok {
+			continue
+		}
+		if _, ok := seriesData[key.rollup]; !ok {
+			rollups = append(rollups, key.rollup)
+		}
+		seriesData[key.rollup] = append(seriesData[key.rollup], []float64{float64(key.day.UnixMilli()), v})
+	}
+
+	series := make([]*types.GenericChartDataSeries, 0, len(rollups))
+	for _, rollup := range rollups {
+		series = append(series, &types.GenericChartDataSeries{Name: rollup, Data: seriesData[rollup]})
+	}
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].Name == "Other" || series[j].Name == "Other" {
+			return series[j].Name == "Other" && series[i].Name != "Other"
+		}
+		return series[i].Name < series[j].Name
+	})
+	return series, nil
+}
+
+func BlobSubmittersBlobCountChartData() (*types.GenericChartData, error) {
+	if LatestEpoch() == 0 {
+		return nil, fmt.Errorf("chart-data not available pre-genesis")
+	}
+
+	series, err := blobSubmitterChartSeries(func(stats *types.BlobSubmitterStats) (float64, bool) {
+		return float64(stats.BlobCount), true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chartData := &types.GenericChartData{
+		Title:                           "Blobs per Rollup",
+		Subtitle:                        "The daily number of blobs submitted per rollup.",
+		XAxisTitle:                      "",
+		YAxisTitle:                      "Blobs",
+		StackingMode:                    "normal",
+		Type:                            "column",
+		ColumnDataGroupingApproximation: "sum",
+		Series:                          series,
+	}
+
+	return chartData, nil
+}
+
+func BlobSubmittersBlobFeesChartData() (*types.GenericChartData, error) {
+	if LatestEpoch() == 0 {
+		return nil, fmt.Errorf("chart-data not available pre-genesis")
+	}
+
+	series, err := blobSubmitterChartSeries(func(stats *types.BlobSubmitterStats) (float64, bool) {
+		return stats.BlobFee.Div(decimal.NewFromInt(1e18)).InexactFloat64(), true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chartData := &types.GenericChartData{
+		Title:                           "Blob Fees per Rollup",
+		Subtitle:                        fmt.Sprintf("The daily blob fees paid per rollup in %s.", utils.Config.Frontend.ElCurrency),
+		XAxisTitle:                      "",
+		YAxisTitle:                      fmt.Sprintf("Blob Fees [%s]", utils.Config.Frontend.ElCurrency),
+		StackingMode:                    "normal",
+		Type:                            "column",
+		ColumnDataGroupingApproximation: "sum",
+		Series:                          series,
+	}
+
+	return chartData, nil
+}
+
+func BlobSubmittersFillEfficiencyChartData() (*types.GenericChartData, error) {
+	if LatestEpoch() == 0 {
+		return nil, fmt.Errorf("chart-data not available pre-genesis")
+	}
+
+	series, err := blobSubmitterChartSeries(func(stats *types.BlobSubmitterStats) (float64, bool) {
+		if stats.MeasuredBlobCount == 0 {
+			return 0, false
+		}
+		return utils.BlobFillEfficiency(stats.NonZeroBytes, stats.MeasuredBlobCount) * 100, true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chartData := &types.GenericChartData{
+		Title:                           "Blob Fill Efficiency per Rollup",
+		Subtitle:                        "The daily share of non-zero bytes of the blobs submitted per rollup.",
+		XAxisTitle:                      "",
+		YAxisTitle:                      "Fill Efficiency [%]",
+		StackingMode:                    "false",
+		Type:                            "line",
+		ColumnDataGroupingApproximation: "average",
+		Series:                          series,
+	}
+
+	return chartData, nil
+}
+
 func AvgBlockSizeChartData() (*types.GenericChartData, error) {
 	return nil, fmt.Errorf("unimplemented")
 }
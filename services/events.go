@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// eventSubscriptionBufferSize is the number of events buffered per subscriber, events for subscribers
+// that do not keep up are dropped instead of blocking the other subscribers
+const eventSubscriptionBufferSize = 64
+
+var eventsRedisClient *redis.Client
+var eventsRedisClientOnce = &sync.Once{}
+
+var eventHub = &streamEventHub{
+	subscriptions: make(map[*EventSubscription]struct{}),
+	mux:           &sync.RWMutex{},
+	started:       &sync.Once{},
+}
+
+// EventSubscription receives all events of the event stream matching its topics and validators
+type EventSubscription struct {
+	C          chan *types.StreamEvent
+	topics     map[types.StreamEventType]bool
+	validators map[uint64]bool
+}
+
+// streamEventHub holds a single redis subscription per explorer instance and fans out the received events
+// to all local subscriptions
+type streamEventHub struct {
+	subscriptions map[*EventSubscription]struct{}
+	mux           *sync.RWMutex
+	started       *sync.Once
+}
+
+func getEventsRedisClient() *redis.Client {
+	eventsRedisClientOnce.Do(func() {
+		eventsRedisClient = redis.NewClient(&redis.Options{
+			Addr:        utils.Config.RedisCacheEndpoint,
+			ReadTimeout: time.Second * 20,
+		})
+	})
+	return eventsRedisClient
+}
+
+// EventsEnabled returns whether the event stream is available, events are distributed via redis so the event stream is
+// disabled if no redis endpoint is configured
+func EventsEnabled() bool {
+	return utils.Config.RedisCacheEndpoint != ""
+}
+
+func eventsChannel() string {
+	return fmt.Sprintf("%d:events", utils.Config.Chain.ClConfig.DepositChainID)
+}
+
+// PublishEvent publishes the event to the event streams of all explorer instances, events are dropped if the event
+// stream is disabled
+func PublishEvent(event *types.StreamEvent) error {
+	if !EventsEnabled() {
+		return nil
+	}
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().Unix()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error marshalling %v event: %w", event.Type, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	err = getEventsRedisClient().Publish(ctx, eventsChannel(), data).Err()
+	if err != nil {
+		return fmt.Errorf("error publishing %v event: %w", event.Type, err)
+	}
+	metrics.Counter.WithLabelValues("events_published_" + string(event.Type)).Inc()
+	return nil
+}
+
+// SubscribeEvents subscribes to the events of the given topics, if validators are given only block events
+// proposed by one of the validators are delivered and events without a proposer are not. The subscription has to be closed when it is no longer used.
+func SubscribeEvents(topics []types.StreamEventType, validators []uint64) *EventSubscription {
+	eventHub.started.Do(func() {
+		go eventHub.run()
+	})
+
+	sub := &EventSubscription{
+		C:      make(chan *types.StreamEvent, eventSubscriptionBufferSize),
+		topics: make(map[types.StreamEventType]bool, len(topics)),
+	}
+	for _, t := range topics {
+		sub.topics[t] = true
+	}
+	if len(validators) > 0 {
+		sub.validators = make(map[uint64]bool, len(validators))
+		for _, v := range validators {
+			sub.validators[v] = true
+		}
+	}
+
+	eventHub.mux.Lock()
+	eventHub.subscriptions[sub] = struct{}{}
+	eventHub.mux.Unlock()
+
+	return sub
+}
+
+// Close removes the subscription from the event hub
+func (sub *EventSubscription) Close() {
+	eventHub.mux.Lock()
+	delete(eventHub.subscriptions, sub)
+	eventHub.mux.Unlock()
+}
+
+func (sub *EventSubscription) matches(event *types.StreamEvent) bool {
+	if !sub.topics[event.Type] {
+		return false
+	}
+	if sub.validators == nil {
+		return true
+	}
+	// events without a proposer do not belong to any validator
+	return event.Proposer != nil && sub.validators[*event.Proposer]
+}
+
+func (hub *streamEventHub) run() {
+	for {
+		err := hub.receive()
+		logger.Errorf("error receiving events from redis, resubscribing: %v", err)
+		time.Sleep(time.Second)
+	}
+}
+
+func (hub *streamEventHub) receive() error {
+	pubsub := getEventsRedisClient().Subscribe(context.Background(), eventsChannel())
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.ReceiveMessage(context.Background())
+		if err != nil {
+			return err
+		}
+
+		var event types.StreamEvent
+		err = json.Unmarshal([]byte(msg.Payload), &event)
+		if err != nil {
+			logger.Errorf("error unmarshalling event %v: %v", msg.Payload, err)
+			continue
+		}
+		hub.broadcast(&event)
+	}
+}
+
+func (hub *streamEventHub) broadcast(event *types.StreamEvent) {
+	hub.mux.RLock()
+	defer hub.mux.RUnlock()
+
+	for sub := range hub.subscriptions {
+		if !sub.matches(event) {
+			continue
+		}
+		select {
+		case sub.C <- event:
+		default:
+			metrics.Counter.WithLabelValues("events_dropped").Inc()
+		}
+	}
+}
+
+// publishSlotEvents publishes a slot event for every block exported after the given slot up to and including
+// the latest slot, at most one epoch of slots is published to avoid flooding the stream after a restart
+func publishSlotEvents(previousSlot, latestSlot uint64) error {
+	if latestSlot-previousSlot > utils.Config.Chain.ClConfig.SlotsPerEpoch {
+		previousSlot = latestSlot - utils.Config.Chain.ClConfig.SlotsPerEpoch
+	}
+
+	blocks := []struct {
+		Slot      uint64 `db:"slot"`
+		Epoch     uint64 `db:"epoch"`
+		BlockRoot []byte `db:"blockroot"`
+		Proposer  uint64 `db:"proposer"`
+		Status    string `db:"status"`
+	}{}
+	err := db.ReaderDb.Select(&blocks, `SELECT slot, epoch, blockroot, proposer, status FROM blocks WHERE slot > $1 AND slot <= $2 AND status != '3' ORDER BY slot`, previousSlot, latestSlot)
+	if err != nil {
+		return fmt.Errorf("error retrieving blocks for slot events: %w", err)
+	}
+
+	for _, b := range blocks {
+		proposer := b.Proposer
+		event := &types.StreamEvent{
+			Type:     types.StreamEventSlot,
+			Slot:     b.Slot,
+			Epoch:    b.Epoch,
+			Proposer: &proposer,
+			Status:   b.Status,
+		}
+		if len(b.BlockRoot) == 32 {
+			event.BlockRoot = fmt.Sprintf("%#x", b.BlockRoot)
+		}
+		err := PublishEvent(event)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
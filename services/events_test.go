@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+func TestEventSubscriptionMatches(t *testing.T) {
+	proposer := uint64(5)
+	otherProposer := uint64(6)
+
+	headEvent := &types.StreamEvent{Type: types.StreamEventHead, Proposer: &proposer}
+	otherHeadEvent := &types.StreamEvent{Type: types.StreamEventHead, Proposer: &otherProposer}
+	epochEvent := &types.StreamEvent{Type: types.StreamEventEpoch}
+
+	tests := []struct {
+		name       string
+		topics     []types.StreamEventType
+		validators []uint64
+		event      *types.StreamEvent
+		want       bool
+	}{
+		{"subscribed topic", []types.StreamEventType{types.StreamEventHead}, nil, headEvent, true},
+		{"other topic", []types.StreamEventType{types.StreamEventSlot}, nil, headEvent, false},
+		{"matching proposer", []types.StreamEventType{types.StreamEventHead}, []uint64{5}, headEvent, true},
+		{"other proposer", []types.StreamEventType{types.StreamEventHead}, []uint64{5}, otherHeadEvent, false},
+		{"network event", []types.StreamEventType{types.StreamEventEpoch}, nil, epochEvent, true},
+		{"network event with validator filter", []types.StreamEventType{types.StreamEventEpoch}, []uint64{5}, epochEvent, false},
+		{"event without proposer with validator filter", []types.StreamEventType{types.StreamEventHead}, []uint64{5}, &types.StreamEvent{Type: types.StreamEventHead}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &EventSubscription{topics: map[types.StreamEventType]bool{}}
+			for _, topic := range tt.topics {
+				sub.topics[topic] = true
+			}
+			if tt.validators != nil {
+				sub.validators = map[uint64]bool{}
+				for _, v := range tt.validators {
+					sub.validators[v] = true
+				}
+			}
+			if got := sub.matches(tt.event); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
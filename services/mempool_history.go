@@ -0,0 +1,368 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	geth_rpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	mempoolHistoryFlushInterval     = time.Minute
+	mempoolHistoryCleanupInterval   = time.Hour
+	mempoolHistoryWaitTimesInterval = time.Minute * 5
+	mempoolHistoryRpcBatchSize      = 100
+)
+
+type mempoolSenderNonce struct {
+	sender common.Address
+	nonce  uint64
+}
+
+type trackedMempoolTx struct {
+	tx *types.MempoolHistoryTransaction
+	// missingSince is the time the transaction was first missing in the mempool, it is zero while it is in the mempool
+	missingSince time.Time
+}
+
+// mempoolHistory follows the transactions of the mempool snapshots taken by the mempool updater. It records when
+// each transaction was first and last seen, detects replacements and resolves transactions that left the mempool as
+// mined, replaced or dropped.
+type mempoolHistory struct {
+	tracked   map[common.Hash]*trackedMempoolTx
+	bySender  map[mempoolSenderNonce]common.Hash
+	lastFlush time.Time
+	// lastCleanup and lastWaitTimes start at zero so that both run with the first snapshot
+	lastCleanup   time.Time
+	lastWaitTimes time.Time
+}
+
+func newMempoolHistory() *mempoolHistory {
+	return &mempoolHistory{
+		tracked:  make(map[common.Hash]*trackedMempoolTx),
+		bySender: make(map[mempoolSenderNonce]common.Hash),
+	}
+}
+
+// load continues the history with the transactions that were pending when the history was last updated
+func (h *mempoolHistory) load() error {
+	txs, err := db.GetPendingMempoolTransactions()
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		h.track(tx)
+	}
+	h.lastFlush = time.Now()
+	logger.Infof("loaded %v pending transactions of the mempool history", len(txs))
+	return nil
+}
+
+func (h *mempoolHistory) track(tx *types.MempoolHistoryTransaction) {
+	hash := common.BytesToHash(tx.Hash)
+	h.tracked[hash] = &trackedMempoolTx{tx: tx}
+	h.bySender[mempoolSenderNonce{sender: common.BytesToAddress(tx.Sender), nonce: tx.Nonce}] = hash
+}
+
+func (h *mempoolHistory) untrack(hash common.Hash) {
+	t, ok := h.tracked[hash]
+	if !ok {
+		return
+	}
+	delete(h.tracked, hash)
+	key := mempoolSenderNonce{sender: common.BytesToAddress(t.tx.Sender), nonce: t.tx.Nonce}
+	if h.bySender[key] == hash {
+		delete(h.bySender, key)
+	}
+}
+
+// update records a snapshot of the mempool and resolves the transactions that have left it. The tracked transactions
+// are changed before they are saved, after an error the history has to be loaded from the db again.
+func (h *mempoolHistory) update(client *geth_rpc.Client, txs map[common.Hash]*types.RawMempoolTransaction, now time.Time) error {
+	added, replaced := h.apply(txs, now)
+	err := db.SaveMempoolTransactions(added, replaced)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := h.resolve(client, now)
+	if err != nil {
+		return err
+	}
+	err = db.UpdateMempoolTransactionsStatus(resolved)
+	if err != nil {
+		return err
+	}
+
+	if now.Sub(h.lastFlush) >= mempoolHistoryFlushInterval {
+		hashes := make([][]byte, 0, len(h.tracked))
+		for hash, t := range h.tracked {
+			if t.missingSince.IsZero() {
+				hashes = append(hashes, hash.Bytes())
+			}
+		}
+		err = db.UpdateMempoolTransactionsLastSeen(hashes, now)
+		if err != nil {
+			return err
+		}
+		h.lastFlush = now
+	}
+
+	if now.Sub(h.lastCleanup) >= mempoolHistoryCleanupInterval {
+		retention := utils.Config.MempoolHistory.Retention
+		if retention == 0 {
+			retention = utils.Day * 30
+		}
+		deleted, err := db.DeleteMempoolTransactionsBefore(now.Add(-retention))
+		if err != nil {
+			return err
+		}
+		logger.Infof("deleted %v transactions from the mempool history", deleted)
+		h.lastCleanup = now
+	}
+
+	if now.Sub(h.lastWaitTimes) >= mempoolHistoryWaitTimesInterval {
+		waitTimes, err := db.GetMempoolWaitTimes(now.Add(-utils.Day))
+		if err != nil {
+			return err
+		}
+		cacheKey := fmt.Sprintf("%d:frontend:mempoolWaitTimes", utils.Config.Chain.ClConfig.DepositChainID)
+		err = cache.TieredCache.Set(cacheKey, waitTimes, utils.Day)
+		if err != nil {
+			return fmt.Errorf("error caching mempool wait times: %w", err)
+		}
+		h.lastWaitTimes = now
+	}
+	return nil
+}
+
+// apply compares a snapshot of the mempool with the tracked transactions. It returns the transactions that are new to
+// the mempool and the tracked transactions that have been replaced by a new transaction with the same sender and nonce.
+// Tracked transactions missing in the snapshot are marked to be resolved.
+func (h *mempoolHistory) apply(txs map[common.Hash]*types.RawMempoolTransaction, now time.Time) (added, replaced []*types.MempoolHistoryTransaction) {
+	for hash, raw := range txs {
+		if raw.From == nil || raw.Nonce == nil {
+			continue
+		}
+		if t, ok := h.tracked[hash]; ok {
+			t.tx.LastSeen = now
+			t.missingSince = time.Time{}
+			continue
+		}
+
+		tx := newMempoolHistoryTransaction(raw, now)
+		key := mempoolSenderNonce{sender: *raw.From, nonce: tx.Nonce}
+		if previous, ok := h.bySender[key]; ok {
+			// the node keeps a single transaction per sender and nonce, the previous one is only replaced if it left the mempool
+			if _, inMempool := txs[previous]; !inMempool {
+				if t, ok := h.tracked[previous]; ok {
+					t.tx.Status = types.MempoolTxReplaced
+					if isMempoolCancellation(raw) {
+						t.tx.Status = types.MempoolTxCancelled
+					}
+					t.tx.ReplacedBy = hash.Bytes()
+					replaced = append(replaced, t.tx)
+					h.untrack(previous)
+				}
+			}
+		}
+		h.track(tx)
+		added = append(added, tx)
+	}
+
+	for hash, t := range h.tracked {
+		if _, ok := txs[hash]; !ok && t.missingSince.IsZero() {
+			t.missingSince = now
+		}
+	}
+	return added, replaced
+}
+
+// resolve checks whether the transactions that have left the mempool have been mined. A transaction that has not been
+// mined is replaced if its nonce has been used by another transaction and dropped once it has been missing for longer
+// than the drop timeout. A transaction stays tracked until the next snapshot if any of its requests failed.
+func (h *mempoolHistory) resolve(client *geth_rpc.Client, now time.Time) ([]*types.MempoolHistoryTransaction, error) {
+	missing := make([]common.Hash, 0)
+	for hash, t := range h.tracked {
+		if !t.missingSince.IsZero() {
+			missing = append(missing, hash)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	type rpcReceipt struct {
+		BlockNumber *hexutil.Big `json:"blockNumber"`
+	}
+	receipts := make([]*rpcReceipt, len(missing))
+	elems := make([]geth_rpc.BatchElem, len(missing))
+	for i, hash := range missing {
+		elems[i] = geth_rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{hash}, Result: &receipts[i]}
+	}
+	err := batchCall(client, elems)
+	if err != nil {
+		return nil, err
+	}
+
+	blockTimes := make(map[uint64]time.Time)
+	notMined := make([]common.Hash, 0)
+	for i, hash := range missing {
+		if elems[i].Error != nil {
+			logger.Warnf("error getting receipt of mempool transaction %v: %v", hash, elems[i].Error)
+			continue
+		}
+		if receipts[i] == nil || receipts[i].BlockNumber == nil {
+			notMined = append(notMined, hash)
+			continue
+		}
+		blockTimes[receipts[i].BlockNumber.ToInt().Uint64()] = time.Time{}
+	}
+
+	type rpcHeader struct {
+		Timestamp hexutil.Uint64 `json:"timestamp"`
+	}
+	blockNumbers := make([]uint64, 0, len(blockTimes))
+	for number := range blockTimes {
+		blockNumbers = append(blockNumbers, number)
+	}
+	headers := make([]*rpcHeader, len(blockNumbers))
+	elems = make([]geth_rpc.BatchElem, len(blockNumbers))
+	for i, number := range blockNumbers {
+		elems[i] = geth_rpc.BatchElem{Method: "eth_getBlockByNumber", Args: []interface{}{hexutil.EncodeUint64(number), false}, Result: &headers[i]}
+	}
+	err = batchCall(client, elems)
+	if err != nil {
+		return nil, err
+	}
+	for i, number := range blockNumbers {
+		if elems[i].Error != nil {
+			logger.Warnf("error getting block %v of mined mempool transactions: %v", number, elems[i].Error)
+			continue
+		}
+		if headers[i] != nil {
+			blockTimes[number] = time.Unix(int64(headers[i].Timestamp), 0).UTC()
+		}
+	}
+
+	resolved := make([]*types.MempoolHistoryTransaction, 0, len(missing))
+	for i, hash := range missing {
+		if receipts[i] == nil || receipts[i].BlockNumber == nil {
+			// not mined or the receipt request failed
+			continue
+		}
+		number := receipts[i].BlockNumber.ToInt().Uint64()
+		if blockTimes[number].IsZero() {
+			// the block is not available yet, retry with the next snapshot
+			continue
+		}
+		tx := h.tracked[hash].tx
+		tx.Status = types.MempoolTxMined
+		tx.BlockNumber = sql.NullInt64{Int64: int64(number), Valid: true}
+		tx.BlockTs = sql.NullTime{Time: blockTimes[number], Valid: true}
+		resolved = append(resolved, tx)
+		h.untrack(hash)
+	}
+
+	nonces := make([]*hexutil.Uint64, len(notMined))
+	elems = make([]geth_rpc.BatchElem, len(notMined))
+	for i, hash := range notMined {
+		elems[i] = geth_rpc.BatchElem{Method: "eth_getTransactionCount", Args: []interface{}{common.BytesToAddress(h.tracked[hash].tx.Sender), "latest"}, Result: &nonces[i]}
+	}
+	err = batchCall(client, elems)
+	if err != nil {
+		return nil, err
+	}
+
+	dropTimeout := utils.Config.MempoolHistory.DropTimeout
+	if dropTimeout == 0 {
+		dropTimeout = time.Minute * 10
+	}
+	for i, hash := range notMined {
+		t := h.tracked[hash]
+		if elems[i].Error != nil {
+			// without the nonce of the sender it is unknown whether the transaction has been replaced
+			logger.Warnf("error getting nonce of the sender of mempool transaction %v: %v", hash, elems[i].Error)
+			continue
+		}
+		if nonces[i] != nil && uint64(*nonces[i]) > t.tx.Nonce {
+			// another transaction with the same nonce has been mined before it was seen in the mempool
+			t.tx.Status = types.MempoolTxReplaced
+		} else if now.Sub(t.missingSince) >= dropTimeout {
+			t.tx.Status = types.MempoolTxDropped
+		} else {
+			continue
+		}
+		resolved = append(resolved, t.tx)
+		h.untrack(hash)
+	}
+	return resolved, nil
+}
+
+// batchCall sends the requests in batches, the error of a single request is set in the Error field of its element
+func batchCall(client *geth_rpc.Client, elems []geth_rpc.BatchElem) error {
+	for start := 0; start < len(elems); start += mempoolHistoryRpcBatchSize {
+		end := start + mempoolHistoryRpcBatchSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+		err := client.BatchCall(elems[start:end])
+		if err != nil {
+			return fmt.Errorf("error sending batch of %v %v requests: %w", end-start, elems[start].Method, err)
+		}
+	}
+	return nil
+}
+
+func newMempoolHistoryTransaction(raw *types.RawMempoolTransaction, now time.Time) *types.MempoolHistoryTransaction {
+	tx := &types.MempoolHistoryTransaction{
+		Hash:      raw.Hash.Bytes(),
+		Sender:    raw.From.Bytes(),
+		Nonce:     raw.Nonce.ToInt().Uint64(),
+		Value:     decimal.Zero,
+		GasPrice:  decimal.Zero,
+		FirstSeen: now,
+		LastSeen:  now,
+		Status:    types.MempoolTxPending,
+	}
+	if raw.To != nil {
+		tx.To = raw.To.Bytes()
+	}
+	if raw.Value != nil {
+		tx.Value = decimal.NewFromBigInt(raw.Value.ToInt(), 0)
+	}
+	if raw.Gas != nil {
+		tx.Gas = raw.Gas.ToInt().Uint64()
+	}
+	if raw.GasPrice != nil {
+		tx.GasPrice = decimal.NewFromBigInt(raw.GasPrice.ToInt(), 0)
+	}
+	if raw.GasTipCap != nil {
+		tx.GasTipCap = decimal.NullDecimal{Decimal: decimal.NewFromBigInt(raw.GasTipCap.ToInt(), 0), Valid: true}
+	}
+	return tx
+}
+
+// isMempoolCancellation reports whether a replacement transaction cancels the replaced one by sending nothing to its own sender
+func isMempoolCancellation(raw *types.RawMempoolTransaction) bool {
+	return raw.To != nil && *raw.To == *raw.From && (raw.Value == nil || raw.Value.ToInt().Sign() == 0)
+}
+
+// LatestMempoolWaitTimes returns the time mined transactions waited in the mempool in the last day grouped by their max priority fee
+func LatestMempoolWaitTimes() []*types.MempoolWaitTime {
+	wanted := []*types.MempoolWaitTime{}
+	cacheKey := fmt.Sprintf("%d:frontend:mempoolWaitTimes", utils.Config.Chain.ClConfig.DepositChainID)
+	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Minute, &wanted); err != nil {
+		return nil
+	}
+	return wanted
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	geth_rpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+func newTestMempoolTx(hash byte, from, to common.Address, nonce, value int64) *types.RawMempoolTransaction {
+	return &types.RawMempoolTransaction{
+		Hash:     common.Hash{hash},
+		From:     &from,
+		To:       &to,
+		Nonce:    (*hexutil.Big)(big.NewInt(nonce)),
+		Value:    (*hexutil.Big)(big.NewInt(value)),
+		Gas:      (*hexutil.Big)(big.NewInt(21000)),
+		GasPrice: (*hexutil.Big)(big.NewInt(1e9)),
+	}
+}
+
+func TestMempoolHistoryApply(t *testing.T) {
+	alice := common.Address{0xa}
+	bob := common.Address{0xb}
+
+	tx1 := newTestMempoolTx(1, alice, bob, 5, 100)
+	tx2 := newTestMempoolTx(2, bob, alice, 0, 100)
+	speedUp := newTestMempoolTx(3, alice, bob, 5, 100)
+	cancel := newTestMempoolTx(4, alice, alice, 5, 0)
+	nextNonce := newTestMempoolTx(5, alice, bob, 6, 100)
+
+	type result struct {
+		added    []byte
+		replaced map[byte]string
+		missing  []byte
+	}
+	tests := []struct {
+		name      string
+		snapshots [][]*types.RawMempoolTransaction
+		want      result
+	}{
+		{
+			name:      "new transactions",
+			snapshots: [][]*types.RawMempoolTransaction{{tx1, tx2}},
+			want:      result{added: []byte{1, 2}},
+		},
+		{
+			name:      "seen again",
+			snapshots: [][]*types.RawMempoolTransaction{{tx1, tx2}, {tx1, tx2}},
+			want:      result{},
+		},
+		{
+			name:      "left the mempool",
+			snapshots: [][]*types.RawMempoolTransaction{{tx1, tx2}, {tx1}},
+			want:      result{missing: []byte{2}},
+		},
+		{
+			name:      "replaced by fee",
+			snapshots: [][]*types.RawMempoolTransaction{{tx1, tx2}, {speedUp, tx2}},
+			want:      result{added: []byte{3}, replaced: map[byte]string{1: types.MempoolTxReplaced}},
+		},
+		{
+			name:      "cancelled",
+			snapshots: [][]*types.RawMempoolTransaction{{tx1}, {cancel}},
+			want:      result{added: []byte{4}, replaced: map[byte]string{1: types.MempoolTxCancelled}},
+		},
+		{
+			name:      "replacement chain",
+			snapshots: [][]*types.RawMempoolTransaction{{tx1}, {speedUp}, {cancel}},
+			want:      result{added: []byte{4}, replaced: map[byte]string{3: types.MempoolTxCancelled}},
+		},
+		{
+			name:      "different nonce is no replacement",
+			snapshots: [][]*types.RawMempoolTransaction{{tx1}, {nextNonce}},
+			want:      result{added: []byte{5}, missing: []byte{1}},
+		},
+		{
+			name:      "reappeared",
+			snapshots: [][]*types.RawMempoolTransaction{{tx1}, {}, {tx1}},
+			want:      result{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newMempoolHistory()
+			now := time.Unix(1700000000, 0)
+			var added, replaced []*types.MempoolHistoryTransaction
+			for _, snapshot := range tt.snapshots {
+				now = now.Add(time.Second * 5)
+				txs := make(map[common.Hash]*types.RawMempoolTransaction)
+				for _, tx := range snapshot {
+					txs[tx.Hash] = tx
+				}
+				added, replaced = h.apply(txs, now)
+			}
+
+			gotAdded := map[byte]bool{}
+			for _, tx := range added {
+				gotAdded[tx.Hash[0]] = true
+				if !tx.FirstSeen.Equal(now) || tx.Status != types.MempoolTxPending {
+					t.Errorf("tx %x: got first seen %v and status %v, want %v and %v", tx.Hash[0], tx.FirstSeen, tx.Status, now, types.MempoolTxPending)
+				}
+			}
+			if len(gotAdded) != len(tt.want.added) {
+				t.Errorf("got %v added transactions, want %v", len(gotAdded), len(tt.want.added))
+			}
+			for _, hash := range tt.want.added {
+				if !gotAdded[hash] {
+					t.Errorf("tx %x has not been added", hash)
+				}
+			}
+
+			if len(replaced) != len(tt.want.replaced) {
+				t.Errorf("got %v replaced transactions, want %v", len(replaced), len(tt.want.replaced))
+			}
+			for _, tx := range replaced {
+				if got, want := tx.Status, tt.want.replaced[tx.Hash[0]]; got != want {
+					t.Errorf("tx %x: got status %v, want %v", tx.Hash[0], got, want)
+				}
+				if len(tx.ReplacedBy) == 0 {
+					t.Errorf("tx %x: missing replacement", tx.Hash[0])
+				}
+				if _, ok := h.tracked[common.BytesToHash(tx.Hash)]; ok {
+					t.Errorf("tx %x: replaced transaction is still tracked", tx.Hash[0])
+				}
+			}
+
+			missing := 0
+			for _, tracked := range h.tracked {
+				if !tracked.missingSince.IsZero() {
+					missing++
+				}
+			}
+			if missing != len(tt.want.missing) {
+				t.Errorf("got %v missing transactions, want %v", missing, len(tt.want.missing))
+			}
+			for _, hash := range tt.want.missing {
+				tracked, ok := h.tracked[common.Hash{hash}]
+				if !ok || tracked.missingSince.IsZero() {
+					t.Errorf("tx %x is not missing", hash)
+				}
+			}
+		})
+	}
+}
+
+// fakeMempoolEth serves the eth requests of the mempool history, hashes and senders listed in failing return an error
+type fakeMempoolEth struct {
+	mined   map[common.Hash]uint64
+	nonces  map[common.Address]uint64
+	failing map[interface{}]bool
+}
+
+func (s *fakeMempoolEth) GetTransactionReceipt(hash common.Hash) (map[string]interface{}, error) {
+	if s.failing[hash] {
+		return nil, fmt.Errorf("receipt of %v is not available", hash)
+	}
+	number, ok := s.mined[hash]
+	if !ok {
+		return nil, nil
+	}
+	return map[string]interface{}{"blockNumber": hexutil.EncodeUint64(number)}, nil
+}
+
+func (s *fakeMempoolEth) GetBlockByNumber(number hexutil.Uint64, full bool) (map[string]interface{}, error) {
+	return map[string]interface{}{"timestamp": hexutil.EncodeUint64(1700000000 + uint64(number)*12)}, nil
+}
+
+func (s *fakeMempoolEth) GetTransactionCount(sender common.Address, block string) (hexutil.Uint64, error) {
+	if s.failing[sender] {
+		return 0, fmt.Errorf("nonce of %v is not available", sender)
+	}
+	return hexutil.Uint64(s.nonces[sender]), nil
+}
+
+func TestMempoolHistoryResolve(t *testing.T) {
+	previous := utils.Config
+	t.Cleanup(func() { utils.Config = previous })
+	utils.Config = &types.Config{}
+	utils.Config.MempoolHistory.DropTimeout = time.Minute
+
+	alice := common.Address{0xa}
+	bob := common.Address{0xb}
+	tx := newTestMempoolTx(1, alice, bob, 5, 100)
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name        string
+		eth         *fakeMempoolEth
+		missingFor  time.Duration
+		wantStatus  string
+		wantTracked bool
+	}{
+		{
+			name:       "mined",
+			eth:        &fakeMempoolEth{mined: map[common.Hash]uint64{tx.Hash: 100}, nonces: map[common.Address]uint64{alice: 6}},
+			wantStatus: types.MempoolTxMined,
+		},
+		{
+			name:        "receipt request failed",
+			eth:         &fakeMempoolEth{nonces: map[common.Address]uint64{alice: 6}, failing: map[interface{}]bool{tx.Hash: true}},
+			missingFor:  time.Hour,
+			wantStatus:  types.MempoolTxPending,
+			wantTracked: true,
+		},
+		{
+			name:       "replaced by a mined transaction",
+			eth:        &fakeMempoolEth{nonces: map[common.Address]uint64{alice: 6}},
+			wantStatus: types.MempoolTxReplaced,
+		},
+		{
+			name:       "dropped",
+			eth:        &fakeMempoolEth{nonces: map[common.Address]uint64{alice: 5}},
+			missingFor: time.Hour,
+			wantStatus: types.MempoolTxDropped,
+		},
+		{
+			name:        "nonce request failed",
+			eth:         &fakeMempoolEth{failing: map[interface{}]bool{alice: true}},
+			missingFor:  time.Hour,
+			wantStatus:  types.MempoolTxPending,
+			wantTracked: true,
+		},
+		{
+			name:        "missing shorter than the drop timeout",
+			eth:         &fakeMempoolEth{nonces: map[common.Address]uint64{alice: 5}},
+			wantStatus:  types.MempoolTxPending,
+			wantTracked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := geth_rpc.NewServer()
+			defer server.Stop()
+			err := server.RegisterName("eth", tt.eth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := geth_rpc.DialInProc(server)
+			defer client.Close()
+
+			h := newMempoolHistory()
+			h.track(newMempoolHistoryTransaction(tx, now.Add(-tt.missingFor-time.Minute)))
+			h.tracked[tx.Hash].missingSince = now.Add(-tt.missingFor)
+
+			resolved, err := h.resolve(client, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, tracked := h.tracked[tx.Hash]; tracked != tt.wantTracked {
+				t.Errorf("got tracked %v, want %v", tracked, tt.wantTracked)
+			}
+			if tt.wantTracked {
+				if len(resolved) != 0 || h.tracked[tx.Hash].tx.Status != tt.wantStatus {
+					t.Errorf("got %v resolved transactions and status %v, want none and %v", len(resolved), h.tracked[tx.Hash].tx.Status, tt.wantStatus)
+				}
+				return
+			}
+			if len(resolved) != 1 || resolved[0].Status != tt.wantStatus {
+				t.Fatalf("got resolved transactions %+v, want one with status %v", resolved, tt.wantStatus)
+			}
+			if tt.wantStatus == types.MempoolTxMined && (resolved[0].BlockNumber.Int64 != 100 || !resolved[0].BlockTs.Valid) {
+				t.Errorf("got block %v at %v, want block 100", resolved[0].BlockNumber, resolved[0].BlockTs)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
 	"crypto/sha256"
 	"database/sql"
 	"database/sql/driver"
@@ -14,6 +15,7 @@ import (
 	"html/template"
 	"io"
 	"math/big"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -34,6 +36,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -276,6 +279,48 @@ func collectNotifications(epoch uint64) (map[uint64]map[types.EventName][]types.
 	}
 	logger.Infof("collecting withdrawal notifications took: %v", time.Since(start))
 
+	err = collectConsolidationRequestNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_consolidation_request").Inc()
+		return nil, fmt.Errorf("error collecting consolidation request notifications: %v", err)
+	}
+	logger.Infof("collecting consolidation request notifications took: %v", time.Since(start))
+
+	err = collectProcessedConsolidationNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_consolidation_processed").Inc()
+		return nil, fmt.Errorf("error collecting processed consolidation notifications: %v", err)
+	}
+	logger.Infof("collecting processed consolidation notifications took: %v", time.Since(start))
+
+	err = collectSwitchToCompoundingNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_switched_to_compounding").Inc()
+		return nil, fmt.Errorf("error collecting switch to compounding notifications: %v", err)
+	}
+	logger.Infof("collecting switch to compounding notifications took: %v", time.Since(start))
+
+	err = collectWithdrawalRequestNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_withdrawal_request").Inc()
+		return nil, fmt.Errorf("error collecting withdrawal request notifications: %v", err)
+	}
+	logger.Infof("collecting withdrawal request notifications took: %v", time.Since(start))
+
+	err = collectQueueEstimateNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_queue_estimate_changed").Inc()
+		return nil, fmt.Errorf("error collecting queue estimate notifications: %v", err)
+	}
+	logger.Infof("collecting queue estimate notifications took: %v", time.Since(start))
+
+	err = collectSlashingDetectedNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_slashing_detected").Inc()
+		return nil, fmt.Errorf("error collecting detected slashing notifications: %v", err)
+	}
+	logger.Infof("collecting detected slashing notifications took: %v", time.Since(start))
+
 	err = collectNetworkNotifications(notificationsByUserID, types.NetworkLivenessIncreasedEventName)
 	if err != nil {
 		metrics.Errors.WithLabelValues("notifications_collect_network").Inc()
@@ -283,6 +328,13 @@ func collectNotifications(epoch uint64) (map[uint64]map[types.EventName][]types.
 	}
 	logger.Infof("collecting network notifications took: %v", time.Since(start))
 
+	err = collectChainReorgNotifications(notificationsByUserID)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_chain_reorg").Inc()
+		return nil, fmt.Errorf("error collecting chain reorg notifications: %v", err)
+	}
+	logger.Infof("collecting chain reorg notifications took: %v", time.Since(start))
+
 	// Rocketpool
 	{
 		var ts int64
@@ -470,7 +522,12 @@ func queueNotifications(notificationsByUserID map[uint64]map[types.EventName][]t
 
 func dispatchNotifications(useDB *sqlx.DB) error {
 
-	err := sendEmailNotifications(useDB)
+	err := flushNotificationDigests(useDB)
+	if err != nil {
+		return fmt.Errorf("error flushing notification digests, err: %w", err)
+	}
+
+	err = sendEmailNotifications(useDB)
 	if err != nil {
 		return fmt.Errorf("error sending email notifications, err: %w", err)
 	}
@@ -490,6 +547,21 @@ func dispatchNotifications(useDB *sqlx.DB) error {
 		return fmt.Errorf("error sending webhook discord notifications, err: %w", err)
 	}
 
+	err = sendSlackNotifications(useDB)
+	if err != nil {
+		return fmt.Errorf("error sending webhook slack notifications, err: %w", err)
+	}
+
+	err = sendTelegramNotifications(useDB)
+	if err != nil {
+		return fmt.Errorf("error sending telegram notifications, err: %w", err)
+	}
+
+	err = sendMatrixNotifications(useDB)
+	if err != nil {
+		return fmt.Errorf("error sending matrix notifications, err: %w", err)
+	}
+
 	return nil
 }
 
@@ -505,6 +577,15 @@ func garbageCollectNotificationQueue(useDB *sqlx.DB) error {
 
 	logger.Infof("deleted %v rows from the notification_queue", rowsAffected)
 
+	rows, err = useDB.Exec(`DELETE FROM users_webhooks_deliveries WHERE ts < now() - INTERVAL '30 days'`)
+	if err != nil {
+		return fmt.Errorf("error deleting from users_webhooks_deliveries %w", err)
+	}
+
+	rowsAffected, _ = rows.RowsAffected()
+
+	logger.Infof("deleted %v rows from the users_webhooks_deliveries", rowsAffected)
+
 	return nil
 }
 
@@ -622,6 +703,12 @@ func sendPushNotifications(useDB *sqlx.DB) error {
 }
 
 func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, useDB *sqlx.DB) error {
+	// notifications of digest subscriptions and notifications during quiet hours are sent later as a summary
+	notificationsByUserID, err := deferDigestNotifications(notificationsByUserID, useDB)
+	if err != nil {
+		return fmt.Errorf("error deferring digest notifications: %w", err)
+	}
+
 	userIDs := []uint64{}
 	for userID := range notificationsByUserID {
 		userIDs = append(userIDs, userID)
@@ -811,7 +898,8 @@ func queueWebhookNotifications(notificationsByUserID map[uint64]map[types.EventN
 				url,
 				retries,
 				event_names,
-				destination
+				destination,
+				verified_at
 			FROM 
 				users_webhooks
 			WHERE 
@@ -826,6 +914,8 @@ func queueWebhookNotifications(notificationsByUserID map[uint64]map[types.EventN
 		}
 		// webhook => [] notifications
 		discordNotifMap := make(map[uint64][]types.TransitDiscordContent)
+		// webhook => epoch => batch of chat messages
+		chatNotifMap := make(map[uint64]map[uint64]*types.TransitChatContent)
 		notifs := make([]types.TransitWebhook, 0)
 		// send the notifications to each registered webhook
 		for _, w := range webhooks {
@@ -858,6 +948,13 @@ func queueWebhookNotifications(notificationsByUserID map[uint64]map[types.EventN
 						}
 					}
 
+					if w.Destination.Valid && isChatDestination(w.Destination.String) {
+						if isChatPlatformConfigured(types.NotificationChannel(w.Destination.String)) && IsChatTargetVerified(w) {
+							queueChatNotifications(chatNotifMap, w, notifications)
+						}
+						continue
+					}
+
 					for _, n := range notifications {
 						if w.Destination.Valid && w.Destination.String == "webhook_discord" {
 							if _, exists := discordNotifMap[w.ID]; !exists {
@@ -938,10 +1035,88 @@ func queueWebhookNotifications(notificationsByUserID map[uint64]map[types.EventN
 				}
 			}
 		}
+		// process chat notifs
+		for _, batches := range chatNotifMap {
+			for _, n := range batches {
+				_, err = useDB.Exec(`INSERT INTO notification_queue (created, channel, content) VALUES (now(), $1, $2);`, n.Webhook.Destination.String, n)
+				if err != nil {
+					logger.WithError(err).Errorf("error inserting into webhooks_queue (%v)", n.Webhook.Destination.String)
+					continue
+				}
+				metrics.NotificationsQueued.WithLabelValues(n.Webhook.Destination.String, "multi").Inc()
+			}
+		}
 	}
 	return nil
 }
 
+// QueueWebhookRedelivery queues a delivered webhook event again, the event is converted to the content of the channel
+// the webhook is currently configured for
+func QueueWebhookRedelivery(useDB *sqlx.DB, w types.UserWebhook, event types.WebhookEvent) error {
+	channel := types.WebhookNotificationChannel
+	if w.Destination.Valid && w.Destination.String != "" {
+		channel = types.NotificationChannel(w.Destination.String)
+	}
+
+	var content interface{}
+	switch {
+	case channel == types.WebhookDiscordNotificationChannel:
+		content = types.TransitDiscordContent{
+			Webhook: w,
+			DiscordRequest: types.DiscordReq{
+				Username: utils.Config.Frontend.SiteDomain,
+				Embeds: []types.DiscordEmbed{
+					{
+						Type:        "rich",
+						Color:       "16745472",
+						Description: event.Description,
+						Title:       event.Title,
+						Fields: []types.DiscordEmbedField{
+							{
+								Name:   "Epoch",
+								Value:  fmt.Sprintf("[%[1]v](https://%[2]s/%[1]v)", event.Epoch, utils.Config.Frontend.SiteDomain+"/epoch"),
+								Inline: false,
+							},
+						},
+					},
+				},
+			},
+		}
+	case isChatDestination(string(channel)):
+		if !isChatPlatformConfigured(channel) || !IsChatTargetVerified(w) {
+			return fmt.Errorf("the %v target of webhook %v is not configured or verified", channel, w.ID)
+		}
+		content = types.TransitChatContent{
+			Webhook:  w,
+			Epoch:    event.Epoch,
+			Messages: []types.ChatMessage{{Title: event.Title, Markdown: event.Description, Target: event.Target}},
+		}
+	default:
+		channel = types.WebhookNotificationChannel
+		content = types.TransitWebhookContent{Webhook: w, Event: event}
+	}
+
+	_, err := useDB.Exec(`INSERT INTO notification_queue (created, channel, content) VALUES (now(), $1, $2);`, channel, content)
+	if err != nil {
+		return fmt.Errorf("error inserting into notification_queue (%v): %w", channel, err)
+	}
+	metrics.NotificationsQueued.WithLabelValues(string(channel), event.Name).Inc()
+	return nil
+}
+
+const (
+	// webhookMaxAttempts is the number of delivery attempts after which a webhook notification is dropped
+	webhookMaxAttempts = 6
+	// webhookRetryBaseDelay is the delay before the first retry, it doubles with every further attempt
+	webhookRetryBaseDelay = time.Second * 10
+	// webhookRetryMaxDelay caps the delay between two attempts
+	webhookRetryMaxDelay = time.Minute * 10
+	// webhookResponseSnippetLength is the number of bytes of the response body stored in the delivery log
+	webhookResponseSnippetLength = 1024
+	// webhookDeliveryConcurrency is the number of webhook notifications delivered in parallel
+	webhookDeliveryConcurrency = 10
+)
+
 func sendWebhookNotifications(useDB *sqlx.DB) error {
 	var notificationQueueItem []types.TransitWebhook
 
@@ -950,8 +1125,9 @@ func sendWebhookNotifications(useDB *sqlx.DB) error {
 		created,
 		sent,
 		channel,
-		content
-	FROM notification_queue WHERE sent IS null AND channel = 'webhook' ORDER BY created ASC`)
+		content,
+		attempts
+	FROM notification_queue WHERE sent IS null AND channel = 'webhook' AND (next_attempt IS NULL OR next_attempt <= now()) ORDER BY created ASC`)
 	if err != nil {
 		return fmt.Errorf("error querying notification queue, err: %w", err)
 	}
@@ -959,78 +1135,179 @@ func sendWebhookNotifications(useDB *sqlx.DB) error {
 
 	logger.Infof("processing %v webhook notifications", len(notificationQueueItem))
 
+	// the secrets are not part of the queued content, they are retrieved when sending so rotated secrets are used right away
+	webhookIDs := make([]uint64, 0, len(notificationQueueItem))
 	for _, n := range notificationQueueItem {
-		// do not retry after 5 attempts
-		if n.Content.Webhook.Retries > 5 {
-			_, err := db.FrontendWriterDB.Exec(`DELETE FROM notification_queue WHERE id = $1`, n.Id)
+		webhookIDs = append(webhookIDs, n.Content.Webhook.ID)
+	}
+	var webhooks []types.UserWebhook
+	err = useDB.Select(&webhooks, `SELECT id, user_id, url, secret FROM users_webhooks WHERE id = ANY($1)`, pq.Array(webhookIDs))
+	if err != nil {
+		return fmt.Errorf("error querying users_webhooks, err: %w", err)
+	}
+	webhooksByID := make(map[uint64]types.UserWebhook, len(webhooks))
+	for _, w := range webhooks {
+		webhooksByID[w.ID] = w
+	}
+
+	g := errgroup.Group{}
+	g.SetLimit(webhookDeliveryConcurrency)
+	for _, n := range notificationQueueItem {
+		n := n
+		webhook, exists := webhooksByID[n.Content.Webhook.ID]
+		if !exists {
+			// the webhook has been deleted in the meantime
+			_, err := useDB.Exec(`DELETE FROM notification_queue WHERE id = $1`, n.Id)
 			if err != nil {
 				return fmt.Errorf("error deleting from notification queue: %w", err)
 			}
 			continue
 		}
 
-		reqBody := new(bytes.Buffer)
-
-		err := json.NewEncoder(reqBody).Encode(n.Content)
+		_, err = url.Parse(webhook.Url)
 		if err != nil {
-			logger.WithError(err).Errorf("error marschalling webhook event")
-		}
-
-		_, err = url.Parse(n.Content.Webhook.Url)
-		if err != nil {
-			_, err := db.FrontendWriterDB.Exec(`DELETE FROM notification_queue WHERE id = $1`, n.Id)
+			_, err := useDB.Exec(`DELETE FROM notification_queue WHERE id = $1`, n.Id)
 			if err != nil {
 				return fmt.Errorf("error deleting from notification queue: %w", err)
 			}
 			continue
 		}
 
-		go func(n types.TransitWebhook) {
-			if n.Content.Webhook.Retries > 0 {
-				time.Sleep(time.Duration(n.Content.Webhook.Retries) * time.Second)
-			}
-			resp, err := client.Post(n.Content.Webhook.Url, "application/json", reqBody)
-			if err != nil {
-				logger.WithError(err).Warnf("error sending request")
-			} else {
-				metrics.NotificationsSent.WithLabelValues("webhook", resp.Status).Inc()
-			}
+		g.Go(func() error {
+			deliverWebhookNotification(useDB, client, n, webhook)
+			return nil
+		})
+	}
+	return g.Wait()
+}
 
-			_, err = useDB.Exec(`UPDATE notification_queue SET sent = now() WHERE id = $1`, n.Id)
-			if err != nil {
-				logger.WithError(err).Errorf("error updating notification_queue table")
-				return
-			}
+// deliverWebhookNotification posts a queued notification to the current url of its webhook, records the attempt in the
+// delivery log and either marks the notification as sent or schedules the next attempt
+func deliverWebhookNotification(useDB *sqlx.DB, client *http.Client, n types.TransitWebhook, webhook types.UserWebhook) {
+	attempt := n.Attempts + 1
 
-			if resp != nil && resp.StatusCode < 400 {
-				_, err = useDB.Exec(`UPDATE users_webhooks SET retries = 0, last_sent = now() WHERE id = $1;`, n.Content.Webhook.ID)
-				if err != nil {
-					logger.WithError(err).Errorf("error updating users_webhooks table; setting retries to zero")
-					return
-				}
-			} else {
-				var errResp types.ErrorResponse
+	reqBody, err := json.Marshal(n.Content)
+	if err != nil {
+		logger.WithError(err).Errorf("error marshalling webhook event")
+		return
+	}
 
-				if resp != nil {
-					b, err := io.ReadAll(resp.Body)
-					if err != nil {
-						logger.WithError(err).Warn("error reading body")
-					} else {
-						errResp.Status = resp.Status
-						errResp.Body = string(b)
-					}
-				}
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(reqBody))
+	if err != nil {
+		logger.WithError(err).Warnf("error creating webhook request")
+		_, err = useDB.Exec(`DELETE FROM notification_queue WHERE id = $1`, n.Id)
+		if err != nil {
+			logger.WithError(err).Errorf("error deleting from notification queue")
+		}
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Delivery", fmt.Sprintf("%d", n.Id))
+	req.Header.Set("X-Webhook-Attempt", fmt.Sprintf("%d", attempt))
+	if webhook.Secret.Valid && webhook.Secret.String != "" {
+		ts := time.Now().Unix()
+		req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", ts))
+		req.Header.Set("X-Webhook-Signature", "sha256="+signWebhookPayload(webhook.Secret.String, ts, reqBody))
+	}
 
-				_, err = useDB.Exec(`UPDATE users_webhooks SET retries = retries + 1, last_sent = now(), request = $2, response = $3 WHERE id = $1;`, n.Content.Webhook.ID, n.Content, errResp)
-				if err != nil {
-					logger.WithError(err).Errorf("error updating users_webhooks table; increasing retries")
-					return
-				}
-			}
-		}(n)
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
 
+	delivery := &types.UserWebhookDelivery{
+		WebhookID:      webhook.ID,
+		NotificationID: n.Id,
+		Ts:             start,
+		Attempt:        attempt,
+		EventName:      n.Content.Event.Name,
+		Request:        reqBody,
+		LatencyMs:      uint64(latency.Milliseconds()),
 	}
-	return nil
+
+	var errResp types.ErrorResponse
+	if err != nil {
+		logger.WithError(err).Warnf("error sending request")
+		errMsg := err.Error()
+		delivery.Error = &errMsg
+	} else {
+		defer resp.Body.Close()
+		metrics.NotificationsSent.WithLabelValues("webhook", resp.Status).Inc()
+
+		statusCode := int64(resp.StatusCode)
+		delivery.StatusCode = &statusCode
+
+		b, err := io.ReadAll(io.LimitReader(resp.Body, webhookResponseSnippetLength))
+		if err != nil {
+			logger.WithError(err).Warn("error reading body")
+		} else {
+			snippet := strings.ToValidUTF8(string(b), "")
+			delivery.ResponseSnippet = &snippet
+			errResp.Body = snippet
+		}
+		errResp.Status = resp.Status
+	}
+
+	_, err = useDB.Exec(`
+		INSERT INTO users_webhooks_deliveries (webhook_id, user_id, notification_id, ts, attempt, event_name, request, status_code, latency_ms, response_snippet, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		delivery.WebhookID, webhook.UserID, delivery.NotificationID, delivery.Ts, delivery.Attempt, delivery.EventName, []byte(delivery.Request), delivery.StatusCode, delivery.LatencyMs, delivery.ResponseSnippet, delivery.Error)
+	if err != nil {
+		logger.WithError(err).Errorf("error inserting into users_webhooks_deliveries table")
+	}
+
+	if resp != nil && resp.StatusCode < 400 {
+		_, err = useDB.Exec(`UPDATE notification_queue SET sent = now(), attempts = $2 WHERE id = $1`, n.Id, attempt)
+		if err != nil {
+			logger.WithError(err).Errorf("error updating notification_queue table")
+			return
+		}
+		_, err = useDB.Exec(`UPDATE users_webhooks SET retries = 0, last_sent = now() WHERE id = $1;`, webhook.ID)
+		if err != nil {
+			logger.WithError(err).Errorf("error updating users_webhooks table; setting retries to zero")
+		}
+		return
+	}
+
+	if attempt < webhookMaxAttempts {
+		_, err = useDB.Exec(`UPDATE notification_queue SET attempts = $2, next_attempt = $3 WHERE id = $1`, n.Id, attempt, time.Now().Add(webhookRetryDelay(attempt)))
+		if err != nil {
+			logger.WithError(err).Errorf("error scheduling retry in notification_queue table")
+		}
+		return
+	}
+
+	// all attempts failed, give up on the notification and count the failure for the webhook
+	_, err = useDB.Exec(`UPDATE notification_queue SET sent = now(), attempts = $2 WHERE id = $1`, n.Id, attempt)
+	if err != nil {
+		logger.WithError(err).Errorf("error updating notification_queue table")
+		return
+	}
+	_, err = useDB.Exec(`UPDATE users_webhooks SET retries = retries + 1, last_sent = now(), request = $2, response = $3 WHERE id = $1;`, webhook.ID, n.Content, errResp)
+	if err != nil {
+		logger.WithError(err).Errorf("error updating users_webhooks table; increasing retries")
+	}
+}
+
+// signWebhookPayload returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>" using the secret of the webhook.
+// Including the timestamp in the signature allows receivers to reject replayed requests.
+func signWebhookPayload(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", ts)))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay returns the delay before the next attempt after the given number of failed attempts, the
+// exponential backoff is jittered between half and the full delay to spread out retries of failing receivers
+func webhookRetryDelay(attempt uint64) time.Duration {
+	delay := webhookRetryMaxDelay
+	if attempt < 16 {
+		delay = webhookRetryBaseDelay << (attempt - 1)
+	}
+	if delay > webhookRetryMaxDelay {
+		delay = webhookRetryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
 }
 
 func sendDiscordNotifications(useDB *sqlx.DB) error {
@@ -2586,6 +2863,119 @@ func collectNetworkNotifications(notificationsByUserID map[uint64]map[types.Even
 	return nil
 }
 
+type chainReorgNotification struct {
+	SubscriptionID  uint64
+	UserID          uint64
+	Epoch           uint64
+	EventFilter     string
+	Slot            uint64
+	Depth           uint64
+	UnsubscribeHash sql.NullString
+}
+
+func (n *chainReorgNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *chainReorgNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *chainReorgNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *chainReorgNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *chainReorgNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *chainReorgNotification) GetEventName() types.EventName {
+	return types.NetworkChainReorgEventName
+}
+
+func (n *chainReorgNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`A chain reorg orphaning %v block(s) starting at slot %v has been detected.`, n.Depth, n.Slot)
+	if includeUrl {
+		return generalPart + fmt.Sprintf(` Learn more at https://%v/slot/%v`, utils.Config.Frontend.SiteDomain, n.Slot)
+	}
+	return generalPart
+}
+
+func (n *chainReorgNotification) GetTitle() string {
+	return "Chain Reorg"
+}
+
+func (n *chainReorgNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *chainReorgNotification) GetInfoMarkdown() string {
+	return fmt.Sprintf(`A chain reorg orphaning %v block(s) starting at slot [%v](https://%v/slot/%v) has been detected.`, n.Depth, n.Slot, utils.Config.Frontend.SiteDomain, n.Slot)
+}
+
+// collectChainReorgNotifications notifies the subscribers about the latest chain reorg detected by the slot exporter
+// within the last hour, unless they have already been notified about it
+func collectChainReorgNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification) error {
+	var reorgs []*types.ChainReorg
+	err := db.WriterDb.Select(&reorgs, `
+		SELECT ts, slot, epoch, depth, old_blockroot, new_blockroot FROM chain_reorgs WHERE ts > now() - interval '60 minutes' ORDER BY ts DESC LIMIT 1;
+	`)
+	if err != nil {
+		return err
+	}
+
+	if len(reorgs) == 0 {
+		return nil
+	}
+	reorg := reorgs[0]
+
+	var dbResult []struct {
+		SubscriptionID  uint64         `db:"id"`
+		UserID          uint64         `db:"user_id"`
+		EventFilter     string         `db:"event_filter"`
+		UnsubscribeHash sql.NullString `db:"unsubscribe_hash"`
+	}
+
+	err = db.FrontendWriterDB.Select(&dbResult, `
+		SELECT us.id, us.user_id, us.event_filter, ENCODE(us.unsubscribe_hash, 'hex') AS unsubscribe_hash
+		FROM users_subscriptions AS us
+		WHERE us.event_name=$1 AND (us.last_sent_ts < $2 OR us.last_sent_ts IS NULL);
+		`,
+		utils.GetNetwork()+":"+string(types.NetworkChainReorgEventName), reorg.Ts)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range dbResult {
+		n := &chainReorgNotification{
+			SubscriptionID:  r.SubscriptionID,
+			UserID:          r.UserID,
+			Epoch:           reorg.Epoch,
+			EventFilter:     r.EventFilter,
+			Slot:            reorg.Slot,
+			Depth:           reorg.Depth,
+			UnsubscribeHash: r.UnsubscribeHash,
+		}
+		if _, exists := notificationsByUserID[r.UserID]; !exists {
+			notificationsByUserID[r.UserID] = map[types.EventName][]types.Notification{}
+		}
+		if _, exists := notificationsByUserID[r.UserID][n.GetEventName()]; !exists {
+			notificationsByUserID[r.UserID][n.GetEventName()] = []types.Notification{}
+		}
+		notificationsByUserID[r.UserID][n.GetEventName()] = append(notificationsByUserID[r.UserID][n.GetEventName()], n)
+		metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+	}
+
+	return nil
+}
+
 type rocketpoolNotification struct {
 	SubscriptionID  uint64
 	UserID          uint64
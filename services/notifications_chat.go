@@ -0,0 +1,594 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+)
+
+// markdownLinkRegex matches the [text](url) links used by GetInfoMarkdown, which is the only markdown construct
+// the notifications contain
+var markdownLinkRegex = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+
+const defaultTelegramApiUrl = "https://api.telegram.org"
+
+// chatPlatform describes how the batched notifications of a chat channel are rendered and sent
+type chatPlatform struct {
+	channel types.NotificationChannel
+	// maxMessageLength is the maximum length of a single rendered message, batches exceeding it are split
+	maxMessageLength int
+	// destinationInterval is the minimum time between two messages sent to the same chat
+	destinationInterval time.Duration
+	// limiter throttles the messages sent to the platform across all chats
+	limiter *rate.Limiter
+	render  func(messages []types.ChatMessage) string
+	send    func(ctx context.Context, client *http.Client, webhook types.UserWebhook, txnID string, messages []types.ChatMessage) error
+}
+
+// chatRateLimitError is returned by a platform if it rejected a message due to rate limiting
+type chatRateLimitError struct {
+	retryAfter time.Duration
+}
+
+func (e *chatRateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %v", e.retryAfter)
+}
+
+// chatPlatforms contains the settings of the platforms, the limits follow the documented limits of each platform:
+// telegram allows about 30 messages per second per bot and 20 messages per minute per group,
+// slack allows one message per second per incoming webhook and matrix homeservers throttle per account.
+var chatPlatforms = map[types.NotificationChannel]*chatPlatform{
+	types.TelegramNotificationChannel: {
+		channel:             types.TelegramNotificationChannel,
+		maxMessageLength:    4096,
+		destinationInterval: time.Second * 3,
+		limiter:             rate.NewLimiter(25, 1),
+		render:              renderTelegramMessage,
+		send:                sendTelegramMessage,
+	},
+	types.WebhookSlackNotificationChannel: {
+		channel:             types.WebhookSlackNotificationChannel,
+		maxMessageLength:    3000,
+		destinationInterval: time.Second,
+		limiter:             rate.NewLimiter(rate.Inf, 1),
+		render:              renderSlackMessage,
+		send:                sendSlackMessage,
+	},
+	types.MatrixNotificationChannel: {
+		channel:             types.MatrixNotificationChannel,
+		maxMessageLength:    32000,
+		destinationInterval: time.Second,
+		limiter:             rate.NewLimiter(5, 1),
+		render:              renderMatrixMessage,
+		send:                sendMatrixMessage,
+	},
+}
+
+// isChatPlatformConfigured returns whether the credentials needed to send messages to the platform are configured
+func isChatPlatformConfigured(channel types.NotificationChannel) bool {
+	switch channel {
+	case types.TelegramNotificationChannel:
+		return utils.Config.Notifications.Telegram.BotToken != ""
+	case types.MatrixNotificationChannel:
+		return utils.Config.Notifications.Matrix.HomeserverUrl != "" && utils.Config.Notifications.Matrix.AccessToken != ""
+	case types.WebhookSlackNotificationChannel:
+		return true
+	}
+	return false
+}
+
+// IsChatTargetVerified returns whether notifications may be sent to the chat of a webhook. All users share the same
+// telegram and matrix bot, so chats and rooms have to be verified to prevent users from sending notifications to chats
+// the bot was added to by someone else.
+func IsChatTargetVerified(w types.UserWebhook) bool {
+	switch types.NotificationChannel(w.Destination.String) {
+	case types.TelegramNotificationChannel, types.MatrixNotificationChannel:
+		return w.VerifiedAt.Valid && w.Url != ""
+	}
+	return true
+}
+
+// isChatDestination returns whether a webhook destination is one of the chat channels
+func isChatDestination(destination string) bool {
+	for _, ch := range types.ChatNotificationChannels {
+		if string(ch) == destination {
+			return true
+		}
+	}
+	return false
+}
+
+// renderMarkdownLinks replaces the links of a markdown text using the link function and escapes the remaining text
+func renderMarkdownLinks(markdown string, escape func(string) string, link func(text, url string) string) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range markdownLinkRegex.FindAllStringSubmatchIndex(markdown, -1) {
+		sb.WriteString(escape(markdown[last:m[0]]))
+		sb.WriteString(link(markdown[m[2]:m[3]], markdown[m[4]:m[5]]))
+		last = m[1]
+	}
+	sb.WriteString(escape(markdown[last:]))
+	return sb.String()
+}
+
+// renderHtmlMessage renders the messages as the html subset supported by telegram and matrix clients
+func renderHtmlMessage(messages []types.ChatMessage) string {
+	parts := make([]string, 0, len(messages))
+	for _, m := range messages {
+		body := renderMarkdownLinks(m.Markdown, html.EscapeString, func(text, url string) string {
+			return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), html.EscapeString(text))
+		})
+		parts = append(parts, fmt.Sprintf("<b>%s</b>\n%s", html.EscapeString(m.Title), body))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func renderTelegramMessage(messages []types.ChatMessage) string {
+	return renderHtmlMessage(messages)
+}
+
+func renderMatrixMessage(messages []types.ChatMessage) string {
+	// matrix clients ignore newlines in formatted bodies
+	return strings.ReplaceAll(renderHtmlMessage(messages), "\n", "<br>")
+}
+
+// slackEscaper escapes the control characters of the slack mrkdwn format
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func renderSlackMessage(messages []types.ChatMessage) string {
+	parts := make([]string, 0, len(messages))
+	for _, m := range messages {
+		body := renderMarkdownLinks(m.Markdown, slackEscaper.Replace, func(text, url string) string {
+			return fmt.Sprintf("<%s|%s>", url, slackEscaper.Replace(text))
+		})
+		parts = append(parts, fmt.Sprintf("*%s*\n%s", slackEscaper.Replace(m.Title), body))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// splitChatMessages splits the messages of a batch into chunks that do not exceed the maximum message length of the
+// platform once rendered, a single message exceeding the length is sent on its own and truncated by the platform
+func splitChatMessages(messages []types.ChatMessage, render func([]types.ChatMessage) string, maxLength int) [][]types.ChatMessage {
+	chunks := [][]types.ChatMessage{}
+	start := 0
+	for i := range messages {
+		if i > start && len(render(messages[start:i+1])) > maxLength {
+			chunks = append(chunks, messages[start:i])
+			start = i
+		}
+	}
+	if start < len(messages) {
+		chunks = append(chunks, messages[start:])
+	}
+	return chunks
+}
+
+// parseRetryAfter parses the seconds of a Retry-After header, a default of one minute is used if it is missing
+func parseRetryAfter(header string) time.Duration {
+	seconds, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// postChatRequest sends a json encoded chat request and returns the response body, responses with status 429 are
+// returned as chatRateLimitError using the Retry-After header or the retry delay parsed from the body
+func postChatRequest(ctx context.Context, client *http.Client, channel types.NotificationChannel, method, url string, headers map[string]string, payload interface{}, retryAfterFromBody func([]byte) time.Duration) ([]byte, error) {
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling %v request: %w", channel, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating %v request: %w", channel, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending %v request: %w", channel, err)
+	}
+	defer resp.Body.Close()
+	metrics.NotificationsSent.WithLabelValues(string(channel), resp.Status).Inc()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, webhookResponseSnippetLength))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %v response: %w", channel, err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if resp.Header.Get("Retry-After") == "" && retryAfterFromBody != nil {
+			if d := retryAfterFromBody(body); d > 0 {
+				retryAfter = d
+			}
+		}
+		return nil, &chatRateLimitError{retryAfter: retryAfter}
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%v request failed with status %v: %s", channel, resp.Status, body)
+	}
+	return body, nil
+}
+
+func sendTelegramMessage(ctx context.Context, client *http.Client, webhook types.UserWebhook, txnID string, messages []types.ChatMessage) error {
+	apiUrl := utils.Config.Notifications.Telegram.ApiUrl
+	if apiUrl == "" {
+		apiUrl = defaultTelegramApiUrl
+	}
+
+	payload := map[string]interface{}{
+		"chat_id":                  webhook.Url,
+		"text":                     renderTelegramMessage(messages),
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}
+	_, err := postChatRequest(ctx, client, types.TelegramNotificationChannel, http.MethodPost, fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimSuffix(apiUrl, "/"), utils.Config.Notifications.Telegram.BotToken), nil, payload, func(body []byte) time.Duration {
+		var resp struct {
+			Parameters struct {
+				RetryAfter int64 `json:"retry_after"`
+			} `json:"parameters"`
+		}
+		if json.Unmarshal(body, &resp) != nil {
+			return 0
+		}
+		return time.Duration(resp.Parameters.RetryAfter) * time.Second
+	})
+	return err
+}
+
+func sendSlackMessage(ctx context.Context, client *http.Client, webhook types.UserWebhook, txnID string, messages []types.ChatMessage) error {
+	payload := map[string]interface{}{
+		"text":         renderSlackMessage(messages),
+		"unfurl_links": false,
+	}
+	_, err := postChatRequest(ctx, client, types.WebhookSlackNotificationChannel, http.MethodPost, webhook.Url, nil, payload, nil)
+	return err
+}
+
+func sendMatrixMessage(ctx context.Context, client *http.Client, webhook types.UserWebhook, txnID string, messages []types.ChatMessage) error {
+	plain := make([]string, 0, len(messages))
+	for _, m := range messages {
+		plain = append(plain, m.Title+"\n"+m.Markdown)
+	}
+
+	payload := map[string]interface{}{
+		"msgtype":        "m.notice",
+		"body":           strings.Join(plain, "\n\n"),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": renderMatrixMessage(messages),
+	}
+	// the transaction id makes the homeserver ignore messages that are sent again after a timeout
+	reqUrl := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s", strings.TrimSuffix(utils.Config.Notifications.Matrix.HomeserverUrl, "/"), url.PathEscape(webhook.Url), url.PathEscape(txnID))
+	headers := map[string]string{"Authorization": "Bearer " + utils.Config.Notifications.Matrix.AccessToken}
+	_, err := postChatRequest(ctx, client, types.MatrixNotificationChannel, http.MethodPut, reqUrl, headers, payload, func(body []byte) time.Duration {
+		var resp struct {
+			RetryAfterMs int64 `json:"retry_after_ms"`
+		}
+		if json.Unmarshal(body, &resp) != nil {
+			return 0
+		}
+		return time.Duration(resp.RetryAfterMs) * time.Millisecond
+	})
+	return err
+}
+
+func sendTelegramNotifications(useDB *sqlx.DB) error {
+	return sendChatNotifications(useDB, chatPlatforms[types.TelegramNotificationChannel])
+}
+
+func sendSlackNotifications(useDB *sqlx.DB) error {
+	return sendChatNotifications(useDB, chatPlatforms[types.WebhookSlackNotificationChannel])
+}
+
+func sendMatrixNotifications(useDB *sqlx.DB) error {
+	return sendChatNotifications(useDB, chatPlatforms[types.MatrixNotificationChannel])
+}
+
+// sendChatNotifications sends the due notification batches of a chat channel. The batches of a chat are sent in
+// order, if the platform rate limits a chat its remaining batches are postponed until the limit has passed.
+func sendChatNotifications(useDB *sqlx.DB, platform *chatPlatform) error {
+	if !isChatPlatformConfigured(platform.channel) {
+		return nil
+	}
+
+	var notificationQueueItem []types.TransitChat
+	err := useDB.Select(&notificationQueueItem, `SELECT
+		id,
+		created,
+		sent,
+		channel,
+		content,
+		attempts
+	FROM notification_queue WHERE sent IS null AND channel = $1 AND (next_attempt IS NULL OR next_attempt <= now()) ORDER BY created ASC`, platform.channel)
+	if err != nil {
+		return fmt.Errorf("error querying notification queue, err: %w", err)
+	}
+	if len(notificationQueueItem) == 0 {
+		return nil
+	}
+
+	logger.Infof("processing %v %v notifications", len(notificationQueueItem), platform.channel)
+
+	// the current destination is used so changes of the chat id take effect right away
+	webhookIDs := make([]uint64, 0, len(notificationQueueItem))
+	for _, n := range notificationQueueItem {
+		webhookIDs = append(webhookIDs, n.Content.Webhook.ID)
+	}
+	var webhooks []types.UserWebhook
+	err = useDB.Select(&webhooks, `SELECT id, user_id, url, destination, verified_at FROM users_webhooks WHERE id = ANY($1)`, pq.Array(webhookIDs))
+	if err != nil {
+		return fmt.Errorf("error querying users_webhooks, err: %w", err)
+	}
+	webhooksByID := make(map[uint64]types.UserWebhook, len(webhooks))
+	for _, w := range webhooks {
+		webhooksByID[w.ID] = w
+	}
+
+	itemsByWebhook := make(map[uint64][]types.TransitChat)
+	for _, n := range notificationQueueItem {
+		webhook, exists := webhooksByID[n.Content.Webhook.ID]
+		if !exists || webhook.Destination.String != string(platform.channel) || !IsChatTargetVerified(webhook) {
+			// the webhook has been deleted, changed to another platform or to a chat that is not verified in the meantime
+			_, err := useDB.Exec(`DELETE FROM notification_queue WHERE id = $1`, n.Id)
+			if err != nil {
+				return fmt.Errorf("error deleting from notification queue: %w", err)
+			}
+			continue
+		}
+		itemsByWebhook[webhook.ID] = append(itemsByWebhook[webhook.ID], n)
+	}
+
+	client := &http.Client{Timeout: time.Second * 30}
+	g := errgroup.Group{}
+	g.SetLimit(webhookDeliveryConcurrency)
+	for webhookID, items := range itemsByWebhook {
+		webhook := webhooksByID[webhookID]
+		items := items
+		g.Go(func() error {
+			deliverChatNotifications(useDB, client, platform, webhook, items)
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// deliverChatNotifications sends the queued batches of a single chat
+func deliverChatNotifications(useDB *sqlx.DB, client *http.Client, platform *chatPlatform, webhook types.UserWebhook, items []types.TransitChat) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	var lastSent time.Time
+	for i, n := range items {
+		attempt := n.Attempts + 1
+
+		delivered, err := sendChatBatch(ctx, client, platform, webhook, n, attempt, &lastSent)
+		if err == nil {
+			_, err = useDB.Exec(`UPDATE notification_queue SET sent = now(), attempts = $2 WHERE id = $1`, n.Id, attempt)
+			if err != nil {
+				logger.WithError(err).Errorf("error updating notification_queue table")
+				continue
+			}
+			_, err = useDB.Exec(`UPDATE users_webhooks SET retries = 0, last_sent = now() WHERE id = $1;`, webhook.ID)
+			if err != nil {
+				logger.WithError(err).Errorf("error updating users_webhooks table; setting retries to zero")
+			}
+			continue
+		}
+
+		// only the messages that have not been delivered yet are sent again
+		n.Content.Messages = n.Content.Messages[delivered:]
+
+		var rateLimitErr *chatRateLimitError
+		if errors.As(err, &rateLimitErr) {
+			logger.Warnf("could not send %v notification due to rate limit, retrying in %v", platform.channel, rateLimitErr.retryAfter)
+			ids := make([]uint64, 0, len(items)-i)
+			for _, item := range items[i:] {
+				ids = append(ids, item.Id)
+			}
+			_, err = useDB.Exec(`UPDATE notification_queue SET next_attempt = $2 WHERE id = ANY($1)`, pq.Array(ids), time.Now().Add(rateLimitErr.retryAfter))
+			if err != nil {
+				logger.WithError(err).Errorf("error postponing rate limited %v notifications", platform.channel)
+			}
+			_, err = useDB.Exec(`UPDATE notification_queue SET content = $2 WHERE id = $1`, n.Id, n.Content)
+			if err != nil {
+				logger.WithError(err).Errorf("error updating content of %v notification", platform.channel)
+			}
+			return
+		}
+		if ctx.Err() != nil {
+			// the remaining batches are sent in the next run
+			return
+		}
+
+		utils.LogWarn(err, fmt.Sprintf("error sending %v notification", platform.channel), 0, map[string]interface{}{"webhook_id": webhook.ID})
+		failChatNotification(useDB, webhook, n, attempt, err)
+	}
+}
+
+// sendChatBatch sends the messages of a queued batch in chunks fitting the platform limits and returns the number of
+// messages delivered before an error occurred
+func sendChatBatch(ctx context.Context, client *http.Client, platform *chatPlatform, webhook types.UserWebhook, n types.TransitChat, attempt uint64, lastSent *time.Time) (int, error) {
+	delivered := 0
+	for i, chunk := range splitChatMessages(n.Content.Messages, platform.render, platform.maxMessageLength) {
+		if wait := platform.destinationInterval - time.Since(*lastSent); wait > 0 {
+			time.Sleep(wait)
+		}
+		err := platform.limiter.Wait(ctx)
+		if err != nil {
+			return delivered, fmt.Errorf("error waiting for %v rate limiter: %w", platform.channel, err)
+		}
+
+		err = platform.send(ctx, client, webhook, fmt.Sprintf("%d-%d-%d", n.Id, attempt, i), chunk)
+		*lastSent = time.Now()
+		if err != nil {
+			return delivered, err
+		}
+		delivered += len(chunk)
+	}
+	return delivered, nil
+}
+
+// failChatNotification schedules the next attempt of a batch that could not be sent or gives up on it once all
+// attempts failed
+func failChatNotification(useDB *sqlx.DB, webhook types.UserWebhook, n types.TransitChat, attempt uint64, sendErr error) {
+	if attempt < webhookMaxAttempts {
+		_, err := useDB.Exec(`UPDATE notification_queue SET attempts = $2, next_attempt = $3, content = $4 WHERE id = $1`, n.Id, attempt, time.Now().Add(webhookRetryDelay(attempt)), n.Content)
+		if err != nil {
+			logger.WithError(err).Errorf("error scheduling retry in notification_queue table")
+		}
+		return
+	}
+
+	_, err := useDB.Exec(`UPDATE notification_queue SET sent = now(), attempts = $2 WHERE id = $1`, n.Id, attempt)
+	if err != nil {
+		logger.WithError(err).Errorf("error updating notification_queue table")
+		return
+	}
+	errResp := types.ErrorResponse{Status: "error", Body: sendErr.Error()}
+	_, err = useDB.Exec(`UPDATE users_webhooks SET retries = retries + 1, last_sent = now(), request = $2, response = $3 WHERE id = $1;`, webhook.ID, n.Content, errResp)
+	if err != nil {
+		logger.WithError(err).Errorf("error updating users_webhooks table; increasing retries")
+	}
+}
+
+// queueChatNotifications adds the notifications of a user for a chat webhook to the batches of the webhook, a batch
+// contains all notifications of an epoch so users receive a single message per epoch
+func queueChatNotifications(batches map[uint64]map[uint64]*types.TransitChatContent, w types.UserWebhook, notifications []types.Notification) {
+	if _, exists := batches[w.ID]; !exists {
+		batches[w.ID] = make(map[uint64]*types.TransitChatContent)
+	}
+	for _, n := range notifications {
+		batch, exists := batches[w.ID][n.GetEpoch()]
+		if !exists {
+			batch = &types.TransitChatContent{
+				Webhook: w,
+				Epoch:   n.GetEpoch(),
+			}
+			batches[w.ID][n.GetEpoch()] = batch
+		}
+		batch.Messages = append(batch.Messages, types.ChatMessage{
+			Title:    n.GetTitle(),
+			Markdown: n.GetInfoMarkdown(),
+			Target:   n.GetEventFilter(),
+		})
+	}
+}
+
+// telegramStartCommandRegex matches the /start command sent by telegram when a chat is opened via a deep link, the
+// bot username is appended to the command in groups
+var telegramStartCommandRegex = regexp.MustCompile(`^/start(@[A-Za-z0-9_]+)?\s+([A-Za-z0-9_-]{1,64})\s*$`)
+
+// ParseTelegramStartToken returns the deep link token of a /start command sent to the bot
+func ParseTelegramStartToken(text string) (string, bool) {
+	m := telegramStartCommandRegex.FindStringSubmatch(text)
+	if m == nil {
+		return "", false
+	}
+	if m[1] != "" && !strings.EqualFold(m[1][1:], utils.Config.Notifications.Telegram.BotUsername) {
+		// the command was meant for another bot of the group
+		return "", false
+	}
+	return m[2], true
+}
+
+// TelegramLinkUrl returns the deep link that opens a chat with the bot and sends the token to it, groups are linked
+// via the startgroup parameter
+func TelegramLinkUrl(token string, group bool) string {
+	param := "start"
+	if group {
+		param = "startgroup"
+	}
+	return fmt.Sprintf("https://t.me/%s?%s=%s", url.PathEscape(utils.Config.Notifications.Telegram.BotUsername), param, url.QueryEscape(token))
+}
+
+// SendTelegramText sends a plain text message to a telegram chat
+func SendTelegramText(ctx context.Context, chatID, text string) error {
+	apiUrl := utils.Config.Notifications.Telegram.ApiUrl
+	if apiUrl == "" {
+		apiUrl = defaultTelegramApiUrl
+	}
+	payload := map[string]interface{}{
+		"chat_id": chatID,
+		"text":    text,
+	}
+	_, err := postChatRequest(ctx, &http.Client{Timeout: time.Second * 10}, types.TelegramNotificationChannel, http.MethodPost, fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimSuffix(apiUrl, "/"), utils.Config.Notifications.Telegram.BotToken), nil, payload, nil)
+	return err
+}
+
+// matrixVerificationHistory is the number of recent room events searched for the verification token
+const matrixVerificationHistory = 100
+
+// ErrMatrixRoomNotVerified is returned by VerifyMatrixRoom if the verification token was not posted in the room
+var ErrMatrixRoomNotVerified = errors.New("the verification code was not found in the room")
+
+// VerifyMatrixRoom joins the room the bot was invited to and checks that the verification token has been posted in
+// it, proving that the user who added the room is a member of it
+func VerifyMatrixRoom(ctx context.Context, roomID, token string) error {
+	homeserver := strings.TrimSuffix(utils.Config.Notifications.Matrix.HomeserverUrl, "/")
+	headers := map[string]string{"Authorization": "Bearer " + utils.Config.Notifications.Matrix.AccessToken}
+	client := &http.Client{Timeout: time.Second * 10}
+
+	// joining only succeeds if the bot has been invited to the room or the room is public
+	_, err := postChatRequest(ctx, client, types.MatrixNotificationChannel, http.MethodPost, fmt.Sprintf("%s/_matrix/client/v3/join/%s", homeserver, url.PathEscape(roomID)), headers, map[string]interface{}{}, nil)
+	if err != nil {
+		return fmt.Errorf("error joining matrix room %v: %w", roomID, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/messages?dir=b&limit=%d", homeserver, url.PathEscape(roomID), matrixVerificationHistory), nil)
+	if err != nil {
+		return fmt.Errorf("error creating matrix messages request: %w", err)
+	}
+	req.Header.Set("Authorization", headers["Authorization"])
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error retrieving messages of matrix room %v: %w", roomID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error retrieving messages of matrix room %v: status %v", roomID, resp.Status)
+	}
+
+	var messages struct {
+		Chunk []struct {
+			Type    string `json:"type"`
+			Content struct {
+				Body string `json:"body"`
+			} `json:"content"`
+		} `json:"chunk"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, 10*1024*1024)).Decode(&messages)
+	if err != nil {
+		return fmt.Errorf("error decoding messages of matrix room %v: %w", roomID, err)
+	}
+	for _, e := range messages.Chunk {
+		if e.Type == "m.room.message" && strings.Contains(e.Content.Body, token) {
+			return nil
+		}
+	}
+	return ErrMatrixRoomNotVerified
+}
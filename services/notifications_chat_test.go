@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+func TestRenderChatMessages(t *testing.T) {
+	messages := []types.ChatMessage{
+		{Title: "Block Proposal <Missed>", Markdown: "Validator [5](https://beaconcha.in/validator/5) missed a proposal & more (see slot)."},
+	}
+
+	tests := []struct {
+		name   string
+		render func([]types.ChatMessage) string
+		want   string
+	}{
+		{"telegram", renderTelegramMessage, "<b>Block Proposal &lt;Missed&gt;</b>\nValidator <a href=\"https://beaconcha.in/validator/5\">5</a> missed a proposal &amp; more (see slot)."},
+		{"slack", renderSlackMessage, "*Block Proposal &lt;Missed&gt;*\nValidator <https://beaconcha.in/validator/5|5> missed a proposal &amp; more (see slot)."},
+		{"matrix", renderMatrixMessage, "<b>Block Proposal &lt;Missed&gt;</b><br>Validator <a href=\"https://beaconcha.in/validator/5\">5</a> missed a proposal &amp; more (see slot)."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.render(messages); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitChatMessages(t *testing.T) {
+	messages := make([]types.ChatMessage, 0, 10)
+	for i := 0; i < 10; i++ {
+		messages = append(messages, types.ChatMessage{Title: "Title", Markdown: strings.Repeat("x", 100)})
+	}
+
+	chunks := splitChatMessages(messages, renderSlackMessage, 350)
+
+	total := 0
+	for _, chunk := range chunks {
+		if len(chunk) > 1 && len(renderSlackMessage(chunk)) > 350 {
+			t.Errorf("chunk of %v messages exceeds the maximum length", len(chunk))
+		}
+		total += len(chunk)
+	}
+	if total != len(messages) {
+		t.Errorf("got %v messages in chunks, want %v", total, len(messages))
+	}
+	if len(chunks) != 4 {
+		t.Errorf("got %v chunks, want 4", len(chunks))
+	}
+
+	if chunks := splitChatMessages(nil, renderSlackMessage, 350); len(chunks) != 0 {
+		t.Errorf("got %v chunks for an empty batch, want 0", len(chunks))
+	}
+}
+
+func TestParseTelegramStartToken(t *testing.T) {
+	config := utils.Config
+	t.Cleanup(func() { utils.Config = config })
+	utils.Config = &types.Config{}
+	utils.Config.Notifications.Telegram.BotUsername = "ExplorerBot"
+
+	tests := []struct {
+		text   string
+		want   string
+		wantOk bool
+	}{
+		{"/start 0123abcd", "0123abcd", true},
+		{"/start@explorerbot 0123abcd", "0123abcd", true},
+		{"/start@OtherBot 0123abcd", "", false},
+		{"/start", "", false},
+		{"/help 0123abcd", "", false},
+		{"/start 0123abcd extra", "", false},
+		{"please /start 0123abcd", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := ParseTelegramStartToken(tt.text)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("got %q %v, want %q %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestVerifyMatrixRoom(t *testing.T) {
+	const room = "!room:example.org"
+	joined := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/_matrix/client/v3/join/"+room:
+			joined = true
+			_, _ = w.Write([]byte(`{"room_id":"` + room + `"}`))
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/_matrix/client/v3/join/"):
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errcode":"M_FORBIDDEN"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/_matrix/client/v3/rooms/"+room+"/messages":
+			if !joined {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"chunk": []map[string]interface{}{
+					{"type": "m.room.member", "content": map[string]interface{}{"membership": "join"}},
+					{"type": "m.room.message", "content": map[string]interface{}{"body": "code 0123abcd"}},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := utils.Config
+	t.Cleanup(func() { utils.Config = config })
+	utils.Config = &types.Config{}
+	utils.Config.Notifications.Matrix.HomeserverUrl = server.URL
+	utils.Config.Notifications.Matrix.AccessToken = "token"
+
+	ctx := context.Background()
+	if err := VerifyMatrixRoom(ctx, room, "0123abcd"); err != nil {
+		t.Errorf("got error %v for the posted code", err)
+	}
+	if err := VerifyMatrixRoom(ctx, room, "4567cdef"); !errors.Is(err, ErrMatrixRoomNotVerified) {
+		t.Errorf("got error %v for a code that was not posted, want %v", err, ErrMatrixRoomNotVerified)
+	}
+	if err := VerifyMatrixRoom(ctx, "!other:example.org", "0123abcd"); err == nil || errors.Is(err, ErrMatrixRoomNotVerified) {
+		t.Errorf("got error %v for a room the bot was not invited to", err)
+	}
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// digestMaxEntriesPerEvent is the number of notifications listed per event in a digest email, the remaining ones
+// are only counted
+const digestMaxEntriesPerEvent = 25
+
+// notificationDigestFlushAfter returns the time after which a notification created at now is sent. Notifications
+// of immediate subscriptions are sent right away unless they fall into the quiet hours of the user, notifications of
+// hourly and daily subscriptions are sent at the end of the current hour or day in the time zone of the user.
+func notificationDigestFlushAfter(mode types.NotificationDigestMode, settings *types.UserNotificationSettings, now time.Time) time.Time {
+	loc, err := time.LoadLocation(settings.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+
+	flushAfter := now
+	switch mode {
+	case types.NotificationDigestHourly:
+		flushAfter = time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+1, 0, 0, 0, loc)
+	case types.NotificationDigestDaily:
+		flushAfter = time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+	}
+
+	if settings.QuietHoursStart != nil && settings.QuietHoursEnd != nil && isInQuietHours(flushAfter.In(loc), *settings.QuietHoursStart, *settings.QuietHoursEnd) {
+		flushAfter = quietHoursEnd(flushAfter.In(loc), *settings.QuietHoursEnd)
+	}
+	return flushAfter
+}
+
+// isInQuietHours returns whether the local time is within the quiet hours, the quiet hours may span midnight
+func isInQuietHours(local time.Time, start, end int64) bool {
+	minute := int64(local.Hour()*60 + local.Minute())
+	switch {
+	case start < end:
+		return minute >= start && minute < end
+	case start > end:
+		return minute >= start || minute < end
+	}
+	return false
+}
+
+// quietHoursEnd returns the next end of the quiet hours after the local time
+func quietHoursEnd(local time.Time, end int64) time.Time {
+	t := time.Date(local.Year(), local.Month(), local.Day(), 0, int(end), 0, 0, local.Location())
+	if !t.After(local) {
+		t = time.Date(local.Year(), local.Month(), local.Day()+1, 0, int(end), 0, 0, local.Location())
+	}
+	return t
+}
+
+// deferDigestNotifications stores the notifications that are not sent right away because of the digest mode of their
+// subscription or the quiet hours of the user and returns the notifications that have to be sent immediately
+func deferDigestNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, useDB *sqlx.DB) (map[uint64]map[types.EventName][]types.Notification, error) {
+	userIDs := make([]uint64, 0, len(notificationsByUserID))
+	subscriptionIDs := []uint64{}
+	for userID, userNotifications := range notificationsByUserID {
+		userIDs = append(userIDs, userID)
+		for _, ns := range userNotifications {
+			for _, n := range ns {
+				subscriptionIDs = append(subscriptionIDs, n.GetSubscriptionID())
+			}
+		}
+	}
+
+	modes, err := db.GetSubscriptionDigestModes(subscriptionIDs)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := db.GetUserNotificationSettings(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	immediate := make(map[uint64]map[types.EventName][]types.Notification, len(notificationsByUserID))
+	deferred := []*types.NotificationDigestEntry{}
+	for userID, userNotifications := range notificationsByUserID {
+		for event, ns := range userNotifications {
+			for _, n := range ns {
+				mode, exists := modes[n.GetSubscriptionID()]
+				if !exists {
+					mode = types.NotificationDigestImmediate
+				}
+
+				flushAfter := notificationDigestFlushAfter(mode, settings[userID], now)
+				if !flushAfter.After(now) {
+					if _, exists := immediate[userID]; !exists {
+						immediate[userID] = make(map[types.EventName][]types.Notification)
+					}
+					immediate[userID][event] = append(immediate[userID][event], n)
+					continue
+				}
+
+				deferred = append(deferred, &types.NotificationDigestEntry{
+					UserID:         userID,
+					SubscriptionID: n.GetSubscriptionID(),
+					EventName:      event,
+					Epoch:          n.GetEpoch(),
+					Title:          n.GetTitle(),
+					Info:           n.GetInfo(true),
+					Created:        now.UTC(),
+					FlushAfter:     flushAfter.UTC(), // the columns do not store a time zone
+				})
+			}
+		}
+	}
+
+	if len(deferred) == 0 {
+		return immediate, nil
+	}
+
+	tx, err := useDB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Preparex(`INSERT INTO notification_digest_entries (user_id, subscription_id, event_name, epoch, title, info, created, flush_after) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing digest insert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, e := range deferred {
+		_, err = stmt.Exec(e.UserID, e.SubscriptionID, e.EventName, e.Epoch, e.Title, e.Info, e.Created, e.FlushAfter)
+		if err != nil {
+			return nil, fmt.Errorf("error inserting digest entry for user %v: %w", e.UserID, err)
+		}
+		metrics.NotificationsQueued.WithLabelValues("email_digest", string(e.EventName)).Inc()
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("error committing digest entries: %w", err)
+	}
+	return immediate, nil
+}
+
+// flushNotificationDigests queues a summary email for every user with digest entries that are due. The entries are
+// kept in the database until the email has been queued so digests survive restarts of the sender.
+func flushNotificationDigests(useDB *sqlx.DB) error {
+	var userIDs []uint64
+	err := useDB.Select(&userIDs, `SELECT DISTINCT user_id FROM notification_digest_entries WHERE flush_after <= now()`)
+	if err != nil {
+		return fmt.Errorf("error querying due notification digests: %w", err)
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	logger.Infof("flushing notification digests of %v users", len(userIDs))
+
+	emailsByUserID, err := db.GetUserEmailsByIds(userIDs)
+	if err != nil {
+		return fmt.Errorf("error getting emails of users with due notification digests: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		err = flushUserNotificationDigest(useDB, userID, emailsByUserID[userID])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func flushUserNotificationDigest(useDB *sqlx.DB, userID uint64, email string) error {
+	tx, err := useDB.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var entries []*types.NotificationDigestEntry
+	err = tx.Select(&entries, `
+		DELETE FROM notification_digest_entries
+		WHERE user_id = $1 AND flush_after <= now()
+		RETURNING id, user_id, subscription_id, event_name, epoch, title, info, created, flush_after`, userID)
+	if err != nil {
+		return fmt.Errorf("error retrieving notification digest of user %v: %w", userID, err)
+	}
+
+	// users that disabled email notifications in the meantime do not receive the digest
+	if len(entries) > 0 && email != "" {
+		_, err = tx.Exec(`INSERT INTO notification_queue (created, channel, content) VALUES ($1, 'email', $2)`, time.Now(), buildNotificationDigestEmail(email, entries))
+		if err != nil {
+			return fmt.Errorf("error queuing notification digest of user %v: %w", userID, err)
+		}
+		metrics.NotificationsQueued.WithLabelValues("email", "digest").Inc()
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing notification digest of user %v: %w", userID, err)
+	}
+	return nil
+}
+
+// buildNotificationDigestEmail summarizes the digest entries of a user with the number of notifications per event
+func buildNotificationDigestEmail(email string, entries []*types.NotificationDigestEntry) types.TransitEmailContent {
+	byEvent := make(map[types.EventName][]*types.NotificationDigestEntry)
+	for _, e := range entries {
+		byEvent[e.EventName] = append(byEvent[e.EventName], e)
+	}
+	events := make([]types.EventName, 0, len(byEvent))
+	for event := range byEvent {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return len(byEvent[events[i]]) > len(byEvent[events[j]])
+	})
+
+	var msg types.Email
+	if utils.Config.Chain.Name != "mainnet" {
+		msg.Body += template.HTML(fmt.Sprintf("<b>Notice: This email contains notifications for the %s network!</b><br>", utils.Config.Chain.Name))
+	}
+
+	since := entries[0].Created
+	for _, e := range entries {
+		if e.Created.Before(since) {
+			since = e.Created
+		}
+	}
+	msg.Body += template.HTML(fmt.Sprintf("Summary of your notifications since %s UTC<br><br>", since.UTC().Format("2006-01-02 15:04")))
+
+	for _, event := range events {
+		label := types.EventLabel[event]
+		if label == "" {
+			label = string(event)
+		}
+		msg.Body += template.HTML(fmt.Sprintf("<b>%s</b>: %d<br>", label, len(byEvent[event])))
+	}
+
+	for _, event := range events {
+		msg.Body += template.HTML(fmt.Sprintf("<br>%s<br>====<br><br>", types.EventLabel[event]))
+		for i, e := range byEvent[event] {
+			if i == digestMaxEntriesPerEvent {
+				msg.Body += template.HTML(fmt.Sprintf("... and %d more<br>", len(byEvent[event])-digestMaxEntriesPerEvent))
+				break
+			}
+			msg.Body += template.HTML(fmt.Sprintf("%s<br>", e.Info))
+		}
+	}
+
+	msg.SubscriptionManageURL = template.HTML(fmt.Sprintf(`<a href="%v" style="color: white" onMouseOver="this.style.color='#F5B498'" onMouseOut="this.style.color='#FFFFFF'">Manage</a>`, "https://"+utils.Config.Frontend.SiteDomain+"/user/notifications"))
+
+	return types.TransitEmailContent{
+		Address: email,
+		Subject: fmt.Sprintf("%s: Notification digest with %d notifications", utils.Config.Frontend.SiteDomain, len(entries)),
+		Email:   msg,
+	}
+}
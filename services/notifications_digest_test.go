@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+func TestNotificationDigestFlushAfter(t *testing.T) {
+	minutes := func(m int64) *int64 { return &m }
+	now := time.Date(2025, 10, 23, 14, 20, 0, 0, time.UTC)
+
+	utc := &types.UserNotificationSettings{TimeZone: "UTC"}
+	quietAfternoon := &types.UserNotificationSettings{TimeZone: "UTC", QuietHoursStart: minutes(14 * 60), QuietHoursEnd: minutes(15*60 + 30)}
+	quietNight := &types.UserNotificationSettings{TimeZone: "UTC", QuietHoursStart: minutes(22 * 60), QuietHoursEnd: minutes(7 * 60)}
+
+	tests := []struct {
+		name     string
+		mode     types.NotificationDigestMode
+		settings *types.UserNotificationSettings
+		want     time.Time
+	}{
+		{"immediate", types.NotificationDigestImmediate, utc, now},
+		{"hourly", types.NotificationDigestHourly, utc, time.Date(2025, 10, 23, 15, 0, 0, 0, time.UTC)},
+		{"daily", types.NotificationDigestDaily, utc, time.Date(2025, 10, 24, 0, 0, 0, 0, time.UTC)},
+		{"immediate during quiet hours", types.NotificationDigestImmediate, quietAfternoon, time.Date(2025, 10, 23, 15, 30, 0, 0, time.UTC)},
+		{"hourly digest within quiet hours", types.NotificationDigestHourly, quietAfternoon, time.Date(2025, 10, 23, 15, 30, 0, 0, time.UTC)},
+		{"immediate outside of quiet hours", types.NotificationDigestImmediate, quietNight, now},
+		{"daily digest within quiet hours spanning midnight", types.NotificationDigestDaily, quietNight, time.Date(2025, 10, 24, 7, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notificationDigestFlushAfter(tt.mode, tt.settings, now); !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationDigestFlushAfterTimeZone(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Kolkata"); err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// 14:20 UTC is 19:50 in India, the hourly digest is sent at 20:00 local time
+	now := time.Date(2025, 10, 23, 14, 20, 0, 0, time.UTC)
+	got := notificationDigestFlushAfter(types.NotificationDigestHourly, &types.UserNotificationSettings{TimeZone: "Asia/Kolkata"}, now)
+	if want := time.Date(2025, 10, 23, 14, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
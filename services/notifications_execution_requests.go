@@ -0,0 +1,432 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// formatRequestValidator returns the index of a validator referenced by an execution layer request or its public key
+// if the validator is not known yet
+func formatRequestValidator(index int64, pubkey []byte) string {
+	if index < 0 {
+		return fmt.Sprintf("0x%x", pubkey)
+	}
+	return fmt.Sprintf("%v", index)
+}
+
+func formatRequestValidatorMarkdown(index int64, pubkey []byte) string {
+	v := formatRequestValidator(index, pubkey)
+	if index < 0 {
+		return fmt.Sprintf("[%v](https://%v/validator/%v)", utils.FormatHashRaw(pubkey), utils.Config.Frontend.SiteDomain, v)
+	}
+	return fmt.Sprintf("[%v](https://%v/validator/%v)", v, utils.Config.Frontend.SiteDomain, v)
+}
+
+func getRequestUrlPart(index int64, pubkey []byte) string {
+	if index < 0 {
+		v := formatRequestValidator(index, pubkey)
+		return fmt.Sprintf(` For more information visit: <a href='https://%s/validator/%v'>https://%s/validator/%v</a>.`, utils.Config.Frontend.SiteDomain, v, utils.Config.Frontend.SiteDomain, v)
+	}
+	return getUrlPart(uint64(index))
+}
+
+// appendRequestNotification adds the notification to the notifications of the user
+func appendRequestNotification(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, userID uint64, n types.Notification) {
+	if _, exists := notificationsByUserID[userID]; !exists {
+		notificationsByUserID[userID] = map[types.EventName][]types.Notification{}
+	}
+	if _, exists := notificationsByUserID[userID][n.GetEventName()]; !exists {
+		notificationsByUserID[userID][n.GetEventName()] = []types.Notification{}
+	}
+	notificationsByUserID[userID][n.GetEventName()] = append(notificationsByUserID[userID][n.GetEventName()], n)
+	metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+}
+
+type validatorConsolidationNotification struct {
+	SubscriptionID  uint64
+	EventName       types.EventName
+	ValidatorIndex  int64
+	ValidatorPubkey []byte
+	Epoch           uint64
+	Request         *types.ConsolidationRequestNotification
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorConsolidationNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *validatorConsolidationNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorConsolidationNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorConsolidationNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorConsolidationNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorConsolidationNotification) GetEventName() types.EventName {
+	return n.EventName
+}
+
+func (n *validatorConsolidationNotification) GetInfo(includeUrl bool) string {
+	source := formatRequestValidator(n.Request.SourceIndex, n.Request.SourcePubkey)
+	target := formatRequestValidator(n.Request.TargetIndex, n.Request.TargetPubkey)
+
+	generalPart := ""
+	switch n.EventName {
+	case types.ValidatorConsolidationRequestEventName:
+		generalPart = fmt.Sprintf(`A consolidation of validator %v into validator %v has been requested in block %v.`, source, target, n.Request.BlockNumber)
+	case types.ValidatorConsolidationProcessedEventName:
+		generalPart = fmt.Sprintf(`The consolidation of validator %v into validator %v has been processed in slot %v, %v have been consolidated.`, source, target, n.Request.Slot, utils.FormatClCurrencyString(n.Request.Amount, utils.Config.Frontend.MainCurrency, 6, true, false, false))
+	case types.ValidatorConsolidationRejectedEventName:
+		generalPart = fmt.Sprintf(`The consolidation request of validator %v into validator %v has been rejected in slot %v.`, source, target, n.Request.Slot)
+	}
+
+	if includeUrl {
+		return generalPart + getRequestUrlPart(n.ValidatorIndex, n.ValidatorPubkey)
+	}
+	return generalPart
+}
+
+func (n *validatorConsolidationNotification) GetTitle() string {
+	switch n.EventName {
+	case types.ValidatorConsolidationProcessedEventName:
+		return "Consolidation Processed"
+	case types.ValidatorConsolidationRejectedEventName:
+		return "Consolidation Rejected"
+	}
+	return "Consolidation Requested"
+}
+
+func (n *validatorConsolidationNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorConsolidationNotification) GetInfoMarkdown() string {
+	source := formatRequestValidatorMarkdown(n.Request.SourceIndex, n.Request.SourcePubkey)
+	target := formatRequestValidatorMarkdown(n.Request.TargetIndex, n.Request.TargetPubkey)
+
+	switch n.EventName {
+	case types.ValidatorConsolidationProcessedEventName:
+		return fmt.Sprintf(`The consolidation of validator %[1]v into validator %[2]v has been processed during slot [%[3]v](https://%[5]v/slot/%[3]v), %[4]v have been consolidated.`, source, target, n.Request.Slot, utils.FormatClCurrencyString(n.Request.Amount, utils.Config.Frontend.MainCurrency, 6, true, false, false), utils.Config.Frontend.SiteDomain)
+	case types.ValidatorConsolidationRejectedEventName:
+		return fmt.Sprintf(`The consolidation request of validator %[1]v into validator %[2]v has been rejected during slot [%[3]v](https://%[4]v/slot/%[3]v).`, source, target, n.Request.Slot, utils.Config.Frontend.SiteDomain)
+	}
+	return fmt.Sprintf(`A consolidation of validator %[1]v into validator %[2]v has been requested by [%[3]v](https://%[6]v/address/0x%[4]x) in transaction [%[5]v](https://%[6]v/tx/0x%[5]x).`, source, target, utils.FormatHashRaw(n.Request.SourceAddress), n.Request.SourceAddress, n.Request.TxHash, utils.Config.Frontend.SiteDomain)
+}
+
+// collectConsolidationRequestNotifications collects all notifications for consolidation requests included in the
+// execution blocks of the epoch with a watched validator as source or target
+func collectConsolidationRequestNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	requests, err := db.GetEpochConsolidationRequests(epoch)
+	if err != nil {
+		return fmt.Errorf("error getting consolidation requests from database, err: %w", err)
+	}
+
+	return collectConsolidationNotifications(notificationsByUserID, types.ValidatorConsolidationRequestEventName, requests, epoch)
+}
+
+// collectProcessedConsolidationNotifications collects all notifications for consolidation requests that were processed
+// or rejected by the consensus layer during the epoch
+func collectProcessedConsolidationNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	requests, err := db.GetEpochProcessedConsolidationRequests(epoch)
+	if err != nil {
+		return fmt.Errorf("error getting processed consolidation requests from database, err: %w", err)
+	}
+
+	processed := []*types.ConsolidationRequestNotification{}
+	rejected := []*types.ConsolidationRequestNotification{}
+	for _, r := range requests {
+		if r.Status == "completed" {
+			processed = append(processed, r)
+		} else {
+			rejected = append(rejected, r)
+		}
+	}
+
+	err = collectConsolidationNotifications(notificationsByUserID, types.ValidatorConsolidationProcessedEventName, processed, epoch)
+	if err != nil {
+		return err
+	}
+	return collectConsolidationNotifications(notificationsByUserID, types.ValidatorConsolidationRejectedEventName, rejected, epoch)
+}
+
+func collectConsolidationNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, eventName types.EventName, requests []*types.ConsolidationRequestNotification, epoch uint64) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	// get all users that are subscribed to this event (scale: a few thousand rows depending on how many users we have)
+	_, subMap, err := db.GetSubsForEventFilter(eventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for %v: %w", eventName, err)
+	}
+
+	for _, request := range requests {
+		notifiedUsers := map[uint64]bool{}
+		for _, validator := range []struct {
+			index  int64
+			pubkey []byte
+		}{{request.SourceIndex, request.SourcePubkey}, {request.TargetIndex, request.TargetPubkey}} {
+			subscribers, ok := subMap[hex.EncodeToString(validator.pubkey)]
+			if !ok {
+				continue
+			}
+			for _, sub := range subscribers {
+				if sub.UserID == nil || sub.ID == nil {
+					return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+				}
+				if sub.LastEpoch != nil {
+					lastSentEpoch := *sub.LastEpoch
+					if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+						continue
+					}
+				}
+				// users watching both the source and the target are only notified once per request
+				if notifiedUsers[*sub.UserID] {
+					continue
+				}
+				notifiedUsers[*sub.UserID] = true
+
+				n := &validatorConsolidationNotification{
+					SubscriptionID:  *sub.ID,
+					EventName:       eventName,
+					ValidatorIndex:  validator.index,
+					ValidatorPubkey: validator.pubkey,
+					Epoch:           epoch,
+					Request:         request,
+					EventFilter:     hex.EncodeToString(validator.pubkey),
+					UnsubscribeHash: sub.UnsubscribeHash,
+				}
+				appendRequestNotification(notificationsByUserID, *sub.UserID, n)
+			}
+		}
+	}
+
+	return nil
+}
+
+type validatorSwitchedToCompoundingNotification struct {
+	SubscriptionID  uint64
+	Epoch           uint64
+	Request         *types.SwitchToCompoundingNotification
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetEventName() types.EventName {
+	return types.ValidatorSwitchedToCompoundingEventName
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`Validator %v switched to compounding (0x02) withdrawal credentials in slot %v.`, formatRequestValidator(n.Request.ValidatorIndex, n.Request.Pubkey), n.Request.Slot)
+	if includeUrl {
+		return generalPart + getRequestUrlPart(n.Request.ValidatorIndex, n.Request.Pubkey)
+	}
+	return generalPart
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetTitle() string {
+	return "Switched to Compounding Credentials"
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorSwitchedToCompoundingNotification) GetInfoMarkdown() string {
+	return fmt.Sprintf(`Validator %[1]v switched to compounding (0x02) withdrawal credentials during slot [%[2]v](https://%[3]v/slot/%[2]v).`, formatRequestValidatorMarkdown(n.Request.ValidatorIndex, n.Request.Pubkey), n.Request.Slot, utils.Config.Frontend.SiteDomain)
+}
+
+// collectSwitchToCompoundingNotifications collects all notifications for validators that switched to 0x02 credentials
+func collectSwitchToCompoundingNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+
+	// get all users that are subscribed to this event (scale: a few thousand rows depending on how many users we have)
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorSwitchedToCompoundingEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for switch to compounding requests %w", err)
+	}
+
+	events, err := db.GetEpochSwitchToCompoundingRequests(epoch)
+	if err != nil {
+		return fmt.Errorf("error getting switch to compounding requests from database, err: %w", err)
+	}
+
+	for _, event := range events {
+		subscribers, ok := subMap[hex.EncodeToString(event.Pubkey)]
+		if ok {
+			for _, sub := range subscribers {
+				if sub.UserID == nil || sub.ID == nil {
+					return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+				}
+				if sub.LastEpoch != nil {
+					lastSentEpoch := *sub.LastEpoch
+					if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+						continue
+					}
+				}
+				n := &validatorSwitchedToCompoundingNotification{
+					SubscriptionID:  *sub.ID,
+					Epoch:           epoch,
+					Request:         event,
+					EventFilter:     hex.EncodeToString(event.Pubkey),
+					UnsubscribeHash: sub.UnsubscribeHash,
+				}
+				appendRequestNotification(notificationsByUserID, *sub.UserID, n)
+			}
+		}
+	}
+
+	return nil
+}
+
+type validatorWithdrawalRequestNotification struct {
+	SubscriptionID  uint64
+	Epoch           uint64
+	Request         *types.WithdrawalRequestNotification
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorWithdrawalRequestNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *validatorWithdrawalRequestNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorWithdrawalRequestNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorWithdrawalRequestNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorWithdrawalRequestNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorWithdrawalRequestNotification) GetEventName() types.EventName {
+	return types.ValidatorWithdrawalRequestEventName
+}
+
+func (n *validatorWithdrawalRequestNotification) GetInfo(includeUrl bool) string {
+	validator := formatRequestValidator(n.Request.ValidatorIndex, n.Request.Pubkey)
+	generalPart := fmt.Sprintf(`A full exit of validator %v has been requested by its withdrawal address 0x%x and was processed in slot %v.`, validator, n.Request.SourceAddress, n.Request.Slot)
+	if n.Request.Amount > 0 {
+		generalPart = fmt.Sprintf(`A partial withdrawal of %v for validator %v has been requested by its withdrawal address 0x%x and was processed in slot %v.`, utils.FormatClCurrencyString(n.Request.Amount, utils.Config.Frontend.MainCurrency, 6, true, false, false), validator, n.Request.SourceAddress, n.Request.Slot)
+	}
+	if includeUrl {
+		return generalPart + getRequestUrlPart(n.Request.ValidatorIndex, n.Request.Pubkey)
+	}
+	return generalPart
+}
+
+func (n *validatorWithdrawalRequestNotification) GetTitle() string {
+	if n.Request.Amount == 0 {
+		return "Exit Requested"
+	}
+	return "Withdrawal Requested"
+}
+
+func (n *validatorWithdrawalRequestNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorWithdrawalRequestNotification) GetInfoMarkdown() string {
+	validator := formatRequestValidatorMarkdown(n.Request.ValidatorIndex, n.Request.Pubkey)
+	requested := "A full exit"
+	if n.Request.Amount > 0 {
+		requested = fmt.Sprintf("A partial withdrawal of %v", utils.FormatClCurrencyString(n.Request.Amount, utils.Config.Frontend.MainCurrency, 6, true, false, false))
+	}
+	return fmt.Sprintf(`%[1]v of validator %[2]v has been requested by its withdrawal address [%[3]v](https://%[6]v/address/0x%[4]x) and was processed during slot [%[5]v](https://%[6]v/slot/%[5]v).`, requested, validator, utils.FormatHashRaw(n.Request.SourceAddress), n.Request.SourceAddress, n.Request.Slot, utils.Config.Frontend.SiteDomain)
+}
+
+// collectWithdrawalRequestNotifications collects all notifications for execution layer triggered withdrawal requests
+// (EIP-7002) of watched validators
+func collectWithdrawalRequestNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+
+	// get all users that are subscribed to this event (scale: a few thousand rows depending on how many users we have)
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorWithdrawalRequestEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for withdrawal requests %w", err)
+	}
+
+	events, err := db.GetEpochWithdrawalRequests(epoch)
+	if err != nil {
+		return fmt.Errorf("error getting withdrawal requests from database, err: %w", err)
+	}
+
+	for _, event := range events {
+		subscribers, ok := subMap[hex.EncodeToString(event.Pubkey)]
+		if ok {
+			for _, sub := range subscribers {
+				if sub.UserID == nil || sub.ID == nil {
+					return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+				}
+				if sub.LastEpoch != nil {
+					lastSentEpoch := *sub.LastEpoch
+					if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+						continue
+					}
+				}
+				n := &validatorWithdrawalRequestNotification{
+					SubscriptionID:  *sub.ID,
+					Epoch:           epoch,
+					Request:         event,
+					EventFilter:     hex.EncodeToString(event.Pubkey),
+					UnsubscribeHash: sub.UnsubscribeHash,
+				}
+				appendRequestNotification(notificationsByUserID, *sub.UserID, n)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+type validatorQueueEstimateNotification struct {
+	SubscriptionID  uint64
+	Epoch           uint64
+	Position        *types.ValidatorQueuePosition
+	PreviousEpoch   uint64
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorQueueEstimateNotification) GetLatestState() string {
+	return formatQueueEstimateState(n.Position.Queue, n.Position.EstEpoch)
+}
+
+func (n *validatorQueueEstimateNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorQueueEstimateNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorQueueEstimateNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorQueueEstimateNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorQueueEstimateNotification) GetEventName() types.EventName {
+	return types.ValidatorQueueEstimateChangedEventName
+}
+
+func (n *validatorQueueEstimateNotification) direction() string {
+	if n.Position.EstEpoch < n.PreviousEpoch {
+		return "moved forward"
+	}
+	return "been delayed"
+}
+
+func (n *validatorQueueEstimateNotification) validatorIndex() int64 {
+	if n.Position.ValidatorIndex.Valid {
+		return n.Position.ValidatorIndex.Int64
+	}
+	return -1
+}
+
+func (n *validatorQueueEstimateNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`The estimated %v of validator %v has %v from epoch %v to epoch %v (%v), the validator is currently #%v in the %v queue.`, n.Position.Queue, formatRequestValidator(n.validatorIndex(), n.Position.Pubkey), n.direction(), n.PreviousEpoch, n.Position.EstEpoch, utils.EpochToTime(n.Position.EstEpoch).UTC().Format("2006-01-02 15:04 UTC"), n.Position.Position, n.Position.Queue)
+	if includeUrl {
+		return generalPart + getRequestUrlPart(n.validatorIndex(), n.Position.Pubkey)
+	}
+	return generalPart
+}
+
+func (n *validatorQueueEstimateNotification) GetTitle() string {
+	if n.Position.Queue == types.ValidatorExitQueue {
+		return "Exit Estimate Changed"
+	}
+	return "Activation Estimate Changed"
+}
+
+func (n *validatorQueueEstimateNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorQueueEstimateNotification) GetInfoMarkdown() string {
+	return fmt.Sprintf(`The estimated %[1]v of validator %[2]v has %[3]v from epoch [%[4]v](https://%[8]v/epoch/%[4]v) to epoch [%[5]v](https://%[8]v/epoch/%[5]v), the validator is currently #%[6]v in the %[7]v queue.`, n.Position.Queue, formatRequestValidatorMarkdown(n.validatorIndex(), n.Position.Pubkey), n.direction(), n.PreviousEpoch, n.Position.EstEpoch, n.Position.Position, n.Position.Queue, utils.Config.Frontend.SiteDomain)
+}
+
+// formatQueueEstimateState returns the internal state of a subscription that stores the last notified estimate
+func formatQueueEstimateState(queue types.ValidatorQueueName, estEpoch uint64) string {
+	return fmt.Sprintf("%v:%v", queue, estEpoch)
+}
+
+// parseQueueEstimateState returns the last notified estimate of the queue or false if the state belongs to another queue
+func parseQueueEstimateState(state sql.NullString, queue types.ValidatorQueueName) (uint64, bool) {
+	if !state.Valid {
+		return 0, false
+	}
+	stateQueue, stateEpoch, found := strings.Cut(state.String, ":")
+	if !found || stateQueue != string(queue) {
+		return 0, false
+	}
+	estEpoch, err := strconv.ParseUint(stateEpoch, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return estEpoch, true
+}
+
+// queueEstimateChangeThreshold returns the number of epochs the estimate has to move before a subscription is notified,
+// the threshold of the subscription takes precedence over the configured default of one day
+func queueEstimateChangeThreshold(sub types.Subscription) uint64 {
+	if sub.EventThreshold > 0 {
+		return uint64(sub.EventThreshold)
+	}
+	if utils.Config.Notifications.QueueEstimateChangeThreshold > 0 {
+		return utils.Config.Notifications.QueueEstimateChangeThreshold
+	}
+	return utils.EpochsPerDay()
+}
+
+// collectQueueEstimateNotifications collects all notifications for validators whose estimated activation or exit epoch
+// moved by more than the threshold since they entered the queue or since the last notification
+func collectQueueEstimateNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	pubkeys, subMap, err := db.GetSubsForEventFilter(types.ValidatorQueueEstimateChangedEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for queue estimate changes %w", err)
+	}
+	if len(pubkeys) == 0 {
+		return nil
+	}
+
+	positions, err := db.GetValidatorQueuePositions(pubkeys)
+	if err != nil {
+		return err
+	}
+	initialEstimates, err := db.GetInitialValidatorQueueEstimates(pubkeys)
+	if err != nil {
+		return err
+	}
+
+	for _, position := range positions {
+		subscribers, ok := subMap[hex.EncodeToString(position.Pubkey)]
+		if !ok {
+			continue
+		}
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil {
+				lastSentEpoch := *sub.LastEpoch
+				if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+					continue
+				}
+			}
+
+			previousEpoch, ok := parseQueueEstimateState(sub.State, position.Queue)
+			if !ok {
+				previousEpoch, ok = initialEstimates[string(position.Queue)+string(position.Pubkey)]
+				if !ok {
+					continue
+				}
+			}
+
+			change := position.EstEpoch - previousEpoch
+			if previousEpoch > position.EstEpoch {
+				change = previousEpoch - position.EstEpoch
+			}
+			if change <= queueEstimateChangeThreshold(sub) {
+				continue
+			}
+
+			n := &validatorQueueEstimateNotification{
+				SubscriptionID:  *sub.ID,
+				Epoch:           epoch,
+				Position:        position,
+				PreviousEpoch:   previousEpoch,
+				EventFilter:     hex.EncodeToString(position.Pubkey),
+				UnsubscribeHash: sub.UnsubscribeHash,
+			}
+			appendRequestNotification(notificationsByUserID, *sub.UserID, n)
+		}
+	}
+
+	return nil
+}
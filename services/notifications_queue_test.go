@@ -0,0 +1,33 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+func TestParseQueueEstimateState(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     sql.NullString
+		queue     types.ValidatorQueueName
+		want      uint64
+		wantFound bool
+	}{
+		{"no state", sql.NullString{}, types.ValidatorActivationQueue, 0, false},
+		{"same queue", sql.NullString{String: formatQueueEstimateState(types.ValidatorActivationQueue, 1234), Valid: true}, types.ValidatorActivationQueue, 1234, true},
+		{"other queue", sql.NullString{String: formatQueueEstimateState(types.ValidatorActivationQueue, 1234), Valid: true}, types.ValidatorExitQueue, 0, false},
+		{"state of another event", sql.NullString{String: "rocketpool", Valid: true}, types.ValidatorExitQueue, 0, false},
+		{"invalid epoch", sql.NullString{String: "exit:abc", Valid: true}, types.ValidatorExitQueue, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := parseQueueEstimateState(tt.state, tt.queue)
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("got (%v, %v), want (%v, %v)", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
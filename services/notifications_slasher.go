@@ -0,0 +1,129 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+type validatorSlashingDetectedNotification struct {
+	SubscriptionID  uint64
+	Epoch           uint64
+	Slashing        *types.SlasherSlashingNotification
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorSlashingDetectedNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *validatorSlashingDetectedNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorSlashingDetectedNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorSlashingDetectedNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorSlashingDetectedNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorSlashingDetectedNotification) GetEventName() types.EventName {
+	return types.ValidatorSlashingDetectedEventName
+}
+
+// offence returns a readable description of the slashing type
+func (n *validatorSlashingDetectedNotification) offence() string {
+	switch n.Slashing.Type {
+	case "double_proposal":
+		return "double proposal"
+	case "double_vote":
+		return "double vote"
+	case "surround_vote":
+		return "surround vote"
+	}
+	return n.Slashing.Type
+}
+
+func (n *validatorSlashingDetectedNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`A %v of validator %v has been detected in slot %v. The validator will be slashed once the offence is included on chain, make sure it is not running on more than one machine.`, n.offence(), n.Slashing.ValidatorIndex, n.Slashing.DetectedSlot)
+	if includeUrl {
+		return generalPart + getUrlPart(n.Slashing.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *validatorSlashingDetectedNotification) GetTitle() string {
+	return "Slashable Offence Detected"
+}
+
+func (n *validatorSlashingDetectedNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorSlashingDetectedNotification) GetInfoMarkdown() string {
+	return fmt.Sprintf(`A %[1]v of validator [%[2]v](https://%[4]v/validator/%[2]v) has been detected in slot [%[3]v](https://%[4]v/slot/%[3]v). The validator will be slashed once the offence is included on chain, make sure it is not running on more than one machine.`, n.offence(), n.Slashing.ValidatorIndex, n.Slashing.DetectedSlot, utils.Config.Frontend.SiteDomain)
+}
+
+// collectSlashingDetectedNotifications collects all notifications for slashable offences of watched validators that
+// the slasher detected in the epoch and that are not yet included on chain
+func collectSlashingDetectedNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorSlashingDetectedEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for detected slashings %w", err)
+	}
+
+	events, err := db.GetSlasherSlashingsForNotifications(epoch)
+	if err != nil {
+		return fmt.Errorf("error getting detected slashings from database, err: %w", err)
+	}
+
+	// a validator can be part of several slashings of the same attestations, users are notified once per validator
+	notified := make(map[string]bool)
+	for _, event := range events {
+		subscribers, ok := subMap[hex.EncodeToString(event.Pubkey)]
+		if !ok {
+			continue
+		}
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil {
+				lastSentEpoch := *sub.LastEpoch
+				if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+					continue
+				}
+			}
+			key := fmt.Sprintf("%v:%v:%v", *sub.ID, event.ValidatorIndex, event.Type)
+			if notified[key] {
+				continue
+			}
+			notified[key] = true
+
+			n := &validatorSlashingDetectedNotification{
+				SubscriptionID:  *sub.ID,
+				Epoch:           epoch,
+				Slashing:        event,
+				EventFilter:     hex.EncodeToString(event.Pubkey),
+				UnsubscribeHash: sub.UnsubscribeHash,
+			}
+			appendRequestNotification(notificationsByUserID, *sub.UserID, n)
+		}
+	}
+
+	return nil
+}
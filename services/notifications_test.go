@@ -0,0 +1,50 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	secret := "secret"
+	body := []byte(`{"event":{"event":"validator_proposal_missed"}}`)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	if got := signWebhookPayload(secret, 1700000000, body); got != want {
+		t.Errorf("got signature %v, want %v", got, want)
+	}
+	if signWebhookPayload(secret, 1700000001, body) == want {
+		t.Errorf("signature does not depend on the timestamp")
+	}
+	if signWebhookPayload("other", 1700000000, body) == want {
+		t.Errorf("signature does not depend on the secret")
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt uint64
+		max     time.Duration
+	}{
+		{1, webhookRetryBaseDelay},
+		{2, webhookRetryBaseDelay * 2},
+		{3, webhookRetryBaseDelay * 4},
+		{10, webhookRetryMaxDelay},
+		{100, webhookRetryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			delay := webhookRetryDelay(tt.attempt)
+			if delay < tt.max/2 || delay > tt.max {
+				t.Fatalf("got delay %v for attempt %v, want between %v and %v", delay, tt.attempt, tt.max/2, tt.max)
+			}
+		}
+	}
+}
@@ -19,6 +19,7 @@ package services
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -58,6 +59,140 @@ func queueEstimateUpdater(wg *sync.WaitGroup) {
 	}
 }
 
+// validatorQueuePositionUpdater stores the queue position and the estimated activation or exit epoch of every queued
+// validator, changes of the estimate are kept as history
+func validatorQueuePositionUpdater(wg *sync.WaitGroup) {
+	firstRun := true
+
+	for {
+		start := time.Now()
+		positions, err := getValidatorQueuePositions(LatestEpoch())
+		if err != nil {
+			logger.Errorf("error retrieving validator queue positions: %v", err)
+			time.Sleep(time.Minute)
+			continue
+		}
+
+		err = db.SaveValidatorQueuePositions(positions)
+		if err != nil {
+			logger.Errorf("error saving validator queue positions: %v", err)
+			time.Sleep(time.Minute)
+			continue
+		}
+		logger.Infof("updated queue positions of %v validators, took %v", len(positions), time.Since(start))
+
+		if firstRun {
+			logrus.Info("initialized validator queue position updater")
+			wg.Done()
+			firstRun = false
+		}
+
+		ReportStatus("validatorQueuePositionUpdater", "Running", nil)
+		time.Sleep(10 * time.Minute)
+	}
+}
+
+// getValidatorQueuePositions returns the positions of all validators in the activation and the exit queue. After
+// electra the activation queue is the pending deposits queue, before electra validators are activated in the order of
+// their eligibility with the churn of getValidatorActivationChurnLimit.
+func getValidatorQueuePositions(epoch uint64) ([]*types.ValidatorQueuePosition, error) {
+	now := time.Now()
+	positions := []*types.ValidatorQueuePosition{}
+
+	if utils.ElectraHasHappened(epoch) {
+		var finalityDelay uint64 = 1
+		if latestState := LatestState(); latestState != nil {
+			finalityDelay = uint64(math.Max(1, float64(latestState.FinalityDelay)-2))
+		}
+
+		// the first deposit of a validator determines its place in the queue, the validators are ordered by it
+		var deposits []*types.PendingDeposit
+		err := db.ReaderDb.Select(&deposits, `
+			SELECT id, pubkey, validator_index, queued_balance_ahead, est_clear_epoch
+			FROM (
+				SELECT DISTINCT ON (q.pubkey) q.id, q.pubkey, q.validator_index, q.queued_balance_ahead, q.est_clear_epoch
+				FROM pending_deposits_queue q
+				LEFT JOIN validators v ON v.pubkey = q.pubkey
+				WHERE v.pubkey IS NULL OR v.activationepoch = 9223372036854775807
+				ORDER BY q.pubkey, q.id
+			) d
+			ORDER BY id`)
+		if err != nil {
+			return nil, fmt.Errorf("error getting pending deposits: %w", err)
+		}
+
+		for i, d := range deposits {
+			positions = append(positions, &types.ValidatorQueuePosition{
+				Ts:             now,
+				Pubkey:         d.Pubkey,
+				Queue:          types.ValidatorActivationQueue,
+				ValidatorIndex: d.ValidatorIndex,
+				Position:       uint64(i) + 1,
+				BalanceAhead:   d.QueuedBalanceAhead,
+				EstEpoch:       d.EstClearEpoch + 1 + finalityDelay + utils.Config.Chain.ClConfig.MaxSeedLookahead + 1,
+			})
+		}
+	} else {
+		activeValidatorCount, err := db.GetActiveValidatorCount()
+		if err != nil {
+			return nil, fmt.Errorf("error getting active validator count: %w", err)
+		}
+		churnLimit, err := getValidatorActivationChurnLimit(activeValidatorCount, epoch)
+		if err != nil {
+			return nil, err
+		}
+		if churnLimit == 0 {
+			return nil, errors.New("validator activation churn limit is 0")
+		}
+
+		var pending []*types.ValidatorQueuePosition
+		err = db.ReaderDb.Select(&pending, `
+			SELECT pubkey, validatorindex AS validator_index, effectivebalance AS balance_ahead
+			FROM validators 
+			WHERE activationeligibilityepoch < 9223372036854775807 AND activationepoch = 9223372036854775807
+			ORDER BY activationeligibilityepoch, validatorindex`)
+		if err != nil {
+			return nil, fmt.Errorf("error getting validators pending activation: %w", err)
+		}
+
+		balanceAhead := uint64(0)
+		for i, p := range pending {
+			effectiveBalance := p.BalanceAhead
+			p.Ts = now
+			p.Queue = types.ValidatorActivationQueue
+			p.Position = uint64(i) + 1
+			p.BalanceAhead = balanceAhead
+			p.EstEpoch = epoch + 1 + utils.Config.Chain.ClConfig.MaxSeedLookahead + uint64(i)/churnLimit
+			positions = append(positions, p)
+			balanceAhead += effectiveBalance
+		}
+	}
+
+	// the exit epoch of a validator is set once the exit is initiated, the position only depends on the exit epochs
+	var exiting []*types.ValidatorQueuePosition
+	err := db.ReaderDb.Select(&exiting, `
+		SELECT pubkey, validatorindex AS validator_index, effectivebalance AS balance_ahead, exitepoch AS est_epoch
+		FROM validators 
+		WHERE exitepoch > $1 AND exitepoch < 9223372036854775807
+		ORDER BY exitepoch, validatorindex`, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting exiting validators: %w", err)
+	}
+
+	balanceAhead := uint64(0)
+	for i, p := range exiting {
+		effectiveBalance := p.BalanceAhead
+		p.Ts = now
+		p.Queue = types.ValidatorExitQueue
+		p.Position = uint64(i) + 1
+		p.BalanceAhead = balanceAhead
+		positions = append(positions, p)
+		balanceAhead += effectiveBalance
+	}
+
+	return positions, nil
+}
+
 func LatestQueueData() *types.QueuesEstimate {
 	wanted := &types.QueuesEstimate{}
 	if wanted, err := cache.TieredCache.GetWithLocalTimeout(getQueueCacheKey(), time.Minute, wanted); err == nil {
@@ -91,6 +91,9 @@ func Init() {
 	ready.Add(1)
 	go queueEstimateUpdater(ready)
 
+	ready.Add(1)
+	go validatorQueuePositionUpdater(ready)
+
 	if utils.Config.RatelimitUpdater.Enabled {
 		go ratelimit.DBUpdater()
 	}
@@ -347,6 +350,7 @@ func relaysUpdater(wg *sync.WaitGroup) {
 
 func epochUpdater(wg *sync.WaitGroup) {
 	firstRun := true
+	var previousEpoch, previousNodeFinalized uint64
 	for {
 		// latest epoch acording to the node
 		var epochNode uint64
@@ -372,6 +376,13 @@ func epochUpdater(wg *sync.WaitGroup) {
 			if err != nil {
 				logger.Errorf("error caching latestNodeFinalized: %v", err)
 			}
+			if !firstRun && latestNodeFinalized > previousNodeFinalized {
+				err := PublishEvent(&types.StreamEvent{Type: types.StreamEventFinalizedCheckpoint, Epoch: latestNodeFinalized, Slot: latestNodeFinalized * utils.Config.Chain.ClConfig.SlotsPerEpoch})
+				if err != nil {
+					logger.Errorf("error publishing finalized checkpoint event: %v", err)
+				}
+			}
+			previousNodeFinalized = latestNodeFinalized
 		}
 
 		// latest exported epoch
@@ -385,6 +396,13 @@ func epochUpdater(wg *sync.WaitGroup) {
 			if err != nil {
 				logger.Errorf("error caching latestEpoch: %v", err)
 			}
+			if !firstRun && epoch > previousEpoch {
+				err := PublishEvent(&types.StreamEvent{Type: types.StreamEventEpoch, Epoch: epoch, Slot: epoch * utils.Config.Chain.ClConfig.SlotsPerEpoch})
+				if err != nil {
+					logger.Errorf("error publishing epoch event: %v", err)
+				}
+			}
+			previousEpoch = epoch
 		}
 
 		// latest exported finalized epoch
@@ -411,6 +429,7 @@ func epochUpdater(wg *sync.WaitGroup) {
 
 func slotUpdater(wg *sync.WaitGroup) {
 	firstRun := true
+	var previousSlot uint64
 
 	for {
 		var slot uint64
@@ -428,6 +447,13 @@ func slotUpdater(wg *sync.WaitGroup) {
 			if err != nil {
 				logger.Errorf("error caching slot: %v", err)
 			}
+			if !firstRun && slot > previousSlot {
+				err := publishSlotEvents(previousSlot, slot)
+				if err != nil {
+					logger.Errorf("error publishing slot events: %v", err)
+				}
+			}
+			previousSlot = slot
 			if firstRun {
 				logger.Info("initialized slot updater")
 				wg.Done()
@@ -1358,6 +1384,7 @@ func getGasNowData() (*types.GasNowPageData, error) {
 	}
 
 	baseFee := block.Header.BaseFee
+	pendingHeader := block.Header
 
 	// Handle edgecase when there's too few txs in the pending block
 	// Use latest instead
@@ -1382,11 +1409,22 @@ func getGasNowData() (*types.GasNowPageData, error) {
 	}
 
 	gpoData := suggestGasPrices(block.Header.GasUsed, block.Header.GasLimit, baseFee, pendingTips)
+	suggestBlobGasPrices(gpoData, pendingHeader)
 
 	// not available in unit test mode
 	if db.BigtableClient != nil {
 		// Log or store historical data.
-		if err = db.BigtableClient.SaveGasNowHistory(gpoData.Data.Slow, gpoData.Data.Standard, gpoData.Data.Fast, gpoData.Data.Rapid); err != nil {
+		err = db.BigtableClient.SaveGasNowHistory(&types.GasNowHistory{
+			Ts:            time.Now(),
+			Slow:          gpoData.Data.Slow,
+			Standard:      gpoData.Data.Standard,
+			Fast:          gpoData.Data.Fast,
+			Rapid:         gpoData.Data.Rapid,
+			BlobBaseFee:   gpoData.Data.BlobBaseFee,
+			BlobGasUsed:   gpoData.Data.BlobGasUsed,
+			ExcessBlobGas: gpoData.Data.ExcessBlobGas,
+		})
+		if err != nil {
 			logrus.WithError(err).Error("error updating gas now history")
 		}
 
@@ -1400,6 +1438,37 @@ func getGasNowData() (*types.GasNowPageData, error) {
 	return gpoData, nil
 }
 
+// blobBaseFeeForecastBlocks is the number of blocks following the pending block the blob base fee is forecast for
+const blobBaseFeeForecastBlocks = 32
+
+// suggestBlobGasPrices adds the blob gas values of the pending block and the forecast of the blob base fee of the
+// following blocks, chains without blobs are left untouched
+func suggestBlobGasPrices(gpoData *types.GasNowPageData, pending *geth_types.Header) {
+	if pending.ExcessBlobGas == nil || pending.BlobGasUsed == nil {
+		return
+	}
+	params := utils.BlobParamsAt(pending.Time)
+
+	gpoData.Data.ExcessBlobGas = *pending.ExcessBlobGas
+	gpoData.Data.BlobGasUsed = *pending.BlobGasUsed
+	gpoData.Data.BlobBaseFee = utils.CalcBlobBaseFee(*pending.ExcessBlobGas, pending.Time)
+	gpoData.Data.BlobTarget = params.Target
+	gpoData.Data.BlobMax = params.Max
+
+	forecast := func(blobs func(params *types.BlobScheduleEntry) uint64) []*big.Int {
+		return utils.ForecastBlobBaseFees(*pending.ExcessBlobGas, *pending.BlobGasUsed, pending.BaseFee, pending.Time, blobBaseFeeForecastBlocks, blobs)
+	}
+	gpoData.Data.BlobBaseFeeForecast = &types.BlobBaseFeeForecast{
+		Blocks: make([]uint64, 0, blobBaseFeeForecastBlocks),
+		Empty:  forecast(func(params *types.BlobScheduleEntry) uint64 { return 0 }),
+		Target: forecast(func(params *types.BlobScheduleEntry) uint64 { return params.Target }),
+		Full:   forecast(func(params *types.BlobScheduleEntry) uint64 { return params.Max }),
+	}
+	for i := uint64(1); i <= blobBaseFeeForecastBlocks; i++ {
+		gpoData.Data.BlobBaseFeeForecast.Blocks = append(gpoData.Data.BlobBaseFeeForecast.Blocks, pending.Number.Uint64()+i)
+	}
+}
+
 func suggestGasPrices(gasUsed uint64, gasLimit uint64, baseFee *big.Int, pendingTips []*big.Int) *types.GasNowPageData {
 	gpoData := &types.GasNowPageData{}
 	gpoData.Code = 200
@@ -1538,6 +1607,8 @@ func mempoolUpdater(wg *sync.WaitGroup) {
 
 	var client *geth_rpc.Client
 
+	var history *mempoolHistory
+
 	for {
 		var err error
 
@@ -1599,6 +1670,23 @@ func mempoolUpdater(wg *sync.WaitGroup) {
 			}
 		}
 
+		if utils.Config.MempoolHistory.Enabled {
+			if history == nil {
+				history = newMempoolHistory()
+				if err := history.load(); err != nil {
+					utils.LogError(err, "error loading mempool history", 0)
+					history = nil
+				}
+			}
+			if history != nil {
+				if err := history.update(client, mempoolTx.TxsByHash, time.Now().UTC()); err != nil {
+					utils.LogError(err, "error updating mempool history", 0)
+					// the tracked transactions may be ahead of the db, continue with the pending transactions of the db
+					history = nil
+				}
+			}
+		}
+
 		cacheKey := fmt.Sprintf("%d:frontend:mempool", utils.Config.Chain.ClConfig.DepositChainID)
 		err = cache.TieredCache.Set(cacheKey, mempoolTx, utils.Day)
 		if err != nil {
@@ -182,4 +182,19 @@ func TestViaFakeRPC(t *testing.T) {
 	checkSuggestion(t, "Fast", result.Data.Fast.Int64(), 3511919489)
 	checkSuggestion(t, "Standard", result.Data.Standard.Int64(), 3123069597)
 	checkSuggestion(t, "Slow", result.Data.Slow.Int64(), 3073069597)
+
+	if result.Data.BlobBaseFee == nil || result.Data.BlobBaseFeeForecast == nil {
+		t.Fatalf("missing blob base fee of the pending block")
+	}
+	checkSuggestion(t, "BlobBaseFee", result.Data.BlobBaseFee.Int64(), 64600264983)
+	forecast := result.Data.BlobBaseFeeForecast
+	if len(forecast.Blocks) != blobBaseFeeForecastBlocks || len(forecast.Empty) != blobBaseFeeForecastBlocks || len(forecast.Target) != blobBaseFeeForecastBlocks || len(forecast.Full) != blobBaseFeeForecastBlocks {
+		t.Fatalf("expected a forecast of %d blocks, got %+v", blobBaseFeeForecastBlocks, forecast)
+	}
+	checkSuggestion(t, "BlobBaseFeeEmpty", forecast.Empty[0].Int64(), 57422457042)
+	for i := range forecast.Blocks {
+		if forecast.Empty[i].Cmp(forecast.Target[i]) > 0 || forecast.Target[i].Cmp(forecast.Full[i]) > 0 {
+			t.Errorf("block %d: expected empty <= target <= full, got %v, %v, %v", forecast.Blocks[i], forecast.Empty[i], forecast.Target[i], forecast.Full[i])
+		}
+	}
 }
@@ -0,0 +1,278 @@
+package slasher
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+)
+
+// voteHistoryLength is the number of target epochs the votes of the validators are kept for to detect double votes,
+// attestations can only be included in blocks of their target epoch and the epoch after it
+const voteHistoryLength = 4
+
+type OffenceType string
+
+const (
+	DoubleVote   OffenceType = "double_vote"
+	SurroundVote OffenceType = "surround_vote"
+)
+
+// Offence is a slashable attestation of a validator that conflicts with an earlier attestation of the same validator
+type Offence struct {
+	Type           OffenceType
+	ValidatorIndex uint64
+	Attestation    *types.IndexedAttestation
+	DataRoot       [32]byte
+	// ConflictingTargetEpoch is the target epoch of the earlier attestation
+	ConflictingTargetEpoch uint64
+	// Surrounding is true if the attestation surrounds the earlier attestation and false if it is surrounded by it
+	Surrounding bool
+}
+
+// Slasher detects double and surround votes of validators
+// It keeps the min and max target spans of every validator for the last historyLength epochs, for a source epoch e
+// the min span holds the lowest target of all attestations with a source after e and the max span the highest target
+// of all attestations with a source before e. Both are stored as distance to e, 0 means that no attestation is known.
+type Slasher struct {
+	mu sync.Mutex
+
+	historyLength uint64
+	currentEpoch  uint64
+	spans         *spanStore
+	db            *pebble.DB
+	// votes holds a hash of the attested data of every validator by target epoch
+	votes map[uint64][]uint64
+}
+
+// New returns a slasher that keeps the spans for historyLength epochs in memory, attestations with an older target are
+// ignored
+func New(historyLength uint64) *Slasher {
+	if historyLength < voteHistoryLength {
+		historyLength = voteHistoryLength
+	}
+	if historyLength > 1<<16-1 {
+		historyLength = 1<<16 - 1
+	}
+	return &Slasher{
+		historyLength: historyLength,
+		spans:         newSpanStore(nil),
+		votes:         make(map[uint64][]uint64),
+	}
+}
+
+// Open returns a slasher that keeps the spans in the pebble database in the directory, the database is cleared as the
+// spans are rebuilt from the attestations of the history
+func Open(historyLength uint64, path string) (*Slasher, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("could not open slasher db at %s: %w", path, err)
+	}
+	s := New(historyLength)
+	s.db = db
+	s.spans = newSpanStore(db)
+	err = s.spans.reset()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the database of the slasher
+func (s *Slasher) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Reset forgets all attestations
+func (s *Slasher) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.currentEpoch = 0
+	clear(s.votes)
+	return s.spans.reset()
+}
+
+// Flush writes the spans to the database of the slasher and only keeps the latest spans in memory, it should be called
+// after the attestations of a block have been processed
+func (s *Slasher) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// attestations are included up to an epoch after their target and update the min span of the epoch before their
+	// source, so the chunks of the previous epochs are kept as well
+	keep := uint64(0)
+	if s.currentEpoch > spanChunkEpochs {
+		keep = s.currentEpoch - spanChunkEpochs
+	}
+	return s.spans.flush(keep)
+}
+
+// DataRoot returns the hash tree root of the attestation data
+func DataRoot(data *types.AttestationData) ([32]byte, error) {
+	return (&ethpb.AttestationData{
+		Slot:            primitives.Slot(data.Slot),
+		CommitteeIndex:  primitives.CommitteeIndex(data.CommitteeIndex),
+		BeaconBlockRoot: data.BeaconBlockRoot,
+		Source:          &ethpb.Checkpoint{Epoch: primitives.Epoch(data.Source.Epoch), Root: data.Source.Root},
+		Target:          &ethpb.Checkpoint{Epoch: primitives.Epoch(data.Target.Epoch), Root: data.Target.Root},
+	}).HashTreeRoot()
+}
+
+// CurrentEpoch returns the latest epoch the slasher has seen
+func (s *Slasher) CurrentEpoch() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentEpoch
+}
+
+// HistoryStart returns the first epoch of the history window of the slasher
+func (s *Slasher) HistoryStart() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.historyStart()
+}
+
+func (s *Slasher) historyStart() uint64 {
+	if s.currentEpoch+1 < s.historyLength {
+		return 0
+	}
+	return s.currentEpoch + 1 - s.historyLength
+}
+
+// Advance moves the history window of the slasher forward to the epoch
+func (s *Slasher) Advance(epoch uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.advance(epoch)
+}
+
+func (s *Slasher) advance(epoch uint64) error {
+	if epoch <= s.currentEpoch {
+		return nil
+	}
+	s.currentEpoch = epoch
+
+	for target := range s.votes {
+		if target+voteHistoryLength <= epoch {
+			delete(s.votes, target)
+		}
+	}
+	// the spans are stored by epoch, spans of epochs before the window are never read and only deleted to free space
+	return s.spans.prune(s.historyStart())
+}
+
+// ProcessAttestation checks the attestation of every attesting validator against their earlier attestations and
+// records it, it returns the offences of all validators that are slashable
+func (s *Slasher) ProcessAttestation(attestation *types.IndexedAttestation) ([]*Offence, error) {
+	dataRoot, err := DataRoot(attestation.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, target := attestation.Data.Source.Epoch, attestation.Data.Target.Epoch
+	if err := s.advance(target); err != nil {
+		return nil, err
+	}
+	start := s.historyStart()
+	if target < start || source > target {
+		return nil, nil
+	}
+
+	vote := binary.BigEndian.Uint64(dataRoot[:8]) | 1
+	var votes []uint64
+	if target+voteHistoryLength > s.currentEpoch {
+		votes = s.votes[target]
+	}
+
+	var offences []*Offence
+	for _, validator := range attestation.AttestingIndices {
+		if target+voteHistoryLength > s.currentEpoch {
+			if validator >= uint64(len(votes)) {
+				votes = append(votes, make([]uint64, validator+1-uint64(len(votes)))...)
+			}
+			if votes[validator] != 0 && votes[validator] != vote {
+				offences = append(offences, &Offence{
+					Type:                   DoubleVote,
+					ValidatorIndex:         validator,
+					Attestation:            attestation,
+					DataRoot:               dataRoot,
+					ConflictingTargetEpoch: target,
+				})
+			}
+			votes[validator] = vote
+		}
+
+		if source >= start {
+			minSpan, err := s.spans.get(minSpanKind, validator, source)
+			if err != nil {
+				return nil, err
+			}
+			if minSpan != 0 && source+uint64(minSpan) < target {
+				offences = append(offences, &Offence{
+					Type:                   SurroundVote,
+					ValidatorIndex:         validator,
+					Attestation:            attestation,
+					DataRoot:               dataRoot,
+					ConflictingTargetEpoch: source + uint64(minSpan),
+					Surrounding:            true,
+				})
+			}
+			maxSpan, err := s.spans.get(maxSpanKind, validator, source)
+			if err != nil {
+				return nil, err
+			}
+			if maxSpan != 0 && source+uint64(maxSpan) > target {
+				offences = append(offences, &Offence{
+					Type:                   SurroundVote,
+					ValidatorIndex:         validator,
+					Attestation:            attestation,
+					DataRoot:               dataRoot,
+					ConflictingTargetEpoch: source + uint64(maxSpan),
+				})
+			}
+		}
+
+		// the min target is non decreasing with the epoch, so the update can stop at the first epoch that has a lower target
+		for e := source; e > start; e-- {
+			minSpan, err := s.spans.get(minSpanKind, validator, e-1)
+			if err != nil {
+				return nil, err
+			}
+			if minSpan != 0 && e-1+uint64(minSpan) <= target {
+				break
+			}
+			if err := s.spans.set(minSpanKind, validator, e-1, uint16(target-(e-1))); err != nil {
+				return nil, err
+			}
+		}
+		// the max target is non decreasing as well, targets before the epoch are never surrounding and not stored
+		for e := max(source+1, start); e <= s.currentEpoch && e < target; e++ {
+			maxSpan, err := s.spans.get(maxSpanKind, validator, e)
+			if err != nil {
+				return nil, err
+			}
+			if maxSpan != 0 && e+uint64(maxSpan) >= target {
+				break
+			}
+			if err := s.spans.set(maxSpanKind, validator, e, uint16(target-e)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if target+voteHistoryLength > s.currentEpoch {
+		s.votes[target] = votes
+	}
+
+	return offences, nil
+}
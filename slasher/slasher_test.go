@@ -0,0 +1,201 @@
+package slasher
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+func testAttestation(source, target uint64, blockRoot byte, validators ...uint64) *types.IndexedAttestation {
+	return &types.IndexedAttestation{
+		Data: &types.AttestationData{
+			Slot:            target * 32,
+			BeaconBlockRoot: bytes.Repeat([]byte{blockRoot}, 32),
+			Source:          &types.Checkpoint{Epoch: source, Root: make([]byte, 32)},
+			Target:          &types.Checkpoint{Epoch: target, Root: make([]byte, 32)},
+		},
+		AttestingIndices: validators,
+	}
+}
+
+func TestSlasher(t *testing.T) {
+	type offence struct {
+		typ         OffenceType
+		validator   uint64
+		target      uint64
+		surrounding bool
+	}
+	tests := []struct {
+		name         string
+		attestations []*types.IndexedAttestation
+		expected     []offence
+	}{
+		{
+			name: "honest attestations",
+			attestations: []*types.IndexedAttestation{
+				testAttestation(9, 10, 1, 1, 2),
+				testAttestation(10, 11, 2, 1, 2),
+				testAttestation(11, 12, 3, 1, 2),
+				testAttestation(10, 12, 4, 3),
+			},
+		},
+		{
+			name: "same attestation twice",
+			attestations: []*types.IndexedAttestation{
+				testAttestation(9, 10, 1, 1, 2),
+				testAttestation(9, 10, 1, 2, 3),
+			},
+		},
+		{
+			name: "double vote",
+			attestations: []*types.IndexedAttestation{
+				testAttestation(9, 10, 1, 1, 2),
+				testAttestation(9, 10, 2, 2, 3),
+			},
+			expected: []offence{{typ: DoubleVote, validator: 2, target: 10}},
+		},
+		{
+			name: "surrounding vote",
+			attestations: []*types.IndexedAttestation{
+				testAttestation(9, 10, 1, 1),
+				testAttestation(10, 11, 2, 1),
+				testAttestation(8, 12, 3, 1),
+			},
+			expected: []offence{{typ: SurroundVote, validator: 1, target: 10, surrounding: true}},
+		},
+		{
+			name: "surrounded vote",
+			attestations: []*types.IndexedAttestation{
+				testAttestation(5, 12, 1, 1),
+				testAttestation(9, 10, 2, 2),
+				testAttestation(9, 10, 3, 1),
+			},
+			expected: []offence{{typ: SurroundVote, validator: 1, target: 12}},
+		},
+		{
+			name: "surrounded vote of an earlier target",
+			attestations: []*types.IndexedAttestation{
+				testAttestation(8, 11, 1, 1),
+				testAttestation(9, 10, 2, 1),
+			},
+			expected: []offence{{typ: SurroundVote, validator: 1, target: 11}},
+		},
+		{
+			name: "source out of the history window",
+			attestations: []*types.IndexedAttestation{
+				testAttestation(98, 99, 1, 1),
+				testAttestation(99, 100, 2, 1),
+				testAttestation(10, 101, 3, 1),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, onDisk := range []bool{false, true} {
+			name := tt.name
+			if onDisk {
+				name += " on disk"
+			}
+			t.Run(name, func(t *testing.T) {
+				s := New(16)
+				if onDisk {
+					var err error
+					s, err = Open(16, t.TempDir())
+					if err != nil {
+						t.Fatal(err)
+					}
+					defer s.Close()
+				}
+				var got []*Offence
+				for _, attestation := range tt.attestations {
+					offences, err := s.ProcessAttestation(attestation)
+					if err != nil {
+						t.Fatal(err)
+					}
+					got = append(got, offences...)
+					// flushing drops the spans of old epochs from memory, they have to be read from disk again
+					if err := s.Flush(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if len(got) != len(tt.expected) {
+					t.Fatalf("got %v offences want %v", len(got), len(tt.expected))
+				}
+				for i, want := range tt.expected {
+					if got[i].Type != want.typ || got[i].ValidatorIndex != want.validator || got[i].ConflictingTargetEpoch != want.target || got[i].Surrounding != want.surrounding {
+						t.Errorf("got %+v want %+v", *got[i], want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSlasherAdvance(t *testing.T) {
+	s := New(8)
+	if _, err := s.ProcessAttestation(testAttestation(9, 10, 1, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the spans of epochs entering the window must not contain the values of the epochs that left it
+	if err := s.Advance(20); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.HistoryStart(), uint64(13); got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	offences, err := s.ProcessAttestation(testAttestation(17, 20, 2, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offences) != 0 {
+		t.Errorf("got %v offences want none", len(offences))
+	}
+
+	offences, err = s.ProcessAttestation(testAttestation(16, 20, 3, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offences) != 1 || offences[0].Type != DoubleVote {
+		t.Errorf("got %v want a double vote", offences)
+	}
+}
+
+func TestSlasherReset(t *testing.T) {
+	s, err := Open(64, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// the surrounding attestation is far enough in the past to be evicted from memory after flushing
+	if _, err := s.ProcessAttestation(testAttestation(5, 40, 1, 7)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Advance(60); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	offences, err := s.ProcessAttestation(testAttestation(10, 11, 2, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offences) != 1 || offences[0].Type != SurroundVote {
+		t.Fatalf("got %v want a surround vote", offences)
+	}
+
+	// after a reset the attestations are replayed, the surrounded attestation alone is not slashable
+	if err := s.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	offences, err = s.ProcessAttestation(testAttestation(10, 11, 2, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offences) != 0 {
+		t.Errorf("got %v offences after the reset want none", len(offences))
+	}
+}
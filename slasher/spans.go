@@ -0,0 +1,175 @@
+package slasher
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+)
+
+const (
+	// spanChunkValidators and spanChunkEpochs are the dimensions of a span chunk, attestations of a block mostly update
+	// the spans of the latest epochs, so only the chunks of those epochs have to be kept in memory
+	spanChunkValidators = 256
+	spanChunkEpochs     = 16
+	spanChunkSize       = spanChunkValidators * spanChunkEpochs
+
+	minSpanKind byte = 0
+	maxSpanKind byte = 1
+)
+
+// spanChunkKey identifies the chunk of the min or max spans of 256 validators for 16 epochs
+type spanChunkKey struct {
+	kind           byte
+	epochChunk     uint64
+	validatorChunk uint64
+}
+
+// encode returns the key of the chunk in the on-disk store, chunks are ordered by epoch so the chunks that leave the
+// history window can be deleted with a range deletion
+func (k spanChunkKey) encode() []byte {
+	key := make([]byte, 17)
+	key[0] = k.kind
+	binary.BigEndian.PutUint64(key[1:], k.epochChunk)
+	binary.BigEndian.PutUint64(key[9:], k.validatorChunk)
+	return key
+}
+
+// spanStore holds the min and max spans of the validators in chunks. Without a database all chunks are kept in
+// memory, which takes 4 bytes per validator and epoch of the history. With a database only the chunks of the latest
+// epochs are cached and the others are loaded from disk when they are needed.
+type spanStore struct {
+	db     *pebble.DB
+	chunks map[spanChunkKey][]uint16
+	dirty  map[spanChunkKey]bool
+}
+
+func newSpanStore(db *pebble.DB) *spanStore {
+	return &spanStore{
+		db:     db,
+		chunks: make(map[spanChunkKey][]uint16),
+		dirty:  make(map[spanChunkKey]bool),
+	}
+}
+
+func spanPosition(kind byte, validator, epoch uint64) (spanChunkKey, int) {
+	key := spanChunkKey{kind: kind, epochChunk: epoch / spanChunkEpochs, validatorChunk: validator / spanChunkValidators}
+	return key, int(validator%spanChunkValidators)*spanChunkEpochs + int(epoch%spanChunkEpochs)
+}
+
+// chunk returns the chunk of the key, a nil chunk has no spans set
+func (s *spanStore) chunk(key spanChunkKey) ([]uint16, error) {
+	if chunk, ok := s.chunks[key]; ok || s.db == nil {
+		return chunk, nil
+	}
+
+	value, closer, err := s.db.Get(key.encode())
+	if errors.Is(err, pebble.ErrNotFound) {
+		s.chunks[key] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading span chunk: %w", err)
+	}
+	defer closer.Close()
+	if len(value) != spanChunkSize*2 {
+		return nil, fmt.Errorf("span chunk has %v bytes, want %v", len(value), spanChunkSize*2)
+	}
+	chunk := make([]uint16, spanChunkSize)
+	for i := range chunk {
+		chunk[i] = binary.LittleEndian.Uint16(value[i*2:])
+	}
+	s.chunks[key] = chunk
+	return chunk, nil
+}
+
+func (s *spanStore) get(kind byte, validator, epoch uint64) (uint16, error) {
+	key, i := spanPosition(kind, validator, epoch)
+	chunk, err := s.chunk(key)
+	if chunk == nil || err != nil {
+		return 0, err
+	}
+	return chunk[i], nil
+}
+
+func (s *spanStore) set(kind byte, validator, epoch uint64, span uint16) error {
+	key, i := spanPosition(kind, validator, epoch)
+	chunk, err := s.chunk(key)
+	if err != nil {
+		return err
+	}
+	if chunk == nil {
+		chunk = make([]uint16, spanChunkSize)
+		s.chunks[key] = chunk
+	}
+	chunk[i] = span
+	s.dirty[key] = true
+	return nil
+}
+
+// flush writes the changed chunks to disk and drops the chunks before the epoch from the cache
+func (s *spanStore) flush(epoch uint64) error {
+	if s.db == nil {
+		return nil
+	}
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	value := make([]byte, spanChunkSize*2)
+	for key := range s.dirty {
+		for i, span := range s.chunks[key] {
+			binary.LittleEndian.PutUint16(value[i*2:], span)
+		}
+		if err := batch.Set(key.encode(), value, nil); err != nil {
+			return fmt.Errorf("error adding span chunk to batch: %w", err)
+		}
+	}
+	// the spans are rebuilt from the attestations of the database after a restart, so the writes do not need to be synced
+	if err := batch.Commit(pebble.NoSync); err != nil {
+		return fmt.Errorf("error writing span chunks: %w", err)
+	}
+	clear(s.dirty)
+
+	for key := range s.chunks {
+		if key.epochChunk < epoch/spanChunkEpochs {
+			delete(s.chunks, key)
+		}
+	}
+	return nil
+}
+
+// prune deletes the chunks that only contain epochs before the epoch
+func (s *spanStore) prune(epoch uint64) error {
+	end := epoch / spanChunkEpochs
+	for key := range s.chunks {
+		if key.epochChunk < end {
+			delete(s.chunks, key)
+			delete(s.dirty, key)
+		}
+	}
+	if s.db == nil || end == 0 {
+		return nil
+	}
+	for _, kind := range []byte{minSpanKind, maxSpanKind} {
+		err := s.db.DeleteRange(spanChunkKey{kind: kind}.encode(), spanChunkKey{kind: kind, epochChunk: end}.encode(), pebble.NoSync)
+		if err != nil {
+			return fmt.Errorf("error deleting span chunks: %w", err)
+		}
+	}
+	return nil
+}
+
+// reset deletes all spans
+func (s *spanStore) reset() error {
+	clear(s.chunks)
+	clear(s.dirty)
+	if s.db == nil {
+		return nil
+	}
+	err := s.db.DeleteRange([]byte{minSpanKind}, []byte{maxSpanKind + 1}, pebble.NoSync)
+	if err != nil {
+		return fmt.Errorf("error deleting span chunks: %w", err)
+	}
+	return nil
+}
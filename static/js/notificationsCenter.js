@@ -1,6 +1,6 @@
 var csrfToken = ""
 
-const VALIDATOR_EVENTS = ["validator_attestation_missed", "validator_proposal_missed", "validator_proposal_submitted", "validator_got_slashed", "validator_synccommittee_soon", "validator_is_offline", "validator_withdrawal"]
+const VALIDATOR_EVENTS = ["validator_attestation_missed", "validator_proposal_missed", "validator_proposal_submitted", "validator_got_slashed", "validator_synccommittee_soon", "validator_is_offline", "validator_withdrawal", "validator_consolidation_request", "validator_consolidation_processed", "validator_consolidation_rejected", "validator_switched_to_compounding", "validator_withdrawal_request", "validator_queue_estimate_changed", "validator_slashing_detected"]
 
 // const MONITORING_EVENTS = ['monitoring_machine_offline', 'monitoring_hdd_almostfull', 'monitoring_cpu_load']
 
@@ -690,6 +690,17 @@ function loadValidatorsData(data) {
                     break
                   case "validator_withdrawal":
                     badgeColor = "badge-light"
+                    break
+                  case "validator_consolidation_request":
+                  case "validator_consolidation_processed":
+                  case "validator_consolidation_rejected":
+                  case "validator_switched_to_compounding":
+                  case "validator_withdrawal_request":
+                  case "validator_queue_estimate_changed":
+                    badgeColor = "badge-light"
+                    break
+                  case "validator_slashing_detected":
+                    badgeColor = "badge-danger"
                 }
                 notifications += `<span style="font-size: 12px; font-weight: 500;" class="badge badge-pill ${badgeColor} ${textColor} badge-custom-size mr-1 my-1">${n.replace("validator", "").replaceAll("_", " ")}</span>`
               }
@@ -14,6 +14,13 @@ type ApiResponse struct {
 	Data   interface{} `json:"data"`
 }
 
+// ApiListResponse is the response of the paginated list endpoints, next_cursor is null on the last page
+type ApiListResponse struct {
+	Status     string      `json:"status"`
+	Data       interface{} `json:"data"`
+	NextCursor *string     `json:"next_cursor"`
+}
+
 type StatsSystem struct {
 	CPUCores                      uint64 `mapstructure:"cpu_cores"`
 	CPUThreads                    uint64 `mapstructure:"cpu_threads"`
@@ -222,9 +229,82 @@ type ApiEth1AddressERC20TokenResponse struct {
 }
 
 type ApiEth1AddressResponse struct {
-	Address string                             `json:"address"`
-	Ether   string                             `json:"ether"`
-	Tokens  []ApiEth1AddressERC20TokenResponse `json:"tokens"`
+	Address     string                             `json:"address"`
+	Ether       string                             `json:"ether"`
+	Tokens      []ApiEth1AddressERC20TokenResponse `json:"tokens"`
+	DelegatesTo string                             `json:"delegates_to,omitempty"`
+}
+
+type ApiEth1SetCodeAuthorizationResponse struct {
+	TxHash      string    `json:"tx_hash"`
+	BlockNumber uint64    `json:"block_number"`
+	Time        time.Time `json:"time"`
+	Authority   string    `json:"authority"`
+	Delegate    string    `json:"delegate"`
+	ChainId     string    `json:"chain_id"`
+	Nonce       uint64    `json:"nonce"`
+	Valid       bool      `json:"valid"`
+}
+
+type ApiEth1AddressSetCodeAuthorizationsResponse struct {
+	Authorizations []ApiEth1SetCodeAuthorizationResponse `json:"authorizations"`
+	PageToken      string                                `json:"page_token"`
+}
+
+type ApiBlobSubmitterStatsResponse struct {
+	Day            string   `json:"day"`
+	Sender         string   `json:"sender"`
+	To             string   `json:"to"`
+	Rollup         string   `json:"rollup,omitempty"`
+	TxCount        uint64   `json:"tx_count"`
+	BlobCount      uint64   `json:"blob_count"`
+	BlobGasUsed    string   `json:"blob_gas_used"`
+	BlobFee        string   `json:"blob_fee"`        // blob fees paid in wei
+	FillEfficiency *float64 `json:"fill_efficiency"` // share of non-zero bytes of the blobs, null if the blobs have not been measured
+}
+
+type ApiEth1ProxyImplementationResponse struct {
+	TxHash         string    `json:"tx_hash"`
+	BlockNumber    uint64    `json:"block_number"`
+	Time           time.Time `json:"time"`
+	Type           string    `json:"type"`
+	Implementation string    `json:"implementation,omitempty"`
+	Beacon         string    `json:"beacon,omitempty"`
+	Admin          string    `json:"admin,omitempty"`
+}
+
+type ApiEth1UserOperationResponse struct {
+	UserOpHash    string    `json:"user_op_hash"`
+	TxHash        string    `json:"tx_hash"`
+	BlockNumber   uint64    `json:"block_number"`
+	Time          time.Time `json:"time"`
+	EntryPoint    string    `json:"entry_point"`
+	Sender        string    `json:"sender"`
+	Paymaster     string    `json:"paymaster,omitempty"`
+	Bundler       string    `json:"bundler"`
+	Factory       string    `json:"factory,omitempty"`
+	MethodId      string    `json:"method_id,omitempty"`
+	Nonce         string    `json:"nonce"`
+	Success       bool      `json:"success"`
+	ActualGasCost string    `json:"actual_gas_cost"`
+	ActualGasUsed string    `json:"actual_gas_used"`
+}
+
+type ApiEth1AddressUserOperationsResponse struct {
+	UserOperations []ApiEth1UserOperationResponse `json:"user_operations"`
+	PageToken      string                         `json:"page_token"`
+}
+
+type ApiEth1AddressApprovalResponse struct {
+	Token       string    `json:"token"`
+	Type        string    `json:"type"`
+	Spender     string    `json:"spender"`
+	Allowance   string    `json:"allowance,omitempty"`
+	TokenId     string    `json:"token_id,omitempty"`
+	TxHash      string    `json:"tx_hash"`
+	BlockNumber uint64    `json:"block_number"`
+	Time        time.Time `json:"time"`
+	Verified    bool      `json:"verified"`
 }
 
 type Eth1TransactionParsed struct {
@@ -434,6 +514,47 @@ type APIProposerSlashingResponse struct {
 	ProposerIndex     uint64 `json:"proposerindex"`
 }
 
+type APISlasherAttesterSlashingResponse struct {
+	DetectedSlot                 uint64   `json:"detected_slot"`
+	DetectedTs                   string   `json:"detected_ts"`
+	Type                         string   `json:"type"`
+	Validators                   []uint64 `json:"validators"`
+	Attestation1_beaconblockroot string   `json:"attestation1_beaconblockroot"`
+	Attestation1_index           uint64   `json:"attestation1_index"`
+	Attestation1_indices         []uint64 `json:"attestation1_indices"`
+	Attestation1_signature       string   `json:"attestation1_signature"`
+	Attestation1_slot            uint64   `json:"attestation1_slot"`
+	Attestation1_source_epoch    uint64   `json:"attestation1_source_epoch"`
+	Attestation1_source_root     string   `json:"attestation1_source_root"`
+	Attestation1_target_epoch    uint64   `json:"attestation1_target_epoch"`
+	Attestation1_target_root     string   `json:"attestation1_target_root"`
+	Attestation2_beaconblockroot string   `json:"attestation2_beaconblockroot"`
+	Attestation2_index           uint64   `json:"attestation2_index"`
+	Attestation2_indices         []uint64 `json:"attestation2_indices"`
+	Attestation2_signature       string   `json:"attestation2_signature"`
+	Attestation2_slot            uint64   `json:"attestation2_slot"`
+	Attestation2_source_epoch    uint64   `json:"attestation2_source_epoch"`
+	Attestation2_source_root     string   `json:"attestation2_source_root"`
+	Attestation2_target_epoch    uint64   `json:"attestation2_target_epoch"`
+	Attestation2_target_root     string   `json:"attestation2_target_root"`
+}
+
+type APISlasherProposerSlashingResponse struct {
+	DetectedSlot      uint64 `json:"detected_slot"`
+	DetectedTs        string `json:"detected_ts"`
+	Header1Bodyroot   string `json:"header1_bodyroot"`
+	Header1Parentroot string `json:"header1_parentroot"`
+	Header1Signature  string `json:"header1_signature"`
+	Header1Slot       uint64 `json:"header1_slot"`
+	Header1Stateroot  string `json:"header1_stateroot"`
+	Header2Bodyroot   string `json:"header2_bodyroot"`
+	Header2Parentroot string `json:"header2_parentroot"`
+	Header2Signature  string `json:"header2_signature"`
+	Header2Slot       uint64 `json:"header2_slot"`
+	Header2Stateroot  string `json:"header2_stateroot"`
+	ProposerIndex     uint64 `json:"proposerindex"`
+}
+
 type APIVoluntaryExitResponse struct {
 	BlockIndex     uint64 `json:"block_index"`
 	BlockRoot      string `json:"block_root"`
@@ -494,6 +615,24 @@ type ApiValidatorQueueResponse struct {
 	ValidatorsCount     uint64 `json:"validators_count"`
 }
 
+type ApiValidatorQueuePositionResponse struct {
+	Pubkey         string                                     `json:"pubkey"`
+	ValidatorIndex *uint64                                    `json:"validatorindex"`
+	Queue          string                                     `json:"queue"`
+	Position       uint64                                     `json:"position"`
+	BalanceAhead   uint64                                     `json:"balance_ahead"`
+	EstimatedEpoch uint64                                     `json:"estimated_epoch"`
+	EstimatedTs    int64                                      `json:"estimated_ts"`
+	History        []ApiValidatorQueuePositionHistoryResponse `json:"history"`
+}
+
+type ApiValidatorQueuePositionHistoryResponse struct {
+	Ts             int64  `json:"ts"`
+	Position       uint64 `json:"position"`
+	BalanceAhead   uint64 `json:"balance_ahead"`
+	EstimatedEpoch uint64 `json:"estimated_epoch"`
+}
+
 type ApiValidatorDailyStatsResponse struct {
 	ValidatorIndex        uint64    `json:"validatorindex"`
 	AttesterSlashings     uint64    `json:"attester_slashings"`
@@ -692,3 +831,56 @@ type ApiProposalLuckResponse struct {
 	NextProposalEstimateTs  *int64   `json:"next_proposal_estimate_ts"` // The estimated timestamp of the next proposal
 	TimeFrameName           *string  `json:"time_frame_name"`           // The timeframe for which the luck is calculated
 }
+
+type ApiUserUsageResponse struct {
+	From             time.Time          `json:"from"`
+	To               time.Time          `json:"to"`
+	Interval         string             `json:"interval"` // length of the time buckets, hour or day
+	Total            int64              `json:"total"`
+	Blocked          int64              `json:"blocked"` // requests that were answered with 429
+	MonthlyQuotas    []ApiUsageQuota    `json:"monthly_quotas"`
+	Routes           []*ApiUsageSeries  `json:"routes"`
+	Keys             []*ApiUsageSeries  `json:"keys"`
+	TopBlockedRoutes []*ApiUsageBlocked `json:"top_blocked_routes"`
+}
+
+type ApiUsageQuota struct {
+	Bucket    string `json:"bucket"`
+	Limit     int64  `json:"limit"`     // 0 if there is no monthly limit
+	Used      int64  `json:"used"`      // weighted requests of the current month
+	Remaining *int64 `json:"remaining"` // null if there is no monthly limit
+	ResetTs   int64  `json:"reset_ts"`
+}
+
+type ApiUsageSeries struct {
+	Name    string           `json:"name"` // route or masked api key
+	Total   int64            `json:"total"`
+	Blocked int64            `json:"blocked"`
+	Data    []*ApiUsagePoint `json:"data"`
+}
+
+type ApiUsagePoint struct {
+	Ts      int64 `json:"ts"`
+	Count   int64 `json:"count"`
+	Blocked int64 `json:"blocked"`
+}
+
+type ApiUsageBlocked struct {
+	Route   string `json:"route"`
+	Blocked int64  `json:"blocked"`
+}
+
+// ApiValidatorExportRequest is the request to export the balance or income history of validators
+type ApiValidatorExportRequest struct {
+	Validators string   `json:"validators"` // comma separated validator indices or pubkeys
+	Source     string   `json:"source"`     // balance or income
+	Fields     []string `json:"fields"`     // columns of the export, all columns of the source if empty
+	Format     string   `json:"format"`     // csv or parquet
+	StartDate  string   `json:"start_date"` // first day of the export, YYYY-MM-DD in UTC
+	EndDate    string   `json:"end_date"`   // last day of the export, YYYY-MM-DD in UTC
+}
+
+// ApiWebhookSecretResponse is the regenerated signing secret of a webhook
+type ApiWebhookSecretResponse struct {
+	Secret string `json:"secret"`
+}
@@ -55,6 +55,10 @@ type GasNowHistory struct {
 	Standard *big.Int
 	Fast     *big.Int
 	Rapid    *big.Int
+
+	BlobBaseFee   *big.Int
+	BlobGasUsed   uint64
+	ExcessBlobGas uint64
 }
 
 type BulkMutations struct {
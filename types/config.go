@@ -33,6 +33,7 @@ type Config struct {
 	RawBigtable struct {
 		Bigtable Bigtable `yaml:"bigtable"`
 		Remote   string   `yaml:"remote"`
+		Pebble   string   `yaml:"pebble"` // directory of an embedded pebble database used instead of bigtable
 	} `yaml:"rawBigtable"`
 	BlobIndexer struct {
 		S3 struct {
@@ -41,6 +42,8 @@ type Config struct {
 			AccessKeyId     string `yaml:"accessKeyId" envconfig:"BLOB_INDEXER_S3_ACCESS_KEY_ID"`
 			AccessKeySecret string `yaml:"accessKeySecret" envconfig:"BLOB_INDEXER_S3_ACCESS_KEY_SECRET"`
 		} `yaml:"s3"`
+		// SaveStats saves the number of non-zero bytes of each blob to the writer database for the blob submitter analytics
+		SaveStats bool `yaml:"saveStats" envconfig:"BLOB_INDEXER_SAVE_STATS"`
 	} `yaml:"blobIndexer"`
 	Chain                     `yaml:"chain"`
 	Eth1ErigonEndpoint        string `yaml:"eth1ErigonEndpoint" envconfig:"ETH1_ERIGON_ENDPOINT"`
@@ -60,6 +63,7 @@ type Config struct {
 			Port         string `yaml:"port" envconfig:"CLICKHOUSE_READER_DB_PORT"`
 			MaxOpenConns int    `yaml:"maxOpenConns" envconfig:"CLICKHOUSE_READER_DB_MAX_OPEN_CONNS"`
 			MaxIdleConns int    `yaml:"maxIdleConns" envconfig:"CLICKHOUSE_READER_DB_MAX_IDLE_CONNS"`
+			SSL          bool   `yaml:"ssl" envconfig:"CLICKHOUSE_READER_DB_SSL"`
 		} `yaml:"readerDatabase"`
 	} `yaml:"clickhouse"`
 	ClickHouseEnabled bool          `yaml:"clickHouseEnabled" envconfig:"CLICKHOUSE_ENABLED"`
@@ -71,8 +75,18 @@ type Config struct {
 			Host     string `yaml:"host" envconfig:"INDEXER_NODE_HOST"`
 			Type     string `yaml:"type" envconfig:"INDEXER_NODE_TYPE"`
 			PageSize int32  `yaml:"pageSize" envconfig:"INDEXER_NODE_PAGE_SIZE"`
+			// the standard api has no participation endpoint, the participation of an epoch is calculated from the
+			// debug beacon state which downloads the full state per epoch, only enable it for nodes close to the indexer
+			StateParticipation bool `yaml:"stateParticipation" envconfig:"INDEXER_NODE_STATE_PARTICIPATION"`
 		} `yaml:"node"`
-		Eth1DepositContractFirstBlock uint64 `yaml:"eth1DepositContractFirstBlock" envconfig:"INDEXER_ETH1_DEPOSIT_CONTRACT_FIRST_BLOCK"`
+		FailoverNodes []struct {
+			Port string `yaml:"port"`
+			Host string `yaml:"host"`
+			Type string `yaml:"type"`
+		} `yaml:"failoverNodes"`
+		NodeQuorum                    int           `yaml:"nodeQuorum" envconfig:"INDEXER_NODE_QUORUM"`
+		NodeHealthCheckInterval       time.Duration `yaml:"nodeHealthCheckInterval" envconfig:"INDEXER_NODE_HEALTH_CHECK_INTERVAL"`
+		Eth1DepositContractFirstBlock uint64        `yaml:"eth1DepositContractFirstBlock" envconfig:"INDEXER_ETH1_DEPOSIT_CONTRACT_FIRST_BLOCK"`
 		PubKeyTagsExporter            struct {
 			Enabled bool `yaml:"enabled" envconfig:"PUBKEY_TAGS_EXPORTER_ENABLED"`
 		} `yaml:"pubkeyTagsExporter"`
@@ -96,10 +110,24 @@ type Config struct {
 		MachineEventThreshold                         uint64  `yaml:"machineEventThreshold" envconfig:"MACHINE_EVENT_THRESHOLD"`
 		MachineEventFirstRatioThreshold               float64 `yaml:"machineEventFirstRatioThreshold" envconfig:"MACHINE_EVENT_FIRST_RATIO_THRESHOLD"`
 		MachineEventSecondRatioThreshold              float64 `yaml:"machineEventSecondRatioThreshold" envconfig:"MACHINE_EVENT_SECOND_RATIO_THRESHOLD"`
+		QueueEstimateChangeThreshold                  uint64  `yaml:"queueEstimateChangeThreshold" envconfig:"NOTIFICATIONS_QUEUE_ESTIMATE_CHANGE_THRESHOLD"` // in epochs
+		Telegram                                      struct {
+			BotToken    string `yaml:"botToken" envconfig:"NOTIFICATIONS_TELEGRAM_BOT_TOKEN"`
+			BotUsername string `yaml:"botUsername" envconfig:"NOTIFICATIONS_TELEGRAM_BOT_USERNAME"`
+			ApiUrl      string `yaml:"apiUrl" envconfig:"NOTIFICATIONS_TELEGRAM_API_URL"`
+			// secret token of the bot webhook (setWebhook) pointing to /api/v1/telegram/updates, the updates are used to link chats
+			WebhookSecret string `yaml:"webhookSecret" envconfig:"NOTIFICATIONS_TELEGRAM_WEBHOOK_SECRET"`
+		} `yaml:"telegram"`
+		Matrix struct {
+			HomeserverUrl string `yaml:"homeserverUrl" envconfig:"NOTIFICATIONS_MATRIX_HOMESERVER_URL"`
+			AccessToken   string `yaml:"accessToken" envconfig:"NOTIFICATIONS_MATRIX_ACCESS_TOKEN"`
+		} `yaml:"matrix"`
 	} `yaml:"notifications"`
 	RatelimitUpdater struct {
 		Enabled        bool          `yaml:"enabled" envconfig:"RATELIMIT_UPDATER_ENABLED"`
 		UpdateInterval time.Duration `yaml:"updateInterval" envconfig:"RATELIMIT_UPDATER_UPDATE_INTERVAL"`
+		// share of the monthly request limit at which the owner of an api key is notified by email, 0 disables the alerts
+		QuotaAlertThreshold float64 `yaml:"quotaAlertThreshold" envconfig:"RATELIMIT_UPDATER_QUOTA_ALERT_THRESHOLD"`
 	} `yaml:"ratelimitUpdater"`
 	SSVExporter struct {
 		Enabled bool   `yaml:"enabled" envconfig:"SSV_EXPORTER_ENABLED"`
@@ -111,6 +139,33 @@ type Config struct {
 	MevBoostRelayExporter struct {
 		Enabled bool `yaml:"enabled" envconfig:"MEVBOOSTRELAY_EXPORTER_ENABLED"`
 	} `yaml:"mevBoostRelayExporter"`
+	Slasher struct {
+		Enabled bool `yaml:"enabled" envconfig:"SLASHER_ENABLED"`
+		// HistoryLength is the number of epochs the slasher keeps the attestations of the validators for
+		HistoryLength uint64 `yaml:"historyLength" envconfig:"SLASHER_HISTORY_LENGTH"`
+		// Path is the directory the spans of the validators are stored in, without it they are kept in memory which takes
+		// 4 bytes per validator and epoch of the history
+		Path string `yaml:"path" envconfig:"SLASHER_PATH"`
+	} `yaml:"slasher"`
+	MempoolHistory struct {
+		Enabled bool `yaml:"enabled" envconfig:"MEMPOOL_HISTORY_ENABLED"`
+		// DropTimeout is the duration after which a transaction that left the mempool without being mined is flagged as dropped
+		DropTimeout time.Duration `yaml:"dropTimeout" envconfig:"MEMPOOL_HISTORY_DROP_TIMEOUT"`
+		// Retention is the duration the recorded transactions are kept for
+		Retention time.Duration `yaml:"retention" envconfig:"MEMPOOL_HISTORY_RETENTION"`
+	} `yaml:"mempoolHistory"`
+	ValidatorExport struct {
+		S3 struct {
+			Endpoint        string `yaml:"endpoint" envconfig:"VALIDATOR_EXPORT_S3_ENDPOINT"`
+			Bucket          string `yaml:"bucket" envconfig:"VALIDATOR_EXPORT_S3_BUCKET"`
+			AccessKeyId     string `yaml:"accessKeyId" envconfig:"VALIDATOR_EXPORT_S3_ACCESS_KEY_ID"`
+			AccessKeySecret string `yaml:"accessKeySecret" envconfig:"VALIDATOR_EXPORT_S3_ACCESS_KEY_SECRET"`
+		} `yaml:"s3"`
+		// LinkValidity is the duration the signed download links of finished exports are valid for, at most 7 days
+		LinkValidity time.Duration `yaml:"linkValidity" envconfig:"VALIDATOR_EXPORT_LINK_VALIDITY"`
+		// MaxRows is the maximum number of rows (validators times epochs) of a single export
+		MaxRows uint64 `yaml:"maxRows" envconfig:"VALIDATOR_EXPORT_MAX_ROWS"`
+	} `yaml:"validatorExport"`
 	Pprof struct {
 		Enabled bool   `yaml:"enabled" envconfig:"PPROF_ENABLED"`
 		Port    string `yaml:"port" envconfig:"PPROF_PORT"`
@@ -140,6 +195,24 @@ type Chain struct {
 	ElConfig                                  *params.ChainConfig
 	PectraWithdrawalRequestContractAddress    string `yaml:"pectraWithdrawalRequestContractAddress" envconfig:"CHAIN_PECTRA_WITHDRAWAL_REQUEST_CONTRACT_ADDRESS"`
 	PectraConsolidationRequestContractAddress string `yaml:"pectraConsolidationRequestContractAddress" envconfig:"CHAIN_PECTRA_CONSOLIDATION_REQUEST_CONTRACT_ADDRESS"`
+
+	// BlobSchedule are the blob parameters of the chain ordered by activation, if empty they are read from the
+	// blobSchedule of the EL chain config
+	BlobSchedule []*BlobScheduleEntry `yaml:"blobSchedule"`
+	// RollupLabelsPath is the path of the list mapping blob submitter and inbox addresses to rollups, the embedded list
+	// is used on mainnet if it is empty
+	RollupLabelsPath string `yaml:"rollupLabelsPath" envconfig:"CHAIN_ROLLUP_LABELS_PATH"`
+}
+
+// BlobScheduleEntry holds the blob parameters of the fork activated at Timestamp
+type BlobScheduleEntry struct {
+	Fork                  string `yaml:"fork" json:"fork"`
+	Timestamp             uint64 `yaml:"timestamp" json:"timestamp"`
+	Target                uint64 `yaml:"target" json:"target"`
+	Max                   uint64 `yaml:"max" json:"max"`
+	BaseFeeUpdateFraction uint64 `yaml:"baseFeeUpdateFraction" json:"baseFeeUpdateFraction"`
+	// ReservePrice bounds the blob base fee by the execution base fee (EIP-7918), it is active from Osaka on
+	ReservePrice bool `yaml:"reservePrice" json:"reservePrice"`
 }
 
 type Bigtable struct {
@@ -242,6 +315,7 @@ type Frontend struct {
 		VdbAddon10kYearly string `yaml:"vdbAddon10kYearly" envconfig:"FRONTEND_STRIPE_VDB_ADDON_10K_YEARLY"`
 	}
 	RatelimitUpdateInterval              time.Duration `yaml:"ratelimitUpdateInterval" envconfig:"FRONTEND_RATELIMIT_UPDATE_INTERVAL"`
+	RatelimitStore                       string        `yaml:"ratelimitStore" envconfig:"FRONTEND_RATELIMIT_STORE"` // where the ratelimit counters are stored: redis (default), postgres or memory (single replica only)
 	SessionSameSiteNone                  bool          `yaml:"sessionSameSiteNone" envconfig:"FRONTEND_SESSION_SAMESITE_NONE"`
 	SessionSecret                        string        `yaml:"sessionSecret" envconfig:"FRONTEND_SESSION_SECRET"`
 	SessionCookieDomain                  string        `yaml:"sessionCookieDomain" envconfig:"FRONTEND_SESSION_COOKIE_DOMAIN"`
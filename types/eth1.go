@@ -89,3 +89,10 @@ func (e ElConsolidationRequestData) GetTargetValidatorPubkey() ([]byte, error) {
 	}
 	return e[68:116], nil
 }
+
+// Eth1AddressApproval is an active token approval of an address
+type Eth1AddressApproval struct {
+	Approval *Eth1ApprovalIndexed
+	// Verified is true if the approval has been confirmed by the token contract, the value of erc20 approvals is the current allowance then
+	Verified bool
+}
@@ -22,6 +22,70 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ProxyType int32
+
+const (
+	// an implementation emitted by an Upgraded event of EIP-1967
+	ProxyType_EIP1967_PROXY ProxyType = 0
+	// an EIP-1967 proxy with an admin, the admin can not call the implementation
+	ProxyType_TRANSPARENT_PROXY ProxyType = 1
+	// https://eips.ethereum.org/EIPS/eip-1822
+	ProxyType_EIP1822_PROXY ProxyType = 2
+	// an EIP-1967 proxy that delegates to the implementation of a beacon
+	ProxyType_BEACON_PROXY ProxyType = 3
+	// https://eips.ethereum.org/EIPS/eip-1167
+	ProxyType_MINIMAL_PROXY ProxyType = 4
+	// an UpgradeableBeacon of beacon proxies, its implementation is the one of the beacon proxies pointing to it
+	ProxyType_UPGRADEABLE_BEACON ProxyType = 5
+)
+
+// Enum value maps for ProxyType.
+var (
+	ProxyType_name = map[int32]string{
+		0: "EIP1967_PROXY",
+		1: "TRANSPARENT_PROXY",
+		2: "EIP1822_PROXY",
+		3: "BEACON_PROXY",
+		4: "MINIMAL_PROXY",
+		5: "UPGRADEABLE_BEACON",
+	}
+	ProxyType_value = map[string]int32{
+		"EIP1967_PROXY":      0,
+		"TRANSPARENT_PROXY":  1,
+		"EIP1822_PROXY":      2,
+		"BEACON_PROXY":       3,
+		"MINIMAL_PROXY":      4,
+		"UPGRADEABLE_BEACON": 5,
+	}
+)
+
+func (x ProxyType) Enum() *ProxyType {
+	p := new(ProxyType)
+	*p = x
+	return p
+}
+
+func (x ProxyType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProxyType) Descriptor() protoreflect.EnumDescriptor {
+	return file_eth1_proto_enumTypes[0].Descriptor()
+}
+
+func (ProxyType) Type() protoreflect.EnumType {
+	return &file_eth1_proto_enumTypes[0]
+}
+
+func (x ProxyType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProxyType.Descriptor instead.
+func (ProxyType) EnumDescriptor() ([]byte, []int) {
+	return file_eth1_proto_rawDescGZIP(), []int{0}
+}
+
 type StatusType int32
 
 const (
@@ -55,11 +119,11 @@ func (x StatusType) String() string {
 }
 
 func (StatusType) Descriptor() protoreflect.EnumDescriptor {
-	return file_eth1_proto_enumTypes[0].Descriptor()
+	return file_eth1_proto_enumTypes[1].Descriptor()
 }
 
 func (StatusType) Type() protoreflect.EnumType {
-	return &file_eth1_proto_enumTypes[0]
+	return &file_eth1_proto_enumTypes[1]
 }
 
 func (x StatusType) Number() protoreflect.EnumNumber {
@@ -68,7 +132,56 @@ func (x StatusType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use StatusType.Descriptor instead.
 func (StatusType) EnumDescriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{0}
+	return file_eth1_proto_rawDescGZIP(), []int{1}
+}
+
+type ApprovalType int32
+
+const (
+	ApprovalType_ERC20_APPROVAL   ApprovalType = 0
+	ApprovalType_ERC721_APPROVAL  ApprovalType = 1
+	ApprovalType_APPROVAL_FOR_ALL ApprovalType = 2
+)
+
+// Enum value maps for ApprovalType.
+var (
+	ApprovalType_name = map[int32]string{
+		0: "ERC20_APPROVAL",
+		1: "ERC721_APPROVAL",
+		2: "APPROVAL_FOR_ALL",
+	}
+	ApprovalType_value = map[string]int32{
+		"ERC20_APPROVAL":   0,
+		"ERC721_APPROVAL":  1,
+		"APPROVAL_FOR_ALL": 2,
+	}
+)
+
+func (x ApprovalType) Enum() *ApprovalType {
+	p := new(ApprovalType)
+	*p = x
+	return p
+}
+
+func (x ApprovalType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ApprovalType) Descriptor() protoreflect.EnumDescriptor {
+	return file_eth1_proto_enumTypes[2].Descriptor()
+}
+
+func (ApprovalType) Type() protoreflect.EnumType {
+	return &file_eth1_proto_enumTypes[2]
+}
+
+func (x ApprovalType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ApprovalType.Descriptor instead.
+func (ApprovalType) EnumDescriptor() ([]byte, []int) {
+	return file_eth1_proto_rawDescGZIP(), []int{2}
 }
 
 // Eth1Block is stored in the blocks table under <chainID>:<reversePaddedNumber>
@@ -373,10 +486,12 @@ type Eth1Transaction struct {
 	MaxFeePerBlobGas    []byte   `protobuf:"bytes,25,opt,name=max_fee_per_blob_gas,json=maxFeePerBlobGas,proto3" json:"max_fee_per_blob_gas,omitempty"`
 	BlobVersionedHashes [][]byte `protobuf:"bytes,26,rep,name=blob_versioned_hashes,json=blobVersionedHashes,proto3" json:"blob_versioned_hashes,omitempty"`
 	// EIP 4844 receipt
-	BlobGasPrice  []byte `protobuf:"bytes,27,opt,name=blob_gas_price,json=blobGasPrice,proto3" json:"blob_gas_price,omitempty"`
-	BlobGasUsed   uint64 `protobuf:"varint,28,opt,name=blob_gas_used,json=blobGasUsed,proto3" json:"blob_gas_used,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	BlobGasPrice []byte `protobuf:"bytes,27,opt,name=blob_gas_price,json=blobGasPrice,proto3" json:"blob_gas_price,omitempty"`
+	BlobGasUsed  uint64 `protobuf:"varint,28,opt,name=blob_gas_used,json=blobGasUsed,proto3" json:"blob_gas_used,omitempty"`
+	// EIP 7702 transaction
+	AuthorizationList []*Eth1SetCodeAuthorization `protobuf:"bytes,29,rep,name=authorization_list,json=authorizationList,proto3" json:"authorization_list,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Eth1Transaction) Reset() {
@@ -577,6 +692,107 @@ func (x *Eth1Transaction) GetBlobGasUsed() uint64 {
 	return 0
 }
 
+func (x *Eth1Transaction) GetAuthorizationList() []*Eth1SetCodeAuthorization {
+	if x != nil {
+		return x.AuthorizationList
+	}
+	return nil
+}
+
+// https://eips.ethereum.org/EIPS/eip-7702
+type Eth1SetCodeAuthorization struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	ChainId []byte                 `protobuf:"bytes,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	Address []byte                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Nonce   uint64                 `protobuf:"varint,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	YParity uint32                 `protobuf:"varint,4,opt,name=y_parity,json=yParity,proto3" json:"y_parity,omitempty"`
+	R       []byte                 `protobuf:"bytes,5,opt,name=r,proto3" json:"r,omitempty"`
+	S       []byte                 `protobuf:"bytes,6,opt,name=s,proto3" json:"s,omitempty"`
+	// the recovered signer of the authorization, empty if the signature is invalid
+	Authority     []byte `protobuf:"bytes,7,opt,name=authority,proto3" json:"authority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Eth1SetCodeAuthorization) Reset() {
+	*x = Eth1SetCodeAuthorization{}
+	mi := &file_eth1_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Eth1SetCodeAuthorization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Eth1SetCodeAuthorization) ProtoMessage() {}
+
+func (x *Eth1SetCodeAuthorization) ProtoReflect() protoreflect.Message {
+	mi := &file_eth1_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Eth1SetCodeAuthorization.ProtoReflect.Descriptor instead.
+func (*Eth1SetCodeAuthorization) Descriptor() ([]byte, []int) {
+	return file_eth1_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Eth1SetCodeAuthorization) GetChainId() []byte {
+	if x != nil {
+		return x.ChainId
+	}
+	return nil
+}
+
+func (x *Eth1SetCodeAuthorization) GetAddress() []byte {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *Eth1SetCodeAuthorization) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *Eth1SetCodeAuthorization) GetYParity() uint32 {
+	if x != nil {
+		return x.YParity
+	}
+	return 0
+}
+
+func (x *Eth1SetCodeAuthorization) GetR() []byte {
+	if x != nil {
+		return x.R
+	}
+	return nil
+}
+
+func (x *Eth1SetCodeAuthorization) GetS() []byte {
+	if x != nil {
+		return x.S
+	}
+	return nil
+}
+
+func (x *Eth1SetCodeAuthorization) GetAuthority() []byte {
+	if x != nil {
+		return x.Authority
+	}
+	return nil
+}
+
 type IsContractUpdate struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IsContract    bool                   `protobuf:"varint,1,opt,name=is_contract,json=isContract,proto3" json:"is_contract,omitempty"`
@@ -587,7 +803,7 @@ type IsContractUpdate struct {
 
 func (x *IsContractUpdate) Reset() {
 	*x = IsContractUpdate{}
-	mi := &file_eth1_proto_msgTypes[3]
+	mi := &file_eth1_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -599,7 +815,7 @@ func (x *IsContractUpdate) String() string {
 func (*IsContractUpdate) ProtoMessage() {}
 
 func (x *IsContractUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[3]
+	mi := &file_eth1_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -612,7 +828,7 @@ func (x *IsContractUpdate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IsContractUpdate.ProtoReflect.Descriptor instead.
 func (*IsContractUpdate) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{3}
+	return file_eth1_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *IsContractUpdate) GetIsContract() bool {
@@ -629,6 +845,101 @@ func (x *IsContractUpdate) GetSuccess() bool {
 	return false
 }
 
+type Eth1ProxyImplementation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the deployment or upgrade transaction
+	ParentHash     []byte                 `protobuf:"bytes,1,opt,name=parent_hash,json=parentHash,proto3" json:"parent_hash,omitempty"`
+	BlockNumber    uint64                 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Time           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Type           ProxyType              `protobuf:"varint,4,opt,name=type,proto3,enum=types.ProxyType" json:"type,omitempty"`
+	Implementation []byte                 `protobuf:"bytes,5,opt,name=implementation,proto3" json:"implementation,omitempty"`
+	// the beacon of beacon proxies, the implementation is the one of the beacon at the time of the update
+	Beacon []byte `protobuf:"bytes,6,opt,name=beacon,proto3" json:"beacon,omitempty"`
+	// the admin of transparent proxies
+	Admin         []byte `protobuf:"bytes,7,opt,name=admin,proto3" json:"admin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Eth1ProxyImplementation) Reset() {
+	*x = Eth1ProxyImplementation{}
+	mi := &file_eth1_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Eth1ProxyImplementation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Eth1ProxyImplementation) ProtoMessage() {}
+
+func (x *Eth1ProxyImplementation) ProtoReflect() protoreflect.Message {
+	mi := &file_eth1_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Eth1ProxyImplementation.ProtoReflect.Descriptor instead.
+func (*Eth1ProxyImplementation) Descriptor() ([]byte, []int) {
+	return file_eth1_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Eth1ProxyImplementation) GetParentHash() []byte {
+	if x != nil {
+		return x.ParentHash
+	}
+	return nil
+}
+
+func (x *Eth1ProxyImplementation) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Eth1ProxyImplementation) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Eth1ProxyImplementation) GetType() ProxyType {
+	if x != nil {
+		return x.Type
+	}
+	return ProxyType_EIP1967_PROXY
+}
+
+func (x *Eth1ProxyImplementation) GetImplementation() []byte {
+	if x != nil {
+		return x.Implementation
+	}
+	return nil
+}
+
+func (x *Eth1ProxyImplementation) GetBeacon() []byte {
+	if x != nil {
+		return x.Beacon
+	}
+	return nil
+}
+
+func (x *Eth1ProxyImplementation) GetAdmin() []byte {
+	if x != nil {
+		return x.Admin
+	}
+	return nil
+}
+
 type AccessList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Address       []byte                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
@@ -639,7 +950,7 @@ type AccessList struct {
 
 func (x *AccessList) Reset() {
 	*x = AccessList{}
-	mi := &file_eth1_proto_msgTypes[4]
+	mi := &file_eth1_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -651,7 +962,7 @@ func (x *AccessList) String() string {
 func (*AccessList) ProtoMessage() {}
 
 func (x *AccessList) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[4]
+	mi := &file_eth1_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -664,7 +975,7 @@ func (x *AccessList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AccessList.ProtoReflect.Descriptor instead.
 func (*AccessList) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{4}
+	return file_eth1_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *AccessList) GetAddress() []byte {
@@ -693,7 +1004,7 @@ type Eth1Log struct {
 
 func (x *Eth1Log) Reset() {
 	*x = Eth1Log{}
-	mi := &file_eth1_proto_msgTypes[5]
+	mi := &file_eth1_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -705,7 +1016,7 @@ func (x *Eth1Log) String() string {
 func (*Eth1Log) ProtoMessage() {}
 
 func (x *Eth1Log) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[5]
+	mi := &file_eth1_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -718,7 +1029,7 @@ func (x *Eth1Log) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1Log.ProtoReflect.Descriptor instead.
 func (*Eth1Log) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{5}
+	return file_eth1_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Eth1Log) GetAddress() []byte {
@@ -763,7 +1074,7 @@ type Eth1InternalTransaction struct {
 
 func (x *Eth1InternalTransaction) Reset() {
 	*x = Eth1InternalTransaction{}
-	mi := &file_eth1_proto_msgTypes[6]
+	mi := &file_eth1_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -775,7 +1086,7 @@ func (x *Eth1InternalTransaction) String() string {
 func (*Eth1InternalTransaction) ProtoMessage() {}
 
 func (x *Eth1InternalTransaction) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[6]
+	mi := &file_eth1_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -788,7 +1099,7 @@ func (x *Eth1InternalTransaction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1InternalTransaction.ProtoReflect.Descriptor instead.
 func (*Eth1InternalTransaction) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{6}
+	return file_eth1_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Eth1InternalTransaction) GetType() string {
@@ -866,7 +1177,7 @@ type Eth1BlockIndexed struct {
 
 func (x *Eth1BlockIndexed) Reset() {
 	*x = Eth1BlockIndexed{}
-	mi := &file_eth1_proto_msgTypes[7]
+	mi := &file_eth1_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -878,7 +1189,7 @@ func (x *Eth1BlockIndexed) String() string {
 func (*Eth1BlockIndexed) ProtoMessage() {}
 
 func (x *Eth1BlockIndexed) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[7]
+	mi := &file_eth1_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -891,7 +1202,7 @@ func (x *Eth1BlockIndexed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1BlockIndexed.ProtoReflect.Descriptor instead.
 func (*Eth1BlockIndexed) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{7}
+	return file_eth1_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Eth1BlockIndexed) GetHash() []byte {
@@ -1057,7 +1368,7 @@ type Eth1UncleIndexed struct {
 
 func (x *Eth1UncleIndexed) Reset() {
 	*x = Eth1UncleIndexed{}
-	mi := &file_eth1_proto_msgTypes[8]
+	mi := &file_eth1_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1069,7 +1380,7 @@ func (x *Eth1UncleIndexed) String() string {
 func (*Eth1UncleIndexed) ProtoMessage() {}
 
 func (x *Eth1UncleIndexed) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[8]
+	mi := &file_eth1_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1082,7 +1393,7 @@ func (x *Eth1UncleIndexed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1UncleIndexed.ProtoReflect.Descriptor instead.
 func (*Eth1UncleIndexed) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{8}
+	return file_eth1_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Eth1UncleIndexed) GetBlockNumber() uint64 {
@@ -1155,7 +1466,7 @@ type Eth1WithdrawalIndexed struct {
 
 func (x *Eth1WithdrawalIndexed) Reset() {
 	*x = Eth1WithdrawalIndexed{}
-	mi := &file_eth1_proto_msgTypes[9]
+	mi := &file_eth1_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1167,7 +1478,7 @@ func (x *Eth1WithdrawalIndexed) String() string {
 func (*Eth1WithdrawalIndexed) ProtoMessage() {}
 
 func (x *Eth1WithdrawalIndexed) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[9]
+	mi := &file_eth1_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1180,7 +1491,7 @@ func (x *Eth1WithdrawalIndexed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1WithdrawalIndexed.ProtoReflect.Descriptor instead.
 func (*Eth1WithdrawalIndexed) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{9}
+	return file_eth1_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Eth1WithdrawalIndexed) GetBlockNumber() uint64 {
@@ -1248,7 +1559,7 @@ type Eth1TransactionIndexed struct {
 
 func (x *Eth1TransactionIndexed) Reset() {
 	*x = Eth1TransactionIndexed{}
-	mi := &file_eth1_proto_msgTypes[10]
+	mi := &file_eth1_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1260,7 +1571,7 @@ func (x *Eth1TransactionIndexed) String() string {
 func (*Eth1TransactionIndexed) ProtoMessage() {}
 
 func (x *Eth1TransactionIndexed) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[10]
+	mi := &file_eth1_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1273,7 +1584,7 @@ func (x *Eth1TransactionIndexed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1TransactionIndexed.ProtoReflect.Descriptor instead.
 func (*Eth1TransactionIndexed) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{10}
+	return file_eth1_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Eth1TransactionIndexed) GetHash() []byte {
@@ -1390,7 +1701,7 @@ type Eth1InternalTransactionIndexed struct {
 
 func (x *Eth1InternalTransactionIndexed) Reset() {
 	*x = Eth1InternalTransactionIndexed{}
-	mi := &file_eth1_proto_msgTypes[11]
+	mi := &file_eth1_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1402,7 +1713,7 @@ func (x *Eth1InternalTransactionIndexed) String() string {
 func (*Eth1InternalTransactionIndexed) ProtoMessage() {}
 
 func (x *Eth1InternalTransactionIndexed) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[11]
+	mi := &file_eth1_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1415,7 +1726,7 @@ func (x *Eth1InternalTransactionIndexed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1InternalTransactionIndexed.ProtoReflect.Descriptor instead.
 func (*Eth1InternalTransactionIndexed) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{11}
+	return file_eth1_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *Eth1InternalTransactionIndexed) GetParentHash() []byte {
@@ -1495,7 +1806,7 @@ type Eth1BlobTransactionIndexed struct {
 
 func (x *Eth1BlobTransactionIndexed) Reset() {
 	*x = Eth1BlobTransactionIndexed{}
-	mi := &file_eth1_proto_msgTypes[12]
+	mi := &file_eth1_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1507,7 +1818,7 @@ func (x *Eth1BlobTransactionIndexed) String() string {
 func (*Eth1BlobTransactionIndexed) ProtoMessage() {}
 
 func (x *Eth1BlobTransactionIndexed) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[12]
+	mi := &file_eth1_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1520,7 +1831,7 @@ func (x *Eth1BlobTransactionIndexed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1BlobTransactionIndexed.ProtoReflect.Descriptor instead.
 func (*Eth1BlobTransactionIndexed) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{12}
+	return file_eth1_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *Eth1BlobTransactionIndexed) GetHash() []byte {
@@ -1607,8 +1918,264 @@ func (x *Eth1BlobTransactionIndexed) GetBlobVersionedHashes() [][]byte {
 	return nil
 }
 
-type Eth1ERC20Indexed struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+// https://eips.ethereum.org/EIPS/eip-7702
+type Eth1SetCodeAuthorizationIndexed struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ParentHash  []byte                 `protobuf:"bytes,1,opt,name=parent_hash,json=parentHash,proto3" json:"parent_hash,omitempty"`
+	BlockNumber uint64                 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Time        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Authority   []byte                 `protobuf:"bytes,4,opt,name=authority,proto3" json:"authority,omitempty"`
+	// the address the authority delegates to, the zero address clears the delegation
+	Address []byte `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	ChainId []byte `protobuf:"bytes,6,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	Nonce   uint64 `protobuf:"varint,7,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// whether the signature and the chain id of the authorization are valid, the nonce is not checked against the state
+	Valid         bool `protobuf:"varint,8,opt,name=valid,proto3" json:"valid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) Reset() {
+	*x = Eth1SetCodeAuthorizationIndexed{}
+	mi := &file_eth1_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Eth1SetCodeAuthorizationIndexed) ProtoMessage() {}
+
+func (x *Eth1SetCodeAuthorizationIndexed) ProtoReflect() protoreflect.Message {
+	mi := &file_eth1_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Eth1SetCodeAuthorizationIndexed.ProtoReflect.Descriptor instead.
+func (*Eth1SetCodeAuthorizationIndexed) Descriptor() ([]byte, []int) {
+	return file_eth1_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) GetParentHash() []byte {
+	if x != nil {
+		return x.ParentHash
+	}
+	return nil
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) GetAuthority() []byte {
+	if x != nil {
+		return x.Authority
+	}
+	return nil
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) GetAddress() []byte {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) GetChainId() []byte {
+	if x != nil {
+		return x.ChainId
+	}
+	return nil
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *Eth1SetCodeAuthorizationIndexed) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+// https://eips.ethereum.org/EIPS/eip-4337
+type Eth1UserOperationIndexed struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ParentHash  []byte                 `protobuf:"bytes,1,opt,name=parent_hash,json=parentHash,proto3" json:"parent_hash,omitempty"`
+	BlockNumber uint64                 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Time        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	UserOpHash  []byte                 `protobuf:"bytes,4,opt,name=user_op_hash,json=userOpHash,proto3" json:"user_op_hash,omitempty"`
+	EntryPoint  []byte                 `protobuf:"bytes,5,opt,name=entry_point,json=entryPoint,proto3" json:"entry_point,omitempty"`
+	Sender      []byte                 `protobuf:"bytes,6,opt,name=sender,proto3" json:"sender,omitempty"`
+	// the zero address if the operation has been paid by the sender
+	Paymaster     []byte `protobuf:"bytes,7,opt,name=paymaster,proto3" json:"paymaster,omitempty"`
+	Nonce         []byte `protobuf:"bytes,8,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Success       bool   `protobuf:"varint,9,opt,name=success,proto3" json:"success,omitempty"`
+	ActualGasCost []byte `protobuf:"bytes,10,opt,name=actual_gas_cost,json=actualGasCost,proto3" json:"actual_gas_cost,omitempty"`
+	ActualGasUsed []byte `protobuf:"bytes,11,opt,name=actual_gas_used,json=actualGasUsed,proto3" json:"actual_gas_used,omitempty"`
+	// the account that submitted the bundle
+	Bundler []byte `protobuf:"bytes,12,opt,name=bundler,proto3" json:"bundler,omitempty"`
+	// the factory that deployed the sender with this operation, decoded from the handleOps calldata
+	Factory []byte `protobuf:"bytes,13,opt,name=factory,proto3" json:"factory,omitempty"`
+	// the method of the call to the sender, decoded from the handleOps calldata
+	MethodId      []byte `protobuf:"bytes,14,opt,name=method_id,json=methodId,proto3" json:"method_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Eth1UserOperationIndexed) Reset() {
+	*x = Eth1UserOperationIndexed{}
+	mi := &file_eth1_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Eth1UserOperationIndexed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Eth1UserOperationIndexed) ProtoMessage() {}
+
+func (x *Eth1UserOperationIndexed) ProtoReflect() protoreflect.Message {
+	mi := &file_eth1_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Eth1UserOperationIndexed.ProtoReflect.Descriptor instead.
+func (*Eth1UserOperationIndexed) Descriptor() ([]byte, []int) {
+	return file_eth1_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Eth1UserOperationIndexed) GetParentHash() []byte {
+	if x != nil {
+		return x.ParentHash
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Eth1UserOperationIndexed) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetUserOpHash() []byte {
+	if x != nil {
+		return x.UserOpHash
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetEntryPoint() []byte {
+	if x != nil {
+		return x.EntryPoint
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetSender() []byte {
+	if x != nil {
+		return x.Sender
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetPaymaster() []byte {
+	if x != nil {
+		return x.Paymaster
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *Eth1UserOperationIndexed) GetActualGasCost() []byte {
+	if x != nil {
+		return x.ActualGasCost
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetActualGasUsed() []byte {
+	if x != nil {
+		return x.ActualGasUsed
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetBundler() []byte {
+	if x != nil {
+		return x.Bundler
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetFactory() []byte {
+	if x != nil {
+		return x.Factory
+	}
+	return nil
+}
+
+func (x *Eth1UserOperationIndexed) GetMethodId() []byte {
+	if x != nil {
+		return x.MethodId
+	}
+	return nil
+}
+
+type Eth1ERC20Indexed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	ParentHash    []byte                 `protobuf:"bytes,1,opt,name=parent_hash,json=parentHash,proto3" json:"parent_hash,omitempty"`
 	BlockNumber   uint64                 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
 	TokenAddress  []byte                 `protobuf:"bytes,3,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
@@ -1622,7 +2189,7 @@ type Eth1ERC20Indexed struct {
 
 func (x *Eth1ERC20Indexed) Reset() {
 	*x = Eth1ERC20Indexed{}
-	mi := &file_eth1_proto_msgTypes[13]
+	mi := &file_eth1_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1634,7 +2201,7 @@ func (x *Eth1ERC20Indexed) String() string {
 func (*Eth1ERC20Indexed) ProtoMessage() {}
 
 func (x *Eth1ERC20Indexed) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[13]
+	mi := &file_eth1_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1647,7 +2214,7 @@ func (x *Eth1ERC20Indexed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1ERC20Indexed.ProtoReflect.Descriptor instead.
 func (*Eth1ERC20Indexed) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{13}
+	return file_eth1_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *Eth1ERC20Indexed) GetParentHash() []byte {
@@ -1714,7 +2281,7 @@ type Eth1ERC721Indexed struct {
 
 func (x *Eth1ERC721Indexed) Reset() {
 	*x = Eth1ERC721Indexed{}
-	mi := &file_eth1_proto_msgTypes[14]
+	mi := &file_eth1_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1726,7 +2293,7 @@ func (x *Eth1ERC721Indexed) String() string {
 func (*Eth1ERC721Indexed) ProtoMessage() {}
 
 func (x *Eth1ERC721Indexed) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[14]
+	mi := &file_eth1_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1739,7 +2306,7 @@ func (x *Eth1ERC721Indexed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Eth1ERC721Indexed.ProtoReflect.Descriptor instead.
 func (*Eth1ERC721Indexed) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{14}
+	return file_eth1_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *Eth1ERC721Indexed) GetParentHash() []byte {
@@ -1791,6 +2358,126 @@ func (x *Eth1ERC721Indexed) GetTokenId() []byte {
 	return nil
 }
 
+type Eth1ApprovalIndexed struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ParentHash   []byte                 `protobuf:"bytes,1,opt,name=parent_hash,json=parentHash,proto3" json:"parent_hash,omitempty"`
+	BlockNumber  uint64                 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	TokenAddress []byte                 `protobuf:"bytes,3,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	Time         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	Type         ApprovalType           `protobuf:"varint,5,opt,name=type,proto3,enum=types.ApprovalType" json:"type,omitempty"`
+	Owner        []byte                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	// the spender of erc20 and erc721 approvals or the operator of approvals for all
+	Spender []byte `protobuf:"bytes,7,opt,name=spender,proto3" json:"spender,omitempty"`
+	// the allowance of erc20 approvals
+	Value []byte `protobuf:"bytes,8,opt,name=value,proto3" json:"value,omitempty"`
+	// the approved token of erc721 approvals
+	TokenId []byte `protobuf:"bytes,9,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	// whether the operator of an approval for all has been approved or revoked
+	Approved      bool `protobuf:"varint,10,opt,name=approved,proto3" json:"approved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Eth1ApprovalIndexed) Reset() {
+	*x = Eth1ApprovalIndexed{}
+	mi := &file_eth1_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Eth1ApprovalIndexed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Eth1ApprovalIndexed) ProtoMessage() {}
+
+func (x *Eth1ApprovalIndexed) ProtoReflect() protoreflect.Message {
+	mi := &file_eth1_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Eth1ApprovalIndexed.ProtoReflect.Descriptor instead.
+func (*Eth1ApprovalIndexed) Descriptor() ([]byte, []int) {
+	return file_eth1_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Eth1ApprovalIndexed) GetParentHash() []byte {
+	if x != nil {
+		return x.ParentHash
+	}
+	return nil
+}
+
+func (x *Eth1ApprovalIndexed) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Eth1ApprovalIndexed) GetTokenAddress() []byte {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return nil
+}
+
+func (x *Eth1ApprovalIndexed) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Eth1ApprovalIndexed) GetType() ApprovalType {
+	if x != nil {
+		return x.Type
+	}
+	return ApprovalType_ERC20_APPROVAL
+}
+
+func (x *Eth1ApprovalIndexed) GetOwner() []byte {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *Eth1ApprovalIndexed) GetSpender() []byte {
+	if x != nil {
+		return x.Spender
+	}
+	return nil
+}
+
+func (x *Eth1ApprovalIndexed) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Eth1ApprovalIndexed) GetTokenId() []byte {
+	if x != nil {
+		return x.TokenId
+	}
+	return nil
+}
+
+func (x *Eth1ApprovalIndexed) GetApproved() bool {
+	if x != nil {
+		return x.Approved
+	}
+	return false
+}
+
 // https://eips.ethereum.org/EIPS/eip-1155
 type ETh1ERC1155Indexed struct {
 	state        protoimpl.MessageState `protogen:"open.v1"`
@@ -1810,7 +2497,7 @@ type ETh1ERC1155Indexed struct {
 
 func (x *ETh1ERC1155Indexed) Reset() {
 	*x = ETh1ERC1155Indexed{}
-	mi := &file_eth1_proto_msgTypes[15]
+	mi := &file_eth1_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1822,7 +2509,7 @@ func (x *ETh1ERC1155Indexed) String() string {
 func (*ETh1ERC1155Indexed) ProtoMessage() {}
 
 func (x *ETh1ERC1155Indexed) ProtoReflect() protoreflect.Message {
-	mi := &file_eth1_proto_msgTypes[15]
+	mi := &file_eth1_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1835,7 +2522,7 @@ func (x *ETh1ERC1155Indexed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ETh1ERC1155Indexed.ProtoReflect.Descriptor instead.
 func (*ETh1ERC1155Indexed) Descriptor() ([]byte, []int) {
-	return file_eth1_proto_rawDescGZIP(), []int{15}
+	return file_eth1_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *ETh1ERC1155Indexed) GetParentHash() []byte {
@@ -1939,7 +2626,7 @@ const file_eth1_proto_rawDesc = "" +
 	"\x05index\x18\x01 \x01(\x04R\x05index\x12'\n" +
 	"\x0fvalidator_index\x18\x02 \x01(\x04R\x0evalidatorIndex\x12\x18\n" +
 	"\aaddress\x18\x03 \x01(\fR\aaddress\x12\x16\n" +
-	"\x06amount\x18\x04 \x01(\fR\x06amount\"\xb3\x06\n" +
+	"\x06amount\x18\x04 \x01(\fR\x06amount\"\x83\a\n" +
 	"\x0fEth1Transaction\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\rR\x04type\x12\x14\n" +
 	"\x05nonce\x18\x02 \x01(\x04R\x05nonce\x12\x1b\n" +
@@ -1966,11 +2653,29 @@ const file_eth1_proto_rawDesc = "" +
 	"\x14max_fee_per_blob_gas\x18\x19 \x01(\fR\x10maxFeePerBlobGas\x122\n" +
 	"\x15blob_versioned_hashes\x18\x1a \x03(\fR\x13blobVersionedHashes\x12$\n" +
 	"\x0eblob_gas_price\x18\x1b \x01(\fR\fblobGasPrice\x12\"\n" +
-	"\rblob_gas_used\x18\x1c \x01(\x04R\vblobGasUsedJ\x04\b\x16\x10\x17\"M\n" +
+	"\rblob_gas_used\x18\x1c \x01(\x04R\vblobGasUsed\x12N\n" +
+	"\x12authorization_list\x18\x1d \x03(\v2\x1f.types.Eth1SetCodeAuthorizationR\x11authorizationListJ\x04\b\x16\x10\x17\"\xba\x01\n" +
+	"\x18Eth1SetCodeAuthorization\x12\x19\n" +
+	"\bchain_id\x18\x01 \x01(\fR\achainId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\fR\aaddress\x12\x14\n" +
+	"\x05nonce\x18\x03 \x01(\x04R\x05nonce\x12\x19\n" +
+	"\by_parity\x18\x04 \x01(\rR\ayParity\x12\f\n" +
+	"\x01r\x18\x05 \x01(\fR\x01r\x12\f\n" +
+	"\x01s\x18\x06 \x01(\fR\x01s\x12\x1c\n" +
+	"\tauthority\x18\a \x01(\fR\tauthority\"M\n" +
 	"\x10IsContractUpdate\x12\x1f\n" +
 	"\vis_contract\x18\x01 \x01(\bR\n" +
 	"isContract\x12\x18\n" +
-	"\asuccess\x18\x02 \x01(\bR\asuccess\"I\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\"\x89\x02\n" +
+	"\x17Eth1ProxyImplementation\x12\x1f\n" +
+	"\vparent_hash\x18\x01 \x01(\fR\n" +
+	"parentHash\x12!\n" +
+	"\fblock_number\x18\x02 \x01(\x04R\vblockNumber\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12$\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x10.types.ProxyTypeR\x04type\x12&\n" +
+	"\x0eimplementation\x18\x05 \x01(\fR\x0eimplementation\x12\x16\n" +
+	"\x06beacon\x18\x06 \x01(\fR\x06beacon\x12\x14\n" +
+	"\x05admin\x18\a \x01(\fR\x05admin\"I\n" +
 	"\n" +
 	"AccessList\x12\x18\n" +
 	"\aaddress\x18\x01 \x01(\fR\aaddress\x12!\n" +
@@ -2072,7 +2777,36 @@ const file_eth1_proto_rawDesc = "" +
 	"\x0eblob_gas_price\x18\n" +
 	" \x01(\fR\fblobGasPrice\x12\x1b\n" +
 	"\terror_msg\x18\f \x01(\tR\berrorMsg\x122\n" +
-	"\x15blob_versioned_hashes\x18\r \x03(\fR\x13blobVersionedHashesJ\x04\b\v\x10\f\"\xe5\x01\n" +
+	"\x15blob_versioned_hashes\x18\r \x03(\fR\x13blobVersionedHashesJ\x04\b\v\x10\f\"\x94\x02\n" +
+	"\x1fEth1SetCodeAuthorizationIndexed\x12\x1f\n" +
+	"\vparent_hash\x18\x01 \x01(\fR\n" +
+	"parentHash\x12!\n" +
+	"\fblock_number\x18\x02 \x01(\x04R\vblockNumber\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x1c\n" +
+	"\tauthority\x18\x04 \x01(\fR\tauthority\x12\x18\n" +
+	"\aaddress\x18\x05 \x01(\fR\aaddress\x12\x19\n" +
+	"\bchain_id\x18\x06 \x01(\fR\achainId\x12\x14\n" +
+	"\x05nonce\x18\a \x01(\x04R\x05nonce\x12\x14\n" +
+	"\x05valid\x18\b \x01(\bR\x05valid\"\xd8\x03\n" +
+	"\x18Eth1UserOperationIndexed\x12\x1f\n" +
+	"\vparent_hash\x18\x01 \x01(\fR\n" +
+	"parentHash\x12!\n" +
+	"\fblock_number\x18\x02 \x01(\x04R\vblockNumber\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12 \n" +
+	"\fuser_op_hash\x18\x04 \x01(\fR\n" +
+	"userOpHash\x12\x1f\n" +
+	"\ventry_point\x18\x05 \x01(\fR\n" +
+	"entryPoint\x12\x16\n" +
+	"\x06sender\x18\x06 \x01(\fR\x06sender\x12\x1c\n" +
+	"\tpaymaster\x18\a \x01(\fR\tpaymaster\x12\x14\n" +
+	"\x05nonce\x18\b \x01(\fR\x05nonce\x12\x18\n" +
+	"\asuccess\x18\t \x01(\bR\asuccess\x12&\n" +
+	"\x0factual_gas_cost\x18\n" +
+	" \x01(\fR\ractualGasCost\x12&\n" +
+	"\x0factual_gas_used\x18\v \x01(\fR\ractualGasUsed\x12\x18\n" +
+	"\abundler\x18\f \x01(\fR\abundler\x12\x18\n" +
+	"\afactory\x18\r \x01(\fR\afactory\x12\x1b\n" +
+	"\tmethod_id\x18\x0e \x01(\fR\bmethodId\"\xe5\x01\n" +
 	"\x10Eth1ERC20Indexed\x12\x1f\n" +
 	"\vparent_hash\x18\x01 \x01(\fR\n" +
 	"parentHash\x12!\n" +
@@ -2090,7 +2824,20 @@ const file_eth1_proto_rawDesc = "" +
 	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x12\n" +
 	"\x04from\x18\x05 \x01(\fR\x04from\x12\x0e\n" +
 	"\x02to\x18\x06 \x01(\fR\x02to\x12\x19\n" +
-	"\btoken_id\x18\a \x01(\fR\atokenId\"\x9e\x02\n" +
+	"\btoken_id\x18\a \x01(\fR\atokenId\"\xd4\x02\n" +
+	"\x13Eth1ApprovalIndexed\x12\x1f\n" +
+	"\vparent_hash\x18\x01 \x01(\fR\n" +
+	"parentHash\x12!\n" +
+	"\fblock_number\x18\x02 \x01(\x04R\vblockNumber\x12#\n" +
+	"\rtoken_address\x18\x03 \x01(\fR\ftokenAddress\x12.\n" +
+	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12'\n" +
+	"\x04type\x18\x05 \x01(\x0e2\x13.types.ApprovalTypeR\x04type\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\fR\x05owner\x12\x18\n" +
+	"\aspender\x18\a \x01(\fR\aspender\x12\x14\n" +
+	"\x05value\x18\b \x01(\fR\x05value\x12\x19\n" +
+	"\btoken_id\x18\t \x01(\fR\atokenId\x12\x1a\n" +
+	"\bapproved\x18\n" +
+	" \x01(\bR\bapproved\"\x9e\x02\n" +
 	"\x12ETh1ERC1155Indexed\x12\x1f\n" +
 	"\vparent_hash\x18\x01 \x01(\fR\n" +
 	"parentHash\x12!\n" +
@@ -2101,13 +2848,24 @@ const file_eth1_proto_rawDesc = "" +
 	"\x02to\x18\x06 \x01(\fR\x02to\x12\x19\n" +
 	"\btoken_id\x18\a \x01(\fR\atokenId\x12\x14\n" +
 	"\x05value\x18\b \x01(\fR\x05value\x12\x1a\n" +
-	"\boperator\x18\t \x01(\fR\boperator*2\n" +
+	"\boperator\x18\t \x01(\fR\boperator*\x85\x01\n" +
+	"\tProxyType\x12\x11\n" +
+	"\rEIP1967_PROXY\x10\x00\x12\x15\n" +
+	"\x11TRANSPARENT_PROXY\x10\x01\x12\x11\n" +
+	"\rEIP1822_PROXY\x10\x02\x12\x10\n" +
+	"\fBEACON_PROXY\x10\x03\x12\x11\n" +
+	"\rMINIMAL_PROXY\x10\x04\x12\x16\n" +
+	"\x12UPGRADEABLE_BEACON\x10\x05*2\n" +
 	"\n" +
 	"StatusType\x12\n" +
 	"\n" +
 	"\x06FAILED\x10\x00\x12\v\n" +
 	"\aSUCCESS\x10\x01\x12\v\n" +
-	"\aPARTIAL\x10\x02B\tZ\a./typesb\x06proto3"
+	"\aPARTIAL\x10\x02*M\n" +
+	"\fApprovalType\x12\x12\n" +
+	"\x0eERC20_APPROVAL\x10\x00\x12\x13\n" +
+	"\x0fERC721_APPROVAL\x10\x01\x12\x14\n" +
+	"\x10APPROVAL_FOR_ALL\x10\x02B\tZ\a./typesb\x06proto3"
 
 var (
 	file_eth1_proto_rawDescOnce sync.Once
@@ -2121,51 +2879,65 @@ func file_eth1_proto_rawDescGZIP() []byte {
 	return file_eth1_proto_rawDescData
 }
 
-var file_eth1_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_eth1_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_eth1_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_eth1_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_eth1_proto_goTypes = []any{
-	(StatusType)(0),                        // 0: types.StatusType
-	(*Eth1Block)(nil),                      // 1: types.Eth1Block
-	(*Eth1Withdrawal)(nil),                 // 2: types.Eth1Withdrawal
-	(*Eth1Transaction)(nil),                // 3: types.Eth1Transaction
-	(*IsContractUpdate)(nil),               // 4: types.IsContractUpdate
-	(*AccessList)(nil),                     // 5: types.AccessList
-	(*Eth1Log)(nil),                        // 6: types.Eth1Log
-	(*Eth1InternalTransaction)(nil),        // 7: types.Eth1InternalTransaction
-	(*Eth1BlockIndexed)(nil),               // 8: types.Eth1BlockIndexed
-	(*Eth1UncleIndexed)(nil),               // 9: types.Eth1UncleIndexed
-	(*Eth1WithdrawalIndexed)(nil),          // 10: types.Eth1WithdrawalIndexed
-	(*Eth1TransactionIndexed)(nil),         // 11: types.Eth1TransactionIndexed
-	(*Eth1InternalTransactionIndexed)(nil), // 12: types.Eth1InternalTransactionIndexed
-	(*Eth1BlobTransactionIndexed)(nil),     // 13: types.Eth1BlobTransactionIndexed
-	(*Eth1ERC20Indexed)(nil),               // 14: types.Eth1ERC20Indexed
-	(*Eth1ERC721Indexed)(nil),              // 15: types.Eth1ERC721Indexed
-	(*ETh1ERC1155Indexed)(nil),             // 16: types.ETh1ERC1155Indexed
-	(*timestamppb.Timestamp)(nil),          // 17: google.protobuf.Timestamp
+	(ProxyType)(0),                          // 0: types.ProxyType
+	(StatusType)(0),                         // 1: types.StatusType
+	(ApprovalType)(0),                       // 2: types.ApprovalType
+	(*Eth1Block)(nil),                       // 3: types.Eth1Block
+	(*Eth1Withdrawal)(nil),                  // 4: types.Eth1Withdrawal
+	(*Eth1Transaction)(nil),                 // 5: types.Eth1Transaction
+	(*Eth1SetCodeAuthorization)(nil),        // 6: types.Eth1SetCodeAuthorization
+	(*IsContractUpdate)(nil),                // 7: types.IsContractUpdate
+	(*Eth1ProxyImplementation)(nil),         // 8: types.Eth1ProxyImplementation
+	(*AccessList)(nil),                      // 9: types.AccessList
+	(*Eth1Log)(nil),                         // 10: types.Eth1Log
+	(*Eth1InternalTransaction)(nil),         // 11: types.Eth1InternalTransaction
+	(*Eth1BlockIndexed)(nil),                // 12: types.Eth1BlockIndexed
+	(*Eth1UncleIndexed)(nil),                // 13: types.Eth1UncleIndexed
+	(*Eth1WithdrawalIndexed)(nil),           // 14: types.Eth1WithdrawalIndexed
+	(*Eth1TransactionIndexed)(nil),          // 15: types.Eth1TransactionIndexed
+	(*Eth1InternalTransactionIndexed)(nil),  // 16: types.Eth1InternalTransactionIndexed
+	(*Eth1BlobTransactionIndexed)(nil),      // 17: types.Eth1BlobTransactionIndexed
+	(*Eth1SetCodeAuthorizationIndexed)(nil), // 18: types.Eth1SetCodeAuthorizationIndexed
+	(*Eth1UserOperationIndexed)(nil),        // 19: types.Eth1UserOperationIndexed
+	(*Eth1ERC20Indexed)(nil),                // 20: types.Eth1ERC20Indexed
+	(*Eth1ERC721Indexed)(nil),               // 21: types.Eth1ERC721Indexed
+	(*Eth1ApprovalIndexed)(nil),             // 22: types.Eth1ApprovalIndexed
+	(*ETh1ERC1155Indexed)(nil),              // 23: types.ETh1ERC1155Indexed
+	(*timestamppb.Timestamp)(nil),           // 24: google.protobuf.Timestamp
 }
 var file_eth1_proto_depIdxs = []int32{
-	17, // 0: types.Eth1Block.time:type_name -> google.protobuf.Timestamp
-	1,  // 1: types.Eth1Block.uncles:type_name -> types.Eth1Block
-	3,  // 2: types.Eth1Block.transactions:type_name -> types.Eth1Transaction
-	2,  // 3: types.Eth1Block.withdrawals:type_name -> types.Eth1Withdrawal
-	5,  // 4: types.Eth1Transaction.access_list:type_name -> types.AccessList
-	6,  // 5: types.Eth1Transaction.logs:type_name -> types.Eth1Log
-	7,  // 6: types.Eth1Transaction.itx:type_name -> types.Eth1InternalTransaction
-	17, // 7: types.Eth1BlockIndexed.time:type_name -> google.protobuf.Timestamp
-	17, // 8: types.Eth1UncleIndexed.time:type_name -> google.protobuf.Timestamp
-	17, // 9: types.Eth1WithdrawalIndexed.time:type_name -> google.protobuf.Timestamp
-	17, // 10: types.Eth1TransactionIndexed.time:type_name -> google.protobuf.Timestamp
-	0,  // 11: types.Eth1TransactionIndexed.status:type_name -> types.StatusType
-	17, // 12: types.Eth1InternalTransactionIndexed.time:type_name -> google.protobuf.Timestamp
-	17, // 13: types.Eth1BlobTransactionIndexed.time:type_name -> google.protobuf.Timestamp
-	17, // 14: types.Eth1ERC20Indexed.time:type_name -> google.protobuf.Timestamp
-	17, // 15: types.Eth1ERC721Indexed.time:type_name -> google.protobuf.Timestamp
-	17, // 16: types.ETh1ERC1155Indexed.time:type_name -> google.protobuf.Timestamp
-	17, // [17:17] is the sub-list for method output_type
-	17, // [17:17] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	24, // 0: types.Eth1Block.time:type_name -> google.protobuf.Timestamp
+	3,  // 1: types.Eth1Block.uncles:type_name -> types.Eth1Block
+	5,  // 2: types.Eth1Block.transactions:type_name -> types.Eth1Transaction
+	4,  // 3: types.Eth1Block.withdrawals:type_name -> types.Eth1Withdrawal
+	9,  // 4: types.Eth1Transaction.access_list:type_name -> types.AccessList
+	10, // 5: types.Eth1Transaction.logs:type_name -> types.Eth1Log
+	11, // 6: types.Eth1Transaction.itx:type_name -> types.Eth1InternalTransaction
+	6,  // 7: types.Eth1Transaction.authorization_list:type_name -> types.Eth1SetCodeAuthorization
+	24, // 8: types.Eth1ProxyImplementation.time:type_name -> google.protobuf.Timestamp
+	0,  // 9: types.Eth1ProxyImplementation.type:type_name -> types.ProxyType
+	24, // 10: types.Eth1BlockIndexed.time:type_name -> google.protobuf.Timestamp
+	24, // 11: types.Eth1UncleIndexed.time:type_name -> google.protobuf.Timestamp
+	24, // 12: types.Eth1WithdrawalIndexed.time:type_name -> google.protobuf.Timestamp
+	24, // 13: types.Eth1TransactionIndexed.time:type_name -> google.protobuf.Timestamp
+	1,  // 14: types.Eth1TransactionIndexed.status:type_name -> types.StatusType
+	24, // 15: types.Eth1InternalTransactionIndexed.time:type_name -> google.protobuf.Timestamp
+	24, // 16: types.Eth1BlobTransactionIndexed.time:type_name -> google.protobuf.Timestamp
+	24, // 17: types.Eth1SetCodeAuthorizationIndexed.time:type_name -> google.protobuf.Timestamp
+	24, // 18: types.Eth1UserOperationIndexed.time:type_name -> google.protobuf.Timestamp
+	24, // 19: types.Eth1ERC20Indexed.time:type_name -> google.protobuf.Timestamp
+	24, // 20: types.Eth1ERC721Indexed.time:type_name -> google.protobuf.Timestamp
+	24, // 21: types.Eth1ApprovalIndexed.time:type_name -> google.protobuf.Timestamp
+	2,  // 22: types.Eth1ApprovalIndexed.type:type_name -> types.ApprovalType
+	24, // 23: types.ETh1ERC1155Indexed.time:type_name -> google.protobuf.Timestamp
+	24, // [24:24] is the sub-list for method output_type
+	24, // [24:24] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_eth1_proto_init() }
@@ -2178,8 +2950,8 @@ func file_eth1_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_eth1_proto_rawDesc), len(file_eth1_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   16,
+			NumEnums:      3,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
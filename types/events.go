@@ -0,0 +1,29 @@
+package types
+
+// StreamEventType is the type of an event pushed to the clients of the event stream
+type StreamEventType string
+
+const (
+	StreamEventHead                StreamEventType = "head"
+	StreamEventSlot                StreamEventType = "slot"
+	StreamEventEpoch               StreamEventType = "epoch"
+	StreamEventFinalizedCheckpoint StreamEventType = "finalized_checkpoint"
+	StreamEventChainReorg          StreamEventType = "chain_reorg"
+)
+
+// StreamEventTypes contains all event types that can be subscribed to
+var StreamEventTypes = []StreamEventType{StreamEventHead, StreamEventSlot, StreamEventEpoch, StreamEventFinalizedCheckpoint, StreamEventChainReorg}
+
+// StreamEvent is a chain event that is published to all explorer instances and pushed to the clients of the event stream.
+// Head and slot events carry the proposer of the block, epoch, finalization and reorg events
+// concern the whole network.
+type StreamEvent struct {
+	Type         StreamEventType `json:"type"`
+	Slot         uint64          `json:"slot"`
+	Epoch        uint64          `json:"epoch"`
+	BlockRoot    string          `json:"block_root,omitempty"`
+	OldBlockRoot string          `json:"old_block_root,omitempty"`
+	Proposer     *uint64         `json:"proposer,omitempty"`
+	Status       string          `json:"status,omitempty"`
+	Timestamp    int64           `json:"timestamp"`
+}
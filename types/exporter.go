@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgtype"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
@@ -214,6 +215,65 @@ type WithdrawalsNotification struct {
 	Pubkey         []byte `json:"pubkey"`
 }
 
+// ConsolidationRequestNotification is a struct to hold an EIP-7251 consolidation request of either the execution
+// layer (identified by its transaction) or the consensus layer (identified by the slot it was processed in)
+type ConsolidationRequestNotification struct {
+	Slot          uint64 `db:"slot"`
+	BlockNumber   uint64 `db:"block_number"`
+	TxHash        []byte `db:"tx_hash"`
+	SourceAddress []byte `db:"source_address"`
+	SourcePubkey  []byte `db:"source_pubkey"`
+	TargetPubkey  []byte `db:"target_pubkey"`
+	SourceIndex   int64  `db:"source_index"`
+	TargetIndex   int64  `db:"target_index"`
+	Amount        uint64 `db:"amount"`
+	Status        string `db:"status"`
+}
+
+// SwitchToCompoundingNotification is a struct to hold a processed request to switch to 0x02 withdrawal credentials
+type SwitchToCompoundingNotification struct {
+	Slot           uint64 `db:"slot"`
+	Address        []byte `db:"address"`
+	Pubkey         []byte `db:"pubkey"`
+	ValidatorIndex int64  `db:"validator_index"`
+}
+
+// WithdrawalRequestNotification is a struct to hold a processed EIP-7002 withdrawal request, an amount of 0 requests a
+// full exit of the validator
+type WithdrawalRequestNotification struct {
+	Slot           uint64 `db:"slot"`
+	SourceAddress  []byte `db:"source_address"`
+	Pubkey         []byte `db:"pubkey"`
+	ValidatorIndex int64  `db:"validator_index"`
+	Amount         uint64 `db:"amount"`
+}
+
+// SlasherAttestation is an attestation kept in the history of the slasher together with the root of its data
+type SlasherAttestation struct {
+	DataRoot []byte
+	*IndexedAttestation
+}
+
+// SlasherSlashingNotification is a struct to hold a validator that is slashable by a slashing the slasher detected
+type SlasherSlashingNotification struct {
+	ValidatorIndex uint64 `db:"validatorindex"`
+	Pubkey         []byte `db:"pubkey"`
+	Type           string `db:"type"`
+	DetectedSlot   uint64 `db:"detected_slot"`
+}
+
+// BackfillUnit is a struct to hold a range of epochs the backfill command exports and its persisted progress
+type BackfillUnit struct {
+	JobID      uint64         `db:"job_id"`
+	StartEpoch uint64         `db:"start_epoch"`
+	EndEpoch   uint64         `db:"end_epoch"`
+	NextEpoch  uint64         `db:"next_epoch"`
+	Stores     pq.StringArray `db:"stores"`
+	Status     string         `db:"status"`
+	Attempts   uint64         `db:"attempts"`
+	Error      sql.NullString `db:"error"`
+}
+
 // Eth1Data is a struct to hold the ETH1 data
 type Eth1Data struct {
 	DepositRoot  []byte
@@ -289,6 +349,21 @@ type MinimalBlock struct {
 	Canonical  bool   `db:"-"`
 }
 
+// ChainReorg is a struct to hold a chain reorg detected by the slot exporter, slot and old block root are those of the
+// first orphaned block and the new block root is the canonical block at that slot (empty if the slot is now missed).
+// Last slot is the latest exported slot when the reorg was detected, the exported data up to it is rolled back after
+// the reorg has been saved.
+type ChainReorg struct {
+	Ts           time.Time `db:"ts"`
+	Slot         uint64    `db:"slot"`
+	Epoch        uint64    `db:"epoch"`
+	Depth        uint64    `db:"depth"`
+	OldBlockRoot []byte    `db:"old_blockroot"`
+	NewBlockRoot []byte    `db:"new_blockroot"`
+	LastSlot     uint64    `db:"last_slot"`
+	RolledBack   bool      `db:"rolled_back"`
+}
+
 // CanonBlock is a struct to hold canon block data
 type CanonBlock struct {
 	BlockRoot []byte `db:"blockroot"`
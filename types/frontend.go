@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -30,12 +31,20 @@ const (
 	ValidatorIsOfflineEventName                      EventName = "validator_is_offline"
 	ValidatorReceivedWithdrawalEventName             EventName = "validator_withdrawal"
 	ValidatorReceivedDepositEventName                EventName = "validator_received_deposit"
+	ValidatorConsolidationRequestEventName           EventName = "validator_consolidation_request"
+	ValidatorConsolidationProcessedEventName         EventName = "validator_consolidation_processed"
+	ValidatorConsolidationRejectedEventName          EventName = "validator_consolidation_rejected"
+	ValidatorSwitchedToCompoundingEventName          EventName = "validator_switched_to_compounding"
+	ValidatorWithdrawalRequestEventName              EventName = "validator_withdrawal_request"
+	ValidatorQueueEstimateChangedEventName           EventName = "validator_queue_estimate_changed"
+	ValidatorSlashingDetectedEventName               EventName = "validator_slashing_detected"
 	NetworkSlashingEventName                         EventName = "network_slashing"
 	NetworkValidatorActivationQueueFullEventName     EventName = "network_validator_activation_queue_full"
 	NetworkValidatorActivationQueueNotFullEventName  EventName = "network_validator_activation_queue_not_full"
 	NetworkValidatorExitQueueFullEventName           EventName = "network_validator_exit_queue_full"
 	NetworkValidatorExitQueueNotFullEventName        EventName = "network_validator_exit_queue_not_full"
 	NetworkLivenessIncreasedEventName                EventName = "network_liveness_increased"
+	NetworkChainReorgEventName                       EventName = "network_chain_reorg"
 	EthClientUpdateEventName                         EventName = "eth_client_update"
 	MonitoringMachineOfflineEventName                EventName = "monitoring_machine_offline"
 	MonitoringMachineDiskAlmostFullEventName         EventName = "monitoring_hdd_almostfull"
@@ -83,12 +92,20 @@ var EventLabel map[EventName]string = map[EventName]string{
 	ValidatorIsOfflineEventName:                      "Your validator(s) state changed",
 	ValidatorReceivedDepositEventName:                "Your validator(s) received a deposit",
 	ValidatorReceivedWithdrawalEventName:             "A withdrawal was initiated for your validators",
+	ValidatorConsolidationRequestEventName:           "A consolidation was requested for your validator(s)",
+	ValidatorConsolidationProcessedEventName:         "A consolidation of your validator(s) has been processed",
+	ValidatorConsolidationRejectedEventName:          "A consolidation of your validator(s) has been rejected",
+	ValidatorSwitchedToCompoundingEventName:          "Your validator(s) switched to compounding credentials",
+	ValidatorWithdrawalRequestEventName:              "An execution layer withdrawal was requested for your validator(s)",
+	ValidatorQueueEstimateChangedEventName:           "The estimated activation or exit of your validator(s) changed",
+	ValidatorSlashingDetectedEventName:               "A slashable offence of your validator(s) has been detected",
 	NetworkSlashingEventName:                         "A slashing event has been registered by the network",
 	NetworkValidatorActivationQueueFullEventName:     "The activation queue is full",
 	NetworkValidatorActivationQueueNotFullEventName:  "The activation queue is empty",
 	NetworkValidatorExitQueueFullEventName:           "The validator exit queue is full",
 	NetworkValidatorExitQueueNotFullEventName:        "The validator exit queue is empty",
 	NetworkLivenessIncreasedEventName:                "The network is experiencing liveness issues",
+	NetworkChainReorgEventName:                       "A chain reorg has been detected",
 	EthClientUpdateEventName:                         "An Ethereum client has a new update available",
 	MonitoringMachineOfflineEventName:                "Your machine(s) might be offline",
 	MonitoringMachineDiskAlmostFullEventName:         "Your machine(s) disk space is running low",
@@ -132,12 +149,20 @@ var EventNames = []EventName{
 	ValidatorIsOfflineEventName,
 	ValidatorReceivedDepositEventName,
 	ValidatorReceivedWithdrawalEventName,
+	ValidatorConsolidationRequestEventName,
+	ValidatorConsolidationProcessedEventName,
+	ValidatorConsolidationRejectedEventName,
+	ValidatorSwitchedToCompoundingEventName,
+	ValidatorWithdrawalRequestEventName,
+	ValidatorQueueEstimateChangedEventName,
+	ValidatorSlashingDetectedEventName,
 	NetworkSlashingEventName,
 	NetworkValidatorActivationQueueFullEventName,
 	NetworkValidatorActivationQueueNotFullEventName,
 	NetworkValidatorExitQueueFullEventName,
 	NetworkValidatorExitQueueNotFullEventName,
 	NetworkLivenessIncreasedEventName,
+	NetworkChainReorgEventName,
 	EthClientUpdateEventName,
 	MonitoringMachineOfflineEventName,
 	MonitoringMachineDiskAlmostFullEventName,
@@ -202,6 +227,38 @@ var AddWatchlistEvents = []EventNameDesc{
 		Event: ValidatorReceivedWithdrawalEventName,
 		Info:  template.HTML(`<i data-toggle="tooltip" data-html="true" title="<div class='text-left'>Will trigger a notifcation when:<br><ul><li>A partial withdrawal is processed</li><li>Your validator exits and its full balance is withdrawn</li></ul> <div>Requires that your validator has 0x01 credentials</div></div>" class="fas fa-question-circle"></i>`),
 	},
+	{
+		Desc:  "Consolidation requested",
+		Event: ValidatorConsolidationRequestEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" title="Will trigger when a consolidation request with your validator as source or target is included in an execution block" class="fas fa-question-circle"></i>`),
+	},
+	{
+		Desc:  "Consolidation processed",
+		Event: ValidatorConsolidationProcessedEventName,
+	},
+	{
+		Desc:  "Consolidation rejected",
+		Event: ValidatorConsolidationRejectedEventName,
+	},
+	{
+		Desc:  "Switched to 0x02 credentials",
+		Event: ValidatorSwitchedToCompoundingEventName,
+	},
+	{
+		Desc:  "Withdrawal requested",
+		Event: ValidatorWithdrawalRequestEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" title="Will trigger when a partial withdrawal or exit triggered by the withdrawal address (EIP-7002) has been processed" class="fas fa-question-circle"></i>`),
+	},
+	{
+		Desc:  "Queue estimate changed",
+		Event: ValidatorQueueEstimateChangedEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" title="Will trigger when the estimated activation or exit epoch of your validator in the queue moves by more than the configured number of epochs" class="fas fa-question-circle"></i>`),
+	},
+	{
+		Desc:  "Slashable offence detected",
+		Event: ValidatorSlashingDetectedEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" title="Will trigger when the slasher detects a double proposal, double vote or surround vote of your validator before the slashing is included on chain" class="fas fa-question-circle"></i>`),
+	},
 }
 
 // this is the source of truth for the network events that are supported by the user/notification page
@@ -210,6 +267,10 @@ var NetworkNotificationEvents = []EventNameDesc{
 		Desc:  "Network Notifications",
 		Event: NetworkLivenessIncreasedEventName,
 	},
+	{
+		Desc:  "Chain Reorg Notifications",
+		Event: NetworkChainReorgEventName,
+	},
 	// {
 	// 	Desc:  "Slashing Notifications",
 	// 	Event: NetworkSlashingEventName,
@@ -258,11 +319,59 @@ type Subscription struct {
 	LastSent    *time.Time `db:"last_sent_ts"`
 	LastEpoch   *uint64    `db:"last_sent_epoch"`
 	// Channels        pq.StringArray `db:"channels"`
-	CreatedTime     time.Time      `db:"created_ts"`
-	CreatedEpoch    uint64         `db:"created_epoch"`
-	EventThreshold  float64        `db:"event_threshold"`
-	UnsubscribeHash sql.NullString `db:"unsubscribe_hash" swaggertype:"string"`
-	State           sql.NullString `db:"internal_state" swaggertype:"string"`
+	CreatedTime     time.Time              `db:"created_ts"`
+	CreatedEpoch    uint64                 `db:"created_epoch"`
+	EventThreshold  float64                `db:"event_threshold"`
+	UnsubscribeHash sql.NullString         `db:"unsubscribe_hash" swaggertype:"string"`
+	State           sql.NullString         `db:"internal_state" swaggertype:"string"`
+	DigestMode      NotificationDigestMode `db:"digest_mode"`
+}
+
+// NotificationDigestMode defines whether the email notifications of a subscription are sent right away or collected
+// and sent as a summary once per window
+type NotificationDigestMode string
+
+const (
+	NotificationDigestImmediate NotificationDigestMode = "immediate"
+	NotificationDigestHourly    NotificationDigestMode = "hourly"
+	NotificationDigestDaily     NotificationDigestMode = "daily"
+)
+
+var NotificationDigestModes = []NotificationDigestMode{
+	NotificationDigestImmediate,
+	NotificationDigestHourly,
+	NotificationDigestDaily,
+}
+
+func NotificationDigestModeFromString(mode string) (NotificationDigestMode, error) {
+	for _, m := range NotificationDigestModes {
+		if string(m) == mode {
+			return m, nil
+		}
+	}
+	return "", errors.Errorf("%v is not a known digest mode", mode)
+}
+
+// UserNotificationSettings are the delivery settings of a user, notifications created during the quiet hours are
+// collected and sent once the quiet hours are over
+type UserNotificationSettings struct {
+	UserID          uint64 `db:"user_id" json:"-"`
+	TimeZone        string `db:"time_zone" json:"time_zone"`
+	QuietHoursStart *int64 `db:"quiet_hours_start" json:"quiet_hours_start,omitempty"` // minutes after midnight
+	QuietHoursEnd   *int64 `db:"quiet_hours_end" json:"quiet_hours_end,omitempty"`
+}
+
+// NotificationDigestEntry is a notification waiting to be sent with the next digest of a user
+type NotificationDigestEntry struct {
+	ID             uint64    `db:"id"`
+	UserID         uint64    `db:"user_id"`
+	SubscriptionID uint64    `db:"subscription_id"`
+	EventName      EventName `db:"event_name"`
+	Epoch          uint64    `db:"epoch"`
+	Title          string    `db:"title"`
+	Info           string    `db:"info"`
+	Created        time.Time `db:"created"`
+	FlushAfter     time.Time `db:"flush_after"`
 }
 
 type TaggedValidators struct {
@@ -359,8 +468,9 @@ type TransitWebhook struct {
 	Created sql.NullTime `db:"created"`
 	Sent    sql.NullTime `db:"sent"`
 	// Delivered sql.NullTime          `db:"delivered"`
-	Channel string                `db:"channel"`
-	Content TransitWebhookContent `db:"content"`
+	Channel  string                `db:"channel"`
+	Content  TransitWebhookContent `db:"content"`
+	Attempts uint64                `db:"attempts"`
 }
 
 type TransitWebhookContent struct {
@@ -417,6 +527,42 @@ func (a TransitDiscordContent) Value() (driver.Value, error) {
 	return json.Marshal(a)
 }
 
+type TransitChat struct {
+	Id       uint64             `db:"id,omitempty"`
+	Created  sql.NullTime       `db:"created"`
+	Sent     sql.NullTime       `db:"sent"`
+	Channel  string             `db:"channel"`
+	Content  TransitChatContent `db:"content"`
+	Attempts uint64             `db:"attempts"`
+}
+
+// TransitChatContent is a batch of notifications for a single chat destination (slack webhook, telegram chat or matrix room)
+type TransitChatContent struct {
+	Webhook  UserWebhook
+	Epoch    uint64        `json:"epoch"`
+	Messages []ChatMessage `json:"messages"`
+}
+
+// ChatMessage is a single notification of a chat batch, the markdown is converted to the format of the platform when sending
+type ChatMessage struct {
+	Title    string `json:"title"`
+	Markdown string `json:"markdown"`
+	Target   string `json:"target,omitempty"`
+}
+
+func (e *TransitChatContent) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &e)
+}
+
+func (a TransitChatContent) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
 type TransitPush struct {
 	Id      uint64       `db:"id,omitempty"`
 	Created sql.NullTime `db:"created"`
@@ -465,6 +611,25 @@ type UserWebhook struct {
 	Request     sql.NullString `db:"request" json:"request"`
 	Destination sql.NullString `db:"destination" json:"destination"`
 	EventNames  pq.StringArray `db:"event_names" json:"-"`
+	Secret      sql.NullString `db:"secret" json:"-"`
+	// VerificationToken links a telegram chat or verifies a matrix room, chats and rooms are only sent to once VerifiedAt is set
+	VerificationToken sql.NullString `db:"verification_token" json:"-"`
+	VerifiedAt        sql.NullTime   `db:"verified_at" json:"-"`
+}
+
+// UserWebhookDelivery is a single attempt to deliver a notification to a webhook
+type UserWebhookDelivery struct {
+	ID              uint64          `db:"id" json:"id"`
+	WebhookID       uint64          `db:"webhook_id" json:"webhook_id"`
+	NotificationID  uint64          `db:"notification_id" json:"notification_id"`
+	Ts              time.Time       `db:"ts" json:"ts"`
+	Attempt         uint64          `db:"attempt" json:"attempt"`
+	EventName       string          `db:"event_name" json:"event_name"`
+	Request         json.RawMessage `db:"request" json:"request"`
+	StatusCode      *int64          `db:"status_code" json:"status_code"`
+	LatencyMs       uint64          `db:"latency_ms" json:"latency_ms"`
+	ResponseSnippet *string         `db:"response_snippet" json:"response_snippet"`
+	Error           *string         `db:"error" json:"error"`
 }
 
 type UserWebhookSubscriptions struct {
@@ -481,6 +646,9 @@ var NotificationChannelLabels map[NotificationChannel]template.HTML = map[Notifi
 	PushNotificationChannel:           "Push Notification",
 	WebhookNotificationChannel:        `Webhook Notification (<a href="/user/webhooks">configure</a>)`,
 	WebhookDiscordNotificationChannel: "Discord Notification",
+	WebhookSlackNotificationChannel:   "Slack Notification",
+	TelegramNotificationChannel:       "Telegram Notification",
+	MatrixNotificationChannel:         "Matrix Notification",
 }
 
 const (
@@ -488,6 +656,9 @@ const (
 	PushNotificationChannel           NotificationChannel = "push"
 	WebhookNotificationChannel        NotificationChannel = "webhook"
 	WebhookDiscordNotificationChannel NotificationChannel = "webhook_discord"
+	WebhookSlackNotificationChannel   NotificationChannel = "webhook_slack"
+	TelegramNotificationChannel       NotificationChannel = "telegram"
+	MatrixNotificationChannel         NotificationChannel = "matrix"
 )
 
 var NotificationChannels = []NotificationChannel{
@@ -495,6 +666,17 @@ var NotificationChannels = []NotificationChannel{
 	PushNotificationChannel,
 	WebhookNotificationChannel,
 	WebhookDiscordNotificationChannel,
+	WebhookSlackNotificationChannel,
+	TelegramNotificationChannel,
+	MatrixNotificationChannel,
+}
+
+// ChatNotificationChannels are the channels delivering notifications as chat messages, their queue entries
+// contain all notifications of a user for an epoch as a single batch
+var ChatNotificationChannels = []NotificationChannel{
+	WebhookSlackNotificationChannel,
+	TelegramNotificationChannel,
+	MatrixNotificationChannel,
 }
 
 func GetNotificationChannel(channel string) (NotificationChannel, error) {
@@ -541,9 +723,66 @@ type GasNowPageData struct {
 		Price     float64  `json:"price,omitempty"`
 		PriceUSD  float64  `json:"priceUSD"`
 		Currency  string   `json:"currency,omitempty"`
+		// BlobBaseFee, BlobGasUsed and ExcessBlobGas are the blob gas values of the pending block
+		BlobBaseFee         *big.Int             `json:"blobBaseFee,omitempty"`
+		BlobGasUsed         uint64               `json:"blobGasUsed"`
+		ExcessBlobGas       uint64               `json:"excessBlobGas"`
+		BlobTarget          uint64               `json:"blobTarget"`
+		BlobMax             uint64               `json:"blobMax"`
+		BlobBaseFeeForecast *BlobBaseFeeForecast `json:"blobBaseFeeForecast,omitempty"`
 	} `json:"data"`
 }
 
+// BlobBaseFeeForecast is the blob base fee of the blocks following the pending block if they contain no blobs, the
+// target or the max number of blobs
+type BlobBaseFeeForecast struct {
+	Blocks []uint64   `json:"blocks"`
+	Empty  []*big.Int `json:"empty"`
+	Target []*big.Int `json:"target"`
+	Full   []*big.Int `json:"full"`
+}
+
+// BlobSubmitterStats holds the blobs a sender submitted in transactions to an address on a day
+type BlobSubmitterStats struct {
+	Day               time.Time       `db:"day"`
+	Sender            []byte          `db:"sender"`
+	ToAddress         []byte          `db:"to_address"`
+	TxCount           uint64          `db:"tx_count"`
+	BlobCount         uint64          `db:"blob_count"`
+	BlobGasUsed       decimal.Decimal `db:"blob_gas_used"`
+	BlobFee           decimal.Decimal `db:"blob_fee"`
+	MeasuredBlobCount uint64          `db:"measured_blob_count"`
+	NonZeroBytes      uint64          `db:"non_zero_bytes"`
+}
+
+// BlobSidecarStats holds the number of non-zero bytes of a blob
+type BlobSidecarStats struct {
+	BlobVersionedHash []byte `db:"blob_versioned_hash"`
+	Slot              uint64 `db:"block_slot"`
+	NonZeroBytes      uint64 `db:"non_zero_bytes"`
+}
+
+// ApiUsage holds the requests of an api key to a route in a bucket of time
+type ApiUsage struct {
+	Ts       time.Time `db:"ts"`
+	ApiKey   string    `db:"apikey"`
+	Endpoint string    `db:"endpoint"`
+	Count    int64     `db:"count"`
+	Blocked  int64     `db:"blocked"`
+}
+
+// GasNowChartsData holds the data of the charts of the gas now page
+type GasNowChartsData struct {
+	History   []*GasNowHistoryAverage
+	WaitTimes []*MempoolWaitTime
+}
+
+// GasNowHistoryAverage is the average fast gas price of an hour
+type GasNowHistoryAverage struct {
+	Ts      int64   `json:"ts"`
+	AvgFast float64 `json:"fast"`
+}
+
 type Eth1AddressSearchItem struct {
 	Address string `json:"address"`
 	Name    string `json:"name"`
@@ -580,6 +819,56 @@ type MempoolTxPageData struct {
 	RawMempoolTransaction
 	TargetIsContract   bool
 	IsContractCreation bool
+	History            *MempoolTxHistory
+}
+
+const (
+	MempoolTxPending   = "pending"
+	MempoolTxMined     = "mined"
+	MempoolTxReplaced  = "replaced"
+	MempoolTxCancelled = "cancelled"
+	MempoolTxDropped   = "dropped"
+)
+
+// MempoolHistoryTransaction is a transaction recorded by the mempool history with the times it has been seen in the mempool
+type MempoolHistoryTransaction struct {
+	Hash        []byte              `db:"hash"`
+	Sender      []byte              `db:"sender"`
+	Nonce       uint64              `db:"nonce"`
+	To          []byte              `db:"to_address"`
+	Value       decimal.Decimal     `db:"value"`
+	Gas         uint64              `db:"gas"`
+	GasPrice    decimal.Decimal     `db:"gas_price"`
+	GasTipCap   decimal.NullDecimal `db:"gas_tip_cap"`
+	FirstSeen   time.Time           `db:"first_seen"`
+	LastSeen    time.Time           `db:"last_seen"`
+	Status      string              `db:"status"`
+	ReplacedBy  []byte              `db:"replaced_by"`
+	BlockNumber sql.NullInt64       `db:"block_number"`
+	BlockTs     sql.NullTime        `db:"block_ts"`
+}
+
+// TimeInMempool returns the duration between the transaction being seen first and being mined or last seen
+func (tx *MempoolHistoryTransaction) TimeInMempool() time.Duration {
+	if tx.BlockTs.Valid && tx.BlockTs.Time.After(tx.FirstSeen) {
+		return tx.BlockTs.Time.Sub(tx.FirstSeen)
+	}
+	return tx.LastSeen.Sub(tx.FirstSeen)
+}
+
+// MempoolTxHistory is the mempool history of a transaction and of all transactions of the sender with the same nonce
+type MempoolTxHistory struct {
+	Transaction *MempoolHistoryTransaction
+	// Replacements holds the transactions with the same sender and nonce, including the transaction itself, ordered by first seen
+	Replacements []*MempoolHistoryTransaction
+}
+
+// MempoolWaitTime is the time mined transactions waited in the mempool grouped by their max priority fee
+type MempoolWaitTime struct {
+	TipGwei    float64 `db:"tip_gwei" json:"tip_gwei"`
+	Count      uint64  `db:"count" json:"count"`
+	MedianWait float64 `db:"median_wait" json:"median_wait"`
+	P90Wait    float64 `db:"p90_wait" json:"p90_wait"`
 }
 
 type SyncCommitteesStats struct {
@@ -634,3 +923,31 @@ type QueuesEstimate struct {
 	EnteringBalancePerDay          uint64
 	EnteringBalancePerEpoch        uint64
 }
+
+const (
+	ValidatorExportPending  = "pending"
+	ValidatorExportRunning  = "running"
+	ValidatorExportFinished = "finished"
+	ValidatorExportFailed   = "failed"
+)
+
+// ValidatorExportJob is an asynchronous export of the balance or income history of validators to a csv or parquet file
+type ValidatorExportJob struct {
+	ID          uint64         `db:"id" json:"id"`
+	UserID      uint64         `db:"user_id" json:"-"`
+	Validators  pq.Int64Array  `db:"validators" json:"validators"`
+	Source      string         `db:"source" json:"source"`
+	Fields      pq.StringArray `db:"fields" json:"fields"`
+	Format      string         `db:"format" json:"format"`
+	StartEpoch  uint64         `db:"start_epoch" json:"start_epoch"`
+	EndEpoch    uint64         `db:"end_epoch" json:"end_epoch"`
+	Status      string         `db:"status" json:"status"`
+	Error       *string        `db:"error" json:"error"`
+	ObjectKey   *string        `db:"object_key" json:"-"`
+	RowCount    uint64         `db:"row_count" json:"row_count"`
+	SizeBytes   uint64         `db:"size_bytes" json:"size_bytes"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	StartedAt   *time.Time     `db:"started_at" json:"started_at"`
+	FinishedAt  *time.Time     `db:"finished_at" json:"finished_at"`
+	DownloadUrl *string        `db:"-" json:"download_url"` // signed link to the file, only set for finished jobs
+}
@@ -19,6 +19,7 @@ package types
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
@@ -41,3 +42,23 @@ type PendingDeposit struct {
 	// in transition from est_clear_epoch => est_clear_epoch+1: activation eligibility will be set to est_clear_epoch+1 [1 epoch delay]
 	// in transition from est_clear_epoch+2 => est_clear_epoch+3: checkpoint est_clear_epoch+1 finalized, set activation epoch to (est_clear_epoch+1)+1+4  [1 epoch delay, 4 = MAX_SEED_LOOKAHEAD]
 }
+
+// ValidatorQueueName is the name of a queue a validator can be waiting in
+type ValidatorQueueName string
+
+const (
+	ValidatorActivationQueue ValidatorQueueName = "activation"
+	ValidatorExitQueue       ValidatorQueueName = "exit"
+)
+
+// ValidatorQueuePosition is the position of a validator in the activation or exit queue and the epoch in which the
+// validator is estimated to leave the queue
+type ValidatorQueuePosition struct {
+	Ts             time.Time          `db:"ts"`
+	Pubkey         hexutil.Bytes      `db:"pubkey"`
+	Queue          ValidatorQueueName `db:"queue"`
+	ValidatorIndex sql.NullInt64      `db:"validator_index"`
+	Position       uint64             `db:"position"`
+	BalanceAhead   uint64             `db:"balance_ahead"` // effective balance of the validators or deposits ahead in the queue in gwei
+	EstEpoch       uint64             `db:"est_epoch"`     // estimated activation or exit epoch
+}
@@ -442,6 +442,7 @@ type ValidatorPageData struct {
 	EstimatedIndexTs                 time.Time
 	ConsensusElExits                 []*FrontendConsensusELExitRequest
 	EnableWithdrawalsTab             bool
+	QueuePositionHistory             []*ValidatorQueuePosition
 }
 
 type RocketpoolValidatorPageData struct {
@@ -1398,6 +1399,10 @@ type UserNotificationsCenterPageData struct {
 type NotificationChannelsModal struct {
 	CsrfField            template.HTML
 	NotificationChannels []UserNotificationChannels
+	TimeZone             string
+	QuietHoursStart      string // 15:04 formatted, empty if quiet hours are disabled
+	QuietHoursEnd        string
+	DigestModes          []NotificationDigestMode
 }
 
 type UserNotificationChannels struct {
@@ -1498,6 +1503,53 @@ type Price struct {
 	AUD float64   `db:"aud"`
 }
 
+// UserApiUsagePageData is the data of the api usage page of the user settings
+type UserApiUsagePageData struct {
+	Usage    *ApiUserUsageResponse
+	Days     int
+	Interval string
+	Quotas   []UserApiUsageQuota
+}
+
+// UserApiUsageQuota is the monthly quota of a bucket shown on the api usage page
+type UserApiUsageQuota struct {
+	ApiUsageQuota
+	UsedShare      float64 // share of the monthly limit that has been used
+	RemainingCount int64
+}
+
+type UserApiKeysPageData struct {
+	Keys      []*ApiKey
+	Scopes    []string
+	MaxKeys   int
+	CsrfField template.HTML
+	Flashes   []interface{}
+}
+
+// ApiKey is an api key of a user as stored in api_keys
+type ApiKey struct {
+	Id         uint64         `db:"id"`
+	ApiKey     string         `db:"api_key"`
+	Name       string         `db:"name"`
+	Scopes     pq.StringArray `db:"scopes"`
+	AllowedIPs pq.StringArray `db:"allowed_ips"`
+	CreatedAt  time.Time      `db:"created_at"`
+	ValidUntil time.Time      `db:"valid_until"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+	RotatedTo  *uint64        `db:"rotated_to"`
+}
+
+// Active returns true if the key can be used
+func (k *ApiKey) Active() bool {
+	return k.ValidUntil.After(time.Now())
+}
+
+// Expires returns false if the key was created without expiry date
+func (k *ApiKey) Expires() bool {
+	return k.ValidUntil.Year() < 9999
+}
+
 type ApiStatistics struct {
 	Daily      *int `db:"daily"`
 	Monthly    *int `db:"monthly"`
@@ -1584,6 +1636,7 @@ type UserWebhookRow struct {
 	ID           uint64 `db:"id" json:"id"`
 	UrlFull      string
 	Url          template.HTML `db:"url" json:"url"`
+	Secret       template.HTML `db:"secret" json:"-"`
 	Retries      template.HTML `db:"retries" json:"retries"`
 	LastSent     template.HTML `db:"last_retry" json:"lastSent"`
 	Destination  template.HTML `db:"destination" json:"destination"`
@@ -1592,7 +1645,10 @@ type UserWebhookRow struct {
 	Request      *map[string]interface{} `db:"request" json:"request"`
 	Events       []EventNameCheckbox     `db:"event_names" json:"-"`
 	Discord      bool
-	CsrfField    template.HTML
+	// DestinationLabel is the name of the platform the notifications are sent to
+	DestinationLabel string
+	Destinations     []WebhookDestination
+	CsrfField        template.HTML
 }
 
 type AdConfigurationPageData struct {
@@ -1618,12 +1674,20 @@ type WebhookPageData struct {
 	WebhookRows  []UserWebhookRow
 	Webhooks     []UserWebhook
 	Events       []EventNameCheckbox
+	Destinations []WebhookDestination
 	CsrfField    template.HTML
 	Allowed      uint64
 	WebhookCount uint64
 	Flashes      []interface{}
 }
 
+// WebhookDestination is a platform a webhook can send notifications to, the target is either an url or a chat id
+type WebhookDestination struct {
+	Value       string
+	Label       string
+	Placeholder string
+}
+
 type EventNameCheckbox struct {
 	EventLabel string
 	EventName
@@ -1713,24 +1777,28 @@ type DataTableSaveStateColumns struct {
 }
 
 type Eth1AddressPageData struct {
-	Address            string `json:"address"`
-	EnsName            string `json:"ensName"`
-	IsContract         bool
-	QRCode             string `json:"qr_code_base64"`
-	QRCodeInverse      string
-	Metadata           *Eth1AddressMetadata
-	WithdrawalsSummary template.HTML
-	BlocksMinedTable   *DataTableResponse
-	UnclesMinedTable   *DataTableResponse
-	TransactionsTable  *DataTableResponse
-	BlobTxnsTable      *DataTableResponse
-	InternalTxnsTable  *DataTableResponse
-	Erc20Table         *DataTableResponse
-	Erc721Table        *DataTableResponse
-	Erc1155Table       *DataTableResponse
-	WithdrawalsTable   *DataTableResponse
-	EtherValue         template.HTML
-	Tabs               []Eth1AddressPageTabs
+	Address             string `json:"address"`
+	EnsName             string `json:"ensName"`
+	IsContract          bool
+	QRCode              string `json:"qr_code_base64"`
+	QRCodeInverse       string
+	Metadata            *Eth1AddressMetadata
+	WithdrawalsSummary  template.HTML
+	BlocksMinedTable    *DataTableResponse
+	UnclesMinedTable    *DataTableResponse
+	TransactionsTable   *DataTableResponse
+	BlobTxnsTable       *DataTableResponse
+	AuthorizationsTable *DataTableResponse
+	UserOpsTable        *DataTableResponse
+	InternalTxnsTable   *DataTableResponse
+	Erc20Table          *DataTableResponse
+	Erc721Table         *DataTableResponse
+	Erc1155Table        *DataTableResponse
+	ApprovalsTable      *DataTableResponse
+	WithdrawalsTable    *DataTableResponse
+	EtherValue          template.HTML
+	DelegatesTo         template.HTML
+	Tabs                []Eth1AddressPageTabs
 }
 
 type ContractInteractionType uint8
@@ -1907,6 +1975,9 @@ type Eth1TxData struct {
 	IsContractCreation          bool
 	CallData                    string
 	Method                      string
+	DecodedCallData             []*Eth1DecodedCallInput
+	ProxyImplementation         *common.Address
+	ProxyType                   string
 	Events                      []*Eth1EventData
 	Transfers                   []*Transfer
 	DepositContractInteractions []DepositContractInteraction
@@ -1915,6 +1986,16 @@ type Eth1TxData struct {
 	CurrentEtherPrice           template.HTML
 	HistoricalEtherPrice        template.HTML
 	BlobHashes                  [][]byte
+	SetCodeAuthorizations       []*Eth1TxSetCodeAuthorization
+	MempoolHistory              *MempoolTxHistory
+}
+
+type Eth1TxSetCodeAuthorization struct {
+	Authority []byte
+	Address   common.Address
+	ChainId   *big.Int
+	Nonce     uint64
+	Valid     bool
 }
 
 type Eth1EventData struct {
@@ -1925,6 +2006,13 @@ type Eth1EventData struct {
 	DecodedData map[string]Eth1DecodedEventData
 }
 
+type Eth1DecodedCallInput struct {
+	Name    string
+	Type    string
+	Value   string
+	Address common.Address
+}
+
 type Eth1DecodedEventData struct {
 	Type    string
 	Value   string
@@ -0,0 +1,199 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// GasPerBlob is the blob gas used by a single blob (EIP-4844)
+const GasPerBlob = 1 << 17
+
+// BlobSize is the size of a blob in bytes
+const BlobSize = 4096 * 32
+
+// blobBaseCost is the execution gas a blob costs at least once the reserve price is active (EIP-7918)
+const blobBaseCost = 1 << 13
+
+// mainnetBlobSchedule is the blob schedule of mainnet, used if the EL chain config does not define one
+var mainnetBlobSchedule = []*types.BlobScheduleEntry{
+	{Fork: "cancun", Timestamp: 1710338135, Target: 3, Max: 6, BaseFeeUpdateFraction: 3338477},
+	{Fork: "prague", Timestamp: 1746612311, Target: 6, Max: 9, BaseFeeUpdateFraction: 5007716},
+	{Fork: "osaka", Timestamp: 1764798551, Target: 6, Max: 9, BaseFeeUpdateFraction: 5007716, ReservePrice: true},
+	{Fork: "bpo1", Timestamp: 1765290071, Target: 10, Max: 15, BaseFeeUpdateFraction: 8346193, ReservePrice: true},
+	{Fork: "bpo2", Timestamp: 1767747671, Target: 14, Max: 21, BaseFeeUpdateFraction: 11684671, ReservePrice: true},
+}
+
+// BlobParamsAt returns the blob parameters active at the given block timestamp, the Cancun parameters are returned if
+// the chain has no blob schedule
+func BlobParamsAt(ts uint64) *types.BlobScheduleEntry {
+	schedule := Config.Chain.BlobSchedule
+	if len(schedule) == 0 {
+		return mainnetBlobSchedule[0]
+	}
+	for i := len(schedule) - 1; i > 0; i-- {
+		if schedule[i].Timestamp <= ts {
+			return schedule[i]
+		}
+	}
+	return schedule[0]
+}
+
+// CalcBlobBaseFee returns the blob base fee of a block at the given timestamp with the given excess blob gas
+func CalcBlobBaseFee(excessBlobGas, ts uint64) *big.Int {
+	return calcBlobBaseFee(BlobParamsAt(ts), excessBlobGas)
+}
+
+// CalcExcessBlobGas returns the excess blob gas of the block at timestamp ts following the given parent block
+func CalcExcessBlobGas(parentExcessBlobGas, parentBlobGasUsed uint64, parentBaseFee *big.Int, parentTs, ts uint64) uint64 {
+	return calcExcessBlobGas(BlobParamsAt(parentTs), BlobParamsAt(ts), parentExcessBlobGas, parentBlobGasUsed, parentBaseFee)
+}
+
+// ForecastBlobBaseFees returns the blob base fee of the n blocks following the block with the given blob gas values and
+// timestamp, assuming each of the following blocks contains the number of blobs returned by blobs and the execution
+// base fee stays the same
+func ForecastBlobBaseFees(excessBlobGas, blobGasUsed uint64, baseFee *big.Int, ts uint64, n int, blobs func(params *types.BlobScheduleEntry) uint64) []*big.Int {
+	slotTime := Config.Chain.ClConfig.SecondsPerSlot
+	if slotTime == 0 {
+		slotTime = 12
+	}
+
+	fees := make([]*big.Int, 0, n)
+	parentParams := BlobParamsAt(ts)
+	for i := 0; i < n; i++ {
+		ts += slotTime
+		params := BlobParamsAt(ts)
+		excessBlobGas = calcExcessBlobGas(parentParams, params, excessBlobGas, blobGasUsed, baseFee)
+		fees = append(fees, calcBlobBaseFee(params, excessBlobGas))
+		blobGasUsed = blobs(params) * GasPerBlob
+		parentParams = params
+	}
+	return fees
+}
+
+func calcBlobBaseFee(params *types.BlobScheduleEntry, excessBlobGas uint64) *big.Int {
+	return fakeExponential(big.NewInt(1), new(big.Int).SetUint64(excessBlobGas), new(big.Int).SetUint64(params.BaseFeeUpdateFraction))
+}
+
+// calcExcessBlobGas implements the excess blob gas update rule of EIP-4844, including the reserve price of EIP-7918
+// which lets the excess grow slower while the blob base fee is below the reserve price
+func calcExcessBlobGas(parentParams, params *types.BlobScheduleEntry, parentExcessBlobGas, parentBlobGasUsed uint64, parentBaseFee *big.Int) uint64 {
+	targetBlobGas := params.Target * GasPerBlob
+	if parentExcessBlobGas+parentBlobGasUsed < targetBlobGas {
+		return 0
+	}
+	if params.ReservePrice && parentBaseFee != nil {
+		reservePrice := new(big.Int).Mul(parentBaseFee, big.NewInt(blobBaseCost))
+		blobPrice := new(big.Int).Mul(calcBlobBaseFee(parentParams, parentExcessBlobGas), big.NewInt(GasPerBlob))
+		if reservePrice.Cmp(blobPrice) > 0 {
+			return parentExcessBlobGas + parentBlobGasUsed*(params.Max-params.Target)/params.Max
+		}
+	}
+	return parentExcessBlobGas + parentBlobGasUsed - targetBlobGas
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) using a taylor expansion as specified by EIP-4844
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	output := new(big.Int)
+	accum := new(big.Int).Mul(factor, denominator)
+	for i := int64(1); accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+
+		accum.Mul(accum, numerator)
+		accum.Div(accum, denominator)
+		accum.Div(accum, big.NewInt(i))
+	}
+	return output.Div(output, denominator)
+}
+
+// readBlobSchedule returns the blob schedule defined by the blobSchedule and fork times of the EL chain config, the
+// mainnet schedule is returned for mainnet if the EL chain config does not define one
+func readBlobSchedule(elConfigPath string, chainId uint64) ([]*types.BlobScheduleEntry, error) {
+	if elConfigPath == "" {
+		return defaultBlobSchedule(chainId), nil
+	}
+
+	data, err := os.ReadFile(elConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading EL Chain Config file %v: %w", elConfigPath, err)
+	}
+	var elConfig map[string]json.RawMessage
+	err = json.Unmarshal(data, &elConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding EL Chain Config file %v: %w", elConfigPath, err)
+	}
+	if len(elConfig["blobSchedule"]) == 0 {
+		return defaultBlobSchedule(chainId), nil
+	}
+
+	var blobSchedule map[string]struct {
+		Target                uint64 `json:"target"`
+		Max                   uint64 `json:"max"`
+		BaseFeeUpdateFraction uint64 `json:"baseFeeUpdateFraction"`
+	}
+	err = json.Unmarshal(elConfig["blobSchedule"], &blobSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding blobSchedule of EL Chain Config file %v: %w", elConfigPath, err)
+	}
+
+	forkTime := func(fork string) (uint64, bool, error) {
+		raw, ok := elConfig[fork+"Time"]
+		if !ok || string(raw) == "null" {
+			return 0, false, nil
+		}
+		var ts uint64
+		err := json.Unmarshal(raw, &ts)
+		if err != nil {
+			return 0, false, fmt.Errorf("error decoding %vTime of EL Chain Config file %v: %w", fork, elConfigPath, err)
+		}
+		return ts, true, nil
+	}
+	osakaTime, osakaScheduled, err := forkTime("osaka")
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := make([]*types.BlobScheduleEntry, 0, len(blobSchedule))
+	for fork, params := range blobSchedule {
+		ts, scheduled, err := forkTime(fork)
+		if err != nil {
+			return nil, err
+		}
+		if !scheduled {
+			continue
+		}
+		schedule = append(schedule, &types.BlobScheduleEntry{
+			Fork:                  fork,
+			Timestamp:             ts,
+			Target:                params.Target,
+			Max:                   params.Max,
+			BaseFeeUpdateFraction: params.BaseFeeUpdateFraction,
+			ReservePrice:          osakaScheduled && ts >= osakaTime,
+		})
+	}
+	return schedule, nil
+}
+
+func defaultBlobSchedule(chainId uint64) []*types.BlobScheduleEntry {
+	if chainId == 1 {
+		return mainnetBlobSchedule
+	}
+	return nil
+}
+
+// validateBlobSchedule sorts the blob schedule by activation and checks the parameters of its entries
+func validateBlobSchedule(schedule []*types.BlobScheduleEntry) error {
+	sort.Slice(schedule, func(i, j int) bool {
+		return schedule[i].Timestamp < schedule[j].Timestamp
+	})
+	for _, entry := range schedule {
+		if entry.Max == 0 || entry.Target > entry.Max || entry.BaseFeeUpdateFraction == 0 {
+			return fmt.Errorf("invalid blob schedule entry for fork %v: target %v, max %v, base fee update fraction %v", entry.Fork, entry.Target, entry.Max, entry.BaseFeeUpdateFraction)
+		}
+	}
+	return nil
+}
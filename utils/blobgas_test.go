@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+var (
+	testCancunBlobParams = &types.BlobScheduleEntry{Fork: "cancun", Timestamp: 0, Target: 3, Max: 6, BaseFeeUpdateFraction: 3338477}
+	testOsakaBlobParams  = &types.BlobScheduleEntry{Fork: "osaka", Timestamp: 1200, Target: 6, Max: 9, BaseFeeUpdateFraction: 5007716, ReservePrice: true}
+)
+
+func TestCalcBlobBaseFee(t *testing.T) {
+	tests := []struct {
+		excessBlobGas uint64
+		want          int64
+	}{
+		{0, 1},
+		{2314057, 1},
+		{2314058, 2},
+		{10 * 1024 * 1024, 23},
+		{83099648, 64600264983},
+	}
+	for _, tt := range tests {
+		if got := calcBlobBaseFee(testCancunBlobParams, tt.excessBlobGas); got.Int64() != tt.want {
+			t.Errorf("excess blob gas %v: got blob base fee %v, want %v", tt.excessBlobGas, got, tt.want)
+		}
+	}
+}
+
+func TestCalcExcessBlobGas(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        *types.BlobScheduleEntry
+		parentExcess  uint64
+		parentBlobs   uint64
+		parentBaseFee int64
+		want          uint64
+	}{
+		{"below target", testCancunBlobParams, 0, 2, 1e9, 0},
+		{"at target", testCancunBlobParams, 5 * GasPerBlob, 3, 1e9, 5 * GasPerBlob},
+		{"full", testCancunBlobParams, 5 * GasPerBlob, 6, 1e9, 8 * GasPerBlob},
+		{"excess shrinks", testCancunBlobParams, 5 * GasPerBlob, 1, 1e9, 3 * GasPerBlob},
+		{"reserve price", testOsakaBlobParams, 0, 6, 1e9, 2 * GasPerBlob},
+		{"reserve price full", testOsakaBlobParams, 0, 9, 1e9, 3 * GasPerBlob},
+		{"above reserve price", testOsakaBlobParams, 0, 9, 1, 3 * GasPerBlob},
+		{"above reserve price at target", testOsakaBlobParams, 0, 6, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calcExcessBlobGas(tt.params, tt.params, tt.parentExcess, tt.parentBlobs*GasPerBlob, big.NewInt(tt.parentBaseFee))
+			if got != tt.want {
+				t.Errorf("got excess blob gas %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForecastBlobBaseFees(t *testing.T) {
+	Config = &types.Config{}
+	Config.Chain.ClConfig.SecondsPerSlot = 12
+	Config.Chain.BlobSchedule = []*types.BlobScheduleEntry{testCancunBlobParams, testOsakaBlobParams}
+
+	excessBlobGas := uint64(10 * 1024 * 1024)
+	fees := ForecastBlobBaseFees(excessBlobGas, 0, big.NewInt(1), 1164, 5, func(params *types.BlobScheduleEntry) uint64 {
+		return params.Max
+	})
+	if len(fees) != 5 {
+		t.Fatalf("got %v forecast blocks, want 5", len(fees))
+	}
+
+	// the first block follows an empty block, from the fork on the higher target and max apply
+	excess := excessBlobGas - 3*GasPerBlob
+	want := []*big.Int{calcBlobBaseFee(testCancunBlobParams, excess)}
+	excess += 3 * GasPerBlob
+	want = append(want, calcBlobBaseFee(testCancunBlobParams, excess))
+	want = append(want, calcBlobBaseFee(testOsakaBlobParams, excess))
+	for i := 0; i < 2; i++ {
+		excess += 3 * GasPerBlob
+		want = append(want, calcBlobBaseFee(testOsakaBlobParams, excess))
+	}
+	for i := range want {
+		if fees[i].Cmp(want[i]) != 0 {
+			t.Errorf("block %v: got blob base fee %v, want %v", i+1, fees[i], want[i])
+		}
+	}
+}
+
+func TestReadBlobSchedule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "el.json")
+	err := os.WriteFile(path, []byte(`{
+		"chainId": 560048,
+		"cancunTime": 0,
+		"pragueTime": 1742999832,
+		"osakaTime": 1761677592,
+		"bpo1Time": null,
+		"blobSchedule": {
+			"cancun": {"target": 3, "max": 6, "baseFeeUpdateFraction": 3338477},
+			"prague": {"target": 6, "max": 9, "baseFeeUpdateFraction": 5007716},
+			"osaka": {"target": 6, "max": 9, "baseFeeUpdateFraction": 5007716},
+			"bpo1": {"target": 10, "max": 15, "baseFeeUpdateFraction": 8346193}
+		}
+	}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedule, err := readBlobSchedule(path, 560048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = validateBlobSchedule(schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []types.BlobScheduleEntry{
+		{Fork: "cancun", Timestamp: 0, Target: 3, Max: 6, BaseFeeUpdateFraction: 3338477},
+		{Fork: "prague", Timestamp: 1742999832, Target: 6, Max: 9, BaseFeeUpdateFraction: 5007716},
+		{Fork: "osaka", Timestamp: 1761677592, Target: 6, Max: 9, BaseFeeUpdateFraction: 5007716, ReservePrice: true},
+	}
+	if len(schedule) != len(want) {
+		t.Fatalf("got %v blob schedule entries, want %v", len(schedule), len(want))
+	}
+	for i := range want {
+		if *schedule[i] != want[i] {
+			t.Errorf("entry %v: got %+v, want %+v", i, *schedule[i], want[i])
+		}
+	}
+
+	schedule, err = readBlobSchedule("", 1)
+	if err != nil || len(schedule) != len(mainnetBlobSchedule) {
+		t.Errorf("got %v entries and error %v, want the mainnet blob schedule", len(schedule), err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"html/template"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 
@@ -175,6 +176,64 @@ func FormatBlobVersionedHash(h []byte) template.HTML {
 	return FormatBytes(h, true, fmt.Sprintf("%s/%#x", Config.Frontend.BlobProviderUrl, h))
 }
 
+// FormatSetCodeAuthorizationValidity formats whether the signature and the chain id of an EIP-7702 authorization are valid
+func FormatSetCodeAuthorizationValidity(valid bool) template.HTML {
+	if valid {
+		return template.HTML(`<span class="badge badge-success text-white">Valid</span>`)
+	}
+	return template.HTML(`<span class="badge badge-danger text-white" data-toggle="tooltip" title="The signature or the chain id of the authorization is invalid, it has been skipped">Invalid</span>`)
+}
+
+// FormatMempoolStatus formats the status of a transaction recorded by the mempool history
+func FormatMempoolStatus(status string) template.HTML {
+	switch status {
+	case types.MempoolTxPending:
+		return template.HTML(`<span class="badge badge-info badge-pill text-white">In Mempool</span>`)
+	case types.MempoolTxMined:
+		return template.HTML(`<span class="badge badge-success badge-pill text-white">Mined</span>`)
+	case types.MempoolTxReplaced:
+		return template.HTML(`<span class="badge badge-warning badge-pill text-white" data-toggle="tooltip" title="Another transaction with the same sender and nonce has replaced this transaction">Replaced</span>`)
+	case types.MempoolTxCancelled:
+		return template.HTML(`<span class="badge badge-warning badge-pill text-white" data-toggle="tooltip" title="The transaction has been replaced by a transaction of the sender to itself without value">Cancelled</span>`)
+	case types.MempoolTxDropped:
+		return template.HTML(`<span class="badge badge-danger badge-pill text-white" data-toggle="tooltip" title="The transaction has left the mempool without being mined">Dropped</span>`)
+	}
+	return template.HTML(fmt.Sprintf(`<span class="badge badge-secondary badge-pill text-white">%s</span>`, template.HTMLEscapeString(status)))
+}
+
+// FormatMempoolDuration formats the time a transaction spent in the mempool
+func FormatMempoolDuration(d time.Duration) string {
+	if d < time.Second {
+		return "< 1s"
+	}
+	return d.Round(time.Second).String()
+}
+
+// FormatApprovalAmount formats the allowance of an erc20 approval, the token id of an erc721 approval or the scope of an approval for all
+func FormatApprovalAmount(approval *types.Eth1ApprovalIndexed, metadata *types.ERC20Metadata) template.HTML {
+	switch approval.GetType() {
+	case types.ApprovalType_ERC721_APPROVAL:
+		return template.HTML(fmt.Sprintf("Token ID %s", new(big.Int).SetBytes(approval.GetTokenId()).String()))
+	case types.ApprovalType_APPROVAL_FOR_ALL:
+		return template.HTML(`<span data-toggle="tooltip" title="The operator may transfer all tokens of the collection">All Tokens</span>`)
+	}
+	// approvals of at least 2^255 are treated as unlimited, as wallets usually approve the maximum uint256 value
+	if len(approval.GetValue()) == 32 && approval.GetValue()[0] >= 0x80 {
+		return template.HTML(`<span class="badge badge-warning">Unlimited</span>`)
+	}
+	if metadata == nil {
+		return template.HTML(new(big.Int).SetBytes(approval.GetValue()).String())
+	}
+	return FormatTokenValue(&types.Eth1AddressBalance{Balance: approval.GetValue(), Metadata: metadata}, true)
+}
+
+func FormatApprovalVerification(verified bool) template.HTML {
+	if verified {
+		return template.HTML(`<span class="badge badge-success text-white" data-toggle="tooltip" title="The approval has been confirmed by the token contract">Verified</span>`)
+	}
+	return template.HTML(`<span class="badge badge-secondary text-white" data-toggle="tooltip" title="The approval could not be confirmed by the token contract">Unverified</span>`)
+}
+
 func FormatAddressWithLimits(address []byte, name string, isContract bool, link string, digitsLimit int, nameLimit int, addCopyToClipboard bool) template.HTML {
 	return formatAddress(address, nil, name, isContract, link, digitsLimit, nameLimit, addCopyToClipboard)
 }
@@ -31,6 +31,9 @@ const ClaimsContextKey = "ClaimsKey"
 
 const MobileAuthorizedKey = "MobileAuthKey"
 
+// ApiKeyUserIdKey Key for context access to the userID of an api key that may manage notifications, set by the rate limiter
+const ApiKeyUserIdKey = "ApiKeyUserIdKey"
+
 const JsonBodyKey = "JsonBodyKey"
 const JsonBodyNakedKey = "JsonBodyNakedKey"
 
@@ -215,13 +218,20 @@ func GetAuthorizationClaims(r *http.Request) *CustomClaims {
 	return claims
 }
 
-// AuthorizedAPIMiddleware Demands an Authorization header to be present with a valid user api token
+// AuthorizedAPIMiddleware Demands an Authorization header to be present with a valid user api token or an api key
+// that was granted the notifications scope by the rate limiter
 // Once authorization passes, this middleware sets a context entry with the authenticated userID
 func AuthorizedAPIMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
+		var claims *CustomClaims
+		var err error
 		accessToken := r.Header.Get("Authorization")
-		if len(accessToken) <= 0 {
+		if len(accessToken) > 0 {
+			claims, err = ValidateAccessTokenGetClaims(accessToken)
+		} else if userId, ok := context.Get(r, ApiKeyUserIdKey).(uint64); ok {
+			claims = &CustomClaims{UserID: userId}
+		} else {
 			j := json.NewEncoder(w)
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusBadRequest)
@@ -229,7 +239,6 @@ func AuthorizedAPIMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		claims, err := ValidateAccessTokenGetClaims(accessToken)
 		if err != nil {
 			j := json.NewEncoder(w)
 			w.Header().Set("Content-Type", "application/json")
@@ -1,6 +1,12 @@
 package utils
 
-import "math/big"
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/parquet-go/parquet-go"
+)
 
 func BigIntFromParquetBytes(b []byte) *big.Int {
 	// reverse to big-endian for SetBytes
@@ -10,3 +16,100 @@ func BigIntFromParquetBytes(b []byte) *big.Int {
 	z := new(big.Int).SetBytes(b)
 	return z
 }
+
+// ParquetColumn is a column of a file written by ParquetRowWriter, columns hold uint64 values unless String is set
+type ParquetColumn struct {
+	Name   string
+	String bool
+}
+
+// ParquetRowWriter writes parquet files whose columns are only known at runtime, e.g. because they are selected by users
+type ParquetRowWriter struct {
+	writer  *parquet.Writer
+	columns []ParquetColumn
+	index   []int // index of every column in the schema, the columns of the schema are ordered by name
+	rows    []parquet.Row
+}
+
+// NewParquetRowWriter returns a writer for zstd compressed parquet files with the given columns
+func NewParquetRowWriter(w io.Writer, name string, columns []ParquetColumn) (*ParquetRowWriter, error) {
+	group := parquet.Group{}
+	for _, c := range columns {
+		if _, ok := group[c.Name]; ok {
+			return nil, fmt.Errorf("duplicate parquet column %v", c.Name)
+		}
+		node := parquet.Uint(64)
+		if c.String {
+			node = parquet.String()
+		}
+		group[c.Name] = parquet.Compressed(node, &parquet.Zstd)
+	}
+	schema := parquet.NewSchema(name, group)
+
+	schemaIndex := map[string]int{}
+	for i, path := range schema.Columns() {
+		schemaIndex[path[0]] = i
+	}
+	index := make([]int, len(columns))
+	for i, c := range columns {
+		index[i] = schemaIndex[c.Name]
+	}
+
+	return &ParquetRowWriter{
+		writer:  parquet.NewWriter(w, schema),
+		columns: columns,
+		index:   index,
+	}, nil
+}
+
+// WriteRow writes a row with a uint64 or string value for every column, in the order of the columns of the writer
+func (pw *ParquetRowWriter) WriteRow(values []interface{}) error {
+	if len(values) != len(pw.columns) {
+		return fmt.Errorf("got %v values for %v parquet columns", len(values), len(pw.columns))
+	}
+	row := make(parquet.Row, len(values))
+	for i, v := range values {
+		var value parquet.Value
+		switch v := v.(type) {
+		case uint64:
+			if pw.columns[i].String {
+				return fmt.Errorf("got uint64 value for string parquet column %v", pw.columns[i].Name)
+			}
+			value = parquet.Int64Value(int64(v))
+		case string:
+			if !pw.columns[i].String {
+				return fmt.Errorf("got string value for uint64 parquet column %v", pw.columns[i].Name)
+			}
+			value = parquet.ByteArrayValue([]byte(v))
+		default:
+			return fmt.Errorf("unsupported value %T for parquet column %v", v, pw.columns[i].Name)
+		}
+		row[pw.index[i]] = value.Level(0, 0, pw.index[i])
+	}
+
+	pw.rows = append(pw.rows, row)
+	if len(pw.rows) >= 1024 {
+		return pw.flushRows()
+	}
+	return nil
+}
+
+func (pw *ParquetRowWriter) flushRows() error {
+	_, err := pw.writer.WriteRows(pw.rows)
+	pw.rows = pw.rows[:0]
+	if err != nil {
+		return fmt.Errorf("error writing parquet rows: %w", err)
+	}
+	return nil
+}
+
+// Close writes the remaining rows and the footer of the file, it does not close the underlying writer
+func (pw *ParquetRowWriter) Close() error {
+	if len(pw.rows) > 0 {
+		err := pw.flushRows()
+		if err != nil {
+			return err
+		}
+	}
+	return pw.writer.Close()
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestParquetRowWriter(t *testing.T) {
+	type row struct {
+		ValidatorIndex uint64 `parquet:"validator_index"`
+		Epoch          uint64 `parquet:"epoch"`
+		TxFeeRewardWei string `parquet:"tx_fee_reward_wei"`
+	}
+
+	// the columns are not ordered by name to check that the values end up in the right column
+	columns := []ParquetColumn{{Name: "validator_index"}, {Name: "epoch"}, {Name: "tx_fee_reward_wei", String: true}}
+	want := []row{}
+	for i := uint64(0); i < 3000; i++ {
+		want = append(want, row{ValidatorIndex: i % 7, Epoch: 1000 + i, TxFeeRewardWei: "18446744073709551616"})
+	}
+
+	buf := &bytes.Buffer{}
+	pw, err := NewParquetRowWriter(buf, "export", columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range want {
+		err = pw.WriteRow([]interface{}{r.ValidatorIndex, r.Epoch, r.TxFeeRewardWei})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = pw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parquet.Read[row](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v rows, want %v", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got row %+v at %v, want %+v", got[i], i, want[i])
+		}
+	}
+}
+
+func TestParquetRowWriterInvalidRows(t *testing.T) {
+	columns := []ParquetColumn{{Name: "epoch"}, {Name: "wei", String: true}}
+	tests := []struct {
+		name   string
+		values []interface{}
+	}{
+		{name: "missing value", values: []interface{}{uint64(1)}},
+		{name: "string for uint64 column", values: []interface{}{"1", "1"}},
+		{name: "uint64 for string column", values: []interface{}{uint64(1), uint64(1)}},
+		{name: "unsupported type", values: []interface{}{1, "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw, err := NewParquetRowWriter(&bytes.Buffer{}, "export", columns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := pw.WriteRow(tt.values); err == nil {
+				t.Errorf("got no error")
+			}
+		})
+	}
+
+	_, err := NewParquetRowWriter(&bytes.Buffer{}, "export", []ParquetColumn{{Name: "epoch"}, {Name: "epoch"}})
+	if err == nil {
+		t.Errorf("got no error for duplicate columns")
+	}
+}
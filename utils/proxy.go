@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"bytes"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// storage slots of the proxy standards, the EIP-1967 slots are keccak256 of their name minus one
+var (
+	ProxyImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc") // eip1967.proxy.implementation
+	ProxyBeaconSlot         = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50") // eip1967.proxy.beacon
+	ProxyAdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103") // eip1967.proxy.admin
+	ProxiableSlot           = common.HexToHash("0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7") // keccak256("PROXIABLE") of EIP-1822
+)
+
+var (
+	ProxyUpgradedTopic       = crypto.Keccak256([]byte("Upgraded(address)"))
+	ProxyBeaconUpgradedTopic = crypto.Keccak256([]byte("BeaconUpgraded(address)"))
+	// BeaconImplementationMethod is the selector of implementation() of EIP-1967 beacons
+	BeaconImplementationMethod = crypto.Keccak256([]byte("implementation()"))[:4]
+)
+
+// runtime code of EIP-1167 minimal proxies, the implementation address is placed between prefix and suffix
+var (
+	minimalProxyCodePrefix = common.FromHex("0x363d3d373d3d3d363d73")
+	minimalProxyCodeSuffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+)
+
+// ParseMinimalProxy returns the implementation of an EIP-1167 minimal proxy, nil is returned if the code is not the
+// one of a minimal proxy
+func ParseMinimalProxy(code []byte) []byte {
+	if len(code) != len(minimalProxyCodePrefix)+common.AddressLength+len(minimalProxyCodeSuffix) ||
+		!bytes.HasPrefix(code, minimalProxyCodePrefix) || !bytes.HasSuffix(code, minimalProxyCodeSuffix) {
+		return nil
+	}
+	return code[len(minimalProxyCodePrefix) : len(minimalProxyCodePrefix)+common.AddressLength]
+}
+
+// ClassifyProxy detects the proxy standard of a contract by its code and the values of the proxy storage slots. It
+// returns nil if the contract is not a proxy. The implementation of beacon proxies is not set, it has to be read from
+// the beacon.
+func ClassifyProxy(code []byte, implementationSlot, beaconSlot, adminSlot, proxiableSlot common.Hash) *types.Eth1ProxyImplementation {
+	if implementation := ParseMinimalProxy(code); implementation != nil {
+		return &types.Eth1ProxyImplementation{
+			Type:           types.ProxyType_MINIMAL_PROXY,
+			Implementation: implementation,
+		}
+	}
+	if beacon := slotAddress(beaconSlot); beacon != nil {
+		return &types.Eth1ProxyImplementation{
+			Type:   types.ProxyType_BEACON_PROXY,
+			Beacon: beacon,
+		}
+	}
+	if implementation := slotAddress(implementationSlot); implementation != nil {
+		proxy := &types.Eth1ProxyImplementation{
+			Type:           types.ProxyType_EIP1967_PROXY,
+			Implementation: implementation,
+		}
+		if admin := slotAddress(adminSlot); admin != nil {
+			proxy.Type = types.ProxyType_TRANSPARENT_PROXY
+			proxy.Admin = admin
+		}
+		return proxy
+	}
+	if implementation := slotAddress(proxiableSlot); implementation != nil {
+		return &types.Eth1ProxyImplementation{
+			Type:           types.ProxyType_EIP1822_PROXY,
+			Implementation: implementation,
+		}
+	}
+	return nil
+}
+
+// slotAddress returns the address stored in a storage slot, nil is returned if the slot is empty
+func slotAddress(slot common.Hash) []byte {
+	if slot == (common.Hash{}) {
+		return nil
+	}
+	return common.BytesToAddress(slot.Bytes()).Bytes()
+}
+
+// FormatProxyType returns the name of a proxy standard
+func FormatProxyType(proxyType types.ProxyType) string {
+	switch proxyType {
+	case types.ProxyType_TRANSPARENT_PROXY:
+		return "Transparent Proxy"
+	case types.ProxyType_EIP1822_PROXY:
+		return "EIP-1822 Proxy"
+	case types.ProxyType_BEACON_PROXY:
+		return "Beacon Proxy"
+	case types.ProxyType_MINIMAL_PROXY:
+		return "Minimal Proxy"
+	case types.ProxyType_UPGRADEABLE_BEACON:
+		return "Upgradeable Beacon"
+	default:
+		return "EIP-1967 Proxy"
+	}
+}
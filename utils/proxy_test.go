@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestProxySlots(t *testing.T) {
+	eip1967Slot := func(name string) common.Hash {
+		slot := new(big.Int).SetBytes(crypto.Keccak256([]byte(name)))
+		return common.BigToHash(slot.Sub(slot, big.NewInt(1)))
+	}
+	if got := eip1967Slot("eip1967.proxy.implementation"); got != ProxyImplementationSlot {
+		t.Errorf("got implementation slot %v, want %v", ProxyImplementationSlot, got)
+	}
+	if got := eip1967Slot("eip1967.proxy.beacon"); got != ProxyBeaconSlot {
+		t.Errorf("got beacon slot %v, want %v", ProxyBeaconSlot, got)
+	}
+	if got := eip1967Slot("eip1967.proxy.admin"); got != ProxyAdminSlot {
+		t.Errorf("got admin slot %v, want %v", ProxyAdminSlot, got)
+	}
+	if got := crypto.Keccak256Hash([]byte("PROXIABLE")); got != ProxiableSlot {
+		t.Errorf("got proxiable slot %v, want %v", ProxiableSlot, got)
+	}
+}
+
+func TestClassifyProxy(t *testing.T) {
+	implementation := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	beacon := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	admin := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	clone := common.FromHex("0x363d3d373d3d3d363d73" + "1111111111111111111111111111111111111111" + "5af43d82803e903d91602b57fd5bf3")
+	slot := func(address common.Address) common.Hash {
+		return common.BytesToHash(address.Bytes())
+	}
+
+	tests := []struct {
+		name           string
+		code           []byte
+		implementation common.Hash
+		beacon         common.Hash
+		admin          common.Hash
+		proxiable      common.Hash
+		want           *types.Eth1ProxyImplementation
+	}{
+		{"no proxy", []byte{0x60, 0x80}, common.Hash{}, common.Hash{}, common.Hash{}, common.Hash{}, nil},
+		{"minimal proxy", clone, common.Hash{}, common.Hash{}, common.Hash{}, common.Hash{}, &types.Eth1ProxyImplementation{Type: types.ProxyType_MINIMAL_PROXY, Implementation: implementation.Bytes()}},
+		{"minimal proxy with trailing code", append(clone, 0x00), common.Hash{}, common.Hash{}, common.Hash{}, common.Hash{}, nil},
+		{"eip-1967", nil, slot(implementation), common.Hash{}, common.Hash{}, common.Hash{}, &types.Eth1ProxyImplementation{Type: types.ProxyType_EIP1967_PROXY, Implementation: implementation.Bytes()}},
+		{"transparent", nil, slot(implementation), common.Hash{}, slot(admin), common.Hash{}, &types.Eth1ProxyImplementation{Type: types.ProxyType_TRANSPARENT_PROXY, Implementation: implementation.Bytes(), Admin: admin.Bytes()}},
+		{"beacon", nil, common.Hash{}, slot(beacon), common.Hash{}, common.Hash{}, &types.Eth1ProxyImplementation{Type: types.ProxyType_BEACON_PROXY, Beacon: beacon.Bytes()}},
+		{"eip-1822", nil, common.Hash{}, common.Hash{}, common.Hash{}, slot(implementation), &types.Eth1ProxyImplementation{Type: types.ProxyType_EIP1822_PROXY, Implementation: implementation.Bytes()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyProxy(tt.code, tt.implementation, tt.beacon, tt.admin, tt.proxiable)
+			if got == nil || tt.want == nil {
+				if got != tt.want {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+				return
+			}
+			if got.Type != tt.want.Type || !bytes.Equal(got.Implementation, tt.want.Implementation) || !bytes.Equal(got.Beacon, tt.want.Beacon) || !bytes.Equal(got.Admin, tt.want.Admin) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/config"
+)
+
+// RollupLabel is a rollup together with the addresses of its blob submitters and inboxes
+type RollupLabel struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+}
+
+var rollupLabels map[string]string
+var rollupLabelsOnce sync.Once
+
+// GetRollupName returns the name of the rollup the blob submitter or inbox address belongs to, an empty string is
+// returned if the address is not part of the rollup label list
+func GetRollupName(address []byte) string {
+	rollupLabelsOnce.Do(func() {
+		labels, err := readRollupLabels()
+		if err != nil {
+			LogError(err, "error reading rollup label list", 0)
+		}
+		rollupLabels = labels
+	})
+	return rollupLabels[fmt.Sprintf("%x", address)]
+}
+
+// readRollupLabels reads the rollup label list from the configured path, the embedded list only applies to mainnet
+func readRollupLabels() (map[string]string, error) {
+	var data []byte
+	if Config.Chain.RollupLabelsPath != "" {
+		var err error
+		data, err = os.ReadFile(Config.Chain.RollupLabelsPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading rollup label list %v: %w", Config.Chain.RollupLabelsPath, err)
+		}
+	} else if Config.Chain.ClConfig.DepositChainID == 1 {
+		data = []byte(config.RollupsJson)
+	} else {
+		return nil, nil
+	}
+
+	var rollups []*RollupLabel
+	err := json.Unmarshal(data, &rollups)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding rollup label list: %w", err)
+	}
+	labels := make(map[string]string)
+	for _, rollup := range rollups {
+		for _, address := range rollup.Addresses {
+			labels[strings.ToLower(strings.TrimPrefix(address, "0x"))] = rollup.Name
+		}
+	}
+	return labels, nil
+}
+
+// BlobFillEfficiency returns the share of non-zero bytes of the given number of blobs
+func BlobFillEfficiency(nonZeroBytes, blobCount uint64) float64 {
+	if blobCount == 0 {
+		return 0
+	}
+	return float64(nonZeroBytes) / float64(blobCount*BlobSize)
+}
+
+// BlobNonZeroBytes returns the number of non-zero bytes of a blob, rollups pad their data with zeros so it is used to
+// estimate how well a blob is filled
+func BlobNonZeroBytes(blob []byte) uint64 {
+	count := uint64(0)
+	for _, b := range blob {
+		if b != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// BlobSubmitterRollupName returns the name of the rollup of a blob transaction, the to-address (the inbox) takes
+// precedence over the sender, "Other" is returned for unknown submitters
+func BlobSubmitterRollupName(sender, to []byte) string {
+	if name := GetRollupName(to); name != "" {
+		return name
+	}
+	if name := GetRollupName(sender); name != "" {
+		return name
+	}
+	return "Other"
+}
@@ -145,6 +145,9 @@ func GetTemplateFuncs() template.FuncMap {
 		"formatAmount":                            FormatAmount,
 		"formatBytes":                             FormatBytes,
 		"formatBlobVersionedHash":                 FormatBlobVersionedHash,
+		"formatSetCodeAuthorizationValidity":      FormatSetCodeAuthorizationValidity,
+		"formatMempoolStatus":                     FormatMempoolStatus,
+		"formatMempoolDuration":                   FormatMempoolDuration,
 		"formatBigAmount":                         FormatBigAmount,
 		"formatBytesAmount":                       FormatBytesAmount,
 		"formatYesNo":                             FormatYesNo,
@@ -829,6 +832,17 @@ func ReadConfig(cfg *types.Config, path string) error {
 
 	cfg.Chain.Id = cfg.Chain.ClConfig.DepositChainID
 
+	if len(cfg.Chain.BlobSchedule) == 0 {
+		cfg.Chain.BlobSchedule, err = readBlobSchedule(cfg.Chain.ElConfigPath, cfg.Chain.Id)
+		if err != nil {
+			return err
+		}
+	}
+	err = validateBlobSchedule(cfg.Chain.BlobSchedule)
+	if err != nil {
+		return err
+	}
+
 	if cfg.RedisSessionStoreEndpoint == "" && cfg.RedisCacheEndpoint != "" {
 		logrus.Infof("using RedisCacheEndpoint %s as RedisSessionStoreEndpoint as no dedicated RedisSessionStoreEndpoint was provided", cfg.RedisCacheEndpoint)
 		cfg.RedisSessionStoreEndpoint = cfg.RedisCacheEndpoint